@@ -0,0 +1,278 @@
+// Runs the benchmarks of the data pipeline and compares the results against the stored baseline,
+// so the performance regressions are caught before release. Exits with 1 if any benchmark regresses.
+//
+// Usage (from the repository root):
+//
+//	go run ./cmd/benchgate                 # run and compare
+//	go run ./cmd/benchgate -update         # run and overwrite the baseline
+//	go run ./cmd/benchgate -input bench.txt # compare the saved output of `go test -bench`
+//
+// The baseline is only meaningful on the machine it's recorded on, e.g. the release CI runner.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+type Result struct {
+	NsPerOp     float64 `json:"nsPerOp"`
+	BytesPerOp  float64 `json:"bytesPerOp"`
+	AllocsPerOp float64 `json:"allocsPerOp"`
+}
+
+type Baseline struct {
+	Cpu        string             `json:"cpu,omitempty"`
+	Benchmarks map[string]*Result `json:"benchmarks"`
+}
+
+func main() {
+	baselinePath := flag.String("baseline", "config/benchmark_baseline.json", "the path of the baseline file")
+	bench := flag.String("bench", "Pipeline|Merge", "the regular expression of the benchmarks to run")
+	pkg := flag.String("pkg", "./pkg/channeld/", "the package of the benchmarks")
+	count := flag.Int("count", 5, "how many times to run each benchmark. The median is compared")
+	threshold := flag.Float64("threshold", 0.2, "the ratio of the slowdown or the allocation increase that fails the gate")
+	input := flag.String("input", "", "the file of the `go test -bench -benchmem` output to compare, instead of running the benchmarks")
+	update := flag.Bool("update", false, "overwrite the baseline with the results")
+	flag.Parse()
+
+	var output []byte
+	var err error
+	if *input != "" {
+		output, err = os.ReadFile(*input)
+	} else {
+		output, err = runBenchmarks(*pkg, *bench, *count)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to get the benchmark results: %v\n", err)
+		os.Exit(2)
+	}
+
+	current, err := parseBenchmarks(bytes.NewReader(output))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to parse the benchmark results: %v\n", err)
+		os.Exit(2)
+	}
+	if len(current.Benchmarks) == 0 {
+		fmt.Fprintln(os.Stderr, "no benchmark result found")
+		os.Exit(2)
+	}
+
+	if *update {
+		if err := writeBaseline(*baselinePath, current); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write the baseline: %v\n", err)
+			os.Exit(2)
+		}
+		fmt.Printf("updated %s with %d benchmarks\n", *baselinePath, len(current.Benchmarks))
+		return
+	}
+
+	baseline, err := readBaseline(*baselinePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read the baseline: %v\n", err)
+		os.Exit(2)
+	}
+	if baseline.Cpu != "" && current.Cpu != "" && baseline.Cpu != current.Cpu {
+		fmt.Printf("WARNING: the baseline is recorded on %q, but the benchmarks run on %q\n", baseline.Cpu, current.Cpu)
+	}
+
+	comparisons := compare(baseline, current, *threshold)
+	printComparisons(os.Stdout, comparisons)
+	for _, c := range comparisons {
+		if c.Regressed() {
+			fmt.Println("FAIL: performance regression detected")
+			os.Exit(1)
+		}
+	}
+	fmt.Println("PASS")
+}
+
+func runBenchmarks(pkg string, bench string, count int) ([]byte, error) {
+	cmd := exec.Command("go", "test", "-run", "^$", "-bench", bench, "-benchmem", "-count", strconv.Itoa(count), pkg)
+	var output bytes.Buffer
+	// Shows the progress, as the benchmarks take minutes
+	cmd.Stdout = io.MultiWriter(&output, os.Stderr)
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	return output.Bytes(), err
+}
+
+// e.g. "BenchmarkPipelineFanOut/subscribers=1000-8   613   2178063 ns/op   9868 B/op   154 allocs/op"
+var benchmarkLine = regexp.MustCompile(`^(Benchmark\S+?)(?:-\d+)?\s+\d+\s+(.*)$`)
+
+// Parses the output of `go test -bench -benchmem`. The median of each metric is taken if a benchmark runs multiple times.
+func parseBenchmarks(r io.Reader) (*Baseline, error) {
+	samples := make(map[string][]*Result)
+	result := &Baseline{Benchmarks: make(map[string]*Result)}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "cpu: ") {
+			result.Cpu = strings.TrimPrefix(line, "cpu: ")
+			continue
+		}
+		match := benchmarkLine.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		sample := &Result{}
+		fields := strings.Fields(match[2])
+		for i := 0; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid benchmark line %q: %w", line, err)
+			}
+			switch fields[i+1] {
+			case "ns/op":
+				sample.NsPerOp = value
+			case "B/op":
+				sample.BytesPerOp = value
+			case "allocs/op":
+				sample.AllocsPerOp = value
+			}
+		}
+		samples[match[1]] = append(samples[match[1]], sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for name, list := range samples {
+		result.Benchmarks[name] = &Result{
+			NsPerOp:     median(list, func(r *Result) float64 { return r.NsPerOp }),
+			BytesPerOp:  median(list, func(r *Result) float64 { return r.BytesPerOp }),
+			AllocsPerOp: median(list, func(r *Result) float64 { return r.AllocsPerOp }),
+		}
+	}
+	return result, nil
+}
+
+func median(list []*Result, metric func(r *Result) float64) float64 {
+	values := make([]float64, len(list))
+	for i, r := range list {
+		values[i] = metric(r)
+	}
+	sort.Float64s(values)
+	if len(values)%2 == 1 {
+		return values[len(values)/2]
+	}
+	return (values[len(values)/2-1] + values[len(values)/2]) / 2
+}
+
+type Comparison struct {
+	Name     string
+	Baseline *Result
+	Current  *Result
+	// The ratios of the regressed metrics that exceed the threshold, e.g. "ns/op"
+	Regressions []string
+}
+
+// Missing benchmarks count as the regressions, so the gate can't be passed by removing them.
+func (c *Comparison) Regressed() bool {
+	return len(c.Regressions) > 0
+}
+
+func compare(baseline *Baseline, current *Baseline, threshold float64) []*Comparison {
+	names := make(map[string]struct{})
+	for name := range baseline.Benchmarks {
+		names[name] = struct{}{}
+	}
+	for name := range current.Benchmarks {
+		names[name] = struct{}{}
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	comparisons := make([]*Comparison, 0, len(sorted))
+	for _, name := range sorted {
+		c := &Comparison{Name: name, Baseline: baseline.Benchmarks[name], Current: current.Benchmarks[name]}
+		if c.Baseline != nil && c.Current == nil {
+			c.Regressions = append(c.Regressions, "missing")
+		} else if c.Baseline != nil {
+			if exceeds(c.Baseline.NsPerOp, c.Current.NsPerOp, threshold) {
+				c.Regressions = append(c.Regressions, "ns/op")
+			}
+			if exceeds(c.Baseline.BytesPerOp, c.Current.BytesPerOp, threshold) {
+				c.Regressions = append(c.Regressions, "B/op")
+			}
+			// Tolerates one allocation, as it's amortized differently by b.N
+			if c.Current.AllocsPerOp-c.Baseline.AllocsPerOp > 1 && exceeds(c.Baseline.AllocsPerOp, c.Current.AllocsPerOp, threshold) {
+				c.Regressions = append(c.Regressions, "allocs/op")
+			}
+		}
+		comparisons = append(comparisons, c)
+	}
+	return comparisons
+}
+
+func exceeds(baseline float64, current float64, threshold float64) bool {
+	return current > baseline*(1+threshold)
+}
+
+func delta(baseline float64, current float64) string {
+	if baseline == 0 {
+		if current == 0 {
+			return "~"
+		}
+		return "+inf"
+	}
+	return fmt.Sprintf("%+.1f%%", (current-baseline)/baseline*100)
+}
+
+func printComparisons(w io.Writer, comparisons []*Comparison) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "benchmark\tns/op\tdelta\tB/op\tdelta\tallocs/op\tdelta\tstatus")
+	for _, c := range comparisons {
+		switch {
+		case c.Baseline == nil:
+			fmt.Fprintf(tw, "%s\t%.0f\t\t%.0f\t\t%.0f\t\tnew\n", c.Name, c.Current.NsPerOp, c.Current.BytesPerOp, c.Current.AllocsPerOp)
+		case c.Current == nil:
+			fmt.Fprintf(tw, "%s\t\t\t\t\t\t\tmissing\n", c.Name)
+		default:
+			status := "ok"
+			if c.Regressed() {
+				status = "REGRESSED (" + strings.Join(c.Regressions, ", ") + ")"
+			}
+			fmt.Fprintf(tw, "%s\t%.0f\t%s\t%.0f\t%s\t%.0f\t%s\t%s\n", c.Name,
+				c.Current.NsPerOp, delta(c.Baseline.NsPerOp, c.Current.NsPerOp),
+				c.Current.BytesPerOp, delta(c.Baseline.BytesPerOp, c.Current.BytesPerOp),
+				c.Current.AllocsPerOp, delta(c.Baseline.AllocsPerOp, c.Current.AllocsPerOp),
+				status)
+		}
+	}
+	tw.Flush()
+}
+
+func readBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	baseline := &Baseline{}
+	if err := json.Unmarshal(data, baseline); err != nil {
+		return nil, err
+	}
+	return baseline, nil
+}
+
+func writeBaseline(path string, baseline *Baseline) error {
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
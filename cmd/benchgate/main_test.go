@@ -0,0 +1,64 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testOutput = `goos: linux
+goarch: amd64
+pkg: github.com/metaworking/channeld/pkg/channeld
+cpu: Test CPU @ 3.00GHz
+BenchmarkPipelinePacketDecode-8   	  251554	      4000 ns/op	    4920 B/op	      48 allocs/op
+BenchmarkPipelinePacketDecode-8   	  251554	      4500 ns/op	    4920 B/op	      48 allocs/op
+BenchmarkPipelinePacketDecode-8   	  251554	      9000 ns/op	    4920 B/op	      48 allocs/op
+BenchmarkPipelineFanOut/subscribers=1000-8         	    8641	    150000 ns/op	     705 B/op	      11 allocs/op
+PASS
+ok  	github.com/metaworking/channeld/pkg/channeld	14.915s
+`
+
+func TestParseBenchmarks(t *testing.T) {
+	result, err := parseBenchmarks(strings.NewReader(testOutput))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "Test CPU @ 3.00GHz", result.Cpu)
+	assert.Len(t, result.Benchmarks, 2)
+	// The median of the runs, without the GOMAXPROCS suffix
+	decode := result.Benchmarks["BenchmarkPipelinePacketDecode"]
+	if assert.NotNil(t, decode) {
+		assert.EqualValues(t, 4500, decode.NsPerOp)
+		assert.EqualValues(t, 4920, decode.BytesPerOp)
+		assert.EqualValues(t, 48, decode.AllocsPerOp)
+	}
+	assert.Contains(t, result.Benchmarks, "BenchmarkPipelineFanOut/subscribers=1000")
+}
+
+func TestCompare(t *testing.T) {
+	baseline := &Baseline{Benchmarks: map[string]*Result{
+		"BenchmarkA": {NsPerOp: 1000, BytesPerOp: 100, AllocsPerOp: 2},
+		"BenchmarkB": {NsPerOp: 1000, BytesPerOp: 100, AllocsPerOp: 2},
+		"BenchmarkC": {NsPerOp: 1000, BytesPerOp: 100, AllocsPerOp: 10},
+		"BenchmarkD": {NsPerOp: 1000},
+	}}
+	current := &Baseline{Benchmarks: map[string]*Result{
+		// Within the threshold
+		"BenchmarkA": {NsPerOp: 1100, BytesPerOp: 100, AllocsPerOp: 3},
+		"BenchmarkB": {NsPerOp: 1300, BytesPerOp: 100, AllocsPerOp: 2},
+		"BenchmarkC": {NsPerOp: 500, BytesPerOp: 100, AllocsPerOp: 13},
+		"BenchmarkE": {NsPerOp: 1000},
+	}}
+
+	comparisons := compare(baseline, current, 0.2)
+	if !assert.Len(t, comparisons, 5) {
+		return
+	}
+	assert.False(t, comparisons[0].Regressed())
+	assert.Equal(t, []string{"ns/op"}, comparisons[1].Regressions)
+	assert.Equal(t, []string{"allocs/op"}, comparisons[2].Regressions)
+	assert.Equal(t, []string{"missing"}, comparisons[3].Regressions)
+	assert.Nil(t, comparisons[4].Baseline)
+	assert.False(t, comparisons[4].Regressed())
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/metaworking/channeld/pkg/channeld"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/replaypb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// A field whose value is changed by an update. Old is empty if the field is added, and New is empty if it's removed.
+type fieldChange struct {
+	Path    string
+	Old     string
+	New     string
+	Added   bool
+	Removed bool
+}
+
+func (c fieldChange) String() string {
+	switch {
+	case c.Added:
+		return fmt.Sprintf("+ %s = %s", c.Path, c.New)
+	case c.Removed:
+		return fmt.Sprintf("- %s (was %s)", c.Path, c.Old)
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", c.Path, c.Old, c.New)
+	}
+}
+
+// A CHANNEL_DATA_UPDATE sent to the channel in the recording.
+type recordedUpdate struct {
+	// The index in the channel's updates, starting from 1
+	Index int
+	// Since the start of the recording
+	TimeMs      int64
+	PacketIndex int
+	StubId      uint32
+	// The channel data in the update. Nil if it can't be unmarshalled, e.g. the type is not linked.
+	Data    proto.Message
+	Err     error
+	Changes []fieldChange
+}
+
+// The updates merged in a tick, and the channel data after them.
+type step struct {
+	Index   int
+	TickMs  int64
+	Updates []*recordedUpdate
+	// The flattened channel data after the step. See flattenFields().
+	Fields map[string]string
+	// The update that changed each field in the step, by the path.
+	ChangedBy map[string]int
+}
+
+// Merges the CHANNEL_DATA_UPDATEs of the channel in the recording one by one, and groups them by the tick. Each update in
+// its own step if tickMs is 0. The first update initializes the channel data, as the channel does.
+func buildSteps(session *replaypb.ReplaySession, channelId uint32, tickMs int64, options *channeldpb.ChannelDataMergeOptions) []*step {
+	var steps []*step
+	var data proto.Message
+	fields := make(map[string]string)
+	var timeNs int64
+	count := 0
+	for i, packet := range session.Packets {
+		timeNs += packet.OffsetTime
+		for _, mp := range packet.Packet.GetMessages() {
+			if mp.ChannelId != channelId || mp.MsgType != uint32(channeldpb.MessageType_CHANNEL_DATA_UPDATE) {
+				continue
+			}
+			count++
+			update := &recordedUpdate{Index: count, TimeMs: timeNs / 1e6, PacketIndex: i, StubId: mp.StubId}
+			update.Data, update.Err = unmarshalUpdate(mp.MsgBody)
+
+			tick := update.TimeMs
+			if tickMs > 0 {
+				tick = tick / tickMs * tickMs
+			}
+			if len(steps) == 0 || tickMs == 0 || steps[len(steps)-1].TickMs != tick {
+				steps = append(steps, &step{Index: len(steps) + 1, TickMs: tick, ChangedBy: make(map[string]int)})
+			}
+			s := steps[len(steps)-1]
+			s.Updates = append(s.Updates, update)
+
+			if update.Data != nil {
+				if data == nil {
+					data = proto.Clone(update.Data)
+				} else if data.ProtoReflect().Descriptor() != update.Data.ProtoReflect().Descriptor() {
+					update.Err = fmt.Errorf("the update type %s doesn't match the channel data type %s",
+						update.Data.ProtoReflect().Descriptor().FullName(), data.ProtoReflect().Descriptor().FullName())
+				} else {
+					channeld.MergeChannelData(data, proto.Clone(update.Data), options)
+				}
+				newFields := make(map[string]string)
+				flattenFields(data.ProtoReflect(), "", newFields)
+				update.Changes = diffFields(fields, newFields)
+				for _, change := range update.Changes {
+					s.ChangedBy[change.Path] = update.Index
+				}
+				fields = newFields
+			}
+			s.Fields = fields
+		}
+	}
+	return steps
+}
+
+func unmarshalUpdate(body []byte) (proto.Message, error) {
+	updateMsg := &channeldpb.ChannelDataUpdateMessage{}
+	if err := proto.Unmarshal(body, updateMsg); err != nil {
+		return nil, err
+	}
+	if updateMsg.Data == nil {
+		return nil, fmt.Errorf("the update has no data")
+	}
+	return updateMsg.Data.UnmarshalNew()
+}
+
+// Flattens the message into the values of the leaf fields by the paths, e.g. "entities.42.hp". The map entries are addressed
+// by the key, and the list elements by the index. The empty message is a leaf, e.g. the entity without any field set.
+func flattenFields(msg protoreflect.Message, prefix string, fields map[string]string) {
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		path := prefix + string(fd.Name())
+		switch {
+		case fd.IsMap():
+			v.Map().Range(func(mk protoreflect.MapKey, mv protoreflect.Value) bool {
+				flattenValue(fd.MapValue(), mv, path+"."+mk.String(), fields)
+				return true
+			})
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				flattenValue(fd, list.Get(i), path+"."+strconv.Itoa(i), fields)
+			}
+		default:
+			flattenValue(fd, v, path, fields)
+		}
+		return true
+	})
+}
+
+func flattenValue(fd protoreflect.FieldDescriptor, v protoreflect.Value, path string, fields map[string]string) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		n := len(fields)
+		flattenFields(v.Message(), path+".", fields)
+		if len(fields) == n {
+			fields[path] = "{}"
+		}
+	case protoreflect.StringKind:
+		fields[path] = strconv.Quote(v.String())
+	case protoreflect.BytesKind:
+		fields[path] = fmt.Sprintf("%x", v.Bytes())
+	case protoreflect.EnumKind:
+		if value := fd.Enum().Values().ByNumber(v.Enum()); value != nil {
+			fields[path] = string(value.Name())
+		} else {
+			fields[path] = strconv.Itoa(int(v.Enum()))
+		}
+	default:
+		fields[path] = v.String()
+	}
+}
+
+// Returns the changes from the old fields to the new fields, sorted by the path.
+func diffFields(oldFields map[string]string, newFields map[string]string) []fieldChange {
+	var changes []fieldChange
+	for path, newValue := range newFields {
+		oldValue, exists := oldFields[path]
+		if !exists {
+			changes = append(changes, fieldChange{Path: path, New: newValue, Added: true})
+		} else if oldValue != newValue {
+			changes = append(changes, fieldChange{Path: path, Old: oldValue, New: newValue})
+		}
+	}
+	for path, oldValue := range oldFields {
+		if _, exists := newFields[path]; !exists {
+			changes = append(changes, fieldChange{Path: path, Old: oldValue, Removed: true})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// Prints the updates of the step with their changes, then the merged channel data with the changed fields highlighted.
+func printStep(w io.Writer, s *step, total int, color bool) {
+	fmt.Fprintf(w, "=== step %d/%d, tick %dms, %d update(s)\n", s.Index, total, s.TickMs, len(s.Updates))
+	for _, update := range s.Updates {
+		fmt.Fprintf(w, "update #%d at %dms (packet %d, stubId %d)", update.Index, update.TimeMs, update.PacketIndex, update.StubId)
+		if update.Err != nil {
+			fmt.Fprintf(w, ": %v\n", update.Err)
+			continue
+		}
+		if len(update.Changes) == 0 {
+			fmt.Fprintln(w, ": no change")
+			continue
+		}
+		fmt.Fprintln(w)
+		for _, change := range update.Changes {
+			fmt.Fprintf(w, "    %s\n", change)
+		}
+	}
+
+	fmt.Fprintln(w, "--- channel data")
+	paths := make([]string, 0, len(s.Fields))
+	for path := range s.Fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		line := fmt.Sprintf("%s = %s", path, s.Fields[path])
+		if index, changed := s.ChangedBy[path]; changed {
+			line = fmt.Sprintf("* %s    [#%d]", line, index)
+			if color {
+				line = "\x1b[1;33m" + line + "\x1b[0m"
+			}
+		} else {
+			line = "  " + line
+		}
+		fmt.Fprintln(w, line)
+	}
+	// The removed fields are not in the data any more
+	var removed []string
+	for _, update := range s.Updates {
+		for _, change := range update.Changes {
+			if change.Removed {
+				removed = append(removed, fmt.Sprintf("%s    [#%d]", change.Path, update.Index))
+			}
+		}
+	}
+	if len(removed) > 0 {
+		fmt.Fprintf(w, "removed: %s\n", strings.Join(removed, ", "))
+	}
+}
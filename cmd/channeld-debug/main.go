@@ -0,0 +1,120 @@
+// Steps through the channel data updates of a channel in a packet recording (the .cpr file written with the -erp flag)
+// tick by tick, and shows the merged channel data at each step with the fields changed by each update highlighted,
+// for diagnosing the merge bugs offline.
+//
+// Usage (from the repository root):
+//
+//	go run ./cmd/channeld-debug -ch 65536 replays/session_1.cpr          # interactive
+//	go run ./cmd/channeld-debug -ch 65536 -tick 100 -all replays/session_1.cpr
+//	go run ./cmd/channeld-debug -ch 65536 -mo '{"shouldReplaceList":true}' replays/session_1.cpr
+//
+// In the interactive mode, the commands are: n (or Enter) for the next step, p for the previous, g <step> to go to a step,
+// f for the first, l for the last, and q to quit.
+//
+// The updates are merged in the same way as channeld does, so the channel data types and their merge functions should be
+// linked, e.g. by importing the proto package of the game in a copy of this command. The unmarshalling errors of the
+// unlinked types are shown in the steps.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/replay"
+	_ "github.com/metaworking/channeld/pkg/unrealpb"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func main() {
+	channelId := flag.Uint("ch", 0, "the id of the channel to debug")
+	tickMs := flag.Int64("tick", 50, "the tick interval of the channel in milliseconds, which the updates are grouped by. 0 means one step per update")
+	mergeOptions := flag.String("mo", "", "the ChannelDataMergeOptions of the channel in JSON. Empty means the default merge")
+	all := flag.Bool("all", false, "print all the steps and exit, instead of the interactive mode")
+	color := flag.Bool("color", true, "highlight the changed fields with the ANSI colors")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: channeld-debug -ch <channelId> [options] <recording.cpr>")
+		os.Exit(2)
+	}
+
+	var options *channeldpb.ChannelDataMergeOptions
+	if *mergeOptions != "" {
+		options = &channeldpb.ChannelDataMergeOptions{}
+		if err := protojson.Unmarshal([]byte(*mergeOptions), options); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid merge options: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	session, err := replay.ReadReplaySessionFile(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read the recording: %v\n", err)
+		os.Exit(1)
+	}
+	steps := buildSteps(session, uint32(*channelId), *tickMs, options)
+	if len(steps) == 0 {
+		fmt.Fprintf(os.Stderr, "no channel data update of channel %d in the recording\n", *channelId)
+		os.Exit(1)
+	}
+
+	if *all {
+		for _, s := range steps {
+			printStep(os.Stdout, s, len(steps), *color)
+		}
+		return
+	}
+
+	current := 0
+	printStep(os.Stdout, steps[current], len(steps), *color)
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print("(n/p/g <step>/f/l/q) > ")
+		if !scanner.Scan() {
+			return
+		}
+		fields := strings.Fields(scanner.Text())
+		command := "n"
+		if len(fields) > 0 {
+			command = fields[0]
+		}
+		next := current
+		switch command {
+		case "n":
+			next = current + 1
+		case "p":
+			next = current - 1
+		case "f":
+			next = 0
+		case "l":
+			next = len(steps) - 1
+		case "g":
+			if len(fields) < 2 {
+				fmt.Println("usage: g <step>")
+				continue
+			}
+			index, err := strconv.Atoi(fields[1])
+			if err != nil {
+				fmt.Println("invalid step")
+				continue
+			}
+			next = index - 1
+		case "q":
+			return
+		default:
+			fmt.Println("unknown command")
+			continue
+		}
+		if next < 0 || next >= len(steps) {
+			fmt.Printf("no step %d\n", next+1)
+			continue
+		}
+		current = next
+		printStep(os.Stdout, steps[current], len(steps), *color)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/replaypb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func updatePacket(offset time.Duration, channelId uint32, data proto.Message) *replaypb.ReplayPacket {
+	anyData, _ := anypb.New(data)
+	body, _ := proto.Marshal(&channeldpb.ChannelDataUpdateMessage{Data: anyData})
+	return &replaypb.ReplayPacket{
+		OffsetTime: int64(offset),
+		Packet: &channeldpb.Packet{Messages: []*channeldpb.MessagePack{
+			{ChannelId: channelId, MsgType: uint32(channeldpb.MessageType_CHANNEL_DATA_UPDATE), MsgBody: body},
+		}},
+	}
+}
+
+func TestBuildSteps(t *testing.T) {
+	session := &replaypb.ReplaySession{Packets: []*replaypb.ReplayPacket{
+		updatePacket(0, 1, &testpb.TestMergeMessage{
+			List: []string{"a"},
+			Kv:   map[int64]*testpb.TestMergeMessage_StringWrapper{1: {Content: "x"}, 2: {Content: "y"}},
+		}),
+		// Another channel
+		updatePacket(10*time.Millisecond, 2, &testpb.TestMergeMessage{List: []string{"z"}}),
+		// The same tick as the first update
+		updatePacket(10*time.Millisecond, 1, &testpb.TestMergeMessage{
+			Kv: map[int64]*testpb.TestMergeMessage_StringWrapper{1: {Content: "x2"}},
+		}),
+		updatePacket(100*time.Millisecond, 1, &testpb.TestMergeMessage{
+			List: []string{"b"},
+			Kv:   map[int64]*testpb.TestMergeMessage_StringWrapper{2: {Removed: true}},
+		}),
+		// No change
+		updatePacket(100*time.Millisecond, 1, &testpb.TestMergeMessage{List: []string{"b"}}),
+	}}
+
+	options := &channeldpb.ChannelDataMergeOptions{ShouldReplaceList: true, ShouldCheckRemovableMapField: true}
+	steps := buildSteps(session, 1, 50, options)
+	if !assert.Len(t, steps, 3) {
+		return
+	}
+
+	// The first update initializes the data, and the second changes it in the same tick
+	assert.EqualValues(t, 0, steps[0].TickMs)
+	assert.Len(t, steps[0].Updates, 2)
+	assert.Len(t, steps[0].Updates[0].Changes, 3)
+	// ShouldReplaceList replaces the list with the empty one of the update
+	assert.Equal(t, []fieldChange{
+		{Path: "kv.1.content", Old: `"x"`, New: `"x2"`},
+		{Path: "list.0", Old: `"a"`, Removed: true},
+	}, steps[0].Updates[1].Changes)
+	assert.Equal(t, `"x2"`, steps[0].Fields["kv.1.content"])
+	assert.Equal(t, 2, steps[0].ChangedBy["kv.1.content"])
+	assert.Equal(t, 1, steps[0].ChangedBy["kv.2.content"])
+
+	// The list is replaced and the removable map entry is removed by the merge options
+	assert.EqualValues(t, 100, steps[1].TickMs)
+	assert.Equal(t, []fieldChange{
+		{Path: "kv.2.content", Old: `"y"`, Removed: true},
+		{Path: "list.0", New: `"b"`, Added: true},
+	}, steps[1].Updates[0].Changes)
+	assert.NotContains(t, steps[1].Fields, "kv.2.content")
+	assert.Empty(t, steps[2].Updates[0].Changes)
+	assert.Empty(t, steps[2].ChangedBy)
+
+	// One step per update
+	assert.Len(t, buildSteps(session, 1, 0, options), 4)
+
+	var buf bytes.Buffer
+	printStep(&buf, steps[1], len(steps), false)
+	assert.Contains(t, buf.String(), "- kv.2.content (was \"y\")")
+	assert.Contains(t, buf.String(), "* list.0 = \"b\"    [#3]")
+	assert.Contains(t, buf.String(), "  kv.1.content = \"x2\"")
+}
+
+func TestBuildStepsUnresolvedType(t *testing.T) {
+	body, _ := proto.Marshal(&channeldpb.ChannelDataUpdateMessage{Data: &anypb.Any{TypeUrl: "type.googleapis.com/game.Unknown"}})
+	session := &replaypb.ReplaySession{Packets: []*replaypb.ReplayPacket{
+		{Packet: &channeldpb.Packet{Messages: []*channeldpb.MessagePack{
+			{ChannelId: 1, MsgType: uint32(channeldpb.MessageType_CHANNEL_DATA_UPDATE), MsgBody: body},
+		}}},
+		updatePacket(0, 1, &testpb.TestChannelDataMessage{Num: 1}),
+		// Mismatched type
+		updatePacket(0, 1, &testpb.TestMergeMessage{List: []string{"a"}}),
+	}}
+	steps := buildSteps(session, 1, 0, nil)
+	if assert.Len(t, steps, 3) {
+		assert.Error(t, steps[0].Updates[0].Err)
+		assert.Empty(t, steps[0].Fields)
+		assert.NoError(t, steps[1].Updates[0].Err)
+		assert.Equal(t, "1", steps[1].Fields["num"])
+		assert.Error(t, steps[2].Updates[0].Err)
+		assert.Equal(t, "1", steps[2].Fields["num"])
+	}
+}
@@ -0,0 +1,221 @@
+// libchanneld exports the Go client (pkg/client) as a C shared library, so the native engines without a Go runtime
+// can integrate channeld with a thin wrapper. The messages are passed in the wire format (the Protobuf-encoded MessagePack.msgBody),
+// and the wrapper is responsible for encoding and decoding them.
+//
+// Build:
+//
+//	go build -buildmode=c-shared -o libchanneld.so ./cmd/libchanneld
+//
+// The command also generates libchanneld.h. A typical game loop calls ChanneldTick() every frame, which invokes the message callback
+// on the calling thread for each received message, then sends the queued messages.
+package main
+
+/*
+#include <stdint.h>
+
+// The msgBody is only valid during the callback.
+typedef void (*channeld_message_callback)(int32_t handle, uint32_t channelId, uint32_t msgType, uint32_t stubId, const void* msgBody, int32_t msgBodyLen, void* userData);
+
+static inline void channeld_call_message_callback(channeld_message_callback cb, int32_t handle, uint32_t channelId, uint32_t msgType, uint32_t stubId, const void* msgBody, int32_t msgBodyLen, void* userData) {
+	cb(handle, channelId, msgType, stubId, msgBody, msgBodyLen, userData);
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/client"
+)
+
+type libClient struct {
+	handle          C.int32_t
+	client          *client.ChanneldClient
+	messageCallback C.channeld_message_callback
+	userData        unsafe.Pointer
+	receiveErr      atomic.Value
+}
+
+var clients = map[C.int32_t]*libClient{}
+var clientsMutex sync.RWMutex
+var nextHandle C.int32_t = 0
+
+var lastError string
+var lastErrorMutex sync.Mutex
+
+func setLastError(err error) {
+	lastErrorMutex.Lock()
+	defer lastErrorMutex.Unlock()
+	lastError = err.Error()
+}
+
+func getClient(handle C.int32_t) *libClient {
+	clientsMutex.RLock()
+	defer clientsMutex.RUnlock()
+	c, exists := clients[handle]
+	if !exists {
+		setLastError(fmt.Errorf("invalid handle: %d", handle))
+		return nil
+	}
+	return c
+}
+
+// Copies the message of the last error to the buffer as a null-terminated string. Returns the length of the message.
+//
+//export ChanneldGetLastError
+func ChanneldGetLastError(buf *C.char, bufLen C.int32_t) C.int32_t {
+	lastErrorMutex.Lock()
+	defer lastErrorMutex.Unlock()
+	if buf != nil && bufLen > 0 {
+		dst := unsafe.Slice((*byte)(unsafe.Pointer(buf)), int(bufLen))
+		n := copy(dst[:len(dst)-1], lastError)
+		dst[n] = 0
+	}
+	return C.int32_t(len(lastError))
+}
+
+// Connects to channeld. The address starting with "ws" uses WebSocket, otherwise TCP.
+// Returns the handle of the client, or -1 if failed.
+//
+//export ChanneldConnect
+func ChanneldConnect(addr *C.char) C.int32_t {
+	c, err := client.NewClient(C.GoString(addr))
+	if err != nil {
+		setLastError(err)
+		return -1
+	}
+
+	clientsMutex.Lock()
+	nextHandle++
+	lc := &libClient{handle: nextHandle, client: c}
+	clients[lc.handle] = lc
+	clientsMutex.Unlock()
+
+	// Called in ChanneldTick()
+	c.SetRawMessageHandler(func(_ *client.ChanneldClient, mp *channeldpb.MessagePack) {
+		if lc.messageCallback == nil {
+			return
+		}
+		var msgBody unsafe.Pointer
+		if len(mp.MsgBody) > 0 {
+			msgBody = unsafe.Pointer(&mp.MsgBody[0])
+		}
+		C.channeld_call_message_callback(lc.messageCallback, lc.handle, C.uint32_t(mp.ChannelId), C.uint32_t(mp.MsgType), C.uint32_t(mp.StubId),
+			msgBody, C.int32_t(len(mp.MsgBody)), lc.userData)
+	})
+
+	go func() {
+		for {
+			if err := c.Receive(); err != nil {
+				lc.receiveErr.Store(err)
+				return
+			}
+		}
+	}()
+
+	return lc.handle
+}
+
+// Closes the connection and releases the handle.
+//
+//export ChanneldDisconnect
+func ChanneldDisconnect(handle C.int32_t) {
+	lc := getClient(handle)
+	if lc == nil {
+		return
+	}
+	clientsMutex.Lock()
+	delete(clients, handle)
+	clientsMutex.Unlock()
+	lc.client.Disconnect()
+}
+
+// Sets the callback that receives all the messages. The userData is passed to the callback as it is.
+//
+//export ChanneldSetMessageCallback
+func ChanneldSetMessageCallback(handle C.int32_t, callback C.channeld_message_callback, userData unsafe.Pointer) C.int32_t {
+	lc := getClient(handle)
+	if lc == nil {
+		return -1
+	}
+	lc.messageCallback = callback
+	lc.userData = userData
+	return 0
+}
+
+// Sends the AuthMessage. The AuthResultMessage is received in the message callback.
+//
+//export ChanneldAuth
+func ChanneldAuth(handle C.int32_t, loginToken *C.char, playerIdentifierToken *C.char) C.int32_t {
+	lc := getClient(handle)
+	if lc == nil {
+		return -1
+	}
+	lc.client.Auth(C.GoString(loginToken), C.GoString(playerIdentifierToken))
+	return 0
+}
+
+// Returns the connection id assigned by channeld after the authentication, or 0 if not authenticated yet.
+//
+//export ChanneldGetConnId
+func ChanneldGetConnId(handle C.int32_t) C.uint32_t {
+	lc := getClient(handle)
+	if lc == nil {
+		return 0
+	}
+	return C.uint32_t(lc.client.Id)
+}
+
+// Queues the message to send in the next ChanneldTick(). The msgBody is the Protobuf-encoded message, and is copied before returning.
+// If stubId is not 0, the response of the message will have the same stubId.
+//
+//export ChanneldSend
+func ChanneldSend(handle C.int32_t, channelId C.uint32_t, broadcast C.uint32_t, msgType C.uint32_t, stubId C.uint32_t, msgBody unsafe.Pointer, msgBodyLen C.int32_t) C.int32_t {
+	lc := getClient(handle)
+	if lc == nil {
+		return -1
+	}
+	if msgBodyLen < 0 || (msgBody == nil && msgBodyLen > 0) {
+		setLastError(errors.New("invalid msgBody"))
+		return -1
+	}
+	err := lc.client.SendPack(&channeldpb.MessagePack{
+		ChannelId: uint32(channelId),
+		Broadcast: uint32(broadcast),
+		StubId:    uint32(stubId),
+		MsgType:   uint32(msgType),
+		MsgBody:   C.GoBytes(msgBody, msgBodyLen),
+	})
+	if err != nil {
+		setLastError(err)
+		return -1
+	}
+	return 0
+}
+
+// Calls the message callback for each received message, then sends the queued messages.
+// Returns -1 if the connection is closed.
+//
+//export ChanneldTick
+func ChanneldTick(handle C.int32_t) C.int32_t {
+	lc := getClient(handle)
+	if lc == nil {
+		return -1
+	}
+	if err, ok := lc.receiveErr.Load().(error); ok {
+		setLastError(err)
+		return -1
+	}
+	if err := lc.client.Tick(); err != nil {
+		setLastError(err)
+		return -1
+	}
+	return 0
+}
+
+func main() {}
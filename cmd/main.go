@@ -2,11 +2,13 @@ package main
 
 import (
 	"fmt"
-	"net/http"
 
+	"github.com/metaworking/channeld/pkg/archive"
 	"github.com/metaworking/channeld/pkg/channeld"
 	"github.com/metaworking/channeld/pkg/channeldpb"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/metaworking/channeld/pkg/plugin"
+	"github.com/metaworking/channeld/pkg/relay"
+	"go.uber.org/zap"
 )
 
 func main() {
@@ -41,16 +43,104 @@ func main() {
 	}
 	channeld.StartProfiling()
 	channeld.InitLogs()
+
+	if channeld.GlobalSettings.RelayCentralAddress != "" {
+		runEdge()
+		return
+	}
+
 	channeld.InitMetrics()
+	if err := channeld.StartMetricsExporter(); err != nil {
+		channeld.RootLogger().Panic("failed to start the metrics exporter", zap.Error(err))
+	}
 	channeld.InitConnections(channeld.GlobalSettings.ServerFSM, channeld.GlobalSettings.ClientFSM)
+	channeld.InitOperatorConnections(channeld.GlobalSettings.OperatorFSM)
 	channeld.InitChannels()
 
-	// Setup Prometheus
-	http.Handle("/metrics", promhttp.Handler())
-	go http.ListenAndServe(":8080", nil)
+	if channeld.GlobalSettings.MessageArchivePath != "" {
+		config := archive.DefaultSQLiteConfig
+		config.Path = channeld.GlobalSettings.MessageArchivePath
+		messageArchive, err := archive.OpenSQLite(config)
+		if err != nil {
+			channeld.RootLogger().Panic("failed to open the message archive", zap.Error(err))
+		}
+		defer messageArchive.Close()
+		channeld.SetMessageArchive(messageArchive)
+	}
+
+	if channeld.GlobalSettings.BanStorePath != "" {
+		banStore, err := archive.OpenSQLiteBanStore(channeld.GlobalSettings.BanStorePath)
+		if err != nil {
+			channeld.RootLogger().Panic("failed to open the ban store", zap.Error(err))
+		}
+		defer banStore.Close()
+		if err := channeld.SetBanStore(banStore); err != nil {
+			channeld.RootLogger().Panic("failed to load the ban list", zap.Error(err))
+		}
+	}
+
+	if err := channeld.InitScripts(); err != nil {
+		channeld.RootLogger().Panic("failed to load the scripts", zap.Error(err))
+	}
+
+	if err := channeld.InitAnalytics(); err != nil {
+		channeld.RootLogger().Panic("failed to open the analytics sink", zap.Error(err))
+	}
+
+	if channeld.GlobalSettings.ContentFilterWordList != "" {
+		mode := channeld.ContentFilterMode_Mask
+		if channeld.GlobalSettings.ContentFilterReject {
+			mode = channeld.ContentFilterMode_Reject
+		}
+		contentFilter, err := channeld.LoadWordListContentFilter(channeld.GlobalSettings.ContentFilterWordList, mode)
+		if err != nil {
+			channeld.RootLogger().Panic("failed to load the word list of the content filter", zap.Error(err))
+		}
+		channeld.SetContentFilter(contentFilter)
+	}
+
+	// The extensions of the plugins take precedence over the built-in ones above
+	plugins, err := plugin.InitPlugins()
+	if err != nil {
+		channeld.RootLogger().Panic("failed to load the plugins", zap.Error(err))
+	}
+	for _, p := range plugins {
+		defer p.Close()
+	}
+
+	// The client connections are held until the persisted channels are restored
+	go func() {
+		if err := channeld.RestoreChannels(); err != nil {
+			channeld.RootLogger().Panic("failed to restore the channels", zap.Error(err))
+		}
+	}()
+
+	// Setup Prometheus and the debug endpoints
+	go channeld.StartAdminServer()
 
+	if len(channeld.GlobalSettings.Listeners) > 0 {
+		if err := channeld.StartListeners(channeld.GlobalSettings.Listeners); err != nil {
+			channeld.RootLogger().Panic("failed to start the listeners", zap.Error(err))
+		}
+		select {}
+	}
+
+	if channeld.GlobalSettings.OperatorAddress != "" {
+		go channeld.StartListening(channeldpb.ConnectionType_OPERATOR, channeld.GlobalSettings.OperatorNetwork, channeld.GlobalSettings.OperatorAddress)
+	}
 	go channeld.StartListening(channeldpb.ConnectionType_SERVER, channeld.GlobalSettings.ServerNetwork, channeld.GlobalSettings.ServerAddress)
 	// FIXME: After all the server connections are established, the client connection should be listened.*/
 	channeld.StartListening(channeldpb.ConnectionType_CLIENT, channeld.GlobalSettings.ClientNetwork, channeld.GlobalSettings.ClientAddress)
 
 }
+
+// Runs as an edge relay, which only listens for the client connections and forwards them to the central channeld.
+func runEdge() {
+	config := relay.DefaultEdgeConfig
+	config.CentralAddr = channeld.GlobalSettings.RelayCentralAddress
+	config.Network = channeld.GlobalSettings.ClientNetwork
+	config.ListenAddr = channeld.GlobalSettings.ClientAddress
+	if err := relay.NewEdge(config).Run(); err != nil {
+		channeld.RootLogger().Error("edge relay stopped", zap.Error(err))
+	}
+}
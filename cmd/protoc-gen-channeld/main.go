@@ -0,0 +1,393 @@
+// protoc-gen-channeld generates the type-specific Merge and Diff functions for the channel data messages.
+// The generated Merge implements channeld.MergeableChannelData, which is much faster than the reflection-based merge
+// (see BenchmarkCustomMergeMap), so the hand-written merge code is no longer needed in most cases.
+//
+// Add the annotation to the leading comment of the message to generate the functions for it:
+//
+//	// @channeld:merge
+//	message MyChannelData {
+//	    ...
+//	}
+//
+// Usage:
+//
+//	go install github.com/metaworking/channeld/cmd/protoc-gen-channeld
+//	protoc --go_out=. --go_opt=paths=source_relative --channeld_out=. --channeld_opt=paths=source_relative -I . *.proto
+//
+// The generated Merge follows the same rules as the reflection-based merge (channeld.ReflectMerge), with a few differences:
+//   - The merge options are applied to the nested messages that are also annotated, not only the top-level fields.
+//   - The elements of the lists, the values of the maps and the oneof fields are taken from the src as a whole, without being copied.
+//     Merge never modifies them, but the caller should not modify the src after the merge either.
+//   - The spatial info is not notified. Write the Merge function manually if the message contains the spatial info.
+//
+// The generated Diff returns the update message that changes the old message into the new one when merged.
+// If a list is modified other than appended, the update contains the whole lists and should be merged with ShouldReplaceList.
+// The nested messages should also be annotated, otherwise they are compared and merged as a whole by proto.Equal and proto.Merge.
+// As the merge ignores the zero values of the proto3 scalar fields, the fields that are changed to the zero values are not in the diff;
+// and the removed map entries are only in the diff if the map value has the "removed" field (see channeld.RemovableMapField).
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const mergeAnnotation = "@channeld:merge"
+
+const (
+	errorsPackage     = protogen.GoImportPath("errors")
+	bytesPackage      = protogen.GoImportPath("bytes")
+	protoPackage      = protogen.GoImportPath("google.golang.org/protobuf/proto")
+	channeldpbPackage = protogen.GoImportPath("github.com/metaworking/channeld/pkg/channeldpb")
+	commonPackage     = protogen.GoImportPath("github.com/metaworking/channeld/pkg/common")
+)
+
+func main() {
+	protogen.Options{}.Run(func(gen *protogen.Plugin) error {
+		for _, f := range gen.Files {
+			if !f.Generate {
+				continue
+			}
+			if err := generateFile(gen, f); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func isAnnotated(msg *protogen.Message) bool {
+	for _, line := range strings.Split(string(msg.Comments.Leading), "\n") {
+		if strings.TrimSpace(line) == mergeAnnotation {
+			return true
+		}
+	}
+	return false
+}
+
+func annotatedMessages(msgs []*protogen.Message) []*protogen.Message {
+	result := make([]*protogen.Message, 0)
+	for _, msg := range msgs {
+		if msg.Desc.IsMapEntry() {
+			continue
+		}
+		if isAnnotated(msg) {
+			result = append(result, msg)
+		}
+		result = append(result, annotatedMessages(msg.Messages)...)
+	}
+	return result
+}
+
+func generateFile(gen *protogen.Plugin, f *protogen.File) error {
+	msgs := annotatedMessages(f.Messages)
+	if len(msgs) == 0 {
+		return nil
+	}
+	if f.Desc.Syntax() != protoreflect.Proto3 {
+		return fmt.Errorf("%s: %s is only supported in proto3", f.Desc.Path(), mergeAnnotation)
+	}
+
+	g := gen.NewGeneratedFile(f.GeneratedFilenamePrefix+"_merge.pb.go", f.GoImportPath)
+	g.P("// Code generated by protoc-gen-channeld. DO NOT EDIT.")
+	g.P("// source: ", f.Desc.Path())
+	g.P()
+	g.P("package ", f.GoPackageName)
+	g.P()
+
+	for _, msg := range msgs {
+		generateMerge(g, msg)
+		generateDiff(g, msg)
+	}
+	return nil
+}
+
+func generateMerge(g *protogen.GeneratedFile, msg *protogen.Message) {
+	name := msg.GoIdent.GoName
+	g.P("// Implement [channeld.MergeableChannelData]")
+	g.P("func (dst *", name, ") Merge(src ", commonPackage.Ident("ChannelDataMessage"), ", options *", channeldpbPackage.Ident("ChannelDataMergeOptions"), ", spatialNotifier ", commonPackage.Ident("SpatialInfoChangedNotifier"), ") error {")
+	g.P("srcMsg, ok := src.(*", name, ")")
+	g.P("if !ok {")
+	g.P("return ", errorsPackage.Ident("New"), "(\"src is not a ", name, "\")")
+	g.P("}")
+	g.P()
+
+	for _, oneof := range msg.Oneofs {
+		if oneof.Desc.IsSynthetic() {
+			continue
+		}
+		g.P("if srcMsg.", oneof.GoName, " != nil {")
+		g.P("dst.", oneof.GoName, " = srcMsg.", oneof.GoName)
+		g.P("}")
+	}
+
+	for _, field := range msg.Fields {
+		if field.Oneof != nil && !field.Oneof.Desc.IsSynthetic() {
+			continue
+		}
+		dstField, srcField := "dst."+field.GoName, "srcMsg."+field.GoName
+		switch {
+		case field.Desc.IsMap():
+			value := field.Message.Fields[1]
+			g.P("for k, v := range ", srcField, " {")
+			if isRemovable(value) {
+				g.P("if options.ShouldCheckRemovableMapField && v.Removed {")
+				g.P("delete(", dstField, ", k)")
+				g.P("continue")
+				g.P("}")
+			}
+			g.P("if ", dstField, " == nil {")
+			g.P(dstField, " = make(map[", goType(g, field.Message.Fields[0]), "]", goType(g, value), ")")
+			g.P("}")
+			g.P(dstField, "[k] = v")
+			g.P("}")
+		case field.Desc.IsList():
+			g.P("if options.ShouldReplaceList {")
+			g.P(dstField, " = append(", dstField, "[:0:0], ", srcField, "...)")
+			g.P("} else {")
+			g.P(dstField, " = append(", dstField, ", ", srcField, "...)")
+			g.P("}")
+			g.P("if options.ListSizeLimit > 0 && len(", dstField, ") > int(options.ListSizeLimit) {")
+			g.P("if options.TruncateTop {")
+			g.P(dstField, " = ", dstField, "[len(", dstField, ")-int(options.ListSizeLimit):]")
+			g.P("} else {")
+			g.P(dstField, " = ", dstField, "[:options.ListSizeLimit]")
+			g.P("}")
+			g.P("}")
+		case field.Message != nil:
+			g.P("if ", srcField, " != nil {")
+			g.P("if ", dstField, " == nil {")
+			g.P(dstField, " = &", g.QualifiedGoIdent(field.Message.GoIdent), "{}")
+			g.P("}")
+			if isAnnotated(field.Message) {
+				g.P("if err := ", dstField, ".Merge(", srcField, ", options, spatialNotifier); err != nil {")
+				g.P("return err")
+				g.P("}")
+			} else {
+				g.P(protoPackage.Ident("Merge"), "(", dstField, ", ", srcField, ")")
+			}
+			g.P("}")
+		case field.Desc.HasPresence():
+			g.P("if ", srcField, " != nil {")
+			g.P("v := *", srcField)
+			g.P(dstField, " = &v")
+			g.P("}")
+		case field.Desc.Kind() == protoreflect.BytesKind:
+			g.P("if len(", srcField, ") > 0 {")
+			g.P(dstField, " = append(", dstField, "[:0:0], ", srcField, "...)")
+			g.P("}")
+		case field.Desc.Kind() == protoreflect.BoolKind:
+			g.P("if ", srcField, " {")
+			g.P(dstField, " = true")
+			g.P("}")
+		default:
+			g.P("if ", srcField, " != ", zeroValue(field), " {")
+			g.P(dstField, " = ", srcField)
+			g.P("}")
+		}
+	}
+	g.P("return nil")
+	g.P("}")
+	g.P()
+}
+
+func generateDiff(g *protogen.GeneratedFile, msg *protogen.Message) {
+	name := msg.GoIdent.GoName
+	g.P("// Returns the update message that changes the message into newMsg when merged, or nil if there's no change.")
+	g.P("// If any list is modified other than appended, the update should be merged with ShouldReplaceList.")
+	g.P("func (oldMsg *", name, ") Diff(newMsg *", name, ") (diff *", name, ", shouldReplaceList bool) {")
+	g.P("diff, shouldReplaceList = oldMsg.diff(newMsg, false)")
+	g.P("if shouldReplaceList {")
+	g.P("// All the lists in the changed messages should be in the diff, otherwise they are cleared by the merge.")
+	g.P("diff, _ = oldMsg.diff(newMsg, true)")
+	g.P("}")
+	g.P("return diff, shouldReplaceList")
+	g.P("}")
+	g.P()
+
+	g.P("func (oldMsg *", name, ") diff(newMsg *", name, ", replaceList bool) (*", name, ", bool) {")
+	g.P("if newMsg == nil {")
+	g.P("return nil, false")
+	g.P("}")
+	g.P("if oldMsg == nil {")
+	g.P("return newMsg, false")
+	g.P("}")
+	g.P("diff := &", name, "{}")
+	g.P("changed := false")
+	g.P("needsReplaceList := false")
+	g.P()
+
+	for _, oneof := range msg.Oneofs {
+		if oneof.Desc.IsSynthetic() {
+			continue
+		}
+		g.P("switch v := newMsg.", oneof.GoName, ".(type) {")
+		for _, field := range oneof.Fields {
+			g.P("case *", g.QualifiedGoIdent(field.GoIdent), ":")
+			g.P("if old, ok := oldMsg.", oneof.GoName, ".(*", g.QualifiedGoIdent(field.GoIdent), "); !ok || ", notEqual(g, field, "old."+field.GoName, "v."+field.GoName), " {")
+			g.P("diff.", oneof.GoName, " = v")
+			g.P("changed = true")
+			g.P("}")
+		}
+		g.P("}")
+	}
+
+	lists := make([]*protogen.Field, 0)
+	for _, field := range msg.Fields {
+		if field.Oneof != nil && !field.Oneof.Desc.IsSynthetic() {
+			continue
+		}
+		oldField, newField, diffField := "oldMsg."+field.GoName, "newMsg."+field.GoName, "diff."+field.GoName
+		switch {
+		case field.Desc.IsMap():
+			value := field.Message.Fields[1]
+			g.P("for k, v := range ", newField, " {")
+			g.P("if old, exists := ", oldField, "[k]; !exists || ", notEqual(g, value, "old", "v"), " {")
+			g.P("if ", diffField, " == nil {")
+			g.P(diffField, " = make(map[", goType(g, field.Message.Fields[0]), "]", goType(g, value), ")")
+			g.P("}")
+			g.P(diffField, "[k] = v")
+			g.P("changed = true")
+			g.P("}")
+			g.P("}")
+			if isRemovable(value) {
+				g.P("for k := range ", oldField, " {")
+				g.P("if _, exists := ", newField, "[k]; !exists {")
+				g.P("if ", diffField, " == nil {")
+				g.P(diffField, " = make(map[", goType(g, field.Message.Fields[0]), "]", goType(g, value), ")")
+				g.P("}")
+				g.P(diffField, "[k] = &", g.QualifiedGoIdent(value.Message.GoIdent), "{Removed: true}")
+				g.P("changed = true")
+				g.P("}")
+				g.P("}")
+			}
+		case field.Desc.IsList():
+			lists = append(lists, field)
+			modified := strings.ToLower(field.GoName[:1]) + field.GoName[1:] + "Modified"
+			g.P(modified, " := len(", oldField, ") > len(", newField, ")")
+			g.P("for i := 0; !", modified, " && i < len(", oldField, "); i++ {")
+			g.P(modified, " = ", notEqual(g, field, oldField+"[i]", newField+"[i]"))
+			g.P("}")
+			g.P("if ", modified, " || (replaceList && len(", oldField, ") != len(", newField, ")) {")
+			g.P(diffField, " = ", newField)
+			g.P("changed = true")
+			g.P("needsReplaceList = true")
+			g.P("} else if len(", newField, ") > len(", oldField, ") {")
+			g.P("// Only the appended elements")
+			g.P(diffField, " = ", newField, "[len(", oldField, "):]")
+			g.P("changed = true")
+			g.P("}")
+		case field.Message != nil && isAnnotated(field.Message):
+			g.P("if d, needs := ", oldField, ".diff(", newField, ", replaceList); d != nil {")
+			g.P(diffField, " = d")
+			g.P("changed = true")
+			g.P("needsReplaceList = needsReplaceList || needs")
+			g.P("}")
+		case field.Message != nil:
+			g.P("if ", newField, " != nil && ", notEqual(g, field, oldField, newField), " {")
+			g.P(diffField, " = ", newField)
+			g.P("changed = true")
+			g.P("}")
+		case field.Desc.HasPresence():
+			g.P("if ", newField, " != nil && (", oldField, " == nil || *", oldField, " != *", newField, ") {")
+			g.P(diffField, " = ", newField)
+			g.P("changed = true")
+			g.P("}")
+		case field.Desc.Kind() == protoreflect.BytesKind:
+			g.P("if len(", newField, ") > 0 && ", notEqual(g, field, oldField, newField), " {")
+			g.P(diffField, " = ", newField)
+			g.P("changed = true")
+			g.P("}")
+		case field.Desc.Kind() == protoreflect.BoolKind:
+			g.P("if ", newField, " && !", oldField, " {")
+			g.P(diffField, " = true")
+			g.P("changed = true")
+			g.P("}")
+		default:
+			g.P("if ", newField, " != ", zeroValue(field), " && ", notEqual(g, field, oldField, newField), " {")
+			g.P(diffField, " = ", newField)
+			g.P("changed = true")
+			g.P("}")
+		}
+	}
+
+	g.P()
+	g.P("if !changed {")
+	g.P("return nil, false")
+	g.P("}")
+	if len(lists) > 0 {
+		g.P("if replaceList {")
+		for _, field := range lists {
+			g.P("diff.", field.GoName, " = newMsg.", field.GoName)
+		}
+		g.P("}")
+	}
+	g.P("return diff, needsReplaceList")
+	g.P("}")
+	g.P()
+}
+
+// The map value has the "removed" field. See channeld.RemovableMapField.
+func isRemovable(value *protogen.Field) bool {
+	if value.Message == nil {
+		return false
+	}
+	for _, field := range value.Message.Fields {
+		if field.GoName == "Removed" && field.Desc.Kind() == protoreflect.BoolKind && !field.Desc.IsList() && !field.Desc.HasPresence() {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the expression that compares the single (non-list) values of the field.
+func notEqual(g *protogen.GeneratedFile, field *protogen.Field, a string, b string) string {
+	switch {
+	case field.Message != nil:
+		return "!" + g.QualifiedGoIdent(protoPackage.Ident("Equal")) + "(" + a + ", " + b + ")"
+	case field.Desc.Kind() == protoreflect.BytesKind:
+		return "!" + g.QualifiedGoIdent(bytesPackage.Ident("Equal")) + "(" + a + ", " + b + ")"
+	default:
+		return a + " != " + b
+	}
+}
+
+func zeroValue(field *protogen.Field) string {
+	switch field.Desc.Kind() {
+	case protoreflect.StringKind:
+		return `""`
+	default:
+		return "0"
+	}
+}
+
+// Returns the Go type of the single (non-list) value of the field.
+func goType(g *protogen.GeneratedFile, field *protogen.Field) string {
+	switch field.Desc.Kind() {
+	case protoreflect.BoolKind:
+		return "bool"
+	case protoreflect.EnumKind:
+		return g.QualifiedGoIdent(field.Enum.GoIdent)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return "int32"
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return "uint32"
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return "int64"
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return "uint64"
+	case protoreflect.FloatKind:
+		return "float32"
+	case protoreflect.DoubleKind:
+		return "float64"
+	case protoreflect.StringKind:
+		return "string"
+	case protoreflect.BytesKind:
+		return "[]byte"
+	default:
+		return "*" + g.QualifiedGoIdent(field.Message.GoIdent)
+	}
+}
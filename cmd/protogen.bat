@@ -1,5 +1,5 @@
 cd "%CHANNELD_PATH%\internal\testpb"
-protoc --go_out=. --go_opt=paths=source_relative -I . *.proto
+protoc --go_out=. --go_opt=paths=source_relative --channeld_out=. --channeld_opt=paths=source_relative -I . *.proto
 
 cd "%CHANNELD_PATH%\pkg\channeldpb"
 protoc --go_out=. --go_opt=paths=source_relative -I . *.proto
@@ -0,0 +1,75 @@
+// Converts the packet recordings (the .cpr files written with the -erp flag) to newline-delimited JSON, with the message
+// types resolved, for the analysis in pandas or BigQuery. Each line is a replay.ExportedMessage.
+//
+// Usage (from the repository root):
+//
+//	go run ./cmd/replayexport replays/*.cpr > messages.jsonl
+//	go run ./cmd/replayexport -ch 1,65536 -from 10000 -to 20000 -o messages.jsonl replays/session_1.cpr
+//
+// The channel data messages of the game-specific types are exported as the raw bodies, unless the types are linked,
+// e.g. by importing the proto package of the game in a copy of this command.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/metaworking/channeld/pkg/replay"
+)
+
+func main() {
+	channels := flag.String("ch", "", "the comma-separated ids of the channels to export. Empty means all the channels")
+	from := flag.Int64("from", 0, "the start of the time range to export, in milliseconds since the start of each recording")
+	to := flag.Int64("to", 0, "the end (exclusive) of the time range to export, in milliseconds since the start of each recording. 0 means no end")
+	output := flag.String("o", "", "the path of the output file. Empty means stdout")
+	flag.Parse()
+
+	options := replay.ExportOptions{FromMs: *from, ToMs: *to}
+	if *channels != "" {
+		for _, str := range strings.Split(*channels, ",") {
+			channelId, err := strconv.ParseUint(strings.TrimSpace(str), 10, 32)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "invalid channel id %q\n", str)
+				os.Exit(2)
+			}
+			options.ChannelIds = append(options.ChannelIds, uint32(channelId))
+		}
+	}
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "no recording file to export")
+		os.Exit(2)
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to create the output file: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	total := 0
+	for _, path := range flag.Args() {
+		session, err := replay.ReadReplaySessionFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		options.Session = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		count, err := replay.ExportJSON(w, session, options)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to export %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		total += count
+	}
+	fmt.Fprintf(os.Stderr, "exported %d messages from %d recordings\n", total, flag.NArg())
+}
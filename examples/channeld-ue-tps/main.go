@@ -2,13 +2,11 @@ package main
 
 import (
 	"fmt"
-	"net/http"
 
 	"github.com/metaworking/channeld/pkg/channeld"
 	"github.com/metaworking/channeld/pkg/channeldpb"
 	"github.com/metaworking/channeld/pkg/unreal"
 	"github.com/metaworking/channeld/pkg/unrealpb"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 func main() {
@@ -26,9 +24,8 @@ func main() {
 	unreal.InitMessageHandlers()
 	channeld.RegisterChannelDataType(channeldpb.ChannelType_SPATIAL, &unrealpb.SpatialChannelData{})
 
-	// Setup Prometheus
-	http.Handle("/metrics", promhttp.Handler())
-	go http.ListenAndServe(":8080", nil)
+	// Setup Prometheus and the debug endpoints
+	go channeld.StartAdminServer()
 
 	go channeld.StartListening(channeldpb.ConnectionType_SERVER, channeld.GlobalSettings.ServerNetwork, channeld.GlobalSettings.ServerAddress)
 
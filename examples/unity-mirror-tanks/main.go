@@ -2,12 +2,10 @@ package main
 
 import (
 	"fmt"
-	"net/http"
 
 	"github.com/metaworking/channeld/examples/unity-mirror-tanks/tankspb"
 	"github.com/metaworking/channeld/pkg/channeld"
 	"github.com/metaworking/channeld/pkg/channeldpb"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 func main() {
@@ -24,9 +22,8 @@ func main() {
 
 	channeld.InitSpatialController()
 
-	// Setup Prometheus
-	http.Handle("/metrics", promhttp.Handler())
-	go http.ListenAndServe(":8080", nil)
+	// Setup Prometheus and the debug endpoints
+	go channeld.StartAdminServer()
 
 	go channeld.StartListening(channeldpb.ConnectionType_SERVER, channeld.GlobalSettings.ServerNetwork, channeld.GlobalSettings.ServerAddress)
 	// FIXME: After all the server connections are established, the client connection should be listened.*/
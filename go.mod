@@ -6,11 +6,15 @@ require (
 	github.com/golang/snappy v0.0.4
 	github.com/gorilla/websocket v1.4.2
 	github.com/indiest/fmutils v0.1.2
+	github.com/mattn/go-sqlite3 v1.14.16
 	github.com/pkg/profile v1.6.0
 	github.com/prometheus/client_golang v1.11.1
 	github.com/stretchr/testify v1.8.1
+	github.com/vmihailenco/msgpack/v5 v5.3.5
 	github.com/xtaci/kcp-go v5.4.20+incompatible
+	github.com/yuin/gopher-lua v1.1.1
 	go.uber.org/zap v1.19.1
+	golang.org/x/crypto v0.6.0
 	google.golang.org/protobuf v1.28.1
 )
 
@@ -26,7 +30,7 @@ require (
 	github.com/mennanov/fmutils v0.1.1 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/prometheus/client_model v0.2.0 // indirect
+	github.com/prometheus/client_model v0.2.0
 	github.com/prometheus/common v0.26.0 // indirect
 	github.com/prometheus/procfs v0.6.0 // indirect
 	github.com/puzpuzpuz/xsync/v2 v2.4.0
@@ -34,10 +38,10 @@ require (
 	github.com/templexxx/cpufeat v0.0.0-20180724012125-cef66df7f161 // indirect
 	github.com/templexxx/xor v0.0.0-20191217153810-f85b25db303b // indirect
 	github.com/tjfoc/gmsm v1.4.1 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
 	github.com/xtaci/lossyconn v0.0.0-20200209145036-adba10fffc37 // indirect
 	go.uber.org/atomic v1.9.0 // indirect
 	go.uber.org/multierr v1.7.0 // indirect
-	golang.org/x/crypto v0.6.0 // indirect
 	golang.org/x/net v0.7.0 // indirect
 	golang.org/x/sys v0.5.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
@@ -328,6 +328,333 @@ func (x *TestFieldMaskMessage) GetKv2() map[int64]string {
 	return nil
 }
 
+// @channeld:merge
+type TestGeneratedMergeMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name    string                                    `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Num     *int64                                    `protobuf:"varint,2,opt,name=num,proto3,oneof" json:"num,omitempty"`
+	Flag    bool                                      `protobuf:"varint,3,opt,name=flag,proto3" json:"flag,omitempty"`
+	Payload []byte                                    `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
+	Msg     *TestChannelDataMessage                   `protobuf:"bytes,5,opt,name=msg,proto3" json:"msg,omitempty"`
+	Nested  *TestGeneratedNestedMessage               `protobuf:"bytes,6,opt,name=nested,proto3" json:"nested,omitempty"`
+	List    []string                                  `protobuf:"bytes,7,rep,name=list,proto3" json:"list,omitempty"`
+	MsgList []*TestChannelDataMessage                 `protobuf:"bytes,8,rep,name=msgList,proto3" json:"msgList,omitempty"`
+	Kv      map[int64]*TestMergeMessage_StringWrapper `protobuf:"bytes,9,rep,name=kv,proto3" json:"kv,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Kv2     map[string]uint32                         `protobuf:"bytes,10,rep,name=kv2,proto3" json:"kv2,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// Types that are assignable to Value:
+	//	*TestGeneratedMergeMessage_Text
+	//	*TestGeneratedMergeMessage_Data
+	Value isTestGeneratedMergeMessage_Value `protobuf_oneof:"value"`
+}
+
+func (x *TestGeneratedMergeMessage) Reset() {
+	*x = TestGeneratedMergeMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_test_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TestGeneratedMergeMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestGeneratedMergeMessage) ProtoMessage() {}
+
+func (x *TestGeneratedMergeMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestGeneratedMergeMessage.ProtoReflect.Descriptor instead.
+func (*TestGeneratedMergeMessage) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TestGeneratedMergeMessage) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TestGeneratedMergeMessage) GetNum() int64 {
+	if x != nil && x.Num != nil {
+		return *x.Num
+	}
+	return 0
+}
+
+func (x *TestGeneratedMergeMessage) GetFlag() bool {
+	if x != nil {
+		return x.Flag
+	}
+	return false
+}
+
+func (x *TestGeneratedMergeMessage) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *TestGeneratedMergeMessage) GetMsg() *TestChannelDataMessage {
+	if x != nil {
+		return x.Msg
+	}
+	return nil
+}
+
+func (x *TestGeneratedMergeMessage) GetNested() *TestGeneratedNestedMessage {
+	if x != nil {
+		return x.Nested
+	}
+	return nil
+}
+
+func (x *TestGeneratedMergeMessage) GetList() []string {
+	if x != nil {
+		return x.List
+	}
+	return nil
+}
+
+func (x *TestGeneratedMergeMessage) GetMsgList() []*TestChannelDataMessage {
+	if x != nil {
+		return x.MsgList
+	}
+	return nil
+}
+
+func (x *TestGeneratedMergeMessage) GetKv() map[int64]*TestMergeMessage_StringWrapper {
+	if x != nil {
+		return x.Kv
+	}
+	return nil
+}
+
+func (x *TestGeneratedMergeMessage) GetKv2() map[string]uint32 {
+	if x != nil {
+		return x.Kv2
+	}
+	return nil
+}
+
+func (m *TestGeneratedMergeMessage) GetValue() isTestGeneratedMergeMessage_Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (x *TestGeneratedMergeMessage) GetText() string {
+	if x, ok := x.GetValue().(*TestGeneratedMergeMessage_Text); ok {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *TestGeneratedMergeMessage) GetData() *TestChannelDataMessage {
+	if x, ok := x.GetValue().(*TestGeneratedMergeMessage_Data); ok {
+		return x.Data
+	}
+	return nil
+}
+
+type isTestGeneratedMergeMessage_Value interface {
+	isTestGeneratedMergeMessage_Value()
+}
+
+type TestGeneratedMergeMessage_Text struct {
+	Text string `protobuf:"bytes,11,opt,name=text,proto3,oneof"`
+}
+
+type TestGeneratedMergeMessage_Data struct {
+	Data *TestChannelDataMessage `protobuf:"bytes,12,opt,name=data,proto3,oneof"`
+}
+
+func (*TestGeneratedMergeMessage_Text) isTestGeneratedMergeMessage_Value() {}
+
+func (*TestGeneratedMergeMessage_Data) isTestGeneratedMergeMessage_Value() {}
+
+// @channeld:merge
+type TestGeneratedNestedMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	P1 float32  `protobuf:"fixed32,1,opt,name=p1,proto3" json:"p1,omitempty"`
+	P2 []uint32 `protobuf:"varint,2,rep,packed,name=p2,proto3" json:"p2,omitempty"`
+}
+
+func (x *TestGeneratedNestedMessage) Reset() {
+	*x = TestGeneratedNestedMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_test_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TestGeneratedNestedMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestGeneratedNestedMessage) ProtoMessage() {}
+
+func (x *TestGeneratedNestedMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestGeneratedNestedMessage.ProtoReflect.Descriptor instead.
+func (*TestGeneratedNestedMessage) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TestGeneratedNestedMessage) GetP1() float32 {
+	if x != nil {
+		return x.P1
+	}
+	return 0
+}
+
+func (x *TestGeneratedNestedMessage) GetP2() []uint32 {
+	if x != nil {
+		return x.P2
+	}
+	return nil
+}
+
+type TestVector struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	X float32 `protobuf:"fixed32,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y float32 `protobuf:"fixed32,2,opt,name=y,proto3" json:"y,omitempty"`
+	Z float32 `protobuf:"fixed32,3,opt,name=z,proto3" json:"z,omitempty"`
+}
+
+func (x *TestVector) Reset() {
+	*x = TestVector{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_test_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TestVector) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestVector) ProtoMessage() {}
+
+func (x *TestVector) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestVector.ProtoReflect.Descriptor instead.
+func (*TestVector) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TestVector) GetX() float32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *TestVector) GetY() float32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *TestVector) GetZ() float32 {
+	if x != nil {
+		return x.Z
+	}
+	return 0
+}
+
+type TestMoverMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Movers map[uint32]*TestMoverMessage_Mover `protobuf:"bytes,1,rep,name=movers,proto3" json:"movers,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *TestMoverMessage) Reset() {
+	*x = TestMoverMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_test_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TestMoverMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestMoverMessage) ProtoMessage() {}
+
+func (x *TestMoverMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestMoverMessage.ProtoReflect.Descriptor instead.
+func (*TestMoverMessage) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TestMoverMessage) GetMovers() map[uint32]*TestMoverMessage_Mover {
+	if x != nil {
+		return x.Movers
+	}
+	return nil
+}
+
 type TestAnyMessage_Type1 struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -339,7 +666,7 @@ type TestAnyMessage_Type1 struct {
 func (x *TestAnyMessage_Type1) Reset() {
 	*x = TestAnyMessage_Type1{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_test_proto_msgTypes[5]
+		mi := &file_test_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -352,7 +679,7 @@ func (x *TestAnyMessage_Type1) String() string {
 func (*TestAnyMessage_Type1) ProtoMessage() {}
 
 func (x *TestAnyMessage_Type1) ProtoReflect() protoreflect.Message {
-	mi := &file_test_proto_msgTypes[5]
+	mi := &file_test_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -386,7 +713,7 @@ type TestAnyMessage_Type2 struct {
 func (x *TestAnyMessage_Type2) Reset() {
 	*x = TestAnyMessage_Type2{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_test_proto_msgTypes[6]
+		mi := &file_test_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -399,7 +726,7 @@ func (x *TestAnyMessage_Type2) String() string {
 func (*TestAnyMessage_Type2) ProtoMessage() {}
 
 func (x *TestAnyMessage_Type2) ProtoReflect() protoreflect.Message {
-	mi := &file_test_proto_msgTypes[6]
+	mi := &file_test_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -434,7 +761,7 @@ type TestMergeMessage_StringWrapper struct {
 func (x *TestMergeMessage_StringWrapper) Reset() {
 	*x = TestMergeMessage_StringWrapper{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_test_proto_msgTypes[7]
+		mi := &file_test_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -447,7 +774,7 @@ func (x *TestMergeMessage_StringWrapper) String() string {
 func (*TestMergeMessage_StringWrapper) ProtoMessage() {}
 
 func (x *TestMergeMessage_StringWrapper) ProtoReflect() protoreflect.Message {
-	mi := &file_test_proto_msgTypes[7]
+	mi := &file_test_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -489,7 +816,7 @@ type TestMapMessage_StringWrapper struct {
 func (x *TestMapMessage_StringWrapper) Reset() {
 	*x = TestMapMessage_StringWrapper{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_test_proto_msgTypes[10]
+		mi := &file_test_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -502,7 +829,7 @@ func (x *TestMapMessage_StringWrapper) String() string {
 func (*TestMapMessage_StringWrapper) ProtoMessage() {}
 
 func (x *TestMapMessage_StringWrapper) ProtoReflect() protoreflect.Message {
-	mi := &file_test_proto_msgTypes[10]
+	mi := &file_test_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -544,7 +871,7 @@ type TestMapMessage_NullableWrapper struct {
 func (x *TestMapMessage_NullableWrapper) Reset() {
 	*x = TestMapMessage_NullableWrapper{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_test_proto_msgTypes[12]
+		mi := &file_test_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -557,7 +884,7 @@ func (x *TestMapMessage_NullableWrapper) String() string {
 func (*TestMapMessage_NullableWrapper) ProtoMessage() {}
 
 func (x *TestMapMessage_NullableWrapper) ProtoReflect() protoreflect.Message {
-	mi := &file_test_proto_msgTypes[12]
+	mi := &file_test_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -599,7 +926,7 @@ type TestFieldMaskMessage_NestedMessage struct {
 func (x *TestFieldMaskMessage_NestedMessage) Reset() {
 	*x = TestFieldMaskMessage_NestedMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_test_proto_msgTypes[14]
+		mi := &file_test_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -612,7 +939,7 @@ func (x *TestFieldMaskMessage_NestedMessage) String() string {
 func (*TestFieldMaskMessage_NestedMessage) ProtoMessage() {}
 
 func (x *TestFieldMaskMessage_NestedMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_test_proto_msgTypes[14]
+	mi := &file_test_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -642,6 +969,85 @@ func (x *TestFieldMaskMessage_NestedMessage) GetP2() uint32 {
 	return 0
 }
 
+type TestMoverMessage_Mover struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Position     *TestVector `protobuf:"bytes,1,opt,name=position,proto3" json:"position,omitempty"`
+	Velocity     *TestVector `protobuf:"bytes,2,opt,name=velocity,proto3" json:"velocity,omitempty"`
+	Acceleration *TestVector `protobuf:"bytes,3,opt,name=acceleration,proto3" json:"acceleration,omitempty"`
+	Name         string      `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Removed      bool        `protobuf:"varint,5,opt,name=removed,proto3" json:"removed,omitempty"`
+}
+
+func (x *TestMoverMessage_Mover) Reset() {
+	*x = TestMoverMessage_Mover{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_test_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TestMoverMessage_Mover) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestMoverMessage_Mover) ProtoMessage() {}
+
+func (x *TestMoverMessage_Mover) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestMoverMessage_Mover.ProtoReflect.Descriptor instead.
+func (*TestMoverMessage_Mover) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{8, 0}
+}
+
+func (x *TestMoverMessage_Mover) GetPosition() *TestVector {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *TestMoverMessage_Mover) GetVelocity() *TestVector {
+	if x != nil {
+		return x.Velocity
+	}
+	return nil
+}
+
+func (x *TestMoverMessage_Mover) GetAcceleration() *TestVector {
+	if x != nil {
+		return x.Acceleration
+	}
+	return nil
+}
+
+func (x *TestMoverMessage_Mover) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TestMoverMessage_Mover) GetRemoved() bool {
+	if x != nil {
+		return x.Removed
+	}
+	return false
+}
+
 var File_test_proto protoreflect.FileDescriptor
 
 var file_test_proto_rawDesc = []byte{
@@ -744,10 +1150,83 @@ var file_test_proto_rawDesc = []byte{
 	0x76, 0x32, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
 	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
-	0x02, 0x38, 0x01, 0x42, 0x2c, 0x5a, 0x2a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
-	0x6d, 0x2f, 0x6d, 0x65, 0x74, 0x61, 0x77, 0x6f, 0x72, 0x6b, 0x69, 0x6e, 0x67, 0x2f, 0x63, 0x68,
-	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x74, 0x65, 0x73, 0x74, 0x70,
-	0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x02, 0x38, 0x01, 0x22, 0x9d, 0x05, 0x0a, 0x19, 0x54, 0x65, 0x73, 0x74, 0x47, 0x65, 0x6e, 0x65,
+	0x72, 0x61, 0x74, 0x65, 0x64, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x15, 0x0a, 0x03, 0x6e, 0x75, 0x6d, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x48, 0x01, 0x52, 0x03, 0x6e, 0x75, 0x6d, 0x88, 0x01, 0x01, 0x12, 0x12, 0x0a, 0x04,
+	0x66, 0x6c, 0x61, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x66, 0x6c, 0x61, 0x67,
+	0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x30, 0x0a, 0x03, 0x6d, 0x73,
+	0x67, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x74, 0x65, 0x73, 0x74, 0x70, 0x62,
+	0x2e, 0x54, 0x65, 0x73, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74, 0x61,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x03, 0x6d, 0x73, 0x67, 0x12, 0x3a, 0x0a, 0x06,
+	0x6e, 0x65, 0x73, 0x74, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x74,
+	0x65, 0x73, 0x74, 0x70, 0x62, 0x2e, 0x54, 0x65, 0x73, 0x74, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61,
+	0x74, 0x65, 0x64, 0x4e, 0x65, 0x73, 0x74, 0x65, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x52, 0x06, 0x6e, 0x65, 0x73, 0x74, 0x65, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69, 0x73, 0x74,
+	0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x38, 0x0a, 0x07,
+	0x6d, 0x73, 0x67, 0x4c, 0x69, 0x73, 0x74, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e,
+	0x74, 0x65, 0x73, 0x74, 0x70, 0x62, 0x2e, 0x54, 0x65, 0x73, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x07, 0x6d,
+	0x73, 0x67, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x39, 0x0a, 0x02, 0x6b, 0x76, 0x18, 0x09, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x29, 0x2e, 0x74, 0x65, 0x73, 0x74, 0x70, 0x62, 0x2e, 0x54, 0x65, 0x73, 0x74,
+	0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x4b, 0x76, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x02, 0x6b,
+	0x76, 0x12, 0x3c, 0x0a, 0x03, 0x6b, 0x76, 0x32, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a,
+	0x2e, 0x74, 0x65, 0x73, 0x74, 0x70, 0x62, 0x2e, 0x54, 0x65, 0x73, 0x74, 0x47, 0x65, 0x6e, 0x65,
+	0x72, 0x61, 0x74, 0x65, 0x64, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x2e, 0x4b, 0x76, 0x32, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x03, 0x6b, 0x76, 0x32, 0x12,
+	0x14, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
+	0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x34, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x74, 0x65, 0x73, 0x74, 0x70, 0x62, 0x2e, 0x54, 0x65, 0x73,
+	0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x1a, 0x5d, 0x0a, 0x07, 0x4b,
+	0x76, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x3c, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x74, 0x65, 0x73, 0x74, 0x70, 0x62,
+	0x2e, 0x54, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x57, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x36, 0x0a, 0x08, 0x4b, 0x76,
+	0x32, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x06, 0x0a, 0x04, 0x5f,
+	0x6e, 0x75, 0x6d, 0x22, 0x3c, 0x0a, 0x1a, 0x54, 0x65, 0x73, 0x74, 0x47, 0x65, 0x6e, 0x65, 0x72,
+	0x61, 0x74, 0x65, 0x64, 0x4e, 0x65, 0x73, 0x74, 0x65, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x0e, 0x0a, 0x02, 0x70, 0x31, 0x18, 0x01, 0x20, 0x01, 0x28, 0x02, 0x52, 0x02, 0x70,
+	0x31, 0x12, 0x0e, 0x0a, 0x02, 0x70, 0x32, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x02, 0x70,
+	0x32, 0x22, 0x36, 0x0a, 0x0a, 0x54, 0x65, 0x73, 0x74, 0x56, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12,
+	0x0c, 0x0a, 0x01, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x02, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a,
+	0x01, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x01, 0x79, 0x12, 0x0c, 0x0a, 0x01, 0x7a,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x02, 0x52, 0x01, 0x7a, 0x22, 0xfb, 0x02, 0x0a, 0x10, 0x54, 0x65,
+	0x73, 0x74, 0x4d, 0x6f, 0x76, 0x65, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x3c,
+	0x0a, 0x06, 0x6d, 0x6f, 0x76, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24,
+	0x2e, 0x74, 0x65, 0x73, 0x74, 0x70, 0x62, 0x2e, 0x54, 0x65, 0x73, 0x74, 0x4d, 0x6f, 0x76, 0x65,
+	0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x4d, 0x6f, 0x76, 0x65, 0x72, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x6d, 0x6f, 0x76, 0x65, 0x72, 0x73, 0x1a, 0xcd, 0x01, 0x0a,
+	0x05, 0x4d, 0x6f, 0x76, 0x65, 0x72, 0x12, 0x2e, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74, 0x65, 0x73, 0x74, 0x70,
+	0x62, 0x2e, 0x54, 0x65, 0x73, 0x74, 0x56, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x08, 0x70, 0x6f,
+	0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2e, 0x0a, 0x08, 0x76, 0x65, 0x6c, 0x6f, 0x63, 0x69,
+	0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74, 0x65, 0x73, 0x74, 0x70,
+	0x62, 0x2e, 0x54, 0x65, 0x73, 0x74, 0x56, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x08, 0x76, 0x65,
+	0x6c, 0x6f, 0x63, 0x69, 0x74, 0x79, 0x12, 0x36, 0x0a, 0x0c, 0x61, 0x63, 0x63, 0x65, 0x6c, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74,
+	0x65, 0x73, 0x74, 0x70, 0x62, 0x2e, 0x54, 0x65, 0x73, 0x74, 0x56, 0x65, 0x63, 0x74, 0x6f, 0x72,
+	0x52, 0x0c, 0x61, 0x63, 0x63, 0x65, 0x6c, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x1a, 0x59, 0x0a, 0x0b,
+	0x4d, 0x6f, 0x76, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x34, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x74,
+	0x65, 0x73, 0x74, 0x70, 0x62, 0x2e, 0x54, 0x65, 0x73, 0x74, 0x4d, 0x6f, 0x76, 0x65, 0x72, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x4d, 0x6f, 0x76, 0x65, 0x72, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x2c, 0x5a, 0x2a, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d, 0x65, 0x74, 0x61, 0x77, 0x6f, 0x72, 0x6b, 0x69, 0x6e,
+	0x67, 0x2f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x74,
+	0x65, 0x73, 0x74, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -762,47 +1241,67 @@ func file_test_proto_rawDescGZIP() []byte {
 	return file_test_proto_rawDescData
 }
 
-var file_test_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_test_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
 var file_test_proto_goTypes = []interface{}{
 	(*TestChannelDataMessage)(nil),         // 0: testpb.TestChannelDataMessage
 	(*TestAnyMessage)(nil),                 // 1: testpb.TestAnyMessage
 	(*TestMergeMessage)(nil),               // 2: testpb.TestMergeMessage
 	(*TestMapMessage)(nil),                 // 3: testpb.TestMapMessage
 	(*TestFieldMaskMessage)(nil),           // 4: testpb.TestFieldMaskMessage
-	(*TestAnyMessage_Type1)(nil),           // 5: testpb.TestAnyMessage.Type1
-	(*TestAnyMessage_Type2)(nil),           // 6: testpb.TestAnyMessage.Type2
-	(*TestMergeMessage_StringWrapper)(nil), // 7: testpb.TestMergeMessage.StringWrapper
-	nil,                                    // 8: testpb.TestMergeMessage.KvEntry
-	nil,                                    // 9: testpb.TestMapMessage.KvEntry
-	(*TestMapMessage_StringWrapper)(nil),   // 10: testpb.TestMapMessage.StringWrapper
-	nil,                                    // 11: testpb.TestMapMessage.Kv2Entry
-	(*TestMapMessage_NullableWrapper)(nil), // 12: testpb.TestMapMessage.NullableWrapper
-	nil,                                    // 13: testpb.TestMapMessage.Kv3Entry
-	(*TestFieldMaskMessage_NestedMessage)(nil), // 14: testpb.TestFieldMaskMessage.NestedMessage
-	nil,               // 15: testpb.TestFieldMaskMessage.Kv1Entry
-	nil,               // 16: testpb.TestFieldMaskMessage.Kv2Entry
-	(*anypb.Any)(nil), // 17: google.protobuf.Any
+	(*TestGeneratedMergeMessage)(nil),      // 5: testpb.TestGeneratedMergeMessage
+	(*TestGeneratedNestedMessage)(nil),     // 6: testpb.TestGeneratedNestedMessage
+	(*TestVector)(nil),                     // 7: testpb.TestVector
+	(*TestMoverMessage)(nil),               // 8: testpb.TestMoverMessage
+	(*TestAnyMessage_Type1)(nil),           // 9: testpb.TestAnyMessage.Type1
+	(*TestAnyMessage_Type2)(nil),           // 10: testpb.TestAnyMessage.Type2
+	(*TestMergeMessage_StringWrapper)(nil), // 11: testpb.TestMergeMessage.StringWrapper
+	nil,                                    // 12: testpb.TestMergeMessage.KvEntry
+	nil,                                    // 13: testpb.TestMapMessage.KvEntry
+	(*TestMapMessage_StringWrapper)(nil),   // 14: testpb.TestMapMessage.StringWrapper
+	nil,                                    // 15: testpb.TestMapMessage.Kv2Entry
+	(*TestMapMessage_NullableWrapper)(nil), // 16: testpb.TestMapMessage.NullableWrapper
+	nil,                                    // 17: testpb.TestMapMessage.Kv3Entry
+	(*TestFieldMaskMessage_NestedMessage)(nil), // 18: testpb.TestFieldMaskMessage.NestedMessage
+	nil,                            // 19: testpb.TestFieldMaskMessage.Kv1Entry
+	nil,                            // 20: testpb.TestFieldMaskMessage.Kv2Entry
+	nil,                            // 21: testpb.TestGeneratedMergeMessage.KvEntry
+	nil,                            // 22: testpb.TestGeneratedMergeMessage.Kv2Entry
+	(*TestMoverMessage_Mover)(nil), // 23: testpb.TestMoverMessage.Mover
+	nil,                            // 24: testpb.TestMoverMessage.MoversEntry
+	(*anypb.Any)(nil),              // 25: google.protobuf.Any
 }
 var file_test_proto_depIdxs = []int32{
-	17, // 0: testpb.TestAnyMessage.msg:type_name -> google.protobuf.Any
-	17, // 1: testpb.TestAnyMessage.list:type_name -> google.protobuf.Any
-	8,  // 2: testpb.TestMergeMessage.kv:type_name -> testpb.TestMergeMessage.KvEntry
-	9,  // 3: testpb.TestMapMessage.kv:type_name -> testpb.TestMapMessage.KvEntry
-	11, // 4: testpb.TestMapMessage.kv2:type_name -> testpb.TestMapMessage.Kv2Entry
-	13, // 5: testpb.TestMapMessage.kv3:type_name -> testpb.TestMapMessage.Kv3Entry
-	14, // 6: testpb.TestFieldMaskMessage.msg:type_name -> testpb.TestFieldMaskMessage.NestedMessage
-	14, // 7: testpb.TestFieldMaskMessage.list:type_name -> testpb.TestFieldMaskMessage.NestedMessage
-	15, // 8: testpb.TestFieldMaskMessage.kv1:type_name -> testpb.TestFieldMaskMessage.Kv1Entry
-	16, // 9: testpb.TestFieldMaskMessage.kv2:type_name -> testpb.TestFieldMaskMessage.Kv2Entry
-	7,  // 10: testpb.TestMergeMessage.KvEntry.value:type_name -> testpb.TestMergeMessage.StringWrapper
-	10, // 11: testpb.TestMapMessage.Kv2Entry.value:type_name -> testpb.TestMapMessage.StringWrapper
-	12, // 12: testpb.TestMapMessage.Kv3Entry.value:type_name -> testpb.TestMapMessage.NullableWrapper
-	14, // 13: testpb.TestFieldMaskMessage.Kv1Entry.value:type_name -> testpb.TestFieldMaskMessage.NestedMessage
-	14, // [14:14] is the sub-list for method output_type
-	14, // [14:14] is the sub-list for method input_type
-	14, // [14:14] is the sub-list for extension type_name
-	14, // [14:14] is the sub-list for extension extendee
-	0,  // [0:14] is the sub-list for field type_name
+	25, // 0: testpb.TestAnyMessage.msg:type_name -> google.protobuf.Any
+	25, // 1: testpb.TestAnyMessage.list:type_name -> google.protobuf.Any
+	12, // 2: testpb.TestMergeMessage.kv:type_name -> testpb.TestMergeMessage.KvEntry
+	13, // 3: testpb.TestMapMessage.kv:type_name -> testpb.TestMapMessage.KvEntry
+	15, // 4: testpb.TestMapMessage.kv2:type_name -> testpb.TestMapMessage.Kv2Entry
+	17, // 5: testpb.TestMapMessage.kv3:type_name -> testpb.TestMapMessage.Kv3Entry
+	18, // 6: testpb.TestFieldMaskMessage.msg:type_name -> testpb.TestFieldMaskMessage.NestedMessage
+	18, // 7: testpb.TestFieldMaskMessage.list:type_name -> testpb.TestFieldMaskMessage.NestedMessage
+	19, // 8: testpb.TestFieldMaskMessage.kv1:type_name -> testpb.TestFieldMaskMessage.Kv1Entry
+	20, // 9: testpb.TestFieldMaskMessage.kv2:type_name -> testpb.TestFieldMaskMessage.Kv2Entry
+	0,  // 10: testpb.TestGeneratedMergeMessage.msg:type_name -> testpb.TestChannelDataMessage
+	6,  // 11: testpb.TestGeneratedMergeMessage.nested:type_name -> testpb.TestGeneratedNestedMessage
+	0,  // 12: testpb.TestGeneratedMergeMessage.msgList:type_name -> testpb.TestChannelDataMessage
+	21, // 13: testpb.TestGeneratedMergeMessage.kv:type_name -> testpb.TestGeneratedMergeMessage.KvEntry
+	22, // 14: testpb.TestGeneratedMergeMessage.kv2:type_name -> testpb.TestGeneratedMergeMessage.Kv2Entry
+	0,  // 15: testpb.TestGeneratedMergeMessage.data:type_name -> testpb.TestChannelDataMessage
+	24, // 16: testpb.TestMoverMessage.movers:type_name -> testpb.TestMoverMessage.MoversEntry
+	11, // 17: testpb.TestMergeMessage.KvEntry.value:type_name -> testpb.TestMergeMessage.StringWrapper
+	14, // 18: testpb.TestMapMessage.Kv2Entry.value:type_name -> testpb.TestMapMessage.StringWrapper
+	16, // 19: testpb.TestMapMessage.Kv3Entry.value:type_name -> testpb.TestMapMessage.NullableWrapper
+	18, // 20: testpb.TestFieldMaskMessage.Kv1Entry.value:type_name -> testpb.TestFieldMaskMessage.NestedMessage
+	11, // 21: testpb.TestGeneratedMergeMessage.KvEntry.value:type_name -> testpb.TestMergeMessage.StringWrapper
+	7,  // 22: testpb.TestMoverMessage.Mover.position:type_name -> testpb.TestVector
+	7,  // 23: testpb.TestMoverMessage.Mover.velocity:type_name -> testpb.TestVector
+	7,  // 24: testpb.TestMoverMessage.Mover.acceleration:type_name -> testpb.TestVector
+	23, // 25: testpb.TestMoverMessage.MoversEntry.value:type_name -> testpb.TestMoverMessage.Mover
+	26, // [26:26] is the sub-list for method output_type
+	26, // [26:26] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
 }
 
 func init() { file_test_proto_init() }
@@ -872,7 +1371,7 @@ func file_test_proto_init() {
 			}
 		}
 		file_test_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TestAnyMessage_Type1); i {
+			switch v := v.(*TestGeneratedMergeMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -884,7 +1383,7 @@ func file_test_proto_init() {
 			}
 		}
 		file_test_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TestAnyMessage_Type2); i {
+			switch v := v.(*TestGeneratedNestedMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -896,7 +1395,31 @@ func file_test_proto_init() {
 			}
 		}
 		file_test_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TestMergeMessage_StringWrapper); i {
+			switch v := v.(*TestVector); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_test_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TestMoverMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_test_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TestAnyMessage_Type1); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -908,6 +1431,30 @@ func file_test_proto_init() {
 			}
 		}
 		file_test_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TestAnyMessage_Type2); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_test_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TestMergeMessage_StringWrapper); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_test_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*TestMapMessage_StringWrapper); i {
 			case 0:
 				return &v.state
@@ -919,7 +1466,7 @@ func file_test_proto_init() {
 				return nil
 			}
 		}
-		file_test_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+		file_test_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*TestMapMessage_NullableWrapper); i {
 			case 0:
 				return &v.state
@@ -931,7 +1478,7 @@ func file_test_proto_init() {
 				return nil
 			}
 		}
-		file_test_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+		file_test_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*TestFieldMaskMessage_NestedMessage); i {
 			case 0:
 				return &v.state
@@ -943,15 +1490,31 @@ func file_test_proto_init() {
 				return nil
 			}
 		}
+		file_test_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TestMoverMessage_Mover); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_test_proto_msgTypes[5].OneofWrappers = []interface{}{
+		(*TestGeneratedMergeMessage_Text)(nil),
+		(*TestGeneratedMergeMessage_Data)(nil),
 	}
-	file_test_proto_msgTypes[12].OneofWrappers = []interface{}{}
+	file_test_proto_msgTypes[16].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_test_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   17,
+			NumMessages:   25,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
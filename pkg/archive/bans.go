@@ -0,0 +1,145 @@
+package archive
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeld"
+)
+
+const banSchema = `
+CREATE TABLE IF NOT EXISTS bans (
+	type TEXT NOT NULL,
+	value TEXT NOT NULL,
+	reason TEXT NOT NULL,
+	issued_by TEXT NOT NULL,
+	created_at INTEGER NOT NULL,
+	expires_at INTEGER NOT NULL,
+	PRIMARY KEY (type, value)
+);
+CREATE TABLE IF NOT EXISTS ban_audit (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	time INTEGER NOT NULL,
+	action TEXT NOT NULL,
+	type TEXT NOT NULL,
+	value TEXT NOT NULL,
+	reason TEXT NOT NULL,
+	issued_by TEXT NOT NULL,
+	expires_at INTEGER NOT NULL,
+	operator TEXT NOT NULL
+);
+`
+
+// Implements channeld.BanStore with SQLite. Unlike the messages, the bans are written synchronously, as they're rare
+// and should never be lost.
+type SQLiteBanStore struct {
+	db *sql.DB
+}
+
+// Opens the database of the ban list and creates the tables if they don't exist. ":memory:" for a temporary database.
+func OpenSQLiteBanStore(path string) (*SQLiteBanStore, error) {
+	dsn := path
+	if dsn != ":memory:" {
+		dsn = "file:" + dsn + "?_journal_mode=WAL&_busy_timeout=5000"
+	}
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if path == ":memory:" {
+		// Every connection has its own in-memory database
+		db.SetMaxOpenConns(1)
+	}
+	if _, err := db.Exec(banSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &SQLiteBanStore{db: db}, nil
+}
+
+func (s *SQLiteBanStore) LoadBans() ([]*channeld.BanRecord, error) {
+	rows, err := s.db.Query("SELECT type, value, reason, issued_by, created_at, expires_at FROM bans")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*channeld.BanRecord
+	for rows.Next() {
+		ban := &channeld.BanRecord{}
+		var createdAt, expiresAt int64
+		if err := rows.Scan(&ban.Type, &ban.Value, &ban.Reason, &ban.IssuedBy, &createdAt, &expiresAt); err != nil {
+			return nil, err
+		}
+		ban.CreatedAt = fromUnixMilli(createdAt)
+		ban.ExpiresAt = fromUnixMilli(expiresAt)
+		result = append(result, ban)
+	}
+	return result, rows.Err()
+}
+
+func (s *SQLiteBanStore) SaveBan(ban *channeld.BanRecord) error {
+	_, err := s.db.Exec("INSERT OR REPLACE INTO bans (type, value, reason, issued_by, created_at, expires_at) VALUES (?, ?, ?, ?, ?, ?)",
+		string(ban.Type), ban.Value, ban.Reason, ban.IssuedBy, toUnixMilli(ban.CreatedAt), toUnixMilli(ban.ExpiresAt))
+	return err
+}
+
+func (s *SQLiteBanStore) DeleteBan(banType channeld.BanType, value string) error {
+	_, err := s.db.Exec("DELETE FROM bans WHERE type = ? AND value = ?", string(banType), value)
+	return err
+}
+
+func (s *SQLiteBanStore) AppendBanAudit(entry *channeld.BanAuditEntry) error {
+	_, err := s.db.Exec("INSERT INTO ban_audit (time, action, type, value, reason, issued_by, expires_at, operator) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		toUnixMilli(entry.Time), entry.Action, string(entry.Ban.Type), entry.Ban.Value, entry.Ban.Reason, entry.Ban.IssuedBy,
+		toUnixMilli(entry.Ban.ExpiresAt), entry.Operator)
+	return err
+}
+
+// Returns the audit trail of the ban list in the order of being appended, optionally filtered by the type and value of the ban.
+func (s *SQLiteBanStore) Audit(banType channeld.BanType, value string) ([]*channeld.BanAuditEntry, error) {
+	stmt := "SELECT time, action, type, value, reason, issued_by, expires_at, operator FROM ban_audit"
+	var args []interface{}
+	if value != "" {
+		stmt += " WHERE type = ? AND value = ?"
+		args = append(args, string(banType), value)
+	}
+	rows, err := s.db.Query(stmt+" ORDER BY id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*channeld.BanAuditEntry
+	for rows.Next() {
+		entry := &channeld.BanAuditEntry{}
+		var t, expiresAt int64
+		if err := rows.Scan(&t, &entry.Action, &entry.Ban.Type, &entry.Ban.Value, &entry.Ban.Reason, &entry.Ban.IssuedBy,
+			&expiresAt, &entry.Operator); err != nil {
+			return nil, err
+		}
+		entry.Time = fromUnixMilli(t)
+		entry.Ban.ExpiresAt = fromUnixMilli(expiresAt)
+		result = append(result, entry)
+	}
+	return result, rows.Err()
+}
+
+func (s *SQLiteBanStore) Close() error {
+	return s.db.Close()
+}
+
+// The zero time is stored as 0, e.g. the ban that never expires.
+func toUnixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func fromUnixMilli(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
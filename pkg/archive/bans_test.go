@@ -0,0 +1,59 @@
+package archive
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeld"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLiteBanStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bans.db")
+	s, err := OpenSQLiteBanStore(path)
+	assert.NoError(t, err)
+
+	expiresAt := time.UnixMilli(time.Now().Add(time.Hour).UnixMilli())
+	assert.NoError(t, s.SaveBan(&channeld.BanRecord{Type: channeld.BanType_UserId, Value: "u1", Reason: "cheating", IssuedBy: "alice", CreatedAt: time.Now(), ExpiresAt: expiresAt}))
+	assert.NoError(t, s.SaveBan(&channeld.BanRecord{Type: channeld.BanType_IP, Value: "1.2.3.4", CreatedAt: time.Now()}))
+	// Replaces the ban of the same type and value
+	assert.NoError(t, s.SaveBan(&channeld.BanRecord{Type: channeld.BanType_IP, Value: "1.2.3.4", Reason: "spam", CreatedAt: time.Now()}))
+	assert.NoError(t, s.AppendBanAudit(&channeld.BanAuditEntry{Time: time.Now(), Action: channeld.BanAction_Ban, Ban: channeld.BanRecord{Type: channeld.BanType_UserId, Value: "u1"}, Operator: "alice"}))
+	assert.NoError(t, s.AppendBanAudit(&channeld.BanAuditEntry{Time: time.Now(), Action: channeld.BanAction_Ban, Ban: channeld.BanRecord{Type: channeld.BanType_IP, Value: "1.2.3.4"}}))
+	assert.NoError(t, s.Close())
+
+	// The bans survive the reopening
+	s, err = OpenSQLiteBanStore(path)
+	assert.NoError(t, err)
+	defer s.Close()
+	bans, err := s.LoadBans()
+	assert.NoError(t, err)
+	assert.Len(t, bans, 2)
+	for _, ban := range bans {
+		if ban.Type == channeld.BanType_UserId {
+			assert.Equal(t, "u1", ban.Value)
+			assert.Equal(t, "alice", ban.IssuedBy)
+			assert.True(t, expiresAt.Equal(ban.ExpiresAt))
+		} else {
+			assert.Equal(t, "spam", ban.Reason)
+			// Never expires
+			assert.True(t, ban.ExpiresAt.IsZero())
+		}
+	}
+
+	assert.NoError(t, s.DeleteBan(channeld.BanType_UserId, "u1"))
+	bans, err = s.LoadBans()
+	assert.NoError(t, err)
+	assert.Len(t, bans, 1)
+
+	audit, err := s.Audit("", "")
+	assert.NoError(t, err)
+	assert.Len(t, audit, 2)
+	audit, err = s.Audit(channeld.BanType_UserId, "u1")
+	assert.NoError(t, err)
+	if assert.Len(t, audit, 1) {
+		assert.Equal(t, channeld.BanAction_Ban, audit[0].Action)
+		assert.Equal(t, "alice", audit[0].Operator)
+	}
+}
@@ -0,0 +1,272 @@
+// Package archive implements channeld.MessageArchive with SQLite, so the broadcasts of the chat-like channels
+// survive the restarts of channeld and can be fetched page by page. It also implements channeld.BanStore. See bans.go.
+//
+// The messages are written in batches by a single goroutine, so Archive() never blocks the channel's goroutine.
+// Query() waits for the pending messages to be written first, so the archived messages are always visible to it.
+package archive
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/metaworking/channeld/pkg/channeld"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+)
+
+type SQLiteConfig struct {
+	// The path of the database file. ":memory:" for a temporary database.
+	Path string
+	// How many messages can be pending to be written. The exceeded are dropped.
+	QueueSize int
+	// The max number of the messages written in one transaction.
+	BatchSize int
+}
+
+var DefaultSQLiteConfig = SQLiteConfig{
+	Path:      "channeld_archive.db",
+	QueueSize: 4096,
+	BatchSize: 256,
+}
+
+var ErrArchiveClosed = errors.New("the archive is closed")
+
+const schema = `
+CREATE TABLE IF NOT EXISTS messages (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	tenant TEXT NOT NULL,
+	channel_id INTEGER NOT NULL,
+	channel_key TEXT NOT NULL,
+	seq INTEGER NOT NULL,
+	sender_conn_id INTEGER NOT NULL,
+	sender_user_id TEXT NOT NULL,
+	timestamp INTEGER NOT NULL,
+	msg_type INTEGER NOT NULL,
+	broadcast INTEGER NOT NULL,
+	tag_filter TEXT NOT NULL,
+	client_conn_id INTEGER NOT NULL,
+	raw_payload INTEGER NOT NULL,
+	payload BLOB
+);
+CREATE INDEX IF NOT EXISTS messages_channel_id ON messages (tenant, channel_id, id);
+CREATE INDEX IF NOT EXISTS messages_channel_key ON messages (tenant, channel_key, id);
+`
+
+const columns = "id, tenant, channel_id, channel_key, seq, sender_conn_id, sender_user_id, timestamp, msg_type, broadcast, tag_filter, client_conn_id, raw_payload, payload"
+
+type writeOp struct {
+	msg *channeldpb.ArchivedMessage
+	// Closed when the messages queued before are written
+	flushed chan struct{}
+}
+
+type SQLiteArchive struct {
+	config SQLiteConfig
+	db     *sql.DB
+	queue  chan writeOp
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// Opens the database and creates the table if it doesn't exist, then starts the writing goroutine.
+func OpenSQLite(config SQLiteConfig) (*SQLiteArchive, error) {
+	dsn := config.Path
+	if dsn != ":memory:" {
+		dsn = "file:" + dsn + "?_journal_mode=WAL&_busy_timeout=5000"
+	}
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if config.Path == ":memory:" {
+		// Every connection has its own in-memory database
+		db.SetMaxOpenConns(1)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultSQLiteConfig.BatchSize
+	}
+	a := &SQLiteArchive{
+		config: config,
+		db:     db,
+		queue:  make(chan writeOp, config.QueueSize),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go a.run()
+	return a, nil
+}
+
+// The id of the message is assigned when it's written.
+func (a *SQLiteArchive) Archive(msg *channeldpb.ArchivedMessage) {
+	select {
+	case a.queue <- writeOp{msg: msg}:
+	default:
+		channeld.RootLogger().Warn("the archive queue is full, the message is dropped",
+			zap.Uint32("channelId", msg.ChannelId),
+			zap.Uint64("seq", msg.Seq),
+		)
+	}
+}
+
+func (a *SQLiteArchive) Query(query channeld.ArchiveQuery) ([]*channeldpb.ArchivedMessage, bool, error) {
+	if err := a.flush(); err != nil {
+		return nil, false, err
+	}
+
+	where := []string{"tenant = ?", "id > ?", "seq > ?", "timestamp >= ?"}
+	args := []interface{}{query.Tenant, int64(query.AfterId), int64(query.AfterSeq), query.FromTime}
+	if query.ChannelKey != "" {
+		where = append(where, "channel_key = ?")
+		args = append(args, query.ChannelKey)
+	} else {
+		where = append(where, "channel_id = ?")
+		args = append(args, uint32(query.ChannelId))
+	}
+	if query.ToTime > 0 {
+		where = append(where, "timestamp < ?")
+		args = append(args, query.ToTime)
+	}
+	stmt := "SELECT " + columns + " FROM messages WHERE " + strings.Join(where, " AND ") + " ORDER BY id"
+	if query.Limit > 0 {
+		// Queries one more to tell if there are more
+		stmt += " LIMIT ?"
+		args = append(args, query.Limit+1)
+	}
+
+	rows, err := a.db.Query(stmt, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	var result []*channeldpb.ArchivedMessage
+	for rows.Next() {
+		msg := &channeldpb.ArchivedMessage{}
+		var id, seq int64
+		if err := rows.Scan(&id, &msg.Tenant, &msg.ChannelId, &msg.ChannelKey, &seq, &msg.SenderConnId, &msg.SenderUserId,
+			&msg.Timestamp, &msg.MsgType, &msg.Broadcast, &msg.TagFilter, &msg.ClientConnId, &msg.RawPayload, &msg.Payload); err != nil {
+			return nil, false, err
+		}
+		msg.Id, msg.Seq = uint64(id), uint64(seq)
+		result = append(result, msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	if query.Limit > 0 && len(result) > query.Limit {
+		return result[:query.Limit], true, nil
+	}
+	return result, false, nil
+}
+
+// Writes the pending messages and closes the database.
+func (a *SQLiteArchive) Close() error {
+	select {
+	case <-a.stop:
+		return ErrArchiveClosed
+	default:
+	}
+	close(a.stop)
+	<-a.done
+	return a.db.Close()
+}
+
+// Waits until the messages queued before are written.
+func (a *SQLiteArchive) flush() error {
+	flushed := make(chan struct{})
+	select {
+	case a.queue <- writeOp{flushed: flushed}:
+	case <-a.done:
+		return ErrArchiveClosed
+	}
+	select {
+	case <-flushed:
+		return nil
+	case <-a.done:
+		return ErrArchiveClosed
+	}
+}
+
+func (a *SQLiteArchive) run() {
+	defer close(a.done)
+
+	batch := make([]*channeldpb.ArchivedMessage, 0, a.config.BatchSize)
+	var flushes []chan struct{}
+	add := func(op writeOp) {
+		if op.msg != nil {
+			batch = append(batch, op.msg)
+		}
+		if op.flushed != nil {
+			flushes = append(flushes, op.flushed)
+		}
+	}
+
+	for {
+		stopped := false
+		select {
+		case op := <-a.queue:
+			add(op)
+		case <-a.stop:
+			stopped = true
+		}
+
+		// Takes the queued messages as many as possible. When stopped, takes all of them.
+		for more := true; more && (stopped || len(batch) < a.config.BatchSize); {
+			select {
+			case op := <-a.queue:
+				add(op)
+			default:
+				more = false
+			}
+		}
+
+		if len(batch) > 0 {
+			if err := a.write(batch); err != nil {
+				channeld.RootLogger().Error("failed to write the archived messages", zap.Int("num", len(batch)), zap.Error(err))
+			}
+			batch = batch[:0]
+		}
+		for _, flushed := range flushes {
+			close(flushed)
+		}
+		flushes = flushes[:0]
+
+		if stopped {
+			return
+		}
+	}
+}
+
+func (a *SQLiteArchive) write(batch []*channeldpb.ArchivedMessage) error {
+	tx, err := a.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("INSERT INTO messages (" + columns[len("id, "):] + ") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for _, msg := range batch {
+		result, err := stmt.Exec(msg.Tenant, msg.ChannelId, msg.ChannelKey, int64(msg.Seq), msg.SenderConnId, msg.SenderUserId,
+			msg.Timestamp, msg.MsgType, msg.Broadcast, msg.TagFilter, msg.ClientConnId, msg.RawPayload, msg.Payload)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if id, err := result.LastInsertId(); err == nil {
+			msg.Id = uint64(id)
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,77 @@
+package archive
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/metaworking/channeld/pkg/channeld"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLiteArchive(t *testing.T) {
+	channeld.InitLogs()
+
+	config := DefaultSQLiteConfig
+	config.Path = filepath.Join(t.TempDir(), "archive.db")
+	config.BatchSize = 2
+	a, err := OpenSQLite(config)
+	assert.NoError(t, err)
+
+	for i := 1; i <= 5; i++ {
+		a.Archive(&channeldpb.ArchivedMessage{
+			ChannelId:    100,
+			ChannelKey:   "lobby",
+			Seq:          uint64(i),
+			SenderConnId: 1,
+			SenderUserId: "user1",
+			Timestamp:    int64(i * 1000),
+			MsgType:      uint32(channeldpb.MessageType_USER_SPACE_START),
+			Payload:      []byte{byte(i)},
+			Broadcast:    uint32(channeldpb.BroadcastType_ALL),
+		})
+	}
+	a.Archive(&channeldpb.ArchivedMessage{ChannelId: 101, Seq: 1, Timestamp: 1000})
+	a.Archive(&channeldpb.ArchivedMessage{ChannelId: 100, Seq: 1, Timestamp: 1000, Tenant: "t1"})
+
+	// The pending messages are written before querying
+	msgs, hasMore, err := a.Query(channeld.ArchiveQuery{ChannelId: 100, Limit: 3})
+	assert.NoError(t, err)
+	assert.True(t, hasMore)
+	assert.Len(t, msgs, 3)
+	assert.EqualValues(t, 1, msgs[0].Id)
+	assert.Equal(t, "user1", msgs[0].SenderUserId)
+	assert.Equal(t, []byte{1}, msgs[0].Payload)
+
+	msgs, hasMore, err = a.Query(channeld.ArchiveQuery{ChannelId: 100, AfterId: msgs[2].Id, Limit: 3})
+	assert.NoError(t, err)
+	assert.False(t, hasMore)
+	assert.Len(t, msgs, 2)
+
+	msgs, _, err = a.Query(channeld.ArchiveQuery{ChannelKey: "lobby", FromTime: 2000, ToTime: 4000})
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 2)
+	assert.EqualValues(t, 2, msgs[0].Seq)
+
+	msgs, _, err = a.Query(channeld.ArchiveQuery{ChannelId: 100, AfterSeq: 4})
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 1)
+	assert.EqualValues(t, 5, msgs[0].Seq)
+
+	msgs, _, err = a.Query(channeld.ArchiveQuery{ChannelId: 100, Tenant: "t1"})
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 1)
+
+	// The messages survive reopening
+	a.Archive(&channeldpb.ArchivedMessage{ChannelId: 100, Seq: 6, Timestamp: 6000})
+	assert.NoError(t, a.Close())
+	_, _, err = a.Query(channeld.ArchiveQuery{ChannelId: 100})
+	assert.ErrorIs(t, err, ErrArchiveClosed)
+
+	a, err = OpenSQLite(config)
+	assert.NoError(t, err)
+	defer a.Close()
+	msgs, _, err = a.Query(channeld.ArchiveQuery{ChannelId: 100})
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 6)
+}
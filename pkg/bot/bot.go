@@ -0,0 +1,193 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/client"
+	"google.golang.org/protobuf/proto"
+)
+
+// A headless client that runs the steps of a scenario in order, for the end-to-end testing of a channeld deployment.
+type Bot struct {
+	Index    int
+	Client   *client.ChanneldClient
+	scenario *Scenario
+	// The channel data received by the bot. The updates are merged via proto.Merge.
+	channelData map[uint32]proto.Message
+	receiveErr  chan error
+}
+
+// Returns the channel data the bot has received in the channel, or nil if no update has been received.
+func (bot *Bot) ChannelData(channelId uint32) proto.Message {
+	return bot.channelData[channelId]
+}
+
+// Handles the received messages and sends the queued messages, then sleeps for the tick interval of the scenario.
+func (bot *Bot) Tick() error {
+	select {
+	case err := <-bot.receiveErr:
+		return fmt.Errorf("bot %d is disconnected: %w", bot.Index, err)
+	default:
+	}
+
+	if err := bot.Client.Tick(); err != nil {
+		return err
+	}
+	time.Sleep(time.Duration(bot.scenario.Config.TickInterval))
+	return nil
+}
+
+// Ticks the bot until the condition is met. Returns error if the condition is not met within the step timeout of the scenario.
+func (bot *Bot) TickUntil(cond func() bool) error {
+	deadline := time.Now().Add(time.Duration(bot.scenario.Config.StepTimeout))
+	for !cond() {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s", time.Duration(bot.scenario.Config.StepTimeout))
+		}
+		if err := bot.Tick(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (bot *Bot) handleChannelDataUpdate(_ *client.ChanneldClient, channelId uint32, msg *channeldpb.ChannelDataUpdateMessage) {
+	if msg.Data == nil {
+		return
+	}
+	data, err := msg.Data.UnmarshalNew()
+	if err != nil {
+		return
+	}
+	if existing, exists := bot.channelData[channelId]; exists && existing.ProtoReflect().Descriptor() == data.ProtoReflect().Descriptor() {
+		proto.Merge(existing, data)
+	} else {
+		bot.channelData[channelId] = data
+	}
+}
+
+func (bot *Bot) connect() error {
+	c, err := client.NewClient(bot.scenario.Config.ChanneldAddr)
+	if err != nil {
+		return err
+	}
+	bot.Client = c
+	go func() {
+		for {
+			if err := c.Receive(); err != nil {
+				bot.receiveErr <- err
+				return
+			}
+		}
+	}()
+
+	c.OnUpdate(bot.handleChannelDataUpdate)
+	var authResult *channeldpb.AuthResultMessage
+	c.OnAuth(func(_ *client.ChanneldClient, result *channeldpb.AuthResultMessage) {
+		authResult = result
+	})
+	c.Auth(bot.scenario.Config.LoginToken, fmt.Sprintf("%s%d", bot.scenario.Config.PlayerIdentifierToken, bot.Index))
+	if err := bot.TickUntil(func() bool { return authResult != nil }); err != nil {
+		return err
+	}
+	if authResult.Result != channeldpb.AuthResultMessage_SUCCESSFUL {
+		return fmt.Errorf("auth result: %s", authResult.Result)
+	}
+	return nil
+}
+
+func (bot *Bot) run() *BotResult {
+	result := &BotResult{Index: bot.Index}
+	startTime := time.Now()
+	defer func() {
+		result.Duration = time.Since(startTime)
+		if bot.Client != nil {
+			bot.Client.Disconnect()
+		}
+	}()
+
+	if err := bot.connect(); err != nil {
+		result.FailedStep = "connect"
+		result.Err = err
+		return result
+	}
+
+	for _, step := range bot.scenario.Steps {
+		if err := step.Run(bot); err != nil {
+			result.FailedStep = step.Name()
+			result.Err = err
+			return result
+		}
+	}
+	// Send the remaining messages
+	bot.Client.Tick()
+	result.Passed = true
+	return result
+}
+
+type BotResult struct {
+	Index    int
+	Passed   bool
+	Duration time.Duration
+	// The name of the step that failed, or "connect" if the bot failed to connect or authenticate.
+	FailedStep string
+	Err        error
+}
+
+type Report struct {
+	Results  []*BotResult
+	Duration time.Duration
+}
+
+// Returns true if all the bots have run through the scenario.
+func (r *Report) Passed() bool {
+	for _, result := range r.Results {
+		if !result.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Report) String() string {
+	var sb strings.Builder
+	passedNum := 0
+	for _, result := range r.Results {
+		if result.Passed {
+			passedNum++
+			fmt.Fprintf(&sb, "bot %d: PASS (%s)\n", result.Index, result.Duration)
+		} else {
+			fmt.Fprintf(&sb, "bot %d: FAIL at step '%s': %s\n", result.Index, result.FailedStep, result.Err)
+		}
+	}
+	fmt.Fprintf(&sb, "%d/%d bots passed in %s", passedNum, len(r.Results), r.Duration)
+	return sb.String()
+}
+
+// Runs the scenario with ScenarioConfig.BotNumber bots concurrently, and returns the report after all the bots finish.
+func (s *Scenario) Run() *Report {
+	report := &Report{Results: make([]*BotResult, s.Config.BotNumber)}
+	startTime := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < s.Config.BotNumber; i++ {
+		bot := &Bot{
+			Index:       i,
+			scenario:    s,
+			channelData: make(map[uint32]proto.Message),
+			receiveErr:  make(chan error, 1),
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			report.Results[i] = bot.run()
+		}(i)
+		time.Sleep(time.Duration(s.Config.ConnectInterval))
+	}
+	wg.Wait()
+	report.Duration = time.Since(startTime)
+	return report
+}
@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeld"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+var startServerOnce sync.Once
+
+func startTestServer(addr string) {
+	startServerOnce.Do(func() {
+		doStartTestServer(addr)
+	})
+}
+
+func doStartTestServer(addr string) {
+	channeld.InitLogs()
+	channeld.InitChannels()
+	// The clients need to sub to the channel
+	channeld.InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_authoratative_fsm.json")
+	channeld.GlobalSettings.Development = true
+
+	channeld.GetChannel(channeld.GlobalChannelId).Execute(func(ch *channeld.Channel) {
+		ch.InitData(&testpb.TestChannelDataMessage{}, nil)
+	})
+
+	go channeld.StartListening(channeldpb.ConnectionType_CLIENT, "tcp", addr)
+	time.Sleep(100 * time.Millisecond)
+}
+
+func TestRunScenario(t *testing.T) {
+	startTestServer(":32118")
+
+	s := NewScenario("127.0.0.1:32118",
+		Subscribe(uint32(channeld.GlobalChannelId), &channeldpb.ChannelSubscriptionOptions{
+			DataAccess:       channeld.Pointer(channeldpb.ChannelDataAccess_WRITE_ACCESS),
+			FanOutIntervalMs: proto.Uint32(20),
+		}),
+		UpdateChannelData(uint32(channeld.GlobalChannelId), &testpb.TestChannelDataMessage{Text: "hello"}, 3, 20*time.Millisecond),
+		ExpectChannelData(uint32(channeld.GlobalChannelId), &testpb.TestChannelDataMessage{Text: "hello"}),
+	)
+	s.Config.BotNumber = 2
+	s.Config.TickInterval = Duration(10 * time.Millisecond)
+	report := s.Run()
+	assert.True(t, report.Passed(), report.String())
+	assert.Equal(t, 2, len(report.Results))
+
+	// The expected data never arrives
+	s.Steps = append(s.Steps, ExpectChannelData(uint32(channeld.GlobalChannelId), &testpb.TestChannelDataMessage{Num: 1}))
+	s.Config.BotNumber = 1
+	s.Config.StepTimeout = Duration(200 * time.Millisecond)
+	report = s.Run()
+	assert.False(t, report.Passed())
+	assert.Equal(t, "expect data in 0", report.Results[0].FailedStep)
+	assert.Error(t, report.Results[0].Err)
+}
+
+func TestConnectFailure(t *testing.T) {
+	report := NewScenario("127.0.0.1:1").Run()
+	assert.False(t, report.Passed())
+	assert.Equal(t, "connect", report.Results[0].FailedStep)
+}
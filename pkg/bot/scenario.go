@@ -0,0 +1,160 @@
+package bot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	switch value := v.(type) {
+	case float64:
+		*d = Duration(time.Duration(value))
+		return nil
+	case string:
+		tmp, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		*d = Duration(tmp)
+		return nil
+	default:
+		return errors.New("invalid duration")
+	}
+}
+
+type ScenarioConfig struct {
+	ChanneldAddr    string   `json:"channeldAddr"`
+	BotNumber       int      `json:"botNumber"`
+	ConnectInterval Duration `json:"connectInterval"` // interval between starting the bots
+	TickInterval    Duration `json:"tickInterval"`
+	StepTimeout     Duration `json:"stepTimeout"` // max time to wait for the response or the expected channel data
+	LoginToken      string   `json:"loginToken"`
+	// The bot index is appended to make a unique token for each bot.
+	PlayerIdentifierToken string       `json:"playerIdentifierToken"`
+	Steps                 []StepConfig `json:"steps"`
+}
+
+var DefaultScenarioConfig = ScenarioConfig{
+	ChanneldAddr:          "localhost:12108",
+	BotNumber:             1,
+	TickInterval:          Duration(50 * time.Millisecond),
+	StepTimeout:           Duration(5 * time.Second),
+	LoginToken:            "bot",
+	PlayerIdentifierToken: "bot",
+}
+
+// The JSON form of a Step. The messages are in the JSON form of google.protobuf.Any, e.g.
+// {"@type": "type.googleapis.com/chatpb.ChatChannelData", "chatMessages": [...]}.
+// The message types should be registered in protoregistry.GlobalTypes, which is done by importing the generated Go package.
+type StepConfig struct {
+	// One of "sub", "unsub", "update", "send", "wait", and "expectData".
+	Action     string          `json:"action"`
+	ChannelId  uint32          `json:"channelId"`
+	SubOptions json.RawMessage `json:"subOptions"` // sub: the JSON form of ChannelSubscriptionOptions
+	Data       json.RawMessage `json:"data"`       // update, expectData: the channel data; send: the message
+	MsgType    uint32          `json:"msgType"`    // send
+	Broadcast  uint32          `json:"broadcast"`  // send: see channeldpb.BroadcastType
+	Count      int             `json:"count"`      // update, send: the number of times to send. Default is 1.
+	Interval   Duration        `json:"interval"`   // update, send: the interval between the sends
+	Duration   Duration        `json:"duration"`   // wait
+}
+
+func unmarshalAnyJson(data json.RawMessage) (proto.Message, error) {
+	if len(data) == 0 {
+		return nil, errors.New("data is not set")
+	}
+	any := &anypb.Any{}
+	if err := protojson.Unmarshal(data, any); err != nil {
+		return nil, err
+	}
+	return any.UnmarshalNew()
+}
+
+func (sc *StepConfig) toStep() (Step, error) {
+	switch sc.Action {
+	case "sub":
+		var subOptions *channeldpb.ChannelSubscriptionOptions
+		if len(sc.SubOptions) > 0 {
+			subOptions = &channeldpb.ChannelSubscriptionOptions{}
+			if err := protojson.Unmarshal(sc.SubOptions, subOptions); err != nil {
+				return nil, fmt.Errorf("invalid subOptions: %w", err)
+			}
+		}
+		return Subscribe(sc.ChannelId, subOptions), nil
+	case "unsub":
+		return Unsubscribe(sc.ChannelId), nil
+	case "update":
+		data, err := unmarshalAnyJson(sc.Data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid data: %w", err)
+		}
+		return UpdateChannelData(sc.ChannelId, data, sc.Count, time.Duration(sc.Interval)), nil
+	case "send":
+		msg, err := unmarshalAnyJson(sc.Data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid data: %w", err)
+		}
+		return SendMessage(sc.ChannelId, channeldpb.BroadcastType(sc.Broadcast), sc.MsgType, msg, sc.Count, time.Duration(sc.Interval)), nil
+	case "wait":
+		return Wait(time.Duration(sc.Duration)), nil
+	case "expectData":
+		expected, err := unmarshalAnyJson(sc.Data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid data: %w", err)
+		}
+		return ExpectChannelData(sc.ChannelId, expected), nil
+	}
+	return nil, fmt.Errorf("unknown action: %s", sc.Action)
+}
+
+type Scenario struct {
+	Config ScenarioConfig
+	Steps  []Step
+}
+
+// Creates the scenario with the default config. The config can be modified before running the scenario.
+func NewScenario(channeldAddr string, steps ...Step) *Scenario {
+	config := DefaultScenarioConfig
+	config.ChanneldAddr = channeldAddr
+	return &Scenario{Config: config, Steps: steps}
+}
+
+// Loads the scenario from the JSON config file. The unset fields use the values of DefaultScenarioConfig.
+func LoadScenario(path string) (*Scenario, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseScenario(bytes)
+}
+
+func ParseScenario(bytes []byte) (*Scenario, error) {
+	config := DefaultScenarioConfig
+	if err := json.Unmarshal(bytes, &config); err != nil {
+		return nil, err
+	}
+
+	s := &Scenario{Config: config, Steps: make([]Step, len(config.Steps))}
+	for i := range config.Steps {
+		step, err := config.Steps[i].toStep()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse step %d: %w", i, err)
+		}
+		s.Steps[i] = step
+	}
+	return s, nil
+}
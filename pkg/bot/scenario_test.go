@@ -0,0 +1,65 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScenario(t *testing.T) {
+	s, err := ParseScenario([]byte(`{
+		"channeldAddr": "ws://localhost:12108",
+		"botNumber": 10,
+		"stepTimeout": "2s",
+		"steps": [
+			{"action": "sub", "channelId": 1, "subOptions": {"dataAccess": "WRITE_ACCESS", "fanOutIntervalMs": 50}},
+			{"action": "update", "channelId": 1, "data": {"@type": "type.googleapis.com/testpb.TestChannelDataMessage", "text": "a"}, "count": 5, "interval": "100ms"},
+			{"action": "wait", "duration": "1s"},
+			{"action": "expectData", "channelId": 1, "data": {"@type": "type.googleapis.com/testpb.TestChannelDataMessage", "num": 1}},
+			{"action": "unsub", "channelId": 1}
+		]
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "ws://localhost:12108", s.Config.ChanneldAddr)
+	assert.Equal(t, 10, s.Config.BotNumber)
+	assert.Equal(t, Duration(2*time.Second), s.Config.StepTimeout)
+	// Default values
+	assert.Equal(t, DefaultScenarioConfig.TickInterval, s.Config.TickInterval)
+	assert.Equal(t, DefaultScenarioConfig.LoginToken, s.Config.LoginToken)
+	if assert.Equal(t, 5, len(s.Steps)) {
+		assert.Equal(t, "sub 1", s.Steps[0].Name())
+		assert.Equal(t, "update 1", s.Steps[1].Name())
+		assert.Equal(t, "wait 1s", s.Steps[2].Name())
+		assert.Equal(t, "expect data in 1", s.Steps[3].Name())
+		assert.Equal(t, "unsub 1", s.Steps[4].Name())
+	}
+
+	_, err = ParseScenario([]byte(`{"steps": [{"action": "jump"}]}`))
+	assert.Error(t, err)
+
+	// Unregistered type
+	_, err = ParseScenario([]byte(`{"steps": [{"action": "update", "data": {"@type": "type.googleapis.com/foo.Bar"}}]}`))
+	assert.Error(t, err)
+}
+
+func TestContainsFields(t *testing.T) {
+	actual := &testpb.TestMapMessage{
+		Kv: map[uint32]string{1: "a", 2: "b"},
+	}
+	assert.True(t, containsFields(actual.ProtoReflect(), (&testpb.TestMapMessage{}).ProtoReflect()))
+	assert.True(t, containsFields(actual.ProtoReflect(), (&testpb.TestMapMessage{Kv: map[uint32]string{1: "a"}}).ProtoReflect()))
+	assert.False(t, containsFields(actual.ProtoReflect(), (&testpb.TestMapMessage{Kv: map[uint32]string{1: "b"}}).ProtoReflect()))
+	assert.False(t, containsFields(actual.ProtoReflect(), (&testpb.TestMapMessage{Kv: map[uint32]string{3: "c"}}).ProtoReflect()))
+
+	list := &testpb.TestMergeMessage{List: []string{"a", "b"}}
+	assert.True(t, containsFields(list.ProtoReflect(), (&testpb.TestMergeMessage{List: []string{"a", "b"}}).ProtoReflect()))
+	assert.False(t, containsFields(list.ProtoReflect(), (&testpb.TestMergeMessage{List: []string{"a"}}).ProtoReflect()))
+
+	data := &testpb.TestChannelDataMessage{Text: "a", Num: 1}
+	assert.True(t, containsFields(data.ProtoReflect(), (&testpb.TestChannelDataMessage{Num: 1}).ProtoReflect()))
+	assert.False(t, containsFields(data.ProtoReflect(), (&testpb.TestChannelDataMessage{Text: "b"}).ProtoReflect()))
+	// Different types
+	assert.False(t, containsFields(data.ProtoReflect(), list.ProtoReflect()))
+}
@@ -0,0 +1,184 @@
+package bot
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/client"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// A step of the scenario. Returning an error fails the bot, and the remaining steps won't be run.
+type Step interface {
+	Name() string
+	Run(bot *Bot) error
+}
+
+type funcStep struct {
+	name string
+	run  func(bot *Bot) error
+}
+
+func (s *funcStep) Name() string {
+	return s.name
+}
+
+func (s *funcStep) Run(bot *Bot) error {
+	return s.run(bot)
+}
+
+// Creates a custom step.
+func NewStep(name string, run func(bot *Bot) error) Step {
+	return &funcStep{name, run}
+}
+
+// Subscribes the bot to the channel, and waits for the result.
+func Subscribe(channelId uint32, subOptions *channeldpb.ChannelSubscriptionOptions) Step {
+	return NewStep(fmt.Sprintf("sub %d", channelId), func(bot *Bot) error {
+		subscribed := false
+		err := bot.Client.Subscribe(channelId, subOptions, func(_ *client.ChanneldClient, _ uint32, _ *channeldpb.SubscribedToChannelResultMessage) {
+			subscribed = true
+		})
+		if err != nil {
+			return err
+		}
+		return bot.TickUntil(func() bool { return subscribed })
+	})
+}
+
+// Unsubscribes the bot from the channel, and waits for the result.
+func Unsubscribe(channelId uint32) Step {
+	return NewStep(fmt.Sprintf("unsub %d", channelId), func(bot *Bot) error {
+		unsubscribed := false
+		err := bot.Client.Unsubscribe(channelId, func(_ *client.ChanneldClient, _ uint32, _ *channeldpb.UnsubscribedFromChannelResultMessage) {
+			unsubscribed = true
+		})
+		if err != nil {
+			return err
+		}
+		return bot.TickUntil(func() bool { return unsubscribed })
+	})
+}
+
+// Sends the message count times with the interval in between. The bot keeps ticking during the interval.
+func SendMessage(channelId uint32, broadcast channeldpb.BroadcastType, msgType uint32, msg proto.Message, count int, interval time.Duration) Step {
+	return NewStep(fmt.Sprintf("send %d to %d", msgType, channelId), func(bot *Bot) error {
+		return sendRepeatedly(bot, count, interval, func() error {
+			return bot.Client.Send(channelId, broadcast, msgType, msg, nil)
+		})
+	})
+}
+
+// Sends the channel data update count times with the interval in between. The bot should have the write access to the channel.
+func UpdateChannelData(channelId uint32, data proto.Message, count int, interval time.Duration) Step {
+	return NewStep(fmt.Sprintf("update %d", channelId), func(bot *Bot) error {
+		return sendRepeatedly(bot, count, interval, func() error {
+			return bot.Client.UpdateChannelData(channelId, data)
+		})
+	})
+}
+
+func sendRepeatedly(bot *Bot, count int, interval time.Duration, send func() error) error {
+	if count <= 0 {
+		count = 1
+	}
+	for i := 0; i < count; i++ {
+		if err := send(); err != nil {
+			return err
+		}
+		sendTime := time.Now()
+		// Tick at least once to send the message
+		if err := bot.Tick(); err != nil {
+			return err
+		}
+		if i < count-1 {
+			for time.Since(sendTime) < interval {
+				if err := bot.Tick(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Keeps ticking the bot for the duration.
+func Wait(duration time.Duration) Step {
+	return NewStep(fmt.Sprintf("wait %s", duration), func(bot *Bot) error {
+		startTime := time.Now()
+		for time.Since(startTime) < duration {
+			if err := bot.Tick(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Waits until the channel data received by the bot contains the expected data, i.e. each field set in the expected data
+// has the same value in the received data. The lists should be equal, and the maps should contain the expected entries.
+func ExpectChannelData(channelId uint32, expected proto.Message) Step {
+	return ExpectChannelDataFunc(fmt.Sprintf("expect data in %d", channelId), channelId, func(data proto.Message) bool {
+		return containsFields(data.ProtoReflect(), expected.ProtoReflect())
+	})
+}
+
+// Waits until the channel data received by the bot matches the predicate.
+func ExpectChannelDataFunc(name string, channelId uint32, predicate func(data proto.Message) bool) Step {
+	return NewStep(name, func(bot *Bot) error {
+		var lastData proto.Message
+		err := bot.TickUntil(func() bool {
+			lastData = bot.ChannelData(channelId)
+			return lastData != nil && predicate(lastData)
+		})
+		if err != nil {
+			return fmt.Errorf("received channel data: {%v}, %w", lastData, err)
+		}
+		return nil
+	})
+}
+
+func containsFields(actual protoreflect.Message, expected protoreflect.Message) bool {
+	if actual.Descriptor() != expected.Descriptor() {
+		return false
+	}
+
+	contains := true
+	expected.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if !actual.Has(fd) {
+			contains = false
+		} else if fd.IsList() {
+			contains = proto.Equal(listOwner(actual, fd), listOwner(expected, fd))
+		} else if fd.IsMap() {
+			actualMap := actual.Get(fd).Map()
+			v.Map().Range(func(key protoreflect.MapKey, value protoreflect.Value) bool {
+				contains = actualMap.Has(key) && valueContains(fd.MapValue(), actualMap.Get(key), value)
+				return contains
+			})
+		} else {
+			contains = valueContains(fd, actual.Get(fd), v)
+		}
+		return contains
+	})
+	return contains
+}
+
+func valueContains(fd protoreflect.FieldDescriptor, actual protoreflect.Value, expected protoreflect.Value) bool {
+	if fd.Message() != nil {
+		return containsFields(actual.Message(), expected.Message())
+	}
+	if fd.Kind() == protoreflect.BytesKind {
+		return bytes.Equal(actual.Bytes(), expected.Bytes())
+	}
+	return actual.Interface() == expected.Interface()
+}
+
+// Returns a message that only has the list field of the message, so the lists can be compared via proto.Equal.
+func listOwner(m protoreflect.Message, fd protoreflect.FieldDescriptor) proto.Message {
+	owner := m.New()
+	owner.Set(fd, m.Get(fd))
+	return owner.Interface()
+}
@@ -0,0 +1,221 @@
+// Package bridge mirrors a channel on one channeld deployment into a channel on another, e.g. a global chat channel
+// shared across the regional clusters. The bridge connects to both deployments via the Go client, subscribes to both channels,
+// and forwards the ChannelDataUpdateMessages between them. The channel data is forwarded in the Any form, so the bridge
+// doesn't need to know the channel data type.
+//
+// Loop prevention: the bridge subscribes with SkipSelfUpdateFanOut, so the updates it writes to a channel are not fanned out
+// back to it, and won't be forwarded again.
+//
+// Merging: the forwarded updates are merged by each deployment in the same way as the updates from any other connection,
+// with the channel's merge options. In the two-way mode, only the source channel's full states are sent to the target
+// on the first fan-out (the target subscription skips the first fan-out), so the lists are not duplicated on either side.
+// If the same field is updated on both sides within a fan-out interval, the two sides may settle on different values;
+// use the one-way mode if the target must always be identical to the source.
+package bridge
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/client"
+	"google.golang.org/protobuf/proto"
+)
+
+type EndpointConfig struct {
+	// The address of the channeld deployment. Should be the trusted (server) address, as the bridge needs to write the channel data.
+	Addr                  string
+	ChannelId             uint32
+	LoginToken            string
+	PlayerIdentifierToken string
+}
+
+type ChannelBridgeConfig struct {
+	Source EndpointConfig
+	Target EndpointConfig
+	// If false, only the updates of the source channel are forwarded to the target channel.
+	TwoWay           bool
+	FanOutIntervalMs uint32
+	TickInterval     time.Duration
+	// Max time to wait for the auth and the subscription results.
+	Timeout time.Duration
+}
+
+var DefaultChannelBridgeConfig = ChannelBridgeConfig{
+	FanOutIntervalMs: 50,
+	TickInterval:     10 * time.Millisecond,
+	Timeout:          5 * time.Second,
+}
+
+type ChannelBridge struct {
+	config  ChannelBridgeConfig
+	source  *bridgeEndpoint
+	target  *bridgeEndpoint
+	stopped int32
+}
+
+type bridgeEndpoint struct {
+	config     *EndpointConfig
+	client     *client.ChanneldClient
+	receiveErr chan error
+	// The number of the updates forwarded from this endpoint to the other.
+	forwardedNum int64
+}
+
+func NewChannelBridge(config ChannelBridgeConfig) *ChannelBridge {
+	b := &ChannelBridge{config: config}
+	b.source = &bridgeEndpoint{config: &b.config.Source, receiveErr: make(chan error, 1)}
+	b.target = &bridgeEndpoint{config: &b.config.Target, receiveErr: make(chan error, 1)}
+	return b
+}
+
+// Returns the number of the updates forwarded from the source to the target, and from the target to the source.
+func (b *ChannelBridge) ForwardedNum() (sourceToTarget int64, targetToSource int64) {
+	return atomic.LoadInt64(&b.source.forwardedNum), atomic.LoadInt64(&b.target.forwardedNum)
+}
+
+// Stops the bridge. Run() returns after the current tick.
+func (b *ChannelBridge) Stop() {
+	atomic.StoreInt32(&b.stopped, 1)
+}
+
+func (b *ChannelBridge) isStopped() bool {
+	return atomic.LoadInt32(&b.stopped) == 1
+}
+
+// Connects to both deployments and forwards the updates until Stop() is called or any of the connections is closed.
+func (b *ChannelBridge) Run() error {
+	for _, ep := range []*bridgeEndpoint{b.source, b.target} {
+		if err := b.connect(ep); err != nil {
+			return fmt.Errorf("failed to connect to %s: %w", ep.config.Addr, err)
+		}
+		defer ep.client.Disconnect()
+	}
+	b.forward(b.source, b.target)
+	if b.config.TwoWay {
+		b.forward(b.target, b.source)
+	}
+
+	// Subscribe to the target channel first, so the bridge can write the source's full states to it on the first fan-out.
+	if err := b.subscribe(b.target, &channeldpb.ChannelSubscriptionOptions{
+		DataAccess:           channeldpb.ChannelDataAccess_WRITE_ACCESS.Enum(),
+		SkipSelfUpdateFanOut: proto.Bool(true),
+		// The target's full states would be duplicated in the source channel
+		SkipFirstFanOut:  proto.Bool(true),
+		FanOutIntervalMs: proto.Uint32(b.config.FanOutIntervalMs),
+	}); err != nil {
+		return fmt.Errorf("failed to subscribe to the target channel: %w", err)
+	}
+
+	if err := b.subscribe(b.source, &channeldpb.ChannelSubscriptionOptions{
+		DataAccess:           b.sourceDataAccess(),
+		SkipSelfUpdateFanOut: proto.Bool(true),
+		FanOutIntervalMs:     proto.Uint32(b.config.FanOutIntervalMs),
+	}); err != nil {
+		return fmt.Errorf("failed to subscribe to the source channel: %w", err)
+	}
+
+	for !b.isStopped() {
+		if err := b.tick(); err != nil {
+			return err
+		}
+		time.Sleep(b.config.TickInterval)
+	}
+	return nil
+}
+
+func (b *ChannelBridge) sourceDataAccess() *channeldpb.ChannelDataAccess {
+	if !b.config.TwoWay {
+		return channeldpb.ChannelDataAccess_READ_ACCESS.Enum()
+	}
+	return channeldpb.ChannelDataAccess_WRITE_ACCESS.Enum()
+}
+
+func (b *ChannelBridge) tick() error {
+	for _, ep := range []*bridgeEndpoint{b.source, b.target} {
+		select {
+		case err := <-ep.receiveErr:
+			return fmt.Errorf("disconnected from %s: %w", ep.config.Addr, err)
+		default:
+		}
+		if err := ep.client.Tick(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Connects to the endpoint and waits for the auth result.
+func (b *ChannelBridge) connect(ep *bridgeEndpoint) error {
+	c, err := client.NewClient(ep.config.Addr)
+	if err != nil {
+		return err
+	}
+	ep.client = c
+	go func() {
+		for {
+			if err := c.Receive(); err != nil {
+				ep.receiveErr <- err
+				return
+			}
+		}
+	}()
+
+	var authResult *channeldpb.AuthResultMessage
+	c.OnAuth(func(_ *client.ChanneldClient, result *channeldpb.AuthResultMessage) {
+		authResult = result
+	})
+	c.Auth(ep.config.LoginToken, ep.config.PlayerIdentifierToken)
+	if err := b.tickUntil(ep, func() bool { return authResult != nil }); err != nil {
+		return err
+	}
+	if authResult.Result != channeldpb.AuthResultMessage_SUCCESSFUL {
+		return fmt.Errorf("auth result: %s", authResult.Result)
+	}
+	return nil
+}
+
+// Forwards the updates of the channel from one endpoint to the other.
+func (b *ChannelBridge) forward(from *bridgeEndpoint, to *bridgeEndpoint) {
+	from.client.OnUpdate(func(_ *client.ChanneldClient, channelId uint32, msg *channeldpb.ChannelDataUpdateMessage) {
+		if channelId != from.config.ChannelId || msg.Data == nil {
+			return
+		}
+		to.client.Send(to.config.ChannelId, channeldpb.BroadcastType_NO_BROADCAST, uint32(channeldpb.MessageType_CHANNEL_DATA_UPDATE), &channeldpb.ChannelDataUpdateMessage{
+			Data: msg.Data,
+		}, nil)
+		atomic.AddInt64(&from.forwardedNum, 1)
+	})
+}
+
+func (b *ChannelBridge) subscribe(ep *bridgeEndpoint, subOptions *channeldpb.ChannelSubscriptionOptions) error {
+	subscribed := false
+	err := ep.client.Subscribe(ep.config.ChannelId, subOptions, func(_ *client.ChanneldClient, _ uint32, _ *channeldpb.SubscribedToChannelResultMessage) {
+		subscribed = true
+	})
+	if err != nil {
+		return err
+	}
+	return b.tickUntil(ep, func() bool { return subscribed })
+}
+
+func (b *ChannelBridge) tickUntil(ep *bridgeEndpoint, cond func() bool) error {
+	deadline := time.Now().Add(b.config.Timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			return errors.New("timed out")
+		}
+		select {
+		case err := <-ep.receiveErr:
+			return err
+		default:
+		}
+		if err := ep.client.Tick(); err != nil {
+			return err
+		}
+		time.Sleep(b.config.TickInterval)
+	}
+	return nil
+}
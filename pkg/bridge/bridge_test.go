@@ -0,0 +1,134 @@
+package bridge
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeld"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+const testServerAddr = "127.0.0.1:32128"
+
+// Both the source and the target channels are in the same test server.
+func startTestServer(t *testing.T) (sourceChannelId uint32, targetChannelId uint32) {
+	channeld.InitLogs()
+	channeld.InitChannels()
+	channeld.InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_authoratative_fsm.json")
+	channeld.GlobalSettings.Development = true
+
+	source := channeld.GetChannel(channeld.GlobalChannelId)
+	source.Execute(func(ch *channeld.Channel) {
+		ch.InitData(&testpb.TestChannelDataMessage{Text: "a"}, nil)
+	})
+	target, err := channeld.CreateChannel(channeldpb.ChannelType_SUBWORLD, nil)
+	assert.NoError(t, err)
+	target.Execute(func(ch *channeld.Channel) {
+		ch.InitData(&testpb.TestChannelDataMessage{}, nil)
+	})
+
+	go channeld.StartListening(channeldpb.ConnectionType_CLIENT, "tcp", ":32128")
+	time.Sleep(100 * time.Millisecond)
+	return uint32(source.Id()), uint32(target.Id())
+}
+
+// The channel data received by the test writer. Accessed by the test and the writer's tick goroutine.
+type receivedData struct {
+	sync.Mutex
+	data testpb.TestChannelDataMessage
+}
+
+func (d *receivedData) get() *testpb.TestChannelDataMessage {
+	d.Lock()
+	defer d.Unlock()
+	return proto.Clone(&d.data).(*testpb.TestChannelDataMessage)
+}
+
+// Subscribes to the channel with write access, and ticks the client in the background until the test ends.
+func newTestWriter(t *testing.T, channelId uint32, data *receivedData) (*client.ChanneldClient, chan struct{}) {
+	c, err := client.NewClient(testServerAddr)
+	assert.NoError(t, err)
+	go func() {
+		for c.Receive() == nil {
+		}
+	}()
+	c.OnAuth(func(c *client.ChanneldClient, result *channeldpb.AuthResultMessage) {
+		c.Subscribe(channelId, &channeldpb.ChannelSubscriptionOptions{
+			DataAccess:       channeldpb.ChannelDataAccess_WRITE_ACCESS.Enum(),
+			FanOutIntervalMs: proto.Uint32(20),
+		}, nil)
+	})
+	c.OnUpdate(func(_ *client.ChanneldClient, chId uint32, msg *channeldpb.ChannelDataUpdateMessage) {
+		if chId == channelId {
+			update := &testpb.TestChannelDataMessage{}
+			msg.Data.UnmarshalTo(update)
+			data.Lock()
+			proto.Merge(&data.data, update)
+			data.Unlock()
+		}
+	})
+	c.Auth("test", "writer")
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				c.Disconnect()
+				return
+			default:
+				c.Tick()
+				time.Sleep(10 * time.Millisecond)
+			}
+		}
+	}()
+	return c, done
+}
+
+func TestChannelBridge(t *testing.T) {
+	sourceChannelId, targetChannelId := startTestServer(t)
+
+	config := DefaultChannelBridgeConfig
+	config.Source = EndpointConfig{Addr: testServerAddr, ChannelId: sourceChannelId, PlayerIdentifierToken: "bridge-source"}
+	config.Target = EndpointConfig{Addr: testServerAddr, ChannelId: targetChannelId, PlayerIdentifierToken: "bridge-target"}
+	config.TwoWay = true
+	config.FanOutIntervalMs = 20
+	b := NewChannelBridge(config)
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- b.Run()
+	}()
+
+	sourceData := &receivedData{}
+	targetData := &receivedData{}
+	_, sourceDone := newTestWriter(t, sourceChannelId, sourceData)
+	defer close(sourceDone)
+	targetWriter, targetDone := newTestWriter(t, targetChannelId, targetData)
+	defer close(targetDone)
+
+	// The full states of the source are mirrored to the target
+	assert.Eventually(t, func() bool { return targetData.get().Text == "a" }, time.Second, 10*time.Millisecond)
+
+	// Two-way
+	targetWriter.UpdateChannelData(targetChannelId, &testpb.TestChannelDataMessage{Num: 1})
+	assert.Eventually(t, func() bool { return sourceData.get().Num == 1 }, time.Second, 10*time.Millisecond)
+
+	// No loop: the updates are forwarded once in each direction
+	time.Sleep(200 * time.Millisecond)
+	sourceToTarget, targetToSource := b.ForwardedNum()
+	assert.EqualValues(t, 1, sourceToTarget)
+	assert.EqualValues(t, 1, targetToSource)
+
+	b.Stop()
+	select {
+	case err := <-runErr:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Error("bridge didn't stop")
+	}
+}
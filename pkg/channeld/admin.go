@@ -0,0 +1,82 @@
+package channeld
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+// Creates the handler of the admin port:
+//
+//	/metrics         Prometheus metrics, including the Go runtime metrics (GC pause, goroutine count, heap, etc.)
+//	/health/channels See HandleChannelHealth()
+//	/debug/memory    See HandleMemoryUsage()
+//	/debug/channels  See HandleChannelStats()
+//	/debug/tenants   See HandleTenantUsage()
+//	/debug/trace     See HandleConnectionTrace()
+//	/debug/bans      See HandleBans()
+//	/debug/netem     See HandleNetworkConditions(), only if GlobalSettings.EnableNetem is true
+//	/debug/pprof/    net/http/pprof, only if GlobalSettings.EnablePprof is true
+//
+// If GlobalSettings.AdminAuthToken is set, the /debug/ endpoints require the "Authorization: Bearer <token>" header.
+// The requests that trace a connection or change the ban list or the network conditions are refused if it's not set. See requireAdminToken().
+func NewAdminHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/health/channels", HandleChannelHealth)
+
+	debugMux := http.NewServeMux()
+	debugMux.HandleFunc("/debug/memory", HandleMemoryUsage)
+	debugMux.HandleFunc("/debug/channels", HandleChannelStats)
+	debugMux.HandleFunc("/debug/tenants", HandleTenantUsage)
+	debugMux.HandleFunc("/debug/trace", HandleConnectionTrace)
+	debugMux.HandleFunc("/debug/bans", HandleBans)
+	if GlobalSettings.EnableNetem {
+		debugMux.HandleFunc("/debug/netem", HandleNetworkConditions)
+	}
+	if GlobalSettings.EnablePprof {
+		debugMux.HandleFunc("/debug/pprof/", pprof.Index)
+		debugMux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		debugMux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		debugMux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		debugMux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+	mux.Handle("/debug/", adminAuth(debugMux))
+
+	return mux
+}
+
+func adminAuth(next http.Handler) http.Handler {
+	if GlobalSettings.AdminAuthToken == "" {
+		return next
+	}
+	expected := []byte("Bearer " + GlobalSettings.AdminAuthToken)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			securityLogger.Info("unauthorized admin request", zap.String("path", r.URL.Path), zap.String("remoteAddr", r.RemoteAddr))
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Returns false and responds 403 if GlobalSettings.AdminAuthToken is not set. Called by the admin endpoints before
+// changing anything, so they are not open to whoever can reach the admin port with the default settings.
+func requireAdminToken(w http.ResponseWriter, r *http.Request) bool {
+	if GlobalSettings.AdminAuthToken != "" {
+		return true
+	}
+	securityLogger.Info("refused the admin request as no auth token is set", zap.String("method", r.Method), zap.String("path", r.URL.Path), zap.String("remoteAddr", r.RemoteAddr))
+	http.Error(w, "the admin auth token is required to make changes", http.StatusForbidden)
+	return false
+}
+
+// Blocks until the admin server stops.
+func StartAdminServer() {
+	rootLogger.Info("start listening the admin port", zap.String("address", GlobalSettings.AdminAddress), zap.Bool("pprof", GlobalSettings.EnablePprof))
+	rootLogger.Error("stopped listening the admin port", zap.Error(http.ListenAndServe(GlobalSettings.AdminAddress, NewAdminHandler())))
+}
@@ -0,0 +1,46 @@
+package channeld
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminHandler(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	defer func(enablePprof bool, token string) {
+		GlobalSettings.EnablePprof = enablePprof
+		GlobalSettings.AdminAuthToken = token
+	}(GlobalSettings.EnablePprof, GlobalSettings.AdminAuthToken)
+
+	serve := func(h http.Handler, path string, token string) int {
+		req := httptest.NewRequest("GET", path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	GlobalSettings.EnablePprof = false
+	GlobalSettings.AdminAuthToken = ""
+	h := NewAdminHandler()
+	assert.Equal(t, http.StatusOK, serve(h, "/metrics", ""))
+	assert.Equal(t, http.StatusOK, serve(h, "/debug/memory", ""))
+	assert.Equal(t, http.StatusNotFound, serve(h, "/debug/pprof/", ""))
+
+	GlobalSettings.EnablePprof = true
+	GlobalSettings.AdminAuthToken = "secret"
+	h = NewAdminHandler()
+	assert.Equal(t, http.StatusOK, serve(h, "/metrics", ""))
+	assert.Equal(t, http.StatusUnauthorized, serve(h, "/debug/pprof/", ""))
+	assert.Equal(t, http.StatusUnauthorized, serve(h, "/debug/pprof/", "wrong"))
+	assert.Equal(t, http.StatusOK, serve(h, "/debug/pprof/", "secret"))
+	assert.Equal(t, http.StatusOK, serve(h, "/debug/memory", "secret"))
+}
@@ -0,0 +1,220 @@
+package channeld
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	DefaultAnalyticsBatchSize       = 100
+	DefaultAnalyticsFlushIntervalMs = 5000
+	// The events are dropped when the queue is full, so the sink never slows down the receiving of the connections.
+	analyticsQueueSize = 4096
+)
+
+// The event of channeldpb.AnalyticsMessage, with the information of the connection that sent it.
+type AnalyticsEvent struct {
+	ConnId       uint32
+	Pit          string
+	Tenant       string `json:",omitempty"`
+	Event        string
+	Properties   map[string]string `json:",omitempty"`
+	ClientTimeMs int64
+	ServerTimeMs int64
+}
+
+// Writes the batches of the analytics events, e.g. to a file or an HTTP endpoint. Called in the analytics goroutine.
+// The batch is dropped if an error is returned.
+type AnalyticsSink interface {
+	Write(events []*AnalyticsEvent) error
+}
+
+// Appends the events to the file as newline-delimited JSON.
+type FileAnalyticsSink struct {
+	file *os.File
+}
+
+func NewFileAnalyticsSink(path string) (*FileAnalyticsSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileAnalyticsSink{file: file}, nil
+}
+
+func (s *FileAnalyticsSink) Write(events []*AnalyticsEvent) error {
+	writer := bufio.NewWriter(s.file)
+	encoder := json.NewEncoder(writer)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+func (s *FileAnalyticsSink) Close() error {
+	return s.file.Close()
+}
+
+// POSTs the events to the endpoint as a JSON array.
+type HttpAnalyticsSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewHttpAnalyticsSink(url string) *HttpAnalyticsSink {
+	return &HttpAnalyticsSink{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *HttpAnalyticsSink) Write(events []*AnalyticsEvent) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("analytics endpoint responded %s", resp.Status)
+	}
+	return nil
+}
+
+// Batches the events for the sink. See GlobalSettingsType.AnalyticsSink.
+type analyticsBatcher struct {
+	sink          AnalyticsSink
+	batchSize     int
+	flushInterval time.Duration
+	queue         chan *AnalyticsEvent
+	batch         []*AnalyticsEvent
+}
+
+var analytics *analyticsBatcher
+var analyticsLock sync.RWMutex
+
+// Sets the sink of the analytics events and starts batching them. The AnalyticsMessages are dropped if the sink is nil.
+// See GlobalSettingsType.AnalyticsBatchSize and AnalyticsFlushIntervalMs.
+func SetAnalyticsSink(sink AnalyticsSink) {
+	analyticsLock.Lock()
+	defer analyticsLock.Unlock()
+	if analytics != nil {
+		// Flushes the pending events to the previous sink and stops
+		close(analytics.queue)
+		analytics = nil
+	}
+	if sink == nil {
+		return
+	}
+
+	batchSize := GlobalSettings.AnalyticsBatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultAnalyticsBatchSize
+	}
+	flushIntervalMs := GlobalSettings.AnalyticsFlushIntervalMs
+	if flushIntervalMs == 0 {
+		flushIntervalMs = DefaultAnalyticsFlushIntervalMs
+	}
+	analytics = &analyticsBatcher{
+		sink:          sink,
+		batchSize:     batchSize,
+		flushInterval: time.Duration(flushIntervalMs) * time.Millisecond,
+		queue:         make(chan *AnalyticsEvent, analyticsQueueSize),
+	}
+	go analytics.run()
+}
+
+// Creates the sink of GlobalSettingsType.AnalyticsSink: an HTTP endpoint if it starts with http:// or https://, otherwise a file path.
+// Does nothing if the setting is empty.
+func InitAnalytics() error {
+	if GlobalSettings.AnalyticsSink == "" {
+		return nil
+	}
+	if strings.HasPrefix(GlobalSettings.AnalyticsSink, "http://") || strings.HasPrefix(GlobalSettings.AnalyticsSink, "https://") {
+		SetAnalyticsSink(NewHttpAnalyticsSink(GlobalSettings.AnalyticsSink))
+		return nil
+	}
+	sink, err := NewFileAnalyticsSink(GlobalSettings.AnalyticsSink)
+	if err != nil {
+		return err
+	}
+	SetAnalyticsSink(sink)
+	return nil
+}
+
+func (b *analyticsBatcher) run() {
+	ticker := time.NewTicker(b.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case event, ok := <-b.queue:
+			if !ok {
+				b.flush()
+				return
+			}
+			b.batch = append(b.batch, event)
+			if len(b.batch) >= b.batchSize {
+				b.flush()
+			}
+		case <-ticker.C:
+			b.flush()
+		}
+	}
+}
+
+func (b *analyticsBatcher) flush() {
+	if len(b.batch) == 0 {
+		return
+	}
+	if err := b.sink.Write(b.batch); err != nil {
+		rootLogger.Warn("failed to write analytics events", zap.Error(err), zap.Int("num", len(b.batch)))
+		analyticsEventsDropped.Add(float64(len(b.batch)))
+	}
+	b.batch = nil
+}
+
+// Handled in the receive goroutine, as the analytics events don't involve any channel.
+func (c *Connection) handleAnalyticsMessage(mp *channeldpb.MessagePack) {
+	if c.State() != ConnectionState_READY {
+		c.Logger().Warn("analytics message is not allowed before authenticated")
+		return
+	}
+	msg := &channeldpb.AnalyticsMessage{}
+	if err := proto.Unmarshal(mp.MsgBody, msg); err != nil {
+		c.Logger().Error("unmarshalling AnalyticsMessage", zap.Error(err))
+		return
+	}
+
+	analyticsLock.RLock()
+	defer analyticsLock.RUnlock()
+	if analytics == nil {
+		return
+	}
+	event := &AnalyticsEvent{
+		ConnId:       uint32(c.id),
+		Pit:          c.pit,
+		Tenant:       GetTenant(c),
+		Event:        msg.Event,
+		Properties:   msg.Properties,
+		ClientTimeMs: msg.ClientTimeMs,
+		ServerTimeMs: time.Now().UnixMilli(),
+	}
+	select {
+	case analytics.queue <- event:
+	default:
+		analyticsEventsDropped.Inc()
+	}
+}
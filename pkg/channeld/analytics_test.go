@@ -0,0 +1,120 @@
+package channeld
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+type testAnalyticsSink struct {
+	lock    sync.Mutex
+	batches [][]*AnalyticsEvent
+}
+
+func (s *testAnalyticsSink) Write(events []*AnalyticsEvent) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.batches = append(s.batches, events)
+	return nil
+}
+
+func (s *testAnalyticsSink) batchNum() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.batches)
+}
+
+func TestAnalytics(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.AnalyticsBatchSize = 2
+	defer func() {
+		GlobalSettings.AnalyticsBatchSize = 0
+	}()
+	sink := &testAnalyticsSink{}
+	SetAnalyticsSink(sink)
+	defer SetAnalyticsSink(nil)
+
+	send := func(c *Connection, event string) {
+		body, _ := proto.Marshal(&channeldpb.AnalyticsMessage{Event: event, Properties: map[string]string{"level": "1"}, ClientTimeMs: 100})
+		c.handleAnalyticsMessage(&channeldpb.MessagePack{MsgType: uint32(channeldpb.MessageType_ANALYTICS), MsgBody: body})
+	}
+
+	// Not authenticated
+	unauthed := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	send(unauthed, "level_start")
+
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	client.OnAuthenticated("p1")
+	send(client, "level_start")
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 0, sink.batchNum())
+
+	send(client, "level_end")
+	assert.Eventually(t, func() bool { return sink.batchNum() == 1 }, time.Second, 10*time.Millisecond)
+	batch := sink.batches[0]
+	if assert.Len(t, batch, 2) {
+		assert.Equal(t, "level_start", batch[0].Event)
+		assert.Equal(t, "level_end", batch[1].Event)
+		assert.EqualValues(t, client.Id(), batch[0].ConnId)
+		assert.Equal(t, "p1", batch[0].Pit)
+		assert.Equal(t, "1", batch[0].Properties["level"])
+		assert.EqualValues(t, 100, batch[0].ClientTimeMs)
+		assert.NotZero(t, batch[0].ServerTimeMs)
+	}
+
+	// The pending events are flushed when the sink is replaced
+	send(client, "quit")
+	time.Sleep(50 * time.Millisecond)
+	SetAnalyticsSink(nil)
+	assert.Eventually(t, func() bool { return sink.batchNum() == 2 }, time.Second, 10*time.Millisecond)
+}
+
+func TestAnalyticsSinks(t *testing.T) {
+	events := []*AnalyticsEvent{{ConnId: 1, Event: "a"}, {ConnId: 2, Event: "b"}}
+
+	path := filepath.Join(t.TempDir(), "analytics.log")
+	fileSink, err := NewFileAnalyticsSink(path)
+	if assert.NoError(t, err) {
+		assert.NoError(t, fileSink.Write(events))
+		assert.NoError(t, fileSink.Write(events[:1]))
+		fileSink.Close()
+		data, _ := os.ReadFile(path)
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		var num int
+		for decoder.More() {
+			var event AnalyticsEvent
+			assert.NoError(t, decoder.Decode(&event))
+			num++
+		}
+		assert.Equal(t, 3, num)
+	}
+
+	var received []*AnalyticsEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+	assert.NoError(t, NewHttpAnalyticsSink(server.URL).Write(events))
+	if assert.Len(t, received, 2) {
+		assert.Equal(t, "b", received[1].Event)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	assert.Error(t, NewHttpAnalyticsSink(failing.URL).Write(events))
+}
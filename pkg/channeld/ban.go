@@ -0,0 +1,343 @@
+package channeld
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+)
+
+// What a ban is matched against when a connection connects or authenticates.
+type BanType string
+
+const (
+	// The user id resolved by the UserStore, or the PIT if not linked to any account. See Connection.UserId().
+	BanType_UserId BanType = "userId"
+	// The IP address of the connection. See GetIP().
+	BanType_IP BanType = "ip"
+	// The device id tag of the connection, provided by the TaggingAuthProvider. See DeviceIdTag.
+	BanType_DeviceId BanType = "deviceId"
+)
+
+// The tag of the connection's device id, which the TaggingAuthProvider should attach for the device bans to work.
+const DeviceIdTag = "deviceId"
+
+const (
+	BanAction_Ban   = "ban"
+	BanAction_Unban = "unban"
+	// The ban is removed by the expiry rather than an operator.
+	BanAction_Expire = "expire"
+)
+
+var ErrInvalidBan = errors.New("the ban should have a valid type and a value")
+
+type BanRecord struct {
+	Type  BanType
+	Value string
+	// Why the ban is issued, e.g. "cheating". Only for the audit.
+	Reason string
+	// Who issued the ban, e.g. the name of the operator. Only for the audit.
+	IssuedBy  string
+	CreatedAt time.Time
+	// The zero time means the ban never expires.
+	ExpiresAt time.Time
+}
+
+func (b *BanRecord) Expired(now time.Time) bool {
+	return !b.ExpiresAt.IsZero() && !now.Before(b.ExpiresAt)
+}
+
+// An entry of the audit trail of the ban list.
+type BanAuditEntry struct {
+	Time time.Time
+	// BanAction_Ban, BanAction_Unban or BanAction_Expire
+	Action string
+	Ban    BanRecord
+	// Who made the change. Empty for the expiry.
+	Operator string
+}
+
+// Persists the ban list and its audit trail, so the bans survive the restarts of channeld. See the archive package for the
+// SQLite implementation. Called in the goroutine that bans or unbans, and when a connection connects or authenticates.
+type BanStore interface {
+	// Returns all the bans, including the expired ones that haven't been deleted.
+	LoadBans() ([]*BanRecord, error)
+	// Inserts the ban, or replaces the one of the same type and value.
+	SaveBan(ban *BanRecord) error
+	DeleteBan(banType BanType, value string) error
+	AppendBanAudit(entry *BanAuditEntry) error
+}
+
+// Keeps the ban list and the audit trail in memory. Mostly for the development and the tests.
+type MemoryBanStore struct {
+	lock  sync.RWMutex
+	bans  map[banKey]*BanRecord
+	audit []*BanAuditEntry
+}
+
+func NewMemoryBanStore() *MemoryBanStore {
+	return &MemoryBanStore{bans: make(map[banKey]*BanRecord)}
+}
+
+func (s *MemoryBanStore) LoadBans() ([]*BanRecord, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	result := make([]*BanRecord, 0, len(s.bans))
+	for _, ban := range s.bans {
+		copied := *ban
+		result = append(result, &copied)
+	}
+	return result, nil
+}
+
+func (s *MemoryBanStore) SaveBan(ban *BanRecord) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	copied := *ban
+	s.bans[banKey{ban.Type, ban.Value}] = &copied
+	return nil
+}
+
+func (s *MemoryBanStore) DeleteBan(banType BanType, value string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.bans, banKey{banType, value})
+	return nil
+}
+
+func (s *MemoryBanStore) AppendBanAudit(entry *BanAuditEntry) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.audit = append(s.audit, entry)
+	return nil
+}
+
+// Returns the audit trail in the order of being appended.
+func (s *MemoryBanStore) Audit() []*BanAuditEntry {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return append([]*BanAuditEntry(nil), s.audit...)
+}
+
+type banKey struct {
+	banType BanType
+	value   string
+}
+
+// The bans are cached in memory, and written through to the store.
+var bans = make(map[banKey]*BanRecord)
+var banStore BanStore = NewMemoryBanStore()
+var bansLock sync.RWMutex
+
+// Replaces the store of the ban list, and loads the bans from it. The bans in memory are discarded.
+func SetBanStore(store BanStore) error {
+	loaded, err := store.LoadBans()
+	if err != nil {
+		return err
+	}
+	bansLock.Lock()
+	defer bansLock.Unlock()
+	banStore = store
+	bans = make(map[banKey]*BanRecord, len(loaded))
+	for _, ban := range loaded {
+		bans[banKey{ban.Type, ban.Value}] = ban
+	}
+	rootLogger.Info("loaded the ban list", zap.Int("num", len(bans)))
+	return nil
+}
+
+// Bans the user id, IP or device id until the ban expires, and kicks the matching connections. A ban of the same type and
+// value is replaced. The ban is persisted to the BanStore, and recorded in the audit trail and the security log.
+func Ban(ban BanRecord) error {
+	if ban.Value == "" || (ban.Type != BanType_UserId && ban.Type != BanType_IP && ban.Type != BanType_DeviceId) {
+		return ErrInvalidBan
+	}
+	if ban.CreatedAt.IsZero() {
+		ban.CreatedAt = time.Now()
+	}
+
+	bansLock.Lock()
+	if err := banStore.SaveBan(&ban); err != nil {
+		bansLock.Unlock()
+		return err
+	}
+	bans[banKey{ban.Type, ban.Value}] = &ban
+	auditBan(&BanAuditEntry{Time: ban.CreatedAt, Action: BanAction_Ban, Ban: ban, Operator: ban.IssuedBy})
+	bansLock.Unlock()
+
+	allConnections.Range(func(_ ConnectionId, c *Connection) bool {
+		if !c.IsClosing() && c.banValue(ban.Type) == ban.Value {
+			c.CloseWithReason(channeldpb.DisconnectMessage_KICKED, "banned")
+		}
+		return true
+	})
+	return nil
+}
+
+// Lifts the ban of the type and value. Returns false if there's no such ban.
+func Unban(banType BanType, value string, operator string) (bool, error) {
+	bansLock.Lock()
+	defer bansLock.Unlock()
+	key := banKey{banType, value}
+	ban, exists := bans[key]
+	if !exists {
+		return false, nil
+	}
+	if err := banStore.DeleteBan(banType, value); err != nil {
+		return false, err
+	}
+	delete(bans, key)
+	auditBan(&BanAuditEntry{Time: time.Now(), Action: BanAction_Unban, Ban: *ban, Operator: operator})
+	return true, nil
+}
+
+// Returns the unexpired ban of the type and value, or nil if there's none. The expired ban is removed. Goroutine-safe.
+func FindBan(banType BanType, value string) *BanRecord {
+	if value == "" {
+		return nil
+	}
+	key := banKey{banType, value}
+	bansLock.RLock()
+	ban, exists := bans[key]
+	bansLock.RUnlock()
+	if !exists {
+		return nil
+	}
+	now := time.Now()
+	if !ban.Expired(now) {
+		return ban
+	}
+
+	bansLock.Lock()
+	defer bansLock.Unlock()
+	// Could have been replaced or removed in the meantime
+	if bans[key] == ban {
+		if err := banStore.DeleteBan(banType, value); err != nil {
+			rootLogger.Warn("failed to delete the expired ban", zap.String("type", string(banType)), zap.String("value", value), zap.Error(err))
+		}
+		delete(bans, key)
+		auditBan(&BanAuditEntry{Time: now, Action: BanAction_Expire, Ban: *ban})
+	}
+	return nil
+}
+
+// Returns the unexpired bans, ordered by the creation time.
+func GetBans() []*BanRecord {
+	now := time.Now()
+	bansLock.RLock()
+	defer bansLock.RUnlock()
+	result := make([]*BanRecord, 0, len(bans))
+	for _, ban := range bans {
+		if !ban.Expired(now) {
+			result = append(result, ban)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result
+}
+
+// Should be called with bansLock held.
+func auditBan(entry *BanAuditEntry) {
+	securityLogger.Info("ban list changed",
+		zap.String("action", entry.Action),
+		zap.String("type", string(entry.Ban.Type)),
+		zap.String("value", entry.Ban.Value),
+		zap.String("reason", entry.Ban.Reason),
+		zap.String("operator", entry.Operator),
+		zap.Time("expiresAt", entry.Ban.ExpiresAt),
+	)
+	if err := banStore.AppendBanAudit(entry); err != nil {
+		rootLogger.Warn("failed to append the ban audit", zap.String("action", entry.Action), zap.Error(err))
+	}
+}
+
+func (c *Connection) banValue(banType BanType) string {
+	switch banType {
+	case BanType_UserId:
+		return c.UserId()
+	case BanType_IP:
+		if addr := c.RemoteAddr(); addr != nil {
+			return GetIP(addr)
+		}
+	case BanType_DeviceId:
+		value, _ := c.GetTag(DeviceIdTag)
+		return value
+	}
+	return ""
+}
+
+// Returns the ban that keeps the connection from being authenticated as the user, or nil if there's none.
+func (c *Connection) findBan(userId string) *BanRecord {
+	if ban := FindBan(BanType_UserId, userId); ban != nil {
+		return ban
+	}
+	if ban := FindBan(BanType_IP, c.banValue(BanType_IP)); ban != nil {
+		return ban
+	}
+	return FindBan(BanType_DeviceId, c.banValue(BanType_DeviceId))
+}
+
+// The admin API of the ban list, e.g.
+//
+//	GET    /debug/bans                                                      returns the unexpired bans
+//	POST   /debug/bans?type=userId&value=u1&durationSec=3600&reason=cheating&by=alice
+//	                                                                        bans the user for an hour (durationSec=0 or omitted means forever)
+//	DELETE /debug/bans?type=ip&value=1.2.3.4&by=alice                       lifts the ban
+//
+// The type is one of userId, ip and deviceId. Banning and unbanning require GlobalSettings.AdminAuthToken to be set. Responds
+// the bans as JSON.
+func HandleBans(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	banType := BanType(query.Get("type"))
+	value := query.Get("value")
+
+	if r.Method != http.MethodGet && !requireAdminToken(w, r) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		ban := BanRecord{Type: banType, Value: value, Reason: query.Get("reason"), IssuedBy: query.Get("by"), CreatedAt: time.Now()}
+		if str := query.Get("durationSec"); str != "" {
+			seconds, err := strconv.ParseUint(str, 10, 32)
+			if err != nil {
+				http.Error(w, "invalid durationSec", http.StatusBadRequest)
+				return
+			}
+			if seconds > 0 {
+				ban.ExpiresAt = ban.CreatedAt.Add(time.Duration(seconds) * time.Second)
+			}
+		}
+		if err := Ban(ban); err != nil {
+			if errors.Is(err, ErrInvalidBan) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			} else {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+	case http.MethodDelete:
+		found, err := Unban(banType, value, query.Get("by"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !found {
+			http.Error(w, "ban not found", http.StatusNotFound)
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(GetBans())
+}
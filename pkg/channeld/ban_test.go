@@ -0,0 +1,132 @@
+package channeld
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBan(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+	store := NewMemoryBanStore()
+	assert.NoError(t, SetBanStore(store))
+	defer SetBanStore(NewMemoryBanStore())
+
+	login := func(pit string, deviceId string) *Connection {
+		c := addTestConnection(channeldpb.ConnectionType_CLIENT)
+		if deviceId != "" {
+			c.SetTag(DeviceIdTag, deviceId)
+		}
+		onAuthComplete(MessageContext{
+			MsgType:    channeldpb.MessageType_AUTH,
+			Msg:        &channeldpb.AuthMessage{PlayerIdentifierToken: pit},
+			Connection: c,
+			Channel:    globalChannel,
+		}, channeldpb.AuthResultMessage_SUCCESSFUL, pit)
+		return c
+	}
+
+	// Banning kicks the connected user
+	c1 := login("p1", "")
+	assert.Equal(t, ConnectionState_READY, c1.State())
+	assert.ErrorIs(t, Ban(BanRecord{Type: BanType_UserId}), ErrInvalidBan)
+	assert.NoError(t, Ban(BanRecord{Type: BanType_UserId, Value: "p1", Reason: "cheating", IssuedBy: "alice"}))
+	assert.Eventually(t, c1.IsClosing, time.Second, 10*time.Millisecond)
+	assert.Equal(t, channeldpb.DisconnectMessage_KICKED, c1.DisconnectReason())
+
+	// ...and keeps the user out after reconnecting
+	c2 := login("p1", "")
+	assert.NotEqual(t, ConnectionState_READY, c2.State())
+	assert.Equal(t, channeldpb.DisconnectMessage_AUTH_FAILURE, c2.DisconnectReason())
+	assert.Equal(t, ConnectionState_READY, login("p2", "").State())
+
+	// Device ban
+	assert.NoError(t, Ban(BanRecord{Type: BanType_DeviceId, Value: "d1"}))
+	assert.Equal(t, channeldpb.DisconnectMessage_AUTH_FAILURE, login("p3", "d1").DisconnectReason())
+	assert.Equal(t, ConnectionState_READY, login("p3", "d2").State())
+
+	// The expired ban is removed
+	assert.NoError(t, Ban(BanRecord{Type: BanType_UserId, Value: "p4", ExpiresAt: time.Now().Add(-time.Second)}))
+	assert.Equal(t, ConnectionState_READY, login("p4", "").State())
+	assert.Nil(t, FindBan(BanType_UserId, "p4"))
+
+	// Unban
+	found, err := Unban(BanType_UserId, "p1", "bob")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	found, _ = Unban(BanType_UserId, "p1", "bob")
+	assert.False(t, found)
+	assert.Equal(t, ConnectionState_READY, login("p1", "").State())
+
+	// Persisted and audited
+	persisted, _ := store.LoadBans()
+	assert.Len(t, persisted, 1)
+	actions := []string{}
+	for _, entry := range store.Audit() {
+		actions = append(actions, entry.Action)
+	}
+	assert.Equal(t, []string{BanAction_Ban, BanAction_Ban, BanAction_Ban, BanAction_Expire, BanAction_Unban}, actions)
+	assert.Equal(t, "bob", store.Audit()[4].Operator)
+
+	// The bans are loaded from the store
+	assert.NoError(t, SetBanStore(store))
+	assert.NotNil(t, FindBan(BanType_DeviceId, "d1"))
+}
+
+func TestHandleBans(t *testing.T) {
+	InitLogs()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+	assert.NoError(t, SetBanStore(NewMemoryBanStore()))
+	defer func(token string) {
+		GlobalSettings.AdminAuthToken = token
+	}(GlobalSettings.AdminAuthToken)
+
+	request := func(method string, query string) (int, []*BanRecord) {
+		rec := httptest.NewRecorder()
+		HandleBans(rec, httptest.NewRequest(method, "/debug/bans?"+query, nil))
+		var result []*BanRecord
+		json.Unmarshal(rec.Body.Bytes(), &result)
+		return rec.Code, result
+	}
+
+	// Can't change the ban list without the auth token
+	GlobalSettings.AdminAuthToken = ""
+	code, _ := request("POST", "type=ip&value=1.2.3.4")
+	assert.Equal(t, 403, code)
+	assert.Nil(t, FindBan(BanType_IP, "1.2.3.4"))
+	code, _ = request("DELETE", "type=ip&value=1.2.3.4")
+	assert.Equal(t, 403, code)
+	code, _ = request("GET", "")
+	assert.Equal(t, 200, code)
+
+	GlobalSettings.AdminAuthToken = "secret"
+	code, result := request("POST", "type=ip&value=1.2.3.4&durationSec=3600&reason=spam&by=alice")
+	assert.Equal(t, 200, code)
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, BanType_IP, result[0].Type)
+		assert.Equal(t, "alice", result[0].IssuedBy)
+		assert.False(t, result[0].ExpiresAt.IsZero())
+	}
+	assert.NotNil(t, FindBan(BanType_IP, "1.2.3.4"))
+
+	code, _ = request("POST", "type=name&value=x")
+	assert.Equal(t, 400, code)
+	code, _ = request("POST", "type=ip&value=x&durationSec=abc")
+	assert.Equal(t, 400, code)
+
+	code, result = request("GET", "")
+	assert.Equal(t, 200, code)
+	assert.Len(t, result, 1)
+
+	code, result = request("DELETE", "type=ip&value=1.2.3.4&by=bob")
+	assert.Equal(t, 200, code)
+	assert.Len(t, result, 0)
+	code, _ = request("DELETE", "type=ip&value=1.2.3.4")
+	assert.Equal(t, 404, code)
+}
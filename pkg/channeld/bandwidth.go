@@ -0,0 +1,147 @@
+package channeld
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// The report is ignored if the client hasn't sent a new one for this long, e.g. it stopped reporting.
+const bandwidthReportTtl = 30 * time.Second
+
+// The network condition reported by the client. See channeldpb.BandwidthReportMessage.
+type BandwidthReport struct {
+	DownlinkKbps uint32
+	PacketLoss   float32
+	RttMs        uint32
+	Time         time.Time
+}
+
+// Adapts the fan-outs to the subscriber to its reported network condition. Called in the channel's goroutine.
+type BandwidthPolicy interface {
+	// Returns the fan-out interval of the subscription, given the interval decided by the subscription options.
+	FanOutIntervalMs(report *BandwidthReport, intervalMs uint32) uint32
+	// Returns the max size of each fan-out of the subscription, given the budget decided by the subscription options
+	// (0 means no budget). Only applied if the channel data has the entity map. See ChannelSettingsType.EntityMapField.
+	FanOutBudgetBytes(report *BandwidthReport, intervalMs uint32, budgetBytes int) int
+}
+
+// Lengthens the fan-out interval in proportion to the packet loss, and limits the fan-out budget to a share of the downlink.
+type DefaultBandwidthPolicy struct {
+	// The share of the reported downlink that the fan-outs of each subscription can use.
+	DownlinkShare float64
+	// The packet loss above which the fan-out interval is lengthened.
+	LossThreshold float32
+	// The max times the fan-out interval can be lengthened.
+	MaxIntervalScale float64
+}
+
+func (p DefaultBandwidthPolicy) FanOutIntervalMs(report *BandwidthReport, intervalMs uint32) uint32 {
+	if report.PacketLoss <= p.LossThreshold {
+		return intervalMs
+	}
+	// e.g. 10% more loss than the threshold doubles the interval
+	scale := math.Min(1+float64(report.PacketLoss-p.LossThreshold)*10, p.MaxIntervalScale)
+	return uint32(math.Round(float64(intervalMs) * scale))
+}
+
+func (p DefaultBandwidthPolicy) FanOutBudgetBytes(report *BandwidthReport, intervalMs uint32, budgetBytes int) int {
+	if report.DownlinkKbps == 0 || intervalMs == 0 {
+		return budgetBytes
+	}
+	bytes := int(float64(report.DownlinkKbps) * 1000 / 8 * p.DownlinkShare * float64(intervalMs) / 1000)
+	if bytes < 1 {
+		bytes = 1
+	}
+	if budgetBytes > 0 && budgetBytes < bytes {
+		return budgetBytes
+	}
+	return bytes
+}
+
+var bandwidthPolicy BandwidthPolicy = DefaultBandwidthPolicy{DownlinkShare: 0.5, LossThreshold: 0.02, MaxIntervalScale: 4}
+var bandwidthPolicyLock sync.RWMutex
+
+// Replaces the policy that adapts the fan-outs to the bandwidth reports of the clients. Setting it to nil disables the adaptation.
+func SetBandwidthPolicy(policy BandwidthPolicy) {
+	bandwidthPolicyLock.Lock()
+	defer bandwidthPolicyLock.Unlock()
+	bandwidthPolicy = policy
+}
+
+func getBandwidthPolicy() BandwidthPolicy {
+	bandwidthPolicyLock.RLock()
+	defer bandwidthPolicyLock.RUnlock()
+	return bandwidthPolicy
+}
+
+// Handled in the receive goroutine, as the report doesn't involve any channel.
+func (c *Connection) handleBandwidthReport(mp *channeldpb.MessagePack) {
+	if c.State() != ConnectionState_READY {
+		c.Logger().Warn("bandwidth report is not allowed before authenticated")
+		return
+	}
+	msg := &channeldpb.BandwidthReportMessage{}
+	if err := proto.Unmarshal(mp.MsgBody, msg); err != nil {
+		c.Logger().Error("unmarshalling BandwidthReportMessage", zap.Error(err))
+		return
+	}
+	loss := msg.PacketLoss
+	if loss < 0 || math.IsNaN(float64(loss)) {
+		loss = 0
+	} else if loss > 1 {
+		loss = 1
+	}
+	c.bandwidthReport.Store(&BandwidthReport{
+		DownlinkKbps: msg.DownlinkKbps,
+		PacketLoss:   loss,
+		RttMs:        msg.RttMs,
+		Time:         time.Now(),
+	})
+}
+
+// Returns the latest bandwidth report of the connection, or nil if there's none or it's expired. Goroutine-safe.
+func (c *Connection) BandwidthReport() *BandwidthReport {
+	report, _ := c.bandwidthReport.Load().(*BandwidthReport)
+	if report == nil || time.Since(report.Time) > bandwidthReportTtl {
+		return nil
+	}
+	return report
+}
+
+func (foc *fanOutConnection) bandwidthPolicyAndReport() (BandwidthPolicy, *BandwidthReport) {
+	c, ok := foc.conn.(*Connection)
+	if !ok {
+		return nil, nil
+	}
+	policy := getBandwidthPolicy()
+	if policy == nil {
+		return nil, nil
+	}
+	report := c.BandwidthReport()
+	if report == nil {
+		return nil, nil
+	}
+	return policy, report
+}
+
+// The fan-out interval of the subscription, adapted to the subscriber's bandwidth report.
+func (foc *fanOutConnection) bandwidthFanOutIntervalMs(cs *ChannelSubscription) uint32 {
+	interval := foc.fanOutIntervalMs(cs)
+	if policy, report := foc.bandwidthPolicyAndReport(); policy != nil {
+		return policy.FanOutIntervalMs(report, interval)
+	}
+	return interval
+}
+
+// The fan-out budget of the subscription, adapted to the subscriber's bandwidth report.
+func (foc *fanOutConnection) bandwidthFanOutBudget(cs *ChannelSubscription, budget int) int {
+	if policy, report := foc.bandwidthPolicyAndReport(); policy != nil {
+		return policy.FanOutBudgetBytes(report, foc.bandwidthFanOutIntervalMs(cs), budget)
+	}
+	return budget
+}
@@ -0,0 +1,79 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDefaultBandwidthPolicy(t *testing.T) {
+	policy := DefaultBandwidthPolicy{DownlinkShare: 0.5, LossThreshold: 0.02, MaxIntervalScale: 4}
+
+	assert.EqualValues(t, 50, policy.FanOutIntervalMs(&BandwidthReport{PacketLoss: 0.01}, 50))
+	assert.EqualValues(t, 100, policy.FanOutIntervalMs(&BandwidthReport{PacketLoss: 0.12}, 50))
+	assert.EqualValues(t, 200, policy.FanOutIntervalMs(&BandwidthReport{PacketLoss: 1}, 50))
+
+	// 800kbps * 0.5 = 50KB/s, 5KB per 100ms
+	assert.Equal(t, 5000, policy.FanOutBudgetBytes(&BandwidthReport{DownlinkKbps: 800}, 100, 0))
+	assert.Equal(t, 2000, policy.FanOutBudgetBytes(&BandwidthReport{DownlinkKbps: 800}, 100, 2000))
+	assert.Equal(t, 5000, policy.FanOutBudgetBytes(&BandwidthReport{DownlinkKbps: 800}, 100, 8000))
+	// Unknown downlink
+	assert.Equal(t, 2000, policy.FanOutBudgetBytes(&BandwidthReport{}, 100, 2000))
+}
+
+func TestBandwidthReport(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	owner := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, owner)
+	// Stop the channel.Tick() goroutine
+	ch.removing = 1
+	ch.InitData(&testpb.TestChannelDataMessage{}, nil)
+	cs, _ := client.SubscribeToChannel(ch, &channeldpb.ChannelSubscriptionOptions{FanOutIntervalMs: proto.Uint32(50)})
+	foc := cs.fanOutElement.Value.(*fanOutConnection)
+
+	report := func(msg *channeldpb.BandwidthReportMessage) {
+		msgBody, _ := proto.Marshal(msg)
+		client.receiveMessage(&channeldpb.MessagePack{
+			MsgType: uint32(channeldpb.MessageType_BANDWIDTH_REPORT),
+			MsgBody: msgBody,
+		})
+	}
+
+	// Not allowed before authenticated
+	report(&channeldpb.BandwidthReportMessage{PacketLoss: 0.12})
+	assert.Nil(t, client.BandwidthReport())
+	assert.EqualValues(t, 50, foc.bandwidthFanOutIntervalMs(cs))
+
+	client.OnAuthenticated("")
+	report(&channeldpb.BandwidthReportMessage{DownlinkKbps: 800, PacketLoss: 0.12, RttMs: 30})
+	if assert.NotNil(t, client.BandwidthReport()) {
+		assert.EqualValues(t, 800, client.BandwidthReport().DownlinkKbps)
+		assert.EqualValues(t, 30, client.BandwidthReport().RttMs)
+	}
+	assert.EqualValues(t, 100, foc.bandwidthFanOutIntervalMs(cs))
+	// Budgeted by the adapted interval
+	assert.Equal(t, 5000, foc.bandwidthFanOutBudget(cs, 0))
+
+	// The invalid packet loss is clamped
+	report(&channeldpb.BandwidthReportMessage{PacketLoss: 5})
+	assert.EqualValues(t, 1, client.BandwidthReport().PacketLoss)
+
+	// The expired report is ignored
+	client.BandwidthReport().Time = time.Now().Add(-bandwidthReportTtl * 2)
+	assert.Nil(t, client.BandwidthReport())
+	assert.EqualValues(t, 50, foc.bandwidthFanOutIntervalMs(cs))
+
+	// The adaptation is disabled without the policy
+	report(&channeldpb.BandwidthReportMessage{PacketLoss: 0.12})
+	SetBandwidthPolicy(nil)
+	defer SetBandwidthPolicy(DefaultBandwidthPolicy{DownlinkShare: 0.5, LossThreshold: 0.02, MaxIntervalScale: 4})
+	assert.EqualValues(t, 50, foc.bandwidthFanOutIntervalMs(cs))
+}
@@ -0,0 +1,46 @@
+package channeld
+
+import "sync"
+
+// The capacities of the pooled byte buffers. The largest class fits the default read buffer size (0x0001ffff)
+// and the snappy-encoded packet of MaxPacketSize.
+var bufferSizeClasses = [...]int{1 << 10, 4 << 10, 16 << 10, 64 << 10, 128 << 10}
+
+var bufferPools [len(bufferSizeClasses)]sync.Pool
+
+func init() {
+	for i := range bufferPools {
+		size := bufferSizeClasses[i]
+		bufferPools[i].New = func() interface{} {
+			buf := make([]byte, 0, size)
+			return &buf
+		}
+	}
+}
+
+// Returns a zero-length buffer with the capacity of at least the size. The buffer should be put back via putBuffer() after use.
+// If the size exceeds the largest size class, the buffer is allocated directly and won't be pooled.
+func getBuffer(size int) *[]byte {
+	for i, classSize := range bufferSizeClasses {
+		if size <= classSize {
+			buf := bufferPools[i].Get().(*[]byte)
+			*buf = (*buf)[:0]
+			return buf
+		}
+	}
+	buf := make([]byte, 0, size)
+	return &buf
+}
+
+// The buffer should not be used after put back.
+func putBuffer(buf *[]byte) {
+	if buf == nil {
+		return
+	}
+	for i, classSize := range bufferSizeClasses {
+		if cap(*buf) == classSize {
+			bufferPools[i].Put(buf)
+			return
+		}
+	}
+}
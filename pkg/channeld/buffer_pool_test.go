@@ -0,0 +1,147 @@
+package channeld
+
+import (
+	"net"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// Writes to the buffer and reads the last written packet repeatedly
+type loopbackConn struct {
+	net.Conn
+	packet []byte
+}
+
+func (c *loopbackConn) Read(b []byte) (n int, err error) {
+	return copy(b, c.packet), nil
+}
+
+func (c *loopbackConn) Write(b []byte) (n int, err error) {
+	c.packet = append(c.packet[:0], b...)
+	return len(b), nil
+}
+
+func TestBufferPool(t *testing.T) {
+	buf := getBuffer(100)
+	assert.Equal(t, 0, len(*buf))
+	assert.Equal(t, 1<<10, cap(*buf))
+	*buf = append(*buf, 1, 2, 3)
+	putBuffer(buf)
+
+	buf = getBuffer(1<<10 + 1)
+	assert.Equal(t, 0, len(*buf))
+	assert.Equal(t, 4<<10, cap(*buf))
+	putBuffer(buf)
+
+	// Not pooled
+	buf = getBuffer(1 << 20)
+	assert.Equal(t, 1<<20, cap(*buf))
+	putBuffer(buf)
+	putBuffer(nil)
+}
+
+func newLoopbackConnection(compressionType channeldpb.CompressionType) *Connection {
+	readBufferPtr := getBuffer(MaxPacketSize + PacketHeaderSize)
+	return &Connection{
+		connectionType:  channeldpb.ConnectionType_CLIENT,
+		compressionType: compressionType,
+		conn:            &loopbackConn{},
+		readBuffer:      (*readBufferPtr)[:MaxPacketSize+PacketHeaderSize],
+		readBufferPtr:   readBufferPtr,
+		sendQueues:      newSendQueues(),
+		logger:          &Logger{zap.NewNop()},
+	}
+}
+
+func TestFlushAndReceivePooledPacket(t *testing.T) {
+	InitLogs()
+	InitChannels()
+
+	for _, ct := range []channeldpb.CompressionType{channeldpb.CompressionType_NO_COMPRESSION, channeldpb.CompressionType_SNAPPY} {
+		c := newLoopbackConnection(ct)
+		msgBody, _ := proto.Marshal(&testpb.TestChannelDataMessage{Text: "abc", Num: 123})
+		c.sendQueues[0] <- &channeldpb.MessagePack{ChannelId: 100, MsgType: uint32(channeldpb.MessageType_USER_SPACE_START), MsgBody: msgBody}
+		c.flush()
+
+		packet := c.conn.(*loopbackConn).packet
+		assert.Equal(t, []byte{67, 72}, packet[:2])
+		assert.Equal(t, byte(ct), packet[4])
+		body := packet[PacketHeaderSize:]
+		assert.Equal(t, len(body), readSize(packet))
+		if ct == channeldpb.CompressionType_SNAPPY {
+			body, _ = snappy.Decode(nil, body)
+		}
+		var p channeldpb.Packet
+		assert.NoError(t, proto.Unmarshal(body, &p))
+		if assert.Equal(t, 1, len(p.Messages)) {
+			assert.Equal(t, msgBody, p.Messages[0].MsgBody)
+		}
+
+		// The channel doesn't exist, but the packet should be read
+		c.receive()
+		assert.Equal(t, 0, c.readPos)
+		assert.False(t, c.IsClosing())
+	}
+}
+
+func newBenchmarkMessagePacks() []*channeldpb.MessagePack {
+	msgBody, _ := proto.Marshal(&testpb.TestChannelDataMessage{Text: "The quick brown fox jumps over the lazy dog", Num: 123})
+	mps := make([]*channeldpb.MessagePack, 20)
+	for i := range mps {
+		mps[i] = &channeldpb.MessagePack{ChannelId: 100, MsgType: uint32(channeldpb.MessageType_USER_SPACE_START), MsgBody: msgBody}
+	}
+	return mps
+}
+
+func BenchmarkFlushPacket(b *testing.B) {
+	InitLogs()
+	c := newLoopbackConnection(channeldpb.CompressionType_SNAPPY)
+	mps := newBenchmarkMessagePacks()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, mp := range mps {
+			c.sendQueues[0] <- mp
+		}
+		c.flush()
+	}
+
+	// make() for each packet:
+	// BenchmarkFlushPacket    	   79663	     15748 ns/op	    5829 B/op	      48 allocs/op
+	// BenchmarkFlushPacket    	   74308	     15370 ns/op	    5829 B/op	      48 allocs/op
+
+	// Pooled buffers:
+	// BenchmarkFlushPacket    	   84661	     15874 ns/op	    3136 B/op	      44 allocs/op
+	// BenchmarkFlushPacket    	   86530	     13983 ns/op	    3136 B/op	      44 allocs/op
+}
+
+func BenchmarkReceivePacket(b *testing.B) {
+	InitLogs()
+	InitChannels()
+	c := newLoopbackConnection(channeldpb.CompressionType_SNAPPY)
+	for _, mp := range newBenchmarkMessagePacks() {
+		c.sendQueues[0] <- mp
+	}
+	c.flush()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.receive()
+	}
+
+	// make() for each packet:
+	// BenchmarkReceivePacket  	  165561	      7133 ns/op	    8488 B/op	      71 allocs/op
+	// BenchmarkReceivePacket  	  174592	      7047 ns/op	    8488 B/op	      71 allocs/op
+
+	// Pooled buffers:
+	// BenchmarkReceivePacket  	  167382	      6724 ns/op	    7336 B/op	      70 allocs/op
+	// BenchmarkReceivePacket  	  172056	      6806 ns/op	    7336 B/op	      70 allocs/op
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/metaworking/channeld/pkg/channeldpb"
 	"github.com/metaworking/channeld/pkg/common"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/puzpuzpuz/xsync/v2"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/reflect/protoreflect"
@@ -56,6 +57,7 @@ type ConnectionInChannel interface {
 	sendSubscribed(ctx MessageContext, ch *Channel, connToSub ConnectionInChannel, stubId uint32, subOptions *channeldpb.ChannelSubscriptionOptions)
 	sendUnsubscribed(ctx MessageContext, ch *Channel, connToUnsub *Connection, stubId uint32)
 	HasInterestIn(spatialChId common.ChannelId) bool
+	GetTag(key string) (string, bool)
 	Logger() *Logger
 	RemoteAddr() net.Addr
 }
@@ -70,20 +72,79 @@ type Channel struct {
 	connectionsLock sync.RWMutex
 	// Read-only property, e.g. name
 	metadata string
-	data     *ChannelData
+	// See Tenant()
+	tenant string
+	// The unique key of the named channel. See setKey().
+	key  string
+	data *ChannelData
 	// The ID of the client connection that causes the latest ChannelDataUpdate
 	latestDataUpdateConnId ConnectionId
 	spatialNotifier        common.SpatialInfoChangedNotifier
 	entityController       EntityGroupController
 	inMsgQueue             chan channelMessage
+	inbox                  channelInbox
 	fanOutQueue            *list.List
 	// Time since channel created
-	startTime             time.Time
-	tickInterval          time.Duration
+	startTime    time.Time
+	tickInterval time.Duration
+	// See ChannelSettingsType.HandlingDeadlineMs
+	handlingDeadline      time.Duration
 	tickFrames            int
 	enableClientBroadcast bool
-	logger                *Logger
-	removing              int32
+	// See ChannelSettingsType.SenderInboxQuota and SenderMessagesPerTick
+	senderInboxQuota      int
+	senderMessagesPerTick int
+	// The sequence number of the latest user-space broadcast
+	broadcastSeq       uint64
+	retainedBroadcasts *list.List
+	// The user-space messages to forward in the future ticks. See ScheduleBroadcast().
+	scheduledBroadcasts scheduledBroadcastQueue
+	scheduleSeq         uint64
+	// The recurring timers registered by the owner, by the timerId. See handleChannelTimer().
+	timers map[uint32]*channelTimer
+	// Only for the GLOBAL channel. See presenceState().
+	presence *presenceState
+	// How many times the channel has recovered from a panic. See recoverFromPanic().
+	panicCount int
+	// The latest *ChannelMemoryUsage. See sampleMemoryUsage().
+	memoryUsage               atomic.Value
+	lastMemoryUsageSampleTime time.Time
+	// The latest *ChannelStats. See tickStats().
+	stats               atomic.Value
+	statsCounters       channelStatsCounters
+	statsSamples        []channelStatsSample
+	lastStatsSampleTime time.Time
+	lastStatsPushTime   time.Time
+	// The owner that disconnected in the last tick, and the frozen state of the channel. See OwnerDisconnectPolicy.
+	disconnectedOwner *Connection
+	ownerReclaim      *ownerReclaim
+	// The subscription options of the disconnected owner, for the connection that resumes it. See resumeOwner().
+	previousOwnerOptions *channeldpb.ChannelSubscriptionOptions
+	// See PauseDataFanOut()
+	dataFanOutPaused bool
+	// The start time of the latest tick, in UnixNano. See Health().
+	lastTickTime int64
+	// If the channel's goroutine is locked to its own OS thread, and the CPU time of the thread at the last tick. See lockThread().
+	dedicatedThread   bool
+	lastThreadCpuTime time.Duration
+	// The metrics updated in every tick, looked up once by the channel type
+	tickDurationGauge prometheus.Gauge
+	cpuTimeCounter    prometheus.Counter
+	// How many ticks in a row have lagged, and if the lag has been reported. See checkTickLag().
+	laggedTicks int
+	tickLagging bool
+	// The primary channel of the replica, and the replicas of the primary channel. See SpawnReplica().
+	primary      *Channel
+	replicas     []*Channel
+	replicasLock sync.RWMutex
+	// See BridgeToBroker()
+	brokerBridge *brokerBridge
+	// See tickDataCheckpoint()
+	lastDataCheckpointTime time.Time
+	// The last time the channel had a subscriber, or a message from the owner. See tickIdle().
+	lastActiveTime time.Time
+	logger         *Logger
+	removing       int32
 }
 
 const (
@@ -106,6 +167,8 @@ func InitChannels() {
 	}
 
 	allChannels = xsync.NewTypedMapOf[common.ChannelId, *Channel](UintIdHasher[common.ChannelId]())
+	channelKeys = xsync.NewMapOf[common.ChannelId]()
+	quarantinedChannelIds = xsync.NewTypedMapOf[common.ChannelId, time.Time](UintIdHasher[common.ChannelId]())
 
 	nextChannelId = 0
 	nextSpatialChannelId = GlobalSettings.SpatialChannelIdStart
@@ -134,6 +197,7 @@ func InitChannels() {
 	}
 }
 
+// Doesn't check the tenant, as the tenants share the channel id space. Use GetChannelOfConnection() for the ids from the connections.
 func GetChannel(id common.ChannelId) *Channel {
 	ch, ok := allChannels.Load(id)
 	if ok {
@@ -147,26 +211,38 @@ var ErrNonSpatialChannelFull = errors.New("non-spatial channels are full")
 var ErrSpatialChannelFull = errors.New("spatial channels are full")
 var ErrEntityChannelFull = errors.New("entity channels are full")
 
-func createChannelWithId(channelId common.ChannelId, t channeldpb.ChannelType, owner ConnectionInChannel) *Channel {
+func createChannelWithId(channelId common.ChannelId, t channeldpb.ChannelType, owner ConnectionInChannel, tenant string) *Channel {
+	settings := GlobalSettings.GetTenantChannelSettings(tenant, t)
+	inboxSize := settings.InboxSize
+	if inboxSize <= 0 {
+		inboxSize = DefaultInboxSize
+	}
 	ch := &Channel{
 		id:                    channelId,
 		channelType:           t,
+		tenant:                tenant,
 		ownerConnection:       owner,
 		subscribedConnections: make(map[ConnectionInChannel]*ChannelSubscription),
 		connectionsLock:       sync.RWMutex{},
 		/* Channel data is not created by default. See handleCreateChannel().
 		data:                  ReflectChannelData(t, nil),
 		*/
-		inMsgQueue:   make(chan channelMessage, 1024),
-		fanOutQueue:  list.New(),
-		startTime:    time.Now(),
-		tickInterval: time.Duration(GlobalSettings.GetChannelSettings(t).TickIntervalMs) * time.Millisecond,
-		tickFrames:   0,
+		inMsgQueue:            make(chan channelMessage, inboxSize),
+		senderInboxQuota:      settings.SenderInboxQuota,
+		senderMessagesPerTick: settings.SenderMessagesPerTick,
+		fanOutQueue:           list.New(),
+		startTime:             time.Now(),
+		lastActiveTime:        time.Now(),
+		tickInterval:          time.Duration(settings.TickIntervalMs) * time.Millisecond,
+		handlingDeadline:      time.Duration(settings.HandlingDeadlineMs) * time.Millisecond,
+		tickFrames:            0,
 		logger: &Logger{rootLogger.With(
 			zap.String("channelType", t.String()),
 			zap.Uint32("channelId", uint32(channelId)),
 		)},
-		removing: 0,
+		removing:          0,
+		tickDurationGauge: channelTickDuration.WithLabelValues(t.String()),
+		cpuTimeCounter:    channelCpuTime.WithLabelValues(t.String()),
 	}
 
 	if ch.channelType == channeldpb.ChannelType_ENTITY {
@@ -185,28 +261,61 @@ func createChannelWithId(channelId common.ChannelId, t channeldpb.ChannelType, o
 	go ch.Tick()
 
 	channelNum.WithLabelValues(ch.channelType.String()).Inc()
+	tenantChannelNum.WithLabelValues(ch.tenant).Inc()
+	getTenantUsage(ch.tenant).addChannel(ch.channelType, 1)
 
 	Event_ChannelCreated.Broadcast(ch)
 	return ch
 }
 
-// Go-routine safe - should only be called in the GLOBAL channel
+// Go-routine safe - should only be called in the GLOBAL channel.
+// The channel belongs to the tenant of the owner. See CreateTenantChannel().
 func CreateChannel(t channeldpb.ChannelType, owner ConnectionInChannel) (*Channel, error) {
+	return CreateTenantChannel(t, owner, GetTenant(owner))
+}
+
+// Go-routine safe - should only be called in the GLOBAL channel
+// Returns *TenantQuotaError if the tenant has reached its max channels.
+func CreateTenantChannel(t channeldpb.ChannelType, owner ConnectionInChannel, tenant string) (*Channel, error) {
+	ch, err := createTenantChannel(t, owner, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < ch.settings().FanOutReplicas; i++ {
+		if _, err := ch.SpawnReplica(); err != nil {
+			ch.Logger().Warn("failed to spawn replica", zap.Error(err))
+			break
+		}
+	}
+	if topic := ch.brokerTopic(); topic != "" && getBroker() != nil {
+		if err := ch.BridgeToBroker(topic, ch.settings().BrokerInbound); err != nil {
+			ch.Logger().Warn("failed to bridge channel to broker", zap.String("topic", topic), zap.Error(err))
+		}
+	}
+	return ch, nil
+}
+
+func createTenantChannel(t channeldpb.ChannelType, owner ConnectionInChannel, tenant string) (*Channel, error) {
 	if t == channeldpb.ChannelType_GLOBAL && globalChannel != nil {
 		return nil, errors.New("failed to create GLOBAL channel as it already exists")
 	}
 
+	if quotaMsg := checkTenantChannelQuota(tenant, t); quotaMsg != nil {
+		return nil, &TenantQuotaError{quotaMsg}
+	}
+
 	var channelId common.ChannelId
 	var ok bool
 	if t == channeldpb.ChannelType_SPATIAL {
 		if spatialChannelFull {
 			return nil, ErrSpatialChannelFull
 		}
-		channelId, ok = GetNextIdTyped[common.ChannelId, *Channel](allChannels, nextSpatialChannelId, GlobalSettings.SpatialChannelIdStart, GlobalSettings.EntityChannelIdStart-1)
+		channelId, ok = GetNextIdTyped[common.ChannelId, *Channel](channelIdAllocator{}, nextSpatialChannelId, GlobalSettings.SpatialChannelIdStart, GlobalSettings.EntityChannelIdStart-1)
 		if ok {
 			nextSpatialChannelId = channelId
 		} else {
-			spatialChannelFull = true
+			spatialChannelFull = onChannelIdExhausted("spatial", ErrSpatialChannelFull)
 			return nil, ErrSpatialChannelFull
 		}
 		/* Entity channels use fixed channelId (= netId)
@@ -226,16 +335,16 @@ func CreateChannel(t channeldpb.ChannelType, owner ConnectionInChannel) (*Channe
 		if nonSpatialChannelFull {
 			return nil, ErrNonSpatialChannelFull
 		}
-		channelId, ok = GetNextIdTyped[common.ChannelId, *Channel](allChannels, nextChannelId, 1, GlobalSettings.SpatialChannelIdStart-1)
+		channelId, ok = GetNextIdTyped[common.ChannelId, *Channel](channelIdAllocator{}, nextChannelId, 1, GlobalSettings.SpatialChannelIdStart-1)
 		if ok {
 			nextChannelId = channelId
 		} else {
-			nonSpatialChannelFull = true
+			nonSpatialChannelFull = onChannelIdExhausted("non_spatial", ErrNonSpatialChannelFull)
 			return nil, ErrNonSpatialChannelFull
 		}
 	}
 
-	return createChannelWithId(channelId, t, owner), nil
+	return createChannelWithId(channelId, t, owner, tenant), nil
 }
 
 func RemoveChannel(ch *Channel) {
@@ -249,17 +358,35 @@ func RemoveChannel(ch *Channel) {
 	atomic.AddInt32(&ch.removing, 1)
 	close(ch.inMsgQueue)
 	allChannels.Delete(ch.id)
-	// Reset the channel full status cache
+	ch.removeReplicas()
+	ch.closeBrokerBridge()
+	if ch.key != "" {
+		channelKeys.Delete(tenantChannelKey(ch.tenant, ch.key))
+	}
+	// Reset the channel full status cache.
+	// If the id is quarantined, keep allocating the ids in order, so the removed ids are reused as late as possible.
 	if ch.channelType == channeldpb.ChannelType_SPATIAL {
 		spatialChannelFull = false
-		nextSpatialChannelId = ch.id
+		quarantineChannelId(ch.id)
+		if GlobalSettings.ChannelIdQuarantineMs == 0 {
+			nextSpatialChannelId = ch.id
+		}
 	} else if ch.channelType == channeldpb.ChannelType_ENTITY {
+		// Entity channels use fixed channelId (= netId), so the id is not quarantined.
 	} else {
 		nonSpatialChannelFull = false
-		nextChannelId = ch.id
+		quarantineChannelId(ch.id)
+		if GlobalSettings.ChannelIdQuarantineMs == 0 {
+			nextChannelId = ch.id
+		}
 	}
 
 	channelNum.WithLabelValues(ch.channelType.String()).Dec()
+	tenantChannelNum.WithLabelValues(ch.tenant).Dec()
+	getTenantUsage(ch.tenant).addChannel(ch.channelType, -1)
+	if usage := ch.MemoryUsage(); usage != nil {
+		channelMemoryBytes.WithLabelValues(usage.ChannelType).Sub(float64(usage.TotalBytes))
+	}
 
 	Event_ChannelRemoved.Broadcast(ch.id)
 }
@@ -280,7 +407,7 @@ func (ch *Channel) PutMessage(msg common.Message, handler MessageHandlerFunc, co
 	if ch.IsRemoving() {
 		return
 	}
-	ch.inMsgQueue <- channelMessage{ctx: MessageContext{
+	ch.tryPutMessage(channelMessage{ctx: MessageContext{
 		MsgType:     channeldpb.MessageType(pack.MsgType),
 		Msg:         msg,
 		Connection:  conn,
@@ -288,8 +415,11 @@ func (ch *Channel) PutMessage(msg common.Message, handler MessageHandlerFunc, co
 		Broadcast:   pack.Broadcast,
 		StubId:      pack.StubId,
 		ChannelId:   pack.ChannelId,
+		TraceId:     pack.TraceId,
 		arrivalTime: ch.GetTime(),
-	}, handler: handler}
+		channelKey:  pack.ChannelKey,
+		rawPayload:  pack.RawPayload,
+	}, handler: handler})
 }
 
 func (ch *Channel) PutMessageContext(ctx MessageContext, handler MessageHandlerFunc) {
@@ -337,13 +467,16 @@ func (ch *Channel) GetTime() ChannelTime {
 	return ChannelTime(time.Since(ch.startTime))
 }
 
-func (ch *Channel) Tick() {
+func (ch *Channel) tickLoop() {
+	goroutineId := ch.currentGoroutineId()
 	for {
 		if ch.IsRemoving() {
 			return
 		}
 
 		tickStart := time.Now()
+		ch.checkTickLag(tickStart)
+		atomic.StoreInt64(&ch.lastTickTime, tickStart.UnixNano())
 
 		// Run the code of SpatialController only in GLOBAL channel, to avoid any race condition.
 		if ch.channelType == channeldpb.ChannelType_GLOBAL && spatialController != nil {
@@ -352,41 +485,46 @@ func (ch *Channel) Tick() {
 
 		ch.tickFrames++
 
-		ch.tickMessages(tickStart)
+		ch.tickMessages(tickStart, goroutineId)
+
+		ch.tickScheduledBroadcasts(ch.GetTime())
+
+		ch.tickTimers(ch.GetTime())
 
+		if ch.channelType == channeldpb.ChannelType_GLOBAL {
+			ch.tickPresence()
+		}
+
+		watch := ch.watchHandling("fanOut", goroutineId, nil)
 		ch.tickData(ch.GetTime())
+		watch.done()
+
+		if ch.data != nil {
+			ch.data.takeSnapshot(ch.GetTime())
+		}
 
 		ch.tickConnections()
 
-		tickDuration := time.Since(tickStart)
-		channelTickDuration.WithLabelValues(ch.channelType.String()).Set(float64(tickDuration) / float64(time.Millisecond))
+		ch.tickOwner(time.Now())
 
-		time.Sleep(ch.tickInterval - tickDuration)
-	}
-}
+		ch.tickIdle(time.Now())
 
-func (ch *Channel) tickMessages(tickStart time.Time) {
-	for len(ch.inMsgQueue) > 0 {
-		cm := <-ch.inMsgQueue
+		ch.tickStats(time.Now())
 
-		// No message in the context, just execute the handler.
-		if cm.ctx.Msg == nil {
-			cm.handler(cm.ctx)
-			continue
-		}
+		ch.tickDataCheckpoint(time.Now())
 
-		if cm.ctx.Connection == nil {
-			ch.Logger().Warn("drops message as the sender is lost", zap.Uint32("msgType", uint32(cm.ctx.MsgType)))
-			continue
-		}
-		cm.handler(cm.ctx)
-		if ch.tickInterval > 0 && time.Since(tickStart) >= ch.tickInterval {
-			ch.Logger().Warn("spent too long handling messages, will delay the left to the next tick",
-				zap.Duration("duration", time.Since(tickStart)),
-				zap.Int("remaining", len(ch.inMsgQueue)),
-			)
-			break
+		if time.Since(ch.lastMemoryUsageSampleTime) >= memoryUsageSampleInterval {
+			ch.sampleMemoryUsage()
+			if ch.channelType == channeldpb.ChannelType_GLOBAL {
+				sampleConnectionsMemoryUsage()
+			}
 		}
+
+		tickDuration := time.Since(tickStart)
+		ch.tickDurationGauge.Set(float64(tickDuration) / float64(time.Millisecond))
+		ch.accountCpuTime(tickDuration)
+
+		time.Sleep(ch.tickSleep(tickDuration))
 	}
 }
 
@@ -396,7 +534,7 @@ func (ch *Channel) tickConnections() {
 	}()
 	ch.connectionsLock.RLock()
 
-	for conn := range ch.subscribedConnections {
+	for conn, cs := range ch.subscribedConnections {
 		if conn.IsClosing() {
 			// Unsub the connection from the channel
 			delete(ch.subscribedConnections, conn)
@@ -409,7 +547,7 @@ func (ch *Channel) tickConnections() {
 						Event_GlobalChannelUnpossessed.Broadcast(struct{}{})
 					}
 					conn.Logger().Info("found removed ownner connection of channel", zap.Uint32("channelId", uint32(ch.id)))
-					if GlobalSettings.GetChannelSettings(ch.channelType).RemoveChannelAfterOwnerRemoved {
+					if policy := ch.settings().ownerDisconnectPolicy(); policy == OwnerDisconnectPolicy_Destroy {
 						atomic.AddInt32(&ch.removing, 1)
 						/* Let the GLOBAL channel handles the channel remove
 						// Send RemoveChannelMessage to all subscribed connections
@@ -434,9 +572,14 @@ func (ch *Channel) tickConnections() {
 
 						ch.Logger().Info("removing channel after the owner is removed")
 						return
+					} else if policy != OwnerDisconnectPolicy_Keep && ch.channelType != channeldpb.ChannelType_GLOBAL {
+						// See tickOwner()
+						ch.disconnectedOwner = ownerConn
+						ch.previousOwnerOptions = &cs.options
 					}
 				} else if conn != nil {
 					ch.ownerConnection.sendUnsubscribed(MessageContext{}, ch, conn.(*Connection), 0)
+					ch.sendDisconnected(conn.(*Connection))
 				}
 			}
 		}
@@ -454,22 +597,29 @@ func (ch *Channel) Broadcast(ctx MessageContext) {
 		if conn == nil {
 			continue
 		}
-		if channeldpb.BroadcastType_ALL_BUT_SENDER.Check(ctx.Broadcast) && conn == ctx.Connection {
-			continue
-		}
-		if channeldpb.BroadcastType_ALL_BUT_OWNER.Check(ctx.Broadcast) && conn == ch.ownerConnection {
-			continue
-		}
-		if channeldpb.BroadcastType_ALL_BUT_CLIENT.Check(ctx.Broadcast) && conn.GetConnectionType() == channeldpb.ConnectionType_CLIENT {
-			continue
-		}
-		if channeldpb.BroadcastType_ALL_BUT_SERVER.Check(ctx.Broadcast) && conn.GetConnectionType() == channeldpb.ConnectionType_SERVER {
+		if !ch.shouldBroadcastTo(ctx, conn) {
 			continue
 		}
 		conn.Send(ctx)
 	}
 }
 
+func (ch *Channel) shouldBroadcastTo(ctx MessageContext, conn ConnectionInChannel) bool {
+	if channeldpb.BroadcastType_ALL_BUT_SENDER.Check(ctx.Broadcast) && conn == ctx.Connection {
+		return false
+	}
+	if channeldpb.BroadcastType_ALL_BUT_OWNER.Check(ctx.Broadcast) && conn == ch.ownerConnection {
+		return false
+	}
+	if channeldpb.BroadcastType_ALL_BUT_CLIENT.Check(ctx.Broadcast) && conn.GetConnectionType() == channeldpb.ConnectionType_CLIENT {
+		return false
+	}
+	if channeldpb.BroadcastType_ALL_BUT_SERVER.Check(ctx.Broadcast) && conn.GetConnectionType() == channeldpb.ConnectionType_SERVER {
+		return false
+	}
+	return ch.scriptBroadcastFilter(ctx, conn)
+}
+
 // Goroutine-safe read of the subscribed connections
 func (ch *Channel) GetAllConnections() map[ConnectionInChannel]struct{} {
 	defer func() {
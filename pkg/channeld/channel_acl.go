@@ -26,15 +26,32 @@ var (
 	ErrOwnerOnlyAccess           = errors.New("only the channel owenr can access")
 	ErrOwnerAndGlobalOwnerAccess = errors.New("only the channel owenr or global channel owner can access")
 	ErrIllegalAccessLevel        = errors.New("illegal channel access level")
+	ErrRequiredTagsMismatch      = errors.New("connection doesn't have the required tags")
+	ErrTenantMismatch            = errors.New("connection is not in the tenant of the channel")
 )
 
+// Checks if the connection has the tags required by ACLSettings.SubRequiredTags to subscribe to the channel.
+// The connection must also be in the same tenant as the channel. See TenantTag.
+func (ch *Channel) CheckRequiredTags(c ConnectionInChannel) error {
+	if !ch.IsVisibleTo(c) {
+		return ErrTenantMismatch
+	}
+	channelSettings, exists := ch.settingsOfType()
+	if !exists {
+		return nil
+	}
+	if !MatchTags(c, channelSettings.ACLSettings.SubRequiredTags) {
+		return ErrRequiredTagsMismatch
+	}
+	return nil
+}
+
 func (ch *Channel) CheckACL(c ConnectionInChannel, accessType ChannelAccessType) (bool, error) {
 	// default level is none
 	level := ChannelAccessLevel_None
 
-	// get acl from global setting
-	channelSettings, exists := GlobalSettings.ChannelSettings[ch.channelType]
-	if exists {
+	// get acl from global setting, or the tenant's
+	if channelSettings, exists := ch.settingsOfType(); exists {
 		aclSettings := channelSettings.ACLSettings
 		switch accessType {
 		case ChannelAccessType_Sub:
@@ -42,7 +42,7 @@ func (c *aclTestConnection) Send(ctx MessageContext) {
 
 func (c *aclTestConnection) SubscribeToChannel(ch *Channel, options *channeldpb.ChannelSubscriptionOptions) (*ChannelSubscription, bool) {
 	return &ChannelSubscription{
-		options: *defaultSubOptions(ch.channelType),
+		options: *defaultSubOptions(ch),
 	}, false
 }
 
@@ -62,6 +62,10 @@ func (c *aclTestConnection) HasInterestIn(spatialChId common.ChannelId) bool {
 	return false
 }
 
+func (c *aclTestConnection) GetTag(key string) (string, bool) {
+	return "", false
+}
+
 func (c *aclTestConnection) Logger() *Logger {
 	return rootLogger
 }
@@ -111,6 +115,32 @@ func TestCheckACL(t *testing.T) {
 	InitLogs()
 	InitChannels()
 
+	// The test replaces the settings and the GLOBAL channel. Restore them and remove the created channels afterwards, so
+	// the following tests don't run with the zero tick and fan-out intervals.
+	existingChannels := make(map[common.ChannelId]bool)
+	allChannels.Range(func(id common.ChannelId, _ *Channel) bool {
+		existingChannels[id] = true
+		return true
+	})
+	channelSettings := make(map[channeldpb.ChannelType]ChannelSettingsType, len(GlobalSettings.ChannelSettings))
+	for chType, settings := range GlobalSettings.ChannelSettings {
+		channelSettings[chType] = settings
+	}
+	defer func(global *Channel) {
+		var created []*Channel
+		allChannels.Range(func(id common.ChannelId, ch *Channel) bool {
+			if !existingChannels[id] {
+				created = append(created, ch)
+			}
+			return true
+		})
+		for _, ch := range created {
+			RemoveChannel(ch)
+		}
+		globalChannel = global
+		GlobalSettings.ChannelSettings = channelSettings
+	}(globalChannel)
+
 	accessTypes := []ChannelAccessType{ChannelAccessType_Sub, ChannelAccessType_Unsub, ChannelAccessType_Remove}
 
 	const ChannelType_Test1 channeldpb.ChannelType = 201
@@ -0,0 +1,275 @@
+package channeld
+
+import (
+	"sync"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultFetchArchiveLimit = 20
+	MaxFetchArchiveLimit     = 100
+)
+
+// Saves the user-space broadcasts of the channels that have ChannelSettingsType.ArchiveBroadcasts enabled,
+// e.g. the chat channels. See the archive package for the SQLite implementation.
+type MessageArchive interface {
+	// Saves the message. The id of the message is assigned by the archive. Called in the channel's goroutine, so it shouldn't block.
+	Archive(msg *channeldpb.ArchivedMessage)
+	// Returns the archived messages that match the query, in the order of being archived, and true if there are more.
+	// The messages passed to Archive() before the call should be included.
+	Query(query ArchiveQuery) ([]*channeldpb.ArchivedMessage, bool, error)
+}
+
+type ArchiveQuery struct {
+	Tenant    string
+	ChannelId common.ChannelId
+	// If set, the messages are matched by the key instead of the ChannelId, so the messages of the previous channels with the key are included.
+	ChannelKey string
+	// In unix milliseconds. ToTime = 0 means no upper bound.
+	FromTime int64
+	ToTime   int64
+	// The messages with id greater than the value.
+	AfterId uint64
+	// The messages with seq greater than the value.
+	AfterSeq uint64
+	// The max number of the messages. 0 means no limit.
+	Limit int
+}
+
+// Archives the messages in memory. Mostly for the development and the tests.
+type MemoryMessageArchive struct {
+	lock     sync.RWMutex
+	messages []*channeldpb.ArchivedMessage
+}
+
+func NewMemoryMessageArchive() *MemoryMessageArchive {
+	return &MemoryMessageArchive{}
+}
+
+func (a *MemoryMessageArchive) Archive(msg *channeldpb.ArchivedMessage) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	msg.Id = uint64(len(a.messages) + 1)
+	a.messages = append(a.messages, msg)
+}
+
+func (a *MemoryMessageArchive) Query(query ArchiveQuery) ([]*channeldpb.ArchivedMessage, bool, error) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	var result []*channeldpb.ArchivedMessage
+	for _, msg := range a.messages {
+		if !query.Match(msg) {
+			continue
+		}
+		if query.Limit > 0 && len(result) == query.Limit {
+			return result, true, nil
+		}
+		result = append(result, msg)
+	}
+	return result, false, nil
+}
+
+// Returns true if the archived message matches the query, regardless of the Limit.
+func (q *ArchiveQuery) Match(msg *channeldpb.ArchivedMessage) bool {
+	if msg.Tenant != q.Tenant || msg.Id <= q.AfterId || msg.Seq <= q.AfterSeq || msg.Timestamp < q.FromTime {
+		return false
+	}
+	if q.ToTime > 0 && msg.Timestamp >= q.ToTime {
+		return false
+	}
+	if q.ChannelKey != "" {
+		return msg.ChannelKey == q.ChannelKey
+	}
+	return msg.ChannelId == uint32(q.ChannelId)
+}
+
+var messageArchive MessageArchive
+
+func SetMessageArchive(value MessageArchive) {
+	messageArchive = value
+}
+
+func (ch *Channel) isArchivingBroadcasts() bool {
+	return messageArchive != nil && ch.settings().ArchiveBroadcasts
+}
+
+// Returns the query of the messages archived by the channel. As the channelId can be reused after the channel is removed,
+// the messages archived before the channel is created are excluded, unless the channel has a key.
+func (ch *Channel) archiveQuery() ArchiveQuery {
+	query := ArchiveQuery{Tenant: ch.tenant, ChannelId: ch.id, ChannelKey: ch.key}
+	if ch.key == "" {
+		query.FromTime = ch.startTime.UnixMilli()
+	}
+	return query
+}
+
+// Should be called in the channel's goroutine.
+func (ch *Channel) archiveBroadcast(ctx MessageContext, msg *channeldpb.ServerForwardMessage, tagFilter string, senderUserId string) {
+	messageArchive.Archive(&channeldpb.ArchivedMessage{
+		ChannelId:    uint32(ch.id),
+		ChannelKey:   ch.key,
+		Seq:          msg.Seq,
+		SenderConnId: uint32(ctx.Connection.Id()),
+		SenderUserId: senderUserId,
+		Timestamp:    time.Now().UnixMilli(),
+		MsgType:      uint32(ctx.MsgType),
+		Payload:      msg.Payload,
+		Tenant:       ch.tenant,
+		Broadcast:    ctx.Broadcast,
+		TagFilter:    tagFilter,
+		ClientConnId: msg.ClientConnId,
+		RawPayload:   ctx.rawPayload,
+	})
+}
+
+// Returns true if the connection would have received the archived broadcast. See Channel.shouldBroadcastTo().
+func archivedMessageVisibleTo(msg *channeldpb.ArchivedMessage, conn ConnectionInChannel, owner ConnectionInChannel) bool {
+	if channeldpb.BroadcastType_ALL_BUT_SENDER.Check(msg.Broadcast) && uint32(conn.Id()) == msg.SenderConnId {
+		return false
+	}
+	if channeldpb.BroadcastType_ALL_BUT_OWNER.Check(msg.Broadcast) && conn == owner {
+		return false
+	}
+	if channeldpb.BroadcastType_ALL_BUT_CLIENT.Check(msg.Broadcast) && conn.GetConnectionType() == channeldpb.ConnectionType_CLIENT {
+		return false
+	}
+	if channeldpb.BroadcastType_ALL_BUT_SERVER.Check(msg.Broadcast) && conn.GetConnectionType() == channeldpb.ConnectionType_SERVER {
+		return false
+	}
+	if isBlockedBy(conn, msg.SenderUserId) {
+		return false
+	}
+	if msg.TagFilter != "" {
+		filter, err := ParseTagFilter(msg.TagFilter)
+		if err != nil || !filter.Match(conn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Queries the archive page by page, until the limit of the visible messages is reached. 0 means no limit.
+func queryVisibleArchivedMessages(query ArchiveQuery, limit int, visible func(*channeldpb.ArchivedMessage) bool) ([]*channeldpb.ArchivedMessage, bool, error) {
+	var result []*channeldpb.ArchivedMessage
+	query.Limit = limit
+	for {
+		msgs, hasMore, err := messageArchive.Query(query)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, msg := range msgs {
+			query.AfterId = msg.Id
+			if !visible(msg) {
+				continue
+			}
+			if limit > 0 && len(result) == limit {
+				return result, true, nil
+			}
+			result = append(result, msg)
+		}
+		if !hasMore || len(msgs) == 0 {
+			return result, false, nil
+		}
+	}
+}
+
+// Sends the archived broadcasts of the channel with seq greater than fromSeq to the connection, in order.
+// Unlike the retained broadcasts, the replay is not bounded by the size. Should be called in the channel's goroutine.
+func (ch *Channel) replayArchivedBroadcasts(conn ConnectionInChannel, fromSeq uint64) {
+	// The seq restarts from 1 in every channel, so the key is not used in the query.
+	query := ArchiveQuery{Tenant: ch.tenant, ChannelId: ch.id, FromTime: ch.startTime.UnixMilli(), AfterSeq: fromSeq}
+	owner := ch.ownerConnection
+	msgs, _, err := queryVisibleArchivedMessages(query, 0, func(msg *channeldpb.ArchivedMessage) bool {
+		return archivedMessageVisibleTo(msg, conn, owner)
+	})
+	if err != nil {
+		conn.Logger().Error("failed to query the archived broadcasts to replay",
+			zap.Uint32("channelId", uint32(ch.id)),
+			zap.Uint64("resumeFromSeq", fromSeq),
+			zap.Error(err),
+		)
+		return
+	}
+
+	for _, msg := range msgs {
+		conn.Send(MessageContext{
+			MsgType:    channeldpb.MessageType(msg.MsgType),
+			Msg:        &channeldpb.ServerForwardMessage{ClientConnId: msg.ClientConnId, Payload: msg.Payload, Seq: msg.Seq},
+			Broadcast:  msg.Broadcast,
+			ChannelId:  uint32(ch.id),
+			Channel:    ch,
+			rawPayload: msg.RawPayload,
+		})
+	}
+
+	conn.Logger().Debug("replayed archived broadcasts",
+		zap.Uint32("channelId", uint32(ch.id)),
+		zap.Uint64("resumeFromSeq", fromSeq),
+		zap.Int("replayed", len(msgs)),
+	)
+}
+
+// Runs the archive queries of FETCH_ARCHIVE, in a new goroutine by default. Replaced in the tests to run synchronously.
+var runArchiveQuery = func(query func()) {
+	go query()
+}
+
+func handleFetchArchive(ctx MessageContext) {
+	msg, ok := ctx.Msg.(*channeldpb.FetchArchiveMessage)
+	if !ok {
+		ctx.Connection.Logger().Error("message is not a FetchArchiveMessage, will not be handled.")
+		return
+	}
+
+	if ctx.Channel.ownerConnection != ctx.Connection {
+		if _, subscribed := ctx.Channel.subscribedConnections[ctx.Connection]; !subscribed {
+			ctx.Connection.Logger().Warn("attempt to fetch archive but not subscribed to the channel",
+				zap.Uint32("channelId", uint32(ctx.Channel.id)),
+			)
+			return
+		}
+	}
+
+	if !ctx.Channel.isArchivingBroadcasts() {
+		ctx.Connection.Logger().Warn("the channel doesn't archive the broadcasts, will not fetch archive",
+			zap.Uint32("channelId", uint32(ctx.Channel.id)),
+			zap.String("channelType", ctx.Channel.channelType.String()),
+		)
+		return
+	}
+
+	limit := int(msg.Limit)
+	if limit == 0 {
+		limit = DefaultFetchArchiveLimit
+	} else if limit > MaxFetchArchiveLimit {
+		limit = MaxFetchArchiveLimit
+	}
+
+	query := ctx.Channel.archiveQuery()
+	if msg.FromTime > query.FromTime {
+		query.FromTime = msg.FromTime
+	}
+	query.ToTime = msg.ToTime
+	query.AfterId = msg.AfterId
+
+	// Don't block the channel's goroutine while querying the archive
+	owner := ctx.Channel.ownerConnection
+	runArchiveQuery(func() {
+		msgs, hasMore, err := queryVisibleArchivedMessages(query, limit, func(archived *channeldpb.ArchivedMessage) bool {
+			return archivedMessageVisibleTo(archived, ctx.Connection, owner)
+		})
+		if err != nil {
+			ctx.Connection.Logger().Error("failed to query the archive",
+				zap.Uint32("channelId", uint32(query.ChannelId)),
+				zap.Error(err),
+			)
+			return
+		}
+		ctx.Msg = &channeldpb.FetchArchiveResultMessage{Messages: msgs, HasMore: hasMore}
+		ctx.Connection.Send(ctx)
+	})
+}
@@ -0,0 +1,126 @@
+package channeld
+
+import (
+	"testing"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestArchiveBroadcasts(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	archive := NewMemoryMessageArchive()
+	SetMessageArchive(archive)
+	defer SetMessageArchive(nil)
+	// Query the archive in the test's goroutine
+	defer func(run func(func())) { runArchiveQuery = run }(runArchiveQuery)
+	runArchiveQuery = func(query func()) { query() }
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		// Ignored as the archive replaces the retained broadcasts
+		BroadcastRetentionSize: 1,
+		ArchiveBroadcasts:      true,
+		ACLSettings:            ACLSettingsType{Sub: ChannelAccessLevel_Any},
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	client.SetTag(UserIdTag, "user1")
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	defer RemoveChannel(ch)
+	ch.enableClientBroadcast = true
+
+	for i := 0; i < 3; i++ {
+		HandleServerToClientUserMessage(MessageContext{
+			MsgType:    channeldpb.MessageType_USER_SPACE_START,
+			Msg:        &channeldpb.ServerForwardMessage{Payload: []byte{byte(i)}},
+			Broadcast:  uint32(channeldpb.BroadcastType_ALL),
+			Connection: server,
+			Channel:    ch,
+		})
+	}
+	// Only the connections with the tag can fetch or replay it
+	HandleServerToClientUserMessage(MessageContext{
+		MsgType:    channeldpb.MessageType_USER_SPACE_START,
+		Msg:        &channeldpb.ServerForwardMessage{Payload: []byte{3}, TagFilter: `team == "red"`},
+		Broadcast:  uint32(channeldpb.BroadcastType_ALL),
+		Connection: server,
+		Channel:    ch,
+	})
+	assert.Nil(t, ch.retainedBroadcasts)
+	assert.Len(t, archive.messages, 4)
+	assert.EqualValues(t, 4, archive.messages[3].Seq)
+	assert.EqualValues(t, server.Id(), archive.messages[0].SenderConnId)
+	assert.Equal(t, `team == "red"`, archive.messages[3].TagFilter)
+
+	// The client resumes from seq 1
+	handleSubToChannel(MessageContext{
+		MsgType:    channeldpb.MessageType_SUB_TO_CHANNEL,
+		Msg:        &channeldpb.SubscribedToChannelMessage{ConnId: uint32(client.Id()), ResumeFromSeq: proto.Uint64(1)},
+		Connection: client,
+		Channel:    ch,
+	})
+	var seqs []uint64
+	for _, msg := range client.testQueue() {
+		if fwd, ok := msg.(*channeldpb.ServerForwardMessage); ok {
+			seqs = append(seqs, fwd.Seq)
+		}
+	}
+	assert.Equal(t, []uint64{2, 3}, seqs)
+
+	// The client's broadcast has the sender's user id. Only the channel without the owner accepts the client's broadcast.
+	ch.ownerConnection = nil
+	handleClientToServerUserMessage(MessageContext{
+		MsgType:    channeldpb.MessageType_USER_SPACE_START,
+		Msg:        &channeldpb.ServerForwardMessage{ClientConnId: uint32(client.Id()), Payload: []byte{4}},
+		Broadcast:  uint32(channeldpb.BroadcastType_ALL_BUT_SENDER),
+		Connection: client,
+		Channel:    ch,
+	})
+	ch.ownerConnection = server
+	assert.Equal(t, "user1", archive.messages[4].SenderUserId)
+	assert.EqualValues(t, 5, archive.messages[4].Seq)
+
+	fetch := func(msg *channeldpb.FetchArchiveMessage) *channeldpb.FetchArchiveResultMessage {
+		handleFetchArchive(MessageContext{
+			MsgType:    channeldpb.MessageType_FETCH_ARCHIVE,
+			Msg:        msg,
+			Connection: server,
+			Channel:    ch,
+		})
+		result, ok := server.latestMsg().(*channeldpb.FetchArchiveResultMessage)
+		if !assert.True(t, ok) {
+			t.FailNow()
+		}
+		server.testQueue()
+		return result
+	}
+
+	// The owner can't see the message with the tag filter
+	result := fetch(&channeldpb.FetchArchiveMessage{Limit: 2})
+	assert.True(t, result.HasMore)
+	assert.Len(t, result.Messages, 2)
+	assert.EqualValues(t, []byte{0}, result.Messages[0].Payload)
+	result = fetch(&channeldpb.FetchArchiveMessage{AfterId: result.Messages[1].Id, Limit: 2})
+	assert.False(t, result.HasMore)
+	assert.Len(t, result.Messages, 2)
+	assert.EqualValues(t, []byte{2}, result.Messages[0].Payload)
+	assert.EqualValues(t, []byte{4}, result.Messages[1].Payload)
+}
+
+func TestArchiveQueryMatch(t *testing.T) {
+	msg := &channeldpb.ArchivedMessage{Id: 5, ChannelId: 100, ChannelKey: "lobby", Seq: 3, Timestamp: 1000}
+	assert.True(t, (&ArchiveQuery{ChannelId: 100}).Match(msg))
+	assert.False(t, (&ArchiveQuery{ChannelId: 101}).Match(msg))
+	// The key takes precedence over the channel id
+	assert.True(t, (&ArchiveQuery{ChannelId: 101, ChannelKey: "lobby"}).Match(msg))
+	assert.False(t, (&ArchiveQuery{ChannelId: 100, Tenant: "t1"}).Match(msg))
+	assert.False(t, (&ArchiveQuery{ChannelId: 100, AfterId: 5}).Match(msg))
+	assert.False(t, (&ArchiveQuery{ChannelId: 100, AfterSeq: 3}).Match(msg))
+	assert.True(t, (&ArchiveQuery{ChannelId: 100, FromTime: 1000, ToTime: 1001}).Match(msg))
+	assert.False(t, (&ArchiveQuery{ChannelId: 100, ToTime: 1000}).Match(msg))
+}
@@ -0,0 +1,152 @@
+package channeld
+
+import (
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"go.uber.org/zap"
+)
+
+type ChannelOwnerPolicy uint8
+
+const (
+	// The auto-created channel has no owner.
+	ChannelOwnerPolicy_None ChannelOwnerPolicy = 0
+	// The first subscribed connection owns the auto-created channel.
+	ChannelOwnerPolicy_Subscriber ChannelOwnerPolicy = 1
+	// The GLOBAL channel owner (usually the master server) owns the auto-created channel.
+	ChannelOwnerPolicy_GlobalOwner ChannelOwnerPolicy = 2
+	// The server picked by consistent hashing of the channel key (or the channelId if there's no key) owns the auto-created channel,
+	// so the same channel goes to the same server across the server restarts and scale events. When the owner disconnects,
+	// OwnerDisconnectPolicy_Transfer transfers the ownership to the next server on the hash ring.
+	ChannelOwnerPolicy_ConsistentHash ChannelOwnerPolicy = 3
+)
+
+// If channelKey is not empty, the channelId will be allocated instead of using the given one.
+func canAutoCreateChannel(channelId common.ChannelId, channelKey string, channelType channeldpb.ChannelType, tenant string) bool {
+	switch channelType {
+	// Spatial and entity channels have their own creation process
+	case channeldpb.ChannelType_UNKNOWN, channeldpb.ChannelType_GLOBAL, channeldpb.ChannelType_SPATIAL, channeldpb.ChannelType_ENTITY:
+		return false
+	}
+
+	if channelKey == "" {
+		if channelId == GlobalChannelId || channelId >= GlobalSettings.SpatialChannelIdStart {
+			return false
+		}
+		// The late subscription to a removed channel shouldn't create the channel again.
+		if _, inUse := (channelIdAllocator{}).Load(channelId); inUse {
+			return false
+		}
+	}
+
+	return GlobalSettings.GetTenantChannelSettings(tenant, channelType).AutoCreateOnSub
+}
+
+// Handles the SubscribedToChannelMessage that targets a non-existing channel, in the GLOBAL channel's goroutine.
+// If the channel type allows, the channel is created and the message is passed to the new channel.
+func handleSubToAutoCreatedChannel(ctx MessageContext) {
+	msg, ok := ctx.Msg.(*channeldpb.SubscribedToChannelMessage)
+	if !ok {
+		ctx.Connection.Logger().Error("message is not a SubscribedToChannelMessage, will not be handled.")
+		return
+	}
+
+	channelId := common.ChannelId(ctx.ChannelId)
+	// The channel is created in the tenant of the sender.
+	tenant := GetTenant(ctx.Connection)
+	// The channel may be created by a previous subscription.
+	var ch *Channel
+	if ctx.channelKey != "" {
+		ch = GetTenantChannelByKey(tenant, ctx.channelKey)
+	} else {
+		ch = GetChannelOfConnection(channelId, ctx.Connection)
+	}
+	if ch == nil {
+		if !canAutoCreateChannel(channelId, ctx.channelKey, msg.ChannelType, tenant) {
+			ctx.Connection.Logger().Warn("can't find channel",
+				zap.Uint32("channelId", ctx.ChannelId),
+				zap.String("channelKey", ctx.channelKey),
+				zap.Uint32("msgType", uint32(ctx.MsgType)),
+			)
+			return
+		}
+
+		var subConn ConnectionInChannel = ctx.Connection
+		if ctx.Connection.GetConnectionType() == channeldpb.ConnectionType_SERVER && msg.ConnId != 0 {
+			if conn := GetConnectionOfConnection(ConnectionId(msg.ConnId), ctx.Connection); conn != nil {
+				subConn = conn
+			}
+		}
+
+		if quotaMsg := checkTenantChannelQuota(tenant, msg.ChannelType); quotaMsg != nil {
+			ctx.Connection.Logger().Warn("failed to auto-create channel as the tenant's quota is hit",
+				zap.Uint32("channelType", uint32(msg.ChannelType)),
+				zap.Stringer("quotaType", quotaMsg.QuotaType),
+			)
+			if conn, ok := ctx.Connection.(*Connection); ok {
+				conn.sendQuotaExceeded(quotaMsg)
+			}
+			return
+		}
+
+		var owner ConnectionInChannel
+		switch GlobalSettings.GetTenantChannelSettings(tenant, msg.ChannelType).AutoCreateOwnerPolicy {
+		case ChannelOwnerPolicy_Subscriber:
+			owner = subConn
+		case ChannelOwnerPolicy_GlobalOwner:
+			owner = globalChannel.ownerConnection
+		case ChannelOwnerPolicy_ConsistentHash:
+			if hashOwner := ConsistentHashOwner(tenant, msg.ChannelType, channelId, ctx.channelKey); hashOwner != nil {
+				owner = hashOwner
+			}
+		}
+
+		if ctx.channelKey != "" {
+			var err error
+			ch, err = CreateTenantChannel(msg.ChannelType, owner, tenant)
+			if err != nil {
+				ctx.Connection.Logger().Error("failed to auto-create channel",
+					zap.Uint32("channelType", uint32(msg.ChannelType)),
+					zap.Error(err),
+				)
+				return
+			}
+			if err := ch.setKey(ctx.channelKey); err != nil {
+				ctx.Connection.Logger().Error("failed to set the channel key", zap.String("channelKey", ctx.channelKey), zap.Error(err))
+				RemoveChannel(ch)
+				return
+			}
+		} else {
+			ch = createChannelWithId(channelId, msg.ChannelType, owner, tenant)
+		}
+		// Channel data should always be initialized
+		ch.InitData(nil, nil)
+		ch.Logger().Info("auto-created channel on the first subscription", zap.Uint32("subConnId", uint32(subConn.Id())))
+
+		var ownerConnId uint32
+		if ch.HasOwner() {
+			ownerConnId = uint32(ch.ownerConnection.Id())
+		}
+		resultCtx := MessageContext{
+			MsgType: channeldpb.MessageType_CREATE_CHANNEL,
+			Msg: &channeldpb.CreateChannelResultMessage{
+				ChannelType: ch.channelType,
+				OwnerConnId: ownerConnId,
+				ChannelId:   uint32(ch.id),
+				ChannelKey:  ch.key,
+			},
+			ChannelId: uint32(GlobalChannelId),
+		}
+		if globalChannel.HasOwner() {
+			globalChannel.ownerConnection.Send(resultCtx)
+		}
+		// The server picked by consistent hashing learns that it owns the channel
+		if ch.HasOwner() && ch.ownerConnection != globalChannel.ownerConnection && ch.ownerConnection != subConn {
+			ch.ownerConnection.Send(resultCtx)
+		}
+	}
+
+	ctx.Channel = ch
+	ctx.ChannelId = uint32(ch.id)
+	ch.PutMessageContext(ctx, handleSubToChannel)
+}
@@ -0,0 +1,67 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoCreateChannelOnSub(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	client1 := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	client2 := addTestConnection(channeldpb.ConnectionType_CLIENT)
+
+	const channelId = 1234
+	subCtx := MessageContext{
+		MsgType:    channeldpb.MessageType_SUB_TO_CHANNEL,
+		Msg:        &channeldpb.SubscribedToChannelMessage{ChannelType: channeldpb.ChannelType_TEST},
+		Connection: client1,
+		Channel:    globalChannel,
+		ChannelId:  channelId,
+	}
+
+	// Auto-creation is not enabled for the channel type
+	handleSubToAutoCreatedChannel(subCtx)
+	assert.Nil(t, GetChannel(channelId))
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		// The queued subscriptions are handled by tickMessages() below
+		TickIntervalMs:        3600000,
+		AutoCreateOnSub:       true,
+		AutoCreateOwnerPolicy: ChannelOwnerPolicy_Subscriber,
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	// The GLOBAL channel and spatial channels can't be auto-created
+	assert.False(t, canAutoCreateChannel(GlobalChannelId, "", channeldpb.ChannelType_TEST, ""))
+	assert.False(t, canAutoCreateChannel(GlobalSettings.SpatialChannelIdStart, "", channeldpb.ChannelType_TEST, ""))
+	assert.False(t, canAutoCreateChannel(channelId, "", channeldpb.ChannelType_SPATIAL, ""))
+
+	handleSubToAutoCreatedChannel(subCtx)
+	ch := GetChannel(channelId)
+	if !assert.NotNil(t, ch) {
+		return
+	}
+	defer RemoveChannel(ch)
+	assert.Equal(t, channeldpb.ChannelType_TEST, ch.Type())
+	assert.Equal(t, client1, ch.ownerConnection)
+	assert.NotNil(t, ch.Data())
+	// Wait for the first tick, after which the channel.Tick() goroutine sleeps for an hour
+	time.Sleep(50 * time.Millisecond)
+	ch.tickMessages(time.Now(), "")
+	assert.Contains(t, ch.GetAllConnections(), client1)
+
+	// The second subscription goes to the existing channel
+	subCtx.Connection = client2
+	handleSubToAutoCreatedChannel(subCtx)
+	assert.Same(t, ch, GetChannel(common.ChannelId(channelId)))
+	assert.Equal(t, client1, ch.ownerConnection)
+	ch.tickMessages(time.Now(), "")
+	assert.Contains(t, ch.GetAllConnections(), client2)
+}
@@ -0,0 +1,289 @@
+package channeld
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// The connId of the broker bridge in the channel. The updates injected from the broker are merged with it,
+	// so they are not published back (the bridge subscribes with SkipSelfUpdateFanOut).
+	brokerBridgeConnId ConnectionId = math.MaxUint32
+	// The messages are dropped when the queue is full, so the broker never slows down the channel.
+	brokerQueueSize = 1024
+)
+
+var ErrNoBroker = errors.New("the broker is not set")
+var ErrAlreadyBridged = errors.New("the channel is already bridged to a broker topic")
+
+// The adapter of the external broker, e.g. NATS or Redis Streams, that the channels are bridged to.
+// The payload is a marshalled channeldpb.BrokerMessage.
+type Broker interface {
+	// Called in the publishing goroutine of the bridge. The message is dropped if an error is returned.
+	Publish(topic string, payload []byte) error
+	// The handler can be called in any goroutine. The returned function cancels the subscription.
+	Subscribe(topic string, handler func(payload []byte)) (func(), error)
+}
+
+var broker Broker
+var brokerLock sync.RWMutex
+
+// Identifies the messages published by this channeld instance, so they are not injected back.
+var brokerOrigin = fmt.Sprintf("%d-%d", os.Getpid(), time.Now().UnixNano())
+
+// Sets the broker that the channels are bridged to. See ChannelSettingsType.BrokerTopic.
+func SetBroker(value Broker) {
+	brokerLock.Lock()
+	defer brokerLock.Unlock()
+	broker = value
+}
+
+func getBroker() Broker {
+	brokerLock.RLock()
+	defer brokerLock.RUnlock()
+	return broker
+}
+
+// The in-process broker, e.g. for testing the bridge or bridging the channels in the same channeld instance.
+type MemoryBroker struct {
+	lock     sync.RWMutex
+	handlers map[string]map[int]func(payload []byte)
+	nextId   int
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{handlers: make(map[string]map[int]func(payload []byte))}
+}
+
+func (b *MemoryBroker) Publish(topic string, payload []byte) error {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	for _, handler := range b.handlers[topic] {
+		handler(payload)
+	}
+	return nil
+}
+
+func (b *MemoryBroker) Subscribe(topic string, handler func(payload []byte)) (func(), error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.handlers[topic] == nil {
+		b.handlers[topic] = make(map[int]func(payload []byte))
+	}
+	id := b.nextId
+	b.nextId++
+	b.handlers[topic][id] = handler
+	return func() {
+		b.lock.Lock()
+		defer b.lock.Unlock()
+		delete(b.handlers[topic], id)
+	}, nil
+}
+
+// The subscriber of the channel that publishes the data updates and the user-space messages to the broker topic,
+// and optionally injects the messages of the topic back into the channel.
+type brokerBridge struct {
+	internalConnection
+	ch          *Channel
+	broker      Broker
+	topic       string
+	queue       chan []byte
+	closed      chan struct{}
+	unsubscribe func()
+}
+
+// Returns the topic in the settings, with "{channelId}" replaced by the id of the channel.
+func (ch *Channel) brokerTopic() string {
+	return strings.ReplaceAll(ch.settings().BrokerTopic, "{channelId}", strconv.FormatUint(uint64(ch.id), 10))
+}
+
+// Go-routine safe - should only be called in the GLOBAL channel.
+// Mirrors the data updates and the user-space messages of the channel to the broker topic. If inbound is true, the
+// messages of the topic published by the other channeld instances or the backend are injected into the channel.
+// The bridge is closed along with the channel.
+func (ch *Channel) BridgeToBroker(topic string, inbound bool) error {
+	b := getBroker()
+	if b == nil {
+		return ErrNoBroker
+	}
+	if ch.brokerBridge != nil {
+		return ErrAlreadyBridged
+	}
+
+	bridge := &brokerBridge{
+		internalConnection: internalConnection{id: brokerBridgeConnId},
+		ch:                 ch,
+		broker:             b,
+		topic:              topic,
+		queue:              make(chan []byte, brokerQueueSize),
+		closed:             make(chan struct{}),
+	}
+	if inbound {
+		unsubscribe, err := b.Subscribe(topic, bridge.onBrokerMessage)
+		if err != nil {
+			return err
+		}
+		bridge.unsubscribe = unsubscribe
+	}
+	ch.brokerBridge = bridge
+	bridge.SubscribeToChannel(ch, &channeldpb.ChannelSubscriptionOptions{
+		DataAccess:           Pointer(channeldpb.ChannelDataAccess_READ_ACCESS),
+		SkipSelfUpdateFanOut: Pointer(true),
+		FanOutDelayMs:        Pointer[int32](0),
+	})
+	go bridge.publishLoop()
+
+	ch.Logger().Info("bridged channel to broker", zap.String("topic", topic), zap.Bool("inbound", inbound))
+	return nil
+}
+
+// Called in RemoveChannel().
+func (ch *Channel) closeBrokerBridge() {
+	if ch.brokerBridge == nil {
+		return
+	}
+	if ch.brokerBridge.unsubscribe != nil {
+		ch.brokerBridge.unsubscribe()
+	}
+	close(ch.brokerBridge.closed)
+}
+
+func (b *brokerBridge) publishLoop() {
+	for {
+		select {
+		case payload := <-b.queue:
+			if err := b.broker.Publish(b.topic, payload); err != nil {
+				b.ch.Logger().Warn("failed to publish to broker", zap.String("topic", b.topic), zap.Error(err))
+			}
+		case <-b.closed:
+			return
+		}
+	}
+}
+
+// The backend pipelines are the server-side consumers, so the messages not for the servers are not published.
+func (b *brokerBridge) GetConnectionType() channeldpb.ConnectionType {
+	return channeldpb.ConnectionType_SERVER
+}
+
+func (b *brokerBridge) IsClosing() bool {
+	select {
+	case <-b.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Called in the channel's goroutine.
+func (b *brokerBridge) Send(ctx MessageContext) {
+	if ctx.MsgType != channeldpb.MessageType_CHANNEL_DATA_UPDATE && ctx.MsgType < channeldpb.MessageType_USER_SPACE_START {
+		return
+	}
+
+	msgBody, err := proto.Marshal(ctx.Msg)
+	if err != nil {
+		b.ch.Logger().Error("failed to marshal the message for broker", zap.Uint32("msgType", uint32(ctx.MsgType)), zap.Error(err))
+		return
+	}
+	payload, err := proto.Marshal(&channeldpb.BrokerMessage{
+		Origin: brokerOrigin,
+		Pack: &channeldpb.MessagePack{
+			ChannelId: uint32(b.ch.id),
+			Broadcast: ctx.Broadcast,
+			MsgType:   uint32(ctx.MsgType),
+			MsgBody:   msgBody,
+			TraceId:   ctx.TraceId,
+		},
+	})
+	if err != nil {
+		b.ch.Logger().Error("failed to marshal the BrokerMessage", zap.Error(err))
+		return
+	}
+
+	select {
+	case b.queue <- payload:
+	default:
+		b.ch.Logger().Warn("broker queue is full, the message is dropped", zap.String("topic", b.topic), zap.Uint32("msgType", uint32(ctx.MsgType)))
+	}
+}
+
+func (b *brokerBridge) onBrokerMessage(payload []byte) {
+	brokerMsg := &channeldpb.BrokerMessage{}
+	if err := proto.Unmarshal(payload, brokerMsg); err != nil {
+		b.ch.Logger().Warn("failed to unmarshal the BrokerMessage", zap.String("topic", b.topic), zap.Error(err))
+		return
+	}
+	if brokerMsg.Origin == brokerOrigin || brokerMsg.Pack == nil || b.ch.IsRemoving() {
+		return
+	}
+	b.ch.Execute(func(ch *Channel) {
+		b.inject(brokerMsg.Pack)
+	})
+}
+
+// Called in the channel's goroutine.
+func (b *brokerBridge) inject(pack *channeldpb.MessagePack) {
+	msgType := channeldpb.MessageType(pack.MsgType)
+	if msgType == channeldpb.MessageType_CHANNEL_DATA_UPDATE {
+		if b.ch.data == nil {
+			return
+		}
+		updateMsg := &channeldpb.ChannelDataUpdateMessage{}
+		if err := proto.Unmarshal(pack.MsgBody, updateMsg); err != nil {
+			b.ch.Logger().Warn("failed to unmarshal the data update from broker", zap.Error(err))
+			return
+		}
+		dataMsg, err := updateMsg.Data.UnmarshalNew()
+		if err != nil {
+			b.ch.Logger().Warn("failed to unmarshal the channel data from broker", zap.Error(err))
+			return
+		}
+		b.ch.data.OnUpdate(dataMsg, b.ch.GetTime(), brokerBridgeConnId, b.ch.spatialNotifier)
+	} else if msgType >= channeldpb.MessageType_USER_SPACE_START {
+		msg := &channeldpb.ServerForwardMessage{}
+		if err := proto.Unmarshal(pack.MsgBody, msg); err != nil {
+			b.ch.Logger().Warn("failed to unmarshal the user-space message from broker", zap.Error(err))
+			return
+		}
+		ctx := MessageContext{
+			MsgType: msgType,
+			Msg:     msg,
+			// Not to publish the message back
+			Broadcast:  pack.Broadcast | uint32(channeldpb.BroadcastType_ALL_BUT_SENDER),
+			ChannelId:  uint32(b.ch.id),
+			TraceId:    pack.TraceId,
+			Connection: b,
+			Channel:    b.ch,
+		}
+		if pack.Broadcast == uint32(channeldpb.BroadcastType_NO_BROADCAST) {
+			if b.ch.HasOwner() {
+				b.ch.ownerConnection.Send(ctx)
+			}
+		} else {
+			b.ch.Broadcast(ctx)
+		}
+	}
+}
+
+func (b *brokerBridge) SubscribeToChannel(ch *Channel, options *channeldpb.ChannelSubscriptionOptions) (*ChannelSubscription, bool) {
+	return subscribeInternal(b, ch, options)
+}
+
+func (b *brokerBridge) UnsubscribeFromChannel(ch *Channel) (*channeldpb.ChannelSubscriptionOptions, error) {
+	return unsubscribeInternal(b, ch)
+}
+
+func (b *brokerBridge) Logger() *Logger {
+	return b.ch.Logger()
+}
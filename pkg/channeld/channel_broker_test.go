@@ -0,0 +1,111 @@
+package channeld
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestBridgeToBroker(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	broker := NewMemoryBroker()
+	SetBroker(broker)
+	defer SetBroker(nil)
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		DefaultFanOutIntervalMs: 50,
+		BrokerTopic:             "test.{channelId}",
+		BrokerInbound:           true,
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	// Stop the channel.Tick() goroutine
+	ch.removing = 1
+	ch.InitData(&testpb.TestChannelDataMessage{Text: "a", Num: 1}, nil)
+	ch.tickInterval = time.Hour
+
+	topic := fmt.Sprintf("test.%d", ch.id)
+	if !assert.NotNil(t, ch.brokerBridge) {
+		return
+	}
+	assert.Equal(t, topic, ch.brokerBridge.topic)
+	assert.ErrorIs(t, ch.BridgeToBroker(topic, false), ErrAlreadyBridged)
+
+	published := make(chan *channeldpb.BrokerMessage, 16)
+	broker.Subscribe(topic, func(payload []byte) {
+		msg := &channeldpb.BrokerMessage{}
+		proto.Unmarshal(payload, msg)
+		published <- msg
+	})
+	nextPublished := func() *channeldpb.BrokerMessage {
+		select {
+		case msg := <-published:
+			return msg
+		case <-time.After(100 * time.Millisecond):
+			return nil
+		}
+	}
+
+	// The data updates are published
+	fanOutIntervalMs := ch.settings().DefaultFanOutIntervalMs
+	t0 := ch.GetTime()
+	ch.tickData(t0.AddMs(fanOutIntervalMs))
+	msg := nextPublished()
+	if assert.NotNil(t, msg) {
+		assert.Equal(t, brokerOrigin, msg.Origin)
+		assert.EqualValues(t, ch.id, msg.Pack.ChannelId)
+		assert.EqualValues(t, channeldpb.MessageType_CHANNEL_DATA_UPDATE, msg.Pack.MsgType)
+	}
+
+	// The data update from the broker is merged, but not published back
+	data, _ := anypb.New(&testpb.TestChannelDataMessage{Num: 5})
+	updateBody, _ := proto.Marshal(&channeldpb.ChannelDataUpdateMessage{Data: data})
+	ch.brokerBridge.inject(&channeldpb.MessagePack{
+		MsgType: uint32(channeldpb.MessageType_CHANNEL_DATA_UPDATE),
+		MsgBody: updateBody,
+	})
+	assert.EqualValues(t, 5, ch.GetDataMessage().(*testpb.TestChannelDataMessage).Num)
+	ch.tickData(t0.AddMs(fanOutIntervalMs * 3))
+	assert.Nil(t, nextPublished())
+
+	// The user-space message from the broker is broadcast to the subscribers, but not published back
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	client.SubscribeToChannel(ch, nil)
+	forwardBody, _ := proto.Marshal(&channeldpb.ServerForwardMessage{Payload: []byte("hello")})
+	ch.brokerBridge.inject(&channeldpb.MessagePack{
+		MsgType:   uint32(channeldpb.MessageType_USER_SPACE_START) + 1,
+		Broadcast: uint32(channeldpb.BroadcastType_ALL),
+		MsgBody:   forwardBody,
+	})
+	forwardMsg, ok := client.latestMsg().(*channeldpb.ServerForwardMessage)
+	if assert.True(t, ok) {
+		assert.Equal(t, []byte("hello"), forwardMsg.Payload)
+	}
+	assert.Nil(t, nextPublished())
+
+	// The user-space broadcast in the channel is published
+	ch.Broadcast(MessageContext{
+		MsgType:   channeldpb.MessageType_USER_SPACE_START + 1,
+		Msg:       &channeldpb.ServerForwardMessage{Payload: []byte("world")},
+		Broadcast: uint32(channeldpb.BroadcastType_ALL),
+		Channel:   ch,
+	})
+	msg = nextPublished()
+	if assert.NotNil(t, msg) {
+		assert.EqualValues(t, channeldpb.MessageType_USER_SPACE_START+1, msg.Pack.MsgType)
+	}
+
+	// The subscription of the inbound topic is cancelled along with the channel
+	RemoveChannel(ch)
+	assert.Len(t, broker.handlers[topic], 1)
+}
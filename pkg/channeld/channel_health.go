@@ -0,0 +1,138 @@
+package channeld
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultHealthMaxTickLagMs    = 1000
+	DefaultHealthMaxErrorsPerSec = 10
+)
+
+type ChannelHealthStatus string
+
+const (
+	ChannelHealthStatus_Healthy  ChannelHealthStatus = "healthy"
+	ChannelHealthStatus_Degraded ChannelHealthStatus = "degraded"
+)
+
+type ChannelHealth struct {
+	ChannelId   uint32
+	ChannelType string
+	Status      ChannelHealthStatus
+	// How long the current tick has been behind its schedule. A wedged channel (e.g. blocked in a handler) lags behind more and more.
+	TickLagMs    int64
+	ErrorsPerSec float64
+	// Why the channel is degraded
+	Reasons []string `json:",omitempty"`
+}
+
+type ChannelHealthReport struct {
+	// Degraded if any of the channels is degraded
+	Status   ChannelHealthStatus
+	Channels []*ChannelHealth
+}
+
+// Returns true if the channel is reported by the health check. See ChannelSettingsType.HealthCheck.
+func (ch *Channel) isHealthChecked() bool {
+	return ch.channelType == channeldpb.ChannelType_GLOBAL || ch.settings().HealthCheck
+}
+
+// Returns the health of the channel at the time. Goroutine-safe.
+func (ch *Channel) Health(now time.Time) *ChannelHealth {
+	health := &ChannelHealth{
+		ChannelId:   uint32(ch.id),
+		ChannelType: ch.channelType.String(),
+		Status:      ChannelHealthStatus_Healthy,
+	}
+
+	lastTickTime := ch.startTime
+	if nano := atomic.LoadInt64(&ch.lastTickTime); nano > 0 {
+		lastTickTime = time.Unix(0, nano)
+	}
+	if lag := now.Sub(lastTickTime) - ch.tickInterval; lag > 0 {
+		health.TickLagMs = lag.Milliseconds()
+	}
+	if stats := ch.Stats(); stats != nil {
+		health.ErrorsPerSec = stats.ErrorsPerSec
+	}
+
+	settings := ch.settings()
+	maxTickLagMs := int64(settings.HealthMaxTickLagMs)
+	if maxTickLagMs == 0 {
+		maxTickLagMs = DefaultHealthMaxTickLagMs
+	}
+	maxErrorsPerSec := settings.HealthMaxErrorsPerSec
+	if maxErrorsPerSec == 0 {
+		maxErrorsPerSec = DefaultHealthMaxErrorsPerSec
+	}
+	if health.TickLagMs > maxTickLagMs {
+		health.Reasons = append(health.Reasons, "tick lag")
+	}
+	if health.ErrorsPerSec > maxErrorsPerSec {
+		health.Reasons = append(health.Reasons, "error rate")
+	}
+	if len(health.Reasons) > 0 {
+		health.Status = ChannelHealthStatus_Degraded
+	}
+	return health
+}
+
+// The health check of the critical channels (the GLOBAL channel and the channel types with ChannelSettingsType.HealthCheck),
+// for the external monitors. Responds 200 if all the channels are healthy, or 503 if any is degraded, e.g.
+//
+//	/health/channels              all the critical channels
+//	/health/channels?channelId=1  the channel 1 only, even if it's not critical
+func HandleChannelHealth(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	report := &ChannelHealthReport{
+		Status:   ChannelHealthStatus_Healthy,
+		Channels: make([]*ChannelHealth, 0),
+	}
+	if str := r.URL.Query().Get("channelId"); str != "" {
+		channelId, err := strconv.ParseUint(str, 10, 32)
+		if err != nil {
+			http.Error(w, "invalid channelId", http.StatusBadRequest)
+			return
+		}
+		ch := GetChannel(common.ChannelId(channelId))
+		if ch == nil {
+			http.Error(w, "channel not found", http.StatusNotFound)
+			return
+		}
+		report.Channels = append(report.Channels, ch.Health(now))
+	} else {
+		allChannels.Range(func(_ common.ChannelId, ch *Channel) bool {
+			if ch.isHealthChecked() && !ch.IsRemoving() {
+				report.Channels = append(report.Channels, ch.Health(now))
+			}
+			return true
+		})
+	}
+
+	sort.Slice(report.Channels, func(i, j int) bool {
+		return report.Channels[i].ChannelId < report.Channels[j].ChannelId
+	})
+	for _, health := range report.Channels {
+		if health.Status != ChannelHealthStatus_Healthy {
+			report.Status = health.Status
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if report.Status != ChannelHealthStatus_Healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		rootLogger.Error("failed to write channel health report", zap.Error(err))
+	}
+}
@@ -0,0 +1,112 @@
+package channeld
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelHealth(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{HealthCheck: true, HealthMaxErrorsPerSec: 1}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	t.Cleanup(func() { RemoveChannel(ch) })
+	// Stop the channel.Tick() goroutine
+	ch.removing = 1
+	ch.tickInterval = 10 * time.Millisecond
+	now := time.Now()
+	atomic.StoreInt64(&ch.lastTickTime, now.UnixNano())
+	assert.True(t, ch.isHealthChecked())
+	assert.True(t, globalChannel.isHealthChecked())
+
+	health := ch.Health(now.Add(5 * time.Millisecond))
+	assert.Equal(t, ChannelHealthStatus_Healthy, health.Status)
+	assert.Zero(t, health.TickLagMs)
+
+	// Wedged
+	health = ch.Health(now.Add(2 * time.Second))
+	assert.Equal(t, ChannelHealthStatus_Degraded, health.Status)
+	assert.EqualValues(t, 1990, health.TickLagMs)
+	assert.Equal(t, []string{"tick lag"}, health.Reasons)
+
+	// Failing the messages
+	ch.tickStats(now)
+	ch.countStats(&ch.statsCounters.errors)
+	ch.countStats(&ch.statsCounters.errors)
+	ch.tickStats(now.Add(time.Second))
+	health = ch.Health(now)
+	assert.Equal(t, ChannelHealthStatus_Degraded, health.Status)
+	assert.Equal(t, 2.0, health.ErrorsPerSec)
+	assert.Equal(t, []string{"error rate"}, health.Reasons)
+}
+
+// Replaces the channels with a new registry that only has the GLOBAL channel, so the test doesn't see the channels left by
+// the other tests. The channels of the new registry are removed, and the previous registry is restored in the cleanup.
+func useNewChannelRegistry(t *testing.T) {
+	channels, keys, quarantined := allChannels, channelKeys, quarantinedChannelIds
+	global, nextId, nextSpatialId := globalChannel, nextChannelId, nextSpatialChannelId
+	allChannels, globalChannel = nil, nil
+	InitChannels()
+	t.Cleanup(func() {
+		var created []*Channel
+		allChannels.Range(func(_ common.ChannelId, ch *Channel) bool {
+			created = append(created, ch)
+			return true
+		})
+		for _, ch := range created {
+			RemoveChannel(ch)
+		}
+		allChannels, channelKeys, quarantinedChannelIds = channels, keys, quarantined
+		globalChannel, nextChannelId, nextSpatialChannelId = global, nextId, nextSpatialId
+	})
+}
+
+func TestHandleChannelHealth(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+	useNewChannelRegistry(t)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	// Stop the channel.Tick() goroutine
+	ch.removing = 1
+	atomic.StoreInt64(&ch.lastTickTime, time.Now().Add(-time.Minute).UnixNano())
+
+	serve := func(query string) (int, *ChannelHealthReport) {
+		rec := httptest.NewRecorder()
+		NewAdminHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/health/channels?"+query, nil))
+		report := &ChannelHealthReport{}
+		json.Unmarshal(rec.Body.Bytes(), report)
+		return rec.Code, report
+	}
+
+	// Only the GLOBAL channel is critical by default
+	code, report := serve("")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, ChannelHealthStatus_Healthy, report.Status)
+	if assert.Len(t, report.Channels, 1) {
+		assert.EqualValues(t, GlobalChannelId, report.Channels[0].ChannelId)
+	}
+
+	code, report = serve("channelId=" + strconv.FormatUint(uint64(ch.id), 10))
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, ChannelHealthStatus_Degraded, report.Status)
+
+	code, _ = serve("channelId=12345")
+	assert.Equal(t, http.StatusNotFound, code)
+}
@@ -0,0 +1,58 @@
+package channeld
+
+import (
+	"time"
+
+	"github.com/metaworking/channeld/pkg/common"
+	"github.com/puzpuzpuz/xsync/v2"
+	"go.uber.org/zap"
+)
+
+// The ids of the removed channels, and the time when they can be reused.
+// Quarantining the ids prevents the late messages to the removed channel being handled by a new channel with the same id.
+var quarantinedChannelIds *xsync.MapOf[common.ChannelId, time.Time]
+
+// Used by GetNextIdTyped() to skip the ids that are either in use or quarantined.
+type channelIdAllocator struct{}
+
+func (channelIdAllocator) Load(channelId common.ChannelId) (*Channel, bool) {
+	if ch, exists := allChannels.Load(channelId); exists {
+		return ch, true
+	}
+
+	releaseTime, quarantined := quarantinedChannelIds.Load(channelId)
+	if !quarantined {
+		return nil, false
+	}
+	if time.Now().Before(releaseTime) {
+		return nil, true
+	}
+
+	quarantinedChannelIds.Delete(channelId)
+	channelIdQuarantined.Dec()
+	return nil, false
+}
+
+func quarantineChannelId(channelId common.ChannelId) {
+	if GlobalSettings.ChannelIdQuarantineMs == 0 {
+		return
+	}
+	releaseTime := time.Now().Add(time.Duration(GlobalSettings.ChannelIdQuarantineMs) * time.Millisecond)
+	if _, loaded := quarantinedChannelIds.LoadOrStore(channelId, releaseTime); !loaded {
+		channelIdQuarantined.Inc()
+	}
+}
+
+// Should be called when no channel id can be allocated in the range.
+// Returns true if the range is really full, so the caller can cache the status;
+// if some ids are quarantined, they will become available later.
+func onChannelIdExhausted(idRange string, err error) bool {
+	channelIdExhausted.WithLabelValues(idRange).Inc()
+	quarantinedNum := quarantinedChannelIds.Size()
+	rootLogger.Error("failed to allocate channel id",
+		zap.String("idRange", idRange),
+		zap.Int("quarantinedNum", quarantinedNum),
+		zap.Error(err),
+	)
+	return quarantinedNum == 0
+}
@@ -0,0 +1,52 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelIdQuarantine(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	defer func(ms uint32) {
+		GlobalSettings.ChannelIdQuarantineMs = ms
+	}(GlobalSettings.ChannelIdQuarantineMs)
+	GlobalSettings.ChannelIdQuarantineMs = 10000
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	ch1, err := CreateChannel(channeldpb.ChannelType_TEST, server)
+	assert.NoError(t, err)
+	removedId := ch1.Id()
+	RemoveChannel(ch1)
+
+	// The removed id is not reused during the quarantine
+	_, unavailable := channelIdAllocator{}.Load(removedId)
+	assert.True(t, unavailable)
+	ch2, err := CreateChannel(channeldpb.ChannelType_TEST, server)
+	assert.NoError(t, err)
+	assert.NotEqual(t, removedId, ch2.Id())
+
+	// Some ids will be available after the quarantine, so the status should not be cached
+	assert.False(t, onChannelIdExhausted("non_spatial", ErrNonSpatialChannelFull))
+
+	// The id is released after the quarantine
+	quarantinedChannelIds.Store(removedId, time.Now().Add(-time.Millisecond))
+	_, unavailable = channelIdAllocator{}.Load(removedId)
+	assert.False(t, unavailable)
+	_, quarantined := quarantinedChannelIds.Load(removedId)
+	assert.False(t, quarantined)
+
+	// Reuse immediately if the quarantine is disabled
+	GlobalSettings.ChannelIdQuarantineMs = 0
+	removedId = ch2.Id()
+	RemoveChannel(ch2)
+	ch3, err := CreateChannel(channeldpb.ChannelType_TEST, server)
+	assert.NoError(t, err)
+	assert.Equal(t, removedId, ch3.Id())
+	assert.Equal(t, removedId, nextChannelId)
+}
@@ -0,0 +1,183 @@
+package channeld
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+const DefaultInboxSize = 1024
+
+// The messages drained from the channel's inMsgQueue, queued by the sender so a spamming connection can't starve the others.
+// Should only be accessed in the channel's goroutine. See Channel.tickMessages().
+type channelInbox struct {
+	// The messages without a sender connection, e.g. Execute(). Always handled first and never limited.
+	system []channelMessage
+	// The pending messages of each sender, in the order of arrival.
+	senders map[ConnectionInChannel][]channelMessage
+	// The senders in the round-robin order
+	order []ConnectionInChannel
+	// The index in the order of the sender to handle next
+	cursor int
+}
+
+func (inbox *channelInbox) pendingNum() int {
+	num := len(inbox.system)
+	for _, queue := range inbox.senders {
+		num += len(queue)
+	}
+	return num
+}
+
+// Returns false if the message is dropped because the sender's quota is exceeded.
+func (inbox *channelInbox) push(cm channelMessage, senderQuota int) bool {
+	if cm.ctx.Msg == nil || cm.ctx.Connection == nil {
+		inbox.system = append(inbox.system, cm)
+		return true
+	}
+
+	sender := cm.ctx.Connection
+	queue, exists := inbox.senders[sender]
+	if senderQuota > 0 && len(queue) >= senderQuota {
+		return false
+	}
+	if !exists {
+		if inbox.senders == nil {
+			inbox.senders = make(map[ConnectionInChannel][]channelMessage)
+		}
+		inbox.order = append(inbox.order, sender)
+	}
+	inbox.senders[sender] = append(queue, cm)
+	return true
+}
+
+// Pops the next message of the sender at the cursor, and moves the cursor to the next sender.
+func (inbox *channelInbox) pop() channelMessage {
+	sender := inbox.order[inbox.cursor]
+	queue := inbox.senders[sender]
+	cm := queue[0]
+	if len(queue) == 1 {
+		delete(inbox.senders, sender)
+		inbox.order = append(inbox.order[:inbox.cursor], inbox.order[inbox.cursor+1:]...)
+	} else {
+		queue[0] = channelMessage{}
+		inbox.senders[sender] = queue[1:]
+		inbox.cursor++
+	}
+	if inbox.cursor >= len(inbox.order) {
+		inbox.cursor = 0
+	}
+	return cm
+}
+
+// Moves the messages from the inMsgQueue to the inbox, dropping the ones exceeding the sender's quota.
+func (ch *Channel) drainInMsgQueue() {
+	for len(ch.inMsgQueue) > 0 {
+		cm := <-ch.inMsgQueue
+		if !ch.inbox.push(cm, ch.senderInboxQuota) {
+			channelInboxDropped.WithLabelValues(ch.channelType.String(), "senderQuota").Inc()
+			cm.ctx.Connection.Logger().Debug("dropped message as the sender's inbox quota of the channel is exceeded",
+				zap.Uint32("msgType", uint32(cm.ctx.MsgType)),
+				zap.Uint32("channelId", uint32(ch.id)),
+				zap.Int("quota", ch.senderInboxQuota),
+			)
+		}
+	}
+}
+
+// Puts the message from the connection into the inMsgQueue without blocking the connection's goroutine.
+func (ch *Channel) tryPutMessage(cm channelMessage) {
+	select {
+	case ch.inMsgQueue <- cm:
+	default:
+		channelInboxDropped.WithLabelValues(ch.channelType.String(), "inboxFull").Inc()
+		ch.Logger().Warn("dropped message as the inbox of the channel is full",
+			zap.Uint32("msgType", uint32(cm.ctx.MsgType)),
+			zap.Int("inboxSize", cap(ch.inMsgQueue)),
+		)
+	}
+}
+
+// Handles the messages in the inbox, one message per sender in turn. Each sender handles at most
+// ChannelSettingsType.SenderMessagesPerTick messages in a tick. The left are deferred to the next tick.
+func (ch *Channel) tickMessages(tickStart time.Time, goroutineId string) {
+	ch.drainInMsgQueue()
+
+	for len(ch.inbox.system) > 0 {
+		cm := ch.inbox.system[0]
+		ch.inbox.system[0] = channelMessage{}
+		ch.inbox.system = ch.inbox.system[1:]
+		ch.handleMessage(cm, goroutineId)
+	}
+
+	var handledNum map[ConnectionInChannel]int
+	for skipped := 0; len(ch.inbox.order) > 0 && skipped < len(ch.inbox.order); {
+		sender := ch.inbox.order[ch.inbox.cursor]
+		if ch.senderMessagesPerTick > 0 {
+			if handledNum[sender] >= ch.senderMessagesPerTick {
+				skipped++
+				ch.inbox.cursor = (ch.inbox.cursor + 1) % len(ch.inbox.order)
+				continue
+			}
+			if handledNum == nil {
+				handledNum = make(map[ConnectionInChannel]int)
+			}
+			handledNum[sender]++
+		}
+		skipped = 0
+
+		ch.handleMessage(ch.inbox.pop(), goroutineId)
+
+		if ch.tickInterval > 0 && time.Since(tickStart) >= ch.messageBudget() {
+			ch.Logger().Warn("spent too long handling messages, will delay the left to the next tick",
+				zap.Duration("duration", time.Since(tickStart)),
+				zap.Int("remaining", ch.inbox.pendingNum()+len(ch.inMsgQueue)),
+			)
+			break
+		}
+	}
+
+	if deferred := ch.inbox.pendingNum(); deferred > 0 {
+		channelInboxDeferred.WithLabelValues(ch.channelType.String()).Add(float64(deferred))
+	}
+
+	ch.mergePendingUpdates()
+}
+
+func (ch *Channel) handleMessage(cm channelMessage, goroutineId string) {
+	// No message in the context, just execute the handler.
+	if cm.ctx.Msg == nil {
+		watch := ch.watchHandling(cm.ctx.MsgType.String(), goroutineId, nil)
+		cm.handler(cm.ctx)
+		watch.done()
+		return
+	}
+
+	if cm.ctx.Connection == nil {
+		ch.Logger().Warn("drops message as the sender is lost", zap.Uint32("msgType", uint32(cm.ctx.MsgType)))
+		return
+	}
+	if cm.ctx.Connection == ch.ownerConnection {
+		ch.lastActiveTime = time.Now()
+	}
+	if err := validateByScripts(cm.ctx); err != nil {
+		ch.Logger().Warn("message is rejected by the script", zap.Error(err),
+			zap.Uint32("msgType", uint32(cm.ctx.MsgType)),
+			zap.Uint32("connId", uint32(cm.ctx.Connection.Id())),
+		)
+		return
+	}
+	conn, _ := cm.ctx.Connection.(*Connection)
+	tracing := conn != nil && conn.isTracing()
+	var start time.Time
+	if tracing {
+		start = time.Now()
+	}
+	watch := ch.watchHandling(cm.ctx.MsgType.String(), goroutineId, cm.ctx.Connection)
+	cm.handler(cm.ctx)
+	watch.done()
+	if tracing {
+		conn.traceMessage(MessageTraceDirection_In, cm.ctx.ChannelId, uint32(cm.ctx.MsgType), proto.Size(cm.ctx.Msg), time.Since(start))
+	}
+}
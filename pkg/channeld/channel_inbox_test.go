@@ -0,0 +1,82 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelInboxFairShare(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		TickIntervalMs:        3600000,
+		SenderInboxQuota:      8,
+		SenderMessagesPerTick: 2,
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	spammer := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	// Wait for the first tick, after which the channel.Tick() goroutine sleeps for an hour
+	time.Sleep(50 * time.Millisecond)
+
+	handled := []ConnectionInChannel{}
+	handler := func(ctx MessageContext) {
+		handled = append(handled, ctx.Connection)
+	}
+	for i := 0; i < 10; i++ {
+		ch.PutMessage(&testpb.TestChannelDataMessage{}, handler, spammer, &channeldpb.MessagePack{})
+	}
+	ch.PutMessage(&testpb.TestChannelDataMessage{}, handler, client, &channeldpb.MessagePack{})
+	executed := false
+	ch.Execute(func(ch *Channel) { executed = true })
+
+	droppedBefore := testutil.ToFloat64(channelInboxDropped.WithLabelValues(channeldpb.ChannelType_TEST.String(), "senderQuota"))
+	ch.tickMessages(time.Now(), "")
+	assert.True(t, executed)
+	// The client is not starved by the spammer
+	assert.Equal(t, []ConnectionInChannel{spammer, client, spammer}, handled)
+	// The spammer's messages exceeding the quota are dropped, and the left are deferred.
+	assert.EqualValues(t, 2, testutil.ToFloat64(channelInboxDropped.WithLabelValues(channeldpb.ChannelType_TEST.String(), "senderQuota"))-droppedBefore)
+	assert.Equal(t, 6, ch.inbox.pendingNum())
+
+	handled = handled[:0]
+	ch.tickMessages(time.Now(), "")
+	assert.Equal(t, []ConnectionInChannel{spammer, spammer}, handled)
+	assert.Equal(t, 4, ch.inbox.pendingNum())
+	ch.removing = 1
+}
+
+func TestChannelInboxFull(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		TickIntervalMs: 3600000,
+		InboxSize:      2,
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	// Wait for the first tick, after which the channel.Tick() goroutine sleeps for an hour
+	time.Sleep(50 * time.Millisecond)
+
+	droppedBefore := testutil.ToFloat64(channelInboxDropped.WithLabelValues(channeldpb.ChannelType_TEST.String(), "inboxFull"))
+	for i := 0; i < 3; i++ {
+		// Doesn't block the connection's goroutine
+		ch.PutMessage(&testpb.TestChannelDataMessage{}, func(ctx MessageContext) {}, server, &channeldpb.MessagePack{})
+	}
+	assert.EqualValues(t, 1, testutil.ToFloat64(channelInboxDropped.WithLabelValues(channeldpb.ChannelType_TEST.String(), "inboxFull"))-droppedBefore)
+	ch.removing = 1
+}
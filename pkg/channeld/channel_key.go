@@ -0,0 +1,41 @@
+package channeld
+
+import (
+	"errors"
+
+	"github.com/metaworking/channeld/pkg/common"
+	"github.com/puzpuzpuz/xsync/v2"
+)
+
+var ErrChannelKeyInUse = errors.New("channel key is already in use")
+
+// The index of the named channels
+var channelKeys *xsync.MapOf[string, common.ChannelId]
+
+// Returns nil if there is no channel with the key in the default tenant.
+func GetChannelByKey(key string) *Channel {
+	return GetTenantChannelByKey("", key)
+}
+
+// Returns nil if there is no channel with the key in the tenant.
+func GetTenantChannelByKey(tenant string, key string) *Channel {
+	channelId, ok := channelKeys.Load(tenantChannelKey(tenant, key))
+	if !ok {
+		return nil
+	}
+	return GetChannel(channelId)
+}
+
+// The unique key of the named channel. Empty if the channel is not named.
+func (ch *Channel) Key() string {
+	return ch.key
+}
+
+// Go-routine safe - should only be called once, right after the channel is created.
+func (ch *Channel) setKey(key string) error {
+	if _, loaded := channelKeys.LoadOrStore(tenantChannelKey(ch.tenant, key), ch.id); loaded {
+		return ErrChannelKeyInUse
+	}
+	ch.key = key
+	return nil
+}
@@ -0,0 +1,104 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNamedChannel(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		// The queued subscription is handled by tickMessages() below
+		TickIntervalMs: 3600000,
+		ACLSettings:    ACLSettingsType{Sub: ChannelAccessLevel_Any},
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+
+	createCtx := MessageContext{
+		MsgType:    channeldpb.MessageType_CREATE_CHANNEL,
+		Msg:        &channeldpb.CreateChannelMessage{ChannelType: channeldpb.ChannelType_TEST, ChannelKey: "guild:1234"},
+		Connection: server,
+		Channel:    globalChannel,
+	}
+	handleCreateChannel(createCtx)
+	ch := GetChannelByKey("guild:1234")
+	if !assert.NotNil(t, ch) {
+		return
+	}
+	assert.Equal(t, "guild:1234", ch.Key())
+	// Wait for the first tick, after which the channel.Tick() goroutine sleeps for an hour
+	time.Sleep(50 * time.Millisecond)
+	var createResult *channeldpb.CreateChannelResultMessage
+	for _, msg := range server.testQueue() {
+		if result, ok := msg.(*channeldpb.CreateChannelResultMessage); ok {
+			createResult = result
+		}
+	}
+	if assert.NotNil(t, createResult) {
+		assert.Equal(t, "guild:1234", createResult.ChannelKey)
+		assert.EqualValues(t, ch.Id(), createResult.ChannelId)
+	}
+
+	// The key is already in use
+	channelCount := allChannels.Size()
+	handleCreateChannel(createCtx)
+	assert.Equal(t, channelCount, allChannels.Size())
+	assert.Same(t, ch, GetChannelByKey("guild:1234"))
+
+	// Subscribe by the key instead of the channelId
+	server.OnAuthenticated("")
+	msgBody, _ := proto.Marshal(&channeldpb.SubscribedToChannelMessage{ConnId: uint32(client.Id())})
+	server.receiveMessage(&channeldpb.MessagePack{
+		ChannelKey: "guild:1234",
+		MsgType:    uint32(channeldpb.MessageType_SUB_TO_CHANNEL),
+		MsgBody:    msgBody,
+	})
+	ch.tickMessages(time.Now(), "")
+	assert.Contains(t, ch.GetAllConnections(), client)
+
+	RemoveChannel(ch)
+	assert.Nil(t, GetChannelByKey("guild:1234"))
+}
+
+func TestAutoCreateNamedChannel(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		// The queued subscription is handled by tickMessages() below
+		TickIntervalMs:  3600000,
+		AutoCreateOnSub: true,
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	handleSubToAutoCreatedChannel(MessageContext{
+		MsgType:    channeldpb.MessageType_SUB_TO_CHANNEL,
+		Msg:        &channeldpb.SubscribedToChannelMessage{ChannelType: channeldpb.ChannelType_TEST},
+		Connection: client,
+		Channel:    globalChannel,
+		channelKey: "guild:5678",
+	})
+	ch := GetChannelByKey("guild:5678")
+	if !assert.NotNil(t, ch) {
+		return
+	}
+	defer RemoveChannel(ch)
+	assert.Less(t, ch.Id(), GlobalSettings.SpatialChannelIdStart)
+	assert.False(t, ch.HasOwner())
+	// Wait for the first tick, after which the channel.Tick() goroutine sleeps for an hour
+	time.Sleep(50 * time.Millisecond)
+	ch.tickMessages(time.Now(), "")
+	assert.Contains(t, ch.GetAllConnections(), client)
+}
@@ -0,0 +1,206 @@
+package channeld
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+)
+
+// What happens to the channel when the connection of its owner drops. Not applied to the GLOBAL channel.
+type OwnerDisconnectPolicy uint8
+
+const (
+	// The channel stays without an owner.
+	OwnerDisconnectPolicy_Keep OwnerDisconnectPolicy = 0
+	// The channel is removed. Same as ChannelSettingsType.RemoveChannelAfterOwnerRemoved.
+	OwnerDisconnectPolicy_Destroy OwnerDisconnectPolicy = 1
+	// The channel rejects the channel data updates until the owner reclaims it, by subscribing to the channel with the same PIT
+	// from a new SERVER connection. The channel is removed if it's not reclaimed in the grace period.
+	OwnerDisconnectPolicy_Freeze OwnerDisconnectPolicy = 2
+	// The ownership is transferred to the earliest subscribed SERVER connection. If there's none, the channel is frozen,
+	// and any SERVER connection that subscribes in the grace period takes the ownership.
+	// If the channel is assigned by ChannelOwnerPolicy_ConsistentHash, the ownership is transferred to the next server on the hash ring.
+	OwnerDisconnectPolicy_Transfer OwnerDisconnectPolicy = 3
+)
+
+const DefaultOwnerReclaimGracePeriodMs uint32 = 30000
+
+func (s ChannelSettingsType) ownerDisconnectPolicy() OwnerDisconnectPolicy {
+	if s.RemoveChannelAfterOwnerRemoved {
+		return OwnerDisconnectPolicy_Destroy
+	}
+	return s.OwnerDisconnectPolicy
+}
+
+func (s ChannelSettingsType) ownerReclaimGracePeriodMs() uint32 {
+	if s.OwnerReclaimGracePeriodMs == 0 {
+		return DefaultOwnerReclaimGracePeriodMs
+	}
+	return s.OwnerReclaimGracePeriodMs
+}
+
+// The frozen state of the channel, after its owner disconnected. See OwnerDisconnectPolicy_Freeze.
+type ownerReclaim struct {
+	previousOwner *Connection
+	deadline      time.Time
+	// If true, any SERVER connection can reclaim the channel. Otherwise, only the one with the previous owner's PIT.
+	anyServer bool
+	// The client messages to the owner, to be sent to the new owner. See ChannelSettingsType.OwnerReclaimBufferSize.
+	buffered []MessageContext
+}
+
+// Returns true if the channel rejects the channel data updates, as it's waiting for the owner to reclaim it.
+// Should be called in the channel's goroutine.
+func (ch *Channel) IsFrozen() bool {
+	return ch.ownerReclaim != nil
+}
+
+// Applies the OwnerDisconnectPolicy to the owner that disconnected in tickConnections(), and removes the frozen channel
+// when the grace period expires. Should be called in the channel's goroutine.
+func (ch *Channel) tickOwner(now time.Time) {
+	if previousOwner := ch.disconnectedOwner; previousOwner != nil {
+		ch.disconnectedOwner = nil
+		policy := ch.settings().ownerDisconnectPolicy()
+		if policy == OwnerDisconnectPolicy_Transfer {
+			if newOwner := ch.consistentHashOwnerCandidate(); newOwner != nil {
+				ch.changeOwner(newOwner, previousOwner)
+				return
+			}
+			if newOwner := ch.findOwnerCandidate(nil); newOwner != nil {
+				ch.changeOwner(newOwner, previousOwner)
+				return
+			}
+		}
+		if policy == OwnerDisconnectPolicy_Freeze || policy == OwnerDisconnectPolicy_Transfer {
+			ch.freeze(previousOwner, now, policy == OwnerDisconnectPolicy_Transfer)
+		}
+		return
+	}
+
+	reclaim := ch.ownerReclaim
+	if reclaim == nil {
+		return
+	}
+	if newOwner := ch.findOwnerCandidate(reclaim); newOwner != nil {
+		ch.reclaimOwner(newOwner, reclaim.previousOwner)
+		return
+	}
+	if now.Before(reclaim.deadline) {
+		return
+	}
+
+	ch.ownerReclaim = nil
+	atomic.AddInt32(&ch.removing, 1)
+	// Same as removing the channel after the owner is removed
+	globalChannel.PutMessage(&channeldpb.RemoveChannelMessage{
+		ChannelId: uint32(ch.id),
+	}, handleRemoveChannel, nil, &channeldpb.MessagePack{
+		Broadcast: 0,
+		StubId:    0,
+		ChannelId: uint32(GlobalChannelId),
+	})
+	ch.Logger().Info("removing channel as the owner didn't reclaim it in the grace period")
+}
+
+// Returns the earliest subscribed SERVER connection that can own the channel, or nil if there's none.
+func (ch *Channel) findOwnerCandidate(reclaim *ownerReclaim) *Connection {
+	ch.connectionsLock.RLock()
+	defer ch.connectionsLock.RUnlock()
+
+	var candidate *Connection
+	var candidateSubTime ChannelTime
+	for conn, cs := range ch.subscribedConnections {
+		c, ok := conn.(*Connection)
+		if !ok || c.IsClosing() || c.GetConnectionType() != channeldpb.ConnectionType_SERVER {
+			continue
+		}
+		if reclaim != nil && !reclaim.anyServer && c.pit != reclaim.previousOwner.pit {
+			continue
+		}
+		if candidate == nil || cs.subTime < candidateSubTime {
+			candidate = c
+			candidateSubTime = cs.subTime
+		}
+	}
+	return candidate
+}
+
+func (ch *Channel) freeze(previousOwner *Connection, now time.Time, anyServer bool) {
+	graceMs := ch.settings().ownerReclaimGracePeriodMs()
+	ch.ownerReclaim = &ownerReclaim{
+		previousOwner: previousOwner,
+		deadline:      now.Add(time.Duration(graceMs) * time.Millisecond),
+		anyServer:     anyServer,
+	}
+	ch.Logger().Info("froze channel until the owner reclaims it", zap.Uint32("previousOwnerConnId", uint32(previousOwner.Id())))
+	ch.Broadcast(MessageContext{
+		MsgType: channeldpb.MessageType_CHANNEL_OWNER_CHANGED,
+		Msg: &channeldpb.ChannelOwnerChangedMessage{
+			PreviousOwnerConnId: uint32(previousOwner.Id()),
+			Frozen:              true,
+			ReclaimGraceMs:      graceMs,
+		},
+		ChannelId: uint32(ch.id),
+	})
+}
+
+// Unfreezes the channel with the new owner, and sends the buffered client messages to it.
+func (ch *Channel) reclaimOwner(newOwner *Connection, previousOwner *Connection) {
+	var buffered []MessageContext
+	if ch.ownerReclaim != nil {
+		buffered = ch.ownerReclaim.buffered
+		ch.ownerReclaim = nil
+	}
+	ch.previousOwnerOptions = nil
+	ch.changeOwner(newOwner, previousOwner)
+	for _, ctx := range buffered {
+		newOwner.Send(ctx)
+	}
+	if len(buffered) > 0 {
+		ch.Logger().Info("sent the buffered client messages to the new owner", zap.Int("num", len(buffered)))
+	}
+}
+
+// Buffers the client message to the owner while the channel is frozen. Returns false if the message is not buffered.
+// The oldest message is dropped when the buffer is full. See ChannelSettingsType.OwnerReclaimBufferSize.
+func (ch *Channel) bufferForOwner(ctx MessageContext) bool {
+	size := ch.settings().OwnerReclaimBufferSize
+	if ch.ownerReclaim == nil || size <= 0 {
+		return false
+	}
+	reclaim := ch.ownerReclaim
+	if len(reclaim.buffered) >= size {
+		reclaim.buffered[0] = MessageContext{}
+		reclaim.buffered = reclaim.buffered[1:]
+		ch.Logger().Debug("dropped the oldest buffered client message as the buffer is full")
+	}
+	reclaim.buffered = append(reclaim.buffered, ctx)
+	return true
+}
+
+func (ch *Channel) changeOwner(newOwner *Connection, previousOwner *Connection) {
+	ch.ownerConnection = newOwner
+	ch.lastActiveTime = time.Now()
+	ch.Logger().Info("transferred the ownership of channel",
+		zap.Uint32("ownerConnId", uint32(newOwner.Id())),
+		zap.Uint32("previousOwnerConnId", uint32(previousOwner.Id())),
+	)
+	ownerChangedCtx := MessageContext{
+		MsgType: channeldpb.MessageType_CHANNEL_OWNER_CHANGED,
+		Msg: &channeldpb.ChannelOwnerChangedMessage{
+			OwnerConnId:         uint32(newOwner.Id()),
+			PreviousOwnerConnId: uint32(previousOwner.Id()),
+		},
+		ChannelId: uint32(ch.id),
+	}
+	ch.Broadcast(ownerChangedCtx)
+	// The new owner picked by consistent hashing may not subscribe to the channel
+	ch.connectionsLock.RLock()
+	_, subscribed := ch.subscribedConnections[newOwner]
+	ch.connectionsLock.RUnlock()
+	if !subscribed {
+		newOwner.Send(ownerChangedCtx)
+	}
+}
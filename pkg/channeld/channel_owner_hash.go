@@ -0,0 +1,105 @@
+package channeld
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+)
+
+const (
+	// The number of the points of each server on the hash ring. More points spread the channels more evenly.
+	ownerHashVirtualNodes = 64
+)
+
+type ownerHashNode struct {
+	hash uint64
+	conn *Connection
+}
+
+// The consistent-hash ring of the SERVER connections that can own the channels. The servers are placed on the ring by
+// their PITs, so a restarted server that authenticates with the same PIT gets the same channels back, and adding or
+// removing a server only moves the channels between it and its neighbours on the ring. See ChannelOwnerPolicy_ConsistentHash.
+type ownerHashRing []ownerHashNode
+
+func ownerHash(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// Builds the ring of the READY SERVER connections in the tenant that have the required tags.
+// The connection that is closing or draining is not on the ring.
+func newOwnerHashRing(tenant string, requiredTags map[string]string) ownerHashRing {
+	// The connections with the same PIT, e.g. the one replacing a dropped connection, take the same place on the ring
+	byPit := make(map[string]*Connection)
+	allConnections.Range(func(_ ConnectionId, c *Connection) bool {
+		if c.GetConnectionType() != channeldpb.ConnectionType_SERVER || c.IsClosing() || c.State() != ConnectionState_READY {
+			return true
+		}
+		if GetTenant(c) != tenant || !MatchTags(c, requiredTags) {
+			return true
+		}
+		pit := c.pit
+		if pit == "" {
+			pit = "#" + strconv.FormatUint(uint64(c.Id()), 10)
+		}
+		if existing, exists := byPit[pit]; !exists || existing.Id() < c.Id() {
+			byPit[pit] = c
+		}
+		return true
+	})
+
+	ring := make(ownerHashRing, 0, len(byPit)*ownerHashVirtualNodes)
+	for pit, c := range byPit {
+		for i := 0; i < ownerHashVirtualNodes; i++ {
+			ring = append(ring, ownerHashNode{hash: ownerHash(pit + "-" + strconv.Itoa(i)), conn: c})
+		}
+	}
+	sort.Slice(ring, func(i, j int) bool {
+		if ring[i].hash == ring[j].hash {
+			return ring[i].conn.Id() < ring[j].conn.Id()
+		}
+		return ring[i].hash < ring[j].hash
+	})
+	return ring
+}
+
+// Returns the server that owns the key, i.e. the first one clockwise on the ring, or nil if the ring is empty.
+func (ring ownerHashRing) owner(key string) *Connection {
+	if len(ring) == 0 {
+		return nil
+	}
+	h := ownerHash(key)
+	i := sort.Search(len(ring), func(i int) bool { return ring[i].hash >= h })
+	if i == len(ring) {
+		i = 0
+	}
+	return ring[i].conn
+}
+
+// The key of the channel on the hash ring. The channel key is preferred, as it stays the same when the channel is recreated.
+func ownerHashKey(channelId common.ChannelId, channelKey string) string {
+	if channelKey != "" {
+		return channelKey
+	}
+	return strconv.FormatUint(uint64(channelId), 10)
+}
+
+// Go-routine safe - returns the server that should own the channel of the key by consistent hashing, or nil if no server
+// is available. See ChannelOwnerPolicy_ConsistentHash.
+func ConsistentHashOwner(tenant string, channelType channeldpb.ChannelType, channelId common.ChannelId, channelKey string) *Connection {
+	requiredTags := GlobalSettings.GetTenantChannelSettings(tenant, channelType).OwnerHashServerTags
+	return newOwnerHashRing(tenant, requiredTags).owner(ownerHashKey(channelId, channelKey))
+}
+
+// Returns the server that the ownership of the channel is transferred to, when the channel is assigned by consistent
+// hashing and its owner disconnects. Should be called in the channel's goroutine.
+func (ch *Channel) consistentHashOwnerCandidate() *Connection {
+	if ch.settings().AutoCreateOwnerPolicy != ChannelOwnerPolicy_ConsistentHash {
+		return nil
+	}
+	return ConsistentHashOwner(ch.tenant, ch.channelType, ch.id, ch.key)
+}
@@ -0,0 +1,153 @@
+package channeld
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func addTestHashServer(pit string, group string) *Connection {
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	server.OnAuthenticated(pit)
+	server.SetTag("hashGroup", group)
+	return server
+}
+
+func TestOwnerHashRing(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	tags := map[string]string{"hashGroup": "ring"}
+	assert.Nil(t, newOwnerHashRing("", tags).owner("a"))
+
+	s1 := addTestHashServer("s1", "ring")
+	s2 := addTestHashServer("s2", "ring")
+	s3 := addTestHashServer("s3", "ring")
+	// Not on the ring: not authenticated, or not tagged
+	addTestConnection(channeldpb.ConnectionType_SERVER).SetTag("hashGroup", "ring")
+	addTestHashServer("other", "other")
+
+	const numKeys = 1000
+	keys := make([]string, numKeys)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("room-%d", i)
+	}
+	owners := func() map[string]*Connection {
+		ring := newOwnerHashRing("", tags)
+		result := make(map[string]*Connection, numKeys)
+		for _, key := range keys {
+			result[key] = ring.owner(key)
+		}
+		return result
+	}
+
+	before := owners()
+	counts := make(map[*Connection]int)
+	for _, owner := range before {
+		counts[owner]++
+	}
+	assert.Len(t, counts, 3)
+	for _, s := range []*Connection{s1, s2, s3} {
+		// Roughly even
+		assert.Greater(t, counts[s], numKeys/10)
+	}
+
+	// Adding a server only moves the keys to it
+	s4 := addTestHashServer("s4", "ring")
+	after := owners()
+	moved := 0
+	for _, key := range keys {
+		if after[key] != before[key] {
+			moved++
+			assert.Same(t, s4, after[key])
+		}
+	}
+	assert.Greater(t, moved, 0)
+	assert.Less(t, moved, numKeys/2)
+
+	// Removing the server moves its keys back, and only them
+	s4.Close()
+	assert.Equal(t, before, owners())
+
+	// The restarted server with the same PIT gets the same keys
+	s2.Close()
+	withoutS2 := owners()
+	for _, key := range keys {
+		if before[key] != s2 {
+			assert.Same(t, before[key], withoutS2[key])
+		}
+	}
+	s2Restarted := addTestHashServer("s2", "ring")
+	for key, owner := range owners() {
+		if before[key] == s2 {
+			assert.Same(t, s2Restarted, owner)
+		} else {
+			assert.Same(t, before[key], owner)
+		}
+	}
+}
+
+func TestConsistentHashOwnerPolicy(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		AutoCreateOnSub:       true,
+		AutoCreateOwnerPolicy: ChannelOwnerPolicy_ConsistentHash,
+		OwnerDisconnectPolicy: OwnerDisconnectPolicy_Transfer,
+		OwnerHashServerTags:   map[string]string{"hashGroup": "policy"},
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	servers := []*Connection{
+		addTestHashServer("p1", "policy"),
+		addTestHashServer("p2", "policy"),
+		addTestHashServer("p3", "policy"),
+	}
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+
+	const channelKey = "room-42"
+	expected := ConsistentHashOwner("", channeldpb.ChannelType_TEST, 0, channelKey)
+	if !assert.NotNil(t, expected) {
+		return
+	}
+	handleSubToAutoCreatedChannel(MessageContext{
+		MsgType:    channeldpb.MessageType_SUB_TO_CHANNEL,
+		Msg:        &channeldpb.SubscribedToChannelMessage{ChannelType: channeldpb.ChannelType_TEST},
+		Connection: client,
+		Channel:    globalChannel,
+		channelKey: channelKey,
+	})
+	ch := GetChannelByKey(channelKey)
+	if !assert.NotNil(t, ch) {
+		return
+	}
+	ch.removing = 1
+	assert.Same(t, expected, ch.ownerConnection)
+	// The owner is notified
+	resultMsg, ok := expected.latestMsg().(*channeldpb.CreateChannelResultMessage)
+	if assert.True(t, ok) {
+		assert.EqualValues(t, ch.id, resultMsg.ChannelId)
+		assert.EqualValues(t, expected.Id(), resultMsg.OwnerConnId)
+	}
+
+	// The ownership is transferred to the next server on the ring, even if it doesn't subscribe to the channel
+	expected.Close()
+	next := ConsistentHashOwner("", channeldpb.ChannelType_TEST, 0, channelKey)
+	assert.NotSame(t, expected, next)
+	assert.Contains(t, servers, next)
+	ch.disconnectedOwner = expected
+	ch.tickOwner(time.Now())
+	assert.Same(t, next, ch.ownerConnection)
+	assert.False(t, ch.IsFrozen())
+	changedMsg, ok := next.latestMsg().(*channeldpb.ChannelOwnerChangedMessage)
+	if assert.True(t, ok) {
+		assert.EqualValues(t, next.Id(), changedMsg.OwnerConnId)
+		assert.EqualValues(t, expected.Id(), changedMsg.PreviousOwnerConnId)
+	}
+}
@@ -0,0 +1,116 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOwnerDisconnectFreeze(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		OwnerDisconnectPolicy:     OwnerDisconnectPolicy_Freeze,
+		OwnerReclaimGracePeriodMs: 1000,
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	owner := addTestConnection(channeldpb.ConnectionType_SERVER)
+	owner.pit = "server1"
+	owner.setProtocolVersion(ProtocolVersion)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	client.setProtocolVersion(ProtocolVersion)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, owner)
+	ch.removing = 1
+	owner.SubscribeToChannel(ch, nil)
+	client.SubscribeToChannel(ch, nil)
+
+	owner.Close()
+	ch.tickConnections()
+	now := time.Now()
+	ch.tickOwner(now)
+	assert.True(t, ch.IsFrozen())
+	ownerMsg, ok := client.latestMsg().(*channeldpb.ChannelOwnerChangedMessage)
+	if assert.True(t, ok) {
+		assert.True(t, ownerMsg.Frozen)
+		assert.EqualValues(t, owner.Id(), ownerMsg.PreviousOwnerConnId)
+		assert.EqualValues(t, 1000, ownerMsg.ReclaimGraceMs)
+	}
+
+	// Another server can't reclaim the channel
+	server2 := addTestConnection(channeldpb.ConnectionType_SERVER)
+	server2.pit = "server2"
+	server2.SubscribeToChannel(ch, nil)
+	ch.tickOwner(now.Add(500 * time.Millisecond))
+	assert.True(t, ch.IsFrozen())
+
+	// The owner reconnects
+	newOwner := addTestConnection(channeldpb.ConnectionType_SERVER)
+	newOwner.pit = "server1"
+	newOwner.SubscribeToChannel(ch, nil)
+	ch.tickOwner(now.Add(600 * time.Millisecond))
+	assert.False(t, ch.IsFrozen())
+	assert.Same(t, newOwner, ch.ownerConnection)
+	ownerMsg, ok = client.latestMsg().(*channeldpb.ChannelOwnerChangedMessage)
+	if assert.True(t, ok) {
+		assert.False(t, ownerMsg.Frozen)
+		assert.EqualValues(t, newOwner.Id(), ownerMsg.OwnerConnId)
+	}
+
+	// Not reclaimed in the grace period
+	newOwner.Close()
+	ch.tickConnections()
+	ch.tickOwner(now)
+	assert.True(t, ch.IsFrozen())
+	ch.tickOwner(now.Add(1000 * time.Millisecond))
+	assert.False(t, ch.IsFrozen())
+	assert.EqualValues(t, 2, ch.removing)
+}
+
+func TestOwnerDisconnectTransfer(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		OwnerDisconnectPolicy: OwnerDisconnectPolicy_Transfer,
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	owner := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	server2 := addTestConnection(channeldpb.ConnectionType_SERVER)
+	server3 := addTestConnection(channeldpb.ConnectionType_SERVER)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, owner)
+	ch.removing = 1
+	owner.SubscribeToChannel(ch, nil)
+	client.SubscribeToChannel(ch, nil)
+	server2.SubscribeToChannel(ch, nil)
+	server3.SubscribeToChannel(ch, nil)
+	ch.subscribedConnections[server3].subTime = ch.subscribedConnections[server2].subTime - 1
+
+	// The earliest subscribed server takes the ownership
+	owner.Close()
+	ch.tickConnections()
+	ch.tickOwner(time.Now())
+	assert.Same(t, server3, ch.ownerConnection)
+	assert.False(t, ch.IsFrozen())
+
+	// No server left. Any server can reclaim the frozen channel.
+	server2.Close()
+	server3.Close()
+	ch.tickConnections()
+	ch.tickOwner(time.Now())
+	assert.True(t, ch.IsFrozen())
+	assert.Nil(t, ch.ownerConnection)
+
+	server4 := addTestConnection(channeldpb.ConnectionType_SERVER)
+	server4.SubscribeToChannel(ch, nil)
+	ch.tickOwner(time.Now())
+	assert.Same(t, server4, ch.ownerConnection)
+	assert.False(t, ch.IsFrozen())
+}
@@ -0,0 +1,215 @@
+package channeld
+
+import (
+	"errors"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"go.uber.org/zap"
+)
+
+var ErrReplicaOfReplica = errors.New("can't spawn a replica of a replica channel")
+var ErrReplicaOfGlobal = errors.New("can't spawn a replica of the GLOBAL channel")
+
+/*
+A replica takes over a share of the read-only subscribers of a hot channel, e.g. the global announcements or the
+tournament spectating with tens of thousands of subscribers. The primary channel fans out the merged data to each
+replica once, and the replica merges it into its own copy of the data and fans it out to its subscribers in its own
+goroutine. The subscribers don't know about the replica: the fan-out carries the primary's channelId, and the
+(un)sub messages are sent to the primary. To replicate the channel to another node, see the bridge package.
+*/
+
+// Go-routine safe - should only be called in the GLOBAL channel.
+// Spawns a replica of the channel. The replica is removed along with the channel.
+func (ch *Channel) SpawnReplica() (*Channel, error) {
+	if ch.primary != nil {
+		return nil, ErrReplicaOfReplica
+	}
+	if ch.channelType == channeldpb.ChannelType_GLOBAL {
+		return nil, ErrReplicaOfGlobal
+	}
+
+	replica, err := createTenantChannel(ch.channelType, nil, ch.tenant)
+	if err != nil {
+		return nil, err
+	}
+	replica.primary = ch
+	replica.metadata = ch.metadata
+
+	feed := &replicaFeed{replica: replica}
+	feed.SubscribeToChannel(ch, &channeldpb.ChannelSubscriptionOptions{
+		DataAccess:           Pointer(channeldpb.ChannelDataAccess_READ_ACCESS),
+		SkipSelfUpdateFanOut: Pointer(false),
+		SkipFirstFanOut:      Pointer(false),
+		FanOutDelayMs:        Pointer[int32](0),
+	})
+
+	ch.replicasLock.Lock()
+	ch.replicas = append(ch.replicas, replica)
+	ch.replicasLock.Unlock()
+
+	ch.Logger().Info("spawned replica", zap.Uint32("replicaChannelId", uint32(replica.id)))
+	return replica, nil
+}
+
+// Goroutine-safe read of the replicas of the channel.
+func (ch *Channel) Replicas() []*Channel {
+	ch.replicasLock.RLock()
+	defer ch.replicasLock.RUnlock()
+	return append([]*Channel(nil), ch.replicas...)
+}
+
+// Returns the primary channel if the channel is a replica, or nil otherwise.
+func (ch *Channel) Primary() *Channel {
+	return ch.primary
+}
+
+// The channelId in the fan-out. The replica fans out on behalf of the primary channel.
+func (ch *Channel) fanOutChannelId() common.ChannelId {
+	if ch.primary != nil {
+		return ch.primary.id
+	}
+	return ch.id
+}
+
+// Returns the channel to sub the connection to. Only the read-only subscriptions of the clients are spread over the
+// replicas, to the one with the fewest subscribers. The existing subscription stays where it is.
+func (ch *Channel) subscriptionTarget(conn ConnectionInChannel, options *channeldpb.ChannelSubscriptionOptions) *Channel {
+	if conn.GetConnectionType() != channeldpb.ConnectionType_CLIENT ||
+		options.GetDataAccess() == channeldpb.ChannelDataAccess_WRITE_ACCESS {
+		return ch
+	}
+
+	replicas := ch.Replicas()
+	if len(replicas) == 0 {
+		return ch
+	}
+
+	if subCh := ch.subscribedChannelOf(conn); subCh != nil {
+		return subCh
+	}
+
+	target := ch
+	minSubs := -1
+	for _, replica := range replicas {
+		if replica.IsRemoving() {
+			continue
+		}
+		replica.connectionsLock.RLock()
+		subs := len(replica.subscribedConnections)
+		replica.connectionsLock.RUnlock()
+		if minSubs < 0 || subs < minSubs {
+			target = replica
+			minSubs = subs
+		}
+	}
+	return target
+}
+
+// Returns the channel or the replica that the connection has subscribed to, or nil if there's none.
+func (ch *Channel) subscribedChannelOf(conn ConnectionInChannel) *Channel {
+	if _, subed := ch.GetAllConnections()[conn]; subed {
+		return ch
+	}
+	for _, replica := range ch.Replicas() {
+		if _, subed := replica.GetAllConnections()[conn]; subed {
+			return replica
+		}
+	}
+	return nil
+}
+
+// Called in RemoveChannel(). Removes the replicas of the channel, or detaches the replica from its primary.
+func (ch *Channel) removeReplicas() {
+	if ch.primary != nil {
+		ch.primary.replicasLock.Lock()
+		for i, replica := range ch.primary.replicas {
+			if replica == ch {
+				ch.primary.replicas = append(ch.primary.replicas[:i], ch.primary.replicas[i+1:]...)
+				break
+			}
+		}
+		ch.primary.replicasLock.Unlock()
+		// The feed is removed from the primary in the next tickConnections(), as it's closing.
+		return
+	}
+
+	ch.replicasLock.Lock()
+	replicas := ch.replicas
+	ch.replicas = nil
+	ch.replicasLock.Unlock()
+	for _, replica := range replicas {
+		if !replica.IsRemoving() {
+			RemoveChannel(replica)
+		}
+	}
+}
+
+// The subscriber of the primary channel that feeds the data updates and the user-space broadcasts to the replica.
+// As it stands for the clients subscribed to the replica, its connection type is CLIENT.
+type replicaFeed struct {
+	internalConnection
+	replica *Channel
+	// Only accessed in the replica's goroutine
+	synced bool
+}
+
+func (f *replicaFeed) GetConnectionType() channeldpb.ConnectionType {
+	return channeldpb.ConnectionType_CLIENT
+}
+
+func (f *replicaFeed) IsClosing() bool {
+	return f.replica.IsRemoving()
+}
+
+// Called in the primary channel's goroutine.
+func (f *replicaFeed) Send(ctx MessageContext) {
+	if f.replica.IsRemoving() {
+		return
+	}
+
+	if ctx.MsgType == channeldpb.MessageType_CHANNEL_DATA_UPDATE {
+		updateMsg, ok := ctx.Msg.(*channeldpb.ChannelDataUpdateMessage)
+		if !ok || ctx.Channel.data == nil {
+			return
+		}
+		mergeOptions := ctx.Channel.data.mergeOptions
+		f.replica.Execute(func(replica *Channel) {
+			f.applyUpdate(replica, updateMsg, mergeOptions)
+		})
+	} else if ctx.MsgType >= channeldpb.MessageType_USER_SPACE_START {
+		f.replica.Execute(func(replica *Channel) {
+			ctx.Channel = replica
+			replica.Broadcast(ctx)
+		})
+	}
+}
+
+// The first update carries the whole data of the primary channel. The feed doesn't use the keyframes, so the
+// following updates are always merged.
+func (f *replicaFeed) applyUpdate(replica *Channel, updateMsg *channeldpb.ChannelDataUpdateMessage, mergeOptions *channeldpb.ChannelDataMergeOptions) {
+	dataMsg, err := updateMsg.Data.UnmarshalNew()
+	if err != nil {
+		replica.Logger().Error("failed to unmarshal the data update from the primary channel", zap.Error(err))
+		return
+	}
+
+	if !f.synced || replica.data == nil {
+		replica.InitData(dataMsg, mergeOptions)
+		f.synced = true
+		return
+	}
+	replica.data.OnUpdate(dataMsg, replica.GetTime(), 0, nil)
+}
+
+func (f *replicaFeed) SubscribeToChannel(ch *Channel, options *channeldpb.ChannelSubscriptionOptions) (*ChannelSubscription, bool) {
+	return subscribeInternal(f, ch, options)
+}
+
+func (f *replicaFeed) UnsubscribeFromChannel(ch *Channel) (*channeldpb.ChannelSubscriptionOptions, error) {
+	return unsubscribeInternal(f, ch)
+}
+
+func (f *replicaFeed) Logger() *Logger {
+	return f.replica.Logger()
+}
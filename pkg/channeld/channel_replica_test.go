@@ -0,0 +1,131 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSpawnReplica(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	primary, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	// Stop the channel.Tick() goroutine
+	primary.removing = 1
+
+	_, err := globalChannel.SpawnReplica()
+	assert.ErrorIs(t, err, ErrReplicaOfGlobal)
+
+	r1, err := primary.SpawnReplica()
+	assert.NoError(t, err)
+	r2, _ := primary.SpawnReplica()
+	assert.Equal(t, primary, r1.Primary())
+	assert.Nil(t, primary.Primary())
+	assert.Equal(t, []*Channel{r1, r2}, primary.Replicas())
+	assert.Equal(t, primary.id, r1.fanOutChannelId())
+	_, err = r1.SpawnReplica()
+	assert.ErrorIs(t, err, ErrReplicaOfReplica)
+
+	// The read-only client subscriptions are spread over the replicas
+	c1 := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	c2 := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	c3 := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	target := primary.subscriptionTarget(c1, nil)
+	assert.Equal(t, r1, target)
+	c1.SubscribeToChannel(target, nil)
+	target = primary.subscriptionTarget(c2, nil)
+	assert.Equal(t, r2, target)
+	c2.SubscribeToChannel(target, nil)
+	// The existing subscription stays where it is
+	assert.Equal(t, r1, primary.subscriptionTarget(c1, nil))
+	assert.Equal(t, r1, primary.subscribedChannelOf(c1))
+	assert.Nil(t, primary.subscribedChannelOf(c3))
+
+	// The writable and the server subscriptions stay in the primary channel
+	assert.Equal(t, primary, primary.subscriptionTarget(c3, &channeldpb.ChannelSubscriptionOptions{
+		DataAccess: channeldpb.ChannelDataAccess_WRITE_ACCESS.Enum(),
+	}))
+	assert.Equal(t, primary, primary.subscriptionTarget(server, nil))
+
+	RemoveChannel(primary)
+	assert.True(t, r1.IsRemoving())
+	assert.True(t, r2.IsRemoving())
+	assert.Empty(t, primary.Replicas())
+}
+
+func TestReplicaFeed(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	// Not to depend on the settings of the GLOBAL channel type, which the other tests may change
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{TickIntervalMs: 10, DefaultFanOutIntervalMs: 20}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	primary, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	// Stop the channel.Tick() goroutine
+	primary.removing = 1
+	primary.InitData(&testpb.TestChannelDataMessage{Text: "a", Num: 1}, nil)
+	primary.tickInterval = time.Hour
+
+	replica, _ := primary.SpawnReplica()
+	// Also removes the replica
+	defer RemoveChannel(primary)
+	replicaData := func() *testpb.TestChannelDataMessage {
+		result := make(chan *testpb.TestChannelDataMessage, 1)
+		replica.Execute(func(ch *Channel) {
+			if ch.data == nil {
+				result <- nil
+				return
+			}
+			result <- proto.Clone(ch.GetDataMessage()).(*testpb.TestChannelDataMessage)
+		})
+		select {
+		case data := <-result:
+			return data
+		case <-time.After(time.Second):
+			return nil
+		}
+	}
+
+	// The first fan-out carries the whole data
+	fanOutIntervalMs := primary.settings().DefaultFanOutIntervalMs
+	t0 := primary.GetTime()
+	primary.tickData(t0.AddMs(fanOutIntervalMs))
+	data := replicaData()
+	if assert.NotNil(t, data) {
+		assert.Equal(t, "a", data.Text)
+		assert.EqualValues(t, 1, data.Num)
+	}
+
+	// The following fan-outs are merged
+	primary.Data().OnUpdate(&testpb.TestChannelDataMessage{Num: 2}, t0.AddMs(fanOutIntervalMs*2), server.Id(), nil)
+	primary.tickData(t0.AddMs(fanOutIntervalMs * 3))
+	data = replicaData()
+	if assert.NotNil(t, data) {
+		assert.Equal(t, "a", data.Text)
+		assert.EqualValues(t, 2, data.Num)
+	}
+
+	// The replica doesn't accept the data update from the subscribers
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	handleChannelDataUpdate(MessageContext{
+		MsgType:    channeldpb.MessageType_CHANNEL_DATA_UPDATE,
+		Msg:        &channeldpb.ChannelDataUpdateMessage{},
+		Connection: client,
+		Channel:    replica,
+		ChannelId:  uint32(replica.id),
+	})
+	errMsg, ok := client.latestMsg().(*channeldpb.ErrorMessage)
+	if assert.True(t, ok) {
+		assert.Equal(t, channeldpb.ErrorMessage_ACCESS_DENIED, errMsg.Code)
+	}
+}
@@ -0,0 +1,123 @@
+package channeld
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+)
+
+// Restores the persisted channels of a type at startup, e.g. from the storage written by the IdleChannelPersister.
+type ChannelRestoreFunc func() error
+
+type channelRestorer struct {
+	channelType channeldpb.ChannelType
+	restore     ChannelRestoreFunc
+	dependsOn   []channeldpb.ChannelType
+}
+
+var channelRestorers = make(map[channeldpb.ChannelType]*channelRestorer)
+
+var ErrChannelRestoreCycle = errors.New("the channel restorers depend on each other")
+
+// 1 if the channels are being restored, so the client connections are held. See waitForWarmUp().
+var warmingUp int32
+var warmUpDone = make(chan struct{})
+
+// Registers the function that restores the channels of the type at startup, after the channels of the types it depends on
+// have been restored, e.g. the SUBWORLD channels depend on the GLOBAL channel, and the ENTITY channels depend on the SPATIAL
+// channels. The client connections are not accepted until RestoreChannels() completes.
+func RegisterChannelRestorer(channelType channeldpb.ChannelType, restore ChannelRestoreFunc, dependsOn ...channeldpb.ChannelType) {
+	channelRestorers[channelType] = &channelRestorer{
+		channelType: channelType,
+		restore:     restore,
+		dependsOn:   dependsOn,
+	}
+	atomic.StoreInt32(&warmingUp, 1)
+}
+
+// Returns true if the client connections are held until the channels are restored. Goroutine-safe.
+func IsWarmingUp() bool {
+	return atomic.LoadInt32(&warmingUp) == 1
+}
+
+// Runs the registered channel restorers in the order of their dependencies, then releases the held client connections,
+// even if any of the restorers fails. Should be called after InitChannels().
+func RestoreChannels() error {
+	defer finishWarmUp()
+
+	restorers, err := sortChannelRestorers()
+	if err != nil {
+		return err
+	}
+	for _, r := range restorers {
+		if err := r.restore(); err != nil {
+			return fmt.Errorf("failed to restore the %s channels: %w", r.channelType, err)
+		}
+		rootLogger.Info("restored channels", zap.String("channelType", r.channelType.String()))
+	}
+	return nil
+}
+
+// Returns the restorers in the order that each one comes after the ones it depends on. The dependencies that have no restorer
+// are considered restored, e.g. the GLOBAL channel which is created by InitChannels().
+func sortChannelRestorers() ([]*channelRestorer, error) {
+	channelTypes := make([]channeldpb.ChannelType, 0, len(channelRestorers))
+	for channelType := range channelRestorers {
+		channelTypes = append(channelTypes, channelType)
+	}
+	// Deterministic order of the independent restorers
+	sort.Slice(channelTypes, func(i, j int) bool { return channelTypes[i] < channelTypes[j] })
+
+	sorted := make([]*channelRestorer, 0, len(channelRestorers))
+	// false = visiting, true = visited
+	visited := make(map[channeldpb.ChannelType]bool)
+	var visit func(channelType channeldpb.ChannelType) error
+	visit = func(channelType channeldpb.ChannelType) error {
+		r, exists := channelRestorers[channelType]
+		if !exists {
+			return nil
+		}
+		if done, visiting := visited[channelType]; visiting {
+			if !done {
+				return fmt.Errorf("%w: %s", ErrChannelRestoreCycle, channelType)
+			}
+			return nil
+		}
+		visited[channelType] = false
+		for _, dependency := range r.dependsOn {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		visited[channelType] = true
+		sorted = append(sorted, r)
+		return nil
+	}
+	for _, channelType := range channelTypes {
+		if err := visit(channelType); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+func finishWarmUp() {
+	if atomic.CompareAndSwapInt32(&warmingUp, 1, 0) {
+		close(warmUpDone)
+		rootLogger.Info("released the held client connections")
+	}
+}
+
+// Blocks the client listener until the channels are restored, so the client connections wait in the backlog instead of
+// getting errors from the channels that don't exist yet. The other connections, e.g. the servers, are not held.
+func waitForWarmUp(t channeldpb.ConnectionType) {
+	if t != channeldpb.ConnectionType_CLIENT || !IsWarmingUp() {
+		return
+	}
+	rootLogger.Info("holding the client connections until the channels are restored")
+	<-warmUpDone
+}
@@ -0,0 +1,82 @@
+package channeld
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func resetChannelRestorers() {
+	channelRestorers = make(map[channeldpb.ChannelType]*channelRestorer)
+	warmingUp = 0
+	warmUpDone = make(chan struct{})
+}
+
+func TestRestoreChannels(t *testing.T) {
+	InitLogs()
+	resetChannelRestorers()
+	defer resetChannelRestorers()
+
+	restored := make([]channeldpb.ChannelType, 0)
+	restorer := func(channelType channeldpb.ChannelType) ChannelRestoreFunc {
+		return func() error {
+			restored = append(restored, channelType)
+			return nil
+		}
+	}
+	RegisterChannelRestorer(channeldpb.ChannelType_ENTITY, restorer(channeldpb.ChannelType_ENTITY), channeldpb.ChannelType_SPATIAL)
+	RegisterChannelRestorer(channeldpb.ChannelType_SUBWORLD, restorer(channeldpb.ChannelType_SUBWORLD), channeldpb.ChannelType_GLOBAL)
+	RegisterChannelRestorer(channeldpb.ChannelType_SPATIAL, restorer(channeldpb.ChannelType_SPATIAL), channeldpb.ChannelType_GLOBAL)
+	RegisterChannelRestorer(channeldpb.ChannelType_GLOBAL, restorer(channeldpb.ChannelType_GLOBAL))
+	assert.True(t, IsWarmingUp())
+
+	// The client listener is held until the restore completes
+	released := make(chan struct{})
+	go func() {
+		waitForWarmUp(channeldpb.ConnectionType_CLIENT)
+		close(released)
+	}()
+	// The server listener is not held
+	waitForWarmUp(channeldpb.ConnectionType_SERVER)
+	select {
+	case <-released:
+		assert.Fail(t, "the client connections should be held")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	assert.NoError(t, RestoreChannels())
+	assert.Equal(t, []channeldpb.ChannelType{
+		channeldpb.ChannelType_GLOBAL,
+		channeldpb.ChannelType_SUBWORLD,
+		channeldpb.ChannelType_SPATIAL,
+		channeldpb.ChannelType_ENTITY,
+	}, restored)
+	assert.False(t, IsWarmingUp())
+	select {
+	case <-released:
+	case <-time.After(time.Second):
+		assert.Fail(t, "the client connections should be released")
+	}
+}
+
+func TestRestoreChannelsFailure(t *testing.T) {
+	InitLogs()
+	resetChannelRestorers()
+	defer resetChannelRestorers()
+
+	noop := func() error { return nil }
+	RegisterChannelRestorer(channeldpb.ChannelType_SPATIAL, noop, channeldpb.ChannelType_ENTITY)
+	RegisterChannelRestorer(channeldpb.ChannelType_ENTITY, noop, channeldpb.ChannelType_SPATIAL)
+	assert.ErrorIs(t, RestoreChannels(), ErrChannelRestoreCycle)
+	// Released anyway
+	assert.False(t, IsWarmingUp())
+
+	resetChannelRestorers()
+	errRestore := errors.New("storage unavailable")
+	RegisterChannelRestorer(channeldpb.ChannelType_SUBWORLD, func() error { return errRestore })
+	assert.ErrorIs(t, RestoreChannels(), errRestore)
+	assert.False(t, IsWarmingUp())
+}
@@ -0,0 +1,155 @@
+package channeld
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+)
+
+type retainedBroadcast struct {
+	seq    uint64
+	ctx    MessageContext
+	filter TagFilter
+	// See broadcastSenderUserId()
+	senderUserId string
+}
+
+// Parses and clears the tag filter of the user-space message. Returns false if the filter is invalid.
+func takeBroadcastTagFilter(ctx MessageContext, msg *channeldpb.ServerForwardMessage) (TagFilter, bool) {
+	if msg.TagFilter == "" {
+		return nil, true
+	}
+	filter, err := ParseTagFilter(msg.TagFilter)
+	if err != nil {
+		ctx.Connection.Logger().Error("failed to parse the tag filter of the broadcast message",
+			zap.Uint32("msgType", uint32(ctx.MsgType)),
+			zap.String("tagFilter", msg.TagFilter),
+			zap.Error(err),
+		)
+		return nil, false
+	}
+	// The receivers don't need to know the filter
+	msg.TagFilter = ""
+	return filter, true
+}
+
+// Assigns the sequence number to the user-space message and broadcasts it. If the channel retains or archives the broadcasts,
+// the message is also saved for the subscribers that resume after reconnecting.
+// Should be called in the channel's goroutine.
+func (ch *Channel) broadcastUserMessage(ctx MessageContext, msg *channeldpb.ServerForwardMessage) {
+	tagFilter := msg.TagFilter
+	filter, ok := takeBroadcastTagFilter(ctx, msg)
+	if !ok {
+		return
+	}
+	senderUserId := broadcastSenderUserId(ctx, msg)
+	if !ch.checkChatFlood(ctx, msg, senderUserId) {
+		return
+	}
+	if !ch.filterContent(ctx, msg) {
+		return
+	}
+	ch.deliverUserMessage(ctx, msg, tagFilter, filter, senderUserId, nil)
+}
+
+// The connections that a user-space message has been sent to. Shared by the channels that deliver the same message,
+// so each connection receives it only once.
+type broadcastRecipients struct {
+	lock  sync.Mutex
+	conns map[ConnectionInChannel]struct{}
+}
+
+func newBroadcastRecipients() *broadcastRecipients {
+	return &broadcastRecipients{conns: make(map[ConnectionInChannel]struct{})}
+}
+
+// Returns false if the message has been sent to the connection.
+func (r *broadcastRecipients) add(conn ConnectionInChannel) bool {
+	if r == nil {
+		return true
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, exists := r.conns[conn]; exists {
+		return false
+	}
+	r.conns[conn] = struct{}{}
+	return true
+}
+
+// Retains or archives the user-space message that has passed the checks in broadcastUserMessage(), and sends it to the subscribers.
+// If recipients is not nil, the connections that have received the message are skipped.
+// Should be called in the channel's goroutine.
+func (ch *Channel) deliverUserMessage(ctx MessageContext, msg *channeldpb.ServerForwardMessage, tagFilter string, filter TagFilter, senderUserId string, recipients *broadcastRecipients) {
+	retentionSize := ch.settings().BroadcastRetentionSize
+	if ch.isArchivingBroadcasts() {
+		ch.broadcastSeq++
+		msg.Seq = ch.broadcastSeq
+		// The archive replaces the retained broadcasts
+		ch.archiveBroadcast(ctx, msg, tagFilter, senderUserId)
+	} else if retentionSize > 0 {
+		ch.broadcastSeq++
+		msg.Seq = ch.broadcastSeq
+
+		if ch.retainedBroadcasts == nil {
+			ch.retainedBroadcasts = list.New()
+		}
+		ch.retainedBroadcasts.PushBack(&retainedBroadcast{seq: msg.Seq, ctx: ctx, filter: filter, senderUserId: senderUserId})
+		for ch.retainedBroadcasts.Len() > retentionSize {
+			ch.retainedBroadcasts.Remove(ch.retainedBroadcasts.Front())
+		}
+	}
+
+	if filter == nil && senderUserId == "" && recipients == nil {
+		ch.Broadcast(ctx)
+		return
+	}
+
+	for conn := range ch.GetAllConnections() {
+		if ch.shouldBroadcastTo(ctx, conn) && (filter == nil || filter.Match(conn)) && !isBlockedBy(conn, senderUserId) && recipients.add(conn) {
+			conn.Send(ctx)
+		}
+	}
+}
+
+// Sends the retained broadcasts with seq greater than fromSeq to the connection, in order.
+// Should be called in the channel's goroutine.
+func (ch *Channel) replayBroadcasts(conn ConnectionInChannel, fromSeq uint64) {
+	if ch.isArchivingBroadcasts() {
+		ch.replayArchivedBroadcasts(conn, fromSeq)
+		return
+	}
+
+	if ch.retainedBroadcasts == nil || ch.retainedBroadcasts.Len() == 0 {
+		return
+	}
+
+	oldestSeq := ch.retainedBroadcasts.Front().Value.(*retainedBroadcast).seq
+	if fromSeq+1 < oldestSeq {
+		conn.Logger().Warn("some broadcasts are no longer retained and will not be replayed",
+			zap.Uint32("channelId", uint32(ch.id)),
+			zap.Uint64("resumeFromSeq", fromSeq),
+			zap.Uint64("oldestSeq", oldestSeq),
+		)
+	}
+
+	replayed := 0
+	for e := ch.retainedBroadcasts.Front(); e != nil; e = e.Next() {
+		rb := e.Value.(*retainedBroadcast)
+		if rb.seq <= fromSeq || !ch.shouldBroadcastTo(rb.ctx, conn) || (rb.filter != nil && !rb.filter.Match(conn)) || isBlockedBy(conn, rb.senderUserId) {
+			continue
+		}
+		ctx := rb.ctx
+		ctx.StubId = 0
+		conn.Send(ctx)
+		replayed++
+	}
+
+	conn.Logger().Debug("replayed retained broadcasts",
+		zap.Uint32("channelId", uint32(ch.id)),
+		zap.Uint64("resumeFromSeq", fromSeq),
+		zap.Int("replayed", replayed),
+	)
+}
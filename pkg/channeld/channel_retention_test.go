@@ -0,0 +1,138 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestBroadcastRetention(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		BroadcastRetentionSize: 3,
+		ACLSettings:            ACLSettingsType{Sub: ChannelAccessLevel_Any},
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+
+	for i := 0; i < 5; i++ {
+		HandleServerToClientUserMessage(MessageContext{
+			MsgType:    channeldpb.MessageType_USER_SPACE_START,
+			Msg:        &channeldpb.ServerForwardMessage{Payload: []byte{byte(i)}},
+			Broadcast:  uint32(channeldpb.BroadcastType_ALL_BUT_OWNER),
+			Connection: server,
+			Channel:    ch,
+		})
+	}
+	assert.EqualValues(t, 5, ch.broadcastSeq)
+	assert.Equal(t, 3, ch.retainedBroadcasts.Len())
+
+	// The client resumes from seq 3, so only seq 4 and 5 are replayed
+	handleSubToChannel(MessageContext{
+		MsgType:    channeldpb.MessageType_SUB_TO_CHANNEL,
+		Msg:        &channeldpb.SubscribedToChannelMessage{ConnId: uint32(client.Id()), ResumeFromSeq: proto.Uint64(3)},
+		Connection: client,
+		Channel:    ch,
+	})
+	var seqs []uint64
+	for _, msg := range client.testQueue() {
+		if fwd, ok := msg.(*channeldpb.ServerForwardMessage); ok {
+			seqs = append(seqs, fwd.Seq)
+		}
+	}
+	assert.Equal(t, []uint64{4, 5}, seqs)
+
+	// The owner is excluded by the broadcast type
+	ch.replayBroadcasts(server, 0)
+	for _, msg := range server.testQueue() {
+		_, isForward := msg.(*channeldpb.ServerForwardMessage)
+		assert.False(t, isForward)
+	}
+}
+
+func TestAdjacentChannelsBroadcast(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_SPATIAL] = ChannelSettingsType{BroadcastRetentionSize: 10}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_SPATIAL)
+
+	prevController := spatialController
+	defer func() { spatialController = prevController }()
+	// 2-by-2-grid world, so all the other channels are adjacent
+	spatialController = &StaticGrid2DSpatialController{
+		WorldOffsetX: -5,
+		WorldOffsetZ: -5,
+		GridWidth:    5,
+		GridHeight:   5,
+		GridCols:     2,
+		GridRows:     2,
+		ServerCols:   1,
+		ServerRows:   1,
+	}
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	channels := make([]*Channel, 4)
+	for i := range channels {
+		channels[i] = createChannelWithId(GlobalSettings.SpatialChannelIdStart+common.ChannelId(i), channeldpb.ChannelType_SPATIAL, server, "")
+	}
+
+	sender := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	sender.SetTag(UserIdTag, "troll")
+	sender.SubscribeToChannel(channels[0], nil)
+	// Subscribed to two of the channels
+	both := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	both.SubscribeToChannel(channels[0], nil)
+	both.SubscribeToChannel(channels[1], nil)
+	blocking := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	blocking.BlockUser("troll")
+	blocking.SubscribeToChannel(channels[2], nil)
+	other := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	other.SubscribeToChannel(channels[3], nil)
+
+	countForwards := func(c *Connection) int {
+		n := 0
+		for _, msg := range c.testQueue() {
+			if _, ok := msg.(*channeldpb.ServerForwardMessage); ok {
+				n++
+			}
+		}
+		return n
+	}
+
+	// The server's broadcast on behalf of the client
+	HandleServerToClientUserMessage(MessageContext{
+		MsgType:    channeldpb.MessageType_USER_SPACE_START,
+		Msg:        &channeldpb.ServerForwardMessage{ClientConnId: uint32(sender.Id()), Payload: []byte{1}},
+		Broadcast:  uint32(channeldpb.BroadcastType_ADJACENT_CHANNELS),
+		Connection: server,
+		Channel:    channels[0],
+	})
+	// Wait for the adjacent channels to deliver the message
+	time.Sleep(50 * time.Millisecond)
+
+	assert.Equal(t, 0, countForwards(sender))
+	assert.Equal(t, 1, countForwards(both))
+	assert.Equal(t, 0, countForwards(blocking))
+	assert.Equal(t, 1, countForwards(other))
+
+	// Every channel retains the message for the subscribers that resume
+	for _, ch := range channels {
+		ch.Execute(func(ch *Channel) {
+			assert.EqualValues(t, 1, ch.broadcastSeq)
+			assert.Equal(t, 1, ch.retainedBroadcasts.Len())
+		})
+	}
+	time.Sleep(50 * time.Millisecond)
+}
@@ -0,0 +1,84 @@
+package channeld
+
+import (
+	"container/heap"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+)
+
+type scheduledBroadcast struct {
+	time ChannelTime
+	// The order of scheduling, to keep the messages scheduled at the same time in order
+	seq uint64
+	ctx MessageContext
+}
+
+// Min-heap of the scheduled broadcasts, ordered by the time.
+type scheduledBroadcastQueue []*scheduledBroadcast
+
+func (q scheduledBroadcastQueue) Len() int {
+	return len(q)
+}
+
+func (q scheduledBroadcastQueue) Less(i, j int) bool {
+	if q[i].time == q[j].time {
+		return q[i].seq < q[j].seq
+	}
+	return q[i].time < q[j].time
+}
+
+func (q scheduledBroadcastQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *scheduledBroadcastQueue) Push(x any) {
+	*q = append(*q, x.(*scheduledBroadcast))
+}
+
+func (q *scheduledBroadcastQueue) Pop() any {
+	old := *q
+	n := len(old)
+	sb := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return sb
+}
+
+// Holds the user-space message and forwards it on the first tick at or after the channel time t.
+// The message is forwarded in the same way as HandleServerToClientUserMessage(), even if the sender has disconnected by then.
+// Should be called in the channel's goroutine.
+func (ch *Channel) ScheduleBroadcast(ctx MessageContext, t ChannelTime) {
+	ch.scheduleSeq++
+	heap.Push(&ch.scheduledBroadcasts, &scheduledBroadcast{time: t, seq: ch.scheduleSeq, ctx: ctx})
+}
+
+func (ch *Channel) tickScheduledBroadcasts(t ChannelTime) {
+	for ch.scheduledBroadcasts.Len() > 0 && ch.scheduledBroadcasts[0].time <= t {
+		sb := heap.Pop(&ch.scheduledBroadcasts).(*scheduledBroadcast)
+		HandleServerToClientUserMessage(sb.ctx)
+	}
+}
+
+// Takes the delay of the user-space message from the channel owner, and schedules the message.
+// Returns false if the message has no delay and should be forwarded immediately.
+func scheduleDelayedBroadcast(ctx MessageContext, msg *channeldpb.ServerForwardMessage) bool {
+	if msg.DelayMs == 0 {
+		return false
+	}
+
+	if ctx.Connection != ctx.Channel.ownerConnection {
+		ctx.Connection.Logger().Error("only the channel owner can schedule the user-space message",
+			zap.Uint32("msgType", uint32(ctx.MsgType)),
+			zap.Uint32("channelId", uint32(ctx.Channel.id)),
+		)
+		// Don't forward the message earlier than expected
+		return true
+	}
+
+	delayMs := msg.DelayMs
+	// The receivers don't need to know the delay
+	msg.DelayMs = 0
+	ctx.Channel.ScheduleBroadcast(ctx, ctx.Channel.GetTime().AddMs(delayMs))
+	return true
+}
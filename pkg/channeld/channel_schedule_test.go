@@ -0,0 +1,65 @@
+package channeld
+
+import (
+	"testing"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduledBroadcast(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	owner := addTestConnection(channeldpb.ConnectionType_SERVER)
+	other := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, owner)
+	client.SubscribeToChannel(ch, nil)
+
+	forward := func(conn *Connection, payload byte, delayMs uint32) {
+		HandleServerToClientUserMessage(MessageContext{
+			MsgType:    channeldpb.MessageType_USER_SPACE_START,
+			Msg:        &channeldpb.ServerForwardMessage{Payload: []byte{payload}, DelayMs: delayMs},
+			Broadcast:  uint32(channeldpb.BroadcastType_ALL_BUT_OWNER),
+			Connection: conn,
+			Channel:    ch,
+		})
+	}
+	receivedNum := 0
+	received := func() []byte {
+		var payloads []byte
+		queue := client.testQueue()
+		for _, msg := range queue[receivedNum:] {
+			if fwd, ok := msg.(*channeldpb.ServerForwardMessage); ok {
+				// The delay is cleared before forwarding
+				assert.Zero(t, fwd.DelayMs)
+				payloads = append(payloads, fwd.Payload[0])
+			}
+		}
+		receivedNum = len(queue)
+		return payloads
+	}
+
+	// The delays are long enough that the channel's own ticks don't forward the messages during the test
+	now := ch.GetTime()
+	forward(owner, 1, 20000)
+	forward(owner, 2, 10000)
+	forward(owner, 3, 10000)
+	// Only the owner can schedule
+	forward(other, 4, 10000)
+	assert.Equal(t, 3, ch.scheduledBroadcasts.Len())
+	assert.Empty(t, received())
+
+	ch.tickScheduledBroadcasts(now.AddMs(5000))
+	assert.Empty(t, received())
+
+	// The messages scheduled at the same time keep the order
+	ch.tickScheduledBroadcasts(now.AddMs(15000))
+	assert.Equal(t, []byte{2, 3}, received())
+
+	ch.tickScheduledBroadcasts(now.AddMs(30000))
+	assert.Equal(t, []byte{1}, received())
+	assert.Zero(t, ch.scheduledBroadcasts.Len())
+}
@@ -0,0 +1,184 @@
+package channeld
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// How often the channels sample their statistics
+	channelStatsSampleInterval = time.Second
+	// How many samples the rates are averaged over
+	channelStatsWindowSamples = 10
+)
+
+// The rolling statistics of a channel, averaged over the last channelStatsWindowSamples samples.
+type ChannelStats struct {
+	ChannelId          uint32
+	ChannelType        string
+	WindowMs           uint32
+	UpdatesPerSec      float64
+	FanOutsPerSec      float64
+	SubscribesPerSec   float64
+	UnsubscribesPerSec float64
+	// The failed messages (see replyError()) and the panics
+	ErrorsPerSec    float64
+	SubscriberCount int
+	DataBytes       int
+	// The change of DataBytes in the window
+	DataBytesDelta int
+	// The CPU time spent in the ticks, in percent of the window. See ChannelSettingsType.DedicatedThread.
+	CpuPercent float64
+}
+
+// The accumulated counts of the channel. Goroutine-safe.
+type channelStatsCounters struct {
+	updates      uint64
+	fanOuts      uint64
+	subscribes   uint64
+	unsubscribes uint64
+	errors       uint64
+	// The CPU time spent in the ticks. See accountCpuTime().
+	cpuNanos uint64
+}
+
+type channelStatsSample struct {
+	time         time.Time
+	updates      uint64
+	fanOuts      uint64
+	subscribes   uint64
+	unsubscribes uint64
+	errors       uint64
+	cpuNanos     uint64
+	dataBytes    int
+}
+
+func (ch *Channel) countStats(counter *uint64) {
+	atomic.AddUint64(counter, 1)
+}
+
+// Samples the statistics, and pushes them to the owner every ChannelSettingsType.StatsPushIntervalMs.
+// Should be called in the channel's goroutine.
+func (ch *Channel) tickStats(now time.Time) {
+	if now.Sub(ch.lastStatsSampleTime) < channelStatsSampleInterval {
+		return
+	}
+	ch.lastStatsSampleTime = now
+
+	sample := channelStatsSample{
+		time:         now,
+		updates:      atomic.LoadUint64(&ch.statsCounters.updates),
+		fanOuts:      atomic.LoadUint64(&ch.statsCounters.fanOuts),
+		subscribes:   atomic.LoadUint64(&ch.statsCounters.subscribes),
+		unsubscribes: atomic.LoadUint64(&ch.statsCounters.unsubscribes),
+		errors:       atomic.LoadUint64(&ch.statsCounters.errors),
+		cpuNanos:     atomic.LoadUint64(&ch.statsCounters.cpuNanos),
+	}
+	if ch.data != nil && ch.data.msg != nil {
+		sample.dataBytes = proto.Size(ch.data.msg)
+	}
+	ch.statsSamples = append(ch.statsSamples, sample)
+	if len(ch.statsSamples) > channelStatsWindowSamples+1 {
+		ch.statsSamples = ch.statsSamples[1:]
+	}
+
+	oldest := ch.statsSamples[0]
+	stats := &ChannelStats{
+		ChannelId:      uint32(ch.id),
+		ChannelType:    ch.channelType.String(),
+		WindowMs:       uint32(sample.time.Sub(oldest.time).Milliseconds()),
+		DataBytes:      sample.dataBytes,
+		DataBytesDelta: sample.dataBytes - oldest.dataBytes,
+	}
+	if seconds := sample.time.Sub(oldest.time).Seconds(); seconds > 0 {
+		stats.UpdatesPerSec = float64(sample.updates-oldest.updates) / seconds
+		stats.FanOutsPerSec = float64(sample.fanOuts-oldest.fanOuts) / seconds
+		stats.SubscribesPerSec = float64(sample.subscribes-oldest.subscribes) / seconds
+		stats.UnsubscribesPerSec = float64(sample.unsubscribes-oldest.unsubscribes) / seconds
+		stats.ErrorsPerSec = float64(sample.errors-oldest.errors) / seconds
+		stats.CpuPercent = time.Duration(sample.cpuNanos-oldest.cpuNanos).Seconds() / seconds * 100
+	}
+	ch.connectionsLock.RLock()
+	stats.SubscriberCount = len(ch.subscribedConnections)
+	ch.connectionsLock.RUnlock()
+	ch.stats.Store(stats)
+
+	interval := time.Duration(ch.settings().StatsPushIntervalMs) * time.Millisecond
+	if interval > 0 && ch.HasOwner() && now.Sub(ch.lastStatsPushTime) >= interval {
+		ch.lastStatsPushTime = now
+		ch.SendToOwner(uint32(channeldpb.MessageType_CHANNEL_STATS), stats.toMessage())
+	}
+}
+
+func (stats *ChannelStats) toMessage() *channeldpb.ChannelStatsMessage {
+	return &channeldpb.ChannelStatsMessage{
+		WindowMs:           stats.WindowMs,
+		UpdatesPerSec:      stats.UpdatesPerSec,
+		FanOutsPerSec:      stats.FanOutsPerSec,
+		SubscribesPerSec:   stats.SubscribesPerSec,
+		UnsubscribesPerSec: stats.UnsubscribesPerSec,
+		SubscriberCount:    uint32(stats.SubscriberCount),
+		DataBytes:          uint32(stats.DataBytes),
+		DataBytesDelta:     int32(stats.DataBytesDelta),
+		CpuPercent:         stats.CpuPercent,
+	}
+}
+
+// Returns the latest sampled statistics of the channel, or nil if not sampled yet. Goroutine-safe.
+func (ch *Channel) Stats() *ChannelStats {
+	stats, _ := ch.stats.Load().(*ChannelStats)
+	return stats
+}
+
+// The admin API that reports the rolling statistics of the channels, in descending order of UpdatesPerSec, e.g.
+//
+//	/debug/channels               all the channels
+//	/debug/channels?top=10        the 10 busiest channels
+//	/debug/channels?channelId=1   the channel 1 only
+func HandleChannelStats(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	report := make([]*ChannelStats, 0)
+	if str := query.Get("channelId"); str != "" {
+		channelId, err := strconv.ParseUint(str, 10, 32)
+		if err != nil {
+			http.Error(w, "invalid channelId", http.StatusBadRequest)
+			return
+		}
+		ch := GetChannel(common.ChannelId(channelId))
+		if ch == nil {
+			http.Error(w, "channel not found", http.StatusNotFound)
+			return
+		}
+		if stats := ch.Stats(); stats != nil {
+			report = append(report, stats)
+		}
+	} else {
+		allChannels.Range(func(_ common.ChannelId, ch *Channel) bool {
+			if stats := ch.Stats(); stats != nil {
+				report = append(report, stats)
+			}
+			return true
+		})
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].UpdatesPerSec > report[j].UpdatesPerSec
+	})
+	if top, err := strconv.Atoi(query.Get("top")); err == nil && top >= 0 && len(report) > top {
+		report = report[:top]
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		rootLogger.Error("failed to write channel stats report", zap.Error(err))
+	}
+}
@@ -0,0 +1,114 @@
+package channeld
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelStats(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{StatsPushIntervalMs: 5000}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client1 := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	client2 := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	ch.removing = 1
+	assert.Nil(t, ch.Stats())
+
+	now := time.Now()
+	ch.tickStats(now)
+	if assert.NotNil(t, ch.Stats()) {
+		assert.Zero(t, ch.Stats().UpdatesPerSec)
+	}
+	// Pushed to the owner with the first sample
+	msg, ok := server.latestMsg().(*channeldpb.ChannelStatsMessage)
+	assert.True(t, ok)
+	assert.Zero(t, msg.WindowMs)
+
+	client1.SubscribeToChannel(ch, nil)
+	client2.SubscribeToChannel(ch, nil)
+	client2.UnsubscribeFromChannel(ch)
+	for i := 0; i < 20; i++ {
+		ch.countStats(&ch.statsCounters.updates)
+	}
+	// Not sampled yet
+	ch.tickStats(now.Add(500 * time.Millisecond))
+	assert.Zero(t, ch.Stats().UpdatesPerSec)
+
+	ch.tickStats(now.Add(2 * time.Second))
+	stats := ch.Stats()
+	assert.EqualValues(t, 2000, stats.WindowMs)
+	assert.Equal(t, 10.0, stats.UpdatesPerSec)
+	assert.Equal(t, 1.0, stats.SubscribesPerSec)
+	assert.Equal(t, 0.5, stats.UnsubscribesPerSec)
+	assert.Equal(t, 1, stats.SubscriberCount)
+	assert.Len(t, server.testQueue(), 1)
+
+	// The window is rolling
+	for i := 3; i <= channelStatsWindowSamples+5; i++ {
+		ch.tickStats(now.Add(time.Duration(i) * time.Second))
+	}
+	stats = ch.Stats()
+	assert.EqualValues(t, channelStatsWindowSamples*1000, stats.WindowMs)
+	assert.Zero(t, stats.UpdatesPerSec)
+	assert.Zero(t, stats.SubscribesPerSec)
+	msg, ok = server.latestMsg().(*channeldpb.ChannelStatsMessage)
+	if assert.True(t, ok) {
+		assert.EqualValues(t, 1, msg.SubscriberCount)
+	}
+	assert.Len(t, server.testQueue(), 4)
+}
+
+func TestHandleChannelStats(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	busy, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	busy.removing = 1
+	idle, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	idle.removing = 1
+	now := time.Now()
+	busy.tickStats(now)
+	idle.tickStats(now)
+	busy.countStats(&busy.statsCounters.updates)
+	busy.tickStats(now.Add(time.Second))
+	idle.tickStats(now.Add(time.Second))
+
+	serve := func(query string) (int, []*ChannelStats) {
+		rec := httptest.NewRecorder()
+		HandleChannelStats(rec, httptest.NewRequest("GET", "/debug/channels?"+query, nil))
+		var report []*ChannelStats
+		json.Unmarshal(rec.Body.Bytes(), &report)
+		return rec.Code, report
+	}
+
+	code, report := serve("top=1")
+	assert.Equal(t, http.StatusOK, code)
+	if assert.Len(t, report, 1) {
+		assert.EqualValues(t, busy.id, report[0].ChannelId)
+		assert.Equal(t, 1.0, report[0].UpdatesPerSec)
+	}
+
+	code, report = serve("channelId=" + strconv.FormatUint(uint64(idle.id), 10))
+	assert.Equal(t, http.StatusOK, code)
+	if assert.Len(t, report, 1) {
+		assert.EqualValues(t, idle.id, report[0].ChannelId)
+	}
+
+	code, _ = serve("channelId=abc")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
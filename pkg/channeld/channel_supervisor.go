@@ -0,0 +1,110 @@
+package channeld
+
+import (
+	"runtime/debug"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// Runs the tick loop of the channel in its goroutine. If the loop panics, the channel recovers and the loop restarts,
+// so a bad message or merge won't take down the whole process. See recoverFromPanic().
+func (ch *Channel) Tick() {
+	if ch.settings().DedicatedThread {
+		defer ch.lockThread()()
+	}
+	for ch.runTickLoop() {
+		// Don't spin if the panic happens in every tick
+		time.Sleep(ch.tickInterval)
+	}
+}
+
+// Returns true if the tick loop panicked and should be restarted.
+func (ch *Channel) runTickLoop() (restart bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			restart = ch.recoverFromPanic(r, debug.Stack())
+		}
+	}()
+	ch.tickLoop()
+	return false
+}
+
+// Restores the channel data from the last snapshot, and notifies the owner. If the channel has panicked more than
+// ChannelSettingsType.MaxPanicRestarts times, the channel is removed.
+func (ch *Channel) recoverFromPanic(r interface{}, stack []byte) bool {
+	ch.panicCount++
+	ch.countStats(&ch.statsCounters.errors)
+	channelPanics.WithLabelValues(ch.channelType.String()).Inc()
+	ch.Logger().Error("channel recovered from panic",
+		zap.Any("panic", r),
+		zap.ByteString("stack", stack),
+		zap.Int("panicCount", ch.panicCount),
+	)
+
+	if ch.IsRemoving() {
+		return false
+	}
+
+	recoveredMsg := &channeldpb.ChannelRecoveredMessage{PanicCount: uint32(ch.panicCount)}
+	if ch.data != nil {
+		recoveredMsg.DataRestored = ch.restoreSnapshot()
+		recoveredMsg.Version = ch.data.msgIndex
+	}
+
+	maxRestarts := ch.settings().MaxPanicRestarts
+	if maxRestarts > 0 && ch.panicCount > maxRestarts && ch.channelType != channeldpb.ChannelType_GLOBAL {
+		recoveredMsg.Removing = true
+		// Same as removing the channel after the owner is removed
+		globalChannel.PutMessage(&channeldpb.RemoveChannelMessage{
+			ChannelId: uint32(ch.id),
+		}, handleRemoveChannel, nil, &channeldpb.MessagePack{
+			Broadcast: 0,
+			StubId:    0,
+			ChannelId: uint32(GlobalChannelId),
+		})
+		ch.Logger().Warn("removing channel after too many panics", zap.Int("maxPanicRestarts", maxRestarts))
+	}
+
+	if ch.HasOwner() {
+		ch.SendToOwner(uint32(channeldpb.MessageType_CHANNEL_RECOVERED), recoveredMsg)
+	}
+	return true
+}
+
+// Snapshots the channel data if it has changed since the last snapshot and the interval has passed.
+// Should be called at the end of a tick, when the channel data is known to be good.
+func (d *ChannelData) takeSnapshot(t ChannelTime) {
+	if d.snapshotIntervalMs == 0 || d.msg == nil || d.msgIndex == d.snapshotIndex {
+		return
+	}
+	if d.snapshot != nil && t < d.snapshotTime.AddMs(d.snapshotIntervalMs) {
+		return
+	}
+	d.snapshot = proto.Clone(d.msg)
+	d.snapshotIndex = d.msgIndex
+	d.snapshotTime = t
+}
+
+// Replaces the channel data with the last snapshot. The buffered updates are dropped, and all the subscribers
+// will receive the whole channel data in the next fan-out. Returns false if there's no snapshot.
+func (ch *Channel) restoreSnapshot() bool {
+	d := ch.data
+	if d.snapshot == nil {
+		return false
+	}
+	d.msg = proto.Clone(d.snapshot)
+	// The restored data is a new version to the subscribers
+	d.msgIndex++
+	d.snapshotIndex = d.msgIndex
+	d.updateMsgBuffer.Init()
+	d.accumulatedUpdateMsg = nil
+	d.tombstones = nil
+	d.pendingUpdates = nil
+	for e := ch.fanOutQueue.Front(); e != nil; e = e.Next() {
+		e.Value.(*fanOutConnection).hadFirstFanOut = false
+	}
+	return true
+}
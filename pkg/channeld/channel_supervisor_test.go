@@ -0,0 +1,75 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestChannelPanicRecovery(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		TickIntervalMs:          10,
+		DefaultFanOutIntervalMs: 20,
+		DataSnapshotIntervalMs:  1,
+		MaxPanicRestarts:        1,
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	owner := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	testChannel, _ := CreateChannel(channeldpb.ChannelType_TEST, owner)
+	testChannel.InitData(&testpb.TestChannelDataMessage{Text: "a", Num: 1}, nil)
+	client.SubscribeToChannel(testChannel, nil)
+	panicCount := testutil.ToFloat64(channelPanics.WithLabelValues(channeldpb.ChannelType_TEST.String()))
+
+	// The good update is snapshotted at the end of the tick
+	testChannel.PutMessageContext(MessageContext{Msg: &testpb.TestChannelDataMessage{}, Connection: owner}, func(ctx MessageContext) {
+		testChannel.Data().OnUpdate(&testpb.TestChannelDataMessage{Text: "b"}, testChannel.GetTime(), owner.Id(), nil)
+	})
+	time.Sleep(50 * time.Millisecond)
+
+	// The bad update panics in the middle of the handling
+	testChannel.PutMessageContext(MessageContext{Msg: &testpb.TestChannelDataMessage{}, Connection: owner}, func(ctx MessageContext) {
+		testChannel.Data().OnUpdate(&testpb.TestChannelDataMessage{Text: "c"}, testChannel.GetTime(), owner.Id(), nil)
+		panic("bad update")
+	})
+	time.Sleep(50 * time.Millisecond)
+
+	assert.Equal(t, panicCount+1, testutil.ToFloat64(channelPanics.WithLabelValues(channeldpb.ChannelType_TEST.String())))
+	assert.False(t, testChannel.IsRemoving())
+	recoveredMsg, ok := owner.latestMsg().(*channeldpb.ChannelRecoveredMessage)
+	if assert.True(t, ok) {
+		assert.EqualValues(t, 1, recoveredMsg.PanicCount)
+		assert.True(t, recoveredMsg.DataRestored)
+		assert.False(t, recoveredMsg.Removing)
+	}
+	assert.True(t, proto.Equal(&testpb.TestChannelDataMessage{Text: "b", Num: 1}, testChannel.GetDataMessage()))
+
+	// The channel keeps ticking after the recovery
+	handled := false
+	testChannel.PutMessageContext(MessageContext{Msg: &testpb.TestChannelDataMessage{}, Connection: owner}, func(ctx MessageContext) {
+		handled = true
+	})
+	time.Sleep(50 * time.Millisecond)
+	assert.True(t, handled)
+
+	// Exceeds MaxPanicRestarts
+	testChannel.PutMessageContext(MessageContext{Msg: &testpb.TestChannelDataMessage{}, Connection: owner}, func(ctx MessageContext) {
+		panic("bad update again")
+	})
+	time.Sleep(50 * time.Millisecond)
+	recoveredMsg, ok = owner.latestMsg().(*channeldpb.ChannelRecoveredMessage)
+	if assert.True(t, ok) {
+		assert.EqualValues(t, 2, recoveredMsg.PanicCount)
+		assert.True(t, recoveredMsg.Removing)
+	}
+}
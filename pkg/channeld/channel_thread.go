@@ -0,0 +1,62 @@
+package channeld
+
+import (
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Locks the channel's goroutine to its own OS thread, so the heavy channel doesn't share the thread with the other channels,
+// and the thread can be pinned to the CPUs by its id, e.g. with taskset. See ChannelSettingsType.DedicatedThread.
+// Returns the function that unlocks the thread. Should be called in the channel's goroutine.
+func (ch *Channel) lockThread() func() {
+	runtime.LockOSThread()
+	ch.dedicatedThread = true
+	ch.lastThreadCpuTime = threadCpuTime()
+	ch.Logger().Info("locked channel to dedicated OS thread", zap.Int("threadId", threadId()))
+	return func() {
+		ch.dedicatedThread = false
+		runtime.UnlockOSThread()
+	}
+}
+
+// How long the channel can spend handling the messages in a tick, before deferring the rest to the next tick.
+// See ChannelSettingsType.SchedulingWeight.
+func (ch *Channel) messageBudget() time.Duration {
+	weight := ch.settings().SchedulingWeight
+	if weight <= 0 {
+		return ch.tickInterval
+	}
+	return time.Duration(float64(ch.tickInterval) * weight)
+}
+
+// The shortest sleep between the ticks, so the channel with 0 tick interval or the overrunning ticks doesn't busy-spin and
+// starve the other goroutines.
+const minTickSleep = time.Millisecond
+
+// How long to sleep until the next tick, after the tick that took tickDuration.
+func (ch *Channel) tickSleep(tickDuration time.Duration) time.Duration {
+	if sleep := ch.tickInterval - tickDuration; sleep > minTickSleep {
+		return sleep
+	}
+	return minTickSleep
+}
+
+// Accumulates the CPU time of the tick, for ChannelStats.CpuPercent and the metrics. The CPU time of the dedicated thread
+// is used if supported by the OS. Otherwise, the time the tick spent is used. Should be called in the channel's goroutine.
+func (ch *Channel) accountCpuTime(tickDuration time.Duration) {
+	cpuTime := tickDuration
+	if ch.dedicatedThread {
+		if now := threadCpuTime(); now > 0 {
+			cpuTime = now - ch.lastThreadCpuTime
+			ch.lastThreadCpuTime = now
+		}
+	}
+	if cpuTime <= 0 {
+		return
+	}
+	atomic.AddUint64(&ch.statsCounters.cpuNanos, uint64(cpuTime))
+	ch.cpuTimeCounter.Add(cpuTime.Seconds())
+}
@@ -0,0 +1,26 @@
+//go:build linux
+
+package channeld
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// CLOCK_THREAD_CPUTIME_ID in <time.h>
+const clockThreadCpuTimeId = 3
+
+// The id of the current OS thread, as shown by `ps -T` or top -H.
+func threadId() int {
+	return syscall.Gettid()
+}
+
+// The CPU time consumed by the current OS thread, or 0 if it fails.
+func threadCpuTime() time.Duration {
+	var ts syscall.Timespec
+	if _, _, errno := syscall.Syscall(syscall.SYS_CLOCK_GETTIME, clockThreadCpuTimeId, uintptr(unsafe.Pointer(&ts)), 0); errno != 0 {
+		return 0
+	}
+	return time.Duration(ts.Nano())
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package channeld
+
+import "time"
+
+// Not supported on this OS.
+func threadId() int {
+	return 0
+}
+
+// Not supported on this OS. The time the tick spent is used instead.
+func threadCpuTime() time.Duration {
+	return 0
+}
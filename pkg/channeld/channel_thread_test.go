@@ -0,0 +1,74 @@
+package channeld
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageBudget(t *testing.T) {
+	InitLogs()
+	InitChannels()
+
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, nil)
+	// Stop the channel.Tick() goroutine
+	ch.removing = 1
+	ch.tickInterval = 100 * time.Millisecond
+	assert.Equal(t, 100*time.Millisecond, ch.messageBudget())
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{SchedulingWeight: 0.25}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+	assert.Equal(t, 25*time.Millisecond, ch.messageBudget())
+}
+
+func TestTickSleep(t *testing.T) {
+	ch := &Channel{tickInterval: 50 * time.Millisecond}
+	assert.Equal(t, 40*time.Millisecond, ch.tickSleep(10*time.Millisecond))
+	// Doesn't busy-spin when the tick overruns or the tick interval is 0
+	assert.Equal(t, minTickSleep, ch.tickSleep(60*time.Millisecond))
+	ch.tickInterval = 0
+	assert.Equal(t, minTickSleep, ch.tickSleep(0))
+}
+
+func TestDedicatedThreadCpuTime(t *testing.T) {
+	InitLogs()
+	InitChannels()
+
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, nil)
+	// Stop the channel.Tick() goroutine
+	ch.removing = 1
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		unlock := ch.lockThread()
+		defer unlock()
+		assert.True(t, ch.dedicatedThread)
+
+		// Burn some CPU in the thread
+		deadline := time.Now().Add(20 * time.Millisecond)
+		for time.Now().Before(deadline) {
+		}
+		ch.accountCpuTime(time.Nanosecond)
+	}()
+	<-done
+	assert.False(t, ch.dedicatedThread)
+
+	if runtime.GOOS == "linux" {
+		// The CPU time of the thread is used instead of the tick duration
+		assert.Greater(t, ch.statsCounters.cpuNanos, uint64(10*time.Millisecond))
+	} else {
+		assert.EqualValues(t, 1, ch.statsCounters.cpuNanos)
+	}
+
+	// The CPU time in the window
+	now := time.Now()
+	ch.tickStats(now)
+	ch.accountCpuTime(500 * time.Millisecond)
+	ch.tickStats(now.Add(time.Second))
+	assert.InDelta(t, 50, ch.Stats().CpuPercent, 0.01)
+	assert.InDelta(t, 50, ch.Stats().toMessage().CpuPercent, 0.01)
+}
@@ -0,0 +1,70 @@
+package channeld
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+)
+
+const DefaultTickLagTicks = 5
+
+// Measures the actual interval since the last tick. Reports the lag when it has exceeded ChannelSettingsType.TickLagThresholdMs
+// for TickLagTicks ticks in a row, and the recovery when the tick catches up. Should be called in the channel's goroutine.
+func (ch *Channel) checkTickLag(tickStart time.Time) {
+	settings := ch.settings()
+	if settings.TickLagThresholdMs == 0 {
+		return
+	}
+	lastTickTime := atomic.LoadInt64(&ch.lastTickTime)
+	if lastTickTime == 0 {
+		return
+	}
+
+	interval := tickStart.Sub(time.Unix(0, lastTickTime))
+	if interval-ch.tickInterval <= time.Duration(settings.TickLagThresholdMs)*time.Millisecond {
+		ch.laggedTicks = 0
+		if ch.tickLagging {
+			ch.tickLagging = false
+			ch.Logger().Info("channel tick recovered from the lag", zap.Duration("interval", interval))
+			ch.notifyOwnerOfTickLag(settings, interval)
+		}
+		return
+	}
+
+	ch.laggedTicks++
+	maxTicks := settings.TickLagTicks
+	if maxTicks <= 0 {
+		maxTicks = DefaultTickLagTicks
+	}
+	if ch.tickLagging || ch.laggedTicks < maxTicks {
+		return
+	}
+
+	ch.tickLagging = true
+	channelTickLagged.WithLabelValues(ch.channelType.String()).Inc()
+	ch.Logger().Warn("channel tick lagged behind the tick interval",
+		zap.Duration("tickInterval", ch.tickInterval),
+		zap.Duration("interval", interval),
+		zap.Int("laggedTicks", ch.laggedTicks),
+	)
+	ch.notifyOwnerOfTickLag(settings, interval)
+}
+
+func (ch *Channel) notifyOwnerOfTickLag(settings ChannelSettingsType, interval time.Duration) {
+	if !settings.NotifyOwnerOfTickLag || !ch.HasOwner() {
+		return
+	}
+	ch.ownerConnection.Send(MessageContext{
+		MsgType: channeldpb.MessageType_CHANNEL_TICK_LAG,
+		Msg: &channeldpb.ChannelTickLagMessage{
+			TickIntervalMs:   uint32(ch.tickInterval.Milliseconds()),
+			ActualIntervalMs: uint32(interval.Milliseconds()),
+			Ticks:            uint32(ch.laggedTicks),
+			Recovered:        !ch.tickLagging,
+		},
+		Channel:   ch,
+		ChannelId: uint32(ch.id),
+	})
+}
@@ -0,0 +1,74 @@
+package channeld
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckTickLag(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		TickLagThresholdMs:   50,
+		TickLagTicks:         3,
+		NotifyOwnerOfTickLag: true,
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	// Stop the channel.Tick() goroutine
+	ch.removing = 1
+	ch.tickInterval = 10 * time.Millisecond
+
+	now := time.Now()
+	tick := func(interval time.Duration) {
+		atomic.StoreInt64(&ch.lastTickTime, now.UnixNano())
+		now = now.Add(interval)
+		ch.checkTickLag(now)
+	}
+	laggedCount := testutil.ToFloat64(channelTickLagged.WithLabelValues(channeldpb.ChannelType_TEST.String()))
+
+	// Within the threshold
+	tick(60 * time.Millisecond)
+	tick(60 * time.Millisecond)
+	tick(60 * time.Millisecond)
+	assert.False(t, ch.tickLagging)
+
+	// Not in a row
+	tick(100 * time.Millisecond)
+	tick(100 * time.Millisecond)
+	tick(10 * time.Millisecond)
+	tick(100 * time.Millisecond)
+	assert.False(t, ch.tickLagging)
+	assert.Empty(t, server.testQueue())
+
+	tick(100 * time.Millisecond)
+	tick(100 * time.Millisecond)
+	assert.True(t, ch.tickLagging)
+	assert.Equal(t, laggedCount+1, testutil.ToFloat64(channelTickLagged.WithLabelValues(channeldpb.ChannelType_TEST.String())))
+	if msg, ok := server.latestMsg().(*channeldpb.ChannelTickLagMessage); assert.True(t, ok) {
+		assert.EqualValues(t, 10, msg.TickIntervalMs)
+		assert.EqualValues(t, 100, msg.ActualIntervalMs)
+		assert.EqualValues(t, 3, msg.Ticks)
+		assert.False(t, msg.Recovered)
+	}
+
+	// Reported once
+	tick(100 * time.Millisecond)
+	assert.Len(t, server.testQueue(), 1)
+
+	tick(10 * time.Millisecond)
+	assert.False(t, ch.tickLagging)
+	if msg, ok := server.latestMsg().(*channeldpb.ChannelTickLagMessage); assert.True(t, ok) {
+		assert.True(t, msg.Recovered)
+	}
+	assert.Len(t, server.testQueue(), 2)
+}
@@ -0,0 +1,68 @@
+package channeld
+
+import (
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+)
+
+// The recurring timer registered by the channel owner. See channeldpb.ChannelTimerMessage.
+type channelTimer struct {
+	interval     ChannelTime
+	nextFireTime ChannelTime
+	count        uint64
+}
+
+func handleChannelTimer(ctx MessageContext) {
+	msg, ok := ctx.Msg.(*channeldpb.ChannelTimerMessage)
+	if !ok {
+		ctx.Connection.Logger().Error("message is not a ChannelTimerMessage, will not be handled.")
+		return
+	}
+
+	if ctx.Channel.ownerConnection != ctx.Connection {
+		ctx.Connection.Logger().Error("only the channel owner can register the timer",
+			zap.Uint32("channelId", uint32(ctx.Channel.id)),
+			zap.Uint32("timerId", msg.TimerId),
+		)
+		return
+	}
+
+	if msg.IntervalMs == 0 {
+		delete(ctx.Channel.timers, msg.TimerId)
+		return
+	}
+
+	if ctx.Channel.timers == nil {
+		ctx.Channel.timers = make(map[uint32]*channelTimer)
+	}
+	interval := ChannelTime(time.Duration(msg.IntervalMs) * time.Millisecond)
+	ctx.Channel.timers[msg.TimerId] = &channelTimer{
+		interval:     interval,
+		nextFireTime: ctx.Channel.GetTime() + interval,
+	}
+}
+
+// Sends the TimerFiredMessage to the owner for each timer that is due. If the tick is late for more than one interval,
+// the timer only fires once and the missed fires are skipped, so the owner doesn't receive a burst of the messages.
+func (ch *Channel) tickTimers(t ChannelTime) {
+	for timerId, timer := range ch.timers {
+		if t < timer.nextFireTime {
+			continue
+		}
+		for timer.nextFireTime <= t {
+			timer.nextFireTime += timer.interval
+		}
+		timer.count++
+
+		if !ch.HasOwner() {
+			continue
+		}
+		ch.SendToOwner(uint32(channeldpb.MessageType_CHANNEL_TIMER), &channeldpb.TimerFiredMessage{
+			TimerId:       timerId,
+			Count:         timer.count,
+			ChannelTimeMs: int64(time.Duration(t) / time.Millisecond),
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelTimer(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	owner := addTestConnection(channeldpb.ConnectionType_SERVER)
+	other := addTestConnection(channeldpb.ConnectionType_SERVER)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, owner)
+
+	register := func(conn *Connection, timerId uint32, intervalMs uint32) {
+		handleChannelTimer(MessageContext{
+			MsgType:    channeldpb.MessageType_CHANNEL_TIMER,
+			Msg:        &channeldpb.ChannelTimerMessage{TimerId: timerId, IntervalMs: intervalMs},
+			Connection: conn,
+			Channel:    ch,
+		})
+	}
+	firedNum := 0
+	fired := func() []*channeldpb.TimerFiredMessage {
+		var msgs []*channeldpb.TimerFiredMessage
+		queue := owner.testQueue()
+		for _, msg := range queue[firedNum:] {
+			if timerMsg, ok := msg.(*channeldpb.TimerFiredMessage); ok {
+				msgs = append(msgs, timerMsg)
+			}
+		}
+		firedNum = len(queue)
+		return msgs
+	}
+
+	// The intervals are long enough that the channel's own ticks don't fire the timers during the test
+	register(owner, 1, 10000)
+	// Only the owner can register
+	register(other, 2, 10000)
+	assert.Len(t, ch.timers, 1)
+	now := ch.GetTime()
+
+	ch.tickTimers(now.AddMs(5000))
+	assert.Empty(t, fired())
+
+	ch.tickTimers(now.AddMs(10000))
+	msgs := fired()
+	if assert.Len(t, msgs, 1) {
+		assert.EqualValues(t, 1, msgs[0].TimerId)
+		assert.EqualValues(t, 1, msgs[0].Count)
+		assert.Equal(t, int64(time.Duration(now.AddMs(10000))/time.Millisecond), msgs[0].ChannelTimeMs)
+	}
+
+	// A late tick only fires once
+	ch.tickTimers(now.AddMs(45000))
+	msgs = fired()
+	if assert.Len(t, msgs, 1) {
+		assert.EqualValues(t, 2, msgs[0].Count)
+	}
+	ch.tickTimers(now.AddMs(49000))
+	assert.Empty(t, fired())
+	ch.tickTimers(now.AddMs(50000))
+	assert.Len(t, fired(), 1)
+
+	// Cancel
+	register(owner, 1, 0)
+	assert.Empty(t, ch.timers)
+	ch.tickTimers(now.AddMs(100000))
+	assert.Empty(t, fired())
+}
@@ -0,0 +1,67 @@
+//go:build chaos
+
+package channeld
+
+import (
+	"flag"
+	"math/rand"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Soak/chaos test mode. Only compiled with `-tags chaos`, so the production build never pays for the checks.
+// The faults are injected at the network layer of the connections, in order to validate that the fan-out accumulation,
+// handover, and reconnection logic stay correct under adverse conditions.
+type ChaosSettingsType struct {
+	// The chance (0-1) for a connection to be dropped, checked every time it receives bytes.
+	DropConnectionChance float64
+	// The max random delay before a packet is written to the connection.
+	MaxPacketDelayMs int
+	// The ratio (0-1) of the connections that are marked as slow subscribers when they are added.
+	SlowSubscriberRatio float64
+	// The extra delay of each flush of a slow subscriber.
+	SlowSubscriberDelayMs int
+}
+
+var ChaosSettings = ChaosSettingsType{}
+
+const chaosEnabled = true
+
+func init() {
+	flag.Float64Var(&ChaosSettings.DropConnectionChance, "chaosdrop", 0, "[chaos] the chance (0-1) to drop a connection when it receives bytes")
+	flag.IntVar(&ChaosSettings.MaxPacketDelayMs, "chaosdelay", 0, "[chaos] the max random delay in milliseconds before sending a packet")
+	flag.Float64Var(&ChaosSettings.SlowSubscriberRatio, "chaosslow", 0, "[chaos] the ratio (0-1) of the connections to be slow subscribers")
+	flag.IntVar(&ChaosSettings.SlowSubscriberDelayMs, "chaosslowdelay", 500, "[chaos] the delay in milliseconds of each flush of a slow subscriber")
+}
+
+func chaosOnConnectionAdded(c *Connection) {
+	if ChaosSettings.SlowSubscriberRatio > 0 && rand.Float64() < ChaosSettings.SlowSubscriberRatio {
+		c.chaosSlow = true
+		c.Logger().Info("[chaos] marked as slow subscriber")
+	}
+}
+
+// Returns true if the connection should be dropped.
+func chaosShouldDrop(c *Connection) bool {
+	if ChaosSettings.DropConnectionChance > 0 && rand.Float64() < ChaosSettings.DropConnectionChance {
+		c.Logger().Info("[chaos] dropping connection")
+		return true
+	}
+	return false
+}
+
+// Called in the flush goroutine before the packet is written.
+func chaosBeforeFlush(c *Connection) {
+	delayMs := 0
+	if ChaosSettings.MaxPacketDelayMs > 0 {
+		delayMs = rand.Intn(ChaosSettings.MaxPacketDelayMs + 1)
+	}
+	if c.chaosSlow {
+		delayMs += ChaosSettings.SlowSubscriberDelayMs
+	}
+	if delayMs > 0 {
+		c.Logger().VeryVerbose("[chaos] delaying packet", zap.Int("delayMs", delayMs))
+		time.Sleep(time.Duration(delayMs) * time.Millisecond)
+	}
+}
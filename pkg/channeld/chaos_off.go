@@ -0,0 +1,15 @@
+//go:build !chaos
+
+package channeld
+
+// No-op implementations of the chaos hooks. See chaos.go for the soak/chaos test mode.
+
+const chaosEnabled = false
+
+func chaosOnConnectionAdded(c *Connection) {}
+
+func chaosShouldDrop(c *Connection) bool {
+	return false
+}
+
+func chaosBeforeFlush(c *Connection) {}
@@ -0,0 +1,31 @@
+//go:build chaos
+
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+// Run with: go test -tags chaos -run TestChaos ./pkg/channeld
+func TestChaosFaults(t *testing.T) {
+	InitLogs()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	ChaosSettings = ChaosSettingsType{SlowSubscriberRatio: 1, SlowSubscriberDelayMs: 50}
+	c := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	assert.True(t, c.chaosSlow)
+	assert.False(t, chaosShouldDrop(c))
+
+	start := time.Now()
+	chaosBeforeFlush(c)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	ChaosSettings = ChaosSettingsType{DropConnectionChance: 1}
+	c = addTestConnection(channeldpb.ConnectionType_CLIENT)
+	assert.False(t, c.chaosSlow)
+	assert.True(t, chaosShouldDrop(c))
+}
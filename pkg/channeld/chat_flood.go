@@ -0,0 +1,228 @@
+package channeld
+
+import (
+	"hash/fnv"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/puzpuzpuz/xsync/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultChatRepeatWindowMs = 10000
+	DefaultChatPenaltyResetMs = 600000
+	DefaultChatMentionPattern = `@\w+`
+)
+
+var DefaultChatMuteDurationsMs = []uint32{10000, 60000, 600000}
+
+// The max number of the recent payloads that are remembered per user for the repeat detection
+const maxChatPayloadRecords = 64
+
+const chatFloodSweepInterval = time.Minute
+
+// The flood state of a user. It's shared by the channels of the tenant, so the user can't escape the mute
+// by switching the channel or reconnecting.
+type chatFloodState struct {
+	lock              sync.Mutex
+	mutedUntil        time.Time
+	level             uint32
+	lastViolationTime time.Time
+	lastActiveTime    time.Time
+	// The recent payloads of the user, in the order of time
+	recent []chatPayloadRecord
+}
+
+type chatPayloadRecord struct {
+	hash uint64
+	time time.Time
+}
+
+// By tenantChannelKey(tenant, userId)
+var chatFloodStates = xsync.NewMapOf[*chatFloodState]()
+
+// The compiled ChatFloodSettingsType.MentionPattern. Nil if the pattern is invalid.
+var chatMentionPatterns = xsync.NewMapOf[*regexp.Regexp]()
+
+// The unix nano time of the last sweep of the stale states
+var chatFloodLastSweep int64
+
+func getChatMentionPattern(pattern string) *regexp.Regexp {
+	if pattern == "" {
+		pattern = DefaultChatMentionPattern
+	}
+	re, _ := chatMentionPatterns.LoadOrCompute(pattern, func() *regexp.Regexp {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			rootLogger.Error("invalid chat mention pattern", zap.String("pattern", pattern), zap.Error(err))
+			return nil
+		}
+		return re
+	})
+	return re
+}
+
+// Returns the violation of the payload, or false if there's none. Records the payload if there's no violation.
+// Should be called with the lock held.
+func (s *chatFloodState) check(settings *ChatFloodSettingsType, payload []byte, now time.Time) (channeldpb.ChatPenaltyMessage_Reason, bool) {
+	if settings.MaxMentions > 0 {
+		if re := getChatMentionPattern(settings.MentionPattern); re != nil && len(re.FindAllIndex(payload, settings.MaxMentions+1)) > settings.MaxMentions {
+			return channeldpb.ChatPenaltyMessage_MENTION_SPAM, true
+		}
+	}
+
+	if settings.MaxRepeats <= 0 {
+		return 0, false
+	}
+	window := time.Duration(settings.RepeatWindowMs) * time.Millisecond
+	if window == 0 {
+		window = DefaultChatRepeatWindowMs * time.Millisecond
+	}
+	expired := 0
+	for expired < len(s.recent) && now.Sub(s.recent[expired].time) > window {
+		expired++
+	}
+	s.recent = s.recent[expired:]
+
+	h := fnv.New64a()
+	h.Write(payload)
+	hash := h.Sum64()
+	repeats := 0
+	for _, record := range s.recent {
+		if record.hash == hash {
+			repeats++
+		}
+	}
+	if repeats >= settings.MaxRepeats {
+		return channeldpb.ChatPenaltyMessage_REPEATED, true
+	}
+
+	if len(s.recent) >= maxChatPayloadRecords {
+		s.recent = s.recent[1:]
+	}
+	s.recent = append(s.recent, chatPayloadRecord{hash: hash, time: now})
+	return 0, false
+}
+
+// Raises the penalty level and mutes the user. Returns the mute duration. Should be called with the lock held.
+func (s *chatFloodState) penalize(settings *ChatFloodSettingsType, now time.Time) time.Duration {
+	resetDuration := time.Duration(settings.PenaltyResetMs) * time.Millisecond
+	if resetDuration == 0 {
+		resetDuration = DefaultChatPenaltyResetMs * time.Millisecond
+	}
+	if now.Sub(s.lastViolationTime) > resetDuration {
+		s.level = 0
+	}
+	s.level++
+	s.lastViolationTime = now
+
+	durations := settings.MuteDurationsMs
+	if len(durations) == 0 {
+		durations = DefaultChatMuteDurationsMs
+	}
+	i := int(s.level) - 1
+	if i >= len(durations) {
+		i = len(durations) - 1
+	}
+	mute := time.Duration(durations[i]) * time.Millisecond
+	s.mutedUntil = now.Add(mute)
+	s.recent = nil
+	return mute
+}
+
+// Removes the states that are neither muted nor penalized recently, at most once per chatFloodSweepInterval.
+func sweepChatFloodStates(now time.Time) {
+	last := atomic.LoadInt64(&chatFloodLastSweep)
+	if now.UnixNano()-last < int64(chatFloodSweepInterval) || !atomic.CompareAndSwapInt64(&chatFloodLastSweep, last, now.UnixNano()) {
+		return
+	}
+	chatFloodStates.Range(func(key string, s *chatFloodState) bool {
+		s.lock.Lock()
+		stale := now.After(s.mutedUntil) &&
+			now.Sub(s.lastViolationTime) > DefaultChatPenaltyResetMs*time.Millisecond &&
+			now.Sub(s.lastActiveTime) > chatFloodSweepInterval
+		s.lock.Unlock()
+		if stale {
+			chatFloodStates.Delete(key)
+		}
+		return true
+	})
+}
+
+// Returns false if the broadcast is dropped, as the sender is muted, or gets muted for the broadcast.
+// Should be called in the channel's goroutine.
+func (ch *Channel) checkChatFlood(ctx MessageContext, msg *channeldpb.ServerForwardMessage, senderUserId string) bool {
+	settings := ch.settings().ChatFlood
+	if senderUserId == "" || !settings.Enabled() {
+		return true
+	}
+
+	now := time.Now()
+	sweepChatFloodStates(now)
+	state, _ := chatFloodStates.LoadOrCompute(tenantChannelKey(ch.tenant, senderUserId), func() *chatFloodState {
+		return &chatFloodState{}
+	})
+
+	state.lock.Lock()
+	state.lastActiveTime = now
+	if now.Before(state.mutedUntil) {
+		state.lock.Unlock()
+		chatFloodDropped.WithLabelValues(ch.channelType.String(), "MUTED").Inc()
+		return false
+	}
+	reason, violated := state.check(&settings, msg.Payload, now)
+	if !violated {
+		state.lock.Unlock()
+		return true
+	}
+	mute := state.penalize(&settings, now)
+	level := state.level
+	state.lock.Unlock()
+
+	chatFloodDropped.WithLabelValues(ch.channelType.String(), reason.String()).Inc()
+
+	// The offender is the client that the server broadcasts on behalf of, if any.
+	var offender ConnectionInChannel = ctx.Connection
+	if ctx.Connection.GetConnectionType() == channeldpb.ConnectionType_SERVER && msg.ClientConnId != 0 {
+		if client := GetConnection(ConnectionId(msg.ClientConnId)); client != nil {
+			offender = client
+		} else {
+			offender = nil
+		}
+	}
+
+	penaltyMsg := &channeldpb.ChatPenaltyMessage{
+		UserId: senderUserId,
+		Reason: reason,
+		Level:  level,
+		MuteMs: uint32(mute.Milliseconds()),
+	}
+	if offender != nil {
+		penaltyMsg.ConnId = uint32(offender.Id())
+	}
+
+	ch.Logger().Info("muted the user for flooding",
+		zap.String("userId", senderUserId),
+		zap.String("reason", reason.String()),
+		zap.Uint32("level", level),
+		zap.Duration("mute", mute),
+	)
+
+	penaltyCtx := MessageContext{
+		MsgType:   channeldpb.MessageType_CHAT_PENALTY,
+		Msg:       penaltyMsg,
+		Channel:   ch,
+		ChannelId: uint32(ch.id),
+	}
+	if ch.HasOwner() && ch.ownerConnection != offender {
+		ch.ownerConnection.Send(penaltyCtx)
+	}
+	if offender != nil {
+		offender.Send(penaltyCtx)
+	}
+	return false
+}
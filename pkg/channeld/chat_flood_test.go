@@ -0,0 +1,108 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChatFloodState(t *testing.T) {
+	settings := &ChatFloodSettingsType{MaxRepeats: 2, RepeatWindowMs: 1000, MaxMentions: 2, MuteDurationsMs: []uint32{100, 1000}, PenaltyResetMs: 5000}
+	s := &chatFloodState{}
+	now := time.Now()
+
+	_, violated := s.check(settings, []byte("hi"), now)
+	assert.False(t, violated)
+	_, violated = s.check(settings, []byte("hi"), now)
+	assert.False(t, violated)
+	reason, violated := s.check(settings, []byte("hi"), now)
+	assert.True(t, violated)
+	assert.Equal(t, channeldpb.ChatPenaltyMessage_REPEATED, reason)
+	// The repeats out of the window don't count
+	_, violated = s.check(settings, []byte("hi"), now.Add(1500*time.Millisecond))
+	assert.False(t, violated)
+
+	_, violated = s.check(settings, []byte("@a @b"), now)
+	assert.False(t, violated)
+	reason, violated = s.check(settings, []byte("@a @b @c"), now)
+	assert.True(t, violated)
+	assert.Equal(t, channeldpb.ChatPenaltyMessage_MENTION_SPAM, reason)
+
+	// The mute gets longer, and the last duration is used for the higher levels
+	assert.Equal(t, 100*time.Millisecond, s.penalize(settings, now))
+	assert.Equal(t, 1000*time.Millisecond, s.penalize(settings, now.Add(time.Second)))
+	assert.Equal(t, 1000*time.Millisecond, s.penalize(settings, now.Add(2*time.Second)))
+	assert.EqualValues(t, 3, s.level)
+	// The level is reset after PenaltyResetMs without a violation
+	assert.Equal(t, 100*time.Millisecond, s.penalize(settings, now.Add(10*time.Second)))
+	assert.EqualValues(t, 1, s.level)
+}
+
+func TestChatFloodMute(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		ChatFlood: ChatFloodSettingsType{MaxRepeats: 1, MuteDurationsMs: []uint32{60000}},
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	spammer := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	spammer.SetTag(UserIdTag, "spammer")
+	other := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	defer chatFloodStates.Delete("spammer")
+
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	spammer.SubscribeToChannel(ch, nil)
+	other.SubscribeToChannel(ch, nil)
+
+	countForwards := func(c *Connection) int {
+		n := 0
+		for _, msg := range c.testQueue() {
+			if _, ok := msg.(*channeldpb.ServerForwardMessage); ok {
+				n++
+			}
+		}
+		return n
+	}
+	// The server broadcasts the chat message on behalf of the client
+	broadcast := func(ch *Channel, payload string) {
+		HandleServerToClientUserMessage(MessageContext{
+			MsgType:    channeldpb.MessageType_USER_SPACE_START,
+			Msg:        &channeldpb.ServerForwardMessage{ClientConnId: uint32(spammer.Id()), Payload: []byte(payload)},
+			Broadcast:  uint32(channeldpb.BroadcastType_ALL),
+			Connection: server,
+			Channel:    ch,
+		})
+	}
+
+	broadcast(ch, "buy gold")
+	assert.Equal(t, 1, countForwards(other))
+
+	// The repeated message mutes the user, and the owner and the user are notified
+	broadcast(ch, "buy gold")
+	assert.Equal(t, 1, countForwards(other))
+	expected := &channeldpb.ChatPenaltyMessage{
+		ConnId: uint32(spammer.Id()),
+		UserId: "spammer",
+		Reason: channeldpb.ChatPenaltyMessage_REPEATED,
+		Level:  1,
+		MuteMs: 60000,
+	}
+	assert.EqualValues(t, expected, server.latestMsg())
+	assert.EqualValues(t, expected, spammer.latestMsg())
+
+	// The muted user's broadcasts are dropped
+	broadcast(ch, "hello")
+	assert.Equal(t, 1, countForwards(other))
+
+	// The channel types without ChatFlood are not affected
+	otherChannel, _ := CreateChannel(channeldpb.ChannelType_SUBWORLD, server)
+	other.SubscribeToChannel(otherChannel, nil)
+	broadcast(otherChannel, "hello")
+	assert.Equal(t, 2, countForwards(other))
+}
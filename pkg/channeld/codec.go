@@ -0,0 +1,150 @@
+package channeld
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func isChannelDataCodecSupported(codec channeldpb.ChannelDataCodec) bool {
+	_, exists := channeldpb.ChannelDataCodec_name[int32(codec)]
+	return exists
+}
+
+// Converts the channel data from the canonical Protobuf form to the codec.
+// The type of the channel data should be registered in protoregistry.GlobalTypes, which is done by importing the generated Go package.
+func encodeChannelData(data *anypb.Any, codec channeldpb.ChannelDataCodec) (*anypb.Any, error) {
+	if data == nil || codec == channeldpb.ChannelDataCodec_PROTOBUF {
+		return data, nil
+	}
+
+	msg, err := data.UnmarshalNew()
+	if err != nil {
+		return nil, err
+	}
+	value, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	if codec == channeldpb.ChannelDataCodec_MSGPACK {
+		if value, err = jsonToMsgpack(value); err != nil {
+			return nil, err
+		}
+	}
+	return &anypb.Any{TypeUrl: data.TypeUrl, Value: value}, nil
+}
+
+// Converts the channel data from the codec to the canonical Protobuf form.
+func decodeChannelData(data *anypb.Any, codec channeldpb.ChannelDataCodec) (*anypb.Any, error) {
+	if data == nil || codec == channeldpb.ChannelDataCodec_PROTOBUF {
+		return data, nil
+	}
+
+	msgType, err := protoregistry.GlobalTypes.FindMessageByURL(data.TypeUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the channel data type %s: %w", data.TypeUrl, err)
+	}
+	value := data.Value
+	if codec == channeldpb.ChannelDataCodec_MSGPACK {
+		if value, err = msgpackToJson(value); err != nil {
+			return nil, err
+		}
+	}
+	msg := msgType.New().Interface()
+	if err := protojson.Unmarshal(value, msg); err != nil {
+		return nil, err
+	}
+	return anypb.New(msg)
+}
+
+func jsonToMsgpack(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// Keep the integers as they are
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	return msgpack.Marshal(convertJsonNumbers(v))
+}
+
+func convertJsonNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, value := range v {
+			v[key] = convertJsonNumbers(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = convertJsonNumbers(value)
+		}
+	}
+	return v
+}
+
+func msgpackToJson(data []byte) ([]byte, error) {
+	var v interface{}
+	if err := msgpack.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// Returns the copy of the message with the channel data encoded in the connection's codec,
+// or the message itself if the connection uses Protobuf or the message doesn't contain channel data.
+// The original message is not modified, as it can be shared by multiple connections.
+func (c *Connection) encodeChannelDataMessage(msg common.Message) (common.Message, error) {
+	if c.channelDataCodec == channeldpb.ChannelDataCodec_PROTOBUF {
+		return msg, nil
+	}
+
+	switch m := msg.(type) {
+	case *channeldpb.ChannelDataUpdateMessage:
+		data, err := encodeChannelData(m.Data, c.channelDataCodec)
+		if err != nil {
+			return nil, err
+		}
+		encodedMsg := cloneUpdateEnvelope(m)
+		encodedMsg.Data = data
+		return encodedMsg, nil
+	case *channeldpb.QueryChannelDataResultMessage:
+		data, err := encodeChannelData(m.Data, c.channelDataCodec)
+		if err != nil {
+			return nil, err
+		}
+		resultMsg := proto.Clone(m).(*channeldpb.QueryChannelDataResultMessage)
+		resultMsg.Data = data
+		return resultMsg, nil
+	}
+	return msg, nil
+}
+
+// Converts the channel data in the received message to the canonical Protobuf form.
+func (c *Connection) decodeChannelDataMessage(msg common.Message) error {
+	if c.channelDataCodec == channeldpb.ChannelDataCodec_PROTOBUF {
+		return nil
+	}
+
+	if m, ok := msg.(*channeldpb.ChannelDataUpdateMessage); ok {
+		data, err := decodeChannelData(m.Data, c.channelDataCodec)
+		if err != nil {
+			return err
+		}
+		m.Data = data
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package channeld
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestChannelDataCodec(t *testing.T) {
+	data, _ := anypb.New(&testpb.TestChannelDataMessage{Text: "a", Num: 1})
+
+	encoded, err := encodeChannelData(data, channeldpb.ChannelDataCodec_JSON)
+	assert.NoError(t, err)
+	assert.Equal(t, data.TypeUrl, encoded.TypeUrl)
+	var jsonValue map[string]interface{}
+	assert.NoError(t, json.Unmarshal(encoded.Value, &jsonValue))
+	assert.Equal(t, "a", jsonValue["text"])
+	assert.EqualValues(t, 1, jsonValue["num"])
+	decoded, err := decodeChannelData(encoded, channeldpb.ChannelDataCodec_JSON)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(data, decoded))
+
+	encoded, err = encodeChannelData(data, channeldpb.ChannelDataCodec_MSGPACK)
+	assert.NoError(t, err)
+	var msgpackValue map[string]interface{}
+	assert.NoError(t, msgpack.Unmarshal(encoded.Value, &msgpackValue))
+	assert.Equal(t, "a", msgpackValue["text"])
+	assert.EqualValues(t, 1, msgpackValue["num"])
+	decoded, err = decodeChannelData(encoded, channeldpb.ChannelDataCodec_MSGPACK)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(data, decoded))
+
+	// Unregistered type
+	_, err = decodeChannelData(&anypb.Any{TypeUrl: "type.googleapis.com/foo.Bar", Value: []byte("{}")}, channeldpb.ChannelDataCodec_JSON)
+	assert.Error(t, err)
+}
+
+func TestChannelDataCodecNegotiation(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	handleAuth(MessageContext{
+		MsgType:    channeldpb.MessageType_AUTH,
+		Msg:        &channeldpb.AuthMessage{PlayerIdentifierToken: "test", ChannelDataCodec: channeldpb.ChannelDataCodec_MSGPACK},
+		Connection: client,
+		Channel:    globalChannel,
+	})
+	result, ok := client.latestMsg().(*channeldpb.AuthResultMessage)
+	if assert.True(t, ok) {
+		assert.Equal(t, channeldpb.ChannelDataCodec_MSGPACK, result.ChannelDataCodec)
+	}
+	assert.Equal(t, channeldpb.ChannelDataCodec_MSGPACK, client.channelDataCodec)
+
+	// Unsupported codec falls back to Protobuf
+	client2 := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	handleAuth(MessageContext{
+		MsgType:    channeldpb.MessageType_AUTH,
+		Msg:        &channeldpb.AuthMessage{PlayerIdentifierToken: "test2", ChannelDataCodec: 100},
+		Connection: client2,
+		Channel:    globalChannel,
+	})
+	result, ok = client2.latestMsg().(*channeldpb.AuthResultMessage)
+	if assert.True(t, ok) {
+		assert.Equal(t, channeldpb.ChannelDataCodec_PROTOBUF, result.ChannelDataCodec)
+	}
+
+	// The shared update message is not modified when sent
+	client.sender = &queuedMessagePackSender{}
+	data, _ := anypb.New(&testpb.TestChannelDataMessage{Text: "a", Num: 1})
+	updateMsg := &channeldpb.ChannelDataUpdateMessage{Data: data}
+	client.Send(MessageContext{MsgType: channeldpb.MessageType_CHANNEL_DATA_UPDATE, Msg: updateMsg})
+	mp := <-client.sendQueues[MessagePriority_ChannelData]
+	sentMsg := &channeldpb.ChannelDataUpdateMessage{}
+	assert.NoError(t, proto.Unmarshal(mp.MsgBody, sentMsg))
+	var msgpackValue map[string]interface{}
+	assert.NoError(t, msgpack.Unmarshal(sentMsg.Data.Value, &msgpackValue))
+	assert.Equal(t, "a", msgpackValue["text"])
+	assert.Same(t, data, updateMsg.Data)
+
+	// The received update message is decoded to Protobuf
+	msgpackBody, _ := msgpack.Marshal(map[string]interface{}{"text": "b"})
+	received := &channeldpb.ChannelDataUpdateMessage{Data: &anypb.Any{TypeUrl: data.TypeUrl, Value: msgpackBody}}
+	assert.NoError(t, client.decodeChannelDataMessage(received))
+	receivedData := &testpb.TestChannelDataMessage{}
+	assert.NoError(t, received.Data.UnmarshalTo(receivedData))
+	assert.Equal(t, "b", receivedData.Text)
+}
+
+func TestChannelDataCodecKeepsEnvelope(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	client.channelDataCodec = channeldpb.ChannelDataCodec_JSON
+	data, _ := anypb.New(&testpb.TestChannelDataMessage{Text: "a", Num: 1})
+	updateMsg := &channeldpb.ChannelDataUpdateMessage{
+		Data:            data,
+		ContextConnId:   1,
+		Version:         3,
+		Checksum:        proto.Uint32(123),
+		Keyframe:        true,
+		KeyframeVersion: proto.Uint64(3),
+	}
+
+	encoded, err := client.encodeChannelDataMessage(updateMsg)
+	assert.NoError(t, err)
+	// Round trip through the wire
+	body, err := proto.Marshal(encoded)
+	assert.NoError(t, err)
+	received := &channeldpb.ChannelDataUpdateMessage{}
+	assert.NoError(t, proto.Unmarshal(body, received))
+	assert.NoError(t, client.decodeChannelDataMessage(received))
+
+	assert.True(t, proto.Equal(updateMsg, received))
+	assert.EqualValues(t, 3, received.Version)
+	assert.EqualValues(t, 123, received.GetChecksum())
+	assert.True(t, received.Keyframe)
+
+	resultMsg := &channeldpb.QueryChannelDataResultMessage{Data: data, Version: 3}
+	encoded, err = client.encodeChannelDataMessage(resultMsg)
+	assert.NoError(t, err)
+	encodedResult := encoded.(*channeldpb.QueryChannelDataResultMessage)
+	assert.EqualValues(t, 3, encodedResult.Version)
+	assert.NotEqual(t, data.Value, encodedResult.Data.Value)
+	assert.Same(t, data, resultMsg.Data)
+}
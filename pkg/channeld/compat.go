@@ -0,0 +1,181 @@
+package channeld
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+)
+
+// The version of the protocol (the messages in channeldpb) that this build of channeld speaks.
+// Bump it when a change to the messages can't be understood by the older SDKs, and register the CompatShim for the change,
+// so the older SDKs keep working during a rolling upgrade.
+const ProtocolVersion uint32 = 11
+
+// Converts a type of message for the connections that use an older protocol version.
+type CompatShim struct {
+	MsgType channeldpb.MessageType
+	// The shim applies to the connections whose protocol version is lower than Version.
+	Version uint32
+	// Converts the message received from an older connection to the current version. Optional.
+	Upgrade func(msg common.Message) common.Message
+	// Converts the message to send to an older connection from the current version. Optional.
+	// Returning nil drops the message, for the message types that the older SDKs don't know.
+	Downgrade func(msg common.Message) common.Message
+}
+
+// Sorted by the version in ascending order
+var compatShims = make(map[channeldpb.MessageType][]*CompatShim)
+
+// Should be called before the connections are accepted, as the shims are not protected from concurrent access.
+// The shims should not modify the message in place, as the message can be shared by the connections.
+func RegisterCompatShim(shim *CompatShim) {
+	shims := append(compatShims[shim.MsgType], shim)
+	sort.SliceStable(shims, func(i, j int) bool {
+		return shims[i].Version < shims[j].Version
+	})
+	compatShims[shim.MsgType] = shims
+}
+
+// Returns the protocol version to use for the connection that speaks the version.
+func negotiateProtocolVersion(version uint32) uint32 {
+	if version > ProtocolVersion {
+		return ProtocolVersion
+	}
+	return version
+}
+
+// The negotiated protocol version of the connection. 0 before authenticated, or for the SDKs built before the version exchange.
+func (c *Connection) ProtocolVersion() uint32 {
+	return atomic.LoadUint32(&c.protocolVersion)
+}
+
+func (c *Connection) setProtocolVersion(version uint32) {
+	atomic.StoreUint32(&c.protocolVersion, version)
+}
+
+// Upgrades the received message in the order of the shims' version. Returns nil if the message should be dropped.
+func (c *Connection) upgradeMessage(msgType channeldpb.MessageType, msg common.Message) common.Message {
+	version := c.ProtocolVersion()
+	for _, shim := range compatShims[msgType] {
+		if version < shim.Version && shim.Upgrade != nil {
+			if msg = shim.Upgrade(msg); msg == nil {
+				return nil
+			}
+		}
+	}
+	return msg
+}
+
+// Downgrades the message to send in the reverse order of the shims' version. Returns nil if the message should be dropped.
+func (c *Connection) downgradeMessage(msgType channeldpb.MessageType, msg common.Message) common.Message {
+	version := c.ProtocolVersion()
+	shims := compatShims[msgType]
+	for i := len(shims) - 1; i >= 0; i-- {
+		if version < shims[i].Version && shims[i].Downgrade != nil {
+			if msg = shims[i].Downgrade(msg); msg == nil {
+				return nil
+			}
+		}
+	}
+	return msg
+}
+
+func dropMessage(msg common.Message) common.Message {
+	return nil
+}
+
+// Replaces the auth result that the older SDKs don't know with INVALID_LT.
+func downgradeAuthResult(result channeldpb.AuthResultMessage_AuthResult) func(common.Message) common.Message {
+	return func(msg common.Message) common.Message {
+		resultMsg, ok := msg.(*channeldpb.AuthResultMessage)
+		if !ok || resultMsg.Result != result {
+			return msg
+		}
+		return &channeldpb.AuthResultMessage{
+			Result:           channeldpb.AuthResultMessage_INVALID_LT,
+			ConnId:           resultMsg.ConnId,
+			CompressionType:  resultMsg.CompressionType,
+			ChannelDataCodec: resultMsg.ChannelDataCodec,
+			UserId:           resultMsg.UserId,
+			ProtocolVersion:  resultMsg.ProtocolVersion,
+			ResumeToken:      resultMsg.ResumeToken,
+		}
+	}
+}
+
+// The changes in protocol version 1
+func init() {
+	// DUPLICATE_LOGIN is not in the older SDKs' AuthResult.
+	RegisterCompatShim(&CompatShim{
+		MsgType:   channeldpb.MessageType_AUTH,
+		Version:   1,
+		Downgrade: downgradeAuthResult(channeldpb.AuthResultMessage_DUPLICATE_LOGIN),
+	})
+
+	// The notifications that the older SDKs don't know
+	for _, msgType := range []channeldpb.MessageType{
+		channeldpb.MessageType_CHANNEL_RECOVERED,
+		channeldpb.MessageType_DUPLICATE_LOGIN,
+		channeldpb.MessageType_SLOW_SUBSCRIBER,
+	} {
+		RegisterCompatShim(&CompatShim{MsgType: msgType, Version: 1, Downgrade: dropMessage})
+	}
+}
+
+// The changes in protocol version 2
+func init() {
+	// QUOTA_EXCEEDED is not in the older SDKs' AuthResult.
+	RegisterCompatShim(&CompatShim{
+		MsgType:   channeldpb.MessageType_AUTH,
+		Version:   2,
+		Downgrade: downgradeAuthResult(channeldpb.AuthResultMessage_QUOTA_EXCEEDED),
+	})
+	RegisterCompatShim(&CompatShim{MsgType: channeldpb.MessageType_QUOTA_EXCEEDED, Version: 2, Downgrade: dropMessage})
+}
+
+// The changes in protocol version 3
+func init() {
+	RegisterCompatShim(&CompatShim{MsgType: channeldpb.MessageType_CHAT_PENALTY, Version: 3, Downgrade: dropMessage})
+}
+
+// The changes in protocol version 4
+func init() {
+	// The older SDKs don't expect the DisconnectMessage from channeld.
+	RegisterCompatShim(&CompatShim{MsgType: channeldpb.MessageType_DISCONNECT, Version: 4, Downgrade: dropMessage})
+}
+
+// The changes in protocol version 5
+func init() {
+	// The older SDKs can't reply to the idle ping, so they are closed after the ping timeout without it.
+	RegisterCompatShim(&CompatShim{MsgType: channeldpb.MessageType_PING, Version: 5, Downgrade: dropMessage})
+}
+
+// The changes in protocol version 6
+func init() {
+	RegisterCompatShim(&CompatShim{MsgType: channeldpb.MessageType_CHANNEL_OWNER_CHANGED, Version: 6, Downgrade: dropMessage})
+}
+
+// Protocol version 7 adds MessagePack.continued, which needs no shim, as the messages are only chunked for the connections
+// that support it. See queueMessagePack().
+
+// The changes in protocol version 8
+func init() {
+	RegisterCompatShim(&CompatShim{MsgType: channeldpb.MessageType_MESSAGE_TOO_LARGE, Version: 8, Downgrade: dropMessage})
+}
+
+// The changes in protocol version 9
+func init() {
+	RegisterCompatShim(&CompatShim{MsgType: channeldpb.MessageType_CHANNEL_STATS, Version: 9, Downgrade: dropMessage})
+}
+
+// The changes in protocol version 10
+func init() {
+	RegisterCompatShim(&CompatShim{MsgType: channeldpb.MessageType_ERROR, Version: 10, Downgrade: dropMessage})
+}
+
+// The changes in protocol version 11
+func init() {
+	RegisterCompatShim(&CompatShim{MsgType: channeldpb.MessageType_CHANNEL_TICK_LAG, Version: 11, Downgrade: dropMessage})
+}
@@ -0,0 +1,75 @@
+package channeld
+
+import (
+	"testing"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNegotiateProtocolVersion(t *testing.T) {
+	assert.EqualValues(t, 0, negotiateProtocolVersion(0))
+	assert.Equal(t, ProtocolVersion, negotiateProtocolVersion(ProtocolVersion))
+	assert.Equal(t, ProtocolVersion, negotiateProtocolVersion(ProtocolVersion+1))
+}
+
+func TestCompatShimDowngrade(t *testing.T) {
+	c := newLoopbackConnection(channeldpb.CompressionType_NO_COMPRESSION)
+	sender := &queuedMessagePackSender{}
+	popMsg := func(msgType channeldpb.MessageType, msg common.Message) bool {
+		select {
+		case mp := <-c.sendQueues[GetMessagePriority(msgType)]:
+			assert.NoError(t, proto.Unmarshal(mp.MsgBody, msg))
+			return true
+		default:
+			return false
+		}
+	}
+
+	// The legacy SDK doesn't know DUPLICATE_LOGIN
+	sender.Send(c, MessageContext{MsgType: channeldpb.MessageType_AUTH, Msg: &channeldpb.AuthResultMessage{Result: channeldpb.AuthResultMessage_DUPLICATE_LOGIN, ConnId: 1}})
+	resultMsg := &channeldpb.AuthResultMessage{}
+	assert.True(t, popMsg(channeldpb.MessageType_AUTH, resultMsg))
+	assert.Equal(t, channeldpb.AuthResultMessage_INVALID_LT, resultMsg.Result)
+	assert.EqualValues(t, 1, resultMsg.ConnId)
+
+	sender.Send(c, MessageContext{MsgType: channeldpb.MessageType_SLOW_SUBSCRIBER, Msg: &channeldpb.SlowSubscriberMessage{ConnId: 1}})
+	assert.False(t, popMsg(channeldpb.MessageType_SLOW_SUBSCRIBER, &channeldpb.SlowSubscriberMessage{}))
+
+	// The up-to-date SDK receives the messages as they are
+	c.setProtocolVersion(ProtocolVersion)
+	sender.Send(c, MessageContext{MsgType: channeldpb.MessageType_AUTH, Msg: &channeldpb.AuthResultMessage{Result: channeldpb.AuthResultMessage_DUPLICATE_LOGIN}})
+	assert.True(t, popMsg(channeldpb.MessageType_AUTH, resultMsg))
+	assert.Equal(t, channeldpb.AuthResultMessage_DUPLICATE_LOGIN, resultMsg.Result)
+
+	sender.Send(c, MessageContext{MsgType: channeldpb.MessageType_SLOW_SUBSCRIBER, Msg: &channeldpb.SlowSubscriberMessage{ConnId: 1}})
+	assert.True(t, popMsg(channeldpb.MessageType_SLOW_SUBSCRIBER, &channeldpb.SlowSubscriberMessage{}))
+}
+
+func TestCompatShimOrder(t *testing.T) {
+	const msgType = channeldpb.MessageType_USER_SPACE_START
+	defer delete(compatShims, msgType)
+
+	appendText := func(text string) func(common.Message) common.Message {
+		return func(msg common.Message) common.Message {
+			return &channeldpb.ServerForwardMessage{Payload: append(append([]byte{}, msg.(*channeldpb.ServerForwardMessage).Payload...), text...)}
+		}
+	}
+	// Registered out of order. The shim for the next version is to test the order.
+	RegisterCompatShim(&CompatShim{MsgType: msgType, Version: ProtocolVersion + 1, Upgrade: appendText("b"), Downgrade: appendText("b")})
+	RegisterCompatShim(&CompatShim{MsgType: msgType, Version: ProtocolVersion, Upgrade: appendText("a"), Downgrade: appendText("a")})
+
+	c := newLoopbackConnection(channeldpb.CompressionType_NO_COMPRESSION)
+	msg := &channeldpb.ServerForwardMessage{}
+	// The oldest change is upgraded first, and downgraded last
+	assert.Equal(t, []byte("ab"), c.upgradeMessage(msgType, msg).(*channeldpb.ServerForwardMessage).Payload)
+	assert.Equal(t, []byte("ba"), c.downgradeMessage(msgType, msg).(*channeldpb.ServerForwardMessage).Payload)
+	// The original message is not modified
+	assert.Empty(t, msg.Payload)
+
+	// Only the newer changes apply
+	c.setProtocolVersion(ProtocolVersion)
+	assert.Equal(t, []byte("b"), c.upgradeMessage(msgType, msg).(*channeldpb.ServerForwardMessage).Payload)
+}
@@ -7,6 +7,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -27,14 +28,6 @@ type ConnectionId uint32
 const MaxPacketSize int = 0x00ffff
 const PacketHeaderSize int = 5
 
-//type ConnectionState int32
-
-const (
-	ConnectionState_UNAUTHENTICATED int32 = 0
-	ConnectionState_AUTHENTICATED   int32 = 1
-	ConnectionState_CLOSING         int32 = 2
-)
-
 // Add an interface before the underlying network layer for the test purpose.
 type MessageSender interface {
 	Send(c *Connection, ctx MessageContext) //(c *Connection, channelId ChannelId, msgType channeldpb.MessageType, msg Message)
@@ -50,24 +43,74 @@ func (s *queuedMessageCtxSender) Send(c *Connection, ctx MessageContext) {
 }
 */
 
+// The outgoing messages are put into different lanes by their priorities, so a flood of fan-out traffic
+// can never delay the control messages (auth replies, unsubscribe confirmations, etc.)
+type MessagePriority uint8
+
+const (
+	MessagePriority_Control MessagePriority = iota
+	MessagePriority_UserSpace
+	MessagePriority_ChannelData
+	messagePriorityCount
+)
+
+func GetMessagePriority(msgType channeldpb.MessageType) MessagePriority {
+	switch {
+	case msgType == channeldpb.MessageType_CHANNEL_DATA_UPDATE, msgType == channeldpb.MessageType_CHANNEL_DATA_HANDOVER:
+		return MessagePriority_ChannelData
+	case msgType >= channeldpb.MessageType_USER_SPACE_START:
+		return MessagePriority_UserSpace
+	default:
+		return MessagePriority_Control
+	}
+}
+
 type queuedMessagePackSender struct {
 	MessageSender
 }
 
 func (s *queuedMessagePackSender) Send(c *Connection, ctx MessageContext) {
-	msgBody, err := proto.Marshal(ctx.Msg)
+	if ctx.rawPayload {
+		if forwardMsg, ok := ctx.Msg.(*channeldpb.ServerForwardMessage); ok {
+			c.queueMessagePack(&channeldpb.MessagePack{
+				ChannelId:    ctx.ChannelId,
+				Broadcast:    ctx.Broadcast,
+				StubId:       ctx.StubId,
+				MsgType:      uint32(ctx.MsgType),
+				MsgBody:      forwardMsg.Payload,
+				RawPayload:   true,
+				ClientConnId: forwardMsg.ClientConnId,
+				TraceId:      ctx.TraceId,
+			}, ctx)
+			return
+		}
+	}
+
+	msg := c.downgradeMessage(ctx.MsgType, ctx.Msg)
+	if msg == nil {
+		return
+	}
+
+	msg, err := c.encodeChannelDataMessage(msg)
+	if err != nil {
+		c.logger.Error("failed to encode channel data", zap.Error(err), zap.Uint32("msgType", uint32(ctx.MsgType)), zap.Stringer("codec", c.channelDataCodec))
+		return
+	}
+
+	msgBody, err := proto.Marshal(msg)
 	if err != nil {
 		c.logger.Error("failed to marshal message", zap.Error(err), zap.Uint32("msgType", uint32(ctx.MsgType)))
 		return
 	}
 
-	c.sendQueue <- &channeldpb.MessagePack{
+	c.queueMessagePack(&channeldpb.MessagePack{
 		ChannelId: ctx.ChannelId,
 		Broadcast: ctx.Broadcast,
 		StubId:    ctx.StubId,
 		MsgType:   uint32(ctx.MsgType),
 		MsgBody:   msgBody,
-	}
+		TraceId:   ctx.TraceId,
+	}, ctx)
 }
 
 type Connection struct {
@@ -75,28 +118,71 @@ type Connection struct {
 	id              ConnectionId
 	connectionType  channeldpb.ConnectionType
 	compressionType channeldpb.CompressionType
-	conn            net.Conn
-	readBuffer      []byte
-	readPos         int
+	// Decided by the first packet received. 0 means not decided yet, and v1 is used in sending. See WireVersion.
+	wireVersion WireVersion
+	// Set when the AuthMessage is received. See ProtocolVersion.
+	protocolVersion uint32
+	// Set when the AuthMessage is received. 0 means MaxPacketSize. See MaxPacketSize().
+	maxPacketSize uint32
+	// Set when authenticated. See channeldpb.AuthMessage.ChannelDataCodec.
+	channelDataCodec channeldpb.ChannelDataCodec
+	conn             net.Conn
+	readBuffer       []byte
+	readBufferPtr    *[]byte // The pooled buffer that backs the readBuffer
+	readPos          int
+	discardBytes     int // The rest of the oversized packet to discard in the next reads. See dropOversizedPacket().
 	// reader          *bufio.Reader
 	// writer          *bufio.Writer
 	sender               MessageSender
-	sendQueue            chan *channeldpb.MessagePack //MessageContext
+	sendQueues           [messagePriorityCount]chan *channeldpb.MessagePack
+	pendingSend          *channeldpb.MessagePack // The message that didn't fit in the last packet. Will be sent first in the next flush.
+	sendQueueBytes       int64                   // The total size of the message bodies in the send queues
+	chunkAssembler       MessagePackAssembler    // Reassembles the chunked messages received. Only used in the receive goroutine.
+	// The encryption of the packets sent. Only used in the flush goroutine. See startSession().
+	session              *connectionSession
+	pendingSession       atomic.Value // *connectionSession
+	encrypted            uint32       // Set to 1 when the encryption is requested
+	receiveSessionKeys   atomic.Value // map[uint32]*SessionKey. The keys to decrypt the packets received, replaced on rotation.
 	pit                  string
 	fsm                  *fsm.FiniteStateMachine
 	fsmDisallowedCounter int
 	logger               *Logger
-	state                int32 // Don't put the connection state into the FSM as 1) the FSM's states are user-defined. 2) the FSM is not goroutine-safe.
+	state                int32 // See ConnectionState. Don't put the connection state into the FSM as 1) the FSM's states are user-defined. 2) the FSM is not goroutine-safe.
 	connTime             time.Time
-	closeHandlers        []func()
+	// In unix nanoseconds. See checkIdle().
+	lastReceiveTime int64
+	idlePingTime    int64
+	closeHandlers   []func()
+	// Only set for the server connection from an edge relay
+	relayLink            *relayLink
 	replaySession        *replaypb.ReplaySession
 	spatialSubscriptions *xsync.MapOf[common.ChannelId, *channeldpb.ChannelSubscriptionOptions]
+	tags                 *xsync.MapOf[string, string]
+	// Only used in the chaos mode
+	chaosSlow bool
+	// The time (unix nano) of the last notification of the exceeded bandwidth. See consumeTenantBandwidth().
+	bandwidthExceededTime int64
+	// The users that the connection doesn't receive the user-space broadcasts from. See BlockUser().
+	blockedUsers *xsync.MapOf[string, struct{}]
+	// See channeldpb.DisconnectMessage_Reason and CloseWithReason().
+	disconnectReason int32
+	// The *connectionTrace, if the messages of the connection are being traced. See StartTrace().
+	trace atomic.Value
+	// The *NetworkConditions emulated on the packets sent to the connection. See SetNetworkConditions().
+	netemConditions atomic.Value
+	netem           netemState
+	// The queued fan-outs to measure the latency of. See trackFanOutLatency().
+	fanOutArrivals     map[*channeldpb.MessagePack]fanOutArrival
+	fanOutArrivalsLock sync.Mutex
+	// The latest *BandwidthReport of the client. See handleBandwidthReport().
+	bandwidthReport atomic.Value
 }
 
 var allConnections *xsync.MapOf[ConnectionId, *Connection]
 var nextConnectionId uint32 = 0
 var serverFsm *fsm.FiniteStateMachine
 var clientFsm *fsm.FiniteStateMachine
+var operatorFsm *fsm.FiniteStateMachine
 
 func InitConnections(serverFsmPath string, clientFsmPath string) {
 	if allConnections != nil {
@@ -132,6 +218,25 @@ func InitConnections(serverFsmPath string, clientFsmPath string) {
 			zap.String("currentState", clientFsm.CurrentState().Name),
 		)
 	}
+
+	go checkIdleConns()
+}
+
+// Loads the FSM of the OPERATOR connections, which only allows the live-ops messages (inspect, inject and force-unsub),
+// instead of everything the servers can send.
+func InitOperatorConnections(operatorFsmPath string) {
+	bytes, err := os.ReadFile(operatorFsmPath)
+	if err == nil {
+		operatorFsm, err = fsm.Load(bytes)
+	}
+	if err != nil {
+		rootLogger.Panic("failed to read operator FSM", zap.Error(err))
+	} else {
+		rootLogger.Info("loaded operator FSM",
+			zap.String("path", operatorFsmPath),
+			zap.String("currentState", operatorFsm.CurrentState().Name),
+		)
+	}
 }
 
 func GetConnection(id ConnectionId) *Connection {
@@ -151,7 +256,12 @@ func startGoroutines(connection *Connection) {
 	go func() {
 		for !connection.IsClosing() {
 			connection.receive()
+			if chaosEnabled && chaosShouldDrop(connection) {
+				connection.Close()
+			}
 		}
+		// The read buffer is only accessed in the receive goroutine, so it's safe to put it back now.
+		putBuffer(connection.readBufferPtr)
 	}()
 
 	// tick & flush goroutine
@@ -163,21 +273,49 @@ func startGoroutines(connection *Connection) {
 	}()
 }
 
+// The options of StartListeningWithOptions().
+type ListenOptions struct {
+	// Reads the PROXY protocol header on the accepted connections, for the real addresses of the clients behind the L4 load balancer.
+	// The connections without the header are refused. Only works with TCP. See readProxyHeader().
+	ProxyProtocol bool
+}
+
 func StartListening(t channeldpb.ConnectionType, network string, address string) {
+	StartListeningWithOptions(t, network, address, ListenOptions{
+		ProxyProtocol: t == channeldpb.ConnectionType_CLIENT && GlobalSettings.ClientProxyProtocol,
+	})
+}
+
+func StartListeningWithOptions(t channeldpb.ConnectionType, network string, address string, options ListenOptions) {
 	rootLogger.Info("start listenning",
 		zap.String("connType", t.String()),
 		zap.String("network", network),
 		zap.String("address", address),
+		zap.Bool("proxyProtocol", options.ProxyProtocol),
 	)
 
 	var listener net.Listener
 	var err error
-	switch network {
-	case "ws", "websocket":
-		startWebSocketServer(t, address)
+	// The suffix "4" or "6" restricts the address family, e.g. "tcp6", "kcp4" or "ws6". See ListenerSettings.
+	transport, family := splitNetwork(network)
+	if options.ProxyProtocol && transport != "tcp" {
+		rootLogger.Panic("PROXY protocol only works with tcp", zap.String("network", network))
+		return
+	}
+	switch transport {
+	case "ws":
+		startWebSocketServer(t, "tcp"+family, address)
 		return
 	case "kcp":
-		listener, err = kcp.Listen(address)
+		if family == "" {
+			listener, err = kcp.Listen(address)
+		} else {
+			var packetConn net.PacketConn
+			packetConn, err = net.ListenPacket("udp"+family, address)
+			if err == nil {
+				listener, err = kcp.ServeConn(nil, 0, 0, packetConn)
+			}
+		}
 	default:
 		listener, err = net.Listen(network, address)
 	}
@@ -189,13 +327,25 @@ func StartListening(t channeldpb.ConnectionType, network string, address string)
 
 	defer listener.Close()
 
+	// The PROXY headers are read in separate goroutines so a slow connection doesn't block the others,
+	// then the connections are added in one goroutine, the same as startWebSocketServer().
+	var proxiedConns chan net.Conn
+	if options.ProxyProtocol {
+		proxiedConns = make(chan net.Conn, 128)
+		go func() {
+			for conn := range proxiedConns {
+				acceptConnection(t, conn)
+			}
+		}()
+	}
+
+	waitForWarmUp(t)
 	for {
 		conn, err := listener.Accept()
 		if err != nil {
 			rootLogger.Error("failed to accept connection", zap.Error(err))
 		} else {
-			if network == "tcp" {
-				tcpConn := conn.(*net.TCPConn)
+			if tcpConn, ok := conn.(*net.TCPConn); ok {
 				if err := tcpConn.SetReadBuffer(0x0fffff); err != nil {
 					rootLogger.Error("failed to set read buffer size", zap.Error(err))
 				}
@@ -205,22 +355,39 @@ func StartListening(t channeldpb.ConnectionType, network string, address string)
 				tcpConn.SetNoDelay(true)
 			}
 
-			// Check if the IP address is banned.
-			ip := GetIP(conn.RemoteAddr())
-			_, banned := ipBlacklist[ip]
-			if banned {
-				securityLogger.Info("refused connection of banned IP address", zap.String("ip", ip))
-				conn.Close()
+			if proxiedConns != nil {
+				go func(conn net.Conn) {
+					proxied, err := readProxyHeader(conn, ProxyHeaderTimeout)
+					if err != nil {
+						securityLogger.Info("refused connection without valid PROXY header", zap.String("remoteAddr", conn.RemoteAddr().String()), zap.Error(err))
+						conn.Close()
+						return
+					}
+					proxiedConns <- proxied
+				}(conn)
 				continue
 			}
 
-			connection := AddConnection(conn, t)
-			connection.Logger().Debug("accepted connection")
-			startGoroutines(connection)
+			acceptConnection(t, conn)
 		}
 	}
 }
 
+func acceptConnection(t channeldpb.ConnectionType, conn net.Conn) {
+	// Check if the IP address is banned.
+	ip := GetIP(conn.RemoteAddr())
+	_, banned := ipBlacklist[ip]
+	if banned || FindBan(BanType_IP, ip) != nil {
+		securityLogger.Info("refused connection of banned IP address", zap.String("ip", ip))
+		conn.Close()
+		return
+	}
+
+	connection := AddConnection(conn, t)
+	connection.Logger().Debug("accepted connection")
+	startGoroutines(connection)
+}
+
 func generateNextConnId(c net.Conn, maxConnId uint32) {
 	if GlobalSettings.Development {
 		atomic.AddUint32(&nextConnectionId, 1)
@@ -239,18 +406,21 @@ func generateNextConnId(c net.Conn, maxConnId uint32) {
 // NOT goroutine-safe. NEVER call AddConnection in different goroutines.
 func AddConnection(c net.Conn, t channeldpb.ConnectionType) *Connection {
 	var readerSize int
+	var maxPendingChunkedBytes int
 	// var writerSize int
-	if t == channeldpb.ConnectionType_SERVER {
+	if t == channeldpb.ConnectionType_SERVER || t == channeldpb.ConnectionType_OPERATOR {
 		readerSize = GlobalSettings.ServerReadBufferSize
+		maxPendingChunkedBytes = GlobalSettings.ServerMaxPendingChunkedBytes
 		// writerSize = GlobalSettings.ServerWriteBufferSize
 	} else if t == channeldpb.ConnectionType_CLIENT {
 		readerSize = GlobalSettings.ClientReadBufferSize
+		maxPendingChunkedBytes = GlobalSettings.ClientMaxPendingChunkedBytes
 		// writerSize = GlobalSettings.ClientWriteBufferSize
 	} else {
 		rootLogger.Panic("invalid connection type", zap.Int32("connType", int32(t)))
 	}
-	if readerSize < MaxPacketSize+PacketHeaderSize {
-		readerSize = MaxPacketSize + PacketHeaderSize
+	if readerSize < MaxPacketSize+MaxPacketHeaderSize {
+		readerSize = MaxPacketSize + MaxPacketHeaderSize
 	}
 	maxConnId := uint32(1)<<GlobalSettings.MaxConnectionIdBits - 1
 
@@ -266,26 +436,32 @@ func AddConnection(c net.Conn, t channeldpb.ConnectionType) *Connection {
 		}
 	}
 
+	readBufferPtr := getBuffer(readerSize)
 	connection := &Connection{
 		id:              ConnectionId(nextConnectionId),
 		connectionType:  t,
 		compressionType: channeldpb.CompressionType_NO_COMPRESSION,
 		conn:            c,
-		readBuffer:      make([]byte, readerSize),
+		readBuffer:      (*readBufferPtr)[:readerSize],
+		readBufferPtr:   readBufferPtr,
 		readPos:         0,
 		// reader:    bufio.NewReaderSize(c, readerSize),
 		// writer:    bufio.NewWriterSize(c, writerSize),
 		sender:               &queuedMessagePackSender{},
-		sendQueue:            make(chan *channeldpb.MessagePack, 128),
+		sendQueues:           newSendQueues(),
+		chunkAssembler:       MessagePackAssembler{MaxPendingBytes: maxPendingChunkedBytes},
 		fsmDisallowedCounter: 0,
 		logger: &Logger{rootLogger.With(
 			zap.String("connType", t.String()),
 			zap.Uint32("connId", nextConnectionId),
 		)},
-		state:                ConnectionState_UNAUTHENTICATED,
+		state:                int32(ConnectionState_CONNECTING),
 		connTime:             time.Now(),
+		lastReceiveTime:      time.Now().UnixNano(),
 		closeHandlers:        make([]func(), 0),
 		spatialSubscriptions: xsync.NewTypedMapOf[common.ChannelId, *channeldpb.ChannelSubscriptionOptions](UintIdHasher[common.ChannelId]()),
+		tags:                 xsync.NewMapOf[string](),
+		blockedUsers:         xsync.NewMapOf[struct{}](),
 	}
 
 	if connection.isPacketRecordingEnabled() {
@@ -301,6 +477,12 @@ func AddConnection(c net.Conn, t channeldpb.ConnectionType) *Connection {
 			fsm := *serverFsm
 			connection.fsm = &fsm
 		}
+	case channeldpb.ConnectionType_OPERATOR:
+		if operatorFsm != nil {
+			// IMPORTANT: always make a value copy
+			fsm := *operatorFsm
+			connection.fsm = &fsm
+		}
 	case channeldpb.ConnectionType_CLIENT:
 		if clientFsm != nil {
 			// IMPORTANT: always make a value copy
@@ -320,10 +502,23 @@ func AddConnection(c net.Conn, t channeldpb.ConnectionType) *Connection {
 	}
 
 	connectionNum.WithLabelValues(t.String()).Inc()
+	connectionStateNum.WithLabelValues(t.String(), ConnectionState_CONNECTING.String()).Inc()
+
+	if chaosEnabled {
+		chaosOnConnectionAdded(connection)
+	}
 
 	return connection
 }
 
+func newSendQueues() [messagePriorityCount]chan *channeldpb.MessagePack {
+	var queues [messagePriorityCount]chan *channeldpb.MessagePack
+	for i := range queues {
+		queues[i] = make(chan *channeldpb.MessagePack, 128)
+	}
+	return queues
+}
+
 func (c *Connection) AddCloseHandler(handlerFunc func()) {
 	c.closeHandlers = append(c.closeHandlers, handlerFunc)
 }
@@ -332,7 +527,7 @@ func (c *Connection) Close() {
 	defer func() {
 		recover()
 	}()
-	if c.IsClosing() {
+	if !c.transitionTo(ConnectionState_DRAINING) {
 		c.Logger().Debug("connection is already closed")
 		return
 	}
@@ -345,18 +540,20 @@ func (c *Connection) Close() {
 		handlerFunc()
 	}
 
-	atomic.StoreInt32(&c.state, ConnectionState_CLOSING)
 	c.conn.Close()
-	close(c.sendQueue)
+	for _, queue := range c.sendQueues {
+		close(queue)
+	}
 	allConnections.Delete(c.id)
 	unauthenticatedConnections.Delete(c.id)
 
+	c.transitionTo(ConnectionState_CLOSED)
 	c.Logger().Info("closed connection")
 	connectionNum.WithLabelValues(c.connectionType.String()).Dec()
 }
 
 func (c *Connection) IsClosing() bool {
-	return c.state > ConnectionState_AUTHENTICATED
+	return c.State() >= ConnectionState_DRAINING
 }
 
 func (c *Connection) receive() {
@@ -386,6 +583,16 @@ func (c *Connection) receive() {
 		return
 	}
 	c.readPos += bytesRead
+	atomic.StoreInt64(&c.lastReceiveTime, time.Now().UnixNano())
+	if c.discardBytes > 0 {
+		n := c.discardBytes
+		if n > c.readPos {
+			n = c.readPos
+		}
+		copy(c.readBuffer, c.readBuffer[n:c.readPos])
+		c.readPos -= n
+		c.discardBytes -= n
+	}
 	if c.readPos < PacketHeaderSize {
 		// Unfinished header
 		fragmentedPacketCount.WithLabelValues(c.connectionType.String()).Inc()
@@ -429,26 +636,49 @@ func readSize(tag []byte) int {
 }
 
 func (c *Connection) readPacket(bufPos *int) (*channeldpb.Packet, error) {
-	tag := c.readBuffer[*bufPos : *bufPos+PacketHeaderSize]
-
-	packetSize := readSize(tag)
-	if packetSize == 0 {
+	header, complete, err := ReadPacketHeader(c.readBuffer[*bufPos:c.readPos])
+	if err == nil && header.Flags&WireFlag_Encrypted != 0 && c.receiveSessionKeys.Load() == nil {
+		err = fmt.Errorf("%w: the encryption is not requested", ErrUnsupportedWireFlags)
+	}
+	if err != nil && !errors.Is(err, ErrPacketOversized) {
+		tag := c.readBuffer[*bufPos:c.readPos]
+		if len(tag) > MaxPacketHeaderSize {
+			tag = tag[:MaxPacketHeaderSize]
+		}
 		c.readPos = 0
 		connectionClosed.WithLabelValues(c.connectionType.String()).Inc()
 		c.Logger().Warn("invalid tag, the connection will be closed",
 			zap.Binary("tag", tag),
+			zap.Error(err),
 		)
-		return nil, errors.New("invlaid tag")
+		return nil, err
+	}
+
+	if !complete {
+		// Unfinished header
+		fragmentedPacketCount.WithLabelValues(c.connectionType.String()).Inc()
+		return nil, nil
 	}
 
-	if packetSize > MaxPacketSize {
+	if c.wireVersion == 0 {
+		c.wireVersion = header.Version
+		c.Logger().Debug("decided the wire version", zap.Uint8("wireVersion", uint8(header.Version)))
+	} else if header.Version != c.wireVersion {
 		c.readPos = 0
 		connectionClosed.WithLabelValues(c.connectionType.String()).Inc()
-		c.Logger().Warn("packet size exceeds the limit, the connection will be closed", zap.Int("packetSize", packetSize), zap.Int("bufferSize", len(c.readBuffer)))
-		return nil, errors.New("packetSize too large")
+		c.Logger().Warn("wire version changed, the connection will be closed",
+			zap.Uint8("wireVersion", uint8(c.wireVersion)),
+			zap.Uint8("packetWireVersion", uint8(header.Version)),
+		)
+		return nil, errors.New("wire version changed")
+	}
+
+	if header.BodySize > c.MaxPacketSize() {
+		return c.dropOversizedPacket(header, bufPos), nil
 	}
 
-	fullSize := PacketHeaderSize + packetSize
+	packetSize := header.BodySize
+	fullSize := header.FullSize()
 
 	if c.readPos < *bufPos+fullSize {
 		// Unfinished packet
@@ -459,37 +689,59 @@ func (c *Connection) readPacket(bufPos *int) (*channeldpb.Packet, error) {
 		return nil, nil
 	}
 
-	bytes := c.readBuffer[*bufPos+PacketHeaderSize : *bufPos+fullSize]
+	bytes := c.readBuffer[*bufPos+header.HeaderSize : *bufPos+fullSize]
 
 	bytesReceived.WithLabelValues(c.connectionType.String()).Add(float64(fullSize))
 
-	// Apply the decompression from the 5th byte in the header
-	ct := tag[4]
-	_, valid := channeldpb.CompressionType_name[int32(ct)]
-	if valid && ct != 0 {
-		c.compressionType = channeldpb.CompressionType(ct)
-		if c.compressionType == channeldpb.CompressionType_SNAPPY {
-			len, err := snappy.DecodedLen(bytes)
-			if err != nil {
-				c.Logger().Error("snappy.DecodedLen", zap.Error(err))
-				return nil, err
+	if !c.consumeTenantBandwidth(fullSize) {
+		*bufPos += fullSize
+		return &channeldpb.Packet{}, nil
+	}
 
-			}
-			dst := make([]byte, len)
-			bytes, err = snappy.Decode(dst, bytes)
-			if err != nil {
-				c.Logger().Error("snappy.Decode", zap.Error(err))
-				return nil, err
+	// The body is compressed before it's encrypted
+	if header.Flags&WireFlag_Encrypted != 0 {
+		bytes, err = c.openPacket(bytes)
+		if err != nil {
+			c.readPos = 0
+			connectionClosed.WithLabelValues(c.connectionType.String()).Inc()
+			c.Logger().Warn("failed to decrypt the packet, the connection will be closed", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	// Apply the decompression from the 5th byte in the v1 header, or the flags in the v2 header
+	if header.Flags&WireFlag_Compressed != 0 {
+		c.compressionType = channeldpb.CompressionType_SNAPPY
+		len, err := snappy.DecodedLen(bytes)
+		if err != nil {
+			c.Logger().Error("snappy.DecodedLen", zap.Error(err))
+			return nil, err
+
+		}
+		dst := getBuffer(len)
+		// The unmarshalled packet doesn't reference the decoded bytes
+		defer putBuffer(dst)
+		bytes, err = snappy.Decode((*dst)[:len], bytes)
+		if err != nil {
+			c.Logger().Error("snappy.Decode", zap.Error(err))
+			return nil, err
 
-			}
 		}
 	}
 
 	var p channeldpb.Packet
-	if err := proto.Unmarshal(bytes, &p); err != nil {
+	if header.Flags&WireFlag_Batched != 0 {
+		err = proto.Unmarshal(bytes, &p)
+	} else {
+		// v2 sends the single message without the Packet wrapper
+		mp := &channeldpb.MessagePack{}
+		err = proto.Unmarshal(bytes, mp)
+		p.Messages = []*channeldpb.MessagePack{mp}
+	}
+	if err != nil {
 		c.Logger().Error("failed to unmarshall packet, the connection will be closed", zap.Error(err),
 			zap.Uint32("size", uint32(packetSize)),
-			zap.Binary("tag", tag),
+			zap.Binary("tag", c.readBuffer[*bufPos:*bufPos+header.HeaderSize]),
 		)
 		//if c.connectionType == channeldpb.ConnectionType_CLIENT {
 		connectionClosed.WithLabelValues(c.connectionType.String()).Inc()
@@ -503,7 +755,30 @@ func (c *Connection) readPacket(bufPos *int) (*channeldpb.Packet, error) {
 	}
 
 	for _, mp := range p.Messages {
-		c.receiveMessage(mp)
+		// The chunks are buffered before the auth and FSM checks, so only the authenticated connections can send them
+		if mp.Continued && (c.State() != ConnectionState_READY || c.ProtocolVersion() < chunkProtocolVersion) {
+			c.readPos = 0
+			connectionClosed.WithLabelValues(c.connectionType.String()).Inc()
+			c.Logger().Warn("received the chunk before the chunking is negotiated, the connection will be closed",
+				zap.Uint32("msgType", mp.MsgType),
+				zap.Uint32("protocolVersion", c.ProtocolVersion()),
+			)
+			return nil, errors.New("chunking not negotiated")
+		}
+		full, err := c.chunkAssembler.Add(mp)
+		if errors.Is(err, ErrPendingChunksExceeded) {
+			c.readPos = 0
+			connectionClosed.WithLabelValues(c.connectionType.String()).Inc()
+			c.Logger().Warn("too many pending chunks, the connection will be closed", zap.Error(err), zap.Uint32("msgType", mp.MsgType))
+			return nil, err
+		}
+		if err != nil {
+			c.Logger().Error("failed to reassemble the chunked message", zap.Error(err), zap.Uint32("msgType", mp.MsgType))
+			continue
+		}
+		if full != nil {
+			c.receiveMessage(full)
+		}
 	}
 
 	*bufPos += fullSize
@@ -515,18 +790,75 @@ func (c *Connection) isPacketRecordingEnabled() bool {
 }
 
 func (c *Connection) receiveMessage(mp *channeldpb.MessagePack) {
-	channel := GetChannel(common.ChannelId(mp.ChannelId))
+	if mp.MsgType == uint32(channeldpb.MessageType_SERVER_BATCH) {
+		// The entries target their own channels. See handleServerBatchMessage().
+		c.handleServerBatchMessage(mp)
+		return
+	}
+
+	var channel *Channel
+	if mp.ChannelKey != "" {
+		channel = GetTenantChannelByKey(GetTenant(c), mp.ChannelKey)
+		if channel != nil {
+			// The handlers and the responses use the resolved channelId
+			mp.ChannelId = uint32(channel.id)
+		}
+	} else {
+		// The channels of the other tenants are treated as non-existing
+		channel = GetChannelOfConnection(common.ChannelId(mp.ChannelId), c)
+	}
+	// The channel may be created on the first subscription. See handleSubToAutoCreatedChannel().
+	autoCreate := channel == nil && mp.MsgType == uint32(channeldpb.MessageType_SUB_TO_CHANNEL)
+	if autoCreate {
+		channel = globalChannel
+	}
 	if channel == nil {
 		c.Logger().Warn("can't find channel",
 			zap.Uint32("channelId", mp.ChannelId),
+			zap.String("channelKey", mp.ChannelKey),
 			zap.Uint32("msgType", mp.MsgType),
 		)
+		c.replyError(mp, channeldpb.ErrorMessage_CHANNEL_NOT_FOUND, "channel not found")
+		return
+	}
+
+	if mp.MsgType == uint32(channeldpb.MessageType_PING) {
+		// The reply of the ping. The traffic has been recorded in receive().
+		return
+	}
+
+	if mp.MsgType == uint32(channeldpb.MessageType_ANALYTICS) {
+		// Handled in the receive goroutine, without involving the channel. See handleAnalyticsMessage().
+		c.handleAnalyticsMessage(mp)
+		return
+	}
+
+	if mp.MsgType == uint32(channeldpb.MessageType_BANDWIDTH_REPORT) {
+		// Handled in the receive goroutine, without involving the channel. See handleBandwidthReport().
+		c.handleBandwidthReport(mp)
+		return
+	}
+
+	if mp.MsgType == uint32(channeldpb.MessageType_RELAY) {
+		// Handled in the receive goroutine instead of the channel's. See handleRelayMessage().
+		c.handleRelayMessage(mp)
 		return
 	}
 
 	entry := MessageMap[channeldpb.MessageType(mp.MsgType)]
 	if entry == nil && mp.MsgType < uint32(channeldpb.MessageType_USER_SPACE_START) {
 		c.Logger().Error("undefined message type", zap.Uint32("msgType", mp.MsgType))
+		c.replyError(mp, channeldpb.ErrorMessage_MESSAGE_NOT_ALLOWED, "undefined message type")
+		return
+	}
+
+	// Enforced regardless of the FSM, which is user-defined
+	if mp.MsgType != uint32(channeldpb.MessageType_AUTH) && c.State() != ConnectionState_READY {
+		c.Logger().Warn("message is not allowed before authenticated",
+			zap.Uint32("msgType", mp.MsgType),
+			zap.Stringer("state", c.State()),
+		)
+		c.replyError(mp, channeldpb.ErrorMessage_MESSAGE_NOT_ALLOWED, "message is not allowed before authenticated")
 		return
 	}
 
@@ -536,6 +868,7 @@ func (c *Connection) receiveMessage(mp *channeldpb.MessagePack) {
 			zap.Uint32("msgType", mp.MsgType),
 			zap.String("connState", c.fsm.CurrentState().Name),
 		)
+		c.replyError(mp, channeldpb.ErrorMessage_MESSAGE_NOT_ALLOWED, "message is not allowed in the current state")
 		return
 	}
 
@@ -547,6 +880,10 @@ func (c *Connection) receiveMessage(mp *channeldpb.MessagePack) {
 			// User-space message without handler won't be deserialized.
 			msg = &channeldpb.ServerForwardMessage{ClientConnId: uint32(c.id), Payload: mp.MsgBody}
 			handler = handleClientToServerUserMessage
+		} else if mp.RawPayload {
+			// server -> channeld -> client/server, without the ServerForwardMessage wrapper
+			msg = &channeldpb.ServerForwardMessage{ClientConnId: mp.ClientConnId, Payload: mp.MsgBody}
+			handler = HandleServerToClientUserMessage
 		} else {
 			// server -> channeld -> client/server
 			msg = &channeldpb.ServerForwardMessage{}
@@ -557,6 +894,9 @@ func (c *Connection) receiveMessage(mp *channeldpb.MessagePack) {
 			}
 			handler = HandleServerToClientUserMessage
 		}
+		if scripted := scriptHandler(mp.MsgType); scripted != nil {
+			handler = scripted
+		}
 	} else {
 		handler = entry.handler
 		// Always make a clone!
@@ -564,15 +904,28 @@ func (c *Connection) receiveMessage(mp *channeldpb.MessagePack) {
 		err := proto.Unmarshal(mp.MsgBody, msg)
 		if err != nil {
 			c.Logger().Error("unmarshalling message", zap.Error(err))
+			c.replyError(mp, channeldpb.ErrorMessage_INVALID_MESSAGE, err.Error())
+			return
+		}
+		if err := c.decodeChannelDataMessage(msg); err != nil {
+			c.Logger().Error("failed to decode channel data", zap.Error(err), zap.Uint32("msgType", mp.MsgType), zap.Stringer("codec", c.channelDataCodec))
+			c.replyError(mp, channeldpb.ErrorMessage_INVALID_MESSAGE, err.Error())
+			return
+		}
+		if msg = c.upgradeMessage(channeldpb.MessageType(mp.MsgType), msg); msg == nil {
 			return
 		}
 	}
 
+	if autoCreate {
+		handler = handleSubToAutoCreatedChannel
+	}
+
 	c.fsm.OnReceived(mp.MsgType)
 
 	channel.PutMessage(msg, handler, c, mp)
 
-	c.Logger().VeryVerbose("received message", zap.Uint32("msgType", mp.MsgType), zap.Int("size", len(mp.MsgBody)))
+	c.Logger().VeryVerbose("received message", zap.Uint32("msgType", mp.MsgType), zap.Int("size", len(mp.MsgBody)), zap.String("traceId", mp.TraceId))
 	//c.Logger().Debug("received message", zap.Uint32("msgType", mp.MsgType), zap.Int("size", len(mp.MsgBody)))
 
 	msgReceived.WithLabelValues(c.connectionType.String()).Inc() /*.WithLabelValues(
@@ -589,47 +942,111 @@ func (c *Connection) Send(ctx MessageContext) {
 	c.sender.Send(c, ctx)
 }
 
+func (c *Connection) sendQueueLen() int {
+	n := 0
+	if c.pendingSend != nil {
+		n++
+	}
+	for _, queue := range c.sendQueues {
+		n += len(queue)
+	}
+	return n
+}
+
+// Pops the next message to send, from the highest priority lane that is not empty.
+func (c *Connection) nextMessageToSend() *channeldpb.MessagePack {
+	if mp := c.pendingSend; mp != nil {
+		c.pendingSend = nil
+		return mp
+	}
+	for _, queue := range c.sendQueues {
+		select {
+		case mp, ok := <-queue:
+			if ok {
+				atomic.AddInt64(&c.sendQueueBytes, -int64(len(mp.MsgBody)))
+				return mp
+			}
+		default:
+		}
+	}
+	return nil
+}
+
 // Should NOT be called outside the flush goroutine!
 func (c *Connection) flush() {
-	if len(c.sendQueue) == 0 {
+	c.releaseDelayedPackets()
+	if c.sendQueueLen() == 0 {
 		return
 	}
 
-	p := channeldpb.Packet{Messages: make([]*channeldpb.MessagePack, 0, len(c.sendQueue))}
+	p := channeldpb.Packet{Messages: make([]*channeldpb.MessagePack, 0, c.sendQueueLen())}
 	size := 0
+	now := time.Now()
+	// Switched to after the packet is sealed
+	nextSession := c.rotateSessionKey(&p, now)
+	maxSize := c.MaxPacketSize() - c.encryptionOverhead()
 
 	// For now we don't limit the message numbers per packet
-	for len(c.sendQueue) > 0 {
-		mp := <-c.sendQueue
+	for mp := c.nextMessageToSend(); mp != nil; mp = c.nextMessageToSend() {
 		p.Messages = append(p.Messages, mp)
 		size = proto.Size(&p)
-		if size > MaxPacketSize {
+		if size > maxSize {
 			c.Logger().Info("packet is going to be oversized",
 				zap.Int("packetSize", size),
 				zap.Uint32("msgType", uint32(mp.MsgType)),
 				zap.Int("msgSize", len(mp.MsgBody)),
 				zap.Int("msgNum", len(p.Messages)),
-				zap.Int("msgInQueue", len(c.sendQueue)),
+				zap.Int("msgInQueue", c.sendQueueLen()),
 			)
 
 			// Revert adding the message that causes the oversize
 			p.Messages = p.Messages[:len(p.Messages)-1]
 
-			// Put the message back to the queue
-			// FIXME: order may matter
-			c.sendQueue <- mp
+			if len(p.Messages) == 0 {
+				// The message can't fit in any packet. Holding it would stall the flush forever.
+				c.Logger().Error("dropped the message that exceeds the max packet size", zap.Uint32("msgType", mp.MsgType), zap.Int("maxPacketSize", c.MaxPacketSize()))
+				continue
+			}
+
+			// Hold the message for the next flush, so the order is kept
+			c.pendingSend = mp
 			break
 		}
 
+		if mp.MsgType == uint32(channeldpb.MessageType_AUTH) && c.session == nil && nextSession == nil {
+			nextSession = c.takePendingSession(now)
+		}
+
 		c.Logger().VeryVerbose("sent message", zap.Uint32("msgType", uint32(mp.MsgType)), zap.Int("size", len(mp.MsgBody)))
+		c.traceMessage(MessageTraceDirection_Out, mp.ChannelId, mp.MsgType, len(mp.MsgBody), 0)
 
 		msgSent.WithLabelValues(c.connectionType.String()).Inc() /*.WithLabelValues(
 			strconv.FormatUint(uint64(e.Channel.id), 10),
 			strconv.FormatUint(uint64(e.MsgType), 10),
 		)*/
 	}
+	if len(p.Messages) == 0 {
+		return
+	}
+
+	var body proto.Message = &p
+	flags := WireFlag_Batched
+	if c.wireVersion == WireVersion_V2 && len(p.Messages) == 1 {
+		// v2 sends the single message without the Packet wrapper
+		body = p.Messages[0]
+		flags = 0
+	}
+	if c.compressionType == channeldpb.CompressionType_SNAPPY {
+		flags |= WireFlag_Compressed
+	}
 
-	bytes, err := proto.Marshal(&p)
+	size = proto.Size(body)
+	// The tag and the packet body are written into the same buffer, to avoid writing multple times. With WebSocket, every Write() sends a message.
+	bufPtr := getBuffer(MaxPacketHeaderSize + size)
+	defer putBuffer(bufPtr)
+	// Leave the room for the header. See PutPacketHeader().
+	bytes := (*bufPtr)[:MaxPacketHeaderSize]
+	bytes, err := proto.MarshalOptions{UseCachedSize: true}.MarshalAppend(bytes, body)
 	if err != nil {
 		c.Logger().Error("failed to marshal packet", zap.Error(err))
 		return
@@ -637,25 +1054,34 @@ func (c *Connection) flush() {
 
 	// Apply the compression
 	if c.compressionType == channeldpb.CompressionType_SNAPPY {
-		dst := make([]byte, snappy.MaxEncodedLen(len(bytes)))
-		bytes = snappy.Encode(dst, bytes)
+		dstPtr := getBuffer(MaxPacketHeaderSize + snappy.MaxEncodedLen(size))
+		defer putBuffer(dstPtr)
+		dst := (*dstPtr)[:cap(*dstPtr)]
+		bytes = dst[:MaxPacketHeaderSize+len(snappy.Encode(dst[MaxPacketHeaderSize:], bytes[MaxPacketHeaderSize:]))]
+	}
+
+	// Apply the encryption, with the key before the rotation
+	if c.session != nil {
+		encPtr := getBuffer(len(bytes) + SessionKeyOverhead)
+		defer putBuffer(encPtr)
+		bytes = c.session.key.Seal((*encPtr)[:MaxPacketHeaderSize], bytes[MaxPacketHeaderSize:])
+		flags |= WireFlag_Encrypted
 	}
 
-	// 'CHNL' in ASCII
-	tag := []byte{67, 72, 78, 76, byte(c.compressionType)}
-	len := len(bytes)
-	tag[3] = byte(len & 0xff)
-	tag[2] = byte((len >> 8) & 0xff)
-	if len > MaxPacketSize {
+	len := len(bytes) - MaxPacketHeaderSize
+	if len > c.MaxPacketSize() {
 		// Should never happen, but log it just in case
 		c.Logger().Error("packet is oversized", zap.Int("size", len))
 		return
 	}
+	if nextSession != nil {
+		c.session = nextSession
+	}
+	bytes = PutPacketHeader(bytes, len, c.wireVersion, flags)
 
-	/* Avoid writing multple times. With WebSocket, every Write() sends a message.
-	writer.Write(tag)
-	*/
-	bytes = append(tag, bytes...)
+	if chaosEnabled {
+		chaosBeforeFlush(c)
+	}
 	/*
 		_, err = c.writer.Write(bytes)
 		if err != nil {
@@ -665,10 +1091,15 @@ func (c *Connection) flush() {
 
 		c.writer.Flush()
 	*/
+	if c.emulatePacket(bytes) {
+		c.observeFanOutLatency(p.Messages, true)
+		return
+	}
 	len, err = c.conn.Write(bytes)
 	if err != nil {
 		c.Logger().Error("error writing packet", zap.Error(err))
 	}
+	c.observeFanOutLatency(p.Messages, err == nil)
 
 	packetSent.WithLabelValues(c.connectionType.String()).Inc()
 	bytesSent.WithLabelValues(c.connectionType.String()).Add(float64(len))
@@ -687,12 +1118,10 @@ func (c *Connection) GetConnectionType() channeldpb.ConnectionType {
 }
 
 func (c *Connection) OnAuthenticated(pit string) {
-	if c.IsClosing() {
+	if !c.transitionTo(ConnectionState_READY) {
 		return
 	}
 
-	atomic.StoreInt32(&c.state, ConnectionState_AUTHENTICATED)
-
 	unauthenticatedConnections.Delete(c.id)
 
 	c.pit = pit
@@ -0,0 +1,103 @@
+package channeld
+
+import (
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+)
+
+// The max number of the users that a connection can block. The exceeded are ignored.
+const MaxBlockedUsers = 1000
+
+// Blocks the user-space broadcasts from the user. Goroutine-safe. Returns false if the block list is full.
+func (c *Connection) BlockUser(userId string) bool {
+	if _, exists := c.blockedUsers.Load(userId); exists {
+		return true
+	}
+	if c.blockedUsers.Size() >= MaxBlockedUsers {
+		return false
+	}
+	c.blockedUsers.Store(userId, struct{}{})
+	return true
+}
+
+// Goroutine-safe
+func (c *Connection) UnblockUser(userId string) {
+	c.blockedUsers.Delete(userId)
+}
+
+// Goroutine-safe
+func (c *Connection) ClearBlockedUsers() {
+	c.blockedUsers.Clear()
+}
+
+// Goroutine-safe
+func (c *Connection) IsBlocking(userId string) bool {
+	if c.blockedUsers == nil || userId == "" {
+		return false
+	}
+	_, exists := c.blockedUsers.Load(userId)
+	return exists
+}
+
+// Returns the user id that the connection's block list is checked against. If the server broadcasts on behalf of a client
+// (with the clientConnId), it's the user id of the client.
+func broadcastSenderUserId(ctx MessageContext, msg *channeldpb.ServerForwardMessage) string {
+	if ctx.Connection == nil {
+		return ""
+	}
+	if ctx.Connection.GetConnectionType() == channeldpb.ConnectionType_SERVER && msg.ClientConnId != 0 {
+		if client := GetConnection(ConnectionId(msg.ClientConnId)); client != nil {
+			return client.UserId()
+		}
+		return ""
+	}
+	userId, _ := ctx.Connection.GetTag(UserIdTag)
+	return userId
+}
+
+func isBlockedBy(conn ConnectionInChannel, userId string) bool {
+	c, ok := conn.(*Connection)
+	return ok && c.IsBlocking(userId)
+}
+
+func handleUpdateBlockList(ctx MessageContext) {
+	if ctx.Channel != globalChannel {
+		ctx.Connection.Logger().Error("illegal attemp to update block list outside the GLOBAL channel")
+		return
+	}
+
+	msg, ok := ctx.Msg.(*channeldpb.UpdateBlockListMessage)
+	if !ok {
+		ctx.Connection.Logger().Error("message is not an UpdateBlockListMessage, will not be handled.")
+		return
+	}
+
+	connId := ConnectionId(msg.ConnId)
+	if connId == 0 {
+		connId = ctx.Connection.Id()
+	} else if connId != ctx.Connection.Id() && ctx.Connection.GetConnectionType() != channeldpb.ConnectionType_SERVER {
+		ctx.Connection.Logger().Error("illegal attemp to update block list of another connection", zap.Uint32("targetConnId", msg.ConnId))
+		return
+	}
+
+	conn := GetConnection(connId)
+	if conn == nil {
+		ctx.Connection.Logger().Warn("could not find the connection to update block list", zap.Uint32("targetConnId", uint32(connId)))
+		return
+	}
+
+	if msg.Clear {
+		conn.ClearBlockedUsers()
+	}
+	for _, userId := range msg.UnblockUserIds {
+		conn.UnblockUser(userId)
+	}
+	for _, userId := range msg.BlockUserIds {
+		if !conn.BlockUser(userId) {
+			conn.Logger().Warn("the block list is full", zap.Int("maxBlockedUsers", MaxBlockedUsers), zap.String("userId", userId))
+			break
+		}
+	}
+
+	conn.Logger().Debug("updated block list", zap.Int("blockedUsers", conn.blockedUsers.Size()))
+}
@@ -0,0 +1,122 @@
+package channeld
+
+import (
+	"testing"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateBlockList(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	c1 := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	c2 := addTestConnection(channeldpb.ConnectionType_CLIENT)
+
+	update := func(sender *Connection, msg *channeldpb.UpdateBlockListMessage) {
+		handleUpdateBlockList(MessageContext{
+			MsgType:    channeldpb.MessageType_UPDATE_BLOCK_LIST,
+			Msg:        msg,
+			Connection: sender,
+			Channel:    globalChannel,
+		})
+	}
+
+	// The client updates its own list
+	update(c1, &channeldpb.UpdateBlockListMessage{BlockUserIds: []string{"user2", "user3"}})
+	assert.True(t, c1.IsBlocking("user2"))
+	assert.True(t, c1.IsBlocking("user3"))
+	update(c1, &channeldpb.UpdateBlockListMessage{UnblockUserIds: []string{"user3"}})
+	assert.False(t, c1.IsBlocking("user3"))
+
+	// The client can't update the list of another connection
+	update(c1, &channeldpb.UpdateBlockListMessage{ConnId: uint32(c2.Id()), BlockUserIds: []string{"user1"}})
+	assert.False(t, c2.IsBlocking("user1"))
+	// But the server can
+	update(server, &channeldpb.UpdateBlockListMessage{ConnId: uint32(c2.Id()), BlockUserIds: []string{"user1"}})
+	assert.True(t, c2.IsBlocking("user1"))
+
+	update(server, &channeldpb.UpdateBlockListMessage{ConnId: uint32(c1.Id()), Clear: true, BlockUserIds: []string{"user4"}})
+	assert.False(t, c1.IsBlocking("user2"))
+	assert.True(t, c1.IsBlocking("user4"))
+
+	for i := 0; i < MaxBlockedUsers; i++ {
+		c2.BlockUser(string(rune('a' + i)))
+	}
+	assert.Equal(t, MaxBlockedUsers, c2.blockedUsers.Size())
+	assert.False(t, c2.BlockUser("user5"))
+	// Blocking the blocked user is fine
+	assert.True(t, c2.BlockUser("user1"))
+}
+
+func TestBroadcastFromBlockedUser(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{BroadcastRetentionSize: 10}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	sender := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	sender.SetTag(UserIdTag, "troll")
+	blocking := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	blocking.BlockUser("troll")
+	other := addTestConnection(channeldpb.ConnectionType_CLIENT)
+
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, nil)
+	ch.enableClientBroadcast = true
+	for _, c := range []*Connection{server, sender, blocking, other} {
+		c.SubscribeToChannel(ch, nil)
+	}
+
+	countForwards := func(c *Connection) int {
+		n := 0
+		for _, msg := range c.testQueue() {
+			if _, ok := msg.(*channeldpb.ServerForwardMessage); ok {
+				n++
+			}
+		}
+		return n
+	}
+
+	// The client's broadcast
+	handleClientToServerUserMessage(MessageContext{
+		MsgType:    channeldpb.MessageType_USER_SPACE_START,
+		Msg:        &channeldpb.ServerForwardMessage{ClientConnId: uint32(sender.Id()), Payload: []byte{1}},
+		Broadcast:  uint32(channeldpb.BroadcastType_ALL_BUT_SENDER),
+		Connection: sender,
+		Channel:    ch,
+	})
+	assert.Equal(t, 0, countForwards(blocking))
+	assert.Equal(t, 1, countForwards(other))
+
+	// The server's broadcast on behalf of the client
+	HandleServerToClientUserMessage(MessageContext{
+		MsgType:    channeldpb.MessageType_USER_SPACE_START,
+		Msg:        &channeldpb.ServerForwardMessage{ClientConnId: uint32(sender.Id()), Payload: []byte{2}},
+		Broadcast:  uint32(channeldpb.BroadcastType_ALL),
+		Connection: server,
+		Channel:    ch,
+	})
+	assert.Equal(t, 0, countForwards(blocking))
+	assert.Equal(t, 2, countForwards(other))
+
+	// The server's own broadcast is not affected
+	HandleServerToClientUserMessage(MessageContext{
+		MsgType:    channeldpb.MessageType_USER_SPACE_START,
+		Msg:        &channeldpb.ServerForwardMessage{Payload: []byte{3}},
+		Broadcast:  uint32(channeldpb.BroadcastType_ALL),
+		Connection: server,
+		Channel:    ch,
+	})
+	assert.Equal(t, 1, countForwards(blocking))
+
+	// The blocked broadcasts are not replayed either
+	blocking.sender.(*testQueuedMessageSender).msgQueue = nil
+	ch.replayBroadcasts(blocking, 0)
+	assert.Equal(t, 1, countForwards(blocking))
+}
@@ -0,0 +1,102 @@
+package channeld
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// How long the resume token stays valid after the SERVER connection is closed. See channeldpb.AuthMessage.ResumeToken.
+const ResumeTokenTtl = 5 * time.Minute
+
+// The SERVER connections by the resume token
+var resumeTokens = make(map[string]*Connection)
+var resumeTokensLock sync.Mutex
+
+// Issues the resume token for the authenticated SERVER connection. Returns an empty string if the token can't be generated.
+func (c *Connection) issueResumeToken() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		c.Logger().Error("failed to generate the resume token", zap.Error(err))
+		return ""
+	}
+	token := hex.EncodeToString(buf)
+
+	resumeTokensLock.Lock()
+	resumeTokens[token] = c
+	resumeTokensLock.Unlock()
+
+	c.AddCloseHandler(func() {
+		time.AfterFunc(ResumeTokenTtl, func() {
+			resumeTokensLock.Lock()
+			defer resumeTokensLock.Unlock()
+			if resumeTokens[token] == c {
+				delete(resumeTokens, token)
+			}
+		})
+	})
+	return token
+}
+
+// Takes over the channels of the previous connection that the resume token was issued to, e.g. before the server restarted.
+// The previous connection must have the same PIT. If it's still open, it's closed. The GLOBAL channel is not taken over.
+// Returns false if the token is invalid.
+func (c *Connection) resume(token string) bool {
+	resumeTokensLock.Lock()
+	prev, exists := resumeTokens[token]
+	valid := exists && prev != c && prev.pit == c.pit
+	if valid {
+		// The token can only be used once
+		delete(resumeTokens, token)
+	}
+	resumeTokensLock.Unlock()
+
+	if !valid {
+		securityLogger.Warn("refused invalid resume token", zap.Uint32("connId", uint32(c.id)), zap.String("pit", c.pit))
+		return false
+	}
+
+	c.Logger().Info("taking over the channels of the previous connection", zap.Uint32("previousConnId", uint32(prev.id)))
+	allChannels.Range(func(_ common.ChannelId, ch *Channel) bool {
+		if ch.channelType != channeldpb.ChannelType_GLOBAL && !ch.IsRemoving() {
+			ch.Execute(func(ch *Channel) {
+				ch.resumeOwner(c, prev)
+			})
+		}
+		return true
+	})
+
+	if !prev.IsClosing() {
+		prev.CloseWithReason(channeldpb.DisconnectMessage_KICKED, "taken over by the resumed connection")
+	}
+	return true
+}
+
+// Makes the resumed connection the owner, if the channel is owned by the previous connection, or frozen for it.
+// The resumed connection is subscribed to the channel with the previous connection's options, if there's any.
+// Should be called in the channel's goroutine.
+func (ch *Channel) resumeOwner(newOwner *Connection, previousOwner *Connection) {
+	if ch.disconnectedOwner == previousOwner {
+		ch.disconnectedOwner = nil
+	} else if ch.ownerConnection != previousOwner && (ch.ownerReclaim == nil || ch.ownerReclaim.previousOwner != previousOwner) {
+		return
+	}
+
+	var options *channeldpb.ChannelSubscriptionOptions
+	if cs, exists := ch.subscribedConnections[previousOwner]; exists {
+		options = proto.Clone(&cs.options).(*channeldpb.ChannelSubscriptionOptions)
+	} else if ch.previousOwnerOptions != nil {
+		options = proto.Clone(ch.previousOwnerOptions).(*channeldpb.ChannelSubscriptionOptions)
+	}
+	cs, exists := newOwner.SubscribeToChannel(ch, options)
+	if cs != nil && !exists {
+		newOwner.sendSubscribed(MessageContext{}, ch, newOwner, 0, &cs.options)
+	}
+	ch.reclaimOwner(newOwner, previousOwner)
+}
@@ -0,0 +1,122 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResumeServerConnection(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		TickIntervalMs:         3600000,
+		OwnerDisconnectPolicy:  OwnerDisconnectPolicy_Freeze,
+		OwnerReclaimBufferSize: 2,
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	login := func(pit string, resumeToken string) (*Connection, string) {
+		c := addTestConnection(channeldpb.ConnectionType_SERVER)
+		onAuthComplete(MessageContext{
+			MsgType:    channeldpb.MessageType_AUTH,
+			Msg:        &channeldpb.AuthMessage{PlayerIdentifierToken: pit, ResumeToken: resumeToken},
+			Connection: c,
+			Channel:    globalChannel,
+		}, channeldpb.AuthResultMessage_SUCCESSFUL, pit)
+		for _, msg := range c.testQueue() {
+			if resultMsg, ok := msg.(*channeldpb.AuthResultMessage); ok {
+				return c, resultMsg.ResumeToken
+			}
+		}
+		return c, ""
+	}
+
+	owner, token := login("server1", "")
+	assert.NotEmpty(t, token)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, owner)
+	// Wait for the first tick, after which the channel.Tick() goroutine sleeps for an hour
+	time.Sleep(50 * time.Millisecond)
+	owner.SubscribeToChannel(ch, &channeldpb.ChannelSubscriptionOptions{DataAccess: Pointer(channeldpb.ChannelDataAccess_WRITE_ACCESS)})
+	client.SubscribeToChannel(ch, nil)
+
+	owner.Close()
+	ch.tickConnections()
+	ch.tickOwner(time.Now())
+	assert.True(t, ch.IsFrozen())
+
+	// The client messages to the owner are buffered
+	for _, payload := range []string{"a", "b", "c"} {
+		handleClientToServerUserMessage(MessageContext{
+			MsgType:    channeldpb.MessageType_USER_SPACE_START,
+			Msg:        &channeldpb.ServerForwardMessage{ClientConnId: uint32(client.Id()), Payload: []byte(payload)},
+			Connection: client,
+			Channel:    ch,
+		})
+	}
+
+	// The PIT doesn't match
+	other, _ := login("server2", "")
+	assert.False(t, other.resume(token))
+
+	newOwner, newToken := login("server1", token)
+	assert.NotEqual(t, token, newToken)
+	ch.tickMessages(time.Now(), "")
+	assert.False(t, ch.IsFrozen())
+	assert.Same(t, newOwner, ch.ownerConnection)
+	if cs := ch.subscribedConnections[newOwner]; assert.NotNil(t, cs) {
+		assert.Equal(t, channeldpb.ChannelDataAccess_WRITE_ACCESS, cs.options.GetDataAccess())
+	}
+	var payloads []string
+	for _, msg := range newOwner.testQueue() {
+		if forwardMsg, ok := msg.(*channeldpb.ServerForwardMessage); ok {
+			payloads = append(payloads, string(forwardMsg.Payload))
+		}
+	}
+	// The oldest is dropped as the buffer is full
+	assert.Equal(t, []string{"b", "c"}, payloads)
+
+	// The token can only be used once
+	another, _ := login("server1", "")
+	assert.False(t, another.resume(token))
+
+	// The previous connection is still open
+	assert.True(t, another.resume(newToken))
+	ch.tickMessages(time.Now(), "")
+	assert.Same(t, another, ch.ownerConnection)
+	assert.Equal(t, channeldpb.DisconnectMessage_KICKED, newOwner.DisconnectReason())
+}
+
+func TestResumeTokenNotSentToGlobalOwner(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	globalOwner := addTestConnection(channeldpb.ConnectionType_SERVER)
+	defer func(owner ConnectionInChannel) {
+		globalChannel.ownerConnection = owner
+	}(globalChannel.ownerConnection)
+	globalChannel.ownerConnection = globalOwner
+
+	c := addTestConnection(channeldpb.ConnectionType_SERVER)
+	onAuthComplete(MessageContext{
+		MsgType:    channeldpb.MessageType_AUTH,
+		Msg:        &channeldpb.AuthMessage{PlayerIdentifierToken: "server1"},
+		Connection: c,
+		Channel:    globalChannel,
+	}, channeldpb.AuthResultMessage_SUCCESSFUL, "server1")
+	defer c.Close()
+
+	if resultMsg, ok := c.latestMsg().(*channeldpb.AuthResultMessage); assert.True(t, ok) {
+		assert.NotEmpty(t, resultMsg.ResumeToken)
+	}
+	if resultMsg, ok := globalOwner.latestMsg().(*channeldpb.AuthResultMessage); assert.True(t, ok) {
+		assert.EqualValues(t, c.Id(), resultMsg.ConnId)
+		assert.Empty(t, resultMsg.ResumeToken)
+	}
+}
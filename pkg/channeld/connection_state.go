@@ -0,0 +1,92 @@
+package channeld
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+// The lifecycle state of a Connection. Unlike the user-defined FSM (see Connection.fsm), the states and the transitions are fixed,
+// and the state is goroutine-safe.
+type ConnectionState int32
+
+const (
+	// Connected, but hasn't sent the AuthMessage yet
+	ConnectionState_CONNECTING ConnectionState = 0
+	// The AuthMessage is being handled by the auth provider
+	ConnectionState_AUTHENTICATING ConnectionState = 1
+	// Authenticated. Only the AuthMessage is allowed before this state. See Connection.receiveMessage().
+	ConnectionState_READY ConnectionState = 2
+	// Being closed, e.g. running the close handlers
+	ConnectionState_DRAINING ConnectionState = 3
+	ConnectionState_CLOSED   ConnectionState = 4
+)
+
+var connectionStateNames = map[ConnectionState]string{
+	ConnectionState_CONNECTING:     "CONNECTING",
+	ConnectionState_AUTHENTICATING: "AUTHENTICATING",
+	ConnectionState_READY:          "READY",
+	ConnectionState_DRAINING:       "DRAINING",
+	ConnectionState_CLOSED:         "CLOSED",
+}
+
+func (s ConnectionState) String() string {
+	if name, exists := connectionStateNames[s]; exists {
+		return name
+	}
+	return fmt.Sprintf("ConnectionState(%d)", s)
+}
+
+// The allowed transitions, by the state to transition from.
+var connectionStateTransitions = map[ConnectionState][]ConnectionState{
+	// The connection can be authenticated without the auth provider, e.g. ServerBypassAuth.
+	ConnectionState_CONNECTING: {ConnectionState_AUTHENTICATING, ConnectionState_READY, ConnectionState_DRAINING},
+	// Moves back to CONNECTING if the auth fails, so the connection can retry.
+	ConnectionState_AUTHENTICATING: {ConnectionState_CONNECTING, ConnectionState_READY, ConnectionState_DRAINING},
+	ConnectionState_READY:          {ConnectionState_DRAINING},
+	ConnectionState_DRAINING:       {ConnectionState_CLOSED},
+}
+
+// Called after the state of a connection has changed, in the goroutine that makes the transition.
+type ConnectionStateHook func(c *Connection, from ConnectionState, to ConnectionState)
+
+var connectionStateHooks []ConnectionStateHook
+
+// Registers the hook that is called on every transition of the connection state.
+// Not goroutine-safe - should be called before channeld starts accepting the connections.
+func AddConnectionStateHook(hook ConnectionStateHook) {
+	connectionStateHooks = append(connectionStateHooks, hook)
+}
+
+func (c *Connection) State() ConnectionState {
+	return ConnectionState(atomic.LoadInt32(&c.state))
+}
+
+// Moves the connection to the state. Returns false if the transition is not allowed from the current state,
+// or another goroutine has changed the state at the same time.
+func (c *Connection) transitionTo(to ConnectionState) bool {
+	from := c.State()
+	allowed := false
+	for _, s := range connectionStateTransitions[from] {
+		if s == to {
+			allowed = true
+			break
+		}
+	}
+	if !allowed || !atomic.CompareAndSwapInt32(&c.state, int32(from), int32(to)) {
+		return false
+	}
+
+	connectionStateNum.WithLabelValues(c.connectionType.String(), from.String()).Dec()
+	// The closed connections are not counted
+	if to != ConnectionState_CLOSED {
+		connectionStateNum.WithLabelValues(c.connectionType.String(), to.String()).Inc()
+	}
+
+	c.Logger().Debug("connection state changed", zap.Stringer("from", from), zap.Stringer("to", to))
+	for _, hook := range connectionStateHooks {
+		hook(c, from, to)
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/fsm"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestConnectionStateTransitions(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	var transitions [][2]ConnectionState
+	AddConnectionStateHook(func(c *Connection, from ConnectionState, to ConnectionState) {
+		transitions = append(transitions, [2]ConnectionState{from, to})
+	})
+	defer func() {
+		connectionStateHooks = nil
+	}()
+	readyNum := func() float64 {
+		return testutil.ToFloat64(connectionStateNum.WithLabelValues(channeldpb.ConnectionType_SERVER.String(), ConnectionState_READY.String()))
+	}
+	initialReadyNum := readyNum()
+
+	c := addTestConnection(channeldpb.ConnectionType_SERVER)
+	assert.Equal(t, ConnectionState_CONNECTING, c.State())
+	assert.False(t, c.transitionTo(ConnectionState_CLOSED))
+
+	assert.True(t, c.transitionTo(ConnectionState_AUTHENTICATING))
+	// The auth failed
+	assert.True(t, c.transitionTo(ConnectionState_CONNECTING))
+	assert.True(t, c.transitionTo(ConnectionState_AUTHENTICATING))
+	c.OnAuthenticated("")
+	assert.Equal(t, ConnectionState_READY, c.State())
+	assert.Equal(t, initialReadyNum+1, readyNum())
+	assert.False(t, c.transitionTo(ConnectionState_CONNECTING))
+
+	c.Close()
+	assert.Equal(t, ConnectionState_CLOSED, c.State())
+	assert.True(t, c.IsClosing())
+	assert.Equal(t, initialReadyNum, readyNum())
+	assert.Equal(t, [][2]ConnectionState{
+		{ConnectionState_CONNECTING, ConnectionState_AUTHENTICATING},
+		{ConnectionState_AUTHENTICATING, ConnectionState_CONNECTING},
+		{ConnectionState_CONNECTING, ConnectionState_AUTHENTICATING},
+		{ConnectionState_AUTHENTICATING, ConnectionState_READY},
+		{ConnectionState_READY, ConnectionState_DRAINING},
+		{ConnectionState_DRAINING, ConnectionState_CLOSED},
+	}, transitions)
+}
+
+func TestNoSubscriptionBeforeAuth(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		ACLSettings: ACLSettingsType{Sub: ChannelAccessLevel_Any},
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	// The user-defined FSM allows any message before the auth
+	permissiveFsm, err := fsm.Load([]byte(`{
+		"States": [
+			{"Name": "INIT", "MsgTypeWhitelist": "1-65535"},
+			{"Name": "OPEN", "MsgTypeWhitelist": "1-65535"}
+		],
+		"InitState": "INIT",
+		"Transitions": [{"FromState": "INIT", "ToState": "OPEN", "MsgType": 1}]
+	}`))
+	assert.NoError(t, err)
+	client.fsm = permissiveFsm
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+
+	msgBody, _ := proto.Marshal(&channeldpb.SubscribedToChannelMessage{ConnId: uint32(client.Id())})
+	subMsgPack := &channeldpb.MessagePack{
+		ChannelId: uint32(ch.Id()),
+		MsgType:   uint32(channeldpb.MessageType_SUB_TO_CHANNEL),
+		MsgBody:   msgBody,
+	}
+	client.receiveMessage(subMsgPack)
+	time.Sleep(50 * time.Millisecond)
+	_, subscribed := ch.GetAllConnections()[client]
+	assert.False(t, subscribed)
+
+	client.OnAuthenticated("")
+	client.receiveMessage(subMsgPack)
+	assert.Eventually(t, func() bool {
+		_, exists := ch.GetAllConnections()[client]
+		return exists
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,94 @@
+package channeld
+
+import (
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+)
+
+// Optional interface of the AuthProvider. If implemented, the returned tags are attached to the connection
+// once it's successfully authenticated, e.g. platform, region, and build version.
+type TaggingAuthProvider interface {
+	GetTags(connId ConnectionId, pit string, lt string) map[string]string
+}
+
+// Goroutine-safe
+func (c *Connection) SetTag(key string, value string) {
+	c.tags.Store(key, value)
+}
+
+// Goroutine-safe
+func (c *Connection) GetTag(key string) (string, bool) {
+	if c.tags == nil {
+		return "", false
+	}
+	return c.tags.Load(key)
+}
+
+// Goroutine-safe
+func (c *Connection) RemoveTag(key string) {
+	c.tags.Delete(key)
+}
+
+// Returns a copy of all the tags of the connection.
+func (c *Connection) GetTags() map[string]string {
+	tags := make(map[string]string)
+	if c.tags == nil {
+		return tags
+	}
+	c.tags.Range(func(key string, value string) bool {
+		tags[key] = value
+		return true
+	})
+	return tags
+}
+
+// Returns true if the connection has all the tags with the same values.
+func MatchTags(c ConnectionInChannel, requiredTags map[string]string) bool {
+	for key, value := range requiredTags {
+		if v, exists := c.GetTag(key); !exists || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+func handleSetConnectionTags(ctx MessageContext) {
+	if ctx.Channel != globalChannel {
+		ctx.Connection.Logger().Error("illegal attemp to set connection tags outside the GLOBAL channel")
+		return
+	}
+
+	if ctx.Connection.GetConnectionType() != channeldpb.ConnectionType_SERVER {
+		ctx.Connection.Logger().Error("illegal attemp to set connection tags from client connection")
+		return
+	}
+
+	msg, ok := ctx.Msg.(*channeldpb.SetConnectionTagsMessage)
+	if !ok {
+		ctx.Connection.Logger().Error("message is not a SetConnectionTagsMessage, will not be handled.")
+		return
+	}
+
+	conn := GetConnectionOfConnection(ConnectionId(msg.ConnId), ctx.Connection)
+	if conn == nil {
+		ctx.Connection.Logger().Warn("could not find the connection to set tags", zap.Uint32("targetConnId", msg.ConnId))
+		return
+	}
+
+	for key, value := range msg.Tags {
+		if key == TenantTag {
+			ctx.Connection.Logger().Warn("illegal attemp to change the tenant of connection", zap.Uint32("targetConnId", msg.ConnId))
+			continue
+		}
+		conn.SetTag(key, value)
+	}
+	for _, key := range msg.TagsToRemove {
+		if key == TenantTag {
+			ctx.Connection.Logger().Warn("illegal attemp to change the tenant of connection", zap.Uint32("targetConnId", msg.ConnId))
+			continue
+		}
+		conn.RemoveTag(key)
+	}
+
+	conn.Logger().Debug("updated connection tags", zap.Any("tags", conn.GetTags()))
+}
@@ -0,0 +1,75 @@
+package channeld
+
+import (
+	"testing"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetConnectionTags(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+
+	handleSetConnectionTags(MessageContext{
+		MsgType:    channeldpb.MessageType_SET_CONNECTION_TAGS,
+		Msg:        &channeldpb.SetConnectionTagsMessage{ConnId: uint32(client.Id()), Tags: map[string]string{"platform": "ios", "region": "eu"}},
+		Connection: server,
+		Channel:    globalChannel,
+	})
+	platform, exists := client.GetTag("platform")
+	assert.True(t, exists)
+	assert.Equal(t, "ios", platform)
+	assert.True(t, MatchTags(client, map[string]string{"platform": "ios"}))
+	assert.False(t, MatchTags(client, map[string]string{"platform": "android"}))
+
+	handleSetConnectionTags(MessageContext{
+		MsgType:    channeldpb.MessageType_SET_CONNECTION_TAGS,
+		Msg:        &channeldpb.SetConnectionTagsMessage{ConnId: uint32(client.Id()), TagsToRemove: []string{"region"}},
+		Connection: server,
+		Channel:    globalChannel,
+	})
+	assert.Equal(t, map[string]string{"platform": "ios"}, client.GetTags())
+
+	// Clients can't set the tags
+	handleSetConnectionTags(MessageContext{
+		MsgType:    channeldpb.MessageType_SET_CONNECTION_TAGS,
+		Msg:        &channeldpb.SetConnectionTagsMessage{ConnId: uint32(client.Id()), Tags: map[string]string{"platform": "android"}},
+		Connection: client,
+		Channel:    globalChannel,
+	})
+	platform, _ = client.GetTag("platform")
+	assert.Equal(t, "ios", platform)
+}
+
+func TestSubRequiredTags(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		ACLSettings: ACLSettingsType{Sub: ChannelAccessLevel_Any, SubRequiredTags: map[string]string{"team": "red"}},
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+
+	subCtx := MessageContext{
+		MsgType:    channeldpb.MessageType_SUB_TO_CHANNEL,
+		Msg:        &channeldpb.SubscribedToChannelMessage{ConnId: uint32(client.Id())},
+		Connection: client,
+		Channel:    ch,
+	}
+	handleSubToChannel(subCtx)
+	assert.NotContains(t, ch.GetAllConnections(), client)
+
+	client.SetTag("team", "red")
+	handleSubToChannel(subCtx)
+	assert.Contains(t, ch.GetAllConnections(), client)
+}
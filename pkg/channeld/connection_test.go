@@ -146,6 +146,7 @@ func TestWebSocketConnection(t *testing.T) {
 	go func() {
 		StartListening(channeldpb.ConnectionType_CLIENT, "ws", addr)
 	}()
+	time.Sleep(time.Millisecond * 100)
 	_, _, err := websocket.DefaultDialer.Dial(addr, nil)
 	assert.NoError(t, err)
 
@@ -161,7 +162,12 @@ func TestConcurrentAccessConnections(t *testing.T) {
 	wg.Add(1)
 	go func() {
 		for i := 0; i < 100; i++ {
-			AddConnection(nil, channeldpb.ConnectionType_CLIENT)
+			c := AddConnection(nil, channeldpb.ConnectionType_CLIENT)
+			// The connections without the net.Conn would crash the check of the unauthenticated connections
+			t.Cleanup(func() {
+				allConnections.Delete(c.id)
+				unauthenticatedConnections.Delete(c.id)
+			})
 			time.Sleep(1 * time.Millisecond)
 		}
 		wg.Done()
@@ -186,3 +192,38 @@ func TestConcurrentAccessConnections(t *testing.T) {
 	wg.Wait()
 
 }
+
+func TestSendQueuePriority(t *testing.T) {
+	c := &Connection{
+		sender:     &queuedMessagePackSender{},
+		sendQueues: newSendQueues(),
+		logger:     rootLogger,
+	}
+
+	c.Send(MessageContext{MsgType: channeldpb.MessageType_CHANNEL_DATA_UPDATE, Msg: &channeldpb.ChannelDataUpdateMessage{}})
+	c.Send(MessageContext{MsgType: channeldpb.MessageType_USER_SPACE_START, Msg: &channeldpb.ServerForwardMessage{}})
+	c.Send(MessageContext{MsgType: channeldpb.MessageType_CHANNEL_DATA_UPDATE, Msg: &channeldpb.ChannelDataUpdateMessage{}})
+	c.Send(MessageContext{MsgType: channeldpb.MessageType_AUTH, Msg: &channeldpb.AuthResultMessage{}})
+	c.Send(MessageContext{MsgType: channeldpb.MessageType_UNSUB_FROM_CHANNEL, Msg: &channeldpb.UnsubscribedFromChannelResultMessage{}})
+	assert.Equal(t, 5, c.sendQueueLen())
+
+	expected := []channeldpb.MessageType{
+		channeldpb.MessageType_AUTH,
+		channeldpb.MessageType_UNSUB_FROM_CHANNEL,
+		channeldpb.MessageType_USER_SPACE_START,
+		channeldpb.MessageType_CHANNEL_DATA_UPDATE,
+		channeldpb.MessageType_CHANNEL_DATA_UPDATE,
+	}
+	for _, msgType := range expected {
+		mp := c.nextMessageToSend()
+		assert.NotNil(t, mp)
+		assert.EqualValues(t, msgType, mp.MsgType)
+	}
+	assert.Nil(t, c.nextMessageToSend())
+
+	// The held message always goes first
+	c.Send(MessageContext{MsgType: channeldpb.MessageType_AUTH, Msg: &channeldpb.AuthResultMessage{}})
+	c.pendingSend = &channeldpb.MessagePack{MsgType: uint32(channeldpb.MessageType_CHANNEL_DATA_UPDATE)}
+	assert.EqualValues(t, channeldpb.MessageType_CHANNEL_DATA_UPDATE, c.nextMessageToSend().MsgType)
+	assert.EqualValues(t, channeldpb.MessageType_AUTH, c.nextMessageToSend().MsgType)
+}
@@ -0,0 +1,207 @@
+package channeld
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultTraceDuration = time.Minute
+	MaxTraceDuration     = 10 * time.Minute
+	// The traced messages are dropped if the admin API can't stream them fast enough
+	traceStreamBufferSize = 256
+)
+
+type MessageTraceDirection string
+
+const (
+	// Handled by channeld
+	MessageTraceDirection_In MessageTraceDirection = "in"
+	// Flushed to the connection
+	MessageTraceDirection_Out MessageTraceDirection = "out"
+)
+
+type MessageTrace struct {
+	Time      time.Time
+	ConnId    uint32
+	Direction MessageTraceDirection
+	ChannelId uint32
+	MsgType   uint32
+	Size      int
+	// How long the handler took. Zero for the outgoing messages.
+	LatencyUs int64 `json:",omitempty"`
+}
+
+// The trace of all the messages in and out of the connection, until the deadline. See Connection.StartTrace().
+type connectionTrace struct {
+	deadline time.Time
+	// The traced messages are logged if there's no stream.
+	stream chan *MessageTrace
+	// Closed when the trace is stopped or replaced.
+	done chan struct{}
+}
+
+// Starts tracing the messages in and out of the connection for the duration, replacing the existing trace.
+// If the stream is nil, the traced messages are logged. Otherwise, they are sent to the stream, and dropped when it's full.
+// Returns the channel that is closed when the trace is stopped or replaced.
+func (c *Connection) StartTrace(duration time.Duration, stream chan *MessageTrace) <-chan struct{} {
+	return c.startTrace(duration, stream).done
+}
+
+func (c *Connection) StopTrace() {
+	if c.swapTrace(nil) != nil {
+		c.Logger().Info("stopped tracing messages")
+	}
+}
+
+func (c *Connection) startTrace(duration time.Duration, stream chan *MessageTrace) *connectionTrace {
+	trace := &connectionTrace{
+		deadline: time.Now().Add(duration),
+		stream:   stream,
+		done:     make(chan struct{}),
+	}
+	c.swapTrace(trace)
+	c.Logger().Info("started tracing messages", zap.Duration("duration", duration), zap.Bool("stream", stream != nil))
+	return trace
+}
+
+// Stops the trace if it's not replaced yet.
+func (c *Connection) stopTrace(trace *connectionTrace) bool {
+	if c.trace.CompareAndSwap(trace, (*connectionTrace)(nil)) {
+		close(trace.done)
+		return true
+	}
+	return false
+}
+
+func (c *Connection) swapTrace(trace *connectionTrace) *connectionTrace {
+	prev, _ := c.trace.Swap(trace).(*connectionTrace)
+	if prev != nil {
+		close(prev.done)
+	}
+	return prev
+}
+
+func (c *Connection) isTracing() bool {
+	trace, _ := c.trace.Load().(*connectionTrace)
+	return trace != nil
+}
+
+func (c *Connection) traceMessage(direction MessageTraceDirection, channelId uint32, msgType uint32, size int, latency time.Duration) {
+	trace, _ := c.trace.Load().(*connectionTrace)
+	if trace == nil {
+		return
+	}
+	now := time.Now()
+	if now.After(trace.deadline) {
+		if c.stopTrace(trace) {
+			c.Logger().Info("trace expired")
+		}
+		return
+	}
+
+	if trace.stream == nil {
+		c.Logger().Info("traced message",
+			zap.String("direction", string(direction)),
+			zap.Uint32("channelId", channelId),
+			zap.Uint32("msgType", msgType),
+			zap.Int("size", size),
+			zap.Duration("latency", latency),
+		)
+		return
+	}
+	select {
+	case trace.stream <- &MessageTrace{
+		Time:      now,
+		ConnId:    uint32(c.id),
+		Direction: direction,
+		ChannelId: channelId,
+		MsgType:   msgType,
+		Size:      size,
+		LatencyUs: latency.Microseconds(),
+	}:
+	default:
+	}
+}
+
+// The admin API that traces the messages in and out of a connection, without enabling the debug logs globally, e.g.
+//
+//	POST /debug/trace?connId=1&durationMs=30000            logs the traced messages of connection 1 for 30 seconds
+//	POST /debug/trace?connId=1&durationMs=30000&stream=1   streams the traced messages as newline-delimited JSON instead
+//	POST /debug/trace?connId=1&stop=1                      stops the trace
+//
+// The duration defaults to DefaultTraceDuration and is capped by MaxTraceDuration. As the traces expose the traffic of
+// the connection, starting or stopping one requires GlobalSettings.AdminAuthToken to be set.
+func HandleConnectionTrace(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !requireAdminToken(w, r) {
+		return
+	}
+
+	query := r.URL.Query()
+	connId, err := strconv.ParseUint(query.Get("connId"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid connId", http.StatusBadRequest)
+		return
+	}
+	conn := GetConnection(ConnectionId(connId))
+	if conn == nil {
+		http.Error(w, "connection not found", http.StatusNotFound)
+		return
+	}
+
+	if query.Get("stop") != "" {
+		conn.StopTrace()
+		return
+	}
+
+	duration := DefaultTraceDuration
+	if ms, err := strconv.Atoi(query.Get("durationMs")); err == nil && ms > 0 {
+		duration = time.Duration(ms) * time.Millisecond
+	}
+	if duration > MaxTraceDuration {
+		duration = MaxTraceDuration
+	}
+
+	if query.Get("stream") == "" {
+		conn.StartTrace(duration, nil)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	stream := make(chan *MessageTrace, traceStreamBufferSize)
+	trace := conn.startTrace(duration, stream)
+	defer conn.stopTrace(trace)
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	encoder := json.NewEncoder(w)
+	for {
+		select {
+		case msgTrace := <-stream:
+			if err := encoder.Encode(msgTrace); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-timer.C:
+			return
+		case <-r.Context().Done():
+			return
+		case <-trace.done:
+			// Stopped or replaced by another request
+			return
+		}
+	}
+}
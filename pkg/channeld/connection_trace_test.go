@@ -0,0 +1,107 @@
+package channeld
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectionTrace(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	stream := make(chan *MessageTrace, 10)
+	done := client.StartTrace(time.Minute, stream)
+
+	globalChannel.handleMessage(channelMessage{
+		ctx: MessageContext{
+			MsgType:    channeldpb.MessageType_LIST_CHANNEL,
+			Msg:        &channeldpb.ListChannelMessage{},
+			Connection: client,
+			Channel:    globalChannel,
+			ChannelId:  uint32(GlobalChannelId),
+		},
+		handler: func(ctx MessageContext) {},
+	}, "")
+	if assert.Len(t, stream, 1) {
+		msgTrace := <-stream
+		assert.Equal(t, MessageTraceDirection_In, msgTrace.Direction)
+		assert.EqualValues(t, client.Id(), msgTrace.ConnId)
+		assert.EqualValues(t, channeldpb.MessageType_LIST_CHANNEL, msgTrace.MsgType)
+	}
+
+	// Replaced by another trace
+	stream2 := make(chan *MessageTrace, 10)
+	client.StartTrace(-time.Second, stream2)
+	select {
+	case <-done:
+	default:
+		t.Error("the replaced trace is not done")
+	}
+
+	// Expired
+	client.traceMessage(MessageTraceDirection_Out, 0, uint32(channeldpb.MessageType_LIST_CHANNEL), 10, 0)
+	assert.Len(t, stream2, 0)
+	assert.False(t, client.isTracing())
+}
+
+func TestHandleConnectionTrace(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	defer func(token string) {
+		GlobalSettings.AdminAuthToken = token
+	}(GlobalSettings.AdminAuthToken)
+
+	request := func(method string, query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		HandleConnectionTrace(rec, httptest.NewRequest(method, "/debug/trace?"+query, nil))
+		return rec
+	}
+	serve := func(query string) *httptest.ResponseRecorder {
+		return request("POST", query)
+	}
+
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+
+	// Can't start a trace without the auth token
+	GlobalSettings.AdminAuthToken = ""
+	assert.Equal(t, http.StatusForbidden, serve(fmt.Sprintf("connId=%d", client.Id())).Code)
+	assert.False(t, client.isTracing())
+
+	GlobalSettings.AdminAuthToken = "secret"
+	assert.Equal(t, http.StatusMethodNotAllowed, request("GET", fmt.Sprintf("connId=%d", client.Id())).Code)
+	assert.False(t, client.isTracing())
+	assert.Equal(t, http.StatusBadRequest, serve("connId=abc").Code)
+	assert.Equal(t, http.StatusNotFound, serve("connId=99999").Code)
+
+	assert.Equal(t, http.StatusOK, serve(fmt.Sprintf("connId=%d", client.Id())).Code)
+	assert.True(t, client.isTracing())
+	serve(fmt.Sprintf("connId=%d&stop=1", client.Id()))
+	assert.False(t, client.isTracing())
+
+	result := make(chan *httptest.ResponseRecorder)
+	go func() {
+		result <- serve(fmt.Sprintf("connId=%d&durationMs=200&stream=1", client.Id()))
+	}()
+	assert.Eventually(t, client.isTracing, time.Second, 10*time.Millisecond)
+	client.traceMessage(MessageTraceDirection_Out, 1, uint32(channeldpb.MessageType_CHANNEL_DATA_UPDATE), 10, 0)
+
+	rec := <-result
+	assert.False(t, client.isTracing())
+	var msgTrace MessageTrace
+	if assert.NoError(t, json.NewDecoder(rec.Body).Decode(&msgTrace)) {
+		assert.Equal(t, MessageTraceDirection_Out, msgTrace.Direction)
+		assert.EqualValues(t, 1, msgTrace.ChannelId)
+		assert.Equal(t, 10, msgTrace.Size)
+	}
+}
@@ -69,7 +69,7 @@ var upgrader websocket.Upgrader = websocket.Upgrader{
 	},
 }
 
-func startWebSocketServer(t channeldpb.ConnectionType, address string) {
+func startWebSocketServer(t channeldpb.ConnectionType, network string, address string) {
 	if protocolIndex := strings.Index(address, "://"); protocolIndex >= 0 {
 		address = address[protocolIndex+3:]
 	}
@@ -95,6 +95,8 @@ func startWebSocketServer(t channeldpb.ConnectionType, address string) {
 	serverClosed := false
 	// Call AddConnection() in a separate goroutine, to avoid the race condition.
 	go func() {
+		// The upgraded connections are held in the queue
+		waitForWarmUp(t)
 		for !serverClosed {
 			conn := <-connsToAdd
 			c := AddConnection(&wsConn{conn}, t)
@@ -109,6 +111,11 @@ func startWebSocketServer(t channeldpb.ConnectionType, address string) {
 
 	defer server.Close()
 
-	rootLogger.Error("stopped listening", zap.Error(server.ListenAndServe()))
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		rootLogger.Panic("failed to listen", zap.Error(err))
+		return
+	}
+	rootLogger.Error("stopped listening", zap.Error(server.Serve(listener)))
 	serverClosed = true
 }
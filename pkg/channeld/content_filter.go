@@ -0,0 +1,130 @@
+package channeld
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+)
+
+// Checks the payloads of the user-space broadcasts in the channels that have ChannelSettingsType.FilterContent enabled,
+// e.g. for the profanity in the chat. Called in the channel's goroutine, before the broadcast is retained, archived, or sent.
+type ContentFilter interface {
+	// Returns the payload to broadcast, which can be rewritten, or false to reject the message.
+	// The payload is in the user-space format, e.g. a marshalled Protobuf message.
+	FilterContent(ch *Channel, sender ConnectionInChannel, msgType channeldpb.MessageType, payload []byte) ([]byte, bool)
+}
+
+var contentFilter ContentFilter
+
+func SetContentFilter(value ContentFilter) {
+	contentFilter = value
+}
+
+// Returns false if the broadcast is rejected. Should be called in the channel's goroutine.
+func (ch *Channel) filterContent(ctx MessageContext, msg *channeldpb.ServerForwardMessage) bool {
+	if contentFilter == nil || !ch.settings().FilterContent {
+		return true
+	}
+	payload, ok := contentFilter.FilterContent(ch, ctx.Connection, ctx.MsgType, msg.Payload)
+	if !ok {
+		contentFiltered.WithLabelValues(ch.channelType.String(), "rejected").Inc()
+		ctx.Connection.Logger().Debug("the broadcast is rejected by the content filter",
+			zap.Uint32("channelId", uint32(ch.id)),
+			zap.Uint32("msgType", uint32(ctx.MsgType)),
+		)
+		return false
+	}
+	if !bytes.Equal(payload, msg.Payload) {
+		contentFiltered.WithLabelValues(ch.channelType.String(), "rewritten").Inc()
+	}
+	msg.Payload = payload
+	return true
+}
+
+// How RegexContentFilter handles the payload that matches the patterns.
+type ContentFilterMode uint8
+
+const (
+	// Replaces every byte of the matched content with '*'. As the length of the payload doesn't change,
+	// the strings in a marshalled Protobuf message can be masked without breaking the message.
+	ContentFilterMode_Mask ContentFilterMode = 0
+	// Rejects the message.
+	ContentFilterMode_Reject ContentFilterMode = 1
+)
+
+// The reference implementation of ContentFilter that matches the payload with the regular expressions.
+type RegexContentFilter struct {
+	pattern *regexp.Regexp
+	mode    ContentFilterMode
+}
+
+// The patterns are combined into one regular expression. See https://github.com/google/re2/wiki/Syntax.
+func NewRegexContentFilter(patterns []string, mode ContentFilterMode) (*RegexContentFilter, error) {
+	pattern, err := regexp.Compile(strings.Join(patterns, "|"))
+	if err != nil {
+		return nil, err
+	}
+	return &RegexContentFilter{pattern: pattern, mode: mode}, nil
+}
+
+// Matches the whole words, case-insensitively.
+func NewWordListContentFilter(words []string, mode ContentFilterMode) (*RegexContentFilter, error) {
+	quoted := make([]string, 0, len(words))
+	for _, word := range words {
+		if word = strings.TrimSpace(word); word != "" {
+			quoted = append(quoted, regexp.QuoteMeta(word))
+		}
+	}
+	if len(quoted) == 0 {
+		return &RegexContentFilter{mode: mode}, nil
+	}
+	return NewRegexContentFilter([]string{`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`}, mode)
+}
+
+// Loads the words from the file, one word per line. The empty lines and the lines start with '#' are ignored.
+func LoadWordListContentFilter(path string, mode ContentFilterMode) (*RegexContentFilter, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			words = append(words, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewWordListContentFilter(words, mode)
+}
+
+func (f *RegexContentFilter) FilterContent(ch *Channel, sender ConnectionInChannel, msgType channeldpb.MessageType, payload []byte) ([]byte, bool) {
+	if f.pattern == nil {
+		return payload, true
+	}
+	matches := f.pattern.FindAllIndex(payload, -1)
+	if len(matches) == 0 {
+		return payload, true
+	}
+	if f.mode == ContentFilterMode_Reject {
+		return nil, false
+	}
+	// Don't modify the original payload, as it may be shared with the other messages
+	masked := make([]byte, len(payload))
+	copy(masked, payload)
+	for _, match := range matches {
+		for i := match[0]; i < match[1]; i++ {
+			masked[i] = '*'
+		}
+	}
+	return masked, true
+}
@@ -0,0 +1,106 @@
+package channeld
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestWordListContentFilter(t *testing.T) {
+	f, err := NewWordListContentFilter([]string{"darn", " heck ", "", "a.b"}, ContentFilterMode_Mask)
+	assert.NoError(t, err)
+
+	payload, ok := f.FilterContent(nil, nil, channeldpb.MessageType_USER_SPACE_START, []byte("Darn it, what the heck"))
+	assert.True(t, ok)
+	assert.Equal(t, "**** it, what the ****", string(payload))
+	// Only the whole words are matched, and the words are quoted
+	payload, _ = f.FilterContent(nil, nil, channeldpb.MessageType_USER_SPACE_START, []byte("darnation axb"))
+	assert.Equal(t, "darnation axb", string(payload))
+
+	// The masked Protobuf message is still valid
+	msgBody, _ := proto.Marshal(&testpb.TestChannelDataMessage{Text: "oh heck", Num: 1})
+	payload, ok = f.FilterContent(nil, nil, channeldpb.MessageType_USER_SPACE_START, msgBody)
+	assert.True(t, ok)
+	var msg testpb.TestChannelDataMessage
+	assert.NoError(t, proto.Unmarshal(payload, &msg))
+	assert.Equal(t, "oh ****", msg.Text)
+	assert.EqualValues(t, 1, msg.Num)
+
+	f, _ = NewWordListContentFilter([]string{"darn"}, ContentFilterMode_Reject)
+	_, ok = f.FilterContent(nil, nil, channeldpb.MessageType_USER_SPACE_START, []byte("darn"))
+	assert.False(t, ok)
+
+	// The empty list matches nothing
+	f, _ = NewWordListContentFilter(nil, ContentFilterMode_Reject)
+	_, ok = f.FilterContent(nil, nil, channeldpb.MessageType_USER_SPACE_START, []byte("darn"))
+	assert.True(t, ok)
+
+	path := filepath.Join(t.TempDir(), "words.txt")
+	os.WriteFile(path, []byte("# comment\ndarn\n\nheck\n"), 0644)
+	f, err = LoadWordListContentFilter(path, ContentFilterMode_Mask)
+	assert.NoError(t, err)
+	payload, _ = f.FilterContent(nil, nil, channeldpb.MessageType_USER_SPACE_START, []byte("comment darn heck"))
+	assert.Equal(t, "comment **** ****", string(payload))
+}
+
+func TestFilterBroadcastContent(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	SetContentFilter(&testContentFilter{})
+	defer SetContentFilter(nil)
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{FilterContent: true, BroadcastRetentionSize: 10}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	client.SubscribeToChannel(ch, nil)
+
+	broadcast := func(payload string) {
+		HandleServerToClientUserMessage(MessageContext{
+			MsgType:    channeldpb.MessageType_USER_SPACE_START,
+			Msg:        &channeldpb.ServerForwardMessage{Payload: []byte(payload)},
+			Broadcast:  uint32(channeldpb.BroadcastType_ALL),
+			Connection: server,
+			Channel:    ch,
+		})
+	}
+
+	broadcast("not bad")
+	assert.Equal(t, "not ***", string(client.latestMsg().(*channeldpb.ServerForwardMessage).Payload))
+
+	// The rejected message is not broadcasted or retained
+	broadcast("reject me")
+	assert.Len(t, client.testQueue(), 1)
+	assert.EqualValues(t, 1, ch.broadcastSeq)
+
+	// The channel types without FilterContent are not affected
+	otherChannel, _ := CreateChannel(channeldpb.ChannelType_SUBWORLD, server)
+	client.SubscribeToChannel(otherChannel, nil)
+	HandleServerToClientUserMessage(MessageContext{
+		MsgType:    channeldpb.MessageType_USER_SPACE_START,
+		Msg:        &channeldpb.ServerForwardMessage{Payload: []byte("bad")},
+		Broadcast:  uint32(channeldpb.BroadcastType_ALL),
+		Connection: server,
+		Channel:    otherChannel,
+	})
+	assert.Equal(t, "bad", string(client.latestMsg().(*channeldpb.ServerForwardMessage).Payload))
+}
+
+// Masks "bad" and rejects the payload that starts with "reject"
+type testContentFilter struct{}
+
+func (f *testContentFilter) FilterContent(ch *Channel, sender ConnectionInChannel, msgType channeldpb.MessageType, payload []byte) ([]byte, bool) {
+	if bytes.HasPrefix(payload, []byte("reject")) {
+		return nil, false
+	}
+	return bytes.ReplaceAll(payload, []byte("bad"), []byte("***")), true
+}
@@ -3,6 +3,8 @@ package channeld
 import (
 	"container/list"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/indiest/fmutils"
 	"github.com/metaworking/channeld/pkg/channeldpb"
@@ -22,6 +24,49 @@ type ChannelData struct {
 	updateMsgBuffer      *list.List
 	maxFanOutIntervalMs  uint32
 	msgIndex             uint64
+	// See ChannelSettingsType.MaxUpdateMsgBufferSize
+	maxUpdateMsgBufferSize int
+	// See ChannelSettingsType.VersionedFields
+	versionedFields []string
+	// The last change of each versioned field or map entry. See recordFieldVersions().
+	fieldVersions map[string]fieldVersion
+	// The locked map entries. See handleChannelDataLock().
+	locks map[string]*dataLock
+	// The last write of each field and map entry, in the CRDT mode. See crdtMerge().
+	crdtStamps map[string]crdtStamp
+	// See ChannelSettingsType.ListHistorySize
+	historySize int
+	// The truncated entries of the list fields, by the field name. See retainTruncatedHistory().
+	histories map[string]*listHistory
+	// See fanOutMergeOptions()
+	fanOutOptions *channeldpb.ChannelDataMergeOptions
+	// The removed map entries that some subscribers haven't received, by the entry key. See recordTombstones().
+	tombstones map[string]*mapTombstone
+	// See ChannelSettingsType.ChecksumIntervalMs
+	checksumIntervalMs uint32
+	// See ChannelSettingsType.SlowSubscriberTicks
+	slowSubscriberTicks               int
+	slowSubscriberMaxFanOutIntervalMs uint32
+	// The last good channel data, for restoring after a panic. See takeSnapshot().
+	snapshot           common.ChannelDataMessage
+	snapshotIndex      uint64
+	snapshotTime       ChannelTime
+	snapshotIntervalMs uint32
+	// See ChannelSettingsType.Leaderboard
+	leaderboard *leaderboard
+	// The updates to merge at the end of the tick. See ChannelSettingsType.DeterministicMerge.
+	deterministicMerge bool
+	pendingUpdates     []pendingDataUpdate
+	pendingUpdateSeq   uint64
+	// See ChannelSettingsType.EntityMapField and FanOutBudgetBytes
+	entityMapField    string
+	fanOutBudgetBytes uint32
+	// The last input sequence of each client incorporated in the channel data. See recordInputSeq().
+	inputSeqs map[ConnectionId]uint64
+	// See ChannelSettingsType.DeadReckoning
+	deadReckoning *deadReckoning
+	// See ChannelSettingsType.ShadowMerge
+	shadow *shadowData
 }
 
 // Indicate that the channel data message should be initialized with default values.
@@ -39,6 +84,25 @@ type fanOutConnection struct {
 	hadFirstFanOut   bool
 	lastFanOutTime   ChannelTime
 	lastMessageIndex uint64
+	// The last time the fan-out carried the checksum. See ChannelSettingsType.ChecksumIntervalMs.
+	lastChecksumTime ChannelTime
+	// How many ticks in a row the subscriber's send queue has been full. See checkSlowSubscriber().
+	slowTicks     int
+	slowCheckTime ChannelTime
+	// If true, the fan-out always carries the whole channel data.
+	keyframeOnly bool
+	// The time and the version of the last fan-out that carried the whole channel data. See isKeyframeDue().
+	lastKeyframeTime ChannelTime
+	keyframeVersion  uint64
+	// The entities left out of the fan-outs for exceeding the budget, by the entity id. See applyFanOutBudget().
+	deferredEntities map[EntityId]*deferredEntity
+	// The current fan-out interval of the adaptive subscription. See adaptFanOutInterval().
+	adaptiveIntervalMs uint32
+	// See Channel.PauseDataFanOut()
+	paused bool
+	// The digest of the channel data in the last fan-out. See isDuplicateFanOut().
+	lastFanOutDigest uint64
+	hasFanOutDigest  bool
 }
 
 type updateMsgBufferElement struct {
@@ -46,6 +110,8 @@ type updateMsgBufferElement struct {
 	arrivalTime  ChannelTime
 	senderConnId ConnectionId
 	messageIndex uint64
+	// The messageIndex of the first update coalesced into this element. Same as messageIndex if not coalesced.
+	firstMessageIndex uint64
 }
 
 const (
@@ -101,9 +167,31 @@ func ReflectChannelDataMessage(channelType channeldpb.ChannelType) (common.Chann
 
 func (ch *Channel) InitData(dataMsg common.ChannelDataMessage, mergeOptions *channeldpb.ChannelDataMergeOptions) {
 	ch.data = &ChannelData{
-		msg:             dataMsg,
-		updateMsgBuffer: list.New(),
-		mergeOptions:    mergeOptions,
+		msg:                    dataMsg,
+		updateMsgBuffer:        list.New(),
+		mergeOptions:           mergeOptions,
+		maxUpdateMsgBufferSize: ch.settings().MaxUpdateMsgBufferSize,
+		versionedFields:        ch.settings().VersionedFields,
+		historySize:            ch.settings().ListHistorySize,
+		checksumIntervalMs:     ch.settings().ChecksumIntervalMs,
+		snapshotIntervalMs:     ch.settings().DataSnapshotIntervalMs,
+		slowSubscriberTicks:    ch.settings().SlowSubscriberTicks,
+	}
+	ch.data.slowSubscriberMaxFanOutIntervalMs = ch.settings().SlowSubscriberMaxFanOutIntervalMs
+	if ch.data.slowSubscriberMaxFanOutIntervalMs == 0 {
+		ch.data.slowSubscriberMaxFanOutIntervalMs = DefaultSlowSubscriberMaxFanOutIntervalMs
+	}
+	if ch.settings().Leaderboard.MapField != "" {
+		ch.data.leaderboard = &leaderboard{settings: ch.settings().Leaderboard}
+	}
+	ch.data.entityMapField = ch.settings().EntityMapField
+	ch.data.deterministicMerge = ch.settings().DeterministicMerge
+	ch.data.fanOutBudgetBytes = ch.settings().FanOutBudgetBytes
+	if drSettings := ch.settings().DeadReckoning; drSettings.Enabled() {
+		ch.data.deadReckoning = &deadReckoning{settings: drSettings, entityMapField: ch.settings().EntityMapField}
+	}
+	if ch.settings().ShadowMerge.Enabled {
+		ch.data.shadow = &shadowData{settings: ch.settings().ShadowMerge, channelType: ch.channelType, merge: shadowMergeFuncs[ch.channelType]}
 	}
 
 	if dataMsg == nil {
@@ -142,36 +230,104 @@ func (ch *Channel) SetDataUpdateConnId(connId ConnectionId) {
 }
 
 func (d *ChannelData) OnUpdate(updateMsg common.ChannelDataMessage, t ChannelTime, senderConnId ConnectionId, spatialNotifier common.SpatialInfoChangedNotifier) {
-	if d.msg == nil {
+	d.queueUpdate(senderConnId, func() {
+		d.onUpdate(updateMsg, t, senderConnId, spatialNotifier, 0)
+	})
+}
+
+// The crdtTimestamp is only used in the CRDT mode. See channeldpb.ChannelDataUpdateMessage.CrdtTimestamp.
+func (d *ChannelData) onUpdate(updateMsg common.ChannelDataMessage, t ChannelTime, senderConnId ConnectionId, spatialNotifier common.SpatialInfoChangedNotifier, crdtTimestamp int64) {
+	if d.isCrdt() {
+		if d.msg == nil {
+			d.msg = updateMsg.ProtoReflect().New().Interface()
+		}
+		d.dropLockedEntries(updateMsg, t, senderConnId)
+		d.crdtMerge(updateMsg, newCrdtStamp(crdtTimestamp, senderConnId))
+	} else if d.msg == nil {
 		d.msg = updateMsg
 		rootLogger.Info("initialized channel data with update message",
 			zap.Uint32("senderConnId", uint32(senderConnId)),
 			zap.String("msgName", string(updateMsg.ProtoReflect().Descriptor().FullName())),
 		)
 	} else {
+		d.dropLockedEntries(updateMsg, t, senderConnId)
+		d.retainTruncatedHistory(updateMsg)
+		d.mergeShadow(updateMsg)
 		mergeWithOptions(d.msg, updateMsg, d.mergeOptions, spatialNotifier)
 	}
+	d.updateLeaderboard(updateMsg)
+	d.compareShadow()
 	d.msgIndex = d.msgIndex + 1
+	d.recordFieldVersions(updateMsg, senderConnId)
+	d.recordTombstones(updateMsg)
+	d.applyDeadReckoning(updateMsg, t)
 	d.updateMsgBuffer.PushBack(&updateMsgBufferElement{
-		updateMsg:    updateMsg,
-		arrivalTime:  t,
-		senderConnId: senderConnId,
-		messageIndex: d.msgIndex,
+		updateMsg:         updateMsg,
+		arrivalTime:       t,
+		senderConnId:      senderConnId,
+		messageIndex:      d.msgIndex,
+		firstMessageIndex: d.msgIndex,
 	})
-	if d.updateMsgBuffer.Len() > MaxUpdateMsgBufferSize {
-		oldest := d.updateMsgBuffer.Front()
-		// Remove the oldest update message if it should has been fanned-out
-		if oldest.Value.(*updateMsgBufferElement).arrivalTime.AddMs(d.maxFanOutIntervalMs) < t {
-			d.updateMsgBuffer.Remove(oldest)
+
+	maxBufferSize := d.maxUpdateMsgBufferSize
+	if maxBufferSize <= 0 {
+		maxBufferSize = MaxUpdateMsgBufferSize
+	}
+	if d.updateMsgBuffer.Len() > maxBufferSize {
+		oldest := d.updateMsgBuffer.Remove(d.updateMsgBuffer.Front()).(*updateMsgBufferElement)
+		// The oldest update message should has been fanned-out. Otherwise, coalesce it into the next one
+		// so the buffer doesn't grow if some subscribers are stalled.
+		if oldest.arrivalTime.AddMs(d.maxFanOutIntervalMs) >= t {
+			d.coalesceUpdate(oldest, d.updateMsgBuffer.Front().Value.(*updateMsgBufferElement))
 		}
 	}
 }
 
+// Merges the older update message into the newer one in the buffer.
+// The subscribers that have received the older one but not the newer one need a full resync. See tickData().
+func (d *ChannelData) coalesceUpdate(older *updateMsgBufferElement, newer *updateMsgBufferElement) {
+	merged := proto.Clone(older.updateMsg)
+	mergeWithOptions(merged, newer.updateMsg, d.fanOutMergeOptions(), nil)
+	newer.updateMsg = merged
+	newer.firstMessageIndex = older.firstMessageIndex
+	if older.senderConnId != newer.senderConnId {
+		// The coalesced update should not be skipped by any sender
+		newer.senderConnId = 0
+	}
+	channelDataCoalesced.Inc()
+}
+
+// The subscribers that have the same key share the fan-out result in a tick, so the update message is only computed and marshaled once.
+type fanOutGroupKey struct {
+	hadFirstFanOut   bool
+	lastFanOutTime   ChannelTime
+	lastMessageIndex uint64
+	fanOutIntervalMs uint32
+	dataFieldMasks   string
+	updatedFields    bool
+}
+
+type fanOutResult struct {
+	// Nil if there's nothing to fan out
+	updateMsg        *channeldpb.ChannelDataUpdateMessage
+	lastMessageIndex uint64
+	fanOutTime       ChannelTime
+	// The senders of the merged update messages
+	senderConnIds map[ConnectionId]struct{}
+	// The arrival time of the earliest merged update message. Only valid if senderConnIds is not nil.
+	firstArrivalTime ChannelTime
+}
+
 func (ch *Channel) tickData(t ChannelTime) {
 	if ch.data == nil || ch.data.msg == nil {
 		return
 	}
 
+	groupResults := make(map[fanOutGroupKey]*fanOutResult)
+	// The checksums of the channel data computed in this tick, by the field masks
+	checksums := make(map[string]uint32)
+	// See channeldpb.ChannelDataUpdateMessage.ServerTimeMs
+	serverTimeMs := ch.startTime.Add(time.Duration(t)).UnixMilli()
 	focp := ch.fanOutQueue.Front()
 
 	for focp != nil {
@@ -186,7 +342,11 @@ func (ch *Channel) tickData(t ChannelTime) {
 		ch.connectionsLock.RLock()
 		cs := ch.subscribedConnections[conn]
 		ch.connectionsLock.RUnlock()
-		if cs == nil || *cs.options.DataAccess == channeldpb.ChannelDataAccess_NO_ACCESS {
+		if cs == nil || *cs.options.DataAccess == channeldpb.ChannelDataAccess_NO_ACCESS || ch.isFanOutPaused(foc) {
+			focp = focp.Next()
+			continue
+		}
+		if ch.checkSlowSubscriber(foc, cs, t) {
 			focp = focp.Next()
 			continue
 		}
@@ -197,73 +357,80 @@ func (ch *Channel) tickData(t ChannelTime) {
 			   |------FanOutDelay------|---FanOutInterval---|
 			   subTime                 firstFanOutTime      secondFanOutTime
 		*/
-		nextFanOutTime := foc.lastFanOutTime.AddMs(*cs.options.FanOutIntervalMs)
-		// latestFanoutTime := foc.lastFanOutTime
+		fanOutIntervalMs := foc.bandwidthFanOutIntervalMs(cs)
+		nextFanOutTime := foc.lastFanOutTime.AddMs(fanOutIntervalMs)
 		if t >= nextFanOutTime {
-			latestFanoutTime := nextFanOutTime
-			var lastUpdateTime ChannelTime
-			bufp := ch.data.updateMsgBuffer.Front()
-			if ch.data.accumulatedUpdateMsg == nil {
-				ch.data.accumulatedUpdateMsg = ch.data.msg.ProtoReflect().New().Interface()
-			} else {
-				proto.Reset(ch.data.accumulatedUpdateMsg)
+			if foc.isKeyframeDue(cs, t) {
+				foc.hadFirstFanOut = false
+			}
+			keyframe := !foc.hadFirstFanOut
+			previousVersion := foc.lastMessageIndex
+			key := fanOutGroupKey{
+				hadFirstFanOut:   foc.hadFirstFanOut,
+				lastFanOutTime:   foc.lastFanOutTime,
+				lastMessageIndex: foc.lastMessageIndex,
+				fanOutIntervalMs: fanOutIntervalMs,
+				dataFieldMasks:   strings.Join(cs.options.DataFieldMasks, ","),
+				updatedFields:    cs.options.GetUpdatedFields(),
 			}
-			hasEverMerged := false
-
-			//if foc.lastFanOutTime <= cs.subTime {
 			if !foc.hadFirstFanOut {
-				// Send the whole data for the first time
-				ch.fanOutDataUpdate(conn, cs, ch.data.msg)
-				foc.hadFirstFanOut = true
-				foc.lastMessageIndex = ch.data.msgIndex
-				latestFanoutTime = t
-			} else if bufp != nil {
-				if foc.lastFanOutTime >= lastUpdateTime {
-					lastUpdateTime = foc.lastFanOutTime
-				}
-
-				for bufi := 0; bufi < ch.data.updateMsgBuffer.Len(); bufi++ {
-					be := bufp.Value.(*updateMsgBufferElement)
-					/*
-						ch.Logger().Trace("going through updateMsgBuffer",
-							zap.Int("bufi", bufi),
-							zap.Int64("lastUpdateTime", int64(lastUpdateTime)/1000),
-							zap.Int64("arrivalTime", int64(be.arrivalTime)/1000),
-							zap.Int64("nextFanOutTime", int64(nextFanOutTime)/1000),
-							zap.Uint32("senderConnId", uint32(be.senderConnId)),
-						)
-					*/
-
-					if be.senderConnId == conn.Id() && *cs.options.SkipSelfUpdateFanOut {
-						bufp = bufp.Next()
-						continue
-					}
-
-					if be.arrivalTime >= lastUpdateTime && be.arrivalTime <= nextFanOutTime {
-						if !hasEverMerged {
-							proto.Merge(ch.data.accumulatedUpdateMsg, be.updateMsg)
-						} else {
-							mergeWithOptions(ch.data.accumulatedUpdateMsg, be.updateMsg, ch.data.mergeOptions, nil)
-						}
-						hasEverMerged = true
-						lastUpdateTime = be.arrivalTime
-						foc.lastMessageIndex = be.messageIndex
-					}
-
-					/* TODO: remove the out-dated buffer element to decrease the iteration time
-					if be.arrivalTime.AddMs(ch.data.maxFanOutIntervalMs*2) < t {
-						ch.data.updateMsgBuffer.Remove(bufp)
-					}
-					*/
+				// The first fan-out doesn't depend on the subscription time
+				key.lastFanOutTime = 0
+				key.lastMessageIndex = 0
+			}
+			result, exists := groupResults[key]
+			if !exists {
+				result = ch.computeFanOut(foc, cs, t, nextFanOutTime, nil)
+				groupResults[key] = result
+			}
+			if _, isSender := result.senderConnIds[conn.Id()]; isSender && *cs.options.SkipSelfUpdateFanOut {
+				// The shared result contains the connection's own updates
+				connId := conn.Id()
+				result = ch.computeFanOut(foc, cs, t, nextFanOutTime, &connId)
+			}
 
-					bufp = bufp.Next()
+			var updateMsg *channeldpb.ChannelDataUpdateMessage
+			if result.updateMsg != nil {
+				result.updateMsg.Version = result.lastMessageIndex
+				updateMsg = ch.withChecksum(foc, cs, result, t, checksums)
+				if cs.options.GetKeyframeIntervalMs() > 0 {
+					updateMsg = foc.withKeyframe(updateMsg, keyframe, previousVersion)
 				}
-
-				if hasEverMerged {
-					ch.fanOutDataUpdate(conn, cs, ch.data.accumulatedUpdateMsg)
+			}
+			updateMsg = ch.applyFanOutBudget(foc, cs, updateMsg, result.lastMessageIndex)
+			updateMsg = ch.data.withInputAck(conn, updateMsg)
+			if foc.isDuplicateFanOut(cs, updateMsg, keyframe, ch.data.mergeOptions) {
+				updateMsg = nil
+				fanOutDuplicateSuppressed.WithLabelValues(ch.channelType.String()).Inc()
+			}
+			if updateMsg != nil {
+				// Same for all the subscribers in the tick, so it's safe to set on the shared update message.
+				updateMsg.ServerTimeMs = serverTimeMs
+				updateMsg.Tick = uint64(ch.tickFrames)
+				ctx := MessageContext{
+					MsgType:    channeldpb.MessageType_CHANNEL_DATA_UPDATE,
+					Msg:        updateMsg,
+					Connection: nil,
+					Channel:    ch,
+					Broadcast:  0,
+					StubId:     0,
+					ChannelId:  uint32(ch.fanOutChannelId()),
+				}
+				if result.senderConnIds != nil {
+					ctx.updateArrival = ch.startTime.Add(time.Duration(result.firstArrivalTime))
 				}
+				conn.Send(ctx)
+				ch.countStats(&ch.statsCounters.fanOuts)
+			}
+			if keyframe {
+				foc.lastKeyframeTime = t
+				foc.keyframeVersion = result.lastMessageIndex
+			} else {
+				foc.adaptFanOutInterval(cs, result.lastMessageIndex-previousVersion)
 			}
-			foc.lastFanOutTime = latestFanoutTime
+			foc.hadFirstFanOut = true
+			foc.lastMessageIndex = result.lastMessageIndex
+			foc.lastFanOutTime = result.fanOutTime
 
 			temp := focp.Prev()
 			// Move the fanned-out connection to the back of the queue
@@ -283,33 +450,130 @@ func (ch *Channel) tickData(t ChannelTime) {
 			focp = focp.Next()
 		}
 	}
+
+	if len(ch.data.tombstones) > 0 {
+		// The tombstones before it have been received by all the subscribers
+		minLastMessageIndex := ch.data.msgIndex
+		for e := ch.fanOutQueue.Front(); e != nil; e = e.Next() {
+			foc := e.Value.(*fanOutConnection)
+			if foc.hadFirstFanOut && foc.lastMessageIndex < minLastMessageIndex {
+				minLastMessageIndex = foc.lastMessageIndex
+			}
+		}
+		ch.data.pruneTombstones(minLastMessageIndex)
+	}
 }
 
-func (ch *Channel) fanOutDataUpdate(conn ConnectionInChannel, cs *ChannelSubscription, updateMsg common.ChannelDataMessage) {
-	fmutils.Filter(updateMsg, cs.options.DataFieldMasks)
+// Computes the update message to fan out to the subscriber. If skipConnId is not nil, the update messages sent by the connection are excluded.
+func (ch *Channel) computeFanOut(foc *fanOutConnection, cs *ChannelSubscription, t ChannelTime, nextFanOutTime ChannelTime, skipConnId *ConnectionId) *fanOutResult {
+	result := &fanOutResult{
+		lastMessageIndex: foc.lastMessageIndex,
+		fanOutTime:       nextFanOutTime,
+	}
+
+	//if foc.lastFanOutTime <= cs.subTime {
+	if !foc.hadFirstFanOut {
+		// Send the whole data for the first time
+		result.updateMsg = ch.marshalFanOutUpdate(cs, ch.data.msg, false)
+		result.lastMessageIndex = ch.data.msgIndex
+		result.fanOutTime = t
+		return result
+	}
+
+	bufp := ch.data.updateMsgBuffer.Front()
+	if bufp == nil {
+		return result
+	}
+
+	if ch.data.accumulatedUpdateMsg == nil {
+		ch.data.accumulatedUpdateMsg = ch.data.msg.ProtoReflect().New().Interface()
+	} else {
+		proto.Reset(ch.data.accumulatedUpdateMsg)
+	}
+	hasEverMerged := false
+	lastUpdateTime := foc.lastFanOutTime
+
+	for ; bufp != nil; bufp = bufp.Next() {
+		be := bufp.Value.(*updateMsgBufferElement)
+		if skipConnId != nil && be.senderConnId == *skipConnId {
+			continue
+		}
+
+		if be.arrivalTime >= lastUpdateTime && be.arrivalTime <= nextFanOutTime {
+			// The connection has received a part of the coalesced update
+			if be.firstMessageIndex <= foc.lastMessageIndex && foc.lastMessageIndex < be.messageIndex {
+				result.updateMsg = ch.marshalFanOutUpdate(cs, ch.data.withTombstones(foc.lastMessageIndex), false)
+				result.lastMessageIndex = ch.data.msgIndex
+				result.senderConnIds = nil
+				fanOutFullResync.Inc()
+				return result
+			}
+			if !hasEverMerged {
+				proto.Merge(ch.data.accumulatedUpdateMsg, be.updateMsg)
+			} else {
+				mergeWithOptions(ch.data.accumulatedUpdateMsg, be.updateMsg, ch.data.fanOutMergeOptions(), nil)
+			}
+			hasEverMerged = true
+			lastUpdateTime = be.arrivalTime
+			result.lastMessageIndex = be.messageIndex
+			if result.senderConnIds == nil {
+				result.senderConnIds = make(map[ConnectionId]struct{})
+				result.firstArrivalTime = be.arrivalTime
+			}
+			result.senderConnIds[be.senderConnId] = struct{}{}
+		}
+
+		/* TODO: remove the out-dated buffer element to decrease the iteration time
+		if be.arrivalTime.AddMs(ch.data.maxFanOutIntervalMs*2) < t {
+			ch.data.updateMsgBuffer.Remove(bufp)
+		}
+		*/
+	}
+
+	if hasEverMerged {
+		if ch.data.deadReckoning != nil && proto.Size(ch.data.accumulatedUpdateMsg) == 0 {
+			// All the updated positions are extrapolated by the subscriber
+			return result
+		}
+		result.updateMsg = ch.marshalFanOutUpdate(cs, ch.data.accumulatedUpdateMsg, true)
+	}
+	return result
+}
+
+// Applies the DataFieldMasks of the subscription and marshals the update message. Returns nil if failed.
+// The delta update carries the updated fields if the subscription asks for them.
+func (ch *Channel) marshalFanOutUpdate(cs *ChannelSubscription, updateMsg common.ChannelDataMessage, delta bool) *channeldpb.ChannelDataUpdateMessage {
+	updateMsg = ch.data.withoutLeaderboard(updateMsg)
+	if len(cs.options.DataFieldMasks) > 0 {
+		// Don't modify the channel data
+		updateMsg = proto.Clone(updateMsg)
+		fmutils.Filter(updateMsg, cs.options.DataFieldMasks)
+	}
 	any, err := anypb.New(updateMsg)
 	if err != nil {
 		ch.Logger().Error("failed to marshal channel update data", zap.Error(err))
-		return
+		return nil
+	}
+	msg := &channeldpb.ChannelDataUpdateMessage{Data: any}
+	if delta && cs.options.GetUpdatedFields() {
+		msg.UpdatedFields = updatedFieldPaths(updateMsg.ProtoReflect())
+	}
+	if ms := ch.data.maxExtrapolationMs(); ms > 0 {
+		msg.MaxExtrapolationMs = proto.Uint32(ms)
 	}
-	conn.Send(MessageContext{
-		MsgType:    channeldpb.MessageType_CHANNEL_DATA_UPDATE,
-		Msg:        &channeldpb.ChannelDataUpdateMessage{Data: any},
-		Connection: nil,
-		Channel:    ch,
-		Broadcast:  0,
-		StubId:     0,
-		ChannelId:  uint32(ch.id),
+	return msg
+}
+
+// Returns a shallow copy of the fan-out update message that shares the data with it. As the update message can be shared
+// by the fan-out group, the per-subscriber fields are set in the copy.
+func cloneUpdateEnvelope(updateMsg *channeldpb.ChannelDataUpdateMessage) *channeldpb.ChannelDataUpdateMessage {
+	msg := &channeldpb.ChannelDataUpdateMessage{}
+	dst := msg.ProtoReflect()
+	updateMsg.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		dst.Set(fd, v)
+		return true
 	})
-	/*
-		conn.Logger().Trace("fan out",
-			zap.Int64("channelTime", int64(ch.GetTime())),
-			zap.Int64("lastFanOutTime", int64(cs.fanOutElement.Value.(*fanOutConnection).lastFanOutTime)),
-			zap.Stringer("updateMsg", updateMsg.(fmt.Stringer)),
-		)
-	*/
-	// cs.lastFanOutTime = time.Now()
-	// cs.fanOutDataMsg = nil
+	return msg
 }
 
 // Implement this interface to manually merge the channel data. In most cases it can be MUCH more efficient than the default reflection-based merge.
@@ -318,7 +582,35 @@ type MergeableChannelData interface {
 	Merge(src common.ChannelDataMessage, options *channeldpb.ChannelDataMergeOptions, spatialNotifier common.SpatialInfoChangedNotifier) error
 }
 
+// Merges the channel data of a message type outside of the message itself, e.g. in an external plugin.
+// Takes precedence over MergeableChannelData and ReflectMerge.
+type ChannelDataMergeFunc func(dst common.ChannelDataMessage, src common.ChannelDataMessage, options *channeldpb.ChannelDataMergeOptions) error
+
+var channelDataMergeFuncs = make(map[protoreflect.FullName]ChannelDataMergeFunc)
+
+// Registers the merge function for the channel data message type of the full name, e.g. "tps.TestChannelDataMessage".
+// Should be called before any channel is created.
+func RegisterChannelDataMergeFunc(fullName protoreflect.FullName, merge ChannelDataMergeFunc) {
+	channelDataMergeFuncs[fullName] = merge
+}
+
+// Merges the update into the channel data in the same way as the channel does (the registered merge function, MergeableChannelData
+// or ReflectMerge), without touching any channel, e.g. for the offline tools. The CRDT mode is not applied. The options can be nil.
+func MergeChannelData(dst common.ChannelDataMessage, src common.ChannelDataMessage, options *channeldpb.ChannelDataMergeOptions) {
+	mergeWithOptions(dst, src, options, nil)
+}
+
 func mergeWithOptions(dst common.ChannelDataMessage, src common.ChannelDataMessage, options *channeldpb.ChannelDataMergeOptions, spatialNotifier common.SpatialInfoChangedNotifier) {
+	if merge, exists := channelDataMergeFuncs[dst.ProtoReflect().Descriptor().FullName()]; exists {
+		if err := merge(dst, src, options); err != nil {
+			rootLogger.Error("merge function error", zap.Error(err),
+				zap.String("dstType", string(dst.ProtoReflect().Descriptor().FullName().Name())),
+				zap.String("srcType", string(src.ProtoReflect().Descriptor().FullName().Name())),
+			)
+		}
+		return
+	}
+
 	mergeable, ok := dst.(MergeableChannelData)
 	if ok {
 		if options == nil {
@@ -0,0 +1,46 @@
+package channeld
+
+// The ceiling of the adaptive fan-out interval, if the subscription doesn't set channeldpb.ChannelSubscriptionOptions.MaxFanOutIntervalMs.
+const DefaultAdaptiveMaxFanOutIntervalMs = 1000
+
+func (cs *ChannelSubscription) maxAdaptiveFanOutIntervalMs() uint32 {
+	interval := cs.options.GetMaxFanOutIntervalMs()
+	if interval == 0 {
+		interval = DefaultAdaptiveMaxFanOutIntervalMs
+	}
+	if floor := cs.options.GetFanOutIntervalMs(); interval < floor {
+		interval = floor
+	}
+	return interval
+}
+
+// The interval to the next fan-out of the subscriber. See channeldpb.ChannelSubscriptionOptions.AdaptiveFanOut.
+func (foc *fanOutConnection) fanOutIntervalMs(cs *ChannelSubscription) uint32 {
+	floor := *cs.options.FanOutIntervalMs
+	if !cs.options.GetAdaptiveFanOut() || foc.adaptiveIntervalMs < floor {
+		return floor
+	}
+	return foc.adaptiveIntervalMs
+}
+
+// Adapts the fan-out interval to the number of the updates merged in the last fan-out:
+// lengthens it if there's none, and goes back to the floor in a burst.
+func (foc *fanOutConnection) adaptFanOutInterval(cs *ChannelSubscription, updates uint64) {
+	if !cs.options.GetAdaptiveFanOut() {
+		return
+	}
+	interval := foc.fanOutIntervalMs(cs)
+	switch {
+	case updates == 0:
+		if interval == 0 {
+			interval = 1
+		}
+		interval *= 2
+		if ceiling := cs.maxAdaptiveFanOutIntervalMs(); interval > ceiling {
+			interval = ceiling
+		}
+	case updates > 1:
+		interval = *cs.options.FanOutIntervalMs
+	}
+	foc.adaptiveIntervalMs = interval
+}
@@ -0,0 +1,78 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAdaptiveFanOut(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	owner := addTestConnection(channeldpb.ConnectionType_SERVER)
+	adaptive := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	fixed := addTestConnection(channeldpb.ConnectionType_CLIENT)
+
+	testChannel, _ := CreateChannel(channeldpb.ChannelType_TEST, owner)
+	// Stop the channel.Tick() goroutine
+	testChannel.removing = 1
+	testChannel.InitData(&testpb.TestChannelDataMessage{Text: "a", Num: 1}, nil)
+	testChannel.tickInterval = time.Hour
+
+	adaptiveCs, _ := adaptive.SubscribeToChannel(testChannel, &channeldpb.ChannelSubscriptionOptions{
+		FanOutIntervalMs:    proto.Uint32(20),
+		AdaptiveFanOut:      proto.Bool(true),
+		MaxFanOutIntervalMs: proto.Uint32(80),
+	})
+	fixedCs, _ := fixed.SubscribeToChannel(testChannel, &channeldpb.ChannelSubscriptionOptions{FanOutIntervalMs: proto.Uint32(20)})
+	assert.EqualValues(t, 80, testChannel.data.maxFanOutIntervalMs)
+	adaptiveFoc := adaptiveCs.fanOutElement.Value.(*fanOutConnection)
+	fixedFoc := fixedCs.fanOutElement.Value.(*fanOutConnection)
+
+	tick := ChannelTime(0)
+	// Ticks every 10ms until the adaptive subscriber is fanned out
+	tickFanOut := func() {
+		lastFanOutTime := adaptiveFoc.lastFanOutTime
+		for adaptiveFoc.lastFanOutTime == lastFanOutTime {
+			tick = tick.AddMs(10)
+			testChannel.tickData(tick)
+		}
+	}
+
+	// The first fan-out, then doubled after each fan-out without any change
+	tickFanOut()
+	assert.EqualValues(t, 20, adaptiveFoc.fanOutIntervalMs(adaptiveCs))
+	tickFanOut()
+	assert.EqualValues(t, 40, adaptiveFoc.fanOutIntervalMs(adaptiveCs))
+	tickFanOut()
+	assert.EqualValues(t, 80, adaptiveFoc.fanOutIntervalMs(adaptiveCs))
+	// Capped
+	tickFanOut()
+	assert.EqualValues(t, 80, adaptiveFoc.fanOutIntervalMs(adaptiveCs))
+	assert.EqualValues(t, 20, fixedFoc.fanOutIntervalMs(fixedCs))
+
+	// A single change keeps the interval
+	testChannel.Data().OnUpdate(&testpb.TestChannelDataMessage{Num: 2}, tick, owner.Id(), nil)
+	tickFanOut()
+	assert.EqualValues(t, 80, adaptiveFoc.fanOutIntervalMs(adaptiveCs))
+
+	// A burst goes back to the floor
+	testChannel.Data().OnUpdate(&testpb.TestChannelDataMessage{Num: 3}, tick, owner.Id(), nil)
+	testChannel.Data().OnUpdate(&testpb.TestChannelDataMessage{Num: 4}, tick.AddMs(10), owner.Id(), nil)
+	tickFanOut()
+	assert.EqualValues(t, 20, adaptiveFoc.fanOutIntervalMs(adaptiveCs))
+	assert.EqualValues(t, 20, fixedFoc.fanOutIntervalMs(fixedCs))
+
+	// The burst is merged into a single fan-out
+	assert.Equal(t, 3, len(adaptive.testQueue()))
+	dataMsg, _ := adaptive.latestMsg().(*channeldpb.ChannelDataUpdateMessage).Data.UnmarshalNew()
+	assert.EqualValues(t, 4, dataMsg.(*testpb.TestChannelDataMessage).Num)
+	tickFanOut()
+	assert.EqualValues(t, 40, adaptiveFoc.fanOutIntervalMs(adaptiveCs))
+}
@@ -0,0 +1,288 @@
+package channeld
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// The checkpoints are dropped when the queue is full, so the sink never slows down the channels.
+	dataCheckpointQueueSize = 1024
+)
+
+// The selected fields of the channel data at a point of time, flattened into a row. See DataCheckpointSettingsType.
+type DataCheckpoint struct {
+	Tenant      string
+	ChannelId   uint32
+	ChannelType channeldpb.ChannelType
+	// The version of the channel data, i.e. the number of the updates merged.
+	Version uint64
+	TimeMs  int64
+	// The values by the column names. The value is nil if the field doesn't exist, e.g. the map entry is removed.
+	Columns map[string]interface{}
+}
+
+// Writes the batches of the checkpoints, e.g. to an SQL database. Called in the checkpoint goroutine.
+// The batch is dropped if an error is returned.
+type DataCheckpointSink interface {
+	Write(checkpoints []*DataCheckpoint) error
+}
+
+type dataCheckpointWriter struct {
+	sink  DataCheckpointSink
+	queue chan *DataCheckpoint
+}
+
+var dataCheckpoints *dataCheckpointWriter
+var dataCheckpointsLock sync.RWMutex
+
+// Sets the sink of the channel data checkpoints and starts writing them. No checkpoint is taken if the sink is nil.
+// See ChannelSettingsType.DataCheckpoint.
+func SetDataCheckpointSink(sink DataCheckpointSink) {
+	dataCheckpointsLock.Lock()
+	defer dataCheckpointsLock.Unlock()
+	if dataCheckpoints != nil {
+		// Writes the pending checkpoints to the previous sink and stops
+		close(dataCheckpoints.queue)
+		dataCheckpoints = nil
+	}
+	if sink == nil {
+		return
+	}
+
+	dataCheckpoints = &dataCheckpointWriter{
+		sink:  sink,
+		queue: make(chan *DataCheckpoint, dataCheckpointQueueSize),
+	}
+	go dataCheckpoints.run()
+}
+
+func (w *dataCheckpointWriter) run() {
+	for checkpoint := range w.queue {
+		batch := []*DataCheckpoint{checkpoint}
+		// Writes the checkpoints taken in the meantime in the same batch
+		for len(w.queue) > 0 {
+			batch = append(batch, <-w.queue)
+		}
+		if err := w.sink.Write(batch); err != nil {
+			rootLogger.Warn("failed to write the channel data checkpoints", zap.Int("num", len(batch)), zap.Error(err))
+		}
+	}
+}
+
+func queueDataCheckpoint(checkpoint *DataCheckpoint) {
+	dataCheckpointsLock.RLock()
+	defer dataCheckpointsLock.RUnlock()
+	if dataCheckpoints == nil {
+		return
+	}
+	select {
+	case dataCheckpoints.queue <- checkpoint:
+	default:
+		rootLogger.Warn("the checkpoint queue is full, the checkpoint is dropped", zap.Uint32("channelId", checkpoint.ChannelId))
+	}
+}
+
+func hasDataCheckpointSink() bool {
+	dataCheckpointsLock.RLock()
+	defer dataCheckpointsLock.RUnlock()
+	return dataCheckpoints != nil
+}
+
+// Called in the channel's tick.
+func (ch *Channel) tickDataCheckpoint(now time.Time) {
+	settings := ch.settings().DataCheckpoint
+	if settings.IntervalMs == 0 || len(settings.Columns) == 0 || ch.data == nil || ch.data.msg == nil {
+		return
+	}
+	if now.Sub(ch.lastDataCheckpointTime) < time.Duration(settings.IntervalMs)*time.Millisecond || !hasDataCheckpointSink() {
+		return
+	}
+	ch.lastDataCheckpointTime = now
+	queueDataCheckpoint(ch.takeDataCheckpoint(settings.Columns, now))
+}
+
+func (ch *Channel) takeDataCheckpoint(columns map[string]string, now time.Time) *DataCheckpoint {
+	checkpoint := &DataCheckpoint{
+		Tenant:      ch.tenant,
+		ChannelId:   uint32(ch.id),
+		ChannelType: ch.channelType,
+		Version:     ch.data.msgIndex,
+		TimeMs:      now.UnixMilli(),
+		Columns:     make(map[string]interface{}, len(columns)),
+	}
+	msg := ch.data.msg.ProtoReflect()
+	for column, path := range columns {
+		value, err := flattenDataField(msg, path)
+		if err != nil {
+			ch.Logger().Warn("failed to flatten the channel data field for checkpoint",
+				zap.String("column", column),
+				zap.String("path", path),
+				zap.Error(err),
+			)
+		}
+		checkpoint.Columns[column] = value
+	}
+	return checkpoint
+}
+
+// Returns the value of the field in the path, e.g. "score" or "entities.42.hp". The map entries are addressed by the key,
+// and the list elements by the index. The scalar is returned as it is (the enum as its number), and the message, list
+// or map is returned in JSON. Returns nil if the field doesn't exist.
+func flattenDataField(msg protoreflect.Message, path string) (interface{}, error) {
+	segments := strings.Split(path, ".")
+	for i := 0; i < len(segments); i++ {
+		fd := msg.Descriptor().Fields().ByName(protoreflect.Name(segments[i]))
+		if fd == nil || (fd.HasPresence() && !msg.Has(fd)) {
+			return nil, nil
+		}
+		value := msg.Get(fd)
+		last := i == len(segments)-1
+		if fd.IsList() || fd.IsMap() {
+			if last {
+				return collectionJson(fd, value)
+			}
+			i++
+			var ok bool
+			if value, ok = elementValue(fd, value, segments[i]); !ok {
+				return nil, nil
+			}
+			last = i == len(segments)-1
+			fd = elementField(fd)
+		}
+		if last {
+			return scalarOrJson(value, fd)
+		}
+		if fd.Kind() != protoreflect.MessageKind {
+			return nil, nil
+		}
+		msg = value.Message()
+	}
+	return nil, nil
+}
+
+// The descriptor of the elements in the list or the values in the map.
+func elementField(fd protoreflect.FieldDescriptor) protoreflect.FieldDescriptor {
+	if fd.IsMap() {
+		return fd.MapValue()
+	}
+	return fd
+}
+
+func elementValue(fd protoreflect.FieldDescriptor, value protoreflect.Value, segment string) (protoreflect.Value, bool) {
+	if fd.IsList() {
+		index, err := strconv.Atoi(segment)
+		if err != nil || index < 0 || index >= value.List().Len() {
+			return protoreflect.Value{}, false
+		}
+		return value.List().Get(index), true
+	}
+
+	var key protoreflect.MapKey
+	switch fd.MapKey().Kind() {
+	case protoreflect.StringKind:
+		key = protoreflect.ValueOfString(segment).MapKey()
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(segment)
+		if err != nil {
+			return protoreflect.Value{}, false
+		}
+		key = protoreflect.ValueOfBool(b).MapKey()
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, err := strconv.ParseInt(segment, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, false
+		}
+		key = protoreflect.ValueOfInt32(int32(n)).MapKey()
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n, err := strconv.ParseInt(segment, 10, 64)
+		if err != nil {
+			return protoreflect.Value{}, false
+		}
+		key = protoreflect.ValueOfInt64(n).MapKey()
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := strconv.ParseUint(segment, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, false
+		}
+		key = protoreflect.ValueOfUint32(uint32(n)).MapKey()
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, err := strconv.ParseUint(segment, 10, 64)
+		if err != nil {
+			return protoreflect.Value{}, false
+		}
+		key = protoreflect.ValueOfUint64(n).MapKey()
+	default:
+		return protoreflect.Value{}, false
+	}
+	if !value.Map().Has(key) {
+		return protoreflect.Value{}, false
+	}
+	return value.Map().Get(key), true
+}
+
+func scalarOrJson(value protoreflect.Value, fd protoreflect.FieldDescriptor) (interface{}, error) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		b, err := protojson.Marshal(value.Message().Interface())
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case protoreflect.EnumKind:
+		return int32(value.Enum()), nil
+	case protoreflect.BytesKind:
+		// The checkpoint is written in another goroutine
+		return append([]byte(nil), value.Bytes()...), nil
+	default:
+		return value.Interface(), nil
+	}
+}
+
+func collectionJson(fd protoreflect.FieldDescriptor, value protoreflect.Value) (interface{}, error) {
+	element := func(v protoreflect.Value) (json.RawMessage, error) {
+		if elementField(fd).Kind() == protoreflect.MessageKind {
+			return protojson.Marshal(v.Message().Interface())
+		}
+		scalar, err := scalarOrJson(v, elementField(fd))
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(scalar)
+	}
+
+	var result interface{}
+	var err error
+	if fd.IsList() {
+		list := make([]json.RawMessage, 0, value.List().Len())
+		for i := 0; i < value.List().Len() && err == nil; i++ {
+			var raw json.RawMessage
+			raw, err = element(value.List().Get(i))
+			list = append(list, raw)
+		}
+		result = list
+	} else {
+		m := make(map[string]json.RawMessage, value.Map().Len())
+		value.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			m[k.String()], err = element(v)
+			return err == nil
+		})
+		result = m
+	}
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,114 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlattenDataField(t *testing.T) {
+	msg := (&testpb.TestFieldMaskMessage{
+		Name: "a",
+		Msg:  &testpb.TestFieldMaskMessage_NestedMessage{P1: 1, P2: 2},
+		List: []*testpb.TestFieldMaskMessage_NestedMessage{{P1: 3}, {P2: 4}},
+		Kv1:  map[int64]*testpb.TestFieldMaskMessage_NestedMessage{42: {P1: 5}},
+		Kv2:  map[int64]string{7: "b"},
+	}).ProtoReflect()
+
+	flatten := func(path string) interface{} {
+		value, err := flattenDataField(msg, path)
+		assert.NoError(t, err)
+		return value
+	}
+	assert.Equal(t, "a", flatten("name"))
+	assert.EqualValues(t, 1, flatten("msg.p1"))
+	assert.EqualValues(t, 2, flatten("msg.p2"))
+	assert.JSONEq(t, `{"p1":"1","p2":2}`, flatten("msg").(string))
+	assert.EqualValues(t, 3, flatten("list.0.p1"))
+	assert.EqualValues(t, 4, flatten("list.1.p2"))
+	assert.EqualValues(t, 5, flatten("kv1.42.p1"))
+	assert.Equal(t, "b", flatten("kv2.7"))
+	assert.JSONEq(t, `{"7":"b"}`, flatten("kv2").(string))
+	assert.JSONEq(t, `[{"p1":"3"},{"p2":4}]`, flatten("list").(string))
+
+	// The fields that don't exist
+	assert.Nil(t, flatten("unknown"))
+	assert.Nil(t, flatten("list.2.p1"))
+	assert.Nil(t, flatten("kv1.43.p1"))
+	assert.Nil(t, flatten("kv2.x"))
+	assert.Nil(t, flatten("name.length"))
+	assert.Nil(t, (func() interface{} {
+		value, _ := flattenDataField((&testpb.TestFieldMaskMessage{}).ProtoReflect(), "msg.p1")
+		return value
+	})())
+}
+
+type testDataCheckpointSink struct {
+	checkpoints chan *DataCheckpoint
+}
+
+func (s *testDataCheckpointSink) Write(checkpoints []*DataCheckpoint) error {
+	for _, checkpoint := range checkpoints {
+		s.checkpoints <- checkpoint
+	}
+	return nil
+}
+
+func TestTickDataCheckpoint(t *testing.T) {
+	InitLogs()
+	InitChannels()
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		DataCheckpoint: DataCheckpointSettingsType{
+			IntervalMs: 1000,
+			Columns:    map[string]string{"text": "text", "num": "num"},
+		},
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, nil)
+	// Stop the channel.Tick() goroutine
+	ch.removing = 1
+	ch.InitData(&testpb.TestChannelDataMessage{Text: "a", Num: 1}, nil)
+
+	// No checkpoint without the sink
+	now := time.Now()
+	ch.tickDataCheckpoint(now)
+	assert.True(t, ch.lastDataCheckpointTime.IsZero())
+
+	sink := &testDataCheckpointSink{checkpoints: make(chan *DataCheckpoint, 4)}
+	SetDataCheckpointSink(sink)
+	defer SetDataCheckpointSink(nil)
+	nextCheckpoint := func() *DataCheckpoint {
+		select {
+		case checkpoint := <-sink.checkpoints:
+			return checkpoint
+		case <-time.After(100 * time.Millisecond):
+			return nil
+		}
+	}
+
+	ch.tickDataCheckpoint(now)
+	checkpoint := nextCheckpoint()
+	if assert.NotNil(t, checkpoint) {
+		assert.EqualValues(t, ch.id, checkpoint.ChannelId)
+		assert.Equal(t, channeldpb.ChannelType_TEST, checkpoint.ChannelType)
+		assert.Equal(t, now.UnixMilli(), checkpoint.TimeMs)
+		assert.Equal(t, map[string]interface{}{"text": "a", "num": uint32(1)}, checkpoint.Columns)
+	}
+
+	// Within the interval
+	ch.tickDataCheckpoint(now.Add(500 * time.Millisecond))
+	assert.Nil(t, nextCheckpoint())
+
+	ch.data.OnUpdate(&testpb.TestChannelDataMessage{Num: 2}, ch.GetTime(), 0, nil)
+	ch.tickDataCheckpoint(now.Add(time.Second))
+	checkpoint = nextCheckpoint()
+	if assert.NotNil(t, checkpoint) {
+		assert.EqualValues(t, 1, checkpoint.Version)
+		assert.Equal(t, uint32(2), checkpoint.Columns["num"])
+	}
+}
@@ -0,0 +1,65 @@
+package channeld
+
+import (
+	"strings"
+
+	"github.com/indiest/fmutils"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// Returns the fan-out update message with the checksum, if the checksum is due for the subscriber. Otherwise returns result.updateMsg.
+// As the result can be shared by the fan-out group, the checksum is set in a copy. The checksums are cached by the field masks.
+func (ch *Channel) withChecksum(foc *fanOutConnection, cs *ChannelSubscription, result *fanOutResult, t ChannelTime, checksums map[string]uint32) *channeldpb.ChannelDataUpdateMessage {
+	interval := ch.data.checksumIntervalMs
+	if interval == 0 || t < foc.lastChecksumTime.AddMs(interval) {
+		return result.updateMsg
+	}
+	// The subscriber won't have the same channel data as channeld until it receives the following updates.
+	if result.lastMessageIndex != ch.data.msgIndex {
+		return result.updateMsg
+	}
+
+	masks := strings.Join(cs.options.DataFieldMasks, ",")
+	checksum, exists := checksums[masks]
+	if !exists {
+		data := ch.data.withoutLeaderboard(ch.data.msg)
+		if len(cs.options.DataFieldMasks) > 0 {
+			data = proto.Clone(data)
+			fmutils.Filter(data, cs.options.DataFieldMasks)
+		}
+		checksum = common.ChannelDataChecksum(data)
+		checksums[masks] = checksum
+	}
+	foc.lastChecksumTime = t
+
+	msg := cloneUpdateEnvelope(result.updateMsg)
+	msg.Checksum = proto.Uint32(checksum)
+	return msg
+}
+
+func handleChannelDataResync(ctx MessageContext) {
+	msg, ok := ctx.Msg.(*channeldpb.ChannelDataResyncMessage)
+	if !ok {
+		ctx.Connection.Logger().Error("message is not a ChannelDataResyncMessage, will not be handled.")
+		return
+	}
+
+	cs, subscribed := ctx.Channel.subscribedConnections[ctx.Connection]
+	if !subscribed || cs.fanOutElement == nil {
+		ctx.Connection.Logger().Warn("attempt to resync channel data but not subscribed to the channel",
+			zap.Uint32("channelId", uint32(ctx.Channel.id)),
+		)
+		return
+	}
+
+	// The next fan-out sends the whole channel data, same as the first fan-out.
+	cs.fanOutElement.Value.(*fanOutConnection).hadFirstFanOut = false
+	channelDataResyncRequested.WithLabelValues(ctx.Channel.channelType.String()).Inc()
+	ctx.Connection.Logger().Info("requested channel data resync",
+		zap.Uint32("channelId", uint32(ctx.Channel.id)),
+		zap.Uint32("checksum", msg.Checksum),
+	)
+}
@@ -0,0 +1,77 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestFanOutChecksum(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{ChecksumIntervalMs: 100}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	c0 := addTestConnection(channeldpb.ConnectionType_SERVER)
+	c1 := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	c2 := addTestConnection(channeldpb.ConnectionType_CLIENT)
+
+	testChannel, _ := CreateChannel(channeldpb.ChannelType_TEST, c0)
+	// Stop the channel.Tick() goroutine
+	testChannel.removing = 1
+	testChannel.InitData(&testpb.TestChannelDataMessage{Text: "a", Num: 1}, nil)
+	testChannel.tickInterval = time.Hour
+
+	c1.SubscribeToChannel(testChannel, &channeldpb.ChannelSubscriptionOptions{FanOutIntervalMs: proto.Uint32(50)})
+	c2.SubscribeToChannel(testChannel, &channeldpb.ChannelSubscriptionOptions{FanOutIntervalMs: proto.Uint32(50), DataFieldMasks: []string{"text"}})
+	channelStartTime := ChannelTime(100 * int64(time.Millisecond))
+	latestUpdate := func(c *Connection) *channeldpb.ChannelDataUpdateMessage {
+		return c.latestMsg().(*channeldpb.ChannelDataUpdateMessage)
+	}
+
+	// The first fan-out carries the checksum
+	testChannel.tickData(channelStartTime)
+	if assert.NotNil(t, latestUpdate(c1).Checksum) {
+		assert.Equal(t, common.ChannelDataChecksum(testChannel.GetDataMessage()), *latestUpdate(c1).Checksum)
+	}
+	// The checksum is computed with the field masks
+	if assert.NotNil(t, latestUpdate(c2).Checksum) {
+		assert.Equal(t, common.ChannelDataChecksum(&testpb.TestChannelDataMessage{Text: "a"}), *latestUpdate(c2).Checksum)
+	}
+
+	// Not due yet
+	testChannel.Data().OnUpdate(&testpb.TestChannelDataMessage{Num: 2}, channelStartTime.AddMs(10), c0.Id(), nil)
+	testChannel.tickData(channelStartTime.AddMs(50))
+	assert.Equal(t, 2, len(c1.testQueue()))
+	assert.Nil(t, latestUpdate(c1).Checksum)
+
+	testChannel.Data().OnUpdate(&testpb.TestChannelDataMessage{Text: "b"}, channelStartTime.AddMs(60), c0.Id(), nil)
+	testChannel.tickData(channelStartTime.AddMs(100))
+	assert.Equal(t, 3, len(c1.testQueue()))
+	if assert.NotNil(t, latestUpdate(c1).Checksum) {
+		assert.Equal(t, common.ChannelDataChecksum(&testpb.TestChannelDataMessage{Text: "b", Num: 2}), *latestUpdate(c1).Checksum)
+	}
+
+	// c1 has diverged and requests a resync
+	resyncCount := testutil.ToFloat64(channelDataResyncRequested.WithLabelValues(channeldpb.ChannelType_TEST.String()))
+	handleChannelDataResync(MessageContext{
+		MsgType:    channeldpb.MessageType_CHANNEL_DATA_RESYNC,
+		Msg:        &channeldpb.ChannelDataResyncMessage{Checksum: 1},
+		Connection: c1,
+		Channel:    testChannel,
+	})
+	assert.Equal(t, resyncCount+1, testutil.ToFloat64(channelDataResyncRequested.WithLabelValues(channeldpb.ChannelType_TEST.String())))
+	testChannel.tickData(channelStartTime.AddMs(150))
+	assert.Equal(t, 4, len(c1.testQueue()))
+	data := &testpb.TestChannelDataMessage{}
+	assert.NoError(t, latestUpdate(c1).Data.UnmarshalTo(data))
+	assert.True(t, proto.Equal(testChannel.GetDataMessage(), data))
+}
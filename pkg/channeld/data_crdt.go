@@ -0,0 +1,104 @@
+package channeld
+
+import (
+	"time"
+
+	"github.com/metaworking/channeld/pkg/common"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// The timestamp of the last write of a CRDT register. The connId breaks the tie of the same timestamp.
+type crdtStamp struct {
+	timestamp int64
+	connId    ConnectionId
+}
+
+func (s crdtStamp) isNewerThan(other crdtStamp) bool {
+	if s.timestamp == other.timestamp {
+		return s.connId > other.connId
+	}
+	return s.timestamp > other.timestamp
+}
+
+func newCrdtStamp(timestamp int64, senderConnId ConnectionId) crdtStamp {
+	if timestamp == 0 {
+		timestamp = time.Now().UnixMilli()
+	}
+	return crdtStamp{timestamp: timestamp, connId: senderConnId}
+}
+
+func (d *ChannelData) isCrdt() bool {
+	return d.mergeOptions != nil && d.mergeOptions.Crdt
+}
+
+// Copies the value of the field in src to dst, without sharing the underlying list, map or message.
+func setClonedField(dst protoreflect.Message, src protoreflect.Message, fd protoreflect.FieldDescriptor) {
+	tmp := src.New()
+	tmp.Set(fd, src.Get(fd))
+	dst.Set(fd, proto.Clone(tmp.Interface()).ProtoReflect().Get(fd))
+}
+
+// Merges the update into the channel data with the CRDT semantics. See channeldpb.ChannelDataMergeOptions.Crdt.
+// The fields and the map entries that lose are removed from the update message, so the fan-out only contains the applied changes.
+func (d *ChannelData) crdtMerge(updateMsg common.ChannelDataMessage, stamp crdtStamp) {
+	if d.crdtStamps == nil {
+		d.crdtStamps = make(map[string]crdtStamp)
+	}
+
+	dst := d.msg.ProtoReflect()
+	src := updateMsg.ProtoReflect()
+	var lostFields []protoreflect.FieldDescriptor
+	src.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		name := string(fd.Name())
+		if !fd.IsMap() {
+			if last, exists := d.crdtStamps[name]; exists && last.isNewerThan(stamp) {
+				lostFields = append(lostFields, fd)
+				return true
+			}
+			d.crdtStamps[name] = stamp
+			setClonedField(dst, src, fd)
+			return true
+		}
+
+		dstMap := dst.Mutable(fd).Map()
+		var lostKeys []protoreflect.MapKey
+		v.Map().Range(func(mk protoreflect.MapKey, mv protoreflect.Value) bool {
+			key := mapEntryKey(name, mk)
+			last, exists := d.crdtStamps[key]
+			if valueMsg, ok := mv.Interface().(protoreflect.Message); ok && isRemovedMapValue(valueMsg) {
+				// The remover couldn't have observed the newer add
+				if exists && last.isNewerThan(stamp) {
+					lostKeys = append(lostKeys, mk)
+					return true
+				}
+				delete(d.crdtStamps, key)
+				dstMap.Clear(mk)
+				return true
+			}
+
+			// The key is always added, as the remover couldn't have observed this add. The value is only set if it's newer.
+			if dstMap.Has(mk) && exists && last.isNewerThan(stamp) {
+				lostKeys = append(lostKeys, mk)
+				return true
+			}
+			d.crdtStamps[key] = stamp
+			tmp := src.New()
+			tmp.Mutable(fd).Map().Set(mk, mv)
+			dstMap.Set(mk, proto.Clone(tmp.Interface()).ProtoReflect().Get(fd).Map().Get(mk))
+			return true
+		})
+		for _, mk := range lostKeys {
+			v.Map().Clear(mk)
+		}
+		return true
+	})
+	for _, fd := range lostFields {
+		src.Clear(fd)
+	}
+}
+
+func isRemovedMapValue(m protoreflect.Message) bool {
+	removable, ok := m.Interface().(RemovableMapField)
+	return ok && removable.GetRemoved()
+}
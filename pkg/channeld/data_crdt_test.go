@@ -0,0 +1,92 @@
+package channeld
+
+import (
+	"testing"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func newCrdtTestData() *ChannelData {
+	InitLogs()
+	InitChannels()
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, nil)
+	ch.InitData(&testpb.TestGeneratedMergeMessage{}, &channeldpb.ChannelDataMergeOptions{Crdt: true})
+	return ch.Data()
+}
+
+func TestCrdtMerge(t *testing.T) {
+	d := newCrdtTestData()
+	data := d.msg.(*testpb.TestGeneratedMergeMessage)
+	update := func(connId ConnectionId, timestamp int64, msg *testpb.TestGeneratedMergeMessage) *testpb.TestGeneratedMergeMessage {
+		d.onUpdate(msg, 0, connId, nil, timestamp)
+		return msg
+	}
+	wrapper := func(content string, removed bool) *testpb.TestMergeMessage_StringWrapper {
+		return &testpb.TestMergeMessage_StringWrapper{Content: content, Removed: removed}
+	}
+
+	// LWW register: the older write arrives later and is dropped from the fan-out
+	update(1, 20, &testpb.TestGeneratedMergeMessage{Name: "a"})
+	applied := update(2, 10, &testpb.TestGeneratedMergeMessage{Name: "b", Flag: true})
+	assert.Equal(t, "a", data.Name)
+	assert.True(t, data.Flag)
+	assert.Empty(t, applied.Name)
+	// The same timestamp is ordered by the connId
+	update(1, 30, &testpb.TestGeneratedMergeMessage{Name: "c"})
+	update(2, 30, &testpb.TestGeneratedMergeMessage{Name: "d"})
+	update(1, 30, &testpb.TestGeneratedMergeMessage{Name: "e"})
+	assert.Equal(t, "d", data.Name)
+
+	// OR-set: the removal doesn't remove the newer add
+	update(1, 20, &testpb.TestGeneratedMergeMessage{Kv: map[int64]*testpb.TestMergeMessage_StringWrapper{1: wrapper("a", false)}})
+	applied = update(2, 10, &testpb.TestGeneratedMergeMessage{Kv: map[int64]*testpb.TestMergeMessage_StringWrapper{1: wrapper("", true)}})
+	assert.Equal(t, "a", data.Kv[1].Content)
+	assert.Empty(t, applied.Kv)
+
+	update(2, 40, &testpb.TestGeneratedMergeMessage{Kv: map[int64]*testpb.TestMergeMessage_StringWrapper{1: wrapper("", true)}})
+	assert.NotContains(t, data.Kv, int64(1))
+
+	// The add that arrives after the removal couldn't have been observed by the remover, so it wins
+	update(1, 25, &testpb.TestGeneratedMergeMessage{Kv: map[int64]*testpb.TestMergeMessage_StringWrapper{1: wrapper("b", false)}})
+	assert.Equal(t, "b", data.Kv[1].Content)
+	// But the value is still LWW
+	update(2, 15, &testpb.TestGeneratedMergeMessage{Kv: map[int64]*testpb.TestMergeMessage_StringWrapper{1: wrapper("c", false)}})
+	assert.Equal(t, "b", data.Kv[1].Content)
+
+	// The channel data doesn't share the values with the update messages
+	msg := update(1, 50, &testpb.TestGeneratedMergeMessage{Kv: map[int64]*testpb.TestMergeMessage_StringWrapper{2: wrapper("x", false)}})
+	msg.Kv[2].Content = "y"
+	assert.Equal(t, "x", data.Kv[2].Content)
+}
+
+func TestCrdtMergeConverges(t *testing.T) {
+	type write struct {
+		connId    ConnectionId
+		timestamp int64
+		msg       *testpb.TestGeneratedMergeMessage
+	}
+	writes := []write{
+		{1, 10, &testpb.TestGeneratedMergeMessage{Name: "a", Kv2: map[string]uint32{"x": 1}}},
+		{2, 12, &testpb.TestGeneratedMergeMessage{Name: "b", List: []string{"1"}}},
+		{3, 11, &testpb.TestGeneratedMergeMessage{Kv2: map[string]uint32{"x": 3, "y": 3}, List: []string{"2", "3"}}},
+		{1, 13, &testpb.TestGeneratedMergeMessage{Kv2: map[string]uint32{"y": 1}, Flag: true}},
+	}
+
+	forward := newCrdtTestData()
+	for _, w := range writes {
+		forward.onUpdate(proto.Clone(w.msg), 0, w.connId, nil, w.timestamp)
+	}
+	backward := newCrdtTestData()
+	for i := len(writes) - 1; i >= 0; i-- {
+		backward.onUpdate(proto.Clone(writes[i].msg), 0, writes[i].connId, nil, writes[i].timestamp)
+	}
+
+	assert.True(t, proto.Equal(forward.msg, backward.msg), "forward: %v, backward: %v", forward.msg, backward.msg)
+	result := forward.msg.(*testpb.TestGeneratedMergeMessage)
+	assert.Equal(t, "b", result.Name)
+	assert.Equal(t, []string{"1"}, result.List)
+	assert.Equal(t, map[string]uint32{"x": 3, "y": 1}, result.Kv2)
+}
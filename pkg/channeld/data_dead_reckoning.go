@@ -0,0 +1,226 @@
+package channeld
+
+import (
+	"math"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/common"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const DefaultMaxExtrapolationMs = 1000
+
+type vector3 struct {
+	x, y, z float64
+}
+
+func (v vector3) add(o vector3) vector3 {
+	return vector3{v.x + o.x, v.y + o.y, v.z + o.z}
+}
+
+func (v vector3) scale(s float64) vector3 {
+	return vector3{v.x * s, v.y * s, v.z * s}
+}
+
+func (v vector3) distance(o vector3) float64 {
+	return math.Sqrt((v.x-o.x)*(v.x-o.x) + (v.y-o.y)*(v.y-o.y) + (v.z-o.z)*(v.z-o.z))
+}
+
+// The kinematic state that the subscribers extrapolate from, i.e. the last one fanned out.
+type drReference struct {
+	position     vector3
+	velocity     vector3
+	acceleration vector3
+	time         ChannelTime
+}
+
+func (ref *drReference) extrapolate(t ChannelTime) vector3 {
+	dt := time.Duration(t - ref.time).Seconds()
+	return ref.position.add(ref.velocity.scale(dt)).add(ref.acceleration.scale(dt * dt / 2))
+}
+
+// See ChannelSettingsType.DeadReckoning.
+type deadReckoning struct {
+	settings       DeadReckoningSettingsType
+	entityMapField string
+	// Resolved from the channel data message. See resolve(). The entityFd is nil if the channel data itself is the entity.
+	entityFd       protoreflect.FieldDescriptor
+	positionFd     protoreflect.FieldDescriptor
+	velocityFd     protoreflect.FieldDescriptor
+	accelerationFd protoreflect.FieldDescriptor
+	resolved       bool
+	invalid        bool
+	// The references of the entities, by the entity id. The id is 0 if the channel data itself is the entity.
+	references map[EntityId]*drReference
+}
+
+func isVectorField(fd protoreflect.FieldDescriptor) bool {
+	if fd == nil || fd.IsList() || fd.IsMap() || fd.Kind() != protoreflect.MessageKind {
+		return false
+	}
+	for _, name := range []protoreflect.Name{"x", "y", "z"} {
+		f := fd.Message().Fields().ByName(name)
+		if f == nil || (f.Kind() != protoreflect.FloatKind && f.Kind() != protoreflect.DoubleKind) {
+			return false
+		}
+	}
+	return true
+}
+
+func readVector(m protoreflect.Message, fd protoreflect.FieldDescriptor) vector3 {
+	if fd == nil || !m.Has(fd) {
+		return vector3{}
+	}
+	vm := m.Get(fd).Message()
+	fields := vm.Descriptor().Fields()
+	return vector3{
+		x: vm.Get(fields.ByName("x")).Float(),
+		y: vm.Get(fields.ByName("y")).Float(),
+		z: vm.Get(fields.ByName("z")).Float(),
+	}
+}
+
+func (dr *deadReckoning) maxExtrapolationMs() uint32 {
+	if dr.settings.MaxExtrapolationMs == 0 {
+		return DefaultMaxExtrapolationMs
+	}
+	return dr.settings.MaxExtrapolationMs
+}
+
+// Resolves the fields of the settings in the channel data message. Returns false if the settings are invalid.
+func (dr *deadReckoning) resolve(msg common.ChannelDataMessage) bool {
+	if dr.invalid {
+		return false
+	}
+	if dr.resolved {
+		return true
+	}
+	dr.invalid = true
+	entityDesc := msg.ProtoReflect().Descriptor()
+	if dr.entityMapField != "" {
+		dr.entityFd = entityDesc.Fields().ByName(protoreflect.Name(dr.entityMapField))
+		if dr.entityFd == nil || !dr.entityFd.IsMap() || dr.entityFd.MapValue().Kind() != protoreflect.MessageKind {
+			rootLogger.Error("the entity field is not a map field of messages in the channel data",
+				zap.String("msgName", string(entityDesc.FullName())),
+				zap.String("entityMapField", dr.entityMapField),
+			)
+			return false
+		}
+		entityDesc = dr.entityFd.MapValue().Message()
+	}
+	dr.positionFd = entityDesc.Fields().ByName(protoreflect.Name(dr.settings.PositionField))
+	dr.velocityFd = entityDesc.Fields().ByName(protoreflect.Name(dr.settings.VelocityField))
+	if dr.settings.AccelerationField != "" {
+		dr.accelerationFd = entityDesc.Fields().ByName(protoreflect.Name(dr.settings.AccelerationField))
+	}
+	if !isVectorField(dr.positionFd) || !isVectorField(dr.velocityFd) || (dr.settings.AccelerationField != "" && !isVectorField(dr.accelerationFd)) {
+		rootLogger.Error("the dead reckoning fields are not the vector fields of the entity",
+			zap.String("msgName", string(entityDesc.FullName())),
+			zap.String("positionField", dr.settings.PositionField),
+			zap.String("velocityField", dr.settings.VelocityField),
+			zap.String("accelerationField", dr.settings.AccelerationField),
+		)
+		return false
+	}
+	dr.references = make(map[EntityId]*drReference)
+	dr.resolved = true
+	dr.invalid = false
+	return true
+}
+
+// Returns true if the subscribers can extrapolate the position of the entity in the update. Otherwise, the entity's current state
+// becomes the reference. The current entity is the one in the channel data, with the update merged.
+func (dr *deadReckoning) check(id EntityId, updated protoreflect.Message, current protoreflect.Message, t ChannelTime) bool {
+	if !updated.Has(dr.positionFd) {
+		if updated.Has(dr.velocityFd) || (dr.accelerationFd != nil && updated.Has(dr.accelerationFd)) {
+			// The next position update should be fanned out along with the velocity
+			delete(dr.references, id)
+		}
+		return false
+	}
+
+	position := readVector(current, dr.positionFd)
+	velocity := readVector(current, dr.velocityFd)
+	acceleration := readVector(current, dr.accelerationFd)
+	ref := dr.references[id]
+	if ref != nil && t < ref.time.AddMs(dr.maxExtrapolationMs()) {
+		// The extrapolation should stay in the error until the next reference, with the changed velocity and acceleration.
+		maxSeconds := float64(dr.maxExtrapolationMs()) / 1000
+		maxError := dr.settings.MaxError
+		if ref.extrapolate(t).distance(position) <= maxError &&
+			ref.velocity.distance(velocity)*maxSeconds <= maxError &&
+			ref.acceleration.distance(acceleration)*maxSeconds*maxSeconds/2 <= maxError {
+			return true
+		}
+	}
+	dr.references[id] = &drReference{position: position, velocity: velocity, acceleration: acceleration, time: t}
+	return false
+}
+
+func (dr *deadReckoning) strip(m protoreflect.Message) {
+	m.Clear(dr.positionFd)
+	m.Clear(dr.velocityFd)
+	if dr.accelerationFd != nil {
+		m.Clear(dr.accelerationFd)
+	}
+}
+
+// Leaves out the extrapolatable positions from the update message. Should be called after the update message is merged,
+// and before it's buffered for the fan-out. The channel data is not modified.
+func (d *ChannelData) applyDeadReckoning(updateMsg common.ChannelDataMessage, t ChannelTime) {
+	dr := d.deadReckoning
+	if dr == nil || d.msg == nil || updateMsg == d.msg || !dr.resolve(d.msg) {
+		return
+	}
+
+	if dr.entityFd == nil {
+		if dr.check(0, updateMsg.ProtoReflect(), d.msg.ProtoReflect(), t) {
+			// The vector messages can be shared with the channel data, so only the references are cleared.
+			dr.strip(updateMsg.ProtoReflect())
+			deadReckoningExtrapolated.Inc()
+		}
+		return
+	}
+
+	if !updateMsg.ProtoReflect().Has(dr.entityFd) {
+		return
+	}
+	updated := updateMsg.ProtoReflect().Mutable(dr.entityFd).Map()
+	current := d.msg.ProtoReflect().Get(dr.entityFd).Map()
+	var extrapolated []protoreflect.MapKey
+	updated.Range(func(mk protoreflect.MapKey, v protoreflect.Value) bool {
+		id, ok := entityIdOf(mk)
+		if !ok {
+			return true
+		}
+		if !current.Has(mk) || isRemovedMapValue(v.Message()) {
+			delete(dr.references, id)
+			return true
+		}
+		if dr.check(id, v.Message(), current.Get(mk).Message(), t) {
+			extrapolated = append(extrapolated, mk)
+		}
+		return true
+	})
+	for _, mk := range extrapolated {
+		// The entity message can be shared with the channel data
+		entity := proto.Clone(updated.Get(mk).Message().Interface()).ProtoReflect()
+		dr.strip(entity)
+		if proto.Size(entity.Interface()) == 0 {
+			updated.Clear(mk)
+		} else {
+			updated.Set(mk, protoreflect.ValueOfMessage(entity))
+		}
+		deadReckoningExtrapolated.Inc()
+	}
+}
+
+// Returns 0 if the channel has no dead reckoning. See channeldpb.ChannelDataUpdateMessage.MaxExtrapolationMs.
+func (d *ChannelData) maxExtrapolationMs() uint32 {
+	if d.deadReckoning == nil || d.deadReckoning.invalid {
+		return 0
+	}
+	return d.deadReckoning.maxExtrapolationMs()
+}
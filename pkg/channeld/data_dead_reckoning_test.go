@@ -0,0 +1,133 @@
+package channeld
+
+import (
+	"testing"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDeadReckoning(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		EntityMapField: "movers",
+		DeadReckoning: DeadReckoningSettingsType{
+			PositionField:      "position",
+			VelocityField:      "velocity",
+			AccelerationField:  "acceleration",
+			MaxError:           1,
+			MaxExtrapolationMs: 1000,
+		},
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	ch.removing = 1
+	ch.InitData(&testpb.TestMoverMessage{}, nil)
+	client.SubscribeToChannel(ch, &channeldpb.ChannelSubscriptionOptions{FanOutIntervalMs: proto.Uint32(10)})
+
+	base := ch.GetTime()
+	tickTime := base
+	ch.tickData(tickTime)
+	// Updates the mover 1 at the time, and returns the fan-out
+	step := func(ms uint32, mover *testpb.TestMoverMessage_Mover) *testpb.TestMoverMessage_Mover {
+		client.sender.(*testQueuedMessageSender).msgQueue = nil
+		ch.Data().OnUpdate(&testpb.TestMoverMessage{
+			Movers: map[uint32]*testpb.TestMoverMessage_Mover{1: mover},
+		}, base.AddMs(ms), server.Id(), nil)
+		for tickTime < base.AddMs(ms+10) {
+			tickTime = tickTime.AddMs(10)
+			ch.tickData(tickTime)
+		}
+		updateMsg, ok := client.latestMsg().(*channeldpb.ChannelDataUpdateMessage)
+		if !ok {
+			return nil
+		}
+		assert.EqualValues(t, 1000, updateMsg.GetMaxExtrapolationMs())
+		data := &testpb.TestMoverMessage{}
+		assert.NoError(t, updateMsg.Data.UnmarshalTo(data))
+		return data.Movers[1]
+	}
+	vec := func(x float32) *testpb.TestVector {
+		return &testpb.TestVector{X: x}
+	}
+	current := func() *testpb.TestMoverMessage_Mover {
+		return ch.GetDataMessage().(*testpb.TestMoverMessage).Movers[1]
+	}
+
+	mover := step(0, &testpb.TestMoverMessage_Mover{Position: vec(0), Velocity: vec(10), Name: "a"})
+	if assert.NotNil(t, mover) {
+		assert.NotNil(t, mover.Position)
+	}
+
+	// On track. Nothing else to fan out.
+	assert.Nil(t, step(100, &testpb.TestMoverMessage_Mover{Position: vec(1), Velocity: vec(10)}))
+	// The channel data has the actual position
+	assert.EqualValues(t, 1, current().Position.X)
+
+	// Off track
+	mover = step(200, &testpb.TestMoverMessage_Mover{Position: vec(5), Velocity: vec(10), Name: "a"})
+	if assert.NotNil(t, mover) {
+		assert.EqualValues(t, 5, mover.Position.X)
+	}
+
+	// The velocity has changed
+	mover = step(300, &testpb.TestMoverMessage_Mover{Position: vec(6), Velocity: vec(20), Name: "a"})
+	if assert.NotNil(t, mover) {
+		assert.EqualValues(t, 20, mover.Velocity.X)
+	}
+
+	// The other fields are still fanned out
+	mover = step(400, &testpb.TestMoverMessage_Mover{Position: vec(8), Velocity: vec(20), Name: "b"})
+	if assert.NotNil(t, mover) {
+		assert.Nil(t, mover.Position)
+		assert.Nil(t, mover.Velocity)
+		assert.Equal(t, "b", mover.Name)
+	}
+
+	// Extrapolated for too long
+	mover = step(1300, &testpb.TestMoverMessage_Mover{Position: vec(26), Velocity: vec(20), Name: "b"})
+	if assert.NotNil(t, mover) {
+		assert.EqualValues(t, 26, mover.Position.X)
+	}
+}
+
+func TestDeadReckoningChannelDataEntity(t *testing.T) {
+	InitLogs()
+	InitChannels()
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		DeadReckoning: DeadReckoningSettingsType{PositionField: "position", VelocityField: "velocity", MaxError: 1},
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, nil)
+	ch.removing = 1
+	ch.InitData(&testpb.TestMoverMessage_Mover{}, nil)
+	assert.EqualValues(t, DefaultMaxExtrapolationMs, ch.Data().maxExtrapolationMs())
+
+	update := func(ms uint32, x float32) *testpb.TestMoverMessage_Mover {
+		updateMsg := &testpb.TestMoverMessage_Mover{Position: &testpb.TestVector{X: x}, Velocity: &testpb.TestVector{X: 10}}
+		ch.Data().OnUpdate(updateMsg, ChannelTime(0).AddMs(ms), 0, nil)
+		return updateMsg
+	}
+	assert.NotNil(t, update(0, 0).Position)
+	assert.Nil(t, update(500, 5).Position)
+	assert.EqualValues(t, 5, ch.GetDataMessage().(*testpb.TestMoverMessage_Mover).Position.X)
+
+	// The invalid settings disable dead reckoning
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		DeadReckoning: DeadReckoningSettingsType{PositionField: "name", VelocityField: "velocity", MaxError: 1},
+	}
+	ch.InitData(&testpb.TestMoverMessage_Mover{}, nil)
+	update(0, 0)
+	assert.NotNil(t, update(500, 5).Position)
+	assert.EqualValues(t, 0, ch.Data().maxExtrapolationMs())
+}
@@ -0,0 +1,58 @@
+package channeld
+
+import (
+	"hash/fnv"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Returns true if the delta fan-out carries the same channel data as the previous fan-out to the subscriber, so it can be
+// skipped. The fan-out that carries anything else for the subscriber (the checksum, the keyframe chain or the input ack) is
+// never skipped. See channeldpb.ChannelSubscriptionOptions.AllowDuplicateFanOut.
+//
+// As the elements of the repeated fields are appended when merging the delta, the same delta that has any of them is not a
+// duplicate, unless the channel data replaces the lists (see channeldpb.ChannelDataMergeOptions.ShouldReplaceList).
+func (foc *fanOutConnection) isDuplicateFanOut(cs *ChannelSubscription, updateMsg *channeldpb.ChannelDataUpdateMessage, keyframe bool, mergeOptions *channeldpb.ChannelDataMergeOptions) bool {
+	if updateMsg == nil || updateMsg.Data == nil || cs.options.GetAllowDuplicateFanOut() {
+		return false
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(updateMsg.Data.TypeUrl))
+	h.Write(updateMsg.Data.Value)
+	digest := h.Sum64()
+
+	duplicate := !keyframe && foc.hasFanOutDigest && digest == foc.lastFanOutDigest &&
+		updateMsg.Checksum == nil && updateMsg.PreviousVersion == nil && updateMsg.LastInputSeq == nil
+	foc.lastFanOutDigest = digest
+	foc.hasFanOutDigest = true
+
+	if duplicate && !mergeOptions.GetShouldReplaceList() {
+		data, err := updateMsg.Data.UnmarshalNew()
+		duplicate = err == nil && !hasListElements(data.ProtoReflect())
+	}
+	return duplicate
+}
+
+// Returns true if any repeated field in the message, or in its nested messages, has any element.
+func hasListElements(msg protoreflect.Message) bool {
+	found := false
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			found = v.List().Len() > 0
+		case fd.IsMap():
+			if fd.MapValue().Kind() == protoreflect.MessageKind {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					found = hasListElements(mv.Message())
+					return !found
+				})
+			}
+		case fd.Kind() == protoreflect.MessageKind:
+			found = hasListElements(v.Message())
+		}
+		return !found
+	})
+	return found
+}
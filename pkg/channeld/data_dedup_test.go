@@ -0,0 +1,103 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDuplicateFanOutSuppressed(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	owner := addTestConnection(channeldpb.ConnectionType_SERVER)
+	suppressed := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	allowed := addTestConnection(channeldpb.ConnectionType_CLIENT)
+
+	testChannel, _ := CreateChannel(channeldpb.ChannelType_TEST, owner)
+	// Stop the channel.Tick() goroutine
+	testChannel.removing = 1
+	testChannel.InitData(&testpb.TestChannelDataMessage{Text: "a", Num: 1}, nil)
+	testChannel.tickInterval = time.Hour
+
+	suppressed.SubscribeToChannel(testChannel, &channeldpb.ChannelSubscriptionOptions{FanOutIntervalMs: proto.Uint32(20)})
+	allowed.SubscribeToChannel(testChannel, &channeldpb.ChannelSubscriptionOptions{
+		FanOutIntervalMs:     proto.Uint32(20),
+		AllowDuplicateFanOut: proto.Bool(true),
+	})
+
+	// The first fan-out
+	tick := testChannel.GetTime().AddMs(20)
+	testChannel.tickData(tick)
+	assert.Equal(t, 1, len(suppressed.testQueue()))
+	assert.Equal(t, 1, len(allowed.testQueue()))
+
+	// The periodic full-state writer
+	update := func(num uint32) {
+		testChannel.Data().OnUpdate(&testpb.TestChannelDataMessage{Text: "a", Num: num}, tick.AddMs(10), owner.Id(), nil)
+		tick = tick.AddMs(20)
+		testChannel.tickData(tick)
+	}
+	update(2)
+	assert.Equal(t, 2, len(suppressed.testQueue()))
+	assert.Equal(t, 2, len(allowed.testQueue()))
+
+	// Same as the previous fan-out
+	update(2)
+	assert.Equal(t, 2, len(suppressed.testQueue()))
+	assert.Equal(t, 3, len(allowed.testQueue()))
+
+	// Changed
+	update(3)
+	assert.Equal(t, 3, len(suppressed.testQueue()))
+	assert.Equal(t, 4, len(allowed.testQueue()))
+	updateMsg, ok := suppressed.latestMsg().(*channeldpb.ChannelDataUpdateMessage)
+	if assert.True(t, ok) {
+		data, _ := updateMsg.Data.UnmarshalNew()
+		assert.EqualValues(t, 3, data.(*testpb.TestChannelDataMessage).Num)
+	}
+}
+
+func TestDuplicateFanOutWithListAppend(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	owner := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+
+	for _, shouldReplaceList := range []bool{false, true} {
+		testChannel, _ := CreateChannel(channeldpb.ChannelType_TEST, owner)
+		// Stop the channel.Tick() goroutine
+		testChannel.removing = 1
+		testChannel.InitData(&testpb.TestMergeMessage{}, &channeldpb.ChannelDataMergeOptions{ShouldReplaceList: shouldReplaceList})
+		testChannel.tickInterval = time.Hour
+
+		client.SubscribeToChannel(testChannel, &channeldpb.ChannelSubscriptionOptions{FanOutIntervalMs: proto.Uint32(20)})
+		tick := testChannel.GetTime().AddMs(20)
+		testChannel.tickData(tick)
+		sent := len(client.testQueue())
+
+		appendItem := func() {
+			testChannel.Data().OnUpdate(&testpb.TestMergeMessage{List: []string{"a"}}, tick.AddMs(10), owner.Id(), nil)
+			tick = tick.AddMs(20)
+			testChannel.tickData(tick)
+		}
+		appendItem()
+		assert.Equal(t, sent+1, len(client.testQueue()))
+
+		// The same delta appends the item again, unless the list is replaced
+		appendItem()
+		if shouldReplaceList {
+			assert.Equal(t, sent+1, len(client.testQueue()))
+		} else {
+			assert.Equal(t, sent+2, len(client.testQueue()))
+			assert.Equal(t, []string{"a", "a"}, testChannel.Data().msg.(*testpb.TestMergeMessage).List)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package channeld
+
+import (
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+)
+
+type fanOutArrival struct {
+	arrivalTime time.Time
+	channelType channeldpb.ChannelType
+}
+
+// Tracks the queued fan-out, so the latency from the update's arrival at the channel to writing the fan-out to the connection
+// can be observed in flush(). See the fan_out_latency_ms metric.
+func (c *Connection) trackFanOutLatency(mp *channeldpb.MessagePack, arrivalTime time.Time, channelType channeldpb.ChannelType) {
+	c.fanOutArrivalsLock.Lock()
+	defer c.fanOutArrivalsLock.Unlock()
+	if c.fanOutArrivals == nil {
+		c.fanOutArrivals = make(map[*channeldpb.MessagePack]fanOutArrival)
+	}
+	c.fanOutArrivals[mp] = fanOutArrival{arrivalTime: arrivalTime, channelType: channelType}
+}
+
+// Observes the latency of the tracked fan-outs in the flushed messages. Should be called in the flush goroutine.
+func (c *Connection) observeFanOutLatency(messages []*channeldpb.MessagePack, written bool) {
+	c.fanOutArrivalsLock.Lock()
+	defer c.fanOutArrivalsLock.Unlock()
+	if len(c.fanOutArrivals) == 0 {
+		return
+	}
+	now := time.Now()
+	for _, mp := range messages {
+		if mp.MsgType != uint32(channeldpb.MessageType_CHANNEL_DATA_UPDATE) {
+			continue
+		}
+		arrival, exists := c.fanOutArrivals[mp]
+		if !exists {
+			continue
+		}
+		delete(c.fanOutArrivals, mp)
+		if written {
+			fanOutLatency.WithLabelValues(arrival.channelType.String()).Observe(float64(now.Sub(arrival.arrivalTime)) / float64(time.Millisecond))
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func fanOutLatencySampleCount(channelType channeldpb.ChannelType) uint64 {
+	m := &dto.Metric{}
+	fanOutLatency.WithLabelValues(channelType.String()).(prometheus.Metric).Write(m)
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestFanOutLatency(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	client.sender = &queuedMessagePackSender{}
+
+	testChannel, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	// Stop the channel.Tick() goroutine
+	testChannel.removing = 1
+	testChannel.InitData(&testpb.TestChannelDataMessage{Text: "a"}, nil)
+	client.SubscribeToChannel(testChannel, &channeldpb.ChannelSubscriptionOptions{FanOutIntervalMs: proto.Uint32(50)})
+	channelStartTime := ChannelTime(100 * int64(time.Millisecond))
+	sampleCount := fanOutLatencySampleCount(channeldpb.ChannelType_TEST)
+
+	// The first fan-out is not an update
+	testChannel.tickData(channelStartTime)
+	assert.Len(t, client.fanOutArrivals, 0)
+	first := <-client.sendQueues[MessagePriority_ChannelData]
+
+	testChannel.Data().OnUpdate(&testpb.TestChannelDataMessage{Text: "b"}, channelStartTime.AddMs(10), server.Id(), nil)
+	testChannel.Data().OnUpdate(&testpb.TestChannelDataMessage{Num: 1}, channelStartTime.AddMs(20), server.Id(), nil)
+	testChannel.tickData(channelStartTime.AddMs(50))
+	if assert.Len(t, client.fanOutArrivals, 1) {
+		for _, arrival := range client.fanOutArrivals {
+			// The earliest merged update
+			assert.Equal(t, testChannel.startTime.Add(time.Duration(channelStartTime.AddMs(10))), arrival.arrivalTime)
+			assert.Equal(t, channeldpb.ChannelType_TEST, arrival.channelType)
+		}
+	}
+	second := <-client.sendQueues[MessagePriority_ChannelData]
+
+	client.observeFanOutLatency([]*channeldpb.MessagePack{first, second}, true)
+	assert.Len(t, client.fanOutArrivals, 0)
+	assert.Equal(t, sampleCount+1, fanOutLatencySampleCount(channeldpb.ChannelType_TEST))
+
+	// Not observed if failed to write
+	testChannel.Data().OnUpdate(&testpb.TestChannelDataMessage{Num: 2}, channelStartTime.AddMs(60), server.Id(), nil)
+	testChannel.tickData(channelStartTime.AddMs(100))
+	client.observeFanOutLatency([]*channeldpb.MessagePack{<-client.sendQueues[MessagePriority_ChannelData]}, false)
+	assert.Len(t, client.fanOutArrivals, 0)
+	assert.Equal(t, sampleCount+1, fanOutLatencySampleCount(channeldpb.ChannelType_TEST))
+}
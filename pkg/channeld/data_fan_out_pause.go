@@ -0,0 +1,105 @@
+package channeld
+
+import (
+	"errors"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+)
+
+var ErrNotSubscribedToFanOut = errors.New("the connection doesn't receive the channel data fan-out")
+
+// Returns true if the channel data fan-out to the subscriber is paused, by itself or by the whole channel.
+func (ch *Channel) isFanOutPaused(foc *fanOutConnection) bool {
+	return ch.dataFanOutPaused || foc.paused
+}
+
+// Pauses the channel data fan-out to the subscriber, or the whole channel if conn is nil. The updates are buffered and
+// coalesced until ResumeDataFanOut() is called. Should be called in the channel's goroutine.
+func (ch *Channel) PauseDataFanOut(conn ConnectionInChannel) error {
+	return ch.setDataFanOutPaused(conn, true)
+}
+
+// Resumes the channel data fan-out to the subscriber, or the whole channel if conn is nil. The next fan-out carries the
+// whole channel data, so the subscriber doesn't need the updates it missed. Should be called in the channel's goroutine.
+func (ch *Channel) ResumeDataFanOut(conn ConnectionInChannel) error {
+	return ch.setDataFanOutPaused(conn, false)
+}
+
+func (ch *Channel) setDataFanOutPaused(conn ConnectionInChannel, paused bool) error {
+	ch.connectionsLock.RLock()
+	defer ch.connectionsLock.RUnlock()
+
+	if conn == nil {
+		if ch.dataFanOutPaused == paused {
+			return nil
+		}
+		ch.dataFanOutPaused = paused
+		if !paused {
+			for e := ch.fanOutQueue.Front(); e != nil; e = e.Next() {
+				e.Value.(*fanOutConnection).hadFirstFanOut = false
+			}
+		}
+		ch.Logger().Info("set data fan-out paused", zap.Bool("paused", paused))
+		return nil
+	}
+
+	cs, exists := ch.subscribedConnections[conn]
+	if !exists || cs.fanOutElement == nil {
+		return ErrNotSubscribedToFanOut
+	}
+	foc := cs.fanOutElement.Value.(*fanOutConnection)
+	if foc.paused == paused {
+		return nil
+	}
+	foc.paused = paused
+	if !paused {
+		foc.hadFirstFanOut = false
+	}
+	ch.Logger().Debug("set data fan-out paused for subscriber",
+		zap.Uint32("connId", uint32(conn.Id())),
+		zap.Bool("paused", paused),
+	)
+	return nil
+}
+
+func handlePauseDataFanOut(ctx MessageContext) {
+	msg, ok := ctx.Msg.(*channeldpb.PauseDataFanOutMessage)
+	if !ok {
+		ctx.Connection.Logger().Error("message is not a PauseDataFanOutMessage, will not be handled.")
+		return
+	}
+
+	if ctx.Channel.ownerConnection != ctx.Connection {
+		ctx.Connection.Logger().Warn("only the channel owner can pause or resume the data fan-out",
+			zap.Uint32("channelId", uint32(ctx.Channel.id)),
+		)
+		replyError(ctx, channeldpb.ErrorMessage_ACCESS_DENIED, "only the channel owner can pause or resume the data fan-out")
+		return
+	}
+
+	var conn ConnectionInChannel
+	if msg.ConnId != 0 {
+		c := GetConnectionOfConnection(ConnectionId(msg.ConnId), ctx.Connection)
+		if c == nil {
+			replyError(ctx, channeldpb.ErrorMessage_CONNECTION_NOT_FOUND, "connection to pause or resume not found")
+			return
+		}
+		conn = c
+	}
+
+	var err error
+	if msg.Resume {
+		err = ctx.Channel.ResumeDataFanOut(conn)
+	} else {
+		err = ctx.Channel.PauseDataFanOut(conn)
+	}
+	if err != nil {
+		ctx.Connection.Logger().Warn("failed to pause or resume the data fan-out",
+			zap.Uint32("channelId", uint32(ctx.Channel.id)),
+			zap.Uint32("connId", msg.ConnId),
+			zap.Error(err),
+		)
+		replyError(ctx, channeldpb.ErrorMessage_NOT_SUBSCRIBED, err.Error())
+	}
+}
@@ -0,0 +1,88 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPauseDataFanOut(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	c0 := addTestConnection(channeldpb.ConnectionType_SERVER)
+	c1 := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	c2 := addTestConnection(channeldpb.ConnectionType_CLIENT)
+
+	testChannel, _ := CreateChannel(channeldpb.ChannelType_TEST, c0)
+	// Stop the channel.Tick() goroutine
+	testChannel.removing = 1
+	testChannel.InitData(&testpb.TestChannelDataMessage{Text: "a", Num: 1}, nil)
+	testChannel.tickInterval = time.Hour
+
+	c1.SubscribeToChannel(testChannel, &channeldpb.ChannelSubscriptionOptions{FanOutIntervalMs: proto.Uint32(50)})
+	c2.SubscribeToChannel(testChannel, &channeldpb.ChannelSubscriptionOptions{FanOutIntervalMs: proto.Uint32(50)})
+	channelStartTime := ChannelTime(100 * int64(time.Millisecond))
+	testChannel.tickData(channelStartTime)
+	assert.Len(t, c1.testQueue(), 1)
+	assert.Len(t, c2.testQueue(), 1)
+
+	pauseCtx := MessageContext{
+		MsgType:    channeldpb.MessageType_PAUSE_DATA_FAN_OUT,
+		Msg:        &channeldpb.PauseDataFanOutMessage{ConnId: uint32(c1.Id())},
+		Connection: c0,
+		Channel:    testChannel,
+	}
+	handlePauseDataFanOut(pauseCtx)
+
+	// c1 is paused while c2 keeps receiving the updates
+	testChannel.Data().OnUpdate(&testpb.TestChannelDataMessage{Num: 2}, channelStartTime.AddMs(10), c0.Id(), nil)
+	testChannel.tickData(channelStartTime.AddMs(50))
+	testChannel.Data().OnUpdate(&testpb.TestChannelDataMessage{Text: "b"}, channelStartTime.AddMs(60), c0.Id(), nil)
+	testChannel.tickData(channelStartTime.AddMs(100))
+	assert.Len(t, c1.testQueue(), 1)
+	assert.Len(t, c2.testQueue(), 3)
+
+	// The resumed fan-out is a single update with the whole channel data
+	pauseCtx.Msg = &channeldpb.PauseDataFanOutMessage{ConnId: uint32(c1.Id()), Resume: true}
+	handlePauseDataFanOut(pauseCtx)
+	testChannel.tickData(channelStartTime.AddMs(150))
+	if assert.Len(t, c1.testQueue(), 2) {
+		data := &testpb.TestChannelDataMessage{}
+		assert.NoError(t, c1.latestMsg().(*channeldpb.ChannelDataUpdateMessage).Data.UnmarshalTo(data))
+		assert.Equal(t, "b", data.Text)
+		assert.EqualValues(t, 2, data.Num)
+	}
+
+	// Pause the whole channel
+	assert.NoError(t, testChannel.PauseDataFanOut(nil))
+	testChannel.Data().OnUpdate(&testpb.TestChannelDataMessage{Num: 3}, channelStartTime.AddMs(160), c0.Id(), nil)
+	testChannel.tickData(channelStartTime.AddMs(200))
+	assert.Len(t, c1.testQueue(), 2)
+	assert.Len(t, c2.testQueue(), 3)
+	assert.NoError(t, testChannel.ResumeDataFanOut(nil))
+	testChannel.tickData(channelStartTime.AddMs(250))
+	assert.Len(t, c1.testQueue(), 3)
+	assert.Len(t, c2.testQueue(), 4)
+
+	// Only the owner can pause
+	handlePauseDataFanOut(MessageContext{
+		MsgType:    channeldpb.MessageType_PAUSE_DATA_FAN_OUT,
+		Msg:        &channeldpb.PauseDataFanOutMessage{},
+		Connection: c1,
+		Channel:    testChannel,
+	})
+	if msg, ok := c1.latestMsg().(*channeldpb.ErrorMessage); assert.True(t, ok) {
+		assert.Equal(t, channeldpb.ErrorMessage_ACCESS_DENIED, msg.Code)
+	}
+	assert.False(t, testChannel.dataFanOutPaused)
+
+	// The connection is not subscribed
+	c3 := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	assert.ErrorIs(t, testChannel.PauseDataFanOut(c3), ErrNotSubscribedToFanOut)
+}
@@ -0,0 +1,142 @@
+package channeld
+
+import (
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const (
+	DefaultFetchHistoryLimit = 20
+	MaxFetchHistoryLimit     = 100
+)
+
+// The entries truncated from the top of a list field. See ChannelSettingsType.ListHistorySize.
+type listHistory struct {
+	// The oldest first
+	entries []protoreflect.Value
+	// The number of the entries ever truncated, which is also the index of the first entry in the current list.
+	truncatedNum uint64
+}
+
+// Saves the entries that are going to be truncated by merging the update message. Should be called before merging.
+func (d *ChannelData) retainTruncatedHistory(updateMsg common.ChannelDataMessage) {
+	options := d.mergeOptions
+	if d.historySize <= 0 || options == nil || options.ListSizeLimit == 0 || !options.TruncateTop || options.ShouldReplaceList {
+		return
+	}
+
+	dst := d.msg.ProtoReflect()
+	updateMsg.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if !fd.IsList() {
+			return true
+		}
+		dstList := dst.Get(fd).List()
+		srcList := v.List()
+		overflow := dstList.Len() + srcList.Len() - int(options.ListSizeLimit)
+		if overflow <= 0 {
+			return true
+		}
+
+		if d.histories == nil {
+			d.histories = make(map[string]*listHistory)
+		}
+		history, exists := d.histories[string(fd.Name())]
+		if !exists {
+			history = &listHistory{}
+			d.histories[string(fd.Name())] = history
+		}
+		for i := 0; i < overflow; i++ {
+			var entry protoreflect.Value
+			if i < dstList.Len() {
+				entry = dstList.Get(i)
+			} else {
+				entry = srcList.Get(i - dstList.Len())
+			}
+			if fd.Kind() == protoreflect.MessageKind {
+				entry = protoreflect.ValueOfMessage(proto.Clone(entry.Message().Interface()).ProtoReflect())
+			}
+			history.entries = append(history.entries, entry)
+		}
+		history.truncatedNum += uint64(overflow)
+		if len(history.entries) > d.historySize {
+			history.entries = history.entries[len(history.entries)-d.historySize:]
+		}
+		return true
+	})
+}
+
+func handleFetchHistory(ctx MessageContext) {
+	msg, ok := ctx.Msg.(*channeldpb.FetchHistoryMessage)
+	if !ok {
+		ctx.Connection.Logger().Error("message is not a FetchHistoryMessage, will not be handled.")
+		return
+	}
+
+	if ctx.Channel.ownerConnection != ctx.Connection {
+		if _, subscribed := ctx.Channel.subscribedConnections[ctx.Connection]; !subscribed {
+			ctx.Connection.Logger().Warn("attempt to fetch history but not subscribed to the channel",
+				zap.Uint32("channelId", uint32(ctx.Channel.id)),
+			)
+			return
+		}
+	}
+
+	data := ctx.Channel.Data()
+	if data == nil || data.msg == nil {
+		ctx.Connection.Logger().Warn("channel data is not initialized, will not fetch history",
+			zap.Uint32("channelId", uint32(ctx.Channel.id)),
+		)
+		return
+	}
+	fd := data.msg.ProtoReflect().Descriptor().Fields().ByName(protoreflect.Name(msg.Field))
+	if fd == nil || !fd.IsList() {
+		ctx.Connection.Logger().Warn("can only fetch history of the list field of the channel data",
+			zap.Uint32("channelId", uint32(ctx.Channel.id)),
+			zap.String("field", msg.Field),
+		)
+		return
+	}
+
+	limit := int(msg.Limit)
+	if limit == 0 {
+		limit = DefaultFetchHistoryLimit
+	} else if limit > MaxFetchHistoryLimit {
+		limit = MaxFetchHistoryLimit
+	}
+
+	resultMsg := data.msg.ProtoReflect().New()
+	result := &channeldpb.FetchHistoryResultMessage{Field: msg.Field}
+	if history, exists := data.histories[msg.Field]; exists {
+		// The index of the first retained entry
+		firstIndex := history.truncatedNum - uint64(len(history.entries))
+		end := history.truncatedNum
+		if msg.BeforeIndex > 0 && msg.BeforeIndex < end {
+			end = msg.BeforeIndex
+		}
+		if end > firstIndex {
+			start := firstIndex
+			if end-start > uint64(limit) {
+				start = end - uint64(limit)
+			}
+			list := resultMsg.Mutable(fd).List()
+			for _, entry := range history.entries[start-firstIndex : end-firstIndex] {
+				list.Append(entry)
+			}
+			result.StartIndex = start
+			result.HasMore = start > firstIndex
+		}
+	}
+
+	any, err := anypb.New(resultMsg.Interface())
+	if err != nil {
+		ctx.Connection.Logger().Error("failed to marshal the fetched history", zap.Error(err))
+		return
+	}
+	result.Data = any
+	ctx.Msg = result
+	ctx.Connection.Send(ctx)
+}
@@ -0,0 +1,64 @@
+package channeld
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchHistory(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		ACLSettings:     ACLSettingsType{Sub: ChannelAccessLevel_Any},
+		ListHistorySize: 5,
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	ch.InitData(&testpb.TestMergeMessage{}, &channeldpb.ChannelDataMergeOptions{ListSizeLimit: 3, TruncateTop: true})
+	client.SubscribeToChannel(ch, nil)
+
+	for i := 0; i < 10; i++ {
+		ch.Data().OnUpdate(&testpb.TestMergeMessage{List: []string{fmt.Sprint(i)}}, ch.GetTime(), server.Id(), nil)
+	}
+	assert.Equal(t, []string{"7", "8", "9"}, ch.GetDataMessage().(*testpb.TestMergeMessage).List)
+
+	fetch := func(beforeIndex uint64, limit uint32) (*channeldpb.FetchHistoryResultMessage, []string) {
+		handleFetchHistory(MessageContext{
+			MsgType:    channeldpb.MessageType_FETCH_HISTORY,
+			Msg:        &channeldpb.FetchHistoryMessage{Field: "list", BeforeIndex: beforeIndex, Limit: limit},
+			Connection: client,
+			Channel:    ch,
+		})
+		result, ok := client.latestMsg().(*channeldpb.FetchHistoryResultMessage)
+		if !assert.True(t, ok) {
+			return nil, nil
+		}
+		data := &testpb.TestMergeMessage{}
+		assert.NoError(t, result.Data.UnmarshalTo(data))
+		return result, data.List
+	}
+
+	// 0-6 are truncated, and only 2-6 are retained
+	result, list := fetch(0, 3)
+	assert.Equal(t, []string{"4", "5", "6"}, list)
+	assert.EqualValues(t, 4, result.StartIndex)
+	assert.True(t, result.HasMore)
+
+	result, list = fetch(result.StartIndex, 3)
+	assert.Equal(t, []string{"2", "3"}, list)
+	assert.EqualValues(t, 2, result.StartIndex)
+	assert.False(t, result.HasMore)
+
+	result, list = fetch(2, 3)
+	assert.Empty(t, list)
+	assert.False(t, result.HasMore)
+}
@@ -0,0 +1,34 @@
+package channeld
+
+import (
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"google.golang.org/protobuf/proto"
+)
+
+// Records the sequence of the client's input that has been incorporated in the channel data. The sequence never goes back,
+// as the inputs can be processed out of order by different servers. See channeldpb.ChannelDataUpdateMessage.InputSeq.
+func (d *ChannelData) recordInputSeq(connId ConnectionId, seq uint64) {
+	if connId == 0 || seq == 0 {
+		return
+	}
+	if d.inputSeqs == nil {
+		d.inputSeqs = make(map[ConnectionId]uint64)
+	}
+	if seq > d.inputSeqs[connId] {
+		d.inputSeqs[connId] = seq
+	}
+}
+
+// Returns the update message that echoes the last input sequence of the client. The updateMsg is not modified, as it can be shared by the fan-out group.
+func (d *ChannelData) withInputAck(conn ConnectionInChannel, updateMsg *channeldpb.ChannelDataUpdateMessage) *channeldpb.ChannelDataUpdateMessage {
+	if updateMsg == nil || conn.GetConnectionType() != channeldpb.ConnectionType_CLIENT {
+		return updateMsg
+	}
+	seq, exists := d.inputSeqs[conn.Id()]
+	if !exists {
+		return updateMsg
+	}
+	msg := cloneUpdateEnvelope(updateMsg)
+	msg.LastInputSeq = proto.Uint64(seq)
+	return msg
+}
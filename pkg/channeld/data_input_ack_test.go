@@ -0,0 +1,70 @@
+package channeld
+
+import (
+	"testing"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestInputAck(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client1 := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	client2 := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	ch.removing = 1
+	ch.InitData(&testpb.TestChannelDataMessage{}, nil)
+	subOptions := &channeldpb.ChannelSubscriptionOptions{FanOutIntervalMs: proto.Uint32(50)}
+	client1.SubscribeToChannel(ch, subOptions)
+	client2.SubscribeToChannel(ch, subOptions)
+
+	// The server has processed client1's input
+	update := func(ms uint32, text string, inputSeq uint64) {
+		any, _ := anypb.New(&testpb.TestChannelDataMessage{Text: text})
+		handleChannelDataUpdate(MessageContext{
+			MsgType:     channeldpb.MessageType_CHANNEL_DATA_UPDATE,
+			Msg:         &channeldpb.ChannelDataUpdateMessage{Data: any, ContextConnId: uint32(client1.Id()), InputSeq: inputSeq},
+			Connection:  server,
+			Channel:     ch,
+			arrivalTime: ch.GetTime().AddMs(ms),
+		})
+	}
+	latestUpdate := func(c *Connection) *channeldpb.ChannelDataUpdateMessage {
+		updateMsg, _ := c.latestMsg().(*channeldpb.ChannelDataUpdateMessage)
+		return updateMsg
+	}
+
+	update(50, "a", 5)
+	ch.tickData(ch.GetTime().AddMs(100))
+	if updateMsg := latestUpdate(client1); assert.NotNil(t, updateMsg) {
+		assert.EqualValues(t, 5, updateMsg.GetLastInputSeq())
+	}
+	// Only echoed to the client that sent the input
+	if updateMsg := latestUpdate(client2); assert.NotNil(t, updateMsg) {
+		assert.Nil(t, updateMsg.LastInputSeq)
+	}
+
+	// The sequence never goes back
+	update(150, "b", 3)
+	ch.tickData(ch.GetTime().AddMs(200))
+	if updateMsg := latestUpdate(client1); assert.NotNil(t, updateMsg) {
+		assert.EqualValues(t, 2, updateMsg.Version)
+		assert.EqualValues(t, 5, updateMsg.GetLastInputSeq())
+	}
+
+	// The client's own input
+	ch.Data().recordInputSeq(client2.Id(), 9)
+	update(250, "c", 0)
+	ch.tickData(ch.GetTime().AddMs(300))
+	assert.EqualValues(t, 9, latestUpdate(client2).GetLastInputSeq())
+
+	client1.UnsubscribeFromChannel(ch)
+	assert.NotContains(t, ch.Data().inputSeqs, client1.Id())
+}
@@ -0,0 +1,33 @@
+package channeld
+
+import (
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"google.golang.org/protobuf/proto"
+)
+
+// Returns true if the next fan-out to the subscriber should carry the full states.
+// See channeldpb.ChannelSubscriptionOptions.KeyframeIntervalMs.
+func (foc *fanOutConnection) isKeyframeDue(cs *ChannelSubscription, t ChannelTime) bool {
+	if foc.keyframeOnly {
+		return true
+	}
+	interval := cs.options.GetKeyframeIntervalMs()
+	return interval > 0 && foc.hadFirstFanOut && t >= foc.lastKeyframeTime.AddMs(interval)
+}
+
+// Returns the fan-out update message with the keyframe fields. As the update message can be shared by the fan-out group,
+// the fields are set in a copy. See channeldpb.ChannelDataUpdateMessage.Keyframe.
+func (foc *fanOutConnection) withKeyframe(updateMsg *channeldpb.ChannelDataUpdateMessage, keyframe bool, previousVersion uint64) *channeldpb.ChannelDataUpdateMessage {
+	msg := cloneUpdateEnvelope(updateMsg)
+	msg.Keyframe = keyframe
+	if keyframe {
+		msg.KeyframeVersion = proto.Uint64(updateMsg.Version)
+		// The keyframe replaces the whole channel data
+		msg.PreviousVersion = nil
+		msg.UpdatedFields = nil
+	} else {
+		msg.KeyframeVersion = proto.Uint64(foc.keyframeVersion)
+		msg.PreviousVersion = proto.Uint64(previousVersion)
+	}
+	return msg
+}
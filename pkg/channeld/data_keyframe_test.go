@@ -0,0 +1,75 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestFanOutKeyframe(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	c0 := addTestConnection(channeldpb.ConnectionType_SERVER)
+	c1 := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	c2 := addTestConnection(channeldpb.ConnectionType_CLIENT)
+
+	testChannel, _ := CreateChannel(channeldpb.ChannelType_TEST, c0)
+	// Stop the channel.Tick() goroutine
+	testChannel.removing = 1
+	testChannel.InitData(&testpb.TestChannelDataMessage{Text: "a", Num: 1}, nil)
+	testChannel.tickInterval = time.Hour
+
+	c1.SubscribeToChannel(testChannel, &channeldpb.ChannelSubscriptionOptions{FanOutIntervalMs: proto.Uint32(50), KeyframeIntervalMs: proto.Uint32(200)})
+	// Not in the keyframe mode
+	c2.SubscribeToChannel(testChannel, &channeldpb.ChannelSubscriptionOptions{FanOutIntervalMs: proto.Uint32(50)})
+	latestUpdate := func(c *Connection) *channeldpb.ChannelDataUpdateMessage {
+		return c.latestMsg().(*channeldpb.ChannelDataUpdateMessage)
+	}
+	latestData := func(c *Connection) *testpb.TestChannelDataMessage {
+		dataMsg, _ := latestUpdate(c).Data.UnmarshalNew()
+		return dataMsg.(*testpb.TestChannelDataMessage)
+	}
+
+	// The first fan-out is a keyframe
+	tick := ChannelTime(100 * int64(time.Millisecond))
+	testChannel.tickData(tick)
+	assert.True(t, latestUpdate(c1).Keyframe)
+	assert.EqualValues(t, 0, *latestUpdate(c1).KeyframeVersion)
+	assert.Nil(t, latestUpdate(c1).PreviousVersion)
+	assert.False(t, latestUpdate(c2).Keyframe)
+	assert.Nil(t, latestUpdate(c2).KeyframeVersion)
+
+	// The deltas reference the keyframe
+	testChannel.Data().OnUpdate(&testpb.TestChannelDataMessage{Num: 2}, tick.AddMs(10), c0.Id(), nil)
+	tick = tick.AddMs(50)
+	testChannel.tickData(tick)
+	assert.False(t, latestUpdate(c1).Keyframe)
+	assert.EqualValues(t, 0, *latestUpdate(c1).KeyframeVersion)
+	assert.EqualValues(t, 0, *latestUpdate(c1).PreviousVersion)
+	assert.EqualValues(t, 1, latestUpdate(c1).Version)
+	assert.True(t, proto.Equal(&testpb.TestChannelDataMessage{Num: 2}, latestData(c1)))
+
+	testChannel.Data().OnUpdate(&testpb.TestChannelDataMessage{Text: "b"}, tick.AddMs(10), c0.Id(), nil)
+	tick = tick.AddMs(50)
+	testChannel.tickData(tick)
+	assert.False(t, latestUpdate(c1).Keyframe)
+	assert.EqualValues(t, 1, *latestUpdate(c1).PreviousVersion)
+	assert.EqualValues(t, 2, latestUpdate(c1).Version)
+
+	// The keyframe interval has elapsed
+	testChannel.Data().OnUpdate(&testpb.TestChannelDataMessage{Num: 3}, tick.AddMs(10), c0.Id(), nil)
+	tick = tick.AddMs(100)
+	testChannel.tickData(tick)
+	assert.True(t, latestUpdate(c1).Keyframe)
+	assert.EqualValues(t, 3, *latestUpdate(c1).KeyframeVersion)
+	assert.True(t, proto.Equal(&testpb.TestChannelDataMessage{Text: "b", Num: 3}, latestData(c1)))
+	// The subscriber without the keyframe mode still receives the delta
+	assert.False(t, latestUpdate(c2).Keyframe)
+	assert.True(t, proto.Equal(&testpb.TestChannelDataMessage{Num: 3}, latestData(c2)))
+}
@@ -0,0 +1,320 @@
+package channeld
+
+import (
+	"sort"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const (
+	DefaultFetchLeaderboardLimit = 20
+	MaxFetchLeaderboardLimit     = 100
+)
+
+type leaderboardEntry struct {
+	key   string
+	mk    protoreflect.MapKey
+	score float64
+}
+
+// The sorted index of the leaderboard map field. See ChannelSettingsType.Leaderboard.
+type leaderboard struct {
+	settings LeaderboardSettingsType
+	// Resolved from the channel data message. See resolve().
+	fd      protoreflect.FieldDescriptor
+	scoreFd protoreflect.FieldDescriptor
+	invalid bool
+	// The channel data message that the index is built from. The index is rebuilt when the message is replaced,
+	// e.g. restored from the snapshot.
+	msg common.ChannelDataMessage
+	// In the order of the rank
+	entries []leaderboardEntry
+	// The score of each entry in the index, by the key
+	scores map[string]float64
+}
+
+func isNumericKind(kind protoreflect.Kind) bool {
+	switch kind {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind,
+		protoreflect.FloatKind, protoreflect.DoubleKind:
+		return true
+	}
+	return false
+}
+
+func numericValue(kind protoreflect.Kind, v protoreflect.Value) float64 {
+	switch kind {
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return float64(v.Uint())
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return v.Float()
+	default:
+		return float64(v.Int())
+	}
+}
+
+// Resolves the fields of the settings in the channel data message. Returns false if the settings are invalid.
+func (lb *leaderboard) resolve(msg common.ChannelDataMessage) bool {
+	if lb.invalid {
+		return false
+	}
+	if lb.fd != nil {
+		return true
+	}
+	lb.invalid = true
+	fd := msg.ProtoReflect().Descriptor().Fields().ByName(protoreflect.Name(lb.settings.MapField))
+	if fd == nil || !fd.IsMap() {
+		rootLogger.Error("the leaderboard field is not a map field of the channel data",
+			zap.String("msgName", string(msg.ProtoReflect().Descriptor().FullName())),
+			zap.String("mapField", lb.settings.MapField),
+		)
+		return false
+	}
+	if lb.settings.ScoreField == "" {
+		if !isNumericKind(fd.MapValue().Kind()) {
+			rootLogger.Error("the leaderboard map value is not numeric, and no score field is specified", zap.String("mapField", lb.settings.MapField))
+			return false
+		}
+	} else {
+		if fd.MapValue().Kind() != protoreflect.MessageKind {
+			rootLogger.Error("the leaderboard map value is not a message", zap.String("mapField", lb.settings.MapField))
+			return false
+		}
+		lb.scoreFd = fd.MapValue().Message().Fields().ByName(protoreflect.Name(lb.settings.ScoreField))
+		if lb.scoreFd == nil || lb.scoreFd.IsList() || !isNumericKind(lb.scoreFd.Kind()) {
+			rootLogger.Error("the leaderboard score field is not a numeric field of the map value",
+				zap.String("mapField", lb.settings.MapField),
+				zap.String("scoreField", lb.settings.ScoreField),
+			)
+			return false
+		}
+	}
+	lb.fd = fd
+	lb.invalid = false
+	return true
+}
+
+func (lb *leaderboard) scoreOf(v protoreflect.Value) float64 {
+	if lb.scoreFd != nil {
+		return numericValue(lb.scoreFd.Kind(), v.Message().Get(lb.scoreFd))
+	}
+	return numericValue(lb.fd.MapValue().Kind(), v)
+}
+
+func (lb *leaderboard) less(a, b *leaderboardEntry) bool {
+	if a.score != b.score {
+		if lb.settings.Ascending {
+			return a.score < b.score
+		}
+		return a.score > b.score
+	}
+	return a.key < b.key
+}
+
+// Returns the index of the first entry that doesn't rank higher than e.
+func (lb *leaderboard) search(e *leaderboardEntry) int {
+	return sort.Search(len(lb.entries), func(i int) bool {
+		return !lb.less(&lb.entries[i], e)
+	})
+}
+
+// Returns -1 if the key is not in the leaderboard.
+func (lb *leaderboard) rank(key string) int {
+	score, exists := lb.scores[key]
+	if !exists {
+		return -1
+	}
+	return lb.search(&leaderboardEntry{key: key, score: score})
+}
+
+func (lb *leaderboard) remove(key string) {
+	i := lb.rank(key)
+	if i < 0 {
+		return
+	}
+	lb.entries = append(lb.entries[:i], lb.entries[i+1:]...)
+	delete(lb.scores, key)
+}
+
+func (lb *leaderboard) insert(e leaderboardEntry) {
+	i := lb.search(&e)
+	lb.entries = append(lb.entries, leaderboardEntry{})
+	copy(lb.entries[i+1:], lb.entries[i:])
+	lb.entries[i] = e
+	lb.scores[e.key] = e.score
+}
+
+// Removes the lowest ranked entries from the map field, if there are more than TopN.
+func (lb *leaderboard) trim(m protoreflect.Map) {
+	if lb.settings.TopN <= 0 {
+		return
+	}
+	for len(lb.entries) > lb.settings.TopN {
+		last := lb.entries[len(lb.entries)-1]
+		m.Clear(last.mk)
+		delete(lb.scores, last.key)
+		lb.entries = lb.entries[:len(lb.entries)-1]
+	}
+}
+
+func (lb *leaderboard) rebuild(msg common.ChannelDataMessage) {
+	m := msg.ProtoReflect().Get(lb.fd).Map()
+	lb.msg = msg
+	lb.entries = make([]leaderboardEntry, 0, m.Len())
+	lb.scores = make(map[string]float64, m.Len())
+	m.Range(func(mk protoreflect.MapKey, v protoreflect.Value) bool {
+		e := leaderboardEntry{key: mk.String(), mk: mk, score: lb.scoreOf(v)}
+		lb.entries = append(lb.entries, e)
+		lb.scores[e.key] = e.score
+		return true
+	})
+	sort.Slice(lb.entries, func(i, j int) bool {
+		return lb.less(&lb.entries[i], &lb.entries[j])
+	})
+	if lb.settings.TopN > 0 && len(lb.entries) > lb.settings.TopN {
+		lb.trim(msg.ProtoReflect().Mutable(lb.fd).Map())
+	}
+}
+
+// Returns nil if the channel has no valid leaderboard. The index is rebuilt if the channel data message has been replaced.
+func (d *ChannelData) leaderboardIndex() (lb *leaderboard, rebuilt bool) {
+	if d.leaderboard == nil || d.msg == nil || !d.leaderboard.resolve(d.msg) {
+		return nil, false
+	}
+	if d.leaderboard.msg != d.msg {
+		d.leaderboard.rebuild(d.msg)
+		return d.leaderboard, true
+	}
+	return d.leaderboard, false
+}
+
+// Re-sorts the entries changed by the update message. Should be called after the update message is merged.
+func (d *ChannelData) updateLeaderboard(updateMsg common.ChannelDataMessage) {
+	lb, rebuilt := d.leaderboardIndex()
+	if lb == nil || rebuilt || !updateMsg.ProtoReflect().Has(lb.fd) {
+		return
+	}
+	m := d.msg.ProtoReflect().Mutable(lb.fd).Map()
+	updateMsg.ProtoReflect().Get(lb.fd).Map().Range(func(mk protoreflect.MapKey, _ protoreflect.Value) bool {
+		key := mk.String()
+		lb.remove(key)
+		if m.Has(mk) {
+			lb.insert(leaderboardEntry{key: key, mk: mk, score: lb.scoreOf(m.Get(mk))})
+		}
+		return true
+	})
+	lb.trim(m)
+}
+
+// Returns the message without the leaderboard map field, as the field is not fanned out. The returned message can share
+// the fields with the original one, so it should not be modified.
+func (d *ChannelData) withoutLeaderboard(msg common.ChannelDataMessage) common.ChannelDataMessage {
+	lb, _ := d.leaderboardIndex()
+	if lb == nil || !msg.ProtoReflect().Has(lb.fd) {
+		return msg
+	}
+	src := msg.ProtoReflect()
+	dst := src.New()
+	src.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd != lb.fd {
+			dst.Set(fd, v)
+		}
+		return true
+	})
+	return dst.Interface()
+}
+
+func handleFetchLeaderboard(ctx MessageContext) {
+	msg, ok := ctx.Msg.(*channeldpb.FetchLeaderboardMessage)
+	if !ok {
+		ctx.Connection.Logger().Error("message is not a FetchLeaderboardMessage, will not be handled.")
+		return
+	}
+
+	if ctx.Channel.ownerConnection != ctx.Connection {
+		if _, subscribed := ctx.Channel.subscribedConnections[ctx.Connection]; !subscribed {
+			ctx.Connection.Logger().Warn("attempt to fetch leaderboard but not subscribed to the channel",
+				zap.Uint32("channelId", uint32(ctx.Channel.id)),
+			)
+			return
+		}
+	}
+
+	data := ctx.Channel.Data()
+	if data == nil {
+		ctx.Connection.Logger().Warn("channel data is not initialized, will not fetch leaderboard",
+			zap.Uint32("channelId", uint32(ctx.Channel.id)),
+		)
+		return
+	}
+	lb, _ := data.leaderboardIndex()
+	if lb == nil {
+		ctx.Connection.Logger().Warn("channel has no leaderboard",
+			zap.Uint32("channelId", uint32(ctx.Channel.id)),
+		)
+		return
+	}
+
+	result := &channeldpb.FetchLeaderboardResultMessage{Total: uint32(len(lb.entries)), KeyRank: -1}
+	key := msg.AroundKey
+	if msg.AroundSelf {
+		key, _ = ctx.Connection.GetTag(UserIdTag)
+	}
+
+	start, end := 0, 0
+	if key != "" {
+		if rank := lb.rank(key); rank >= 0 {
+			before := int(msg.Before)
+			if before > MaxFetchLeaderboardLimit-1 {
+				before = MaxFetchLeaderboardLimit - 1
+			}
+			after := int(msg.After)
+			if after > MaxFetchLeaderboardLimit-1-before {
+				after = MaxFetchLeaderboardLimit - 1 - before
+			}
+			result.KeyRank = int32(rank)
+			start, end = rank-before, rank+after+1
+		}
+	} else {
+		limit := int(msg.Limit)
+		if limit == 0 {
+			limit = DefaultFetchLeaderboardLimit
+		} else if limit > MaxFetchLeaderboardLimit {
+			limit = MaxFetchLeaderboardLimit
+		}
+		start = int(msg.StartRank)
+		end = start + limit
+	}
+	if start < 0 {
+		start = 0
+	}
+	if end > len(lb.entries) {
+		end = len(lb.entries)
+	}
+
+	resultMsg := data.msg.ProtoReflect().New()
+	if start < end {
+		src := data.msg.ProtoReflect().Get(lb.fd).Map()
+		dst := resultMsg.Mutable(lb.fd).Map()
+		for _, e := range lb.entries[start:end] {
+			dst.Set(e.mk, src.Get(e.mk))
+			result.Keys = append(result.Keys, e.key)
+		}
+		result.StartRank = uint32(start)
+	}
+
+	any, err := anypb.New(resultMsg.Interface())
+	if err != nil {
+		ctx.Connection.Logger().Error("failed to marshal the fetched leaderboard", zap.Error(err))
+		return
+	}
+	result.Data = any
+	ctx.Msg = result
+	ctx.Connection.Send(ctx)
+}
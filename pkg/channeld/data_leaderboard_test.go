@@ -0,0 +1,138 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestLeaderboard(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		Leaderboard: LeaderboardSettingsType{MapField: "kv2", ScoreField: "num", TopN: 3},
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	client.SetTag(UserIdTag, "1")
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	ch.removing = 1
+	ch.InitData(&testpb.TestMapMessage{}, nil)
+	client.SubscribeToChannel(ch, &channeldpb.ChannelSubscriptionOptions{FanOutIntervalMs: proto.Uint32(50)})
+
+	score := func(num int64) *testpb.TestMapMessage_StringWrapper {
+		return &testpb.TestMapMessage_StringWrapper{Num: num}
+	}
+	ranks := func() []string {
+		var keys []string
+		for _, e := range ch.Data().leaderboard.entries {
+			keys = append(keys, e.key)
+		}
+		return keys
+	}
+
+	ch.Data().OnUpdate(&testpb.TestMapMessage{
+		Kv:  map[uint32]string{1: "a"},
+		Kv2: map[uint32]*testpb.TestMapMessage_StringWrapper{1: score(10), 2: score(30), 3: score(20), 4: score(5)},
+	}, ch.GetTime(), server.Id(), nil)
+	assert.Equal(t, []string{"2", "3", "1"}, ranks())
+	// The lowest ranked entry is removed from the channel data
+	assert.NotContains(t, ch.GetDataMessage().(*testpb.TestMapMessage).Kv2, uint32(4))
+
+	ch.Data().OnUpdate(&testpb.TestMapMessage{
+		Kv2: map[uint32]*testpb.TestMapMessage_StringWrapper{1: score(40)},
+	}, ch.GetTime(), server.Id(), nil)
+	assert.Equal(t, []string{"1", "2", "3"}, ranks())
+	// The ties are ranked by the key
+	ch.Data().OnUpdate(&testpb.TestMapMessage{
+		Kv2: map[uint32]*testpb.TestMapMessage_StringWrapper{5: score(20)},
+	}, ch.GetTime(), server.Id(), nil)
+	assert.Equal(t, []string{"1", "2", "3"}, ranks())
+
+	// The leaderboard field is not fanned out
+	ch.tickData(ch.GetTime().AddMs(100))
+	if updateMsg, ok := client.latestMsg().(*channeldpb.ChannelDataUpdateMessage); assert.True(t, ok) {
+		data := &testpb.TestMapMessage{}
+		assert.NoError(t, updateMsg.Data.UnmarshalTo(data))
+		assert.Equal(t, "a", data.Kv[1])
+		assert.Empty(t, data.Kv2)
+	}
+
+	fetch := func(msg *channeldpb.FetchLeaderboardMessage) (*channeldpb.FetchLeaderboardResultMessage, *testpb.TestMapMessage) {
+		handleFetchLeaderboard(MessageContext{
+			MsgType:    channeldpb.MessageType_FETCH_LEADERBOARD,
+			Msg:        msg,
+			Connection: client,
+			Channel:    ch,
+		})
+		result, ok := client.latestMsg().(*channeldpb.FetchLeaderboardResultMessage)
+		if !assert.True(t, ok) {
+			return nil, nil
+		}
+		data := &testpb.TestMapMessage{}
+		assert.NoError(t, result.Data.UnmarshalTo(data))
+		return result, data
+	}
+
+	result, data := fetch(&channeldpb.FetchLeaderboardMessage{StartRank: 1, Limit: 5})
+	assert.Equal(t, []string{"2", "3"}, result.Keys)
+	assert.EqualValues(t, 1, result.StartRank)
+	assert.EqualValues(t, 3, result.Total)
+	assert.EqualValues(t, -1, result.KeyRank)
+	assert.Len(t, data.Kv2, 2)
+	assert.EqualValues(t, 30, data.Kv2[2].Num)
+	assert.Empty(t, data.Kv)
+
+	result, _ = fetch(&channeldpb.FetchLeaderboardMessage{AroundKey: "3", Before: 1, After: 1})
+	assert.Equal(t, []string{"2", "3"}, result.Keys)
+	assert.EqualValues(t, 2, result.KeyRank)
+
+	result, _ = fetch(&channeldpb.FetchLeaderboardMessage{AroundSelf: true, After: 1})
+	assert.Equal(t, []string{"1", "2"}, result.Keys)
+	assert.EqualValues(t, 0, result.KeyRank)
+	assert.EqualValues(t, 0, result.StartRank)
+
+	result, _ = fetch(&channeldpb.FetchLeaderboardMessage{AroundKey: "4"})
+	assert.Empty(t, result.Keys)
+	assert.EqualValues(t, -1, result.KeyRank)
+}
+
+func TestLeaderboardAscending(t *testing.T) {
+	InitLogs()
+	InitChannels()
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		Leaderboard: LeaderboardSettingsType{MapField: "kv2", Ascending: true},
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, nil)
+	ch.removing = 1
+	// The index is built from the initial channel data
+	ch.InitData(&testpb.TestGeneratedMergeMessage{Kv2: map[string]uint32{"a": 30, "b": 10, "c": 20}}, nil)
+	lb, _ := ch.Data().leaderboardIndex()
+	if assert.NotNil(t, lb) {
+		assert.Equal(t, 0, lb.rank("b"))
+		assert.Equal(t, 2, lb.rank("a"))
+	}
+
+	ch.Data().OnUpdate(&testpb.TestGeneratedMergeMessage{Kv2: map[string]uint32{"a": 5}}, ChannelTime(time.Millisecond), 0, nil)
+	assert.Equal(t, 0, lb.rank("a"))
+	assert.Equal(t, 2, lb.rank("c"))
+
+	// The invalid settings disable the leaderboard
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		Leaderboard: LeaderboardSettingsType{MapField: "name"},
+	}
+	ch.InitData(&testpb.TestGeneratedMergeMessage{}, nil)
+	lb, _ = ch.Data().leaderboardIndex()
+	assert.Nil(t, lb)
+}
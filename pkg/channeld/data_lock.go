@@ -0,0 +1,153 @@
+package channeld
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	DefaultDataLockExpireMs = 5000
+	MaxDataLockExpireMs     = 60000
+)
+
+// The exclusive write access to a map entry in the channel data. See channeldpb.ChannelDataLockMessage.
+type dataLock struct {
+	holderConnId ConnectionId
+	expireTime   ChannelTime
+}
+
+func (l *dataLock) isValid(t ChannelTime) bool {
+	if t >= l.expireTime {
+		return false
+	}
+	holder := GetConnection(l.holderConnId)
+	return holder != nil && !holder.IsClosing()
+}
+
+// The key of a map entry in the text form, e.g. 'players[1001]'.
+func mapEntryKey(field string, mk protoreflect.MapKey) string {
+	return fmt.Sprintf("%s[%v]", field, mk.Interface())
+}
+
+// Returns the valid lock of the map entry, or nil if it's not locked.
+func (d *ChannelData) getLock(entryKey string, t ChannelTime) *dataLock {
+	lock, exists := d.locks[entryKey]
+	if !exists {
+		return nil
+	}
+	if !lock.isValid(t) {
+		delete(d.locks, entryKey)
+		return nil
+	}
+	return lock
+}
+
+// Removes the map entries locked by other connections from the update message, before it's merged.
+func (d *ChannelData) dropLockedEntries(updateMsg common.ChannelDataMessage, t ChannelTime, senderConnId ConnectionId) {
+	if len(d.locks) == 0 {
+		return
+	}
+	m := updateMsg.ProtoReflect()
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if !fd.IsMap() || !m.Has(fd) {
+			continue
+		}
+		var lockedKeys []protoreflect.MapKey
+		m.Get(fd).Map().Range(func(mk protoreflect.MapKey, _ protoreflect.Value) bool {
+			if lock := d.getLock(mapEntryKey(string(fd.Name()), mk), t); lock != nil && lock.holderConnId != senderConnId {
+				lockedKeys = append(lockedKeys, mk)
+			}
+			return true
+		})
+		for _, mk := range lockedKeys {
+			m.Mutable(fd).Map().Clear(mk)
+			rootLogger.Debug("dropped update of locked map entry",
+				zap.Uint32("senderConnId", uint32(senderConnId)),
+				zap.String("entry", mapEntryKey(string(fd.Name()), mk)),
+			)
+		}
+	}
+}
+
+func handleChannelDataLock(ctx MessageContext) {
+	msg, ok := ctx.Msg.(*channeldpb.ChannelDataLockMessage)
+	if !ok {
+		ctx.Connection.Logger().Error("message is not a ChannelDataLockMessage, will not be handled.")
+		return
+	}
+
+	// Same as handleChannelDataUpdate()
+	if ctx.Channel.ownerConnection != ctx.Connection {
+		cs := ctx.Channel.subscribedConnections[ctx.Connection]
+		if cs == nil || *cs.options.DataAccess != channeldpb.ChannelDataAccess_WRITE_ACCESS {
+			ctx.Connection.Logger().Warn("attempt to lock channel data but has no access",
+				zap.String("channelType", ctx.Channel.channelType.String()),
+				zap.Uint32("channelId", uint32(ctx.Channel.id)),
+			)
+			return
+		}
+	}
+
+	data := ctx.Channel.Data()
+	if data == nil || data.msg == nil {
+		ctx.Connection.Logger().Warn("channel data is not initialized, will not be locked",
+			zap.Uint32("channelId", uint32(ctx.Channel.id)),
+		)
+		return
+	}
+	fd := data.msg.ProtoReflect().Descriptor().Fields().ByName(protoreflect.Name(msg.Field))
+	if fd == nil || !fd.IsMap() {
+		ctx.Connection.Logger().Warn("can only lock the map field of the channel data",
+			zap.Uint32("channelId", uint32(ctx.Channel.id)),
+			zap.String("field", msg.Field),
+		)
+		return
+	}
+
+	// Ordered with the updates of the other writers. See ChannelData.queueUpdate().
+	data.queueUpdate(ctx.Connection.Id(), func() {
+		t := ctx.Channel.GetTime()
+		entryKey := fmt.Sprintf("%s[%s]", msg.Field, msg.Key)
+		lock := data.getLock(entryKey, t)
+		result := &channeldpb.ChannelDataLockResultMessage{
+			Field:  msg.Field,
+			Key:    msg.Key,
+			Unlock: msg.Unlock,
+		}
+
+		if msg.Unlock {
+			if lock != nil && lock.holderConnId == ctx.Connection.Id() {
+				delete(data.locks, entryKey)
+				lock = nil
+				result.Success = true
+			}
+		} else if lock == nil || lock.holderConnId == ctx.Connection.Id() {
+			expireMs := msg.ExpireMs
+			if expireMs == 0 {
+				expireMs = DefaultDataLockExpireMs
+			} else if expireMs > MaxDataLockExpireMs {
+				expireMs = MaxDataLockExpireMs
+			}
+			lock = &dataLock{holderConnId: ctx.Connection.Id(), expireTime: t.AddMs(expireMs)}
+			if data.locks == nil {
+				data.locks = make(map[string]*dataLock)
+			}
+			data.locks[entryKey] = lock
+			result.Success = true
+		}
+
+		if lock != nil {
+			result.HolderConnId = uint32(lock.holderConnId)
+			result.ExpireTimeMs = int64(time.Duration(lock.expireTime) / time.Millisecond)
+		}
+		ctx.Msg = result
+		ctx.Connection.Send(ctx)
+	})
+}
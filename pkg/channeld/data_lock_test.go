@@ -0,0 +1,90 @@
+package channeld
+
+import (
+	"testing"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestChannelDataLock(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		ACLSettings: ACLSettingsType{Sub: ChannelAccessLevel_Any},
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	serverA := addTestConnection(channeldpb.ConnectionType_SERVER)
+	serverB := addTestConnection(channeldpb.ConnectionType_SERVER)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, serverA)
+	ch.InitData(&testpb.TestMapMessage{Kv: map[uint32]string{}}, nil)
+	serverB.SubscribeToChannel(ch, &channeldpb.ChannelSubscriptionOptions{DataAccess: channeldpb.ChannelDataAccess_WRITE_ACCESS.Enum()})
+
+	lock := func(conn *Connection, msg *channeldpb.ChannelDataLockMessage) *channeldpb.ChannelDataLockResultMessage {
+		handleChannelDataLock(MessageContext{
+			MsgType:    channeldpb.MessageType_CHANNEL_DATA_LOCK,
+			Msg:        msg,
+			Connection: conn,
+			Channel:    ch,
+		})
+		result, _ := conn.latestMsg().(*channeldpb.ChannelDataLockResultMessage)
+		return result
+	}
+	update := func(conn *Connection, kv map[uint32]string) {
+		any, _ := anypb.New(&testpb.TestMapMessage{Kv: kv})
+		handleChannelDataUpdate(MessageContext{
+			MsgType:     channeldpb.MessageType_CHANNEL_DATA_UPDATE,
+			Msg:         &channeldpb.ChannelDataUpdateMessage{Data: any},
+			Connection:  conn,
+			Channel:     ch,
+			arrivalTime: ch.GetTime(),
+		})
+	}
+	data := func() map[uint32]string {
+		return ch.GetDataMessage().(*testpb.TestMapMessage).Kv
+	}
+
+	result := lock(serverA, &channeldpb.ChannelDataLockMessage{Field: "kv", Key: "1"})
+	if assert.NotNil(t, result) {
+		assert.True(t, result.Success)
+		assert.EqualValues(t, serverA.Id(), result.HolderConnId)
+	}
+
+	// The locked entry is dropped from B's update, but the other entry is merged
+	update(serverB, map[uint32]string{1: "b", 2: "b"})
+	assert.NotContains(t, data(), uint32(1))
+	assert.Equal(t, "b", data()[2])
+	update(serverA, map[uint32]string{1: "a"})
+	assert.Equal(t, "a", data()[1])
+
+	// Already locked by A
+	result = lock(serverB, &channeldpb.ChannelDataLockMessage{Field: "kv", Key: "1"})
+	if assert.NotNil(t, result) {
+		assert.False(t, result.Success)
+		assert.EqualValues(t, serverA.Id(), result.HolderConnId)
+	}
+	// Only the holder can unlock
+	result = lock(serverB, &channeldpb.ChannelDataLockMessage{Field: "kv", Key: "1", Unlock: true})
+	assert.False(t, result.Success)
+	result = lock(serverA, &channeldpb.ChannelDataLockMessage{Field: "kv", Key: "1", Unlock: true})
+	assert.True(t, result.Success)
+	assert.Zero(t, result.HolderConnId)
+	update(serverB, map[uint32]string{1: "b"})
+	assert.Equal(t, "b", data()[1])
+
+	// Expired
+	result = lock(serverB, &channeldpb.ChannelDataLockMessage{Field: "kv", Key: "1", ExpireMs: 1000})
+	assert.True(t, result.Success)
+	ch.data.locks["kv[1]"].expireTime = ch.GetTime()
+	update(serverA, map[uint32]string{1: "a"})
+	assert.Equal(t, "a", data()[1])
+
+	// Only the map fields can be locked
+	serverA.sender.(*testQueuedMessageSender).msgQueue = nil
+	assert.Nil(t, lock(serverA, &channeldpb.ChannelDataLockMessage{Field: "kv4", Key: "1"}))
+}
@@ -0,0 +1,49 @@
+package channeld
+
+import (
+	"sort"
+)
+
+// A channel data update that is handled but not merged yet. See ChannelSettingsType.DeterministicMerge.
+type pendingDataUpdate struct {
+	senderConnId ConnectionId
+	// The order of being handled in the channel, which keeps the updates of the same sender in the order they're sent.
+	seq uint64
+	// Checks the update against the channel data at the time of merging (e.g. the base version and the locks), then merges it.
+	merge func()
+}
+
+// Every writer of the channel data goes through here. The update is merged right away, or queued to be merged at the end
+// of the tick if the channel merges deterministically. Should be called in the channel's goroutine.
+func (d *ChannelData) queueUpdate(senderConnId ConnectionId, merge func()) {
+	if !d.deterministicMerge {
+		merge()
+		return
+	}
+	d.pendingUpdateSeq++
+	d.pendingUpdates = append(d.pendingUpdates, pendingDataUpdate{
+		senderConnId: senderConnId,
+		seq:          d.pendingUpdateSeq,
+		merge:        merge,
+	})
+}
+
+// Merges the updates queued in the tick by the sender's connection id, then by the order they're sent, so the merged channel
+// data doesn't depend on how the updates of the different senders interleave when they arrive. Called at the end of
+// tickMessages(), before the fan-out.
+func (ch *Channel) mergePendingUpdates() {
+	if ch.data == nil || len(ch.data.pendingUpdates) == 0 {
+		return
+	}
+	updates := ch.data.pendingUpdates
+	ch.data.pendingUpdates = nil
+	sort.Slice(updates, func(i, j int) bool {
+		if updates[i].senderConnId != updates[j].senderConnId {
+			return updates[i].senderConnId < updates[j].senderConnId
+		}
+		return updates[i].seq < updates[j].seq
+	})
+	for _, update := range updates {
+		update.merge()
+	}
+}
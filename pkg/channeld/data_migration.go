@@ -0,0 +1,51 @@
+package channeld
+
+import (
+	"fmt"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// Migrates the channel data from a schema version to the next one, e.g. renames a field or converts to a new message type.
+// The data can be the initial data of the channel, or the data of an update message, so the unset fields should remain unset.
+type ChannelDataMigrationFunc func(data *anypb.Any) (*anypb.Any, error)
+
+var channelDataMigrations = make(map[channeldpb.ChannelType]map[uint32]ChannelDataMigrationFunc)
+
+// Registers the migration from the fromVersion to fromVersion+1. The migrations are chained on load to reach
+// ChannelSettingsType.DataSchemaVersion. Should be called before the channels of the type are created.
+func RegisterChannelDataMigration(channelType channeldpb.ChannelType, fromVersion uint32, fn ChannelDataMigrationFunc) {
+	migrations, exists := channelDataMigrations[channelType]
+	if !exists {
+		migrations = make(map[uint32]ChannelDataMigrationFunc)
+		channelDataMigrations[channelType] = migrations
+	}
+	migrations[fromVersion] = fn
+}
+
+// Returns the data in the channel's schema version (ChannelSettingsType.DataSchemaVersion), by applying the migrations in order.
+// The data in a newer version is rejected, as it can't be migrated back.
+func (ch *Channel) migrateData(data *anypb.Any, schemaVersion uint32) (*anypb.Any, error) {
+	currentVersion := ch.settings().DataSchemaVersion
+	if schemaVersion == currentVersion {
+		return data, nil
+	}
+	if schemaVersion > currentVersion {
+		return nil, fmt.Errorf("the data schema version %d is newer than the channel's %d", schemaVersion, currentVersion)
+	}
+
+	migrations := channelDataMigrations[ch.channelType]
+	for v := schemaVersion; v < currentVersion; v++ {
+		fn, exists := migrations[v]
+		if !exists {
+			return nil, fmt.Errorf("no migration from the data schema version %d", v)
+		}
+		var err error
+		if data, err = fn(data); err != nil {
+			return nil, fmt.Errorf("failed to migrate from the data schema version %d: %w", v, err)
+		}
+	}
+	channelDataMigrated.WithLabelValues(ch.channelType.String()).Inc()
+	return data, nil
+}
@@ -0,0 +1,74 @@
+package channeld
+
+import (
+	"testing"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestChannelDataMigration(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{DataSchemaVersion: 2}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+	// v0 -> v1: num is in centimeters instead of meters
+	RegisterChannelDataMigration(channeldpb.ChannelType_TEST, 0, func(data *anypb.Any) (*anypb.Any, error) {
+		msg := &testpb.TestChannelDataMessage{}
+		if err := data.UnmarshalTo(msg); err != nil {
+			return nil, err
+		}
+		msg.Num *= 100
+		return anypb.New(msg)
+	})
+	// v1 -> v2: the text is keyed by the num in a map
+	RegisterChannelDataMigration(channeldpb.ChannelType_TEST, 1, func(data *anypb.Any) (*anypb.Any, error) {
+		msg := &testpb.TestChannelDataMessage{}
+		if err := data.UnmarshalTo(msg); err != nil {
+			return nil, err
+		}
+		return anypb.New(&testpb.TestMapMessage{Kv: map[uint32]string{msg.Num: msg.Text}})
+	})
+	defer delete(channelDataMigrations, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	ch.removing = 1
+	ch.InitData(&testpb.TestMapMessage{}, nil)
+	data := func() *testpb.TestMapMessage {
+		return ch.GetDataMessage().(*testpb.TestMapMessage)
+	}
+	update := func(msg proto.Message, schemaVersion uint32) {
+		any, _ := anypb.New(msg)
+		handleChannelDataUpdate(MessageContext{
+			MsgType:    channeldpb.MessageType_CHANNEL_DATA_UPDATE,
+			Msg:        &channeldpb.ChannelDataUpdateMessage{Data: any, SchemaVersion: schemaVersion},
+			Connection: server,
+			Channel:    ch,
+		})
+	}
+
+	// The migrations are chained
+	update(&testpb.TestChannelDataMessage{Text: "a", Num: 2}, 0)
+	assert.Equal(t, "a", data().Kv[200])
+	update(&testpb.TestChannelDataMessage{Text: "b", Num: 300}, 1)
+	assert.Equal(t, "b", data().Kv[300])
+	update(&testpb.TestMapMessage{Kv: map[uint32]string{1: "c"}}, 2)
+	assert.Equal(t, "c", data().Kv[1])
+	assert.EqualValues(t, 3, ch.Data().Version())
+
+	// Can't be migrated back
+	update(&testpb.TestMapMessage{Kv: map[uint32]string{1: "d"}}, 3)
+	assert.Equal(t, "c", data().Kv[1])
+
+	// The chain is broken
+	delete(channelDataMigrations[channeldpb.ChannelType_TEST], 0)
+	_, err := ch.migrateData(&anypb.Any{}, 0)
+	assert.Error(t, err)
+	assert.EqualValues(t, 3, ch.Data().Version())
+}
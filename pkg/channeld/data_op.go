@@ -0,0 +1,50 @@
+package channeld
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+var ErrChannelRemoving = errors.New("the channel is being removed")
+var ErrChannelNoData = errors.New("the channel has no data")
+
+// Runs the operation on the channel data message in the channel's goroutine, so the game logic that embeds channeld
+// can read or modify the data without racing the tick loop. The message must not be retained after the operation returns.
+//
+// The in-place modifications are visible to the new subscribers, but are not fanned out to the existing ones.
+// To fan them out, call Data().OnUpdate() with the update message in the operation instead.
+//
+// The returned channel receives the result after the operation runs, or ErrChannelRemoving / ErrChannelNoData
+// if it doesn't run. Don't wait for it in the channel's goroutine, e.g. in a message handler, as it would deadlock.
+func (ch *Channel) ExecuteDataOp(op func(data proto.Message)) <-chan error {
+	result := make(chan error, 1)
+	if ch.IsRemoving() {
+		result <- ErrChannelRemoving
+		return result
+	}
+	ch.Execute(func(ch *Channel) {
+		result <- ch.runDataOp(op)
+	})
+	return result
+}
+
+func (ch *Channel) runDataOp(op func(data proto.Message)) (err error) {
+	if ch.IsRemoving() {
+		return ErrChannelRemoving
+	}
+	if ch.data == nil || ch.data.msg == nil {
+		return ErrChannelNoData
+	}
+	// The panic of the embedded logic shouldn't stop the tick loop
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("data operation panicked: %v", r)
+			ch.Logger().Error("data operation panicked", zap.Any("panic", r))
+		}
+	}()
+	op(ch.data.msg)
+	return nil
+}
@@ -0,0 +1,62 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExecuteDataOp(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	wait := func(result <-chan error) error {
+		select {
+		case err := <-result:
+			return err
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for the data operation")
+			return nil
+		}
+	}
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	assert.ErrorIs(t, wait(ch.ExecuteDataOp(func(data proto.Message) {})), ErrChannelNoData)
+
+	ch.Execute(func(ch *Channel) {
+		ch.InitData(&testpb.TestChannelDataMessage{Text: "a"}, nil)
+	})
+	assert.NoError(t, wait(ch.ExecuteDataOp(func(data proto.Message) {
+		data.(*testpb.TestChannelDataMessage).Num = 1
+	})))
+	var num uint32
+	assert.NoError(t, wait(ch.ExecuteDataOp(func(data proto.Message) {
+		num = data.(*testpb.TestChannelDataMessage).Num
+	})))
+	assert.EqualValues(t, 1, num)
+
+	// Fanned out with the update message
+	assert.NoError(t, wait(ch.ExecuteDataOp(func(data proto.Message) {
+		ch.Data().OnUpdate(&testpb.TestChannelDataMessage{Num: 2}, ch.GetTime(), 0, nil)
+	})))
+	assert.NoError(t, wait(ch.ExecuteDataOp(func(data proto.Message) {
+		num = data.(*testpb.TestChannelDataMessage).Num
+		assert.Equal(t, 1, ch.Data().updateMsgBuffer.Len())
+	})))
+	assert.EqualValues(t, 2, num)
+
+	// The panic doesn't stop the tick loop
+	assert.Error(t, wait(ch.ExecuteDataOp(func(data proto.Message) {
+		panic("oops")
+	})))
+	assert.NoError(t, wait(ch.ExecuteDataOp(func(data proto.Message) {})))
+
+	RemoveChannel(ch)
+	assert.ErrorIs(t, wait(ch.ExecuteDataOp(func(data proto.Message) {})), ErrChannelRemoving)
+}
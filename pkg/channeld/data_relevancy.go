@@ -0,0 +1,204 @@
+package channeld
+
+import (
+	"sort"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// Scores the relevancy of an entity to the subscriber, e.g. higher for the teammates and the attackers of the subscriber's player.
+// When the fan-out budget of the subscriber is exceeded, the entities with the higher scores are included first.
+// Called in the channel's goroutine. The entity is the map value in ChannelSettingsType.EntityMapField, and should not be modified.
+type EntityRelevancyFunc func(ch *Channel, subscriber ConnectionInChannel, entityId EntityId, entity protoreflect.Message) float64
+
+var entityRelevancyFuncs = make(map[channeldpb.ChannelType]EntityRelevancyFunc)
+
+// Should be called before the channels of the type are created, as the functions are not protected from concurrent access.
+// Without the function, the entities are ranked by how long they have been deferred.
+func RegisterEntityRelevancyFunc(channelType channeldpb.ChannelType, fn EntityRelevancyFunc) {
+	entityRelevancyFuncs[channelType] = fn
+}
+
+// An entity left out of the fan-out for exceeding the budget. The following fan-outs send its whole state.
+type deferredEntity struct {
+	mk    protoreflect.MapKey
+	since ChannelTime
+}
+
+type relevancyCandidate struct {
+	id       EntityId
+	mk       protoreflect.MapKey
+	value    protoreflect.Value
+	score    float64
+	deferred bool
+	since    ChannelTime
+}
+
+func entityIdOf(mk protoreflect.MapKey) (EntityId, bool) {
+	switch key := mk.Interface().(type) {
+	case uint32:
+		return EntityId(key), true
+	case uint64:
+		return EntityId(key), true
+	case int32:
+		return EntityId(key), true
+	case int64:
+		return EntityId(key), true
+	}
+	return 0, false
+}
+
+// The size of the map entry in the fan-out, roughly.
+func mapEntrySize(v protoreflect.Value) int {
+	const keyAndTagSize = 8
+	if msg, ok := v.Interface().(protoreflect.Message); ok {
+		return proto.Size(msg.Interface()) + keyAndTagSize
+	}
+	return keyAndTagSize * 2
+}
+
+// Returns 0 if the subscriber has no fan-out budget. See channeldpb.ChannelSubscriptionOptions.FanOutBudgetBytes.
+func (ch *Channel) fanOutBudget(cs *ChannelSubscription) int {
+	if ch.data.entityMapField == "" {
+		return 0
+	}
+	if cs.options.FanOutBudgetBytes != nil {
+		return int(*cs.options.FanOutBudgetBytes)
+	}
+	return int(ch.data.fanOutBudgetBytes)
+}
+
+// Returns the update message that fits in the fan-out budget of the subscriber. The entities that don't fit are deferred,
+// and have their whole states sent in the following fan-outs, in the order of the relevancy. The removed entities are never deferred.
+// The updateMsg can be nil if there's nothing else to fan out. The updateMsg is not modified, as it can be shared by the fan-out group.
+func (ch *Channel) applyFanOutBudget(foc *fanOutConnection, cs *ChannelSubscription, updateMsg *channeldpb.ChannelDataUpdateMessage, version uint64) *channeldpb.ChannelDataUpdateMessage {
+	budget := ch.fanOutBudget(cs)
+	if ch.data.entityMapField != "" {
+		budget = foc.bandwidthFanOutBudget(cs, budget)
+	}
+	if budget <= 0 || (len(foc.deferredEntities) == 0 && (updateMsg == nil || proto.Size(updateMsg) <= budget)) {
+		return updateMsg
+	}
+	fd := ch.data.msg.ProtoReflect().Descriptor().Fields().ByName(protoreflect.Name(ch.data.entityMapField))
+	if fd == nil || !fd.IsMap() {
+		return updateMsg
+	}
+
+	var src protoreflect.Message
+	if updateMsg != nil {
+		msg, err := updateMsg.Data.UnmarshalNew()
+		if err != nil {
+			ch.Logger().Error("failed to unmarshal the fan-out to apply the budget", zap.Error(err))
+			return updateMsg
+		}
+		src = msg.ProtoReflect()
+	} else {
+		src = ch.data.msg.ProtoReflect().New()
+	}
+
+	// The fan-out without the entities
+	dst := src.New()
+	src.Range(func(f protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if f != fd {
+			dst.Set(f, v)
+		}
+		return true
+	})
+	remaining := budget - proto.Size(dst.Interface())
+	dstMap := dst.Mutable(fd).Map()
+
+	if foc.deferredEntities == nil {
+		foc.deferredEntities = make(map[EntityId]*deferredEntity)
+	}
+	var candidates []*relevancyCandidate
+	src.Get(fd).Map().Range(func(mk protoreflect.MapKey, v protoreflect.Value) bool {
+		id, ok := entityIdOf(mk)
+		if !ok || (fd.MapValue().Kind() == protoreflect.MessageKind && isRemovedMapValue(v.Message())) {
+			dstMap.Set(mk, v)
+			remaining -= mapEntrySize(v)
+			if ok {
+				delete(foc.deferredEntities, id)
+			}
+			return true
+		}
+		if _, deferred := foc.deferredEntities[id]; !deferred {
+			candidates = append(candidates, &relevancyCandidate{id: id, mk: mk, value: v})
+		}
+		return true
+	})
+	// The whole states of the deferred entities, as the subscriber has missed their updates
+	currentMap := ch.data.msg.ProtoReflect().Get(fd).Map()
+	for id, de := range foc.deferredEntities {
+		if !currentMap.Has(de.mk) {
+			// The removal is in the fan-out
+			delete(foc.deferredEntities, id)
+			continue
+		}
+		candidates = append(candidates, &relevancyCandidate{id: id, mk: de.mk, value: currentMap.Get(de.mk), deferred: true, since: de.since})
+	}
+
+	fn := entityRelevancyFuncs[ch.channelType]
+	for _, c := range candidates {
+		if fn != nil && fd.MapValue().Kind() == protoreflect.MessageKind {
+			c.score = fn(ch, foc.conn, c.id, c.value.Message())
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if a.deferred != b.deferred {
+			return a.deferred
+		}
+		if a.since != b.since {
+			return a.since < b.since
+		}
+		return a.id < b.id
+	})
+
+	deferredNum := 0
+	for _, c := range candidates {
+		if size := mapEntrySize(c.value); size <= remaining {
+			dstMap.Set(c.mk, c.value)
+			remaining -= size
+			delete(foc.deferredEntities, c.id)
+		} else {
+			if !c.deferred {
+				foc.deferredEntities[c.id] = &deferredEntity{mk: c.mk, since: ch.GetTime()}
+			}
+			deferredNum++
+		}
+	}
+	if deferredNum > 0 {
+		fanOutEntitiesDeferred.WithLabelValues(ch.channelType.String()).Add(float64(deferredNum))
+	}
+	if updateMsg == nil && dstMap.Len() == 0 {
+		return nil
+	}
+
+	any, err := anypb.New(dst.Interface())
+	if err != nil {
+		ch.Logger().Error("failed to marshal the fan-out with the budget", zap.Error(err))
+		return updateMsg
+	}
+	result := &channeldpb.ChannelDataUpdateMessage{}
+	if updateMsg != nil {
+		result = cloneUpdateEnvelope(updateMsg)
+		// The checksum only matches if the subscriber has all the entities
+		if len(foc.deferredEntities) > 0 {
+			result.Checksum = nil
+		}
+	}
+	result.Data = any
+	result.Version = version
+	// The deferred entities may be added, and the entities in the update may be left out
+	if cs.options.GetUpdatedFields() && (updateMsg == nil || updateMsg.UpdatedFields != nil) {
+		result.UpdatedFields = updatedFieldPaths(dst)
+	}
+	return result
+}
@@ -0,0 +1,163 @@
+package channeld
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func TestFanOutBudget(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{EntityMapField: "kv2"}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+	// The entities with the larger num are more relevant
+	RegisterEntityRelevancyFunc(channeldpb.ChannelType_TEST, func(ch *Channel, subscriber ConnectionInChannel, entityId EntityId, entity protoreflect.Message) float64 {
+		return float64(entity.Interface().(*testpb.TestMapMessage_StringWrapper).Num)
+	})
+	defer delete(entityRelevancyFuncs, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	ch.removing = 1
+	ch.InitData(&testpb.TestMapMessage{}, nil)
+	// Fits two entities of the 40-byte content
+	client.SubscribeToChannel(ch, &channeldpb.ChannelSubscriptionOptions{
+		FanOutIntervalMs:  proto.Uint32(50),
+		FanOutBudgetBytes: proto.Uint32(120),
+	})
+
+	entity := func(num int64) *testpb.TestMapMessage_StringWrapper {
+		return &testpb.TestMapMessage_StringWrapper{Content: strings.Repeat("x", 40), Num: num}
+	}
+	fanOut := func(ms int64) *testpb.TestMapMessage {
+		client.sender.(*testQueuedMessageSender).msgQueue = nil
+		ch.tickData(ch.GetTime().AddMs(uint32(ms)))
+		updateMsg, ok := client.latestMsg().(*channeldpb.ChannelDataUpdateMessage)
+		if !ok {
+			return nil
+		}
+		data := &testpb.TestMapMessage{}
+		assert.NoError(t, updateMsg.Data.UnmarshalTo(data))
+		return data
+	}
+
+	foc := ch.fanOutQueue.Front().Value.(*fanOutConnection)
+
+	ch.Data().OnUpdate(&testpb.TestMapMessage{
+		Kv:  map[uint32]string{1: "a"},
+		Kv2: map[uint32]*testpb.TestMapMessage_StringWrapper{1: entity(1), 2: entity(3), 3: entity(2)},
+	}, ch.GetTime(), server.Id(), nil)
+
+	// The most relevant entities first. The non-entity fields are never deferred.
+	data := fanOut(100)
+	if assert.NotNil(t, data) {
+		assert.Equal(t, "a", data.Kv[1])
+		assert.Len(t, data.Kv2, 2)
+		assert.Contains(t, data.Kv2, uint32(2))
+		assert.Contains(t, data.Kv2, uint32(3))
+	}
+	assert.Contains(t, foc.deferredEntities, EntityId(1))
+
+	// The deferred entity is sent in whole, even with no update
+	data = fanOut(200)
+	if assert.NotNil(t, data) {
+		assert.Len(t, data.Kv2, 1)
+		if assert.Contains(t, data.Kv2, uint32(1)) {
+			assert.EqualValues(t, 1, data.Kv2[1].Num)
+			assert.Len(t, data.Kv2[1].Content, 40)
+		}
+	}
+	assert.Empty(t, foc.deferredEntities)
+
+	// Nothing to fan out
+	assert.Nil(t, fanOut(300))
+}
+
+func TestFanOutBudgetDisabled(t *testing.T) {
+	InitLogs()
+	InitChannels()
+
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, nil)
+	ch.removing = 1
+	ch.InitData(&testpb.TestMapMessage{}, nil)
+	cs := &ChannelSubscription{options: channeldpb.ChannelSubscriptionOptions{FanOutBudgetBytes: proto.Uint32(10)}}
+	// No entity map field
+	assert.Equal(t, 0, ch.fanOutBudget(cs))
+
+	updateMsg := &channeldpb.ChannelDataUpdateMessage{}
+	assert.Same(t, updateMsg, ch.applyFanOutBudget(&fanOutConnection{}, cs, updateMsg, 1))
+}
+
+// The fan-out that goes through the checksum, keyframe, budget and input ack keeps all the fields
+func TestFanOutUpdateEnvelope(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{EntityMapField: "kv2", ChecksumIntervalMs: 50}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	ch.removing = 1
+	ch.InitData(&testpb.TestMapMessage{}, nil)
+	// Fits two entities of the 40-byte content
+	client.SubscribeToChannel(ch, &channeldpb.ChannelSubscriptionOptions{
+		FanOutIntervalMs:   proto.Uint32(50),
+		FanOutBudgetBytes:  proto.Uint32(120),
+		KeyframeIntervalMs: proto.Uint32(1000),
+		UpdatedFields:      proto.Bool(true),
+	})
+	ch.Data().recordInputSeq(client.Id(), 7)
+
+	entity := func(num int64) *testpb.TestMapMessage_StringWrapper {
+		return &testpb.TestMapMessage_StringWrapper{Content: strings.Repeat("x", 40), Num: num}
+	}
+	base := ch.GetTime()
+	fanOut := func(ms uint32) *channeldpb.ChannelDataUpdateMessage {
+		client.sender.(*testQueuedMessageSender).msgQueue = nil
+		ch.tickData(base.AddMs(ms))
+		updateMsg, _ := client.latestMsg().(*channeldpb.ChannelDataUpdateMessage)
+		return updateMsg
+	}
+
+	// The first fan-out is a keyframe, with an entity deferred
+	ch.Data().OnUpdate(&testpb.TestMapMessage{
+		Kv2: map[uint32]*testpb.TestMapMessage_StringWrapper{1: entity(1), 2: entity(3), 3: entity(2)},
+	}, base, server.Id(), nil)
+	if updateMsg := fanOut(100); assert.NotNil(t, updateMsg) {
+		assert.True(t, updateMsg.Keyframe)
+		assert.NotNil(t, updateMsg.KeyframeVersion)
+		// The subscriber doesn't have all the entities
+		assert.Nil(t, updateMsg.Checksum)
+		assert.EqualValues(t, 7, updateMsg.GetLastInputSeq())
+		assert.NotZero(t, updateMsg.ServerTimeMs)
+	}
+
+	// The delta with the deferred entity carries all the fields
+	ch.Data().OnUpdate(&testpb.TestMapMessage{Kv: map[uint32]string{1: "a"}}, base.AddMs(120), server.Id(), nil)
+	if updateMsg := fanOut(150); assert.NotNil(t, updateMsg) {
+		data := &testpb.TestMapMessage{}
+		assert.NoError(t, updateMsg.Data.UnmarshalTo(data))
+		assert.Equal(t, "a", data.Kv[1])
+		assert.Contains(t, data.Kv2, uint32(3))
+		assert.EqualValues(t, 2, updateMsg.Version)
+		assert.False(t, updateMsg.Keyframe)
+		assert.EqualValues(t, 1, updateMsg.GetKeyframeVersion())
+		assert.EqualValues(t, 1, updateMsg.GetPreviousVersion())
+		assert.NotNil(t, updateMsg.Checksum)
+		assert.EqualValues(t, 7, updateMsg.GetLastInputSeq())
+		assert.ElementsMatch(t, []string{"kv.1", "kv2.3.content", "kv2.3.num"}, updateMsg.UpdatedFields)
+		assert.NotZero(t, updateMsg.ServerTimeMs)
+	}
+}
@@ -0,0 +1,95 @@
+package channeld
+
+import (
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// Merges the update into the shadow copy of the channel data. Implement it to validate a new merge implementation
+// against the current one. See ChannelSettingsType.ShadowMerge.
+type ShadowMergeFunc func(dst common.ChannelDataMessage, src common.ChannelDataMessage, options *channeldpb.ChannelDataMergeOptions) error
+
+var shadowMergeFuncs = make(map[channeldpb.ChannelType]ShadowMergeFunc)
+
+// Should be called before the channels of the type are created, as the functions are not protected from concurrent access.
+func RegisterShadowMergeFunc(channelType channeldpb.ChannelType, fn ShadowMergeFunc) {
+	shadowMergeFuncs[channelType] = fn
+}
+
+// The shadow copy of the channel data. It's never fanned out.
+type shadowData struct {
+	settings    ShadowMergeSettingsType
+	channelType channeldpb.ChannelType
+	merge       ShadowMergeFunc
+	msg         common.ChannelDataMessage
+	// The channel data message that the shadow copy is synced from. The shadow copy is synced again when the message is replaced,
+	// e.g. restored from the snapshot.
+	primary common.ChannelDataMessage
+	// The number of the updates merged since the last comparison
+	updateNum  int
+	divergence int
+}
+
+func (s *shadowData) sync(primary common.ChannelDataMessage) {
+	s.primary = primary
+	s.msg = proto.Clone(primary)
+	s.updateNum = 0
+}
+
+// Merges the update message into the shadow copy. Should be called before the update message is merged into the channel data.
+func (d *ChannelData) mergeShadow(updateMsg common.ChannelDataMessage) {
+	s := d.shadow
+	if s == nil || d.msg == nil {
+		return
+	}
+	if s.primary != d.msg {
+		s.sync(d.msg)
+	}
+
+	options := s.settings.MergeOptions
+	if options == nil {
+		options = d.mergeOptions
+	}
+	// The merge can keep the references to the update message, which can be modified later.
+	src := proto.Clone(updateMsg)
+	if s.merge != nil {
+		if err := s.merge(s.msg, src, options); err != nil {
+			rootLogger.Error("shadow merge error", zap.String("channelType", s.channelType.String()), zap.Error(err))
+		}
+	} else if s.settings.UseReflectMerge {
+		ReflectMerge(s.msg, src, options)
+	} else {
+		mergeWithOptions(s.msg, src, options, nil)
+	}
+	s.updateNum++
+}
+
+// Compares the channel data with the shadow copy, if CompareInterval updates have been merged. If they diverge, the divergence
+// is logged, and the shadow copy is synced with the channel data. Should be called after the update message is merged.
+func (d *ChannelData) compareShadow() {
+	s := d.shadow
+	if s == nil || s.primary != d.msg || s.updateNum == 0 || s.updateNum < s.settings.CompareInterval {
+		return
+	}
+	s.updateNum = 0
+
+	// The leaderboard trims the channel data after the merge
+	checksum := common.ChannelDataChecksum(d.withoutLeaderboard(d.msg))
+	shadowChecksum := common.ChannelDataChecksum(d.withoutLeaderboard(s.msg))
+	if checksum == shadowChecksum {
+		return
+	}
+	s.divergence++
+	shadowMergeDivergences.WithLabelValues(s.channelType.String()).Inc()
+	rootLogger.Warn("shadow merge diverged from the channel data",
+		zap.String("channelType", s.channelType.String()),
+		zap.Uint64("version", d.msgIndex),
+		zap.Uint32("checksum", checksum),
+		zap.Uint32("shadowChecksum", shadowChecksum),
+	)
+	rootLogger.Debug("diverged channel data", zap.Any("data", d.msg), zap.Any("shadow", s.msg))
+	// Report the following divergences separately
+	s.sync(d.msg)
+}
@@ -0,0 +1,83 @@
+package channeld
+
+import (
+	"testing"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShadowMerge(t *testing.T) {
+	InitLogs()
+	InitChannels()
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		ShadowMerge: ShadowMergeSettingsType{Enabled: true, UseReflectMerge: true},
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, nil)
+	ch.removing = 1
+	ch.InitData(&testpb.TestMergeMessage{
+		List: []string{"x"},
+		Kv:   map[int64]*testpb.TestMergeMessage_StringWrapper{1: {Content: "a"}},
+	}, nil)
+	divergences := testutil.ToFloat64(shadowMergeDivergences.WithLabelValues(channeldpb.ChannelType_TEST.String()))
+
+	// Same result with either merge
+	ch.Data().OnUpdate(&testpb.TestMergeMessage{List: []string{"y"}}, 0, 0, nil)
+	assert.Equal(t, 0, ch.Data().shadow.divergence)
+
+	// The removed entry is deleted by the custom merge, but kept by the reflection-based merge without the options
+	ch.Data().OnUpdate(&testpb.TestMergeMessage{Kv: map[int64]*testpb.TestMergeMessage_StringWrapper{1: {Removed: true}}}, 0, 0, nil)
+	assert.Equal(t, 1, ch.Data().shadow.divergence)
+	assert.Equal(t, divergences+1, testutil.ToFloat64(shadowMergeDivergences.WithLabelValues(channeldpb.ChannelType_TEST.String())))
+	assert.Empty(t, ch.GetDataMessage().(*testpb.TestMergeMessage).Kv)
+	// Synced after the divergence
+	assert.Empty(t, ch.Data().shadow.msg.(*testpb.TestMergeMessage).Kv)
+
+	ch.Data().OnUpdate(&testpb.TestMergeMessage{Kv: map[int64]*testpb.TestMergeMessage_StringWrapper{2: {Content: "b"}}}, 0, 0, nil)
+	assert.Equal(t, 1, ch.Data().shadow.divergence)
+
+	// The channel data has been replaced
+	ch.Data().snapshot = &testpb.TestMergeMessage{Kv: map[int64]*testpb.TestMergeMessage_StringWrapper{5: {Content: "e"}}}
+	assert.True(t, ch.restoreSnapshot())
+	ch.Data().OnUpdate(&testpb.TestMergeMessage{Kv: map[int64]*testpb.TestMergeMessage_StringWrapper{3: {Content: "c"}}}, 0, 0, nil)
+	assert.Equal(t, 1, ch.Data().shadow.divergence)
+}
+
+func TestShadowMergeFunc(t *testing.T) {
+	InitLogs()
+	InitChannels()
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		ShadowMerge: ShadowMergeSettingsType{Enabled: true, CompareInterval: 2},
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+	// The new merge implementation ignores the num
+	RegisterShadowMergeFunc(channeldpb.ChannelType_TEST, func(dst, src common.ChannelDataMessage, options *channeldpb.ChannelDataMergeOptions) error {
+		if text := src.(*testpb.TestChannelDataMessage).Text; text != "" {
+			dst.(*testpb.TestChannelDataMessage).Text = text
+		}
+		return nil
+	})
+	defer delete(shadowMergeFuncs, channeldpb.ChannelType_TEST)
+
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, nil)
+	ch.removing = 1
+	ch.InitData(&testpb.TestChannelDataMessage{}, nil)
+
+	ch.Data().OnUpdate(&testpb.TestChannelDataMessage{Text: "a"}, 0, 0, nil)
+	ch.Data().OnUpdate(&testpb.TestChannelDataMessage{Text: "b"}, 0, 0, nil)
+	assert.Equal(t, 0, ch.Data().shadow.divergence)
+
+	// Not compared until the second update
+	ch.Data().OnUpdate(&testpb.TestChannelDataMessage{Num: 1}, 0, 0, nil)
+	assert.Equal(t, 0, ch.Data().shadow.divergence)
+	ch.Data().OnUpdate(&testpb.TestChannelDataMessage{Text: "c"}, 0, 0, nil)
+	assert.Equal(t, 1, ch.Data().shadow.divergence)
+	assert.EqualValues(t, 1, ch.Data().shadow.msg.(*testpb.TestChannelDataMessage).Num)
+}
@@ -0,0 +1,89 @@
+package channeld
+
+import (
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// The map entry with removed=true that has been removed from the channel data. The subscribers that haven't received the removal
+// get it in the fan-out, even if the fan-out is a full resync. See ChannelData.withTombstones().
+type mapTombstone struct {
+	fd           protoreflect.FieldDescriptor
+	mk           protoreflect.MapKey
+	value        protoreflect.Value
+	messageIndex uint64
+}
+
+// The options to merge the update messages for the fan-out. The removed map entries are kept in the merged message,
+// so the subscribers can remove them as well.
+func (d *ChannelData) fanOutMergeOptions() *channeldpb.ChannelDataMergeOptions {
+	if d.fanOutOptions == nil {
+		if d.mergeOptions != nil {
+			d.fanOutOptions = proto.Clone(d.mergeOptions).(*channeldpb.ChannelDataMergeOptions)
+		} else {
+			d.fanOutOptions = &channeldpb.ChannelDataMergeOptions{}
+		}
+		d.fanOutOptions.ShouldCheckRemovableMapField = false
+	}
+	return d.fanOutOptions
+}
+
+// Should be called after the update message is merged and the msgIndex is increased.
+func (d *ChannelData) recordTombstones(updateMsg common.ChannelDataMessage) {
+	dst := d.msg.ProtoReflect()
+	updateMsg.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if !fd.IsMap() || fd.MapValue().Kind() != protoreflect.MessageKind {
+			return true
+		}
+		dstMap := dst.Get(fd).Map()
+		v.Map().Range(func(mk protoreflect.MapKey, mv protoreflect.Value) bool {
+			if !isRemovedMapValue(mv.Message()) || dstMap.Has(mk) {
+				return true
+			}
+			if d.tombstones == nil {
+				d.tombstones = make(map[string]*mapTombstone)
+			}
+			d.tombstones[mapEntryKey(string(fd.Name()), mk)] = &mapTombstone{
+				fd:           fd,
+				mk:           mk,
+				value:        mv,
+				messageIndex: d.msgIndex,
+			}
+			return true
+		})
+		return true
+	})
+}
+
+// Returns the channel data with the tombstones of the removals after the lastMessageIndex, for the full resync.
+// Returns the channel data itself if there's no such tombstone.
+func (d *ChannelData) withTombstones(lastMessageIndex uint64) common.ChannelDataMessage {
+	var msg protoreflect.Message
+	for _, tombstone := range d.tombstones {
+		if tombstone.messageIndex <= lastMessageIndex {
+			continue
+		}
+		if msg == nil {
+			msg = proto.Clone(d.msg).ProtoReflect()
+		}
+		// The removal may have been overridden by a later add
+		if !msg.Get(tombstone.fd).Map().Has(tombstone.mk) {
+			msg.Mutable(tombstone.fd).Map().Set(tombstone.mk, tombstone.value)
+		}
+	}
+	if msg == nil {
+		return d.msg
+	}
+	return msg.Interface()
+}
+
+// Removes the tombstones that all the subscribers have received.
+func (d *ChannelData) pruneTombstones(minLastMessageIndex uint64) {
+	for key, tombstone := range d.tombstones {
+		if tombstone.messageIndex <= minLastMessageIndex {
+			delete(d.tombstones, key)
+		}
+	}
+}
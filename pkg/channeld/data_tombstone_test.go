@@ -0,0 +1,74 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestFanOutTombstones(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{MaxUpdateMsgBufferSize: 2}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	c0 := addTestConnectionWithProcessor(channeldpb.ConnectionType_SERVER, testChannelDataMessageProcessor)
+	c1 := addTestConnectionWithProcessor(channeldpb.ConnectionType_CLIENT, testChannelDataMessageProcessor)
+
+	testChannel, _ := CreateChannel(channeldpb.ChannelType_TEST, c0)
+	// Stop the channel.Tick() goroutine
+	testChannel.removing = 1
+	testChannel.InitData(&testpb.TestMergeMessage{
+		Kv: map[int64]*testpb.TestMergeMessage_StringWrapper{1: {Content: "a"}, 2: {Content: "b"}, 3: {Content: "c"}},
+	}, &channeldpb.ChannelDataMergeOptions{ShouldCheckRemovableMapField: true})
+	testChannel.tickInterval = time.Hour
+	data := testChannel.GetDataMessage().(*testpb.TestMergeMessage)
+	removal := func(key int64) *testpb.TestMergeMessage {
+		return &testpb.TestMergeMessage{Kv: map[int64]*testpb.TestMergeMessage_StringWrapper{key: {Removed: true}}}
+	}
+
+	c1.SubscribeToChannel(testChannel, &channeldpb.ChannelSubscriptionOptions{FanOutIntervalMs: proto.Uint32(50)})
+	channelStartTime := ChannelTime(100 * int64(time.Millisecond))
+	// The whole data
+	testChannel.tickData(channelStartTime)
+	assert.Equal(t, 1, len(c1.testQueue()))
+
+	// The removal is accumulated with a later update in the same fan-out
+	testChannel.Data().OnUpdate(removal(1), channelStartTime.AddMs(10), c0.Id(), nil)
+	testChannel.Data().OnUpdate(&testpb.TestMergeMessage{List: []string{"x"}}, channelStartTime.AddMs(20), c0.Id(), nil)
+	assert.NotContains(t, data.Kv, int64(1))
+	testChannel.tickData(channelStartTime.AddMs(50))
+	assert.Equal(t, 2, len(c1.testQueue()))
+	fanOutMsg := c1.latestMsg().(*testpb.TestMergeMessage)
+	if assert.Contains(t, fanOutMsg.Kv, int64(1)) {
+		assert.True(t, fanOutMsg.Kv[1].Removed)
+	}
+	// All the subscribers have received the removal
+	assert.Empty(t, testChannel.Data().tombstones)
+
+	// The removal is coalesced, and c1 needs a full resync
+	testChannel.Data().OnUpdate(&testpb.TestMergeMessage{List: []string{"y"}}, channelStartTime.AddMs(60), c0.Id(), nil)
+	testChannel.Data().OnUpdate(removal(2), channelStartTime.AddMs(70), c0.Id(), nil)
+	testChannel.Data().OnUpdate(&testpb.TestMergeMessage{List: []string{"z"}}, channelStartTime.AddMs(80), c0.Id(), nil)
+	resyncCount := testutil.ToFloat64(fanOutFullResync)
+	testChannel.tickData(channelStartTime.AddMs(100))
+	assert.Equal(t, resyncCount+1, testutil.ToFloat64(fanOutFullResync))
+	assert.Equal(t, 3, len(c1.testQueue()))
+	fanOutMsg = c1.latestMsg().(*testpb.TestMergeMessage)
+	if assert.Contains(t, fanOutMsg.Kv, int64(2)) {
+		assert.True(t, fanOutMsg.Kv[2].Removed)
+	}
+	assert.Equal(t, "c", fanOutMsg.Kv[3].Content)
+	// The removal in the last fan-out is not resent
+	assert.NotContains(t, fanOutMsg.Kv, int64(1))
+	// The channel data itself doesn't have the tombstone
+	assert.NotContains(t, data.Kv, int64(2))
+	assert.Empty(t, testChannel.Data().tombstones)
+}
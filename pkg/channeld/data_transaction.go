@@ -0,0 +1,255 @@
+package channeld
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Fails the transaction. See channeldpb.ChannelDataTransactionResultMessage.
+type transactionError struct {
+	result channeldpb.ChannelDataTransactionResultMessage_Result
+	reason string
+}
+
+func (e *transactionError) Error() string {
+	return e.result.String() + ": " + e.reason
+}
+
+func invalidMutation(format string, args ...interface{}) *transactionError {
+	return &transactionError{result: channeldpb.ChannelDataTransactionResultMessage_INVALID, reason: fmt.Sprintf(format, args...)}
+}
+
+// Parses the key of the map field in the text form, e.g. '1001' for the integer keys.
+func parseMapKey(fd protoreflect.FieldDescriptor, key string) (protoreflect.MapKey, error) {
+	switch fd.MapKey().Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(key).MapKey(), nil
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(key)
+		return protoreflect.ValueOfBool(b).MapKey(), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		i, err := strconv.ParseInt(key, 10, 32)
+		return protoreflect.ValueOfInt32(int32(i)).MapKey(), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		i, err := strconv.ParseInt(key, 10, 64)
+		return protoreflect.ValueOfInt64(i).MapKey(), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		u, err := strconv.ParseUint(key, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(u)).MapKey(), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		u, err := strconv.ParseUint(key, 10, 64)
+		return protoreflect.ValueOfUint64(u).MapKey(), err
+	}
+	return protoreflect.MapKey{}, fmt.Errorf("unsupported map key kind %s", fd.MapKey().Kind())
+}
+
+// Returns the value of the map entry, in the update message if the entry has been mutated in the transaction,
+// otherwise in the channel data. The removed entry doesn't exist.
+func (d *ChannelData) transactionValue(updateMsg protoreflect.Message, fd protoreflect.FieldDescriptor, mk protoreflect.MapKey) (protoreflect.Value, bool) {
+	m := d.msg.ProtoReflect().Get(fd).Map()
+	if updated := updateMsg.Get(fd).Map(); updated.Has(mk) {
+		m = updated
+	}
+	if !m.Has(mk) {
+		return protoreflect.Value{}, false
+	}
+	v := m.Get(mk)
+	if fd.MapValue().Kind() == protoreflect.MessageKind && isRemovedMapValue(v.Message()) {
+		return protoreflect.Value{}, false
+	}
+	return v, true
+}
+
+// Adds the delta to the numeric value. Returns false if the result overflows the kind.
+func addDelta(kind protoreflect.Kind, v protoreflect.Value, delta int64) (protoreflect.Value, bool) {
+	switch kind {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		result := v.Int() + delta
+		return protoreflect.ValueOfInt32(int32(result)), result >= math.MinInt32 && result <= math.MaxInt32
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		result := v.Int() + delta
+		return protoreflect.ValueOfInt64(result), (delta >= 0) == (result >= v.Int())
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		result := int64(v.Uint()) + delta
+		return protoreflect.ValueOfUint32(uint32(result)), result >= 0 && result <= math.MaxUint32
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if delta < 0 && v.Uint() < uint64(-delta) {
+			return v, false
+		}
+		result := v.Uint() + uint64(delta)
+		return protoreflect.ValueOfUint64(result), delta < 0 || result >= v.Uint()
+	case protoreflect.FloatKind:
+		return protoreflect.ValueOfFloat32(float32(v.Float() + float64(delta))), true
+	default:
+		return protoreflect.ValueOfFloat64(v.Float() + float64(delta)), true
+	}
+}
+
+// Returns true if the numeric value is in the range of the INCREMENT mutation.
+func inMutationRange(mutation *channeldpb.ChannelDataTransactionMessage_Mutation, v float64) bool {
+	return (mutation.Min == nil || v >= float64(*mutation.Min)) && (mutation.Max == nil || v <= float64(*mutation.Max))
+}
+
+// Applies the mutation to the update message.
+func (d *ChannelData) applyMutation(updateMsg protoreflect.Message, mutation *channeldpb.ChannelDataTransactionMessage_Mutation, t ChannelTime, senderConnId ConnectionId) *transactionError {
+	fd := d.msg.ProtoReflect().Descriptor().Fields().ByName(protoreflect.Name(mutation.Field))
+	if fd == nil || !fd.IsMap() {
+		return invalidMutation("%s is not a map field of the channel data", mutation.Field)
+	}
+	mk, err := parseMapKey(fd, mutation.Key)
+	if err != nil {
+		return invalidMutation("invalid key %s: %v", mutation.Key, err)
+	}
+	entryKey := mapEntryKey(mutation.Field, mk)
+	if lock := d.getLock(entryKey, t); lock != nil && lock.holderConnId != senderConnId {
+		return &transactionError{result: channeldpb.ChannelDataTransactionResultMessage_LOCKED, reason: entryKey}
+	}
+	current, exists := d.transactionValue(updateMsg, fd, mk)
+
+	switch mutation.Op {
+	case channeldpb.ChannelDataTransactionMessage_Mutation_PUT:
+		if exists && mutation.IfAbsent {
+			return &transactionError{result: channeldpb.ChannelDataTransactionResultMessage_PRECONDITION_FAILED, reason: entryKey + " already exists"}
+		}
+		if mutation.Data == nil {
+			return invalidMutation("no data to put")
+		}
+		data, err := mutation.Data.UnmarshalNew()
+		if err != nil {
+			return invalidMutation("failed to unmarshal the data: %v", err)
+		}
+		if data.ProtoReflect().Descriptor().FullName() != d.msg.ProtoReflect().Descriptor().FullName() {
+			return invalidMutation("the data is not a %s", d.msg.ProtoReflect().Descriptor().FullName())
+		}
+		m := data.ProtoReflect().Get(fd).Map()
+		if !m.Has(mk) {
+			return invalidMutation("%s is not set in the data", entryKey)
+		}
+		updateMsg.Mutable(fd).Map().Set(mk, m.Get(mk))
+
+	case channeldpb.ChannelDataTransactionMessage_Mutation_REMOVE:
+		if !exists {
+			return &transactionError{result: channeldpb.ChannelDataTransactionResultMessage_PRECONDITION_FAILED, reason: entryKey + " doesn't exist"}
+		}
+		if fd.MapValue().Kind() != protoreflect.MessageKind {
+			return invalidMutation("the map value of %s is not removable", mutation.Field)
+		}
+		removedFd := fd.MapValue().Message().Fields().ByName("removed")
+		if removedFd == nil || removedFd.Kind() != protoreflect.BoolKind {
+			return invalidMutation("the map value of %s is not removable", mutation.Field)
+		}
+		removed := proto.Clone(current.Message().Interface()).ProtoReflect()
+		removed.Set(removedFd, protoreflect.ValueOfBool(true))
+		updateMsg.Mutable(fd).Map().Set(mk, protoreflect.ValueOfMessage(removed))
+
+	case channeldpb.ChannelDataTransactionMessage_Mutation_INCREMENT:
+		if mutation.ValueField == "" {
+			kind := fd.MapValue().Kind()
+			if !isNumericKind(kind) {
+				return invalidMutation("the map value of %s is not numeric", mutation.Field)
+			}
+			if !exists {
+				current = fd.MapValue().Default()
+			}
+			result, ok := addDelta(kind, current, mutation.Delta)
+			if !ok || !inMutationRange(mutation, numericValue(kind, result)) {
+				return &transactionError{result: channeldpb.ChannelDataTransactionResultMessage_OUT_OF_RANGE, reason: entryKey}
+			}
+			updateMsg.Mutable(fd).Map().Set(mk, result)
+		} else {
+			if fd.MapValue().Kind() != protoreflect.MessageKind {
+				return invalidMutation("the map value of %s is not a message", mutation.Field)
+			}
+			valueFd := fd.MapValue().Message().Fields().ByName(protoreflect.Name(mutation.ValueField))
+			if valueFd == nil || valueFd.IsList() || !isNumericKind(valueFd.Kind()) {
+				return invalidMutation("%s is not a numeric field of the map value", mutation.ValueField)
+			}
+			var value protoreflect.Message
+			if exists {
+				value = proto.Clone(current.Message().Interface()).ProtoReflect()
+			} else {
+				value = updateMsg.Get(fd).Map().NewValue().Message()
+			}
+			result, ok := addDelta(valueFd.Kind(), value.Get(valueFd), mutation.Delta)
+			if !ok || !inMutationRange(mutation, numericValue(valueFd.Kind(), result)) {
+				return &transactionError{result: channeldpb.ChannelDataTransactionResultMessage_OUT_OF_RANGE, reason: entryKey + "." + mutation.ValueField}
+			}
+			value.Set(valueFd, result)
+			updateMsg.Mutable(fd).Map().Set(mk, protoreflect.ValueOfMessage(value))
+		}
+
+	default:
+		return invalidMutation("unknown op %d", mutation.Op)
+	}
+	return nil
+}
+
+// Applies all the mutations as one update, or none of them. Returns the index of the failed mutation and the error.
+func (d *ChannelData) applyTransaction(mutations []*channeldpb.ChannelDataTransactionMessage_Mutation, t ChannelTime, senderConnId ConnectionId) (int, *transactionError) {
+	if len(mutations) == 0 {
+		return 0, nil
+	}
+	updateMsg := d.msg.ProtoReflect().New()
+	for i, mutation := range mutations {
+		if err := d.applyMutation(updateMsg, mutation, t, senderConnId); err != nil {
+			return i, err
+		}
+	}
+	d.onUpdate(updateMsg.Interface().(common.ChannelDataMessage), t, senderConnId, nil, 0)
+	return 0, nil
+}
+
+func handleChannelDataTransaction(ctx MessageContext) {
+	msg, ok := ctx.Msg.(*channeldpb.ChannelDataTransactionMessage)
+	if !ok {
+		ctx.Connection.Logger().Error("message is not a ChannelDataTransactionMessage, will not be handled.")
+		return
+	}
+
+	// Same as handleChannelDataUpdate()
+	if ctx.Channel.ownerConnection != ctx.Connection {
+		cs := ctx.Channel.subscribedConnections[ctx.Connection]
+		if cs == nil || *cs.options.DataAccess != channeldpb.ChannelDataAccess_WRITE_ACCESS {
+			ctx.Connection.Logger().Warn("attempt to apply channel data transaction but has no access",
+				zap.String("channelType", ctx.Channel.channelType.String()),
+				zap.Uint32("channelId", uint32(ctx.Channel.id)),
+			)
+			return
+		}
+	}
+
+	data := ctx.Channel.Data()
+	if data == nil || data.msg == nil {
+		ctx.Connection.Logger().Warn("channel data is not initialized, will not apply transaction",
+			zap.Uint32("channelId", uint32(ctx.Channel.id)),
+		)
+		return
+	}
+
+	// The preconditions are checked against the channel data at the time of merging. See ChannelData.queueUpdate().
+	data.queueUpdate(ctx.Connection.Id(), func() {
+		result := &channeldpb.ChannelDataTransactionResultMessage{}
+		failedIndex, err := data.applyTransaction(msg.Mutations, ctx.arrivalTime, ctx.Connection.Id())
+		if err != nil {
+			result.Result = err.result
+			result.FailedIndex = uint32(failedIndex)
+			ctx.Connection.Logger().Debug("rejected channel data transaction",
+				zap.Uint32("channelId", uint32(ctx.Channel.id)),
+				zap.Int("failedIndex", failedIndex),
+				zap.Error(err),
+			)
+		}
+		result.Version = data.Version()
+		channelDataTransactions.WithLabelValues(ctx.Channel.channelType.String(), result.Result.String()).Inc()
+
+		ctx.Msg = result
+		ctx.Connection.Send(ctx)
+	})
+}
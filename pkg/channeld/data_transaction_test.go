@@ -0,0 +1,140 @@
+package channeld
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestChannelDataTransaction(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	other := addTestConnection(channeldpb.ConnectionType_SERVER)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	ch.removing = 1
+	// kv2[1].num is the currency, and kv is the inventory
+	ch.InitData(&testpb.TestMapMessage{
+		Kv2: map[uint32]*testpb.TestMapMessage_StringWrapper{1: {Content: "gold", Num: 100}},
+	}, nil)
+	data := func() *testpb.TestMapMessage {
+		return ch.GetDataMessage().(*testpb.TestMapMessage)
+	}
+
+	apply := func(sender *Connection, mutations ...*channeldpb.ChannelDataTransactionMessage_Mutation) *channeldpb.ChannelDataTransactionResultMessage {
+		handleChannelDataTransaction(MessageContext{
+			MsgType:    channeldpb.MessageType_CHANNEL_DATA_TRANSACTION,
+			Msg:        &channeldpb.ChannelDataTransactionMessage{Mutations: mutations},
+			Connection: sender,
+			Channel:    ch,
+		})
+		result, _ := sender.latestMsg().(*channeldpb.ChannelDataTransactionResultMessage)
+		return result
+	}
+	debit := func(amount int64) *channeldpb.ChannelDataTransactionMessage_Mutation {
+		return &channeldpb.ChannelDataTransactionMessage_Mutation{
+			Op:         channeldpb.ChannelDataTransactionMessage_Mutation_INCREMENT,
+			Field:      "kv2",
+			Key:        "1",
+			ValueField: "num",
+			Delta:      -amount,
+			Min:        proto.Int64(0),
+		}
+	}
+	addItem := func(key uint32, name string) *channeldpb.ChannelDataTransactionMessage_Mutation {
+		any, _ := anypb.New(&testpb.TestMapMessage{Kv: map[uint32]string{key: name}})
+		return &channeldpb.ChannelDataTransactionMessage_Mutation{
+			Op:       channeldpb.ChannelDataTransactionMessage_Mutation_PUT,
+			Field:    "kv",
+			Key:      fmt.Sprint(key),
+			Data:     any,
+			IfAbsent: true,
+		}
+	}
+
+	// Buy the sword
+	result := apply(server, debit(30), addItem(7, "sword"))
+	assert.Equal(t, channeldpb.ChannelDataTransactionResultMessage_APPLIED, result.Result)
+	assert.EqualValues(t, 1, result.Version)
+	assert.EqualValues(t, 70, data().Kv2[1].Num)
+	assert.Equal(t, "gold", data().Kv2[1].Content)
+	assert.Equal(t, "sword", data().Kv[7])
+	// Fanned out as a normal update
+	assert.Equal(t, 1, ch.Data().updateMsgBuffer.Len())
+
+	// The item can't be duplicated, and the currency is not debited
+	result = apply(server, debit(30), addItem(7, "sword"))
+	assert.Equal(t, channeldpb.ChannelDataTransactionResultMessage_PRECONDITION_FAILED, result.Result)
+	assert.EqualValues(t, 1, result.FailedIndex)
+	assert.EqualValues(t, 1, result.Version)
+	assert.EqualValues(t, 70, data().Kv2[1].Num)
+
+	// Can't be overdrawn. The later mutation sees the result of the earlier one.
+	result = apply(server, debit(50), debit(50))
+	assert.Equal(t, channeldpb.ChannelDataTransactionResultMessage_OUT_OF_RANGE, result.Result)
+	assert.EqualValues(t, 1, result.FailedIndex)
+	assert.EqualValues(t, 70, data().Kv2[1].Num)
+
+	// The missing entry is created by the increment
+	result = apply(server, &channeldpb.ChannelDataTransactionMessage_Mutation{
+		Op:         channeldpb.ChannelDataTransactionMessage_Mutation_INCREMENT,
+		Field:      "kv2",
+		Key:        "2",
+		ValueField: "num",
+		Delta:      5,
+	})
+	assert.Equal(t, channeldpb.ChannelDataTransactionResultMessage_APPLIED, result.Result)
+	assert.EqualValues(t, 5, data().Kv2[2].Num)
+
+	result = apply(server, debit(1), &channeldpb.ChannelDataTransactionMessage_Mutation{Field: "kv", Key: "abc"})
+	assert.Equal(t, channeldpb.ChannelDataTransactionResultMessage_INVALID, result.Result)
+	result = apply(server, &channeldpb.ChannelDataTransactionMessage_Mutation{Op: channeldpb.ChannelDataTransactionMessage_Mutation_REMOVE, Field: "kv", Key: "7"})
+	assert.Equal(t, channeldpb.ChannelDataTransactionResultMessage_INVALID, result.Result)
+	assert.Equal(t, "sword", data().Kv[7])
+
+	// The entry locked by another connection
+	ch.Data().locks = map[string]*dataLock{"kv2[1]": {holderConnId: other.Id(), expireTime: ch.GetTime().AddMs(60000)}}
+	result = apply(server, debit(1))
+	assert.Equal(t, channeldpb.ChannelDataTransactionResultMessage_LOCKED, result.Result)
+	assert.EqualValues(t, 70, data().Kv2[1].Num)
+
+	// No access
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	client.SubscribeToChannel(ch, nil)
+	assert.Nil(t, apply(client, debit(1)))
+}
+
+func TestChannelDataTransactionRemove(t *testing.T) {
+	InitLogs()
+	InitChannels()
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	ch.removing = 1
+	ch.InitData(&testpb.TestMergeMessage{
+		Kv: map[int64]*testpb.TestMergeMessage_StringWrapper{1: {Content: "potion"}},
+	}, &channeldpb.ChannelDataMergeOptions{ShouldCheckRemovableMapField: true})
+
+	remove := &channeldpb.ChannelDataTransactionMessage_Mutation{
+		Op:    channeldpb.ChannelDataTransactionMessage_Mutation_REMOVE,
+		Field: "kv",
+		Key:   "1",
+	}
+	_, err := ch.Data().applyTransaction([]*channeldpb.ChannelDataTransactionMessage_Mutation{remove}, 0, server.Id())
+	assert.Nil(t, err)
+	assert.NotContains(t, ch.GetDataMessage().(*testpb.TestMergeMessage).Kv, int64(1))
+
+	// The item can't be consumed twice
+	i, err := ch.Data().applyTransaction([]*channeldpb.ChannelDataTransactionMessage_Mutation{remove}, 0, server.Id())
+	assert.Equal(t, 0, i)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, channeldpb.ChannelDataTransactionResultMessage_PRECONDITION_FAILED, err.result)
+	}
+}
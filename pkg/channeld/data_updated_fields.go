@@ -0,0 +1,49 @@
+package channeld
+
+import (
+	"sort"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Returns the sorted paths of the fields populated in the update message. See channeldpb.ChannelDataUpdateMessage.UpdatedFields.
+func updatedFieldPaths(msg protoreflect.Message) []string {
+	paths := make([]string, 0)
+	appendUpdatedFieldPaths(msg, "", &paths)
+	sort.Strings(paths)
+	return paths
+}
+
+func appendUpdatedFieldPaths(msg protoreflect.Message, prefix string, paths *[]string) {
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		path := prefix + string(fd.Name())
+		switch {
+		case fd.IsMap():
+			v.Map().Range(func(mk protoreflect.MapKey, mv protoreflect.Value) bool {
+				entryPath := path + "." + mk.String()
+				if fd.MapValue().Kind() == protoreflect.MessageKind {
+					appendMessagePaths(mv.Message(), entryPath, paths)
+				} else {
+					*paths = append(*paths, entryPath)
+				}
+				return true
+			})
+		case fd.IsList():
+			*paths = append(*paths, path)
+		case fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind:
+			appendMessagePaths(v.Message(), path, paths)
+		default:
+			*paths = append(*paths, path)
+		}
+		return true
+	})
+}
+
+// The empty message is a leaf, e.g. the entity that is added without any field set.
+func appendMessagePaths(msg protoreflect.Message, path string, paths *[]string) {
+	n := len(*paths)
+	appendUpdatedFieldPaths(msg, path+".", paths)
+	if len(*paths) == n {
+		*paths = append(*paths, path)
+	}
+}
@@ -0,0 +1,63 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestUpdatedFieldPaths(t *testing.T) {
+	msg := &testpb.TestFieldMaskMessage{
+		Name: "a",
+		Msg:  &testpb.TestFieldMaskMessage_NestedMessage{P2: 1},
+		List: []*testpb.TestFieldMaskMessage_NestedMessage{{P1: 1}},
+		Kv1: map[int64]*testpb.TestFieldMaskMessage_NestedMessage{
+			42: {P1: 1, P2: 2},
+			// Added without any field set
+			43: {},
+		},
+		Kv2: map[int64]string{7: "b"},
+	}
+	assert.Equal(t, []string{"kv1.42.p1", "kv1.42.p2", "kv1.43", "kv2.7", "list", "msg.p2", "name"}, updatedFieldPaths(msg.ProtoReflect()))
+	assert.Empty(t, updatedFieldPaths((&testpb.TestFieldMaskMessage{}).ProtoReflect()))
+}
+
+func TestFanOutUpdatedFields(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	owner := addTestConnection(channeldpb.ConnectionType_SERVER)
+	withFields := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	withoutFields := addTestConnection(channeldpb.ConnectionType_CLIENT)
+
+	testChannel, _ := CreateChannel(channeldpb.ChannelType_TEST, owner)
+	// Stop the channel.Tick() goroutine
+	testChannel.removing = 1
+	testChannel.InitData(&testpb.TestChannelDataMessage{Text: "a", Num: 1}, nil)
+	testChannel.tickInterval = time.Hour
+
+	withFields.SubscribeToChannel(testChannel, &channeldpb.ChannelSubscriptionOptions{FanOutIntervalMs: proto.Uint32(20), UpdatedFields: proto.Bool(true)})
+	withoutFields.SubscribeToChannel(testChannel, &channeldpb.ChannelSubscriptionOptions{FanOutIntervalMs: proto.Uint32(20)})
+
+	// The first fan-out has the whole channel data
+	tick := ChannelTime(0).AddMs(100)
+	testChannel.tickData(tick)
+	assert.Nil(t, withFields.latestMsg().(*channeldpb.ChannelDataUpdateMessage).UpdatedFields)
+
+	testChannel.Data().OnUpdate(&testpb.TestChannelDataMessage{Num: 2}, tick, owner.Id(), nil)
+	tick = tick.AddMs(100)
+	testChannel.tickData(tick)
+	assert.Equal(t, []string{"num"}, withFields.latestMsg().(*channeldpb.ChannelDataUpdateMessage).UpdatedFields)
+	assert.Nil(t, withoutFields.latestMsg().(*channeldpb.ChannelDataUpdateMessage).UpdatedFields)
+
+	testChannel.Data().OnUpdate(&testpb.TestChannelDataMessage{Text: "b"}, tick, owner.Id(), nil)
+	testChannel.Data().OnUpdate(&testpb.TestChannelDataMessage{Num: 3}, tick.AddMs(10), owner.Id(), nil)
+	tick = tick.AddMs(100)
+	testChannel.tickData(tick)
+	assert.Equal(t, []string{"num", "text"}, withFields.latestMsg().(*channeldpb.ChannelDataUpdateMessage).UpdatedFields)
+}
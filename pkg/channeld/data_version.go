@@ -0,0 +1,60 @@
+package channeld
+
+import (
+	"github.com/metaworking/channeld/pkg/common"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type fieldVersion struct {
+	version uint64
+	connId  ConnectionId
+}
+
+// Returns the version of the channel data, i.e. the number of the updates accepted.
+func (d *ChannelData) Version() uint64 {
+	return d.msgIndex
+}
+
+// Calls fn for each versioned field (or map entry) that is set in the update message, with the key in fieldVersions.
+func (d *ChannelData) rangeVersionedFields(updateMsg common.ChannelDataMessage, fn func(key string)) {
+	m := updateMsg.ProtoReflect()
+	for _, name := range d.versionedFields {
+		fd := m.Descriptor().Fields().ByName(protoreflect.Name(name))
+		if fd == nil || !m.Has(fd) {
+			continue
+		}
+		if fd.IsMap() {
+			m.Get(fd).Map().Range(func(mk protoreflect.MapKey, _ protoreflect.Value) bool {
+				fn(mapEntryKey(name, mk))
+				return true
+			})
+		} else {
+			fn(name)
+		}
+	}
+}
+
+// Should be called after the msgIndex is increased.
+func (d *ChannelData) recordFieldVersions(updateMsg common.ChannelDataMessage, senderConnId ConnectionId) {
+	if len(d.versionedFields) == 0 {
+		return
+	}
+	if d.fieldVersions == nil {
+		d.fieldVersions = make(map[string]fieldVersion)
+	}
+	d.rangeVersionedFields(updateMsg, func(key string) {
+		d.fieldVersions[key] = fieldVersion{version: d.msgIndex, connId: senderConnId}
+	})
+}
+
+// Returns the versioned fields in the update message that have been changed by other connections since the base version.
+// The changes made by the sender itself are not conflicts, as the sender may not have received the fan-out of them yet.
+func (d *ChannelData) checkConflicts(updateMsg common.ChannelDataMessage, baseVersion uint64, senderConnId ConnectionId) []string {
+	var conflictFields []string
+	d.rangeVersionedFields(updateMsg, func(key string) {
+		if fv, exists := d.fieldVersions[key]; exists && fv.version > baseVersion && fv.connId != senderConnId {
+			conflictFields = append(conflictFields, key)
+		}
+	})
+	return conflictFields
+}
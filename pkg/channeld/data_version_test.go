@@ -0,0 +1,80 @@
+package channeld
+
+import (
+	"testing"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestVersionedFieldsConflict(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		ACLSettings:     ACLSettingsType{Sub: ChannelAccessLevel_Any},
+		VersionedFields: []string{"kv"},
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	serverA := addTestConnection(channeldpb.ConnectionType_SERVER)
+	serverB := addTestConnection(channeldpb.ConnectionType_SERVER)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, serverA)
+	ch.InitData(&testpb.TestMapMessage{Kv: map[uint32]string{}}, nil)
+	serverB.SubscribeToChannel(ch, &channeldpb.ChannelSubscriptionOptions{DataAccess: channeldpb.ChannelDataAccess_WRITE_ACCESS.Enum()})
+
+	update := func(conn *Connection, kv map[uint32]string, baseVersion *uint64) {
+		any, _ := anypb.New(&testpb.TestMapMessage{Kv: kv})
+		handleChannelDataUpdate(MessageContext{
+			MsgType:    channeldpb.MessageType_CHANNEL_DATA_UPDATE,
+			Msg:        &channeldpb.ChannelDataUpdateMessage{Data: any, BaseVersion: baseVersion},
+			Connection: conn,
+			Channel:    ch,
+		})
+	}
+	data := func() map[uint32]string {
+		return ch.GetDataMessage().(*testpb.TestMapMessage).Kv
+	}
+
+	update(serverA, map[uint32]string{1: "a"}, proto.Uint64(0))
+	assert.EqualValues(t, 1, ch.Data().Version())
+
+	// Based on the stale version, and the entry has been changed by A
+	update(serverB, map[uint32]string{1: "b", 2: "b"}, proto.Uint64(0))
+	assert.EqualValues(t, 1, ch.Data().Version())
+	assert.Equal(t, "a", data()[1])
+	// The whole update is rejected
+	assert.NotContains(t, data(), uint32(2))
+	conflictMsg, ok := serverB.latestMsg().(*channeldpb.ChannelDataConflictMessage)
+	if assert.True(t, ok) {
+		assert.EqualValues(t, 0, conflictMsg.BaseVersion)
+		assert.EqualValues(t, 1, conflictMsg.CurrentVersion)
+		assert.Equal(t, []string{"kv[1]"}, conflictMsg.ConflictFields)
+		rejected := &testpb.TestMapMessage{}
+		conflictMsg.Data.UnmarshalTo(rejected)
+		assert.Equal(t, "b", rejected.Kv[2])
+	}
+
+	// A different entry doesn't conflict
+	update(serverB, map[uint32]string{2: "b"}, proto.Uint64(0))
+	assert.EqualValues(t, 2, ch.Data().Version())
+	assert.Equal(t, "b", data()[2])
+
+	// The sender's own changes don't conflict
+	update(serverA, map[uint32]string{1: "c"}, proto.Uint64(0))
+	assert.EqualValues(t, 3, ch.Data().Version())
+	assert.Equal(t, "c", data()[1])
+
+	// Based on the latest version
+	update(serverB, map[uint32]string{1: "d"}, proto.Uint64(3))
+	assert.Equal(t, "d", data()[1])
+
+	// Not checked without the base version
+	update(serverA, map[uint32]string{1: "e"}, nil)
+	assert.Equal(t, "e", data()[1])
+	assert.EqualValues(t, 5, ch.Data().Version())
+}
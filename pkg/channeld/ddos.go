@@ -14,7 +14,15 @@ var failedAuthCounters = make(map[string]int)
 var ipBlacklist = make(map[string]time.Time)
 var pitBlacklist = make(map[string]time.Time)
 
+// Set when the event listeners and the check of the unauthenticated connections are started
+var antiDDoSInitialized bool
+
 func InitAntiDDoS() {
+	if antiDDoSInitialized {
+		return
+	}
+	antiDDoSInitialized = true
+
 	Event_AuthComplete.Listen(func(data AuthEventData) {
 		if data.Connection.GetConnectionType() == channeldpb.ConnectionType_SERVER {
 			return
@@ -69,9 +77,9 @@ func checkUnauthConns() {
 			if conn.IsClosing() {
 				return true
 			}
-			if conn.state == ConnectionState_UNAUTHENTICATED && time.Since(conn.connTime).Milliseconds() >= GlobalSettings.ConnectionAuthTimeoutMs {
+			if conn.State() < ConnectionState_READY && time.Since(conn.connTime).Milliseconds() >= GlobalSettings.ConnectionAuthTimeoutMs {
 				ipBlacklist[GetIP(conn.RemoteAddr())] = time.Now()
-				conn.Close()
+				conn.CloseWithReason(channeldpb.DisconnectMessage_AUTH_FAILURE, "auth timeout")
 				securityLogger.Info("closed and blacklisted unauthenticated connection due to timeout", zap.String("ip", conn.conn.RemoteAddr().String()))
 			}
 			return true
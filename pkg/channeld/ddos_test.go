@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -15,14 +16,14 @@ import (
 func TestUnauthTimeout(t *testing.T) {
 	InitLogs()
 	InitAntiDDoS()
+	resetAntiDDoS(t)
 	// InitChannels()
 	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
 
 	GlobalSettings.ConnectionAuthTimeoutMs = 1000
 
 	// go StartListening(channeldpb.ConnectionType_SERVER, "tcp", ":31288")
-	go StartListening(channeldpb.ConnectionType_CLIENT, "tcp", ":32108")
-	time.Sleep(time.Millisecond * 100)
+	startTestClientListener()
 
 	conn, err := net.Dial("tcp", "127.0.0.1:32108")
 	assert.NoError(t, err, "Error connecting to server")
@@ -41,6 +42,7 @@ func TestUnauthTimeout(t *testing.T) {
 func TestInvalidUsername(t *testing.T) {
 	InitLogs()
 	InitAntiDDoS()
+	resetAntiDDoS(t)
 	InitChannels()
 	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
 
@@ -49,8 +51,7 @@ func TestInvalidUsername(t *testing.T) {
 	GlobalSettings.MaxFailedAuthAttempts = 2
 	SetAuthProvider(&AlwaysFailAuthProvider{})
 
-	go StartListening(channeldpb.ConnectionType_CLIENT, "tcp", ":32108")
-	time.Sleep(time.Millisecond * 100)
+	startTestClientListener()
 
 	conn, err := net.Dial("tcp", "127.0.0.1:32108")
 	assert.NoError(t, err, "Error connecting to server")
@@ -84,6 +85,7 @@ func TestInvalidUsername(t *testing.T) {
 func TestWrongPassword(t *testing.T) {
 	InitLogs()
 	InitAntiDDoS()
+	resetAntiDDoS(t)
 	InitChannels()
 	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
 
@@ -92,8 +94,7 @@ func TestWrongPassword(t *testing.T) {
 	GlobalSettings.MaxFailedAuthAttempts = 3
 	SetAuthProvider(&FixedPasswordAuthProvider{"rightpassword"})
 
-	go StartListening(channeldpb.ConnectionType_CLIENT, "tcp", ":32108")
-	time.Sleep(time.Millisecond * 100)
+	startTestClientListener()
 
 	conn, err := net.Dial("tcp", "127.0.0.1:32108")
 	assert.NoError(t, err, "Error connecting to server")
@@ -148,6 +149,33 @@ func TestWrongPassword(t *testing.T) {
 	assert.False(t, checkConnOpen(conn), "Connection should have been closed by now")
 }
 
+// Clears the counters and the blacklists of the previous tests, and restores the settings that the test changes.
+func resetAntiDDoS(t *testing.T) {
+	reset := func() {
+		failedAuthCounters = make(map[string]int)
+		ipBlacklist = make(map[string]time.Time)
+		pitBlacklist = make(map[string]time.Time)
+	}
+	reset()
+	development, authTimeout, maxFailedAuth := GlobalSettings.Development, GlobalSettings.ConnectionAuthTimeoutMs, GlobalSettings.MaxFailedAuthAttempts
+	provider := authProvider
+	t.Cleanup(func() {
+		reset()
+		GlobalSettings.Development, GlobalSettings.ConnectionAuthTimeoutMs, GlobalSettings.MaxFailedAuthAttempts = development, authTimeout, maxFailedAuth
+		SetAuthProvider(provider)
+	})
+}
+
+var testClientListenerOnce sync.Once
+
+// The listener can't be stopped, so the tests share the one on :32108 instead of failing to bind the port again.
+func startTestClientListener() {
+	testClientListenerOnce.Do(func() {
+		go StartListening(channeldpb.ConnectionType_CLIENT, "tcp", ":32108")
+	})
+	time.Sleep(time.Millisecond * 100)
+}
+
 func sendMessage(conn net.Conn, msgType uint32, msg proto.Message) {
 	msgBody, _ := proto.Marshal(msg)
 	p := &channeldpb.Packet{
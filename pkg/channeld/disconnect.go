@@ -0,0 +1,63 @@
+package channeld
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+)
+
+// How long the connection stays open after the DisconnectMessage is sent, for the message to be flushed.
+const DisconnectNotifyDelay = 100 * time.Millisecond
+
+// Notifies the connection with the DisconnectMessage (best-effort), then closes it. The owners of the channels that the
+// connection subscribed to are also notified with the reason, when the subscriptions are removed.
+// The connections that don't know the DisconnectMessage are closed immediately. Only the first reason is kept.
+func (c *Connection) CloseWithReason(reason channeldpb.DisconnectMessage_Reason, detail string) {
+	if c.IsClosing() {
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&c.disconnectReason, 0, int32(reason)) {
+		// Already being closed with another reason
+		return
+	}
+	c.Logger().Info("closing connection", zap.String("reason", reason.String()), zap.String("detail", detail))
+
+	msg := &channeldpb.DisconnectMessage{ConnId: uint32(c.id), Reason: reason, Detail: detail}
+	if c.downgradeMessage(channeldpb.MessageType_DISCONNECT, msg) == nil {
+		c.Close()
+		return
+	}
+	c.Send(MessageContext{
+		MsgType:   channeldpb.MessageType_DISCONNECT,
+		Msg:       msg,
+		ChannelId: uint32(GlobalChannelId),
+	})
+	time.AfterFunc(DisconnectNotifyDelay, c.Close)
+}
+
+func closeWithReason(conn ConnectionInChannel, reason channeldpb.DisconnectMessage_Reason, detail string) {
+	if c, ok := conn.(*Connection); ok {
+		c.CloseWithReason(reason, detail)
+	} else {
+		conn.Close()
+	}
+}
+
+// The reason that the connection is closed with. UNSPECIFIED if the connection is lost, or closed without a reason.
+func (c *Connection) DisconnectReason() channeldpb.DisconnectMessage_Reason {
+	return channeldpb.DisconnectMessage_Reason(atomic.LoadInt32(&c.disconnectReason))
+}
+
+// Notifies the channel owner that the subscribed connection is closed.
+func (ch *Channel) sendDisconnected(conn *Connection) {
+	if ch.ownerConnection == nil || ch.ownerConnection.IsClosing() {
+		return
+	}
+	ch.ownerConnection.Send(MessageContext{
+		MsgType:   channeldpb.MessageType_DISCONNECT,
+		Msg:       &channeldpb.DisconnectMessage{ConnId: uint32(conn.id), Reason: conn.DisconnectReason()},
+		ChannelId: uint32(ch.id),
+	})
+}
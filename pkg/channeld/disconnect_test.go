@@ -0,0 +1,58 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloseWithReason(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	server.setProtocolVersion(ProtocolVersion)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	client.setProtocolVersion(ProtocolVersion)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	ch.removing = 1
+	client.SubscribeToChannel(ch, nil)
+
+	// Kicked by the server
+	handleDisconnect(MessageContext{
+		MsgType:    channeldpb.MessageType_DISCONNECT,
+		Msg:        &channeldpb.DisconnectMessage{ConnId: uint32(client.Id()), Detail: "cheating"},
+		Connection: server,
+		Channel:    globalChannel,
+	})
+	disconnectMsg, ok := client.latestMsg().(*channeldpb.DisconnectMessage)
+	if assert.True(t, ok) {
+		assert.Equal(t, channeldpb.DisconnectMessage_KICKED, disconnectMsg.Reason)
+		assert.Equal(t, "cheating", disconnectMsg.Detail)
+	}
+	// Still open for the message to be sent
+	assert.False(t, client.IsClosing())
+
+	// The first reason is kept
+	client.CloseWithReason(channeldpb.DisconnectMessage_IDLE_TIMEOUT, "")
+	assert.Equal(t, channeldpb.DisconnectMessage_KICKED, client.DisconnectReason())
+	assert.Eventually(t, client.IsClosing, time.Second, 10*time.Millisecond)
+
+	// The owner is notified when the subscription is removed
+	ch.tickConnections()
+	disconnectMsg, ok = server.latestMsg().(*channeldpb.DisconnectMessage)
+	if assert.True(t, ok) {
+		assert.EqualValues(t, client.Id(), disconnectMsg.ConnId)
+		assert.Equal(t, channeldpb.DisconnectMessage_KICKED, disconnectMsg.Reason)
+	}
+
+	// The older SDK is closed immediately without the message
+	oldClient := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	oldClient.CloseWithReason(channeldpb.DisconnectMessage_AUTH_FAILURE, "")
+	assert.True(t, oldClient.IsClosing())
+	assert.Nil(t, oldClient.latestMsg())
+	assert.Equal(t, channeldpb.DisconnectMessage_AUTH_FAILURE, oldClient.DisconnectReason())
+}
@@ -0,0 +1,106 @@
+package channeld
+
+import (
+	"sync"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+)
+
+type DuplicateLoginPolicy uint8
+
+const (
+	// Both connections stay logged in. The old connection is notified.
+	DuplicateLoginPolicy_AllowBoth DuplicateLoginPolicy = 0
+	// The old connection is notified and closed.
+	DuplicateLoginPolicy_KickOld DuplicateLoginPolicy = 1
+	// The new connection fails the auth with AuthResultMessage_DUPLICATE_LOGIN.
+	DuplicateLoginPolicy_RejectNew DuplicateLoginPolicy = 2
+)
+
+// How long the kicked connection stays open, for the DuplicateLoginMessage to be sent.
+const DuplicateLoginKickDelay = 100 * time.Millisecond
+
+// Decides the policy for a duplicate login. The existing connection is the latest one logged in with the same user id.
+type DuplicateLoginResolver func(existing *Connection, newConn *Connection) DuplicateLoginPolicy
+
+var duplicateLoginResolver DuplicateLoginResolver
+
+// Overrides GlobalSettings.DuplicateLoginPolicy, e.g. to let a VIP player log in on multiple devices.
+func SetDuplicateLoginResolver(value DuplicateLoginResolver) {
+	duplicateLoginResolver = value
+}
+
+// The logged-in client connections by the user id, the oldest first.
+var userConnections = make(map[string][]*Connection)
+var userConnectionsLock sync.Mutex
+
+// Applies the duplicate login policy to the client connection that has passed the auth. The connections of the same account
+// on different platforms or devices are also duplicate logins. See UserStore.
+// Returns false if the connection should be rejected.
+func resolveDuplicateLogin(newConn *Connection, userId string) bool {
+	if newConn.GetConnectionType() != channeldpb.ConnectionType_CLIENT || userId == "" {
+		return true
+	}
+
+	userConnectionsLock.Lock()
+	defer userConnectionsLock.Unlock()
+
+	var existingConns []*Connection
+	for _, conn := range userConnections[userId] {
+		if conn != newConn && !conn.IsClosing() {
+			existingConns = append(existingConns, conn)
+		}
+	}
+
+	if len(existingConns) > 0 {
+		policy := GlobalSettings.DuplicateLoginPolicy
+		if duplicateLoginResolver != nil {
+			policy = duplicateLoginResolver(existingConns[len(existingConns)-1], newConn)
+		}
+
+		if policy == DuplicateLoginPolicy_RejectNew {
+			securityLogger.Info("rejected duplicate login", zap.String("userId", userId), zap.Uint32("connId", uint32(newConn.Id())))
+			return false
+		}
+
+		kicked := policy == DuplicateLoginPolicy_KickOld
+		for _, conn := range existingConns {
+			conn.Send(MessageContext{
+				MsgType:   channeldpb.MessageType_DUPLICATE_LOGIN,
+				Msg:       &channeldpb.DuplicateLoginMessage{NewConnId: uint32(newConn.Id()), Kicked: kicked},
+				ChannelId: uint32(GlobalChannelId),
+			})
+			if kicked {
+				securityLogger.Info("kicked connection due to duplicate login", zap.String("userId", userId), zap.Uint32("connId", uint32(conn.Id())))
+				kickedConn := conn
+				time.AfterFunc(DuplicateLoginKickDelay, func() {
+					kickedConn.CloseWithReason(channeldpb.DisconnectMessage_DUPLICATE_LOGIN, "")
+				})
+			}
+		}
+		if kicked {
+			existingConns = nil
+		}
+	}
+
+	userConnections[userId] = append(existingConns, newConn)
+	newConn.AddCloseHandler(func() {
+		userConnectionsLock.Lock()
+		defer userConnectionsLock.Unlock()
+		conns := userConnections[userId]
+		for i, conn := range conns {
+			if conn == newConn {
+				conns = append(conns[:i], conns[i+1:]...)
+				break
+			}
+		}
+		if len(conns) == 0 {
+			delete(userConnections, userId)
+		} else {
+			userConnections[userId] = conns
+		}
+	})
+	return true
+}
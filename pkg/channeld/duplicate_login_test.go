@@ -0,0 +1,84 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuplicateLogin(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+	defer func() {
+		GlobalSettings.DuplicateLoginPolicy = DuplicateLoginPolicy_AllowBoth
+		SetDuplicateLoginResolver(nil)
+	}()
+
+	login := func(pit string) *Connection {
+		c := addTestConnection(channeldpb.ConnectionType_CLIENT)
+		onAuthComplete(MessageContext{
+			MsgType:    channeldpb.MessageType_AUTH,
+			Msg:        &channeldpb.AuthMessage{PlayerIdentifierToken: pit},
+			Connection: c,
+			Channel:    globalChannel,
+		}, channeldpb.AuthResultMessage_SUCCESSFUL, pit)
+		return c
+	}
+
+	// Allow both
+	c1 := login("p1")
+	c2 := login("p1")
+	assert.Equal(t, ConnectionState_READY, c1.State())
+	assert.Equal(t, ConnectionState_READY, c2.State())
+	dupMsg, ok := c1.latestMsg().(*channeldpb.DuplicateLoginMessage)
+	if assert.True(t, ok) {
+		assert.EqualValues(t, c2.Id(), dupMsg.NewConnId)
+		assert.False(t, dupMsg.Kicked)
+	}
+	// Other players are not affected
+	c3 := login("p2")
+	assert.IsType(t, &channeldpb.AuthResultMessage{}, c2.latestMsg())
+	assert.IsType(t, &channeldpb.AuthResultMessage{}, c3.latestMsg())
+
+	// Reject the new
+	GlobalSettings.DuplicateLoginPolicy = DuplicateLoginPolicy_RejectNew
+	c4 := login("p1")
+	assert.Equal(t, ConnectionState_CONNECTING, c4.State())
+	authResult, ok := c4.latestMsg().(*channeldpb.AuthResultMessage)
+	if assert.True(t, ok) {
+		assert.Equal(t, channeldpb.AuthResultMessage_DUPLICATE_LOGIN, authResult.Result)
+	}
+	assert.IsType(t, &channeldpb.AuthResultMessage{}, c2.latestMsg())
+
+	// The resolver overrides the policy and kicks all the old connections
+	var resolvedExisting *Connection
+	SetDuplicateLoginResolver(func(existing *Connection, newConn *Connection) DuplicateLoginPolicy {
+		resolvedExisting = existing
+		return DuplicateLoginPolicy_KickOld
+	})
+	c5 := login("p1")
+	assert.Same(t, c2, resolvedExisting)
+	assert.Equal(t, ConnectionState_READY, c5.State())
+	for _, c := range []*Connection{c1, c2} {
+		dupMsg, ok := c.latestMsg().(*channeldpb.DuplicateLoginMessage)
+		if assert.True(t, ok) {
+			assert.EqualValues(t, c5.Id(), dupMsg.NewConnId)
+			assert.True(t, dupMsg.Kicked)
+		}
+	}
+	assert.Eventually(t, func() bool {
+		return c1.IsClosing() && c2.IsClosing()
+	}, time.Second, 10*time.Millisecond)
+	assert.False(t, c5.IsClosing())
+	assert.False(t, c3.IsClosing())
+
+	// The closed connections are no longer tracked
+	c5.Close()
+	userConnectionsLock.Lock()
+	_, exists := userConnections["p1"]
+	userConnectionsLock.Unlock()
+	assert.False(t, exists)
+}
@@ -16,7 +16,7 @@ func TestEntityChannelGroupController(t *testing.T) {
 	charA := EntityId(1)
 	pcA := EntityId(2)
 	psA := EntityId(3)
-	chA := createChannelWithId(common.ChannelId(charA), channeldpb.ChannelType_ENTITY, nil)
+	chA := createChannelWithId(common.ChannelId(charA), channeldpb.ChannelType_ENTITY, nil, "")
 	chA.entityController.AddToGroup(channeldpb.EntityGroupType_HANDOVER, []EntityId{charA, pcA, psA})
 	handoverEntities := chA.entityController.GetHandoverEntities()
 	assert.Equal(t, 3, len(handoverEntities))
@@ -30,7 +30,7 @@ func TestEntityChannelGroupController(t *testing.T) {
 	charB := EntityId(4)
 	pcB := EntityId(5)
 	psB := EntityId(6)
-	chB := createChannelWithId(common.ChannelId(charB), channeldpb.ChannelType_ENTITY, nil)
+	chB := createChannelWithId(common.ChannelId(charB), channeldpb.ChannelType_ENTITY, nil, "")
 	chB.entityController.AddToGroup(channeldpb.EntityGroupType_HANDOVER, []EntityId{charB, pcB, psB})
 
 	// Triggers cross-server attack
@@ -52,12 +52,12 @@ func TestEntityChannelGroupController(t *testing.T) {
 	 * If A gets down from the vehicle, A should be handed over to the spatial channel corresponding to its current location.
 	 */
 	vehicle := EntityId(7)
-	chV := createChannelWithId(common.ChannelId(vehicle), channeldpb.ChannelType_ENTITY, nil)
+	chV := createChannelWithId(common.ChannelId(vehicle), channeldpb.ChannelType_ENTITY, nil, "")
 
 	charC := EntityId(8)
 	pcC := EntityId(9)
 	psC := EntityId(10)
-	chC := createChannelWithId(common.ChannelId(charC), channeldpb.ChannelType_ENTITY, nil)
+	chC := createChannelWithId(common.ChannelId(charC), channeldpb.ChannelType_ENTITY, nil, "")
 	chC.entityController.AddToGroup(channeldpb.EntityGroupType_HANDOVER, []EntityId{charC, pcC, psC})
 
 	// Character C gets into the vehicle
@@ -0,0 +1,43 @@
+package channeld
+
+import (
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+)
+
+// Sends ErrorMessage back to the sender of the failed control message in the context, so the SDK can surface the error
+// instead of waiting for a response that never comes. The user-space messages and the internal messages are not replied.
+func replyError(ctx MessageContext, code channeldpb.ErrorMessage_Code, message string) {
+	if ctx.MsgType >= channeldpb.MessageType_USER_SPACE_START || !ctx.HasConnection() {
+		return
+	}
+
+	if ctx.Channel != nil {
+		ctx.Channel.countStats(&ctx.Channel.statsCounters.errors)
+	}
+	ctx.Connection.Send(MessageContext{
+		MsgType: channeldpb.MessageType_ERROR,
+		Msg: &channeldpb.ErrorMessage{
+			MsgType: uint32(ctx.MsgType),
+			Code:    code,
+			Message: message,
+		},
+		ChannelId: ctx.ChannelId,
+		StubId:    ctx.StubId,
+	})
+	ctx.Connection.Logger().VeryVerbose("replied error",
+		zap.Uint32("msgType", uint32(ctx.MsgType)),
+		zap.Stringer("code", code),
+		zap.String("message", message),
+	)
+}
+
+// Same as replyError, for the message that fails before its context is created. See Connection.receiveMessage().
+func (c *Connection) replyError(mp *channeldpb.MessagePack, code channeldpb.ErrorMessage_Code, message string) {
+	replyError(MessageContext{
+		MsgType:    channeldpb.MessageType(mp.MsgType),
+		ChannelId:  mp.ChannelId,
+		StubId:     mp.StubId,
+		Connection: c,
+	}, code, message)
+}
@@ -0,0 +1,63 @@
+package channeld
+
+import (
+	"testing"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplyError(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	// Stop the channel.Tick() goroutine
+	ch.removing = 1
+
+	// Unsubscribing from a nonexistent channel
+	client.receiveMessage(&channeldpb.MessagePack{
+		ChannelId: 12345,
+		MsgType:   uint32(channeldpb.MessageType_UNSUB_FROM_CHANNEL),
+		StubId:    1,
+	})
+	if msg, ok := client.latestMsg().(*channeldpb.ErrorMessage); assert.True(t, ok) {
+		assert.EqualValues(t, channeldpb.MessageType_UNSUB_FROM_CHANNEL, msg.MsgType)
+		assert.Equal(t, channeldpb.ErrorMessage_CHANNEL_NOT_FOUND, msg.Code)
+		assert.NotEmpty(t, msg.Message)
+	}
+
+	// The user-space message is not replied
+	client.receiveMessage(&channeldpb.MessagePack{
+		ChannelId: 12345,
+		MsgType:   uint32(channeldpb.MessageType_USER_SPACE_START),
+	})
+	assert.Len(t, client.testQueue(), 1)
+
+	// Failed in the handler
+	handleSubToChannel(MessageContext{
+		MsgType:    channeldpb.MessageType_SUB_TO_CHANNEL,
+		Msg:        &channeldpb.SubscribedToChannelMessage{ConnId: 99999},
+		Connection: server,
+		Channel:    ch,
+		ChannelId:  uint32(ch.id),
+	})
+	if msg, ok := server.latestMsg().(*channeldpb.ErrorMessage); assert.True(t, ok) {
+		assert.EqualValues(t, channeldpb.MessageType_SUB_TO_CHANNEL, msg.MsgType)
+		assert.Equal(t, channeldpb.ErrorMessage_CONNECTION_NOT_FOUND, msg.Code)
+	}
+
+	handleChannelDataUpdate(MessageContext{
+		MsgType:    channeldpb.MessageType_CHANNEL_DATA_UPDATE,
+		Msg:        &channeldpb.ChannelDataUpdateMessage{},
+		Connection: client,
+		Channel:    ch,
+	})
+	if msg, ok := client.latestMsg().(*channeldpb.ErrorMessage); assert.True(t, ok) {
+		assert.Equal(t, channeldpb.ErrorMessage_ACCESS_DENIED, msg.Code)
+	}
+
+}
@@ -13,16 +13,24 @@ var Event_ChannelCreated = &Event[*Channel]{}
 var Event_ChannelRemoving = &Event[*Channel]{}
 var Event_ChannelRemoved = &Event[common.ChannelId]{}
 
+// Broadcast before the idle channel is removed. See ChannelSettingsType.IdleTimeoutMs.
+var Event_ChannelIdle = &Event[*Channel]{}
+
 type AuthEventData struct {
 	AuthResult            channeldpb.AuthResultMessage_AuthResult
 	Connection            ConnectionInChannel
 	PlayerIdentifierToken string
+	// See Connection.UserId(). Empty if the auth failed.
+	UserId string
 }
 
 var Event_AuthComplete = &Event[AuthEventData]{}
 
 var Event_FsmDisallowed = &Event[*Connection]{}
 
+// Broadcast before the idle connection is closed. See GlobalSettingsType.ConnectionIdleTimeoutMs.
+var Event_ConnectionIdle = &Event[*Connection]{}
+
 type EventData interface {
 }
 
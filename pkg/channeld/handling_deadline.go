@@ -0,0 +1,96 @@
+package channeld
+
+import (
+	"bytes"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const maxGoroutineStackDumpSize = 1 << 20
+
+// Watches the handling of a message or the fan-out in the channel's tick. See ChannelSettingsType.HandlingDeadlineMs.
+type handlingWatch struct {
+	ch    *Channel
+	stage string
+	conn  ConnectionInChannel
+	start time.Time
+	timer *time.Timer
+	// Set to 1 when the deadline is exceeded
+	exceeded int32
+}
+
+// Returns the ID of the current goroutine, for sampling its stack when the deadline is exceeded.
+// Returns empty string if the channel has no handling deadline.
+func (ch *Channel) currentGoroutineId() string {
+	if ch.handlingDeadline <= 0 {
+		return ""
+	}
+	buf := make([]byte, 64)
+	buf = bytes.TrimPrefix(buf[:runtime.Stack(buf, false)], []byte("goroutine "))
+	if i := bytes.IndexByte(buf, ' '); i > 0 {
+		return string(buf[:i])
+	}
+	return ""
+}
+
+// Returns the stack of the goroutine, found in the dump of all the goroutines.
+func goroutineStack(goroutineId string) string {
+	buf := make([]byte, maxGoroutineStackDumpSize)
+	buf = buf[:runtime.Stack(buf, true)]
+	start := bytes.Index(buf, []byte("goroutine "+goroutineId+" ["))
+	if start < 0 {
+		return ""
+	}
+	buf = buf[start:]
+	if end := bytes.Index(buf, []byte("\n\n")); end >= 0 {
+		buf = buf[:end]
+	}
+	return string(buf)
+}
+
+// Starts watching the stage of the tick. The returned watch should be done when the stage finishes.
+// Returns nil if the channel has no handling deadline.
+func (ch *Channel) watchHandling(stage string, goroutineId string, conn ConnectionInChannel) *handlingWatch {
+	if ch.handlingDeadline <= 0 {
+		return nil
+	}
+	w := &handlingWatch{
+		ch:    ch,
+		stage: stage,
+		conn:  conn,
+		start: time.Now(),
+	}
+	// Reports while the stage is still running, so a stage that never finishes won't stall the channel silently.
+	w.timer = time.AfterFunc(ch.handlingDeadline, func() {
+		atomic.StoreInt32(&w.exceeded, 1)
+		slowHandling.WithLabelValues(ch.channelType.String(), stage).Inc()
+		fields := []zap.Field{
+			zap.String("stage", stage),
+			zap.Duration("deadline", ch.handlingDeadline),
+		}
+		if conn != nil {
+			fields = append(fields, zap.Uint32("connId", uint32(conn.Id())))
+		}
+		if goroutineId != "" {
+			fields = append(fields, zap.String("stack", goroutineStack(goroutineId)))
+		}
+		ch.Logger().Warn("handling exceeded the deadline", fields...)
+	})
+	return w
+}
+
+func (w *handlingWatch) done() {
+	if w == nil {
+		return
+	}
+	w.timer.Stop()
+	if atomic.LoadInt32(&w.exceeded) == 1 {
+		w.ch.Logger().Warn("slow handling finished",
+			zap.String("stage", w.stage),
+			zap.Duration("duration", time.Since(w.start)),
+		)
+	}
+}
@@ -0,0 +1,50 @@
+package channeld
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlingDeadline(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{HandlingDeadlineMs: 20}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	testChannel, _ := CreateChannel(channeldpb.ChannelType_TEST, nil)
+	// Stop the channel.Tick() goroutine
+	testChannel.removing = 1
+	testChannel.tickInterval = time.Hour
+
+	goroutineId := testChannel.currentGoroutineId()
+	assert.NotEmpty(t, goroutineId)
+	assert.True(t, strings.Contains(goroutineStack(goroutineId), "TestHandlingDeadline"))
+
+	slowCount := func() float64 {
+		return testutil.ToFloat64(slowHandling.WithLabelValues(channeldpb.ChannelType_TEST.String(), channeldpb.MessageType_INVALID.String()))
+	}
+	count := slowCount()
+
+	handled := 0
+	testChannel.inMsgQueue <- channelMessage{ctx: MessageContext{MsgType: channeldpb.MessageType_INVALID}, handler: func(ctx MessageContext) {
+		handled++
+	}}
+	testChannel.tickMessages(time.Now(), goroutineId)
+	assert.Equal(t, 1, handled)
+	assert.Equal(t, count, slowCount())
+
+	testChannel.inMsgQueue <- channelMessage{ctx: MessageContext{MsgType: channeldpb.MessageType_INVALID}, handler: func(ctx MessageContext) {
+		time.Sleep(50 * time.Millisecond)
+		handled++
+	}}
+	testChannel.tickMessages(time.Now(), goroutineId)
+	assert.Equal(t, 2, handled)
+	assert.Equal(t, count+1, slowCount())
+}
@@ -0,0 +1,111 @@
+package channeld
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+)
+
+const idleCheckInterval = 500 * time.Millisecond
+
+// Saves the channel data of the idle channel before it's removed, e.g. to a database, for the channel to be re-created later.
+// Called in the channel's goroutine. If an error is returned, the channel is kept, and checked again after IdleTimeoutMs.
+type IdleChannelPersister func(ch *Channel) error
+
+var idleChannelPersister IdleChannelPersister
+
+// See ChannelSettingsType.IdleTimeoutMs
+func SetIdleChannelPersister(value IdleChannelPersister) {
+	idleChannelPersister = value
+}
+
+// Checks the idle client connections. See GlobalSettingsType.ConnectionIdleTimeoutMs.
+func checkIdleConns() {
+	for {
+		if GlobalSettings.ConnectionIdleTimeoutMs > 0 {
+			now := time.Now()
+			allConnections.Range(func(_ ConnectionId, conn *Connection) bool {
+				conn.checkIdle(now)
+				return true
+			})
+		}
+
+		time.Sleep(idleCheckInterval)
+	}
+}
+
+// Pings the client connection if it has been idle for ConnectionIdleTimeoutMs, and closes it if there's still no traffic
+// after ConnectionIdlePingTimeoutMs.
+func (c *Connection) checkIdle(now time.Time) {
+	if c.connectionType != channeldpb.ConnectionType_CLIENT || c.State() != ConnectionState_READY {
+		return
+	}
+
+	idleTime := now.Sub(time.Unix(0, atomic.LoadInt64(&c.lastReceiveTime)))
+	if idleTime.Milliseconds() < GlobalSettings.ConnectionIdleTimeoutMs {
+		// Replied to the ping, or not idle at all
+		atomic.StoreInt64(&c.idlePingTime, 0)
+		return
+	}
+
+	pingTime := atomic.LoadInt64(&c.idlePingTime)
+	if pingTime == 0 {
+		atomic.StoreInt64(&c.idlePingTime, now.UnixNano())
+		c.Send(MessageContext{
+			MsgType:   channeldpb.MessageType_PING,
+			Msg:       &channeldpb.PingMessage{ServerTimeMs: now.UnixMilli()},
+			ChannelId: uint32(GlobalChannelId),
+		})
+		return
+	}
+
+	if now.Sub(time.Unix(0, pingTime)).Milliseconds() >= GlobalSettings.ConnectionIdlePingTimeoutMs {
+		Event_ConnectionIdle.Broadcast(c)
+		idleConnectionsClosed.Inc()
+		c.CloseWithReason(channeldpb.DisconnectMessage_IDLE_TIMEOUT, "")
+	}
+}
+
+// Removes the channel if it has had no subscriber and no message from the owner for ChannelSettingsType.IdleTimeoutMs.
+// The channel data is persisted first, if there's the persister. Should be called in the channel's goroutine.
+func (ch *Channel) tickIdle(now time.Time) {
+	timeout := ch.settings().IdleTimeoutMs
+	if timeout == 0 || ch.channelType == channeldpb.ChannelType_GLOBAL {
+		return
+	}
+
+	ch.connectionsLock.RLock()
+	subscriberNum := len(ch.subscribedConnections)
+	ch.connectionsLock.RUnlock()
+	if subscriberNum > 0 {
+		ch.lastActiveTime = now
+		return
+	}
+	if now.Sub(ch.lastActiveTime) < time.Duration(timeout)*time.Millisecond {
+		return
+	}
+
+	if idleChannelPersister != nil {
+		if err := idleChannelPersister(ch); err != nil {
+			ch.Logger().Error("failed to persist the idle channel", zap.Error(err))
+			ch.lastActiveTime = now
+			return
+		}
+	}
+
+	Event_ChannelIdle.Broadcast(ch)
+	idleChannelsRemoved.WithLabelValues(ch.channelType.String()).Inc()
+
+	atomic.AddInt32(&ch.removing, 1)
+	// Same as removing the channel after the owner is removed
+	globalChannel.PutMessage(&channeldpb.RemoveChannelMessage{
+		ChannelId: uint32(ch.id),
+	}, handleRemoveChannel, nil, &channeldpb.MessagePack{
+		Broadcast: 0,
+		StubId:    0,
+		ChannelId: uint32(GlobalChannelId),
+	})
+	ch.Logger().Info("removing idle channel", zap.Duration("idleTime", now.Sub(ch.lastActiveTime)))
+}
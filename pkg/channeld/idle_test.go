@@ -0,0 +1,103 @@
+package channeld
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectionIdle(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ConnectionIdleTimeoutMs = 1000
+	defer func() {
+		GlobalSettings.ConnectionIdleTimeoutMs = 0
+	}()
+
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	client.setProtocolVersion(ProtocolVersion)
+	client.OnAuthenticated("p1")
+	var idleConn *Connection
+	Event_ConnectionIdle.ListenFor(t, func(c *Connection) {
+		idleConn = c
+	})
+	defer Event_ConnectionIdle.UnlistenFor(t)
+
+	now := time.Now()
+	client.checkIdle(now.Add(500 * time.Millisecond))
+	assert.Nil(t, client.latestMsg())
+
+	client.checkIdle(now.Add(1000 * time.Millisecond))
+	_, ok := client.latestMsg().(*channeldpb.PingMessage)
+	assert.True(t, ok)
+
+	// Replied to the ping
+	client.lastReceiveTime = now.Add(1500 * time.Millisecond).UnixNano()
+	client.checkIdle(now.Add(1600 * time.Millisecond))
+	assert.EqualValues(t, 0, client.idlePingTime)
+
+	client.checkIdle(now.Add(2500 * time.Millisecond))
+	assert.NotEqualValues(t, 0, client.idlePingTime)
+	client.checkIdle(now.Add(7500 * time.Millisecond))
+	assert.Same(t, client, idleConn)
+	assert.Equal(t, channeldpb.DisconnectMessage_IDLE_TIMEOUT, client.DisconnectReason())
+
+	// The server connections are never idle
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	server.OnAuthenticated("s1")
+	server.checkIdle(now.Add(time.Hour))
+	assert.Nil(t, server.latestMsg())
+}
+
+func TestChannelIdle(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{IdleTimeoutMs: 1000}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+	persistErr := errors.New("database is down")
+	persisted := 0
+	SetIdleChannelPersister(func(ch *Channel) error {
+		persisted++
+		return persistErr
+	})
+	defer SetIdleChannelPersister(nil)
+	var idleCh *Channel
+	Event_ChannelIdle.ListenFor(t, func(ch *Channel) {
+		idleCh = ch
+	})
+	defer Event_ChannelIdle.UnlistenFor(t)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	ch.removing = 1
+	client.SubscribeToChannel(ch, nil)
+
+	now := time.Now()
+	ch.tickIdle(now.Add(time.Hour))
+	assert.Equal(t, 0, persisted)
+
+	client.UnsubscribeFromChannel(ch)
+	ch.tickIdle(now.Add(time.Hour + 500*time.Millisecond))
+	assert.Equal(t, 0, persisted)
+
+	// Kept as the channel data can't be persisted
+	ch.tickIdle(now.Add(time.Hour + 1000*time.Millisecond))
+	assert.Equal(t, 1, persisted)
+	assert.Nil(t, idleCh)
+	ch.tickIdle(now.Add(time.Hour + 1500*time.Millisecond))
+	assert.Equal(t, 1, persisted)
+
+	persistErr = nil
+	ch.tickIdle(now.Add(time.Hour + 2000*time.Millisecond))
+	assert.Equal(t, 2, persisted)
+	assert.Same(t, ch, idleCh)
+	assert.EqualValues(t, 2, ch.removing)
+}
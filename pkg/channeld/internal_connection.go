@@ -0,0 +1,82 @@
+package channeld
+
+import (
+	"errors"
+	"net"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"google.golang.org/protobuf/proto"
+)
+
+// The base of the subscribers inside channeld that are not backed by a network connection, e.g. the replica feed
+// (see SpawnReplica()) and the broker bridge (see BridgeToBroker()). They are never notified of the (un)subscriptions.
+type internalConnection struct {
+	id ConnectionId
+}
+
+func (c *internalConnection) Id() ConnectionId {
+	return c.id
+}
+
+func (c *internalConnection) OnAuthenticated(pit string) {}
+
+func (c *internalConnection) HasAuthorityOver(ch *Channel) bool {
+	return false
+}
+
+func (c *internalConnection) Close() {}
+
+func (c *internalConnection) sendSubscribed(ctx MessageContext, ch *Channel, connToSub ConnectionInChannel, stubId uint32, subOptions *channeldpb.ChannelSubscriptionOptions) {
+}
+
+func (c *internalConnection) sendUnsubscribed(ctx MessageContext, ch *Channel, connToUnsub *Connection, stubId uint32) {
+}
+
+func (c *internalConnection) HasInterestIn(spatialChId common.ChannelId) bool {
+	return false
+}
+
+func (c *internalConnection) GetTag(key string) (string, bool) {
+	return "", false
+}
+
+func (c *internalConnection) RemoteAddr() net.Addr {
+	return nil
+}
+
+// Same as Connection.SubscribeToChannel(), without the notifications and the presence.
+func subscribeInternal(conn ConnectionInChannel, ch *Channel, options *channeldpb.ChannelSubscriptionOptions) (*ChannelSubscription, bool) {
+	ch.connectionsLock.Lock()
+	defer ch.connectionsLock.Unlock()
+
+	if cs, exists := ch.subscribedConnections[conn]; exists {
+		return cs, true
+	}
+
+	cs := &ChannelSubscription{
+		options: *defaultSubOptions(ch),
+		subTime: ch.GetTime(),
+	}
+	if options != nil {
+		proto.Merge(&cs.options, options)
+	}
+	ch.updateFanOutConnection(conn, cs)
+	ch.subscribedConnections[conn] = cs
+	return cs, false
+}
+
+func unsubscribeInternal(conn ConnectionInChannel, ch *Channel) (*channeldpb.ChannelSubscriptionOptions, error) {
+	ch.connectionsLock.Lock()
+	defer ch.connectionsLock.Unlock()
+
+	cs, exists := ch.subscribedConnections[conn]
+	if !exists {
+		return nil, errors.New("subscription does not exist")
+	}
+	if cs.fanOutElement != nil {
+		ch.fanOutQueue.Remove(cs.fanOutElement)
+	}
+	delete(ch.subscribedConnections, conn)
+	return &cs.options, nil
+}
@@ -0,0 +1,173 @@
+package channeld
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+)
+
+const (
+	AddressFamily_DualStack = ""
+	AddressFamily_IPv4      = "ipv4"
+	AddressFamily_IPv6      = "ipv6"
+)
+
+// The config of a listener. See GlobalSettingsType.Listeners.
+type ListenerSettings struct {
+	// Optional. Only shown in the logs, e.g. "internal".
+	Name string
+	// The type of the connections accepted by the listener: SERVER, CLIENT or OPERATOR.
+	// e.g. the listener of the SERVER connections can bind to the internal interface only.
+	ConnectionType string
+	// tcp, kcp or ws. Default is tcp.
+	Transport string
+	// ipv4, ipv6, or empty for dual-stack.
+	AddressFamily string
+	// The address to listen on, e.g. ":12108", "0.0.0.0:12108" or "[::1]:12108". Only the port is used if Interface is set.
+	Address string
+	// Optional. The name of the network interface to bind to, e.g. "eth1".
+	// The listener listens on each address of the interface in the address family.
+	Interface string
+	// Reads the PROXY protocol header on the accepted connections. Only works with tcp. See ListenOptions.ProxyProtocol.
+	ProxyProtocol bool
+}
+
+// Splits the network of StartListening() into the transport and the suffix of the address family, e.g. "tcp6" -> "tcp", "6".
+func splitNetwork(network string) (transport string, family string) {
+	if network == "websocket" {
+		return "ws", ""
+	}
+	for _, t := range []string{"tcp", "kcp", "ws"} {
+		if network == t || network == t+"4" || network == t+"6" {
+			return t, strings.TrimPrefix(network, t)
+		}
+	}
+	return network, ""
+}
+
+func (ls *ListenerSettings) connectionType() (channeldpb.ConnectionType, error) {
+	value, exists := channeldpb.ConnectionType_value[strings.ToUpper(ls.ConnectionType)]
+	if !exists || value == int32(channeldpb.ConnectionType_NO_CONNECTION) {
+		return channeldpb.ConnectionType_NO_CONNECTION, fmt.Errorf("invalid connection type: %q", ls.ConnectionType)
+	}
+	return channeldpb.ConnectionType(value), nil
+}
+
+// The network to pass to StartListening().
+func (ls *ListenerSettings) network() (string, error) {
+	transport := strings.ToLower(ls.Transport)
+	switch transport {
+	case "":
+		transport = "tcp"
+	case "tcp", "kcp", "ws":
+	case "websocket":
+		transport = "ws"
+	default:
+		return "", fmt.Errorf("invalid transport: %q", ls.Transport)
+	}
+
+	switch strings.ToLower(ls.AddressFamily) {
+	case AddressFamily_DualStack:
+		return transport, nil
+	case AddressFamily_IPv4:
+		return transport + "4", nil
+	case AddressFamily_IPv6:
+		return transport + "6", nil
+	default:
+		return "", fmt.Errorf("invalid address family: %q", ls.AddressFamily)
+	}
+}
+
+// The addresses to listen on. More than one if the interface has multiple addresses in the address family.
+func (ls *ListenerSettings) addresses() ([]string, error) {
+	if ls.Interface == "" {
+		return []string{ls.Address}, nil
+	}
+
+	_, port, err := net.SplitHostPort(ls.Address)
+	if err != nil {
+		return nil, err
+	}
+	iface, err := net.InterfaceByName(ls.Interface)
+	if err != nil {
+		return nil, err
+	}
+	ifaceAddrs, err := iface.Addrs()
+	if err != nil {
+		return nil, err
+	}
+
+	family := strings.ToLower(ls.AddressFamily)
+	addrs := make([]string, 0, len(ifaceAddrs))
+	for _, ifaceAddr := range ifaceAddrs {
+		ipNet, ok := ifaceAddr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		isIPv4 := ipNet.IP.To4() != nil
+		if (family == AddressFamily_IPv4 && !isIPv4) || (family == AddressFamily_IPv6 && isIPv4) {
+			continue
+		}
+		host := ipNet.IP.String()
+		if !isIPv4 && ipNet.IP.IsLinkLocalUnicast() {
+			// The link-local address is only valid with the zone
+			host += "%" + iface.Name
+		}
+		addrs = append(addrs, net.JoinHostPort(host, port))
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no address of family %q found on interface %s", ls.AddressFamily, ls.Interface)
+	}
+	return addrs, nil
+}
+
+func (ls *ListenerSettings) resolve() (channeldpb.ConnectionType, string, []string, error) {
+	connType, err := ls.connectionType()
+	if err != nil {
+		return connType, "", nil, err
+	}
+	network, err := ls.network()
+	if err != nil {
+		return connType, "", nil, err
+	}
+	if transport, _ := splitNetwork(network); ls.ProxyProtocol && transport != "tcp" {
+		return connType, "", nil, fmt.Errorf("PROXY protocol doesn't work with %s", transport)
+	}
+	addrs, err := ls.addresses()
+	return connType, network, addrs, err
+}
+
+// Starts the listeners in the background, replacing the single listener per connection type
+// (GlobalSettings.ServerAddress, ClientAddress and OperatorAddress). Returns the error if any listener is misconfigured,
+// before any of them starts.
+func StartListeners(listeners []ListenerSettings) error {
+	if len(listeners) == 0 {
+		return errors.New("no listener is configured")
+	}
+
+	type listenerToStart struct {
+		connType channeldpb.ConnectionType
+		network  string
+		address  string
+		options  ListenOptions
+	}
+	toStart := make([]listenerToStart, 0, len(listeners))
+	for i := range listeners {
+		connType, network, addrs, err := listeners[i].resolve()
+		if err != nil {
+			return fmt.Errorf("listener %d (%s): %w", i, listeners[i].Name, err)
+		}
+		for _, addr := range addrs {
+			toStart = append(toStart, listenerToStart{connType, network, addr, ListenOptions{ProxyProtocol: listeners[i].ProxyProtocol}})
+		}
+	}
+
+	for i := range toStart {
+		l := toStart[i]
+		go StartListeningWithOptions(l.connType, l.network, l.address, l.options)
+	}
+	return nil
+}
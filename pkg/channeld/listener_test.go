@@ -0,0 +1,108 @@
+package channeld
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitNetwork(t *testing.T) {
+	for network, expected := range map[string][2]string{
+		"tcp":       {"tcp", ""},
+		"tcp6":      {"tcp", "6"},
+		"kcp4":      {"kcp", "4"},
+		"ws6":       {"ws", "6"},
+		"websocket": {"ws", ""},
+		"unix":      {"unix", ""},
+	} {
+		transport, family := splitNetwork(network)
+		assert.Equal(t, expected, [2]string{transport, family}, network)
+	}
+}
+
+func TestListenerSettings(t *testing.T) {
+	data, err := os.ReadFile("../../config/listeners_example.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var listeners []ListenerSettings
+	if !assert.NoError(t, json.Unmarshal(data, &listeners)) {
+		return
+	}
+	for i := range listeners {
+		_, _, _, err := listeners[i].resolve()
+		assert.NoError(t, err, listeners[i].Name)
+	}
+
+	ls := ListenerSettings{ConnectionType: "server", Transport: "ws", AddressFamily: "ipv6", Address: ":11288"}
+	connType, network, addrs, err := ls.resolve()
+	assert.NoError(t, err)
+	assert.Equal(t, channeldpb.ConnectionType_SERVER, connType)
+	assert.Equal(t, "ws6", network)
+	assert.Equal(t, []string{":11288"}, addrs)
+
+	_, _, _, err = (&ListenerSettings{ConnectionType: "NO_CONNECTION"}).resolve()
+	assert.Error(t, err)
+	_, _, _, err = (&ListenerSettings{ConnectionType: "CLIENT", Transport: "quic"}).resolve()
+	assert.Error(t, err)
+	_, _, _, err = (&ListenerSettings{ConnectionType: "CLIENT", AddressFamily: "ipx"}).resolve()
+	assert.Error(t, err)
+
+	// Binds to the addresses of the loopback interface in the family
+	ls = ListenerSettings{ConnectionType: "SERVER", AddressFamily: AddressFamily_IPv4, Address: ":11288", Interface: "lo"}
+	addrs, err = ls.addresses()
+	if assert.NoError(t, err) {
+		assert.Contains(t, addrs, "127.0.0.1:11288")
+	}
+	ls.Interface = "no-such-interface"
+	_, err = ls.addresses()
+	assert.Error(t, err)
+
+	assert.Error(t, StartListeners(nil))
+	assert.Error(t, StartListeners([]ListenerSettings{{ConnectionType: "CLIENT"}, {ConnectionType: "?"}}))
+}
+
+func TestStartListenersIPv6(t *testing.T) {
+	probe, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skip("IPv6 is not available:", err)
+	}
+	address := probe.Addr().String()
+	probe.Close()
+
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	assert.NoError(t, StartListeners([]ListenerSettings{{ConnectionType: "CLIENT", AddressFamily: AddressFamily_IPv6, Address: address}}))
+	var conn net.Conn
+	for i := 0; i < 100 && conn == nil; i++ {
+		time.Sleep(10 * time.Millisecond)
+		conn, _ = net.Dial("tcp6", address)
+	}
+	if !assert.NotNil(t, conn) {
+		return
+	}
+	defer conn.Close()
+
+	var accepted *Connection
+	for i := 0; i < 100 && accepted == nil; i++ {
+		time.Sleep(10 * time.Millisecond)
+		allConnections.Range(func(_ ConnectionId, c *Connection) bool {
+			if c.RemoteAddr().String() == conn.LocalAddr().String() {
+				accepted = c
+				return false
+			}
+			return true
+		})
+	}
+	if assert.NotNil(t, accepted) {
+		assert.Equal(t, channeldpb.ConnectionType_CLIENT, accepted.GetConnectionType())
+		assert.Equal(t, "::1", GetIP(accepted.RemoteAddr()))
+	}
+}
@@ -0,0 +1,158 @@
+package channeld
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// How often the channels sample their memory usage
+const memoryUsageSampleInterval = time.Second
+
+// The estimated memory held by a channel. The sizes are the serialized sizes of the messages,
+// which are smaller than the actual heap usage, but good enough to localize the leaks.
+type ChannelMemoryUsage struct {
+	ChannelId              uint32
+	ChannelType            string
+	DataBytes              int
+	AccumulatedUpdateBytes int
+	UpdateBufferLen        int
+	UpdateBufferBytes      int
+	FanOutQueueLen         int
+	InMsgQueueLen          int
+	RetainedBroadcastBytes int
+	TotalBytes             int
+}
+
+// The estimated memory held by a connection.
+type ConnectionMemoryUsage struct {
+	ConnId          uint32
+	ConnType        string
+	ReadBufferBytes int
+	SendQueueLen    int
+	SendQueueBytes  int
+	TotalBytes      int
+}
+
+// Should be called in the channel's goroutine.
+func (ch *Channel) sampleMemoryUsage() {
+	usage := &ChannelMemoryUsage{
+		ChannelId:      uint32(ch.id),
+		ChannelType:    ch.channelType.String(),
+		FanOutQueueLen: ch.fanOutQueue.Len(),
+		InMsgQueueLen:  len(ch.inMsgQueue) + ch.inbox.pendingNum(),
+	}
+
+	if ch.data != nil {
+		usage.DataBytes = proto.Size(ch.data.msg)
+		usage.AccumulatedUpdateBytes = proto.Size(ch.data.accumulatedUpdateMsg)
+		usage.UpdateBufferLen = ch.data.updateMsgBuffer.Len()
+		for e := ch.data.updateMsgBuffer.Front(); e != nil; e = e.Next() {
+			usage.UpdateBufferBytes += proto.Size(e.Value.(*updateMsgBufferElement).updateMsg)
+		}
+	}
+
+	if ch.retainedBroadcasts != nil {
+		for e := ch.retainedBroadcasts.Front(); e != nil; e = e.Next() {
+			usage.RetainedBroadcastBytes += proto.Size(e.Value.(*retainedBroadcast).ctx.Msg)
+		}
+	}
+
+	usage.TotalBytes = usage.DataBytes + usage.AccumulatedUpdateBytes + usage.UpdateBufferBytes + usage.RetainedBroadcastBytes
+
+	var lastTotalBytes int
+	if last := ch.MemoryUsage(); last != nil {
+		lastTotalBytes = last.TotalBytes
+	}
+	channelMemoryBytes.WithLabelValues(usage.ChannelType).Add(float64(usage.TotalBytes - lastTotalBytes))
+
+	ch.memoryUsage.Store(usage)
+	ch.lastMemoryUsageSampleTime = time.Now()
+}
+
+// Returns the latest sampled memory usage of the channel, or nil if not sampled yet. Goroutine-safe.
+func (ch *Channel) MemoryUsage() *ChannelMemoryUsage {
+	usage, _ := ch.memoryUsage.Load().(*ChannelMemoryUsage)
+	return usage
+}
+
+// Goroutine-safe
+func (c *Connection) MemoryUsage() *ConnectionMemoryUsage {
+	usage := &ConnectionMemoryUsage{
+		ConnId:          uint32(c.id),
+		ConnType:        c.connectionType.String(),
+		ReadBufferBytes: len(c.readBuffer),
+		SendQueueBytes:  int(atomic.LoadInt64(&c.sendQueueBytes)),
+	}
+	for _, queue := range c.sendQueues {
+		usage.SendQueueLen += len(queue)
+	}
+	usage.TotalBytes = usage.ReadBufferBytes + usage.SendQueueBytes
+	return usage
+}
+
+// Updates the memory metrics of all the connections. Should be called in the GLOBAL channel's goroutine.
+func sampleConnectionsMemoryUsage() {
+	totalBytes := make(map[channeldpb.ConnectionType]int)
+	allConnections.Range(func(_ ConnectionId, conn *Connection) bool {
+		totalBytes[conn.connectionType] += conn.MemoryUsage().TotalBytes
+		return true
+	})
+	for _, connType := range []channeldpb.ConnectionType{channeldpb.ConnectionType_SERVER, channeldpb.ConnectionType_CLIENT, channeldpb.ConnectionType_OPERATOR} {
+		connectionMemoryBytes.WithLabelValues(connType.String()).Set(float64(totalBytes[connType]))
+	}
+}
+
+type memoryUsageReport struct {
+	Channels    []*ChannelMemoryUsage
+	Connections []*ConnectionMemoryUsage
+}
+
+// The admin API that reports the estimated memory usage of the channels and connections, in descending order of TotalBytes.
+// Use the "top" query parameter to limit the number of the reported channels and connections, e.g. /debug/memory?top=10
+func HandleMemoryUsage(w http.ResponseWriter, r *http.Request) {
+	report := memoryUsageReport{
+		Channels:    make([]*ChannelMemoryUsage, 0),
+		Connections: make([]*ConnectionMemoryUsage, 0),
+	}
+
+	allChannels.Range(func(_ common.ChannelId, ch *Channel) bool {
+		if usage := ch.MemoryUsage(); usage != nil {
+			report.Channels = append(report.Channels, usage)
+		}
+		return true
+	})
+	allConnections.Range(func(_ ConnectionId, conn *Connection) bool {
+		report.Connections = append(report.Connections, conn.MemoryUsage())
+		return true
+	})
+
+	sort.Slice(report.Channels, func(i, j int) bool {
+		return report.Channels[i].TotalBytes > report.Channels[j].TotalBytes
+	})
+	sort.Slice(report.Connections, func(i, j int) bool {
+		return report.Connections[i].TotalBytes > report.Connections[j].TotalBytes
+	})
+
+	if top, err := strconv.Atoi(r.URL.Query().Get("top")); err == nil && top >= 0 {
+		if len(report.Channels) > top {
+			report.Channels = report.Channels[:top]
+		}
+		if len(report.Connections) > top {
+			report.Connections = report.Connections[:top]
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		rootLogger.Error("failed to write memory usage report", zap.Error(err))
+	}
+}
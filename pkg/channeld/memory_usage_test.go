@@ -0,0 +1,64 @@
+package channeld
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestChannelMemoryUsage(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	// Stop the channel.Tick() goroutine
+	ch.removing = 1
+	dataMsg := &testpb.TestChannelDataMessage{Text: "abc", Num: 123}
+	ch.InitData(dataMsg, nil)
+	assert.Nil(t, ch.MemoryUsage())
+
+	ch.Data().OnUpdate(&testpb.TestChannelDataMessage{Num: 456}, ch.GetTime(), server.Id(), nil)
+	ch.sampleMemoryUsage()
+	usage := ch.MemoryUsage()
+	if assert.NotNil(t, usage) {
+		assert.Equal(t, proto.Size(dataMsg), usage.DataBytes)
+		assert.Equal(t, 1, usage.UpdateBufferLen)
+		assert.Greater(t, usage.UpdateBufferBytes, 0)
+		assert.Equal(t, usage.DataBytes+usage.AccumulatedUpdateBytes+usage.UpdateBufferBytes, usage.TotalBytes)
+	}
+
+	rec := httptest.NewRecorder()
+	HandleMemoryUsage(rec, httptest.NewRequest("GET", "/debug/memory?top=1", nil))
+	report := memoryUsageReport{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Len(t, report.Channels, 1)
+	assert.Len(t, report.Connections, 1)
+}
+
+func TestConnectionMemoryUsage(t *testing.T) {
+	c := &Connection{
+		sender:     &queuedMessagePackSender{},
+		sendQueues: newSendQueues(),
+		readBuffer: make([]byte, 1024),
+		logger:     rootLogger,
+	}
+
+	msg := &channeldpb.ServerForwardMessage{Payload: []byte("hello")}
+	c.Send(MessageContext{MsgType: channeldpb.MessageType_USER_SPACE_START, Msg: msg})
+	usage := c.MemoryUsage()
+	assert.Equal(t, 1, usage.SendQueueLen)
+	assert.Equal(t, proto.Size(msg), usage.SendQueueBytes)
+	assert.Equal(t, 1024+proto.Size(msg), usage.TotalBytes)
+
+	c.nextMessageToSend()
+	usage = c.MemoryUsage()
+	assert.Equal(t, 0, usage.SendQueueLen)
+	assert.Equal(t, 0, usage.SendQueueBytes)
+}
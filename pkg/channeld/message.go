@@ -2,10 +2,14 @@ package channeld
 
 import (
 	"strings"
+	"time"
 
+	"github.com/indiest/fmutils"
 	"github.com/metaworking/channeld/pkg/channeldpb"
 	"github.com/metaworking/channeld/pkg/common"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
 )
 
 // The context of a message for both sending and receiving
@@ -18,6 +22,8 @@ type MessageContext struct {
 	// The original channelId in the Packet, could be different from Channel.id.
 	// Used for both send and receive.
 	ChannelId uint32
+	// The trace id in the Packet, preserved in the forwarding and the response. See channeldpb.MessagePack.TraceId.
+	TraceId string
 
 	// The connection that received the message. Required for BroadcastType_ALL_BUT_SENDER but not for sending.
 	Connection ConnectionInChannel
@@ -25,6 +31,12 @@ type MessageContext struct {
 	Channel *Channel
 	// Internally used for receiving
 	arrivalTime ChannelTime
+	// The channelKey in the Packet. Internally used for auto-creating the named channel.
+	channelKey string
+	// The Msg is a ServerForwardMessage whose payload is sent as the raw MsgBody. See channeldpb.MessagePack.RawPayload.
+	rawPayload bool
+	// The arrival time of the earliest channel data update in the fan-out. See trackFanOutLatency().
+	updateArrival time.Time
 }
 
 func (ctx *MessageContext) HasConnection() bool {
@@ -55,12 +67,46 @@ var MessageMap = map[channeldpb.MessageType]*messageMapEntry{
 	channeldpb.MessageType_CREATE_ENTITY_CHANNEL:     {&channeldpb.CreateEntityChannelMessage{}, handleCreateEntityChannel},
 	channeldpb.MessageType_ENTITY_GROUP_ADD:          {&channeldpb.AddEntityGroupMessage{}, handleAddEntityGroup},
 	channeldpb.MessageType_ENTITY_GROUP_REMOVE:       {&channeldpb.RemoveEntityGroupMessage{}, handleRemoveEntityGroup},
+	channeldpb.MessageType_SUB_TO_CHANNELS:           {&channeldpb.SubscribedToChannelsMessage{}, handleSubToChannels},
+	channeldpb.MessageType_UNSUB_FROM_CHANNELS:       {&channeldpb.UnsubscribedFromChannelsMessage{}, handleUnsubFromChannels},
+	channeldpb.MessageType_SET_CONNECTION_TAGS:       {&channeldpb.SetConnectionTagsMessage{}, handleSetConnectionTags},
+	channeldpb.MessageType_QUERY_CHANNEL_DATA:        {&channeldpb.QueryChannelDataMessage{}, handleQueryChannelData},
+	channeldpb.MessageType_CHANNEL_TIMER:             {&channeldpb.ChannelTimerMessage{}, handleChannelTimer},
+	channeldpb.MessageType_CHANNEL_DATA_LOCK:         {&channeldpb.ChannelDataLockMessage{}, handleChannelDataLock},
+	channeldpb.MessageType_FETCH_HISTORY:             {&channeldpb.FetchHistoryMessage{}, handleFetchHistory},
+	channeldpb.MessageType_FETCH_ARCHIVE:             {&channeldpb.FetchArchiveMessage{}, handleFetchArchive},
+	channeldpb.MessageType_UPDATE_BLOCK_LIST:         {&channeldpb.UpdateBlockListMessage{}, handleUpdateBlockList},
+	channeldpb.MessageType_FETCH_LEADERBOARD:         {&channeldpb.FetchLeaderboardMessage{}, handleFetchLeaderboard},
+	channeldpb.MessageType_CHANNEL_DATA_TRANSACTION:  {&channeldpb.ChannelDataTransactionMessage{}, handleChannelDataTransaction},
+	channeldpb.MessageType_CHANNEL_DATA_RESYNC:       {&channeldpb.ChannelDataResyncMessage{}, handleChannelDataResync},
+	channeldpb.MessageType_PAUSE_DATA_FAN_OUT:        {&channeldpb.PauseDataFanOutMessage{}, handlePauseDataFanOut},
 }
 
 func RegisterMessageHandler(msgType uint32, msg common.Message, handler MessageHandlerFunc) {
 	MessageMap[channeldpb.MessageType(msgType)] = &messageMapEntry{msg, handler}
 }
 
+// Returns a new message to unmarshal the MessagePack.MsgBody of the type into, or nil if there's no message registered for
+// the type, e.g. the user-space messages without handler. Includes the messages handled in the receive goroutine.
+func NewMessageOfType(msgType channeldpb.MessageType) common.Message {
+	switch msgType {
+	case channeldpb.MessageType_PING:
+		return &channeldpb.PingMessage{}
+	case channeldpb.MessageType_ANALYTICS:
+		return &channeldpb.AnalyticsMessage{}
+	case channeldpb.MessageType_BANDWIDTH_REPORT:
+		return &channeldpb.BandwidthReportMessage{}
+	case channeldpb.MessageType_RELAY:
+		return &channeldpb.RelayMessage{}
+	case channeldpb.MessageType_SERVER_BATCH:
+		return &channeldpb.ServerBatchMessage{}
+	}
+	if entry := MessageMap[msgType]; entry != nil {
+		return entry.msg.ProtoReflect().New().Interface()
+	}
+	return nil
+}
+
 func handleClientToServerUserMessage(ctx MessageContext) {
 	msg, ok := ctx.Msg.(*channeldpb.ServerForwardMessage)
 	if !ok {
@@ -74,7 +120,7 @@ func handleClientToServerUserMessage(ctx MessageContext) {
 		channelOwnerConnId = uint32(ctx.Channel.ownerConnection.Id())
 	} else if ctx.Broadcast > 0 {
 		if ctx.Channel.enableClientBroadcast {
-			ctx.Channel.Broadcast(ctx)
+			ctx.Channel.broadcastUserMessage(ctx, msg)
 		} else {
 			ctx.Connection.Logger().Error("illegal attempt to broadcast message as the channel's client broadcasting is disabled",
 				zap.Uint32("msgType", uint32(ctx.MsgType)),
@@ -83,6 +129,8 @@ func handleClientToServerUserMessage(ctx MessageContext) {
 			)
 			return
 		}
+	} else if ctx.Channel.bufferForOwner(ctx) {
+		return
 	} else {
 		ctx.Channel.Logger().Error("channel has no owner to forward the user-space messaged",
 			zap.Uint32("msgType", uint32(ctx.MsgType)),
@@ -99,6 +147,7 @@ func handleClientToServerUserMessage(ctx MessageContext) {
 			zap.Uint32("channelOwner", channelOwnerConnId),
 			zap.Uint32("broadcastType", ctx.Broadcast),
 			zap.Int("payloadSize", len(msg.Payload)),
+			zap.String("traceId", ctx.TraceId),
 		)
 	} else {
 		ctx.Connection.Logger().Debug("forward user-space message from client to server",
@@ -108,6 +157,7 @@ func handleClientToServerUserMessage(ctx MessageContext) {
 			zap.Uint32("channelOwner", channelOwnerConnId),
 			zap.Uint32("broadcastType", ctx.Broadcast),
 			zap.Int("payloadSize", len(msg.Payload)),
+			zap.String("traceId", ctx.TraceId),
 		)
 	}
 }
@@ -119,6 +169,18 @@ func HandleServerToClientUserMessage(ctx MessageContext) {
 		return
 	}
 
+	if isOperator(ctx.Connection) {
+		auditOperatorAction(ctx, "inject",
+			zap.Uint32("msgType", uint32(ctx.MsgType)),
+			zap.Uint32("broadcastType", ctx.Broadcast),
+			zap.Uint32("clientConnId", msg.ClientConnId),
+		)
+	}
+
+	if scheduleDelayedBroadcast(ctx, msg) {
+		return
+	}
+
 	if len(msg.Payload) < 128 {
 		ctx.Connection.Logger().Verbose("forward user-space message from server to client/server",
 			zap.Uint32("msgType", uint32(ctx.MsgType)),
@@ -126,6 +188,7 @@ func HandleServerToClientUserMessage(ctx MessageContext) {
 			zap.Uint32("channelId", uint32(ctx.Channel.id)),
 			zap.Uint32("broadcastType", ctx.Broadcast),
 			zap.Int("payloadSize", len(msg.Payload)),
+			zap.String("traceId", ctx.TraceId),
 		)
 	} else {
 		ctx.Connection.Logger().Debug("forward user-space message from server to client/server",
@@ -134,6 +197,7 @@ func HandleServerToClientUserMessage(ctx MessageContext) {
 			zap.Uint32("channelId", uint32(ctx.Channel.id)),
 			zap.Uint32("broadcastType", ctx.Broadcast),
 			zap.Int("payloadSize", len(msg.Payload)),
+			zap.String("traceId", ctx.TraceId),
 		)
 
 	}
@@ -178,7 +242,7 @@ func HandleServerToClientUserMessage(ctx MessageContext) {
 
 	default:
 		if ctx.Broadcast >= uint32(channeldpb.BroadcastType_ALL) && ctx.Broadcast < uint32(channeldpb.BroadcastType_ADJACENT_CHANNELS) {
-			ctx.Channel.Broadcast(ctx)
+			ctx.Channel.broadcastUserMessage(ctx, msg)
 		} else if channeldpb.BroadcastType_ADJACENT_CHANNELS.Check(ctx.Broadcast) {
 			if ctx.Channel.channelType != channeldpb.ChannelType_SPATIAL {
 				ctx.Connection.Logger().Warn("BroadcastType_ADJACENT_CHANNELS only works for Spatial channel")
@@ -193,42 +257,48 @@ func HandleServerToClientUserMessage(ctx MessageContext) {
 				ctx.Connection.Logger().Error("failed to retrieve spatial regions", zap.Error(err))
 				return
 			}
+			tagFilter := msg.TagFilter
+			filter, ok := takeBroadcastTagFilter(ctx, msg)
+			if !ok {
+				return
+			}
+			senderUserId := broadcastSenderUserId(ctx, msg)
+			if !ctx.Channel.checkChatFlood(ctx, msg, senderUserId) {
+				return
+			}
+			if !ctx.Channel.filterContent(ctx, msg) {
+				return
+			}
 			// Add the connections in the owner(center) channel?
 			if !channeldpb.BroadcastType_ALL_BUT_OWNER.Check(ctx.Broadcast) {
 				channelIds = append(channelIds, ctx.Channel.id)
 			}
-
-			// Merge all connection in the adjacent channels to one map, to avoid duplicate send.
-			adjacentConns := make(map[ConnectionInChannel]struct{})
+			// The owners of the adjacent channels still receive the message.
+			ctx.Broadcast &^= uint32(channeldpb.BroadcastType_ALL_BUT_OWNER)
+
+			// Share the recipients between the channels, to avoid duplicate send.
+			recipients := newBroadcastRecipients()
+			// Ignore the client specified in the ServerForwardMessage
+			if client := GetConnection(ConnectionId(msg.ClientConnId)); client != nil {
+				recipients.add(client)
+			}
 			for _, id := range channelIds {
-				channel := GetChannel(id)
+				channel := GetChannelOfConnection(id, ctx.Connection)
 				if channel == nil {
 					ctx.Connection.Logger().Error("invalid channel id for broadcast", zap.Uint32("channelId", uint32(id)))
 					continue
 				}
-				conns := channel.GetAllConnections()
-				for conn := range conns {
-					adjacentConns[conn] = struct{}{}
-				}
-			}
-			for conn := range adjacentConns {
-				// Ignore the sender?
-				if channeldpb.BroadcastType_ALL_BUT_SENDER.Check(ctx.Broadcast) && conn == ctx.Connection {
-					continue
-				}
-				// Ignore the clients?
-				if channeldpb.BroadcastType_ALL_BUT_CLIENT.Check(ctx.Broadcast) && conn.GetConnectionType() == channeldpb.ConnectionType_CLIENT {
-					continue
-				}
-				// Ignore the servers?
-				if channeldpb.BroadcastType_ALL_BUT_SERVER.Check(ctx.Broadcast) && conn.GetConnectionType() == channeldpb.ConnectionType_SERVER {
-					continue
-				}
-				// Ignore the client specified in the ServerForwardMessage
-				if conn.Id() == ConnectionId(msg.ClientConnId) {
-					continue
+				// Each channel assigns its own seq to the message it retains.
+				channelMsg := proto.Clone(msg).(*channeldpb.ServerForwardMessage)
+				channelCtx := ctx
+				channelCtx.Msg = channelMsg
+				if channel == ctx.Channel {
+					channel.deliverUserMessage(channelCtx, channelMsg, tagFilter, filter, senderUserId, recipients)
+				} else {
+					channel.Execute(func(ch *Channel) {
+						ch.deliverUserMessage(channelCtx, channelMsg, tagFilter, filter, senderUserId, recipients)
+					})
 				}
-				conn.Send(ctx)
 			}
 		}
 	}
@@ -251,25 +321,49 @@ func handleAuth(ctx MessageContext) {
 	_, banned := pitBlacklist[msg.PlayerIdentifierToken]
 	if banned {
 		securityLogger.Info("refused authentication of banned PIT", zap.String("pit", msg.PlayerIdentifierToken))
-		ctx.Connection.Close()
+		closeWithReason(ctx.Connection, channeldpb.DisconnectMessage_AUTH_FAILURE, "banned")
 		return
 	}
 
-	if authProvider == nil && !GlobalSettings.Development {
+	if conn, ok := ctx.Connection.(*Connection); ok {
+		conn.setProtocolVersion(negotiateProtocolVersion(msg.ProtocolVersion))
+		conn.setMaxPacketSize(negotiateMaxPacketSize(msg.MaxPacketSize))
+	}
+
+	provider := authProvider
+	if isOperator(ctx.Connection) {
+		provider = operatorAuthProvider
+		if provider == nil {
+			securityLogger.Warn("refused authentication of operator as there's no operator auth provider", zap.String("pit", msg.PlayerIdentifierToken))
+			closeWithReason(ctx.Connection, channeldpb.DisconnectMessage_AUTH_FAILURE, "no operator auth provider")
+			return
+		}
+	} else if authProvider == nil && !GlobalSettings.Development {
 		rootLogger.Panic("no auth provider")
 		return
 	}
 
+	if conn, ok := ctx.Connection.(*Connection); ok {
+		conn.transitionTo(ConnectionState_AUTHENTICATING)
+	}
+
 	authResult := channeldpb.AuthResultMessage_SUCCESSFUL
 	if ctx.Connection.GetConnectionType() == channeldpb.ConnectionType_SERVER && GlobalSettings.ServerBypassAuth {
 		onAuthComplete(ctx, authResult, msg.PlayerIdentifierToken)
-	} else if authProvider != nil {
+	} else if provider != nil {
 		go func() {
-			authResult, err := authProvider.DoAuth(ctx.Connection.Id(), msg.PlayerIdentifierToken, msg.LoginToken)
+			authResult, err := provider.DoAuth(ctx.Connection.Id(), msg.PlayerIdentifierToken, msg.LoginToken)
 			if err != nil {
 				ctx.Connection.Logger().Error("failed to do auth", zap.Error(err))
-				ctx.Connection.Close()
+				closeWithReason(ctx.Connection, channeldpb.DisconnectMessage_AUTH_FAILURE, "auth error")
 			} else {
+				if tagger, ok := provider.(TaggingAuthProvider); ok && authResult == channeldpb.AuthResultMessage_SUCCESSFUL {
+					if conn, ok := ctx.Connection.(*Connection); ok {
+						for key, value := range tagger.GetTags(conn.Id(), msg.PlayerIdentifierToken, msg.LoginToken) {
+							conn.SetTag(key, value)
+						}
+					}
+				}
 				onAuthComplete(ctx, authResult, msg.PlayerIdentifierToken)
 			}
 		}()
@@ -283,20 +377,103 @@ func onAuthComplete(ctx MessageContext, authResult channeldpb.AuthResultMessage_
 		return
 	}
 
+	var userId string
+	// Checks the tenant's quota before resolving the duplicate login, which may kick the existing connection.
+	var releaseTenantConnection func()
+	if conn, ok := ctx.Connection.(*Connection); ok && authResult == channeldpb.AuthResultMessage_SUCCESSFUL {
+		if releaseTenantConnection = conn.acquireTenantConnection(); releaseTenantConnection == nil {
+			authResult = channeldpb.AuthResultMessage_QUOTA_EXCEEDED
+		}
+	}
 	if authResult == channeldpb.AuthResultMessage_SUCCESSFUL {
-		ctx.Connection.OnAuthenticated(pit)
+		var err error
+		if userId, err = resolveUserId(pit); err != nil {
+			ctx.Connection.Logger().Error("failed to resolve user id", zap.String("pit", pit), zap.Error(err))
+			authResult = channeldpb.AuthResultMessage_INVALID_PIT
+		} else if conn, ok := ctx.Connection.(*Connection); ok {
+			if ban := conn.findBan(userId); ban != nil {
+				if releaseTenantConnection != nil {
+					releaseTenantConnection()
+				}
+				securityLogger.Info("refused authentication of banned connection",
+					zap.String("pit", pit),
+					zap.String("userId", userId),
+					zap.String("banType", string(ban.Type)),
+					zap.String("banValue", ban.Value),
+				)
+				conn.CloseWithReason(channeldpb.DisconnectMessage_AUTH_FAILURE, "banned")
+				return
+			}
+			if !resolveDuplicateLogin(conn, userId) {
+				userId = ""
+				authResult = channeldpb.AuthResultMessage_DUPLICATE_LOGIN
+			} else if userId != "" {
+				conn.SetTag(UserIdTag, userId)
+			}
+		}
 	}
 
-	ctx.Msg = &channeldpb.AuthResultMessage{
-		Result:          authResult,
-		ConnId:          uint32(ctx.Connection.Id()),
-		CompressionType: GlobalSettings.CompressionType,
+	codec := channeldpb.ChannelDataCodec_PROTOBUF
+	var resumeToken string
+	if authResult == channeldpb.AuthResultMessage_SUCCESSFUL {
+		if authMsg, ok := ctx.Msg.(*channeldpb.AuthMessage); ok && isChannelDataCodecSupported(authMsg.ChannelDataCodec) {
+			codec = authMsg.ChannelDataCodec
+		}
+		if conn, ok := ctx.Connection.(*Connection); ok {
+			conn.channelDataCodec = codec
+			conn.trackPresence()
+		}
+		ctx.Connection.OnAuthenticated(pit)
+		if conn, ok := ctx.Connection.(*Connection); ok && conn.connectionType == channeldpb.ConnectionType_SERVER {
+			resumeToken = conn.issueResumeToken()
+		}
+		ctx.Connection.Logger().Info("authenticated", zap.String("pit", pit), zap.String("userId", userId), zap.String("tenant", GetTenant(ctx.Connection)))
+	} else if conn, ok := ctx.Connection.(*Connection); ok {
+		if releaseTenantConnection != nil {
+			releaseTenantConnection()
+		}
+		// Allows retrying
+		conn.transitionTo(ConnectionState_CONNECTING)
+	}
+
+	resultMsg := &channeldpb.AuthResultMessage{
+		Result:           authResult,
+		ConnId:           uint32(ctx.Connection.Id()),
+		CompressionType:  GlobalSettings.CompressionType,
+		ChannelDataCodec: codec,
+		UserId:           userId,
+		ResumeToken:      resumeToken,
+	}
+	authMsg, _ := ctx.Msg.(*channeldpb.AuthMessage)
+	if authMsg != nil {
+		resultMsg.ProtocolVersion = negotiateProtocolVersion(authMsg.ProtocolVersion)
+		resultMsg.MaxPacketSize = negotiateMaxPacketSize(authMsg.MaxPacketSize)
+		if conn, ok := ctx.Connection.(*Connection); ok && authResult == channeldpb.AuthResultMessage_SUCCESSFUL && len(authMsg.EncryptionPublicKey) > 0 {
+			resultMsg.EncryptionPublicKey = conn.startSession(authMsg.EncryptionPublicKey)
+		}
 	}
+	ctx.Msg = resultMsg
 	ctx.Connection.Send(ctx)
+	if conn, ok := ctx.Connection.(*Connection); ok && resumeToken != "" && authMsg.GetResumeToken() != "" {
+		conn.resume(authMsg.ResumeToken)
+	}
+	if conn, ok := ctx.Connection.(*Connection); ok && authResult == channeldpb.AuthResultMessage_QUOTA_EXCEEDED {
+		tenant := GetTenant(conn)
+		conn.sendQuotaExceeded(&channeldpb.QuotaExceededMessage{
+			QuotaType: channeldpb.QuotaExceededMessage_CONNECTIONS,
+			Tenant:    tenant,
+			Limit:     int64(GlobalSettings.TenantSettings[tenant].MaxConnections),
+		})
+	}
 
 	// Also send the respond to The GLOBAL channel owner (to handle the client's subscription if it doesn't have the authority to).
 	if globalChannel.HasOwner() {
 		ctx.StubId = 0
+		// The owner must not be able to resume or decrypt the connection
+		ownerMsg := proto.Clone(resultMsg).(*channeldpb.AuthResultMessage)
+		ownerMsg.ResumeToken = ""
+		ownerMsg.EncryptionPublicKey = nil
+		ctx.Msg = ownerMsg
 		globalChannel.ownerConnection.Send(ctx)
 	}
 
@@ -304,6 +481,7 @@ func onAuthComplete(ctx MessageContext, authResult channeldpb.AuthResultMessage_
 		AuthResult:            authResult,
 		Connection:            ctx.Connection,
 		PlayerIdentifierToken: pit,
+		UserId:                userId,
 	})
 }
 
@@ -311,6 +489,7 @@ func handleCreateChannel(ctx MessageContext) {
 	// Only the GLOBAL channel can handle channel creation/deletion/listing
 	if ctx.Channel != globalChannel {
 		ctx.Connection.Logger().Error("illegal attemp to create channel outside the GLOBAL channel")
+		replyError(ctx, channeldpb.ErrorMessage_WRONG_CHANNEL, "channels can only be created in the GLOBAL channel")
 		return
 	}
 
@@ -320,10 +499,21 @@ func handleCreateChannel(ctx MessageContext) {
 		return
 	}
 
+	if msg.ChannelKey != "" && GetTenantChannelByKey(GetTenant(ctx.Connection), msg.ChannelKey) != nil {
+		ctx.Connection.Logger().Error("failed to create channel",
+			zap.Uint32("channelType", uint32(msg.ChannelType)),
+			zap.String("channelKey", msg.ChannelKey),
+			zap.Error(ErrChannelKeyInUse),
+		)
+		replyError(ctx, channeldpb.ErrorMessage_CHANNEL_KEY_IN_USE, ErrChannelKeyInUse.Error())
+		return
+	}
+
 	var newChannel *Channel
 	var err error
 	if msg.ChannelType == channeldpb.ChannelType_UNKNOWN {
 		ctx.Connection.Logger().Error("illegal attemp to create the UNKNOWN channel")
+		replyError(ctx, channeldpb.ErrorMessage_INVALID_ARGUMENT, "can't create the UNKNOWN channel")
 		return
 	} else if msg.ChannelType == channeldpb.ChannelType_GLOBAL {
 		// Global channel is initially created by the system. Creating the channel will attempt to own it.
@@ -334,6 +524,7 @@ func handleCreateChannel(ctx MessageContext) {
 			ctx.Connection.Logger().Info("owned the GLOBAL channel")
 		} else {
 			ctx.Connection.Logger().Error("illegal attemp to create the GLOBAL channel")
+			replyError(ctx, channeldpb.ErrorMessage_ACCESS_DENIED, "the GLOBAL channel already has an owner")
 			return
 		}
 	} else if msg.ChannelType == channeldpb.ChannelType_SPATIAL {
@@ -346,16 +537,39 @@ func handleCreateChannel(ctx MessageContext) {
 				zap.Uint32("channelType", uint32(msg.ChannelType)),
 				zap.Error(err),
 			)
+			if quotaErr, ok := err.(*TenantQuotaError); ok {
+				if conn, ok := ctx.Connection.(*Connection); ok {
+					conn.sendQuotaExceeded(quotaErr.QuotaExceededMessage)
+				}
+			} else {
+				replyError(ctx, channeldpb.ErrorMessage_INTERNAL, err.Error())
+			}
 			return
 		}
 		newChannel.Logger().Info("created channel with owner", zap.Uint32("ownerConnId", uint32(newChannel.ownerConnection.Id())))
 	}
 
+	if msg.ChannelKey != "" {
+		if err := newChannel.setKey(msg.ChannelKey); err != nil {
+			ctx.Connection.Logger().Error("failed to set the channel key", zap.String("channelKey", msg.ChannelKey), zap.Error(err))
+			RemoveChannel(newChannel)
+			replyError(ctx, channeldpb.ErrorMessage_CHANNEL_KEY_IN_USE, err.Error())
+			return
+		}
+	}
+
 	newChannel.metadata = msg.Metadata
 	if msg.Data != nil {
-		dataMsg, err := msg.Data.UnmarshalNew()
+		data, err := newChannel.migrateData(msg.Data, msg.DataSchemaVersion)
+		if err != nil {
+			newChannel.Logger().Error("failed to migrate data message for the new channel", zap.Error(err))
+			replyError(ctx, channeldpb.ErrorMessage_INVALID_MESSAGE, err.Error())
+			return
+		}
+		dataMsg, err := data.UnmarshalNew()
 		if err != nil {
 			newChannel.Logger().Error("failed to unmarshal data message for the new channel", zap.Error(err))
+			replyError(ctx, channeldpb.ErrorMessage_INVALID_MESSAGE, err.Error())
 			return
 		} else {
 			newChannel.InitData(dataMsg, msg.MergeOptions)
@@ -370,6 +584,7 @@ func handleCreateChannel(ctx MessageContext) {
 		Metadata:    newChannel.metadata,
 		OwnerConnId: uint32(ctx.Connection.Id()),
 		ChannelId:   uint32(newChannel.id),
+		ChannelKey:  newChannel.key,
 	}
 	ctx.Connection.Send(ctx)
 	// Also send the response to the GLOBAL channel owner.
@@ -388,17 +603,20 @@ func handleCreateChannel(ctx MessageContext) {
 func handleCreateSpatialChannel(ctx MessageContext, msg *channeldpb.CreateChannelMessage) {
 	if ctx.Connection.GetConnectionType() != channeldpb.ConnectionType_SERVER {
 		ctx.Connection.Logger().Error("illegal attemp to create Spatial channel from client connection")
+		replyError(ctx, channeldpb.ErrorMessage_ACCESS_DENIED, "only the server connection can create the Spatial channels")
 		return
 	}
 
 	if spatialController == nil {
 		ctx.Connection.Logger().Error("illegal attemp to create Spatial channel as there's no controller")
+		replyError(ctx, channeldpb.ErrorMessage_INTERNAL, "no spatial controller")
 		return
 	}
 
 	channels, err := spatialController.CreateChannels(ctx)
 	if err != nil {
 		ctx.Connection.Logger().Error("failed to create Spatial channel", zap.Error(err))
+		replyError(ctx, channeldpb.ErrorMessage_INTERNAL, err.Error())
 		return
 	}
 
@@ -449,6 +667,7 @@ func handleCreateEntityChannel(ctx MessageContext) {
 	// Only the global and spatial channels can create the entity channels
 	if ctx.Channel != globalChannel && ctx.Channel.Type() != channeldpb.ChannelType_SPATIAL {
 		ctx.Connection.Logger().Error("illegal attemp to create entity channel outside the GLOBAL or SPATIAL channels")
+		replyError(ctx, channeldpb.ErrorMessage_WRONG_CHANNEL, "entity channels can only be created in the GLOBAL or SPATIAL channels")
 		return
 	}
 
@@ -458,15 +677,17 @@ func handleCreateEntityChannel(ctx MessageContext) {
 		return
 	}
 
-	newChannel := createChannelWithId(common.ChannelId(msg.EntityId), channeldpb.ChannelType_ENTITY, ctx.Connection)
+	newChannel := createChannelWithId(common.ChannelId(msg.EntityId), channeldpb.ChannelType_ENTITY, ctx.Connection, GetTenant(ctx.Connection))
 	newChannel.Logger().Info("created entity channel",
 		zap.Uint32("ownerConnId", uint32(newChannel.ownerConnection.Id())),
 	)
 
 	newChannel.metadata = msg.Metadata
 	if msg.Data != nil {
-		dataMsg, err := msg.Data.UnmarshalNew()
+		data, err := newChannel.migrateData(msg.Data, msg.DataSchemaVersion)
 		if err != nil {
+			newChannel.Logger().Error("failed to migrate data message for the new channel", zap.Error(err))
+		} else if dataMsg, err := data.UnmarshalNew(); err != nil {
 			newChannel.Logger().Error("failed to unmarshal data message for the new channel", zap.Error(err))
 		} else {
 			newChannel.InitData(dataMsg, msg.MergeOptions)
@@ -545,9 +766,15 @@ func handleRemoveChannel(ctx MessageContext) {
 		return
 	}
 
-	channelToRemove := GetChannel(common.ChannelId(msg.ChannelId))
+	var channelToRemove *Channel
+	if ctx.HasConnection() {
+		channelToRemove = GetChannelOfConnection(common.ChannelId(msg.ChannelId), ctx.Connection)
+	} else {
+		channelToRemove = GetChannel(common.ChannelId(msg.ChannelId))
+	}
 	if channelToRemove == nil {
 		ctx.Connection.Logger().Error("invalid channelId for removing", zap.Uint32("channelId", msg.ChannelId))
+		replyError(ctx, channeldpb.ErrorMessage_CHANNEL_NOT_FOUND, "channel to remove not found")
 		return
 	}
 
@@ -564,6 +791,7 @@ func handleRemoveChannel(ctx MessageContext) {
 			zap.Uint32("channelId", uint32(channelToRemove.id)),
 			zap.Uint32("ownerConnId", ownerConnId),
 			zap.Error(err))
+		replyError(ctx, channeldpb.ErrorMessage_ACCESS_DENIED, "no access to remove the channel")
 		return
 	}
 
@@ -593,6 +821,7 @@ func handleRemoveChannel(ctx MessageContext) {
 func handleListChannel(ctx MessageContext) {
 	if ctx.Channel != globalChannel {
 		ctx.Connection.Logger().Error("illegal attemp to list channel outside the GLOBAL channel")
+		replyError(ctx, channeldpb.ErrorMessage_WRONG_CHANNEL, "channels can only be listed in the GLOBAL channel")
 		return
 	}
 
@@ -607,6 +836,9 @@ func handleListChannel(ctx MessageContext) {
 		if msg.TypeFilter != channeldpb.ChannelType_UNKNOWN && msg.TypeFilter != channel.channelType {
 			return true
 		}
+		if !channel.IsVisibleTo(ctx.Connection) {
+			return true
+		}
 		matched := len(msg.MetadataFilters) == 0
 		for _, keyword := range msg.MetadataFilters {
 			if strings.Contains(channel.metadata, keyword) {
@@ -619,6 +851,7 @@ func handleListChannel(ctx MessageContext) {
 				ChannelId:   uint32(channel.id),
 				ChannelType: channel.channelType,
 				Metadata:    channel.metadata,
+				ChannelKey:  channel.key,
 			})
 		}
 		return true
@@ -642,11 +875,12 @@ func handleSubToChannel(ctx MessageContext) {
 		connToSub = ctx.Connection.(*Connection)
 	} else {
 		// Only the server can specify a ConnId.
-		connToSub = GetConnection(ConnectionId(msg.ConnId))
+		connToSub = GetConnectionOfConnection(ConnectionId(msg.ConnId), ctx.Connection)
 	}
 
 	if connToSub == nil {
 		ctx.Connection.Logger().Error("invalid ConnectionId for sub", zap.Uint32("connIdInMsg", msg.ConnId))
+		replyError(ctx, channeldpb.ErrorMessage_CONNECTION_NOT_FOUND, "connection to sub not found")
 		return
 	}
 
@@ -658,6 +892,17 @@ func handleSubToChannel(ctx MessageContext) {
 			zap.Uint32("channelId", uint32(ctx.Channel.id)),
 			zap.Error(err),
 		)
+		replyError(ctx, channeldpb.ErrorMessage_ACCESS_DENIED, "no access to sub another connection to the channel")
+		return
+	}
+
+	if err := ctx.Channel.CheckRequiredTags(connToSub); err != nil {
+		ctx.Connection.Logger().Warn("connection doesn't have the required tags to sub to this channel",
+			zap.Uint32("subConnId", uint32(connToSub.Id())),
+			zap.String("channelType", ctx.Channel.channelType.String()),
+			zap.Uint32("channelId", uint32(ctx.Channel.id)),
+		)
+		replyError(ctx, channeldpb.ErrorMessage_ACCESS_DENIED, err.Error())
 		return
 	}
 
@@ -677,7 +922,8 @@ func handleSubToChannel(ctx MessageContext) {
 		}
 	*/
 
-	cs, alreadySubed := connToSub.SubscribeToChannel(ctx.Channel, msg.SubOptions)
+	// The read-only subscriptions of the clients are spread over the replicas, if there's any. See SpawnReplica().
+	cs, alreadySubed := connToSub.SubscribeToChannel(ctx.Channel.subscriptionTarget(connToSub, msg.SubOptions), msg.SubOptions)
 	if cs == nil {
 		return
 	}
@@ -690,6 +936,11 @@ func handleSubToChannel(ctx MessageContext) {
 		connToSub.sendSubscribed(ctx, ctx.Channel, connToSub, 0, &cs.options)
 	}
 
+	// Send the missed broadcasts to the resumed subscriber
+	if msg.ResumeFromSeq != nil {
+		ctx.Channel.replayBroadcasts(connToSub, *msg.ResumeFromSeq)
+	}
+
 	// Notify the channel owner if not already subed and it's not the sender.
 	if !alreadySubed && ctx.Channel.HasOwner() && ctx.Channel.ownerConnection != ctx.Connection {
 		ctx.Channel.ownerConnection.sendSubscribed(ctx, ctx.Channel, connToSub, 0, &cs.options)
@@ -704,30 +955,39 @@ func handleUnsubFromChannel(ctx MessageContext) {
 	}
 
 	// The connection that unsubscribes. Could be different to the connection that sends the message.
-	connToUnsub := GetConnection(ConnectionId(msg.ConnId))
+	connToUnsub := GetConnectionOfConnection(ConnectionId(msg.ConnId), ctx.Connection)
 	if connToUnsub == nil {
 		ctx.Connection.Logger().Error("invalid ConnectionId for unsub", zap.Uint32("connId", msg.ConnId))
+		replyError(ctx, channeldpb.ErrorMessage_CONNECTION_NOT_FOUND, "connection to unsub not found")
 		return
 	}
 
 	hasAccess, accessErr := ctx.Channel.CheckACL(ctx.Connection, ChannelAccessType_Unsub)
-	if connToUnsub.id != ctx.Connection.Id() && !hasAccess {
+	if connToUnsub.id != ctx.Connection.Id() && isOperator(ctx.Connection) {
+		auditOperatorAction(ctx, "forceUnsub", zap.Uint32("unsubConnId", msg.ConnId))
+	} else if connToUnsub.id != ctx.Connection.Id() && !hasAccess {
 		ctx.Connection.Logger().Error("connection dosen't have access to unsub connection from this channel",
 			zap.Uint32("unsubConnId", msg.ConnId),
 			zap.String("channelType", ctx.Channel.channelType.String()),
 			zap.Uint32("channelId", uint32(ctx.Channel.id)),
 			zap.Error(accessErr),
 		)
+		replyError(ctx, channeldpb.ErrorMessage_ACCESS_DENIED, "no access to unsub another connection from the channel")
 		return
 	}
 
-	_, err := connToUnsub.UnsubscribeFromChannel(ctx.Channel)
+	unsubCh := ctx.Channel.subscribedChannelOf(connToUnsub)
+	if unsubCh == nil {
+		unsubCh = ctx.Channel
+	}
+	_, err := connToUnsub.UnsubscribeFromChannel(unsubCh)
 	if err != nil {
 		ctx.Connection.Logger().Warn("failed to unsub from channel",
 			zap.String("channelType", ctx.Channel.channelType.String()),
 			zap.Uint32("channelId", uint32(ctx.Channel.id)),
 			zap.Error(err),
 		)
+		replyError(ctx, channeldpb.ErrorMessage_NOT_SUBSCRIBED, err.Error())
 		return
 	}
 
@@ -749,7 +1009,208 @@ func handleUnsubFromChannel(ctx MessageContext) {
 	}
 }
 
+// Returns the channels to (un)sub from in the batch, or the ids of the channels that failed the check.
+func checkBatchSubAccess(ctx MessageContext, connToSub *Connection, channelIds []uint32, accessType ChannelAccessType) ([]*Channel, []uint32) {
+	channels := make([]*Channel, 0, len(channelIds))
+	failedChannelIds := make([]uint32, 0)
+	for _, chId := range channelIds {
+		ch := GetChannelOfConnection(common.ChannelId(chId), ctx.Connection)
+		if ch == nil || ch.IsRemoving() {
+			failedChannelIds = append(failedChannelIds, chId)
+			continue
+		}
+
+		if accessType == ChannelAccessType_Sub {
+			if err := ch.CheckRequiredTags(connToSub); err != nil {
+				ctx.Connection.Logger().Warn("connection doesn't have the required tags to sub to the channel in the batch",
+					zap.Uint32("connId", uint32(connToSub.Id())),
+					zap.String("channelType", ch.channelType.String()),
+					zap.Uint32("channelId", chId),
+				)
+				failedChannelIds = append(failedChannelIds, chId)
+				continue
+			}
+		}
+
+		hasAccess, err := ch.CheckACL(ctx.Connection, accessType)
+		if connToSub.Id() != ctx.Connection.Id() && !hasAccess {
+			ctx.Connection.Logger().Warn("connection doesn't have access to the channel in the batch",
+				zap.Uint32("connId", uint32(connToSub.Id())),
+				zap.String("channelType", ch.channelType.String()),
+				zap.Uint32("channelId", chId),
+				zap.Error(err),
+			)
+			failedChannelIds = append(failedChannelIds, chId)
+			continue
+		}
+		channels = append(channels, ch)
+	}
+	return channels, failedChannelIds
+}
+
+func handleSubToChannels(ctx MessageContext) {
+	if ctx.Channel != globalChannel {
+		ctx.Connection.Logger().Error("illegal attemp to batch sub to channels outside the GLOBAL channel")
+		replyError(ctx, channeldpb.ErrorMessage_WRONG_CHANNEL, "batch sub can only be sent to the GLOBAL channel")
+		return
+	}
+
+	msg, ok := ctx.Msg.(*channeldpb.SubscribedToChannelsMessage)
+	if !ok {
+		ctx.Connection.Logger().Error("message is not a SubscribedToChannelsMessage, will not be handled.")
+		return
+	}
+
+	var connToSub *Connection
+	if ctx.Connection.GetConnectionType() == channeldpb.ConnectionType_CLIENT {
+		connToSub = ctx.Connection.(*Connection)
+	} else {
+		// Only the server can specify a ConnId.
+		connToSub = GetConnectionOfConnection(ConnectionId(msg.ConnId), ctx.Connection)
+	}
+
+	if connToSub == nil {
+		ctx.Connection.Logger().Error("invalid ConnectionId for batch sub", zap.Uint32("connIdInMsg", msg.ConnId))
+		replyError(ctx, channeldpb.ErrorMessage_CONNECTION_NOT_FOUND, "connection to sub not found")
+		return
+	}
+
+	resultMsg := &channeldpb.SubscribedToChannelsResultMessage{
+		ConnId:     uint32(connToSub.Id()),
+		ConnType:   connToSub.GetConnectionType(),
+		SubOptions: make(map[uint32]*channeldpb.ChannelSubscriptionOptions),
+	}
+
+	// Check all the channels before subscribing to any of them
+	channels, failedChannelIds := checkBatchSubAccess(ctx, connToSub, msg.ChannelIds, ChannelAccessType_Sub)
+	if len(failedChannelIds) > 0 {
+		resultMsg.FailedChannelIds = failedChannelIds
+		ctx.Msg = resultMsg
+		ctx.Connection.Send(ctx)
+		ctx.Connection.Logger().Warn("failed to batch sub to channels", zap.Uint32s("failedChannelIds", failedChannelIds))
+		return
+	}
+
+	for _, ch := range channels {
+		subOptions := msg.SubOptions
+		if chSubOptions, exists := msg.ChannelSubOptions[uint32(ch.id)]; exists {
+			if subOptions == nil {
+				subOptions = chSubOptions
+			} else {
+				subOptions = proto.Clone(subOptions).(*channeldpb.ChannelSubscriptionOptions)
+				proto.Merge(subOptions, chSubOptions)
+			}
+		}
+
+		cs, alreadySubed := connToSub.SubscribeToChannel(ch.subscriptionTarget(connToSub, subOptions), subOptions)
+		if cs == nil {
+			continue
+		}
+		resultMsg.SubOptions[uint32(ch.id)] = &cs.options
+
+		// Notify the channel owner if not already subed and it's not the sender.
+		// The owner is only changed in the channel's goroutine, not the GLOBAL channel's.
+		if !alreadySubed {
+			sender := ctx.Connection
+			ch.Execute(func(ch *Channel) {
+				if ch.HasOwner() && ch.ownerConnection != sender {
+					ch.ownerConnection.sendSubscribed(MessageContext{}, ch, connToSub, 0, &cs.options)
+				}
+			})
+		}
+	}
+
+	ctx.Msg = resultMsg
+	ctx.Connection.Send(ctx)
+	// Notify the subscribed if it's not the sender.
+	if connToSub != ctx.Connection {
+		ctx.StubId = 0
+		connToSub.Send(ctx)
+	}
+}
+
+func handleUnsubFromChannels(ctx MessageContext) {
+	if ctx.Channel != globalChannel {
+		ctx.Connection.Logger().Error("illegal attemp to batch unsub from channels outside the GLOBAL channel")
+		replyError(ctx, channeldpb.ErrorMessage_WRONG_CHANNEL, "batch unsub can only be sent to the GLOBAL channel")
+		return
+	}
+
+	msg, ok := ctx.Msg.(*channeldpb.UnsubscribedFromChannelsMessage)
+	if !ok {
+		ctx.Connection.Logger().Error("message is not a UnsubscribedFromChannelsMessage, will not be handled.")
+		return
+	}
+
+	// The connection that unsubscribes. Could be different to the connection that sends the message.
+	connToUnsub := GetConnectionOfConnection(ConnectionId(msg.ConnId), ctx.Connection)
+	if connToUnsub == nil {
+		ctx.Connection.Logger().Error("invalid ConnectionId for batch unsub", zap.Uint32("connId", msg.ConnId))
+		replyError(ctx, channeldpb.ErrorMessage_CONNECTION_NOT_FOUND, "connection to unsub not found")
+		return
+	}
+
+	resultMsg := &channeldpb.UnsubscribedFromChannelsResultMessage{
+		ConnId:     uint32(connToUnsub.Id()),
+		ConnType:   connToUnsub.GetConnectionType(),
+		ChannelIds: make([]uint32, 0, len(msg.ChannelIds)),
+	}
+
+	// Check all the channels before unsubscribing from any of them
+	channels, failedChannelIds := checkBatchSubAccess(ctx, connToUnsub, msg.ChannelIds, ChannelAccessType_Unsub)
+	for _, ch := range channels {
+		if ch.subscribedChannelOf(connToUnsub) == nil {
+			failedChannelIds = append(failedChannelIds, uint32(ch.id))
+		}
+	}
+	if len(failedChannelIds) > 0 {
+		resultMsg.FailedChannelIds = failedChannelIds
+		ctx.Msg = resultMsg
+		ctx.Connection.Send(ctx)
+		ctx.Connection.Logger().Warn("failed to batch unsub from channels", zap.Uint32s("failedChannelIds", failedChannelIds))
+		return
+	}
+
+	for _, ch := range channels {
+		subCh := ch.subscribedChannelOf(connToUnsub)
+		if subCh == nil {
+			continue
+		}
+		if _, err := connToUnsub.UnsubscribeFromChannel(subCh); err != nil {
+			continue
+		}
+		resultMsg.ChannelIds = append(resultMsg.ChannelIds, uint32(ch.id))
+
+		// Notify the channel owner. The owner is only changed in the channel's goroutine, not the GLOBAL channel's.
+		sender := ctx.Connection
+		ch.Execute(func(ch *Channel) {
+			if ch.HasOwner() {
+				if ch.ownerConnection != sender && ch.ownerConnection != connToUnsub {
+					ch.ownerConnection.sendUnsubscribed(MessageContext{}, ch, connToUnsub, 0)
+				} else if ch.ownerConnection == connToUnsub {
+					// Reset the owner if it unsubscribed itself
+					ch.ownerConnection = nil
+				}
+			}
+		})
+	}
+
+	ctx.Msg = resultMsg
+	ctx.Connection.Send(ctx)
+	// Notify the unsubscribed if it's not the sender.
+	if connToUnsub != ctx.Connection {
+		ctx.StubId = 0
+		connToUnsub.Send(ctx)
+	}
+}
+
 func handleChannelDataUpdate(ctx MessageContext) {
+	// The replica's data is only updated by its primary channel. See SpawnReplica().
+	if ctx.Channel.primary != nil {
+		replyError(ctx, channeldpb.ErrorMessage_ACCESS_DENIED, "the channel is a read-only replica")
+		return
+	}
+
 	// Only channel owner or writable subsciptors can update the data
 	if ctx.Channel.ownerConnection != ctx.Connection {
 		cs := ctx.Channel.subscribedConnections[ctx.Connection]
@@ -758,13 +1219,24 @@ func handleChannelDataUpdate(ctx MessageContext) {
 				zap.String("channelType", ctx.Channel.channelType.String()),
 				zap.Uint32("channelId", uint32(ctx.Channel.id)),
 			)
+			replyError(ctx, channeldpb.ErrorMessage_ACCESS_DENIED, "no write access to the channel data")
 			return
 		}
 	}
 
+	if ctx.Channel.IsFrozen() {
+		ctx.Connection.Logger().Warn("attempt to update channel data but the channel is frozen",
+			zap.String("channelType", ctx.Channel.channelType.String()),
+			zap.Uint32("channelId", uint32(ctx.Channel.id)),
+		)
+		replyError(ctx, channeldpb.ErrorMessage_CHANNEL_FROZEN, "channel is frozen")
+		return
+	}
+
 	if ctx.Channel.Data() == nil {
 		ctx.Channel.Logger().Info("channel data is not initialized - should send CreateChannelMessage before ChannelDataUpdateMessage",
 			zap.Uint32("connId", uint32(ctx.Connection.Id())))
+		replyError(ctx, channeldpb.ErrorMessage_CHANNEL_DATA_NOT_INITIALIZED, "channel data is not initialized")
 		return
 	}
 
@@ -773,27 +1245,121 @@ func handleChannelDataUpdate(ctx MessageContext) {
 		ctx.Connection.Logger().Error("message is not a ChannelDataUpdateMessage, will not be handled.")
 		return
 	}
-	updateMsg, err := msg.Data.UnmarshalNew()
+	data, err := ctx.Channel.migrateData(msg.Data, msg.SchemaVersion)
+	if err != nil {
+		ctx.Connection.Logger().Error("failed to migrate channel update data", zap.Error(err),
+			zap.String("channelType", ctx.Channel.channelType.String()),
+			zap.Uint32("schemaVersion", msg.SchemaVersion))
+		replyError(ctx, channeldpb.ErrorMessage_INVALID_MESSAGE, err.Error())
+		return
+	}
+	updateMsg, err := data.UnmarshalNew()
 	if err != nil {
 		ctx.Connection.Logger().Error("failed to unmarshal channel update data", zap.Error(err),
 			zap.String("channelType", ctx.Channel.channelType.String()),
-			zap.String("typeUrl", msg.Data.TypeUrl))
+			zap.String("typeUrl", data.TypeUrl))
+		replyError(ctx, channeldpb.ErrorMessage_INVALID_MESSAGE, err.Error())
 		return
 	}
 
-	if ctx.Channel.spatialNotifier != nil {
+	// Merged at the end of the tick if the channel merges deterministically. See ChannelData.queueUpdate().
+	ctx.Channel.Data().queueUpdate(ctx.Connection.Id(), func() {
+		mergeChannelDataUpdate(ctx, msg, updateMsg)
+	})
+	ctx.Channel.countStats(&ctx.Channel.statsCounters.updates)
+}
+
+// Checks the update against the current channel data, then merges it. See handleChannelDataUpdate().
+func mergeChannelDataUpdate(ctx MessageContext, msg *channeldpb.ChannelDataUpdateMessage, updateMsg common.ChannelDataMessage) {
+	if msg.BaseVersion != nil {
+		if conflictFields := ctx.Channel.Data().checkConflicts(updateMsg, *msg.BaseVersion, ctx.Connection.Id()); len(conflictFields) > 0 {
+			ctx.Connection.Logger().Debug("rejected channel data update based on a stale version",
+				zap.Uint32("channelId", uint32(ctx.Channel.id)),
+				zap.Uint64("baseVersion", *msg.BaseVersion),
+				zap.Uint64("currentVersion", ctx.Channel.Data().Version()),
+				zap.Strings("conflictFields", conflictFields),
+			)
+			ctx.Msg = &channeldpb.ChannelDataConflictMessage{
+				Data:           msg.Data,
+				BaseVersion:    *msg.BaseVersion,
+				CurrentVersion: ctx.Channel.Data().Version(),
+				ConflictFields: conflictFields,
+			}
+			ctx.MsgType = channeldpb.MessageType_CHANNEL_DATA_CONFLICT
+			ctx.Connection.Send(ctx)
+			return
+		}
+	}
+
+	dataUpdateConnId := ConnectionId(msg.ContextConnId)
+	if ctx.Connection.GetConnectionType() == channeldpb.ConnectionType_CLIENT {
+		dataUpdateConnId = ctx.Connection.Id()
+	}
+	if msg.InputSeq > 0 {
 		if ctx.Connection.GetConnectionType() == channeldpb.ConnectionType_CLIENT {
-			ctx.Channel.SetDataUpdateConnId(ctx.Connection.Id())
+			ctx.Channel.Data().recordInputSeq(ctx.Connection.Id(), msg.InputSeq)
 		} else {
-			ctx.Channel.SetDataUpdateConnId(ConnectionId(msg.ContextConnId))
+			ctx.Channel.Data().recordInputSeq(ConnectionId(msg.ContextConnId), msg.InputSeq)
+		}
+	}
+	if ctx.Channel.spatialNotifier != nil {
+		ctx.Channel.SetDataUpdateConnId(dataUpdateConnId)
+	}
+	ctx.Channel.Data().onUpdate(updateMsg, ctx.arrivalTime, ctx.Connection.Id(), ctx.Channel.spatialNotifier, msg.CrdtTimestamp)
+}
+
+func handleQueryChannelData(ctx MessageContext) {
+	msg, ok := ctx.Msg.(*channeldpb.QueryChannelDataMessage)
+	if !ok {
+		ctx.Connection.Logger().Error("message is not a QueryChannelDataMessage, will not be handled.")
+		return
+	}
+
+	// The channel owner and the subscribers can always read the data. Other connections need the sub access.
+	// The operators can inspect any channel.
+	if isOperator(ctx.Connection) {
+		auditOperatorAction(ctx, "inspect")
+	} else if ctx.Channel.ownerConnection != ctx.Connection {
+		if _, subscribed := ctx.Channel.subscribedConnections[ctx.Connection]; !subscribed {
+			hasAccess, err := ctx.Channel.CheckACL(ctx.Connection, ChannelAccessType_Sub)
+			if !hasAccess || ctx.Channel.CheckRequiredTags(ctx.Connection) != nil {
+				ctx.Connection.Logger().Warn("attempt to query channel data but has no access",
+					zap.String("channelType", ctx.Channel.channelType.String()),
+					zap.Uint32("channelId", uint32(ctx.Channel.id)),
+					zap.Error(err),
+				)
+				replyError(ctx, channeldpb.ErrorMessage_ACCESS_DENIED, "no access to query the channel data")
+				return
+			}
 		}
 	}
-	ctx.Channel.Data().OnUpdate(updateMsg, ctx.arrivalTime, ctx.Connection.Id(), ctx.Channel.spatialNotifier)
+
+	if ctx.Channel.GetDataMessage() == nil {
+		ctx.Connection.Logger().Warn("channel data is not initialized, will not be queried",
+			zap.String("channelType", ctx.Channel.channelType.String()),
+			zap.Uint32("channelId", uint32(ctx.Channel.id)),
+		)
+		replyError(ctx, channeldpb.ErrorMessage_CHANNEL_DATA_NOT_INITIALIZED, "channel data is not initialized")
+		return
+	}
+
+	// Copy the data as the field masks modify the message
+	dataMsg := proto.Clone(ctx.Channel.GetDataMessage())
+	fmutils.Filter(dataMsg, msg.DataFieldMasks)
+	any, err := anypb.New(dataMsg)
+	if err != nil {
+		ctx.Connection.Logger().Error("failed to marshal the queried channel data", zap.Error(err))
+		return
+	}
+
+	ctx.Msg = &channeldpb.QueryChannelDataResultMessage{Data: any, Version: ctx.Channel.Data().Version()}
+	ctx.Connection.Send(ctx)
 }
 
 func handleDisconnect(ctx MessageContext) {
 	if ctx.Channel != globalChannel {
 		ctx.Connection.Logger().Error("illegal attemp to disconnect another connection outside the GLOBAL channel")
+		replyError(ctx, channeldpb.ErrorMessage_WRONG_CHANNEL, "connections can only be disconnected in the GLOBAL channel")
 		return
 	}
 
@@ -803,31 +1369,31 @@ func handleDisconnect(ctx MessageContext) {
 		return
 	}
 
-	connToDisconnect := GetConnection(ConnectionId(msg.ConnId))
+	connToDisconnect := GetConnectionOfConnection(ConnectionId(msg.ConnId), ctx.Connection)
 	if connToDisconnect == nil {
 		ctx.Connection.Logger().Warn("could not find the connection to disconnect",
 			zap.Uint32("targetConnId", msg.ConnId),
 		)
+		replyError(ctx, channeldpb.ErrorMessage_CONNECTION_NOT_FOUND, "connection to disconnect not found")
 		return
 	}
 
-	if err := connToDisconnect.Disconnect(); err != nil {
-		ctx.Connection.Logger().Warn("failed to disconnect a connection",
-			zap.Uint32("targetConnId", msg.ConnId),
-			zap.String("targetConnType", connToDisconnect.connectionType.String()),
-		)
-	} else {
-		ctx.Connection.Logger().Info("successfully disconnected a connection",
-			zap.Uint32("targetConnId", msg.ConnId),
-			zap.String("targetConnType", connToDisconnect.connectionType.String()),
-		)
+	reason := msg.Reason
+	if reason == channeldpb.DisconnectMessage_UNSPECIFIED {
+		reason = channeldpb.DisconnectMessage_KICKED
 	}
-	connToDisconnect.Close()
+	ctx.Connection.Logger().Info("disconnecting a connection",
+		zap.Uint32("targetConnId", msg.ConnId),
+		zap.String("targetConnType", connToDisconnect.connectionType.String()),
+		zap.String("reason", reason.String()),
+	)
+	connToDisconnect.CloseWithReason(reason, msg.Detail)
 }
 
 func handleQuerySpatialChannel(ctx MessageContext) {
 	if ctx.Channel != globalChannel {
 		ctx.Connection.Logger().Error("illegal attemp to query spatial channel outside the GLOBAL channel")
+		replyError(ctx, channeldpb.ErrorMessage_WRONG_CHANNEL, "spatial channels can only be queried in the GLOBAL channel")
 		return
 	}
 
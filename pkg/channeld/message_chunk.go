@@ -0,0 +1,158 @@
+package channeld
+
+import (
+	"errors"
+	"sync/atomic"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+)
+
+// The protocol version that introduces MessagePack.continued. The older connections can't reassemble the chunks.
+const chunkProtocolVersion uint32 = 7
+
+// The room for the other fields of the MessagePack and the Packet, and for the overhead of the compression.
+const chunkOverhead = 128
+
+// The max size of the msgBody in a chunk. The connection that negotiates a smaller max packet size uses smaller chunks.
+const MaxChunkSize = MaxPacketSize - chunkOverhead
+
+// The max size of the message reassembled from the chunks, so a connection can't exhaust the memory.
+const MaxChunkedMessageSize = 64 * 1024 * 1024
+
+// The max number of the chunked messages being reassembled at the same time, per connection.
+const MaxPendingChunkedMessages = 16
+
+// The default max total size of the chunked messages being reassembled at the same time, per connection.
+// See MessagePackAssembler.MaxPendingBytes.
+const DefaultMaxPendingChunkedBytes = MaxChunkedMessageSize
+
+var ErrChunkedMessageOversized = errors.New("the chunked message exceeds MaxChunkedMessageSize")
+var ErrPendingChunksExceeded = errors.New("the pending chunked messages exceed MaxPendingChunkedMessages or MaxPendingBytes")
+
+// Splits the MessagePack whose msgBody exceeds chunkSize into consecutive chunks, which should be sent in order.
+// All the chunks but the last one have continued = true. The chunks share the underlying array of the msgBody.
+// Returns the MessagePack itself if it doesn't need to be split.
+func SplitMessagePack(mp *channeldpb.MessagePack, chunkSize int) []*channeldpb.MessagePack {
+	if len(mp.MsgBody) <= chunkSize {
+		return []*channeldpb.MessagePack{mp}
+	}
+	chunks := make([]*channeldpb.MessagePack, 0, (len(mp.MsgBody)+chunkSize-1)/chunkSize)
+	for offset := 0; offset < len(mp.MsgBody); offset += chunkSize {
+		end := offset + chunkSize
+		if end > len(mp.MsgBody) {
+			end = len(mp.MsgBody)
+		}
+		chunks = append(chunks, &channeldpb.MessagePack{
+			ChannelId:    mp.ChannelId,
+			Broadcast:    mp.Broadcast,
+			StubId:       mp.StubId,
+			MsgType:      mp.MsgType,
+			MsgBody:      mp.MsgBody[offset:end],
+			ChannelKey:   mp.ChannelKey,
+			RawPayload:   mp.RawPayload,
+			ClientConnId: mp.ClientConnId,
+			Continued:    end < len(mp.MsgBody),
+			TraceId:      mp.TraceId,
+		})
+	}
+	return chunks
+}
+
+type chunkKey struct {
+	channelId  uint32
+	channelKey string
+	msgType    uint32
+}
+
+// Reassembles the chunks split by SplitMessagePack. Not goroutine-safe, so each receiving goroutine should have its own.
+//
+// The chunks are keyed by the channelId, channelKey and msgType, as the messages of the same type to the same channel
+// always go through the same send queue in order, while the other messages can be interleaved with the chunks.
+type MessagePackAssembler struct {
+	// The max total size of the chunked messages being reassembled at the same time. 0 means DefaultMaxPendingChunkedBytes.
+	MaxPendingBytes int
+	pending         map[chunkKey][]byte
+	pendingBytes    int
+}
+
+// Returns the complete MessagePack, or nil if more chunks are expected. Returns ErrPendingChunksExceeded if the chunks
+// of the connection exceed MaxPendingChunkedMessages or MaxPendingBytes, in which case the connection should be closed.
+func (a *MessagePackAssembler) Add(mp *channeldpb.MessagePack) (*channeldpb.MessagePack, error) {
+	key := chunkKey{mp.ChannelId, mp.ChannelKey, mp.MsgType}
+	body, exists := a.pending[key]
+	if !exists && !mp.Continued {
+		return mp, nil
+	}
+
+	if len(body)+len(mp.MsgBody) > MaxChunkedMessageSize {
+		a.remove(key, body)
+		return nil, ErrChunkedMessageOversized
+	}
+	if mp.Continued {
+		if (!exists && len(a.pending) >= MaxPendingChunkedMessages) || a.pendingBytes+len(mp.MsgBody) > a.maxPendingBytes() {
+			return nil, ErrPendingChunksExceeded
+		}
+		if a.pending == nil {
+			a.pending = make(map[chunkKey][]byte)
+		}
+		a.pending[key] = append(body, mp.MsgBody...)
+		a.pendingBytes += len(mp.MsgBody)
+		return nil, nil
+	}
+
+	a.remove(key, body)
+	body = append(body, mp.MsgBody...)
+	mp.MsgBody = body
+	return mp, nil
+}
+
+func (a *MessagePackAssembler) maxPendingBytes() int {
+	if a.MaxPendingBytes > 0 {
+		return a.MaxPendingBytes
+	}
+	return DefaultMaxPendingChunkedBytes
+}
+
+func (a *MessagePackAssembler) remove(key chunkKey, body []byte) {
+	delete(a.pending, key)
+	a.pendingBytes -= len(body)
+}
+
+// Pushes the MessagePack to the send queue of its priority. If the message doesn't fit in a packet, it's split into
+// chunks for the connection that can reassemble them. Otherwise it's dropped, as it would stall the flush forever,
+// and the sender of the message is notified with MessageTooLargeMessage.
+func (c *Connection) queueMessagePack(mp *channeldpb.MessagePack, ctx MessageContext) {
+	chunks := []*channeldpb.MessagePack{mp}
+	if maxChunkSize := c.maxChunkSize(); len(mp.MsgBody) > maxChunkSize && c.ProtocolVersion() >= chunkProtocolVersion {
+		chunks = SplitMessagePack(mp, maxChunkSize)
+		messageChunked.WithLabelValues(c.connectionType.String()).Inc()
+	} else if len(mp.MsgBody) > c.MaxPacketSize() {
+		c.logger.Error("dropped the oversized message, as the connection doesn't support chunking",
+			zap.Uint32("msgType", mp.MsgType),
+			zap.Int("msgSize", len(mp.MsgBody)),
+			zap.Uint32("protocolVersion", c.ProtocolVersion()),
+		)
+		if sender, ok := ctx.Connection.(*Connection); ok && sender != c {
+			sender.sendMessageTooLarge(mp.ChannelId, &channeldpb.MessageTooLargeMessage{
+				Size:            uint32(len(mp.MsgBody)),
+				MaxPacketSize:   uint32(c.MaxPacketSize()),
+				MsgType:         mp.MsgType,
+				StubId:          mp.StubId,
+				RecipientConnId: uint32(c.id),
+			})
+		}
+		return
+	}
+
+	atomic.AddInt64(&c.sendQueueBytes, int64(len(mp.MsgBody)))
+	if !ctx.updateArrival.IsZero() && ctx.Channel != nil {
+		// The fan-out completes when the last chunk is flushed
+		c.trackFanOutLatency(chunks[len(chunks)-1], ctx.updateArrival, ctx.Channel.channelType)
+	}
+	// May block if the chunks outnumber the capacity of the queue, until the flush goroutine catches up.
+	queue := c.sendQueues[GetMessagePriority(ctx.MsgType)]
+	for _, chunk := range chunks {
+		queue <- chunk
+	}
+}
@@ -0,0 +1,172 @@
+package channeld
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSplitAndAssembleMessagePack(t *testing.T) {
+	small := &channeldpb.MessagePack{ChannelId: 1, MsgType: 100, MsgBody: []byte("abc")}
+	assert.Equal(t, []*channeldpb.MessagePack{small}, SplitMessagePack(small, 3))
+
+	large := &channeldpb.MessagePack{ChannelId: 1, StubId: 2, MsgType: 100, MsgBody: []byte("abcdefgh")}
+	chunks := SplitMessagePack(large, 3)
+	if !assert.Len(t, chunks, 3) {
+		return
+	}
+	assert.Equal(t, []byte("gh"), chunks[2].MsgBody)
+	for i, chunk := range chunks {
+		assert.EqualValues(t, 2, chunk.StubId)
+		assert.Equal(t, i < 2, chunk.Continued)
+	}
+
+	a := &MessagePackAssembler{}
+	mp, err := a.Add(chunks[0])
+	assert.NoError(t, err)
+	assert.Nil(t, mp)
+	// The messages of the other channels and types can be interleaved with the chunks
+	other := &channeldpb.MessagePack{ChannelId: 2, MsgType: 100, MsgBody: []byte("x")}
+	mp, _ = a.Add(other)
+	assert.Equal(t, other, mp)
+	other = &channeldpb.MessagePack{ChannelId: 1, MsgType: 101, MsgBody: []byte("y")}
+	mp, _ = a.Add(other)
+	assert.Equal(t, other, mp)
+
+	mp, _ = a.Add(chunks[1])
+	assert.Nil(t, mp)
+	mp, err = a.Add(chunks[2])
+	assert.NoError(t, err)
+	if assert.NotNil(t, mp) {
+		assert.Equal(t, []byte("abcdefgh"), mp.MsgBody)
+		assert.EqualValues(t, 2, mp.StubId)
+		assert.False(t, mp.Continued)
+	}
+	assert.Empty(t, a.pending)
+}
+
+func TestAssemblerPendingLimits(t *testing.T) {
+	a := &MessagePackAssembler{}
+	for i := 0; i < MaxPendingChunkedMessages; i++ {
+		mp, err := a.Add(&channeldpb.MessagePack{ChannelId: uint32(i), MsgType: 100, MsgBody: []byte("a"), Continued: true})
+		assert.NoError(t, err)
+		assert.Nil(t, mp)
+	}
+	// The chunks of the pending messages are still accepted
+	_, err := a.Add(&channeldpb.MessagePack{ChannelId: 0, MsgType: 100, MsgBody: []byte("b"), Continued: true})
+	assert.NoError(t, err)
+	_, err = a.Add(&channeldpb.MessagePack{ChannelId: 0, MsgType: 101, MsgBody: []byte("c"), Continued: true})
+	assert.ErrorIs(t, err, ErrPendingChunksExceeded)
+	assert.Equal(t, MaxPendingChunkedMessages+1, a.pendingBytes)
+
+	mp, err := a.Add(&channeldpb.MessagePack{ChannelId: 0, MsgType: 100, MsgBody: []byte("c")})
+	assert.NoError(t, err)
+	if assert.NotNil(t, mp) {
+		assert.Equal(t, []byte("abc"), mp.MsgBody)
+	}
+	assert.Equal(t, MaxPendingChunkedMessages-1, a.pendingBytes)
+
+	// The total size of the pending messages is limited
+	a = &MessagePackAssembler{}
+	half := make([]byte, DefaultMaxPendingChunkedBytes/2)
+	_, err = a.Add(&channeldpb.MessagePack{ChannelId: 1, MsgBody: half, Continued: true})
+	assert.NoError(t, err)
+	_, err = a.Add(&channeldpb.MessagePack{ChannelId: 2, MsgBody: half, Continued: true})
+	assert.NoError(t, err)
+	_, err = a.Add(&channeldpb.MessagePack{ChannelId: 3, MsgBody: []byte("a"), Continued: true})
+	assert.ErrorIs(t, err, ErrPendingChunksExceeded)
+
+	a = &MessagePackAssembler{MaxPendingBytes: 2}
+	_, err = a.Add(&channeldpb.MessagePack{ChannelId: 1, MsgBody: []byte("ab"), Continued: true})
+	assert.NoError(t, err)
+	_, err = a.Add(&channeldpb.MessagePack{ChannelId: 2, MsgBody: []byte("a"), Continued: true})
+	assert.ErrorIs(t, err, ErrPendingChunksExceeded)
+}
+
+func TestReceiveChunkBeforeNegotiated(t *testing.T) {
+	InitLogs()
+	InitChannels()
+
+	// Writes the chunk to the read buffer and reads it
+	read := func(c *Connection, channelId uint32) error {
+		body, _ := proto.Marshal(&channeldpb.MessagePack{ChannelId: channelId, MsgType: uint32(channeldpb.MessageType_USER_SPACE_START), MsgBody: []byte{1}, Continued: true})
+		buf := make([]byte, MaxPacketHeaderSize+len(body))
+		copy(buf[MaxPacketHeaderSize:], body)
+		c.readPos = copy(c.readBuffer, PutPacketHeader(buf, len(body), WireVersion_V2, 0))
+		bufPos := 0
+		_, err := c.readPacket(&bufPos)
+		return err
+	}
+
+	// Not authenticated
+	c := newLoopbackConnection(channeldpb.CompressionType_NO_COMPRESSION)
+	c.setProtocolVersion(chunkProtocolVersion)
+	assert.Error(t, read(c, 100))
+	assert.Empty(t, c.chunkAssembler.pending)
+
+	// Authenticated with an older protocol version
+	c = newLoopbackConnection(channeldpb.CompressionType_NO_COMPRESSION)
+	c.state = int32(ConnectionState_READY)
+	c.setProtocolVersion(chunkProtocolVersion - 1)
+	assert.Error(t, read(c, 100))
+
+	c.setProtocolVersion(chunkProtocolVersion)
+	for i := 0; i < MaxPendingChunkedMessages; i++ {
+		assert.NoError(t, read(c, uint32(100+i)))
+	}
+	assert.ErrorIs(t, read(c, 200), ErrPendingChunksExceeded)
+}
+
+func TestSendChunkedMessage(t *testing.T) {
+	InitLogs()
+
+	payload := make([]byte, 1<<20)
+	// Incompressible, so the compression can't make the packets smaller
+	rand.Read(payload)
+	ctx := MessageContext{
+		MsgType:    channeldpb.MessageType_USER_SPACE_START,
+		Msg:        &channeldpb.ServerForwardMessage{Payload: payload},
+		ChannelId:  100,
+		rawPayload: true,
+	}
+
+	c := newLoopbackConnection(channeldpb.CompressionType_SNAPPY)
+	// The older connection can't reassemble the chunks
+	c.setProtocolVersion(chunkProtocolVersion - 1)
+	(&queuedMessagePackSender{}).Send(c, ctx)
+	assert.Equal(t, 0, c.sendQueueLen())
+
+	c.setProtocolVersion(chunkProtocolVersion)
+	(&queuedMessagePackSender{}).Send(c, ctx)
+	assert.Equal(t, (len(payload)+MaxChunkSize-1)/MaxChunkSize, c.sendQueueLen())
+
+	a := &MessagePackAssembler{}
+	var received *channeldpb.MessagePack
+	for c.sendQueueLen() > 0 {
+		c.flush()
+		packet := c.conn.(*loopbackConn).packet
+		header, ok, err := ReadPacketHeader(packet)
+		if !assert.NoError(t, err) || !assert.True(t, ok) {
+			return
+		}
+		assert.LessOrEqual(t, header.FullSize()-header.HeaderSize, MaxPacketSize)
+		body, err := snappy.Decode(nil, packet[header.HeaderSize:header.FullSize()])
+		assert.NoError(t, err)
+		var p channeldpb.Packet
+		assert.NoError(t, proto.Unmarshal(body, &p))
+		for _, mp := range p.Messages {
+			if full, _ := a.Add(mp); full != nil {
+				received = full
+			}
+		}
+	}
+	if assert.NotNil(t, received) {
+		assert.True(t, received.RawPayload)
+		assert.Equal(t, payload, received.MsgBody)
+	}
+	assert.EqualValues(t, 0, c.sendQueueBytes)
+}
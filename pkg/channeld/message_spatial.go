@@ -49,7 +49,7 @@ func handleUpdateSpatialInterest(ctx MessageContext) {
 		return
 	}
 
-	clientConn := GetConnection(ConnectionId(msg.ConnId))
+	clientConn := GetConnectionOfConnection(ConnectionId(msg.ConnId), ctx.Connection)
 	if clientConn == nil {
 		ctx.Connection.Logger().Error("cannot find client connection to update spatial interest", zap.Uint32("clientConnId", msg.ConnId))
 		return
@@ -87,7 +87,7 @@ func handleUpdateSpatialInterest(ctx MessageContext) {
 	channelsToUnsub := Difference(existingsSubs, channelsToSub)
 
 	for chId := range channelsToUnsub {
-		if ctx.Channel = GetChannel(chId); ctx.Channel == nil {
+		if ctx.Channel = GetChannelOfConnection(chId, clientConn); ctx.Channel == nil {
 			continue
 		}
 		ctx.MsgType = channeldpb.MessageType_UNSUB_FROM_CHANNEL
@@ -102,7 +102,7 @@ func handleUpdateSpatialInterest(ctx MessageContext) {
 	}
 
 	for chId, subOptions := range channelsToSub {
-		if ctx.Channel = GetChannel(chId); ctx.Channel == nil {
+		if ctx.Channel = GetChannelOfConnection(chId, clientConn); ctx.Channel == nil {
 			continue
 		}
 		ctx.MsgType = channeldpb.MessageType_SUB_TO_CHANNEL
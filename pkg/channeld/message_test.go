@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/metaworking/channeld/internal/testpb"
 	"github.com/metaworking/channeld/pkg/channeldpb"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/protobuf/proto"
@@ -13,7 +14,8 @@ import (
 
 func TestHandleListChannels(t *testing.T) {
 	InitLogs()
-	InitChannels()
+	// Only count the channels created by the test
+	useNewChannelRegistry(t)
 	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
 
 	c := addTestConnection(channeldpb.ConnectionType_SERVER)
@@ -98,6 +100,30 @@ func TestMessageHandlers(t *testing.T) {
 		case msgType == channeldpb.MessageType_INVALID:
 		case msgType == channeldpb.MessageType_CHANNEL_DATA_HANDOVER:
 		case msgType == channeldpb.MessageType_SPATIAL_REGIONS_UPDATE:
+		// Handled in the receive goroutine. See handleRelayMessage().
+		case msgType == channeldpb.MessageType_RELAY:
+		// The reply of the idle ping. Handled in the receive goroutine.
+		case msgType == channeldpb.MessageType_PING:
+		// Handled in the receive goroutine. See handleAnalyticsMessage().
+		case msgType == channeldpb.MessageType_ANALYTICS:
+		// Handled in the receive goroutine. See handleBandwidthReport().
+		case msgType == channeldpb.MessageType_BANDWIDTH_REPORT:
+		// Unpacked in the receive goroutine. See handleServerBatchMessage().
+		case msgType == channeldpb.MessageType_SERVER_BATCH:
+		// Only sent by channeld
+		case msgType == channeldpb.MessageType_CHANNEL_DATA_CONFLICT:
+		case msgType == channeldpb.MessageType_CHANNEL_RECOVERED:
+		case msgType == channeldpb.MessageType_DUPLICATE_LOGIN:
+		case msgType == channeldpb.MessageType_SLOW_SUBSCRIBER:
+		case msgType == channeldpb.MessageType_QUOTA_EXCEEDED:
+		case msgType == channeldpb.MessageType_PRESENCE_UPDATE:
+		case msgType == channeldpb.MessageType_CHAT_PENALTY:
+		case msgType == channeldpb.MessageType_CHANNEL_OWNER_CHANGED:
+		case msgType == channeldpb.MessageType_MESSAGE_TOO_LARGE:
+		case msgType == channeldpb.MessageType_CHANNEL_STATS:
+		case msgType == channeldpb.MessageType_ERROR:
+		case msgType == channeldpb.MessageType_CHANNEL_TICK_LAG:
+		case msgType == channeldpb.MessageType_SESSION_KEY_ROTATION:
 		case value >= int32(channeldpb.MessageType_USER_SPACE_START):
 			continue
 		default:
@@ -178,3 +204,256 @@ func TestMessageCopy(t *testing.T) {
 	proto.Reset(msgCopy)
 	assert.Equal(t, channeldpb.ChannelType_UNKNOWN, msgCopy.ChannelType)
 }
+
+func TestHandleBatchSubAndUnsub(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	ch1, _ := CreateChannel(channeldpb.ChannelType_SUBWORLD, server)
+	ch2, _ := CreateChannel(channeldpb.ChannelType_SUBWORLD, server)
+
+	// One of the channels doesn't exist - none should be subscribed
+	handleSubToChannels(MessageContext{
+		MsgType: channeldpb.MessageType_SUB_TO_CHANNELS,
+		Msg: &channeldpb.SubscribedToChannelsMessage{
+			ChannelIds: []uint32{uint32(ch1.id), uint32(ch2.id), 0xffff},
+		},
+		Connection: client,
+		Channel:    globalChannel,
+	})
+	result := client.latestMsg().(*channeldpb.SubscribedToChannelsResultMessage)
+	assert.Equal(t, []uint32{0xffff}, result.FailedChannelIds)
+	assert.NotContains(t, ch1.GetAllConnections(), client)
+	assert.NotContains(t, ch2.GetAllConnections(), client)
+
+	handleSubToChannels(MessageContext{
+		MsgType: channeldpb.MessageType_SUB_TO_CHANNELS,
+		Msg: &channeldpb.SubscribedToChannelsMessage{
+			ChannelIds: []uint32{uint32(ch1.id), uint32(ch2.id)},
+			SubOptions: &channeldpb.ChannelSubscriptionOptions{FanOutIntervalMs: proto.Uint32(50)},
+			ChannelSubOptions: map[uint32]*channeldpb.ChannelSubscriptionOptions{
+				uint32(ch2.id): {FanOutIntervalMs: proto.Uint32(100)},
+			},
+		},
+		Connection: client,
+		Channel:    globalChannel,
+	})
+	result = client.latestMsg().(*channeldpb.SubscribedToChannelsResultMessage)
+	assert.Empty(t, result.FailedChannelIds)
+	assert.Len(t, result.SubOptions, 2)
+	assert.EqualValues(t, 50, *result.SubOptions[uint32(ch1.id)].FanOutIntervalMs)
+	assert.EqualValues(t, 100, *result.SubOptions[uint32(ch2.id)].FanOutIntervalMs)
+	assert.Contains(t, ch1.GetAllConnections(), client)
+	assert.Contains(t, ch2.GetAllConnections(), client)
+	// The channel owner is notified for each channel, in the channel's goroutine
+	for _, ch := range []*Channel{ch1, ch2} {
+		done := make(chan struct{})
+		ch.Execute(func(ch *Channel) { close(done) })
+		<-done
+	}
+	assert.IsType(t, &channeldpb.SubscribedToChannelResultMessage{}, server.latestMsg())
+
+	handleUnsubFromChannels(MessageContext{
+		MsgType: channeldpb.MessageType_UNSUB_FROM_CHANNELS,
+		Msg: &channeldpb.UnsubscribedFromChannelsMessage{
+			ConnId:     uint32(client.Id()),
+			ChannelIds: []uint32{uint32(ch1.id), uint32(ch2.id)},
+		},
+		Connection: client,
+		Channel:    globalChannel,
+	})
+	unsubResult := client.latestMsg().(*channeldpb.UnsubscribedFromChannelsResultMessage)
+	assert.Empty(t, unsubResult.FailedChannelIds)
+	assert.ElementsMatch(t, []uint32{uint32(ch1.id), uint32(ch2.id)}, unsubResult.ChannelIds)
+	assert.NotContains(t, ch1.GetAllConnections(), client)
+	assert.NotContains(t, ch2.GetAllConnections(), client)
+}
+
+func TestHandleBatchSubOwner(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+	// The channel goroutine sleeps after the first tick, so the queued messages are only handled by tickMessages() below
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{TickIntervalMs: 3600000}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	defer RemoveChannel(ch)
+	time.Sleep(50 * time.Millisecond)
+
+	handleSubToChannels(MessageContext{
+		MsgType: channeldpb.MessageType_SUB_TO_CHANNELS,
+		Msg: &channeldpb.SubscribedToChannelsMessage{
+			ChannelIds: []uint32{uint32(ch.id)},
+		},
+		Connection: client,
+		Channel:    globalChannel,
+	})
+	assert.IsType(t, &channeldpb.SubscribedToChannelsResultMessage{}, client.latestMsg())
+	// The owner is notified in the channel's goroutine
+	assert.Nil(t, server.latestMsg())
+	ch.tickMessages(time.Now(), "")
+	if subResult, ok := server.latestMsg().(*channeldpb.SubscribedToChannelResultMessage); assert.True(t, ok) {
+		assert.EqualValues(t, client.Id(), subResult.ConnId)
+	}
+}
+
+func TestHandleBatchUnsubOwner(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+	// The channel goroutine sleeps after the first tick, so the queued messages are only handled by tickMessages() below
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{TickIntervalMs: 3600000}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	defer RemoveChannel(ch)
+	time.Sleep(50 * time.Millisecond)
+	server.SubscribeToChannel(ch, nil)
+
+	handleUnsubFromChannels(MessageContext{
+		MsgType: channeldpb.MessageType_UNSUB_FROM_CHANNELS,
+		Msg: &channeldpb.UnsubscribedFromChannelsMessage{
+			ConnId:     uint32(server.Id()),
+			ChannelIds: []uint32{uint32(ch.id)},
+		},
+		Connection: server,
+		Channel:    globalChannel,
+	})
+	unsubResult := server.latestMsg().(*channeldpb.UnsubscribedFromChannelsResultMessage)
+	assert.Equal(t, []uint32{uint32(ch.id)}, unsubResult.ChannelIds)
+	// The owner is reset in the channel's goroutine
+	assert.Equal(t, server, ch.ownerConnection)
+	ch.tickMessages(time.Now(), "")
+	assert.False(t, ch.HasOwner())
+}
+
+func TestHandleQueryChannelData(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		ACLSettings: ACLSettingsType{Sub: ChannelAccessLevel_OwnerOnly},
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	// Stop the channel.Tick() goroutine
+	ch.removing = 1
+	dataMsg := &testpb.TestChannelDataMessage{Text: "a", Num: 1}
+	ch.InitData(dataMsg, nil)
+
+	queryCtx := MessageContext{
+		MsgType:    channeldpb.MessageType_QUERY_CHANNEL_DATA,
+		Msg:        &channeldpb.QueryChannelDataMessage{DataFieldMasks: []string{"num"}},
+		Connection: client,
+		Channel:    ch,
+	}
+
+	// The client has no sub access
+	handleQueryChannelData(queryCtx)
+	if errMsg, ok := client.latestMsg().(*channeldpb.ErrorMessage); assert.True(t, ok) {
+		assert.Equal(t, channeldpb.ErrorMessage_ACCESS_DENIED, errMsg.Code)
+	}
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		ACLSettings: ACLSettingsType{Sub: ChannelAccessLevel_Any},
+	}
+	handleQueryChannelData(queryCtx)
+	result, ok := client.latestMsg().(*channeldpb.QueryChannelDataResultMessage)
+	if assert.True(t, ok) {
+		queried := &testpb.TestChannelDataMessage{}
+		assert.NoError(t, result.Data.UnmarshalTo(queried))
+		assert.EqualValues(t, 1, queried.Num)
+		assert.Empty(t, queried.Text)
+	}
+	// The query doesn't subscribe or modify the channel data
+	assert.NotContains(t, ch.GetAllConnections(), client)
+	assert.Equal(t, "a", dataMsg.Text)
+}
+
+func TestRawPayloadUserMessage(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	// Use the real sender to check the message packs in the send queues
+	server.sender = &queuedMessagePackSender{}
+	client.sender = &queuedMessagePackSender{}
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	client.SubscribeToChannel(ch, nil)
+
+	// Not a valid Protobuf message
+	payload := []byte{0xff, 0xff, 0xff, 0xff}
+
+	// client -> channeld -> server
+	client.OnAuthenticated("")
+	client.receiveMessage(&channeldpb.MessagePack{
+		ChannelId:  uint32(ch.Id()),
+		MsgType:    uint32(channeldpb.MessageType_USER_SPACE_START),
+		MsgBody:    payload,
+		RawPayload: true,
+	})
+	var mp *channeldpb.MessagePack
+	select {
+	case mp = <-server.sendQueues[MessagePriority_UserSpace]:
+	case <-time.After(time.Second):
+	}
+	if assert.NotNil(t, mp) {
+		assert.True(t, mp.RawPayload)
+		assert.Equal(t, payload, mp.MsgBody)
+		assert.EqualValues(t, client.Id(), mp.ClientConnId)
+	}
+
+	// server -> channeld -> client
+	HandleServerToClientUserMessage(MessageContext{
+		MsgType:    channeldpb.MessageType_USER_SPACE_START,
+		Msg:        &channeldpb.ServerForwardMessage{ClientConnId: uint32(client.Id()), Payload: payload},
+		Broadcast:  uint32(channeldpb.BroadcastType_SINGLE_CONNECTION),
+		ChannelId:  uint32(ch.Id()),
+		Connection: server,
+		Channel:    ch,
+		rawPayload: true,
+	})
+	mp = nil
+	select {
+	case mp = <-client.sendQueues[MessagePriority_UserSpace]:
+	default:
+	}
+	if assert.NotNil(t, mp) {
+		assert.True(t, mp.RawPayload)
+		assert.Equal(t, payload, mp.MsgBody)
+	}
+
+	// The wrapped payload is still supported
+	HandleServerToClientUserMessage(MessageContext{
+		MsgType:    channeldpb.MessageType_USER_SPACE_START,
+		Msg:        &channeldpb.ServerForwardMessage{ClientConnId: uint32(client.Id()), Payload: payload},
+		Broadcast:  uint32(channeldpb.BroadcastType_SINGLE_CONNECTION),
+		ChannelId:  uint32(ch.Id()),
+		Connection: server,
+		Channel:    ch,
+	})
+	mp = nil
+	select {
+	case mp = <-client.sendQueues[MessagePriority_UserSpace]:
+	default:
+	}
+	if assert.NotNil(t, mp) {
+		assert.False(t, mp.RawPayload)
+		forwardMsg := &channeldpb.ServerForwardMessage{}
+		assert.NoError(t, proto.Unmarshal(mp.MsgBody, forwardMsg))
+		assert.Equal(t, payload, forwardMsg.Payload)
+	}
+}
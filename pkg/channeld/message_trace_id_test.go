@@ -0,0 +1,56 @@
+package channeld
+
+import (
+	"testing"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceIdPropagation(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	// Stop the channel.Tick() goroutine
+	ch.removing = 1
+	client := newLoopbackConnection(channeldpb.CompressionType_NO_COMPRESSION)
+	client.sender = &queuedMessagePackSender{}
+	client.id = 1000
+	allConnections.Store(client.id, client)
+	defer allConnections.Delete(client.id)
+
+	// Forwarded from the server to the client
+	HandleServerToClientUserMessage(MessageContext{
+		MsgType:    channeldpb.MessageType_USER_SPACE_START,
+		Msg:        &channeldpb.ServerForwardMessage{ClientConnId: uint32(client.id), Payload: []byte("hello")},
+		Broadcast:  uint32(channeldpb.BroadcastType_SINGLE_CONNECTION),
+		TraceId:    "trace-1",
+		Connection: server,
+		Channel:    ch,
+	})
+	if mp := client.nextMessageToSend(); assert.NotNil(t, mp) {
+		assert.Equal(t, "trace-1", mp.TraceId)
+	}
+
+	// The raw payload
+	client.Send(MessageContext{
+		MsgType:    channeldpb.MessageType_USER_SPACE_START,
+		Msg:        &channeldpb.ServerForwardMessage{Payload: []byte("hello")},
+		TraceId:    "trace-2",
+		rawPayload: true,
+	})
+	if mp := client.nextMessageToSend(); assert.NotNil(t, mp) {
+		assert.True(t, mp.RawPayload)
+		assert.Equal(t, "trace-2", mp.TraceId)
+	}
+
+	// Preserved in all the chunks
+	chunks := SplitMessagePack(&channeldpb.MessagePack{MsgBody: make([]byte, 10), TraceId: "trace-3"}, 4)
+	assert.Len(t, chunks, 3)
+	for _, chunk := range chunks {
+		assert.Equal(t, "trace-3", chunk.TraceId)
+	}
+}
@@ -59,6 +59,14 @@ var fragmentedPacketCount = prometheus.NewCounterVec(
 	[]string{"connType"},
 )
 
+var messageChunked = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "messages_chunked",
+		Help: "Messages split into chunks as they don't fit in a packet",
+	},
+	[]string{"connType"},
+)
+
 var combinedPacketCount = prometheus.NewCounterVec(
 	prometheus.CounterOpts{
 		Name: "packets_comb",
@@ -91,6 +99,14 @@ var connectionNum = prometheus.NewGaugeVec(
 	[]string{"type"},
 )
 
+var connectionStateNum = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "connection_state_num",
+		Help: "Number of connections in each state",
+	},
+	[]string{"type", "state"},
+)
+
 var channelNum = prometheus.NewGaugeVec(
 	prometheus.GaugeOpts{
 		Name: "channel_num",
@@ -99,6 +115,201 @@ var channelNum = prometheus.NewGaugeVec(
 	[]string{"type"},
 )
 
+var tenantChannelNum = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "tenant_channel_num",
+		Help: "Number of channels in each tenant",
+	},
+	[]string{"tenant"},
+)
+
+var tenantConnectionNum = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "tenant_connection_num",
+		Help: "Number of authenticated connections in each tenant",
+	},
+	[]string{"tenant"},
+)
+
+var tenantQuotaExceeded = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "tenant_quota_exceeded",
+		Help: "Requests rejected or packets dropped as the tenants' quotas are hit",
+	},
+	[]string{"tenant", "quota"},
+)
+
+var shadowMergeDivergences = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "shadow_merge_divergences",
+		Help: "Divergences between the channel data and its shadow copy merged in an alternative way",
+	},
+	[]string{"channelType"},
+)
+
+var idleConnectionsClosed = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "idle_connections_closed",
+		Help: "Client connections closed as they didn't reply to the idle ping",
+	},
+)
+
+var idleChannelsRemoved = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "idle_channels_removed",
+		Help: "Channels removed as they had no subscriber and no owner activity",
+	},
+	[]string{"channelType"},
+)
+
+var channelDataMigrated = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "channel_data_migrated",
+		Help: "Channel data loaded in an older schema version and migrated",
+	},
+	[]string{"channelType"},
+)
+
+var deadReckoningExtrapolated = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "dead_reckoning_extrapolated",
+		Help: "Entity position updates left out of the fan-outs as the subscribers can extrapolate them",
+	},
+)
+
+var fanOutEntitiesDeferred = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "fan_out_entities_deferred",
+		Help: "Entities deferred to the following fan-outs as the subscriber's fan-out budget is exceeded",
+	},
+	[]string{"channelType"},
+)
+
+var channelDataTransactions = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "channel_data_transactions",
+		Help: "Channel data transactions applied or rejected",
+	},
+	[]string{"channelType", "result"},
+)
+
+var chatFloodDropped = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "chat_flood_dropped",
+		Help: "User-space broadcasts dropped by the chat flood protection",
+	},
+	[]string{"channelType", "reason"},
+)
+
+var contentFiltered = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "content_filtered",
+		Help: "User-space broadcasts rejected or rewritten by the content filter",
+	},
+	[]string{"channelType", "result"},
+)
+
+var channelMemoryBytes = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "channel_memory_bytes",
+		Help: "Estimated memory held by the channels",
+	},
+	[]string{"type"},
+)
+
+var connectionMemoryBytes = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "connection_memory_bytes",
+		Help: "Estimated memory held by the connections",
+	},
+	[]string{"connType"},
+)
+
+var channelDataCoalesced = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "channel_data_coalesced",
+		Help: "Channel data update messages coalesced due to the buffer limit",
+	},
+)
+
+var fanOutFullResync = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "fan_out_full_resync",
+		Help: "Fan-outs that send the full channel data as the connection missed the coalesced updates",
+	},
+)
+
+var fanOutDuplicateSuppressed = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "fan_out_duplicate_suppressed",
+		Help: "Delta fan-outs not sent as they are identical to the previous fan-out to the subscriber",
+	},
+	[]string{"channelType"},
+)
+
+var channelDataResyncRequested = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "channel_data_resync_requested",
+		Help: "Full resyncs requested by the subscribers that detected the divergence of the channel data",
+	},
+	[]string{"type"},
+)
+
+var slowHandling = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "slow_handling",
+		Help: "Message handlings and fan-outs that exceeded the deadline of the channel type",
+	},
+	[]string{"type", "stage"},
+)
+
+var channelPanics = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "channel_panics",
+		Help: "Panics recovered in the channels' goroutines",
+	},
+	[]string{"type"},
+)
+
+var slowSubscriberDowngraded = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "slow_subscriber_downgraded",
+		Help: "Fan-outs downgraded as the subscribers' send queues stayed full",
+	},
+	[]string{"type", "mode"},
+)
+
+var channelInboxDropped = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "channel_inbox_dropped",
+		Help: "Messages dropped as the inbox of the channel is full, or the sender's quota is exceeded",
+	},
+	[]string{"type", "reason"},
+)
+
+var channelInboxDeferred = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "channel_inbox_deferred",
+		Help: "Messages deferred to the next tick of the channel, counted once per tick",
+	},
+	[]string{"type"},
+)
+
+var channelIdQuarantined = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "channel_id_quarantined",
+		Help: "Number of removed channel ids that can't be reused yet",
+	},
+)
+
+var channelIdExhausted = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "channel_id_exhausted",
+		Help: "Failed channel id allocations",
+	},
+	[]string{"idRange"},
+)
+
 var channelTickDuration = prometheus.NewGaugeVec(
 	prometheus.GaugeOpts{
 		Name: "channel_tick_duration",
@@ -106,6 +317,38 @@ var channelTickDuration = prometheus.NewGaugeVec(
 	},
 	[]string{"type"},
 )
+var channelTickLagged = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "channel_tick_lagged",
+		Help: "Times the channels' ticks lagged behind the tick interval for consecutive ticks",
+	},
+	[]string{"type"},
+)
+
+var channelCpuTime = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "channel_cpu_seconds",
+		Help: "CPU time spent in the channels' ticks",
+	},
+	[]string{"type"},
+)
+
+var fanOutLatency = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "fan_out_latency_ms",
+		Help:    "Time from the arrival of the channel data update to writing the fan-out to the subscriber",
+		Buckets: []float64{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000, 2000},
+	},
+	[]string{"channelType"},
+)
+
+var analyticsEventsDropped = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "analytics_events_dropped",
+		Help: "Analytics events dropped as the queue is full or the sink failed",
+	},
+)
+
 var connectionClosed = prometheus.NewCounterVec(
 	prometheus.CounterOpts{
 		Name: "connection_closed",
@@ -122,11 +365,42 @@ func InitMetrics() {
 	prometheus.MustRegister(packetSent)
 	prometheus.MustRegister(packetDropped)
 	prometheus.MustRegister(fragmentedPacketCount)
+	prometheus.MustRegister(messageChunked)
 	prometheus.MustRegister(combinedPacketCount)
 	prometheus.MustRegister(bytesReceived)
 	prometheus.MustRegister(bytesSent)
 	prometheus.MustRegister(connectionNum)
+	prometheus.MustRegister(connectionStateNum)
 	prometheus.MustRegister(channelNum)
+	prometheus.MustRegister(tenantChannelNum)
+	prometheus.MustRegister(tenantConnectionNum)
+	prometheus.MustRegister(tenantQuotaExceeded)
+	prometheus.MustRegister(contentFiltered)
+	prometheus.MustRegister(chatFloodDropped)
+	prometheus.MustRegister(channelDataTransactions)
+	prometheus.MustRegister(fanOutEntitiesDeferred)
+	prometheus.MustRegister(deadReckoningExtrapolated)
+	prometheus.MustRegister(channelDataMigrated)
+	prometheus.MustRegister(shadowMergeDivergences)
+	prometheus.MustRegister(idleConnectionsClosed)
+	prometheus.MustRegister(idleChannelsRemoved)
+	prometheus.MustRegister(channelIdQuarantined)
+	prometheus.MustRegister(channelMemoryBytes)
+	prometheus.MustRegister(channelDataCoalesced)
+	prometheus.MustRegister(fanOutFullResync)
+	prometheus.MustRegister(fanOutDuplicateSuppressed)
+	prometheus.MustRegister(channelDataResyncRequested)
+	prometheus.MustRegister(slowHandling)
+	prometheus.MustRegister(channelPanics)
+	prometheus.MustRegister(channelInboxDropped)
+	prometheus.MustRegister(slowSubscriberDowngraded)
+	prometheus.MustRegister(channelInboxDeferred)
+	prometheus.MustRegister(connectionMemoryBytes)
+	prometheus.MustRegister(channelIdExhausted)
 	prometheus.MustRegister(channelTickDuration)
+	prometheus.MustRegister(channelTickLagged)
+	prometheus.MustRegister(channelCpuTime)
+	prometheus.MustRegister(fanOutLatency)
+	prometheus.MustRegister(analyticsEventsDropped)
 	prometheus.MustRegister(connectionClosed)
 }
@@ -0,0 +1,235 @@
+package channeld
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.uber.org/zap"
+)
+
+const (
+	// Only the Prometheus metrics on the admin port. See NewAdminHandler().
+	MetricsExporter_None = ""
+	// Pushes the metrics to StatsD. The labels are appended to the metric name, e.g. channeld.logs.info
+	MetricsExporter_Statsd = "statsd"
+	// Pushes the metrics to DogStatsD (Datadog). The labels are sent as the tags, e.g. channeld.logs:1|c|#level:info
+	MetricsExporter_DogStatsd = "dogstatsd"
+)
+
+const (
+	DefaultMetricsExportIntervalMs = 10000
+	// Keeps the datagram below the typical MTU
+	maxStatsdPacketSize = 1432
+)
+
+// Pushes the metrics gathered from the Prometheus registry to a backend that doesn't scrape the admin port.
+// The Prometheus metrics are always available regardless of the exporter.
+type MetricsExporter interface {
+	Export(families []*dto.MetricFamily) error
+}
+
+// Creates the exporter of GlobalSettingsType.MetricsExporter, and pushes the metrics every MetricsExportIntervalMs.
+// Does nothing if no exporter is configured.
+func StartMetricsExporter() error {
+	var exporter MetricsExporter
+	switch GlobalSettings.MetricsExporter {
+	case MetricsExporter_None:
+		return nil
+	case MetricsExporter_Statsd, MetricsExporter_DogStatsd:
+		statsdExporter, err := NewStatsdExporter(GlobalSettings.StatsdAddress, GlobalSettings.StatsdPrefix, GlobalSettings.MetricsExporter == MetricsExporter_DogStatsd)
+		if err != nil {
+			return err
+		}
+		exporter = statsdExporter
+	default:
+		return fmt.Errorf("unknown metrics exporter: %s", GlobalSettings.MetricsExporter)
+	}
+
+	interval := time.Duration(GlobalSettings.MetricsExportIntervalMs) * time.Millisecond
+	if interval <= 0 {
+		interval = DefaultMetricsExportIntervalMs * time.Millisecond
+	}
+	rootLogger.Info("start exporting metrics",
+		zap.String("exporter", GlobalSettings.MetricsExporter),
+		zap.String("address", GlobalSettings.StatsdAddress),
+		zap.Duration("interval", interval),
+	)
+	go runMetricsExporter(exporter, prometheus.DefaultGatherer, interval)
+	return nil
+}
+
+func runMetricsExporter(exporter MetricsExporter, gatherer prometheus.Gatherer, interval time.Duration) {
+	for {
+		time.Sleep(interval)
+		families, err := gatherer.Gather()
+		if err != nil {
+			rootLogger.Warn("failed to gather metrics", zap.Error(err))
+		}
+		if err := exporter.Export(families); err != nil {
+			rootLogger.Warn("failed to export metrics", zap.Error(err))
+		}
+	}
+}
+
+// Exports the metrics in the StatsD line protocol over UDP. The Prometheus counters are sent as the deltas since the last export.
+// The histograms and the summaries are sent as the counters of .count and .sum.
+// Should only be used in one goroutine.
+type StatsdExporter struct {
+	conn   net.Conn
+	prefix string
+	// If true, the labels are sent as the DogStatsD tags. Otherwise, they are appended to the metric name.
+	tags bool
+	// The values of the counters in the last export, by the metric line without the value
+	lastCounters map[string]float64
+	buf          bytes.Buffer
+}
+
+func NewStatsdExporter(address string, prefix string, tags bool) (*StatsdExporter, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	return &StatsdExporter{
+		conn:         conn,
+		prefix:       prefix,
+		tags:         tags,
+		lastCounters: make(map[string]float64),
+	}, nil
+}
+
+func (e *StatsdExporter) Close() error {
+	return e.conn.Close()
+}
+
+func (e *StatsdExporter) Export(families []*dto.MetricFamily) error {
+	e.buf.Reset()
+	for _, family := range families {
+		for _, m := range family.Metric {
+			name := family.GetName()
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				e.writeCounter(name, m.Label, m.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				e.writeLine(name, m.Label, m.GetGauge().GetValue(), "g")
+			case dto.MetricType_UNTYPED:
+				e.writeLine(name, m.Label, m.GetUntyped().GetValue(), "g")
+			case dto.MetricType_HISTOGRAM:
+				e.writeCounter(name+".count", m.Label, float64(m.GetHistogram().GetSampleCount()))
+				e.writeCounter(name+".sum", m.Label, m.GetHistogram().GetSampleSum())
+			case dto.MetricType_SUMMARY:
+				e.writeCounter(name+".count", m.Label, float64(m.GetSummary().GetSampleCount()))
+				e.writeCounter(name+".sum", m.Label, m.GetSummary().GetSampleSum())
+			}
+			if err := e.flushIfFull(); err != nil {
+				return err
+			}
+		}
+	}
+	return e.flush()
+}
+
+func (e *StatsdExporter) writeCounter(name string, labels []*dto.LabelPair, value float64) {
+	key := e.metricKey(name, labels)
+	delta := value - e.lastCounters[key]
+	e.lastCounters[key] = value
+	if delta < 0 {
+		// The counter has been reset
+		delta = value
+	}
+	if delta == 0 {
+		return
+	}
+	e.writeLine(name, labels, delta, "c")
+}
+
+func (e *StatsdExporter) writeLine(name string, labels []*dto.LabelPair, value float64, statsdType string) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return
+	}
+	e.buf.WriteString(e.metricName(name, labels))
+	e.buf.WriteByte(':')
+	e.buf.WriteString(strconv.FormatFloat(value, 'f', -1, 64))
+	e.buf.WriteByte('|')
+	e.buf.WriteString(statsdType)
+	if e.tags && len(labels) > 0 {
+		e.buf.WriteString("|#")
+		e.buf.WriteString(e.metricTags(labels))
+	}
+	e.buf.WriteByte('\n')
+}
+
+func (e *StatsdExporter) metricName(name string, labels []*dto.LabelPair) string {
+	var sb strings.Builder
+	sb.WriteString(e.prefix)
+	sb.WriteString(name)
+	if !e.tags {
+		// The label pairs gathered from the registry are sorted by the name
+		for _, label := range labels {
+			if label.GetValue() == "" {
+				continue
+			}
+			sb.WriteByte('.')
+			sb.WriteString(sanitizeStatsdName(label.GetValue()))
+		}
+	}
+	return sb.String()
+}
+
+func (e *StatsdExporter) metricTags(labels []*dto.LabelPair) string {
+	tags := make([]string, 0, len(labels))
+	for _, label := range labels {
+		tags = append(tags, label.GetName()+":"+sanitizeStatsdName(label.GetValue()))
+	}
+	return strings.Join(tags, ",")
+}
+
+func (e *StatsdExporter) metricKey(name string, labels []*dto.LabelPair) string {
+	return e.metricName(name, labels) + "|#" + e.metricTags(labels)
+}
+
+// Sends the lines in the buffer that fit in a datagram, and keeps the rest.
+func (e *StatsdExporter) flushIfFull() error {
+	for e.buf.Len() > maxStatsdPacketSize {
+		data := e.buf.Bytes()
+		end := bytes.LastIndexByte(data[:maxStatsdPacketSize], '\n')
+		if end < 0 {
+			// A single line is longer than the datagram. Send it anyway.
+			end = bytes.IndexByte(data, '\n')
+		}
+		if _, err := e.conn.Write(data[:end]); err != nil {
+			return err
+		}
+		e.buf.Next(end + 1)
+	}
+	return nil
+}
+
+func (e *StatsdExporter) flush() error {
+	if err := e.flushIfFull(); err != nil {
+		return err
+	}
+	if e.buf.Len() == 0 {
+		return nil
+	}
+	_, err := e.conn.Write(bytes.TrimSuffix(e.buf.Bytes(), []byte{'\n'}))
+	e.buf.Reset()
+	return err
+}
+
+// Replaces the characters that have special meanings in the StatsD line protocol.
+func sanitizeStatsdName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '|', '@', '#', ',', '\n', ' ':
+			return '_'
+		}
+		return r
+	}, s)
+}
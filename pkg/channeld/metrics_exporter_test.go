@@ -0,0 +1,84 @@
+package channeld
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsdExporter(t *testing.T) {
+	agent, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer agent.Close()
+	receive := func() []string {
+		var lines []string
+		buf := make([]byte, 65536)
+		for {
+			agent.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+			n, _, err := agent.ReadFrom(buf)
+			if err != nil {
+				return lines
+			}
+			assert.LessOrEqual(t, n, maxStatsdPacketSize)
+			lines = append(lines, strings.Split(string(buf[:n]), "\n")...)
+		}
+	}
+
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "msgs"}, []string{"connType"})
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "conns"})
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "latency"})
+	registry.MustRegister(counter, gauge, histogram)
+	export := func(e *StatsdExporter) {
+		families, err := registry.Gather()
+		assert.NoError(t, err)
+		assert.NoError(t, e.Export(families))
+	}
+
+	dogstatsd, err := NewStatsdExporter(agent.LocalAddr().String(), "channeld.", true)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer dogstatsd.Close()
+	counter.WithLabelValues("CLIENT").Add(3)
+	gauge.Set(5)
+	histogram.Observe(2)
+	export(dogstatsd)
+	assert.ElementsMatch(t, []string{
+		"channeld.msgs:3|c|#connType:CLIENT",
+		"channeld.conns:5|g",
+		"channeld.latency.count:1|c",
+		"channeld.latency.sum:2|c",
+	}, receive())
+
+	// The counters are sent as the deltas
+	counter.WithLabelValues("CLIENT").Add(2)
+	export(dogstatsd)
+	assert.ElementsMatch(t, []string{
+		"channeld.msgs:2|c|#connType:CLIENT",
+		"channeld.conns:5|g",
+	}, receive())
+
+	// The labels are appended to the name in plain StatsD
+	statsd, err := NewStatsdExporter(agent.LocalAddr().String(), "", false)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer statsd.Close()
+	export(statsd)
+	assert.Contains(t, receive(), "msgs.CLIENT:5|c")
+
+	// Split into the datagrams that fit in the MTU
+	for i := 0; i < 100; i++ {
+		counter.WithLabelValues(strings.Repeat("x", i)).Inc()
+	}
+	export(statsd)
+	// The counters and the gauge
+	assert.Len(t, receive(), 101)
+}
@@ -0,0 +1,207 @@
+package channeld
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// The bad network conditions emulated on the packets sent to a connection, so the developers can test how the client
+// behaves without the external tools like tc-netem or Clumsy. See Connection.SetNetworkConditions().
+type NetworkConditions struct {
+	// The extra one-way latency of each packet.
+	LatencyMs uint32
+	// The max random deviation of the latency. The packets are never reordered, as the transports are in order.
+	JitterMs uint32
+	// The chance (0-1) to drop a packet. The whole packet is dropped, so the stream of the reliable transports is still valid.
+	LossRate float64
+	// The max bandwidth in kilobits per second. 0 means unlimited.
+	BandwidthKbps uint32
+}
+
+var ErrInvalidNetworkConditions = errors.New("invalid network conditions")
+
+func (nc *NetworkConditions) validate() error {
+	if nc.LossRate < 0 || nc.LossRate > 1 {
+		return ErrInvalidNetworkConditions
+	}
+	return nil
+}
+
+func (nc *NetworkConditions) isZero() bool {
+	return *nc == NetworkConditions{}
+}
+
+type delayedPacket struct {
+	due   time.Time
+	bytes *[]byte
+}
+
+// The state of the emulation. Only accessed in the flush goroutine.
+type netemState struct {
+	// The packets that are delayed, in the order of sending.
+	pending []delayedPacket
+	// When the emulated link finishes transmitting the last packet. See NetworkConditions.BandwidthKbps.
+	linkFreeTime time.Time
+	lastDueTime  time.Time
+}
+
+// Applies the conditions to the packets sent to the connection from now on. The zero conditions stop the emulation;
+// the packets that are already delayed are still sent when they are due.
+func (c *Connection) SetNetworkConditions(conditions NetworkConditions) error {
+	if err := conditions.validate(); err != nil {
+		return err
+	}
+	if conditions.isZero() {
+		c.netemConditions.Store((*NetworkConditions)(nil))
+		c.Logger().Info("stopped emulating network conditions")
+		return nil
+	}
+	c.netemConditions.Store(&conditions)
+	c.Logger().Info("started emulating network conditions",
+		zap.Uint32("latencyMs", conditions.LatencyMs),
+		zap.Uint32("jitterMs", conditions.JitterMs),
+		zap.Float64("lossRate", conditions.LossRate),
+		zap.Uint32("bandwidthKbps", conditions.BandwidthKbps),
+	)
+	return nil
+}
+
+// Returns the zero conditions if the connection is not emulating.
+func (c *Connection) NetworkConditions() NetworkConditions {
+	if conditions, _ := c.netemConditions.Load().(*NetworkConditions); conditions != nil {
+		return *conditions
+	}
+	return NetworkConditions{}
+}
+
+// Returns true if the packet is dropped or delayed by the emulation, and false if it should be written right away.
+// Should NOT be called outside the flush goroutine!
+func (c *Connection) emulatePacket(bytes []byte) bool {
+	conditions, _ := c.netemConditions.Load().(*NetworkConditions)
+	if conditions == nil && len(c.netem.pending) == 0 {
+		return false
+	}
+
+	now := time.Now()
+	due := now
+	if conditions != nil {
+		if conditions.LossRate > 0 && rand.Float64() < conditions.LossRate {
+			c.Logger().VeryVerbose("[netem] dropped packet", zap.Int("size", len(bytes)))
+			return true
+		}
+		if conditions.BandwidthKbps > 0 {
+			if c.netem.linkFreeTime.Before(now) {
+				c.netem.linkFreeTime = now
+			}
+			c.netem.linkFreeTime = c.netem.linkFreeTime.Add(time.Duration(len(bytes)) * 8 * time.Millisecond / time.Duration(conditions.BandwidthKbps))
+			due = c.netem.linkFreeTime
+		}
+		latency := time.Duration(conditions.LatencyMs) * time.Millisecond
+		if conditions.JitterMs > 0 {
+			latency += time.Duration(rand.Int63n(int64(2*conditions.JitterMs)+1)-int64(conditions.JitterMs)) * time.Millisecond
+		}
+		if latency > 0 {
+			due = due.Add(latency)
+		}
+	}
+	// Keep the order of the packets
+	if due.Before(c.netem.lastDueTime) {
+		due = c.netem.lastDueTime
+	}
+	c.netem.lastDueTime = due
+
+	if !due.After(now) && len(c.netem.pending) == 0 {
+		return false
+	}
+	bufPtr := getBuffer(len(bytes))
+	*bufPtr = append((*bufPtr)[:0], bytes...)
+	c.netem.pending = append(c.netem.pending, delayedPacket{due: due, bytes: bufPtr})
+	return true
+}
+
+// Writes the delayed packets that are due. Should NOT be called outside the flush goroutine!
+func (c *Connection) releaseDelayedPackets() {
+	now := time.Now()
+	for len(c.netem.pending) > 0 && !c.netem.pending[0].due.After(now) {
+		packet := c.netem.pending[0]
+		c.netem.pending[0] = delayedPacket{}
+		c.netem.pending = c.netem.pending[1:]
+
+		len, err := c.conn.Write(*packet.bytes)
+		putBuffer(packet.bytes)
+		if err != nil {
+			c.Logger().Error("error writing delayed packet", zap.Error(err))
+		}
+		packetSent.WithLabelValues(c.connectionType.String()).Inc()
+		bytesSent.WithLabelValues(c.connectionType.String()).Add(float64(len))
+	}
+}
+
+// The admin API that emulates the bad network conditions on the packets sent to a connection, e.g.
+//
+//	/debug/netem?connId=1                                      returns the current conditions of connection 1
+//	/debug/netem?connId=1&latencyMs=100&jitterMs=20&lossRate=0.05&bandwidthKbps=512
+//	                                                           replaces the conditions (the omitted ones are zero)
+//	/debug/netem?connId=1&clear=1                              stops the emulation
+//
+// Only available if GlobalSettings.EnableNetem is true. Changing the conditions requires GlobalSettings.AdminAuthToken to be
+// set. Responds the conditions as JSON.
+func HandleNetworkConditions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	connId, err := strconv.ParseUint(query.Get("connId"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid connId", http.StatusBadRequest)
+		return
+	}
+	conn := GetConnection(ConnectionId(connId))
+	if conn == nil {
+		http.Error(w, "connection not found", http.StatusNotFound)
+		return
+	}
+
+	set := query.Get("clear") != ""
+	var conditions NetworkConditions
+	for _, param := range []struct {
+		name  string
+		value *uint32
+	}{
+		{"latencyMs", &conditions.LatencyMs},
+		{"jitterMs", &conditions.JitterMs},
+		{"bandwidthKbps", &conditions.BandwidthKbps},
+	} {
+		if str := query.Get(param.name); str != "" {
+			value, err := strconv.ParseUint(str, 10, 32)
+			if err != nil {
+				http.Error(w, "invalid "+param.name, http.StatusBadRequest)
+				return
+			}
+			*param.value = uint32(value)
+			set = true
+		}
+	}
+	if str := query.Get("lossRate"); str != "" {
+		if conditions.LossRate, err = strconv.ParseFloat(str, 64); err != nil {
+			http.Error(w, "invalid lossRate", http.StatusBadRequest)
+			return
+		}
+		set = true
+	}
+
+	if set {
+		if !requireAdminToken(w, r) {
+			return
+		}
+		if err := conn.SetNetworkConditions(conditions); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(conn.NetworkConditions())
+}
@@ -0,0 +1,117 @@
+package channeld
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmulateNetworkConditions(t *testing.T) {
+	InitLogs()
+	InitChannels()
+
+	c := newLoopbackConnection(channeldpb.CompressionType_NO_COMPRESSION)
+	conn := c.conn.(*loopbackConn)
+	send := func() {
+		(&queuedMessagePackSender{}).Send(c, MessageContext{
+			MsgType: channeldpb.MessageType_USER_SPACE_START,
+			Msg:     &channeldpb.ServerForwardMessage{Payload: make([]byte, 100)},
+		})
+		c.flush()
+	}
+
+	assert.Error(t, c.SetNetworkConditions(NetworkConditions{LossRate: 2}))
+
+	// Latency
+	assert.NoError(t, c.SetNetworkConditions(NetworkConditions{LatencyMs: 50}))
+	send()
+	assert.Empty(t, conn.packet)
+	assert.Len(t, c.netem.pending, 1)
+	time.Sleep(60 * time.Millisecond)
+	c.flush()
+	assert.NotEmpty(t, conn.packet)
+	assert.Empty(t, c.netem.pending)
+
+	// Loss
+	conn.packet = nil
+	assert.NoError(t, c.SetNetworkConditions(NetworkConditions{LossRate: 1}))
+	send()
+	assert.Empty(t, conn.packet)
+	assert.Empty(t, c.netem.pending)
+
+	// Bandwidth: 8 kbps = 1 byte per millisecond
+	assert.NoError(t, c.SetNetworkConditions(NetworkConditions{BandwidthKbps: 8}))
+	send()
+	send()
+	if assert.Len(t, c.netem.pending, 2) {
+		gap := c.netem.pending[1].due.Sub(c.netem.pending[0].due)
+		assert.InDelta(t, len(*c.netem.pending[1].bytes), gap.Milliseconds(), 1)
+	}
+
+	// The delayed packets are still sent in order after the emulation stops
+	assert.NoError(t, c.SetNetworkConditions(NetworkConditions{}))
+	assert.Equal(t, NetworkConditions{}, c.NetworkConditions())
+	send()
+	assert.Len(t, c.netem.pending, 3)
+	time.Sleep(time.Until(c.netem.lastDueTime) + time.Millisecond)
+	c.flush()
+	assert.Empty(t, c.netem.pending)
+	conn.packet = nil
+	send()
+	assert.NotEmpty(t, conn.packet)
+}
+
+func TestHandleNetworkConditions(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	defer func(token string) {
+		GlobalSettings.AdminAuthToken = token
+	}(GlobalSettings.AdminAuthToken)
+
+	c := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	serve := func(query string) (int, NetworkConditions) {
+		rec := httptest.NewRecorder()
+		HandleNetworkConditions(rec, httptest.NewRequest("GET", "/debug/netem?"+query, nil))
+		var conditions NetworkConditions
+		json.Unmarshal(rec.Body.Bytes(), &conditions)
+		return rec.Code, conditions
+	}
+	connId := "connId=" + strconv.FormatUint(uint64(c.Id()), 10)
+
+	// Can't change the conditions without the auth token
+	GlobalSettings.AdminAuthToken = ""
+	code, _ := serve(connId + "&latencyMs=100")
+	assert.Equal(t, http.StatusForbidden, code)
+	assert.Equal(t, NetworkConditions{}, c.NetworkConditions())
+	code, _ = serve(connId)
+	assert.Equal(t, http.StatusOK, code)
+
+	GlobalSettings.AdminAuthToken = "secret"
+	code, _ = serve("connId=99999")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = serve(connId + "&latencyMs=abc")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = serve(connId + "&lossRate=1.5")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, conditions := serve(connId + "&latencyMs=100&jitterMs=20&lossRate=0.05&bandwidthKbps=512")
+	assert.Equal(t, http.StatusOK, code)
+	expected := NetworkConditions{LatencyMs: 100, JitterMs: 20, LossRate: 0.05, BandwidthKbps: 512}
+	assert.Equal(t, expected, conditions)
+	assert.Equal(t, expected, c.NetworkConditions())
+
+	_, conditions = serve(connId)
+	assert.Equal(t, expected, conditions)
+
+	_, conditions = serve(connId + "&clear=1")
+	assert.Equal(t, NetworkConditions{}, conditions)
+	assert.Equal(t, NetworkConditions{}, c.NetworkConditions())
+}
@@ -0,0 +1,31 @@
+package channeld
+
+import (
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+)
+
+// The auth provider of the OPERATOR connections, separated from the one of the players and servers.
+// Operators are never authenticated without it, even in development mode or with ServerBypassAuth.
+var operatorAuthProvider AuthProvider
+
+func SetOperatorAuthProvider(value AuthProvider) {
+	operatorAuthProvider = value
+}
+
+func isOperator(c ConnectionInChannel) bool {
+	return c != nil && c.GetConnectionType() == channeldpb.ConnectionType_OPERATOR
+}
+
+// Logs the privileged action of the operator to the security log, so the live-ops can be audited.
+func auditOperatorAction(ctx MessageContext, action string, fields ...zap.Field) {
+	fields = append([]zap.Field{
+		zap.String("action", action),
+		zap.Uint32("operatorConnId", uint32(ctx.Connection.Id())),
+		zap.Uint32("channelId", uint32(ctx.Channel.id)),
+	}, fields...)
+	if conn, ok := ctx.Connection.(*Connection); ok {
+		fields = append(fields, zap.String("userId", conn.UserId()))
+	}
+	securityLogger.Info("operator action", fields...)
+}
@@ -0,0 +1,153 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperatorAuth(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+	InitOperatorConnections("../../config/operator_fsm.json")
+	GlobalSettings.Development = true
+	defer func() {
+		GlobalSettings.Development = false
+		SetOperatorAuthProvider(nil)
+	}()
+
+	authCtx := func(c *Connection, lt string) MessageContext {
+		return MessageContext{
+			MsgType:    channeldpb.MessageType_AUTH,
+			Msg:        &channeldpb.AuthMessage{PlayerIdentifierToken: "gm", LoginToken: lt},
+			Connection: c,
+			Channel:    globalChannel,
+		}
+	}
+
+	// Operators are never authenticated without the operator auth provider, even in development mode
+	op1 := addTestConnection(channeldpb.ConnectionType_OPERATOR)
+	handleAuth(authCtx(op1, "secret"))
+	assert.True(t, op1.IsClosing())
+
+	SetOperatorAuthProvider(&FixedPasswordAuthProvider{"secret"})
+	op2 := addTestConnection(channeldpb.ConnectionType_OPERATOR)
+	handleAuth(authCtx(op2, "secret"))
+	assert.Eventually(t, func() bool { return op2.State() == ConnectionState_READY }, time.Second, 10*time.Millisecond)
+}
+
+func TestOperatorPrivileges(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+	InitOperatorConnections("../../config/operator_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		ACLSettings: ACLSettingsType{Sub: ChannelAccessLevel_OwnerOnly, Unsub: ChannelAccessLevel_OwnerOnly},
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	operator := addTestConnection(channeldpb.ConnectionType_OPERATOR)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	// Stop the channel.Tick() goroutine
+	ch.removing = 1
+	ch.InitData(&testpb.TestChannelDataMessage{Text: "a", Num: 1}, nil)
+	client.SubscribeToChannel(ch, nil)
+
+	// Inspect the channel without subscribing
+	handleQueryChannelData(MessageContext{
+		MsgType:    channeldpb.MessageType_QUERY_CHANNEL_DATA,
+		Msg:        &channeldpb.QueryChannelDataMessage{},
+		Connection: operator,
+		Channel:    ch,
+	})
+	assert.IsType(t, &channeldpb.QueryChannelDataResultMessage{}, operator.latestMsg())
+
+	// The client can't unsub others, but the operator can
+	another := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	another.SubscribeToChannel(ch, nil)
+	handleUnsubFromChannel(MessageContext{
+		MsgType:    channeldpb.MessageType_UNSUB_FROM_CHANNEL,
+		Msg:        &channeldpb.UnsubscribedFromChannelMessage{ConnId: uint32(another.Id())},
+		Connection: client,
+		Channel:    ch,
+	})
+	assert.Contains(t, ch.GetAllConnections(), another)
+
+	handleUnsubFromChannel(MessageContext{
+		MsgType:    channeldpb.MessageType_UNSUB_FROM_CHANNEL,
+		Msg:        &channeldpb.UnsubscribedFromChannelMessage{ConnId: uint32(another.Id())},
+		Connection: operator,
+		Channel:    ch,
+	})
+	assert.NotContains(t, ch.GetAllConnections(), another)
+	assert.IsType(t, &channeldpb.UnsubscribedFromChannelResultMessage{}, another.latestMsg())
+
+	// Inject a broadcast
+	HandleServerToClientUserMessage(MessageContext{
+		MsgType:    channeldpb.MessageType_USER_SPACE_START,
+		Msg:        &channeldpb.ServerForwardMessage{Payload: []byte("maintenance in 5 minutes")},
+		Broadcast:  uint32(channeldpb.BroadcastType_ALL),
+		Connection: operator,
+		Channel:    ch,
+	})
+	assert.IsType(t, &channeldpb.ServerForwardMessage{}, client.latestMsg())
+}
+
+func TestOperatorFSM(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+	InitOperatorConnections("../../config/operator_fsm.json")
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	// Stop the channel.Tick() goroutine
+	ch.removing = 1
+
+	operator := addTestConnection(channeldpb.ConnectionType_OPERATOR)
+	operator.OnAuthenticated("gm")
+
+	// The server-only messages are refused
+	for _, msgType := range []channeldpb.MessageType{
+		channeldpb.MessageType_CREATE_CHANNEL,
+		channeldpb.MessageType_REMOVE_CHANNEL,
+		channeldpb.MessageType_SUB_TO_CHANNEL,
+		channeldpb.MessageType_CHANNEL_DATA_UPDATE,
+		channeldpb.MessageType_DISCONNECT,
+	} {
+		operator.receiveMessage(&channeldpb.MessagePack{
+			ChannelId: uint32(ch.id),
+			MsgType:   uint32(msgType),
+			StubId:    1,
+		})
+		if msg, ok := operator.latestMsg().(*channeldpb.ErrorMessage); assert.True(t, ok, msgType.String()) {
+			assert.EqualValues(t, msgType, msg.MsgType)
+			assert.Equal(t, channeldpb.ErrorMessage_MESSAGE_NOT_ALLOWED, msg.Code)
+		}
+	}
+
+	// Inspect, force-unsub and inject are allowed, so no error is replied
+	queueLen := len(operator.testQueue())
+	for _, msgType := range []channeldpb.MessageType{
+		channeldpb.MessageType_QUERY_CHANNEL_DATA,
+		channeldpb.MessageType_UNSUB_FROM_CHANNEL,
+		channeldpb.MessageType_USER_SPACE_START,
+	} {
+		operator.receiveMessage(&channeldpb.MessagePack{
+			ChannelId: uint32(ch.id),
+			MsgType:   uint32(msgType),
+		})
+	}
+	assert.Len(t, operator.testQueue(), queueLen)
+
+	// The servers are not affected
+	server.OnAuthenticated("server")
+	assert.True(t, server.fsm.IsAllowed(uint32(channeldpb.MessageType_CREATE_CHANNEL)))
+}
@@ -0,0 +1,71 @@
+package channeld
+
+import (
+	"sync/atomic"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+)
+
+// The min max packet size that a connection can negotiate, so the chunks still have room for the msgBody.
+// See channeldpb.AuthMessage.MaxPacketSize.
+const MinPacketSize = 512
+
+// Returns the max packet size to use for the connection that requests the size. 0 means MaxPacketSize.
+func negotiateMaxPacketSize(requested uint32) uint32 {
+	if requested == 0 || requested > uint32(MaxPacketSize) {
+		return uint32(MaxPacketSize)
+	}
+	if requested < MinPacketSize {
+		return MinPacketSize
+	}
+	return requested
+}
+
+// The max size of the packet body that the connection sends and receives. MaxPacketSize before authenticated.
+func (c *Connection) MaxPacketSize() int {
+	if size := atomic.LoadUint32(&c.maxPacketSize); size > 0 {
+		return int(size)
+	}
+	return MaxPacketSize
+}
+
+func (c *Connection) setMaxPacketSize(size uint32) {
+	atomic.StoreUint32(&c.maxPacketSize, size)
+}
+
+func (c *Connection) maxChunkSize() int {
+	return c.MaxPacketSize() - chunkOverhead - c.encryptionOverhead()
+}
+
+func (c *Connection) sendMessageTooLarge(channelId uint32, msg *channeldpb.MessageTooLargeMessage) {
+	c.Send(MessageContext{
+		MsgType:   channeldpb.MessageType_MESSAGE_TOO_LARGE,
+		Msg:       msg,
+		ChannelId: channelId,
+	})
+}
+
+// Drops the packet that exceeds the max packet size and notifies the sender, instead of closing the connection.
+// The packet can be larger than the read buffer, so the part that hasn't arrived is discarded in the next reads. See receive().
+// Returns nil if the rest of the buffer has been consumed.
+func (c *Connection) dropOversizedPacket(header PacketHeader, bufPos *int) *channeldpb.Packet {
+	packetDropped.WithLabelValues(c.connectionType.String()).Inc()
+	c.Logger().Warn("dropped the oversized packet",
+		zap.Int("packetSize", header.BodySize),
+		zap.Int("maxPacketSize", c.MaxPacketSize()),
+	)
+	c.sendMessageTooLarge(0, &channeldpb.MessageTooLargeMessage{
+		Size:          uint32(header.BodySize),
+		MaxPacketSize: uint32(c.MaxPacketSize()),
+	})
+
+	fullSize := header.FullSize()
+	if available := c.readPos - *bufPos; available < fullSize {
+		c.discardBytes = fullSize - available
+		*bufPos = c.readPos
+		return nil
+	}
+	*bufPos += fullSize
+	return &channeldpb.Packet{}
+}
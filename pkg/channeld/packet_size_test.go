@@ -0,0 +1,131 @@
+package channeld
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNegotiateMaxPacketSize(t *testing.T) {
+	assert.EqualValues(t, MaxPacketSize, negotiateMaxPacketSize(0))
+	assert.EqualValues(t, MaxPacketSize, negotiateMaxPacketSize(1<<20))
+	assert.EqualValues(t, MinPacketSize, negotiateMaxPacketSize(1))
+	assert.EqualValues(t, 1200, negotiateMaxPacketSize(1200))
+}
+
+// Returns the scripted bytes in each Read()
+type scriptedConn struct {
+	net.Conn
+	reads [][]byte
+}
+
+func (c *scriptedConn) Read(b []byte) (n int, err error) {
+	if len(c.reads) == 0 {
+		return 0, io.EOF
+	}
+	n = copy(b, c.reads[0])
+	c.reads = c.reads[1:]
+	return n, nil
+}
+
+func v2Header(flags byte, size int) []byte {
+	header := make([]byte, MaxPacketHeaderSize)
+	copy(header, []byte{67, 86, flags})
+	return header[:3+binary.PutUvarint(header[3:], uint64(size))]
+}
+
+func TestDropOversizedPacket(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	c := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	c.setMaxPacketSize(1000)
+
+	// The v2 packet that is larger than the read buffer, followed by a valid packet
+	oversizedBody := make([]byte, MaxPacketSize*2)
+	oversized := v2Header(WireFlag_Batched, len(oversizedBody))
+	oversized = append(oversized, oversizedBody...)
+	validBody, _ := proto.Marshal(&channeldpb.MessagePack{MsgType: uint32(channeldpb.MessageType_PING)})
+	valid := v2Header(0, len(validBody))
+	valid = append(valid, validBody...)
+
+	stream := append(oversized, valid...)
+	conn := &scriptedConn{}
+	for len(stream) > 0 {
+		n := MaxPacketSize / 2
+		if n > len(stream) {
+			n = len(stream)
+		}
+		conn.reads = append(conn.reads, stream[:n])
+		stream = stream[n:]
+	}
+	c.conn = conn
+
+	for len(conn.reads) > 0 {
+		c.receive()
+		assert.False(t, c.IsClosing())
+	}
+	assert.Equal(t, 0, c.readPos)
+	assert.Equal(t, 0, c.discardBytes)
+	if assert.Len(t, c.testQueue(), 1) {
+		msg := c.latestMsg().(*channeldpb.MessageTooLargeMessage)
+		assert.EqualValues(t, len(oversizedBody), msg.Size)
+		assert.EqualValues(t, 1000, msg.MaxPacketSize)
+		assert.Zero(t, msg.MsgType)
+	}
+
+	// Smaller than MaxPacketSize, but larger than the negotiated size
+	c.conn = &scriptedConn{reads: [][]byte{append(v2Header(WireFlag_Batched, 1001), make([]byte, 1001)...)}}
+	c.receive()
+	assert.False(t, c.IsClosing())
+	assert.Equal(t, 0, c.readPos)
+	assert.Len(t, c.testQueue(), 2)
+}
+
+func TestMessageTooLargeForRecipient(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	sender := addTestConnection(channeldpb.ConnectionType_SERVER)
+	ctx := MessageContext{
+		MsgType:    channeldpb.MessageType_USER_SPACE_START,
+		Msg:        &channeldpb.ServerForwardMessage{Payload: make([]byte, 2000)},
+		ChannelId:  1,
+		StubId:     5,
+		Connection: sender,
+		rawPayload: true,
+	}
+
+	recipient := newLoopbackConnection(channeldpb.CompressionType_NO_COMPRESSION)
+	recipient.id = 2
+	recipient.setMaxPacketSize(1000)
+	// Can't receive the chunks
+	recipient.setProtocolVersion(chunkProtocolVersion - 1)
+	(&queuedMessagePackSender{}).Send(recipient, ctx)
+	assert.Equal(t, 0, recipient.sendQueueLen())
+	if msg, ok := sender.latestMsg().(*channeldpb.MessageTooLargeMessage); assert.True(t, ok) {
+		assert.EqualValues(t, 2000, msg.Size)
+		assert.EqualValues(t, 1000, msg.MaxPacketSize)
+		assert.EqualValues(t, channeldpb.MessageType_USER_SPACE_START, msg.MsgType)
+		assert.EqualValues(t, 5, msg.StubId)
+		assert.EqualValues(t, 2, msg.RecipientConnId)
+	}
+
+	// Split into the chunks of the negotiated size
+	recipient.setProtocolVersion(ProtocolVersion)
+	(&queuedMessagePackSender{}).Send(recipient, ctx)
+	assert.Equal(t, 3, recipient.sendQueueLen())
+	for recipient.sendQueueLen() > 0 {
+		recipient.flush()
+		header, _, _ := ReadPacketHeader(recipient.conn.(*loopbackConn).packet)
+		assert.LessOrEqual(t, header.BodySize, 1000)
+	}
+	assert.Len(t, sender.testQueue(), 1)
+}
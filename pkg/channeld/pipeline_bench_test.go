@@ -0,0 +1,242 @@
+package channeld
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// The benchmarks of the data pipeline, from decoding the packet to fanning out the channel data.
+// Run by cmd/benchgate, which compares the results against config/benchmark_baseline.json.
+
+// Silences the logs during the benchmark, as writing them costs more than the pipeline itself.
+func initBenchmarkLogs(b *testing.B) {
+	InitLogs()
+	logger := rootLogger
+	rootLogger = &Logger{zap.NewNop()}
+	b.Cleanup(func() { rootLogger = logger })
+}
+
+// Stops the tick of the GLOBAL channel, which samples the memory usage of all the connections in the background.
+func initBenchmarkChannels() {
+	InitChannels()
+	globalChannel.removing = 1
+}
+
+// Drops the messages, so the benchmarks don't grow the memory like testQueuedMessageSender.
+type discardMessageSender struct {
+	sent int
+}
+
+func (s *discardMessageSender) Send(c *Connection, ctx MessageContext) {
+	s.sent++
+}
+
+func addBenchmarkConnection(t channeldpb.ConnectionType) (*Connection, *discardMessageSender) {
+	c := addTestConnection(t)
+	sender := &discardMessageSender{}
+	c.sender = sender
+	c.OnAuthenticated("")
+	return c, sender
+}
+
+// Creates the channel whose tick goroutine sleeps, so the benchmark can tick it manually.
+func createBenchmarkChannel(b *testing.B, t channeldpb.ChannelType, owner *Connection) *Channel {
+	GlobalSettings.ChannelSettings[t] = ChannelSettingsType{TickIntervalMs: 3600000}
+	b.Cleanup(func() { delete(GlobalSettings.ChannelSettings, t) })
+	ch, err := CreateChannel(t, owner)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { ch.removing = 1 })
+	// Wait for the first tick, after which the channel.Tick() goroutine sleeps for an hour
+	time.Sleep(50 * time.Millisecond)
+	return ch
+}
+
+func BenchmarkPipelinePacketDecode(b *testing.B) {
+	initBenchmarkLogs(b)
+	c := newLoopbackConnection(channeldpb.CompressionType_SNAPPY)
+	for _, mp := range newBenchmarkMessagePacks() {
+		c.sendQueues[0] <- mp
+	}
+	c.flush()
+	packet := c.conn.(*loopbackConn).packet
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		header, _, err := ReadPacketHeader(packet)
+		if err != nil {
+			b.Fatal(err)
+		}
+		body, err := snappy.Decode(nil, packet[header.HeaderSize:header.FullSize()])
+		if err != nil {
+			b.Fatal(err)
+		}
+		var p channeldpb.Packet
+		if err := proto.Unmarshal(body, &p); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// From receiving the user-space message of the client to forwarding it to the channel owner.
+func BenchmarkPipelineHandlerDispatch(b *testing.B) {
+	initBenchmarkLogs(b)
+	initBenchmarkChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	server, serverSender := addBenchmarkConnection(channeldpb.ConnectionType_SERVER)
+	client, _ := addBenchmarkConnection(channeldpb.ConnectionType_CLIENT)
+	ch := createBenchmarkChannel(b, channeldpb.ChannelType_TEST, server)
+	client.SubscribeToChannel(ch, nil)
+	mp := newBenchmarkMessagePacks()[0]
+	mp.ChannelId = uint32(ch.Id())
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		client.receiveMessage(mp)
+		ch.tickMessages(time.Now(), "")
+	}
+	b.StopTimer()
+	if serverSender.sent != b.N {
+		b.Fatalf("forwarded %d messages, expected %d", serverSender.sent, b.N)
+	}
+}
+
+// One update of the channel data fanned out to all the subscribers in a tick.
+func BenchmarkPipelineFanOut(b *testing.B) {
+	for _, num := range []int{1000, 10000} {
+		b.Run(fmt.Sprintf("subscribers=%d", num), func(b *testing.B) {
+			benchmarkFanOut(b, num)
+		})
+	}
+}
+
+func benchmarkFanOut(b *testing.B, subscriberNum int) {
+	initBenchmarkLogs(b)
+	initBenchmarkChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	server, _ := addBenchmarkConnection(channeldpb.ConnectionType_SERVER)
+	ch := createBenchmarkChannel(b, channeldpb.ChannelType_TEST, server)
+	ch.InitData(&testpb.TestChannelDataMessage{Text: "a"}, nil)
+	senders := make([]*discardMessageSender, subscriberNum)
+	for i := range senders {
+		var client *Connection
+		client, senders[i] = addBenchmarkConnection(channeldpb.ConnectionType_CLIENT)
+		client.SubscribeToChannel(ch, &channeldpb.ChannelSubscriptionOptions{FanOutIntervalMs: proto.Uint32(20)})
+	}
+	// The first fan-out sends the whole channel data
+	t := ch.GetTime().AddMs(20)
+	ch.tickData(t)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ch.Data().OnUpdate(&testpb.TestChannelDataMessage{Num: uint32(i)}, t.AddMs(10), server.Id(), nil)
+		t = t.AddMs(20)
+		ch.tickData(t)
+	}
+	b.StopTimer()
+	if senders[subscriberNum-1].sent != b.N+1 {
+		b.Fatalf("fanned out %d updates, expected %d", senders[subscriberNum-1].sent, b.N+1)
+	}
+}
+
+// The channel data of the spatial channels, which only counts the entities.
+type benchmarkSpatialData struct {
+	*testpb.TestChannelDataMessage
+}
+
+func (d benchmarkSpatialData) AddEntity(EntityId, common.Message) error {
+	d.Num++
+	return nil
+}
+
+func (d benchmarkSpatialData) RemoveEntity(EntityId) error {
+	d.Num--
+	return nil
+}
+
+// The channel data of the entity channel.
+type benchmarkEntityData struct {
+	*testpb.TestChannelDataMessage
+}
+
+func (d benchmarkEntityData) MergeTo(msg common.Message, full bool) error {
+	msg.(*testpb.TestChannelDataMessage).Num++
+	return nil
+}
+
+// An entity moves back and forth between two spatial channels of the same server.
+func BenchmarkPipelineSpatialHandover(b *testing.B) {
+	initBenchmarkLogs(b)
+	initBenchmarkChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	RegisterChannelDataType(channeldpb.ChannelType_SPATIAL, &testpb.TestChannelDataMessage{})
+	b.Cleanup(func() { delete(channelDataTypeRegistery, channeldpb.ChannelType_SPATIAL) })
+
+	ctl := &StaticGrid2DSpatialController{
+		GridWidth:  100,
+		GridHeight: 100,
+		GridCols:   2,
+		GridRows:   1,
+		ServerCols: 1,
+		ServerRows: 1,
+	}
+	server, serverSender := addBenchmarkConnection(channeldpb.ConnectionType_SERVER)
+	spatialChannels := make([]*Channel, 2)
+	for i := range spatialChannels {
+		ch := createChannelWithId(GlobalSettings.SpatialChannelIdStart+common.ChannelId(i), channeldpb.ChannelType_SPATIAL, server, "")
+		ch.removing = 1
+		ch.InitData(benchmarkSpatialData{&testpb.TestChannelDataMessage{}}, nil)
+		server.SubscribeToChannel(ch, nil)
+		spatialChannels[i] = ch
+	}
+	b.Cleanup(func() {
+		for _, ch := range spatialChannels {
+			allChannels.Delete(ch.id)
+		}
+	})
+
+	entityId := EntityId(GlobalSettings.EntityChannelIdStart)
+	entityCh := createChannelWithId(common.ChannelId(entityId), channeldpb.ChannelType_ENTITY, server, "")
+	entityCh.removing = 1
+	entityCh.InitData(benchmarkEntityData{&testpb.TestChannelDataMessage{}}, nil)
+	b.Cleanup(func() { allChannels.Delete(entityCh.id) })
+	handoverDataProvider := func(srcChannelId common.ChannelId, dstChannelId common.ChannelId, data interface{}) {
+		*data.(*EntityId) = entityId
+	}
+	// Runs the Execute() callbacks of the handover, as the tick goroutines have stopped.
+	drain := func(ch *Channel) {
+		for len(ch.inMsgQueue) > 0 {
+			cm := <-ch.inMsgQueue
+			cm.handler(cm.ctx)
+		}
+	}
+
+	positions := []common.SpatialInfo{{X: 50, Z: 50}, {X: 150, Z: 50}}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ctl.Notify(positions[i%2], positions[(i+1)%2], handoverDataProvider)
+		for _, ch := range spatialChannels {
+			drain(ch)
+		}
+	}
+	b.StopTimer()
+	if serverSender.sent < b.N {
+		b.Fatalf("sent %d handover messages, expected at least %d", serverSender.sent, b.N)
+	}
+}
@@ -0,0 +1,200 @@
+package channeld
+
+import (
+	"time"
+
+	"github.com/indiest/fmutils"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"google.golang.org/protobuf/proto"
+)
+
+// The presence of the authenticated clients, by the tenant and the user id. Enabled by GlobalSettingsType.EnablePresence.
+// Lives in the GLOBAL channel, and should only be accessed in its goroutine.
+type presenceState struct {
+	users map[string]map[string]*userPresence
+	// The presence changed since the last tick, by the tenant and the user id
+	changes map[string]map[string]*channeldpb.PresenceInfo
+	// The GLOBAL channel subscribers that have received the full presence
+	syncedSubscribers map[ConnectionInChannel]struct{}
+}
+
+type userPresence struct {
+	info *channeldpb.PresenceInfo
+	// The user can have multiple client connections, depending on the DuplicateLoginPolicy.
+	connIds map[ConnectionId]struct{}
+}
+
+func (ch *Channel) presenceState() *presenceState {
+	if ch.presence == nil {
+		ch.presence = &presenceState{
+			users:             make(map[string]map[string]*userPresence),
+			changes:           make(map[string]map[string]*channeldpb.PresenceInfo),
+			syncedSubscribers: make(map[ConnectionInChannel]struct{}),
+		}
+	}
+	return ch.presence
+}
+
+func (p *presenceState) markChanged(tenant string, up *userPresence) {
+	up.info.UpdateTime = time.Now().UnixMilli()
+	changes, exists := p.changes[tenant]
+	if !exists {
+		changes = make(map[string]*channeldpb.PresenceInfo)
+		p.changes[tenant] = changes
+	}
+	changes[up.info.UserId] = proto.Clone(up.info).(*channeldpb.PresenceInfo)
+}
+
+func (p *presenceState) setOnline(tenant string, userId string, connId ConnectionId) {
+	users, exists := p.users[tenant]
+	if !exists {
+		users = make(map[string]*userPresence)
+		p.users[tenant] = users
+	}
+	up, exists := users[userId]
+	if !exists {
+		up = &userPresence{
+			info:    &channeldpb.PresenceInfo{UserId: userId, Online: true},
+			connIds: make(map[ConnectionId]struct{}),
+		}
+		users[userId] = up
+	}
+	up.connIds[connId] = struct{}{}
+	up.info.ConnId = uint32(connId)
+	p.markChanged(tenant, up)
+}
+
+func (p *presenceState) setOffline(tenant string, userId string, connId ConnectionId) {
+	up, exists := p.users[tenant][userId]
+	if !exists {
+		return
+	}
+	delete(up.connIds, connId)
+	if len(up.connIds) > 0 {
+		return
+	}
+	up.info.Online = false
+	up.info.ChannelId = 0
+	up.info.ChannelType = channeldpb.ChannelType_UNKNOWN
+	p.markChanged(tenant, up)
+	delete(p.users[tenant], userId)
+}
+
+// Sets the current channel of the user. If ch is nil, the user leaves the prevChannelId if it's the current channel.
+func (p *presenceState) setChannel(tenant string, userId string, ch *Channel, prevChannelId uint32) {
+	up, exists := p.users[tenant][userId]
+	if !exists {
+		return
+	}
+	if ch != nil {
+		up.info.ChannelId = uint32(ch.id)
+		up.info.ChannelType = ch.channelType
+	} else if up.info.ChannelId == prevChannelId {
+		up.info.ChannelId = 0
+		up.info.ChannelType = channeldpb.ChannelType_UNKNOWN
+	} else {
+		return
+	}
+	p.markChanged(tenant, up)
+}
+
+// Returns the user id of the connection if its presence should be tracked.
+func presenceUserId(c *Connection) (string, bool) {
+	if !GlobalSettings.EnablePresence || c.connectionType != channeldpb.ConnectionType_CLIENT {
+		return "", false
+	}
+	userId := c.UserId()
+	return userId, userId != ""
+}
+
+// Should be called after the client is authenticated.
+func (c *Connection) trackPresence() {
+	userId, ok := presenceUserId(c)
+	if !ok {
+		return
+	}
+	tenant := GetTenant(c)
+	globalChannel.Execute(func(ch *Channel) {
+		ch.presenceState().setOnline(tenant, userId, c.id)
+	})
+	c.AddCloseHandler(func() {
+		globalChannel.Execute(func(ch *Channel) {
+			ch.presenceState().setOffline(tenant, userId, c.id)
+		})
+	})
+}
+
+// Updates the current channel in the presence, when the client subscribes to (or unsubscribes from, if subscribed is false) the channel.
+func (c *Connection) updatePresenceChannel(ch *Channel, subscribed bool) {
+	if ch.channelType == channeldpb.ChannelType_GLOBAL || globalChannel == nil {
+		return
+	}
+	userId, ok := presenceUserId(c)
+	if !ok {
+		return
+	}
+	tenant := GetTenant(c)
+	prevChannelId := uint32(ch.id)
+	if !subscribed {
+		ch = nil
+	}
+	globalChannel.Execute(func(global *Channel) {
+		global.presenceState().setChannel(tenant, userId, ch, prevChannelId)
+	})
+}
+
+func (ch *Channel) sendPresenceUpdate(conn ConnectionInChannel, cs *ChannelSubscription, users map[string]*channeldpb.PresenceInfo, full bool) {
+	msg := &channeldpb.PresenceUpdateMessage{Users: users, Full: full}
+	if len(cs.options.PresenceFieldMasks) > 0 {
+		msg.Users = make(map[string]*channeldpb.PresenceInfo, len(users))
+		for userId, info := range users {
+			info = proto.Clone(info).(*channeldpb.PresenceInfo)
+			fmutils.Filter(info, cs.options.PresenceFieldMasks)
+			msg.Users[userId] = info
+		}
+	}
+	conn.Send(MessageContext{
+		MsgType:   channeldpb.MessageType_PRESENCE_UPDATE,
+		Msg:       msg,
+		ChannelId: uint32(ch.id),
+	})
+}
+
+// Sends the full presence to the new presence subscribers, and the changes to the others.
+// Should only be called in the GLOBAL channel's tick.
+func (ch *Channel) tickPresence() {
+	if !GlobalSettings.EnablePresence {
+		return
+	}
+	p := ch.presenceState()
+
+	ch.connectionsLock.RLock()
+	defer ch.connectionsLock.RUnlock()
+
+	for conn := range p.syncedSubscribers {
+		if cs, exists := ch.subscribedConnections[conn]; !exists || !cs.options.GetPresence() {
+			delete(p.syncedSubscribers, conn)
+		}
+	}
+
+	for conn, cs := range ch.subscribedConnections {
+		if !cs.options.GetPresence() || conn.IsClosing() {
+			continue
+		}
+		tenant := GetTenant(conn)
+		if _, synced := p.syncedSubscribers[conn]; !synced {
+			users := make(map[string]*channeldpb.PresenceInfo, len(p.users[tenant]))
+			for userId, up := range p.users[tenant] {
+				users[userId] = up.info
+			}
+			ch.sendPresenceUpdate(conn, cs, users, true)
+			p.syncedSubscribers[conn] = struct{}{}
+		} else if changes := p.changes[tenant]; len(changes) > 0 {
+			ch.sendPresenceUpdate(conn, cs, changes, false)
+		}
+	}
+
+	if len(p.changes) > 0 {
+		p.changes = make(map[string]map[string]*channeldpb.PresenceInfo)
+	}
+}
@@ -0,0 +1,132 @@
+package channeld
+
+import (
+	"testing"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPresenceFanOut(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.EnablePresence = true
+	defer func() { GlobalSettings.EnablePresence = false }()
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	masked := addTestConnection(channeldpb.ConnectionType_SERVER)
+	other := addTestConnection(channeldpb.ConnectionType_SERVER)
+	other.SetTag(TenantTag, "t1")
+	// The presence lives in the GLOBAL channel, but any channel can fan it out, so the test doesn't race with the GLOBAL tick.
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	ch.removing = 1
+
+	server.SubscribeToChannel(ch, &channeldpb.ChannelSubscriptionOptions{Presence: proto.Bool(true)})
+	masked.SubscribeToChannel(ch, &channeldpb.ChannelSubscriptionOptions{Presence: proto.Bool(true), PresenceFieldMasks: []string{"online"}})
+	other.SubscribeToChannel(ch, &channeldpb.ChannelSubscriptionOptions{Presence: proto.Bool(true)})
+	latestUpdate := func(c *Connection) *channeldpb.PresenceUpdateMessage {
+		msg, _ := c.latestMsg().(*channeldpb.PresenceUpdateMessage)
+		return msg
+	}
+
+	p := ch.presenceState()
+	p.setOnline("", "user1", 10)
+	p.setOnline("", "user2", 11)
+	// The full presence on the first tick
+	ch.tickPresence()
+	assert.True(t, latestUpdate(server).Full)
+	assert.Len(t, latestUpdate(server).Users, 2)
+	assert.True(t, latestUpdate(server).Users["user1"].Online)
+	assert.EqualValues(t, 10, latestUpdate(server).Users["user1"].ConnId)
+	// The field masks are applied
+	assert.True(t, latestUpdate(masked).Users["user1"].Online)
+	assert.Zero(t, latestUpdate(masked).Users["user1"].ConnId)
+	// The presence of the other tenants is invisible
+	assert.True(t, latestUpdate(other).Full)
+	assert.Empty(t, latestUpdate(other).Users)
+
+	// Only the changes are fanned out
+	chatChannel := &Channel{id: 100, channelType: channeldpb.ChannelType_TEST}
+	p.setChannel("", "user1", chatChannel, 0)
+	ch.tickPresence()
+	assert.False(t, latestUpdate(server).Full)
+	assert.Len(t, latestUpdate(server).Users, 1)
+	assert.EqualValues(t, 100, latestUpdate(server).Users["user1"].ChannelId)
+	assert.Equal(t, channeldpb.ChannelType_TEST, latestUpdate(server).Users["user1"].ChannelType)
+	assert.True(t, latestUpdate(other).Full)
+
+	// Leaving another channel doesn't change the current channel
+	p.setChannel("", "user1", nil, 200)
+	sentNum := len(server.testQueue())
+	ch.tickPresence()
+	assert.Equal(t, sentNum, len(server.testQueue()))
+
+	// The user is still online with the second connection
+	p.setOnline("", "user1", 12)
+	p.setOffline("", "user1", 10)
+	ch.tickPresence()
+	assert.True(t, latestUpdate(server).Users["user1"].Online)
+	assert.EqualValues(t, 12, latestUpdate(server).Users["user1"].ConnId)
+
+	p.setOffline("", "user1", 12)
+	ch.tickPresence()
+	assert.False(t, latestUpdate(server).Users["user1"].Online)
+	assert.Zero(t, latestUpdate(server).Users["user1"].ChannelId)
+	assert.NotContains(t, p.users[""], "user1")
+
+	// The new subscriber receives the full presence of the online users
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	client.SubscribeToChannel(ch, &channeldpb.ChannelSubscriptionOptions{Presence: proto.Bool(true)})
+	ch.tickPresence()
+	assert.True(t, latestUpdate(client).Full)
+	assert.Len(t, latestUpdate(client).Users, 1)
+	assert.Contains(t, latestUpdate(client).Users, "user2")
+}
+
+func TestTrackPresence(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.EnablePresence = true
+	defer func() { GlobalSettings.EnablePresence = false }()
+
+	// Reads the presence in the GLOBAL channel's goroutine
+	getPresence := func(userId string) *channeldpb.PresenceInfo {
+		result := make(chan *channeldpb.PresenceInfo)
+		globalChannel.Execute(func(ch *Channel) {
+			if up, exists := ch.presenceState().users[""][userId]; exists {
+				result <- proto.Clone(up.info).(*channeldpb.PresenceInfo)
+			} else {
+				result <- nil
+			}
+		})
+		return <-result
+	}
+
+	c := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	c.SetTag(UserIdTag, "presence_user")
+	c.trackPresence()
+	info := getPresence("presence_user")
+	assert.NotNil(t, info)
+	assert.True(t, info.Online)
+	assert.EqualValues(t, c.Id(), info.ConnId)
+
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, nil)
+	c.SubscribeToChannel(ch, nil)
+	assert.EqualValues(t, ch.id, getPresence("presence_user").ChannelId)
+	c.UnsubscribeFromChannel(ch)
+	assert.Zero(t, getPresence("presence_user").ChannelId)
+
+	// The server connections are not tracked
+	s := addTestConnection(channeldpb.ConnectionType_SERVER)
+	s.SetTag(UserIdTag, "presence_server")
+	s.trackPresence()
+	assert.Nil(t, getPresence("presence_server"))
+
+	c.Close()
+	assert.Nil(t, getPresence("presence_user"))
+}
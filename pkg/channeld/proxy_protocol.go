@@ -0,0 +1,156 @@
+package channeld
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// How long the accepted connection has to send the PROXY header before it's refused.
+const ProxyHeaderTimeout = 5 * time.Second
+
+var ErrInvalidProxyHeader = errors.New("invalid PROXY protocol header")
+
+// See https://www.haproxy.org/download/2.8/doc/proxy-protocol.txt
+var proxyV2Signature = []byte{0x0D, 0x0A, 0x0D, 0x0A, 0x00, 0x0D, 0x0A, 0x51, 0x55, 0x49, 0x54, 0x0A}
+
+const (
+	// "PROXY UNKNOWN ffff:f...f:ffff ffff:f...f:ffff 65535 65535\r\n"
+	proxyV1MaxLength    = 107
+	proxyV2HeaderLength = 16
+)
+
+// The connection accepted from the L4 load balancer. RemoteAddr() returns the address of the client in the PROXY header.
+type proxyConn struct {
+	net.Conn
+	// Holds the bytes read after the header
+	reader     *bufio.Reader
+	remoteAddr net.Addr
+}
+
+func (c *proxyConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+func (c *proxyConn) RemoteAddr() net.Addr {
+	if c.remoteAddr != nil {
+		return c.remoteAddr
+	}
+	return c.Conn.RemoteAddr()
+}
+
+// Reads the PROXY protocol v1 or v2 header at the beginning of the connection. For the LOCAL command (e.g. the health check
+// of the load balancer) or the UNKNOWN protocol, the returned connection keeps the address of the load balancer.
+func readProxyHeader(conn net.Conn, timeout time.Duration) (net.Conn, error) {
+	if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+	reader := bufio.NewReader(conn)
+	remoteAddr, err := parseProxyHeader(reader)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.SetReadDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+	return &proxyConn{Conn: conn, reader: reader, remoteAddr: remoteAddr}, nil
+}
+
+func parseProxyHeader(reader *bufio.Reader) (net.Addr, error) {
+	prefix, err := reader.Peek(len(proxyV2Signature))
+	if err != nil {
+		// The v1 header can be shorter than the v2 signature only if it's invalid
+		return nil, err
+	}
+	if bytes.Equal(prefix, proxyV2Signature) {
+		return parseProxyHeaderV2(reader)
+	}
+	if bytes.HasPrefix(prefix, []byte("PROXY ")) {
+		return parseProxyHeaderV1(reader)
+	}
+	return nil, ErrInvalidProxyHeader
+}
+
+// e.g. "PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\n"
+func parseProxyHeaderV1(reader *bufio.Reader) (net.Addr, error) {
+	var line []byte
+	for len(line) < proxyV1MaxLength {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+	}
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, fmt.Errorf("%w: v1 header is not terminated", ErrInvalidProxyHeader)
+	}
+
+	fields := strings.Split(string(line[:len(line)-2]), " ")
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidProxyHeader, line)
+	}
+	ip := net.ParseIP(fields[2])
+	port, err := strconv.ParseUint(fields[4], 10, 16)
+	if ip == nil || err != nil || (fields[1] == "TCP4") != (ip.To4() != nil) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidProxyHeader, line)
+	}
+	return &net.TCPAddr{IP: ip, Port: int(port)}, nil
+}
+
+func parseProxyHeaderV2(reader *bufio.Reader) (net.Addr, error) {
+	header := make([]byte, proxyV2HeaderLength)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, err
+	}
+	versionCommand := header[12]
+	if versionCommand>>4 != 2 {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidProxyHeader, versionCommand>>4)
+	}
+	family := header[13]
+	payload := make([]byte, binary.BigEndian.Uint16(header[14:16]))
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, err
+	}
+
+	switch versionCommand & 0x0F {
+	case 0x0:
+		// LOCAL
+		return nil, nil
+	case 0x1:
+		// PROXY
+	default:
+		return nil, fmt.Errorf("%w: unsupported command %d", ErrInvalidProxyHeader, versionCommand&0x0F)
+	}
+
+	// The TLVs after the addresses are ignored
+	switch family {
+	case 0x11, 0x12:
+		// TCP or UDP over IPv4: the source and destination addresses, then the source and destination ports
+		if len(payload) < 12 {
+			return nil, fmt.Errorf("%w: IPv4 addresses are truncated", ErrInvalidProxyHeader)
+		}
+		return &net.TCPAddr{IP: net.IP(payload[0:4]), Port: int(binary.BigEndian.Uint16(payload[8:10]))}, nil
+	case 0x21, 0x22:
+		// TCP or UDP over IPv6
+		if len(payload) < 36 {
+			return nil, fmt.Errorf("%w: IPv6 addresses are truncated", ErrInvalidProxyHeader)
+		}
+		return &net.TCPAddr{IP: net.IP(payload[0:16]), Port: int(binary.BigEndian.Uint16(payload[32:34]))}, nil
+	default:
+		// UNSPEC or the unix sockets
+		return nil, nil
+	}
+}
@@ -0,0 +1,88 @@
+package channeld
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func proxyV2Header(command byte, family byte, addrs []byte) []byte {
+	header := append([]byte{}, proxyV2Signature...)
+	header = append(header, 0x20|command, family, 0, 0)
+	binary.BigEndian.PutUint16(header[14:], uint16(len(addrs)))
+	return append(header, addrs...)
+}
+
+func TestParseProxyHeader(t *testing.T) {
+	parse := func(header []byte) (net.Addr, error) {
+		return parseProxyHeader(bufio.NewReader(bytes.NewReader(header)))
+	}
+
+	addr, err := parse([]byte("PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.0.1:56324", addr.String())
+	addr, err = parse([]byte("PROXY TCP6 2001:db8::1 2001:db8::2 56324 443\r\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "[2001:db8::1]:56324", addr.String())
+	addr, err = parse([]byte("PROXY UNKNOWN\r\n"))
+	assert.NoError(t, err)
+	assert.Nil(t, addr)
+
+	for _, invalid := range []string{
+		"GET / HTTP/1.1\r\n",
+		"PROXY TCP4 192.168.0.1 192.168.0.11 56324\r\n",
+		"PROXY TCP4 2001:db8::1 192.168.0.11 56324 443\r\n",
+		"PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\n",
+	} {
+		_, err = parse([]byte(invalid))
+		assert.Error(t, err, invalid)
+	}
+
+	// v2 with a TLV after the addresses
+	addrs := []byte{10, 0, 0, 1, 10, 0, 0, 2, 0xdc, 0x04, 0x01, 0xbb, 0x04, 0x00, 0x01, 0x00}
+	addr, err = parse(proxyV2Header(1, 0x11, addrs))
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.1:56324", addr.String())
+
+	addrs = make([]byte, 36)
+	copy(addrs, net.ParseIP("2001:db8::1"))
+	binary.BigEndian.PutUint16(addrs[32:], 56324)
+	addr, err = parse(proxyV2Header(1, 0x21, addrs))
+	assert.NoError(t, err)
+	assert.Equal(t, "[2001:db8::1]:56324", addr.String())
+
+	// The health check of the load balancer
+	addr, err = parse(proxyV2Header(0, 0x00, nil))
+	assert.NoError(t, err)
+	assert.Nil(t, addr)
+
+	_, err = parse(proxyV2Header(1, 0x11, addrs[:8]))
+	assert.ErrorIs(t, err, ErrInvalidProxyHeader)
+}
+
+func TestReadProxyHeader(t *testing.T) {
+	client, server := net.Pipe()
+	go client.Write([]byte("PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\nCH"))
+
+	conn, err := readProxyHeader(server, time.Second)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "192.168.0.1", GetIP(conn.RemoteAddr()))
+	// The bytes after the header are kept
+	buf := make([]byte, 2)
+	_, err = io.ReadFull(conn, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("CH"), buf)
+
+	// Times out without the header
+	_, server = net.Pipe()
+	_, err = readProxyHeader(server, 10*time.Millisecond)
+	assert.Error(t, err)
+}
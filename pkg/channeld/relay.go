@@ -0,0 +1,202 @@
+package channeld
+
+import (
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// The max size of the data in a RelayMessage, so the message always fits in a packet of the link.
+const MaxRelayDataSize = MaxPacketSize / 2
+
+// The client connections relayed by the edge over the link (the server connection from the edge).
+type relayLink struct {
+	sync.Mutex
+	conns map[uint32]*relayedConn
+}
+
+// Implements net.Conn for the client connection relayed by the edge. The central handles it as a normal client connection.
+type relayedConn struct {
+	link       *Connection
+	edgeConnId uint32
+	remoteAddr relayAddr
+	connection *Connection
+	// The data read from the client, sent by the edge
+	inbound chan []byte
+	pending []byte
+	closing chan struct{}
+	closed  int32
+}
+
+type relayAddr string
+
+func (a relayAddr) Network() string {
+	return "relay"
+}
+
+func (a relayAddr) String() string {
+	return string(a)
+}
+
+func (rc *relayedConn) Read(b []byte) (n int, err error) {
+	if len(rc.pending) == 0 {
+		select {
+		case rc.pending = <-rc.inbound:
+		case <-rc.closing:
+			return 0, io.EOF
+		}
+	}
+	n = copy(b, rc.pending)
+	rc.pending = rc.pending[n:]
+	return n, nil
+}
+
+// Sends the data to the edge, which writes it to the client. The data is marshalled in Send(), so it's not retained.
+func (rc *relayedConn) Write(b []byte) (n int, err error) {
+	if atomic.LoadInt32(&rc.closed) == 1 {
+		return 0, net.ErrClosed
+	}
+	for i := 0; i < len(b); i += MaxRelayDataSize {
+		end := i + MaxRelayDataSize
+		if end > len(b) {
+			end = len(b)
+		}
+		rc.link.Send(MessageContext{
+			MsgType: channeldpb.MessageType_RELAY,
+			Msg: &channeldpb.RelayMessage{
+				EdgeConnId: rc.edgeConnId,
+				Event:      channeldpb.RelayMessage_DATA,
+				Data:       b[i:end],
+			},
+		})
+	}
+	return len(b), nil
+}
+
+// Called by the relayed Connection. Notifies the edge to close the client connection.
+func (rc *relayedConn) Close() error {
+	if !atomic.CompareAndSwapInt32(&rc.closed, 0, 1) {
+		return nil
+	}
+	close(rc.closing)
+
+	rc.link.relayLink.Lock()
+	delete(rc.link.relayLink.conns, rc.edgeConnId)
+	rc.link.relayLink.Unlock()
+
+	rc.link.Send(MessageContext{
+		MsgType: channeldpb.MessageType_RELAY,
+		Msg: &channeldpb.RelayMessage{
+			EdgeConnId: rc.edgeConnId,
+			Event:      channeldpb.RelayMessage_DISCONNECTED,
+		},
+	})
+	return nil
+}
+
+func (rc *relayedConn) LocalAddr() net.Addr {
+	return rc.link.conn.LocalAddr()
+}
+
+func (rc *relayedConn) RemoteAddr() net.Addr {
+	return rc.remoteAddr
+}
+
+func (rc *relayedConn) SetDeadline(t time.Time) error {
+	return nil
+}
+
+func (rc *relayedConn) SetReadDeadline(t time.Time) error {
+	return nil
+}
+
+func (rc *relayedConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+// Handles the RelayMessage in the receive goroutine of the link, so the relayed traffic doesn't wait for the GLOBAL channel's tick.
+func (c *Connection) handleRelayMessage(mp *channeldpb.MessagePack) {
+	if c.connectionType != channeldpb.ConnectionType_SERVER {
+		c.Logger().Warn("only the server connection can relay the client connections")
+		return
+	}
+	if !c.fsm.IsAllowed(mp.MsgType) {
+		Event_FsmDisallowed.Broadcast(c)
+		c.Logger().Warn("message is not allowed for current state",
+			zap.Uint32("msgType", mp.MsgType),
+			zap.String("connState", c.fsm.CurrentState().Name),
+		)
+		return
+	}
+
+	msg := &channeldpb.RelayMessage{}
+	if err := proto.Unmarshal(mp.MsgBody, msg); err != nil {
+		c.Logger().Error("failed to unmarshal the relay message", zap.Error(err))
+		return
+	}
+
+	if c.relayLink == nil {
+		c.relayLink = &relayLink{conns: make(map[uint32]*relayedConn)}
+		c.AddCloseHandler(func() {
+			c.relayLink.Lock()
+			conns := make([]*relayedConn, 0, len(c.relayLink.conns))
+			for _, rc := range c.relayLink.conns {
+				conns = append(conns, rc)
+			}
+			c.relayLink.Unlock()
+			for _, rc := range conns {
+				rc.connection.Close()
+			}
+		})
+	}
+
+	c.relayLink.Lock()
+	rc := c.relayLink.conns[msg.EdgeConnId]
+	c.relayLink.Unlock()
+
+	switch msg.Event {
+	case channeldpb.RelayMessage_CONNECTED:
+		if rc != nil {
+			c.Logger().Warn("the relayed connection already exists", zap.Uint32("edgeConnId", msg.EdgeConnId))
+			return
+		}
+		rc = &relayedConn{
+			link:       c,
+			edgeConnId: msg.EdgeConnId,
+			remoteAddr: relayAddr(msg.RemoteAddr),
+			inbound:    make(chan []byte, 64),
+			closing:    make(chan struct{}),
+		}
+		if _, banned := ipBlacklist[GetIP(rc.remoteAddr)]; banned {
+			securityLogger.Info("refused relayed connection of banned IP address", zap.String("ip", GetIP(rc.remoteAddr)))
+			rc.Close()
+			return
+		}
+		c.relayLink.Lock()
+		c.relayLink.conns[msg.EdgeConnId] = rc
+		c.relayLink.Unlock()
+		rc.connection = AddConnection(rc, channeldpb.ConnectionType_CLIENT)
+		rc.connection.Logger().Debug("accepted relayed connection", zap.Uint32("edgeConnId", msg.EdgeConnId), zap.Uint32("linkConnId", uint32(c.id)))
+		startGoroutines(rc.connection)
+
+	case channeldpb.RelayMessage_DATA:
+		if rc == nil {
+			return
+		}
+		select {
+		case rc.inbound <- msg.Data:
+		case <-rc.closing:
+		}
+
+	case channeldpb.RelayMessage_DISCONNECTED:
+		if rc != nil {
+			rc.connection.Close()
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package channeld
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+)
+
+// The hooks that a script registers. Any of them can be empty.
+type ScriptHooks struct {
+	// Handles the user-space message types, instead of forwarding them between the clients and the servers.
+	// The Msg of the context is a ServerForwardMessage.
+	Handlers map[channeldpb.MessageType]MessageHandlerFunc
+	// Rejects the message before it's handled, by returning an error. Called in the channel's goroutine.
+	Validators map[channeldpb.MessageType]func(ctx MessageContext) error
+	// Skips broadcasting the message to the connection, by returning false. Called in the channel's goroutine.
+	BroadcastFilter func(ch *Channel, ctx MessageContext, conn ConnectionInChannel) bool
+	// Releases the resources of the script, e.g. the VM, when the script is reloaded. Optional.
+	Close func()
+}
+
+// Compiles the source of a script and returns its hooks, e.g. by running the script in an embedded Lua VM or WASM runtime.
+// The engine is selected by the file extension of the script. See RegisterScriptEngine().
+type ScriptEngine interface {
+	Load(name string, source []byte) (*ScriptHooks, error)
+}
+
+var scriptEngines = make(map[string]ScriptEngine)
+
+// Registers the engine for the scripts with the file extension, e.g. ".lua" or ".wasm". Should be called before LoadScripts().
+func RegisterScriptEngine(ext string, engine ScriptEngine) {
+	scriptEngines[strings.ToLower(ext)] = engine
+}
+
+// The hooks of all the loaded scripts, merged in the order of the file names.
+type scriptSet struct {
+	handlers   map[channeldpb.MessageType]MessageHandlerFunc
+	validators map[channeldpb.MessageType][]func(ctx MessageContext) error
+	filters    []func(ch *Channel, ctx MessageContext, conn ConnectionInChannel) bool
+	closers    []func()
+	// The modification time of the script files, for the hot reload
+	modTimes map[string]time.Time
+}
+
+// The *scriptSet in use. Replaced as a whole when the scripts are reloaded.
+var scripts atomic.Value
+var scriptsLoadLock sync.Mutex
+
+func loadedScripts() *scriptSet {
+	set, _ := scripts.Load().(*scriptSet)
+	return set
+}
+
+// Loads the scripts in the directory with the registered engines, and replaces the hooks of the previously loaded scripts.
+// If any script fails to load, the previous hooks are kept.
+func LoadScripts(dir string) error {
+	scriptsLoadLock.Lock()
+	defer scriptsLoadLock.Unlock()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	set := &scriptSet{
+		handlers:   make(map[channeldpb.MessageType]MessageHandlerFunc),
+		validators: make(map[channeldpb.MessageType][]func(ctx MessageContext) error),
+		modTimes:   make(map[string]time.Time),
+	}
+	loaded := false
+	defer func() {
+		if !loaded {
+			set.close()
+		}
+	}()
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		engine, exists := scriptEngines[strings.ToLower(filepath.Ext(entry.Name()))]
+		if !exists {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		hooks, err := engine.Load(entry.Name(), source)
+		if err != nil {
+			return fmt.Errorf("failed to load script %s: %w", entry.Name(), err)
+		}
+		if err := set.add(entry.Name(), hooks); err != nil {
+			return err
+		}
+		set.modTimes[path] = info.ModTime()
+	}
+
+	loaded = true
+	if prev := loadedScripts(); prev != nil {
+		defer prev.close()
+	}
+	scripts.Store(set)
+	rootLogger.Info("loaded scripts", zap.String("dir", dir), zap.Int("num", len(set.modTimes)),
+		zap.Int("handlers", len(set.handlers)), zap.Int("filters", len(set.filters)))
+	return nil
+}
+
+func (set *scriptSet) add(name string, hooks *ScriptHooks) error {
+	if hooks == nil {
+		return nil
+	}
+	if hooks.Close != nil {
+		set.closers = append(set.closers, hooks.Close)
+	}
+	for msgType, handler := range hooks.Handlers {
+		if msgType < channeldpb.MessageType_USER_SPACE_START {
+			return fmt.Errorf("script %s can't handle the non user-space message type %d", name, msgType)
+		}
+		if _, exists := set.handlers[msgType]; exists {
+			return fmt.Errorf("script %s handles the message type %d which is already handled by another script", name, msgType)
+		}
+		set.handlers[msgType] = handler
+	}
+	for msgType, validator := range hooks.Validators {
+		set.validators[msgType] = append(set.validators[msgType], validator)
+	}
+	if hooks.BroadcastFilter != nil {
+		set.filters = append(set.filters, hooks.BroadcastFilter)
+	}
+	return nil
+}
+
+// Releases the resources of the scripts, after the set is replaced or fails to load.
+func (set *scriptSet) close() {
+	for _, closer := range set.closers {
+		closer()
+	}
+}
+
+// Returns true if any script file in the directory has been added, removed or modified since the last load.
+func (set *scriptSet) changed(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	num := 0
+	for _, entry := range entries {
+		if _, exists := scriptEngines[strings.ToLower(filepath.Ext(entry.Name()))]; !exists || entry.IsDir() {
+			continue
+		}
+		num++
+		info, err := entry.Info()
+		if err != nil {
+			return false
+		}
+		if modTime, exists := set.modTimes[filepath.Join(dir, entry.Name())]; !exists || !modTime.Equal(info.ModTime()) {
+			return true
+		}
+	}
+	return num != len(set.modTimes)
+}
+
+// Reloads the scripts in the directory when they change, every interval. Blocks forever.
+func WatchScripts(dir string, interval time.Duration) {
+	for {
+		time.Sleep(interval)
+		if set := loadedScripts(); set != nil && !set.changed(dir) {
+			continue
+		}
+		if err := LoadScripts(dir); err != nil {
+			rootLogger.Error("failed to reload scripts, keeping the previous ones", zap.Error(err))
+		}
+	}
+}
+
+// Loads the scripts in GlobalSettingsType.ScriptDir, and watches them for the hot reload if ScriptReloadIntervalMs is set.
+// Does nothing if ScriptDir is empty.
+func InitScripts() error {
+	if GlobalSettings.ScriptDir == "" {
+		return nil
+	}
+	if len(scriptEngines) == 0 {
+		rootLogger.Warn("no script engine is registered, the scripts won't be loaded", zap.String("dir", GlobalSettings.ScriptDir))
+	}
+	if err := LoadScripts(GlobalSettings.ScriptDir); err != nil {
+		return err
+	}
+	if GlobalSettings.ScriptReloadIntervalMs > 0 {
+		go WatchScripts(GlobalSettings.ScriptDir, time.Duration(GlobalSettings.ScriptReloadIntervalMs)*time.Millisecond)
+	}
+	return nil
+}
+
+// Returns the handler of the user-space message type registered by the scripts, or nil if there's none.
+func scriptHandler(msgType uint32) MessageHandlerFunc {
+	if set := loadedScripts(); set != nil {
+		return set.handlers[channeldpb.MessageType(msgType)]
+	}
+	return nil
+}
+
+// Returns the first error of the validators registered by the scripts for the message type.
+func validateByScripts(ctx MessageContext) error {
+	set := loadedScripts()
+	if set == nil {
+		return nil
+	}
+	for _, validator := range set.validators[ctx.MsgType] {
+		if err := validator(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns false if any broadcast filter registered by the scripts skips the connection.
+func (ch *Channel) scriptBroadcastFilter(ctx MessageContext, conn ConnectionInChannel) bool {
+	set := loadedScripts()
+	if set == nil {
+		return true
+	}
+	for _, filter := range set.filters {
+		if !filter(ch, ctx, conn) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,253 @@
+package channeld
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	lua "github.com/yuin/gopher-lua"
+	"go.uber.org/zap"
+)
+
+// Runs the ".lua" scripts in gopher-lua. A script returns the table of its hooks:
+//
+//	return {
+//		-- Handles the user-space message. Returns nothing.
+//		handlers = { [100] = function(ctx) end },
+//		-- Rejects the message by returning the error message.
+//		validators = { [5] = function(ctx) return "not allowed" end },
+//		-- Skips broadcasting the message to the connection by returning false.
+//		broadcastFilter = function(ctx, conn) return true end,
+//	}
+//
+// The ctx table has msgType, channelId, connId, connType, and the clientConnId and payload of the ServerForwardMessage.
+// The conn table has connId and connType. The scripts can call channeld.send(connId, msgType, payload) to send a
+// ServerForwardMessage, and channeld.log(text). Only the base, table, string and math libraries are available, so the
+// scripts can't access the files or run the processes. A call that takes longer than GlobalSettings.ScriptTimeoutMs is
+// aborted with an error.
+type LuaScriptEngine struct{}
+
+const DefaultScriptTimeoutMs = 100
+
+func init() {
+	RegisterScriptEngine(".lua", &LuaScriptEngine{})
+}
+
+// Each script has its own Lua state, which is locked when the hooks are called, as they are called in different goroutines.
+type luaScript struct {
+	name string
+	lock sync.Mutex
+	L    *lua.LState
+	// Set when the state is closed by the reload. The hooks called afterwards do nothing.
+	closed bool
+}
+
+// The Lua libraries that the scripts can use
+var luaLibs = []struct {
+	name string
+	open lua.LGFunction
+}{
+	{lua.BaseLibName, lua.OpenBase},
+	{lua.TabLibName, lua.OpenTable},
+	{lua.StringLibName, lua.OpenString},
+	{lua.MathLibName, lua.OpenMath},
+}
+
+// Creates the Lua state without the os, io, package and debug libraries.
+func newSandboxedLuaState() *lua.LState {
+	L := lua.NewState(lua.Options{SkipOpenLibs: true})
+	for _, lib := range luaLibs {
+		L.Push(L.NewFunction(lib.open))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+	// The base functions that load the files or the modules
+	for _, name := range []string{"dofile", "loadfile", "module", "require"} {
+		L.SetGlobal(name, lua.LNil)
+	}
+	return L
+}
+
+func (e *LuaScriptEngine) Load(name string, source []byte) (*ScriptHooks, error) {
+	s := &luaScript{name: name, L: newSandboxedLuaState()}
+	s.L.SetGlobal("channeld", s.L.SetFuncs(s.L.NewTable(), map[string]lua.LGFunction{
+		"send": luaSend,
+		"log":  s.luaLog,
+	}))
+	cancel := withScriptDeadline(s.L)
+	err := s.L.DoString(string(source))
+	cancel()
+	if err != nil {
+		s.L.Close()
+		return nil, err
+	}
+	tbl, ok := s.L.Get(-1).(*lua.LTable)
+	s.L.SetTop(0)
+	if !ok {
+		s.L.Close()
+		return nil, errors.New("the script should return the table of its hooks")
+	}
+
+	hooks := &ScriptHooks{Close: s.close}
+	if handlers, ok := tbl.RawGetString("handlers").(*lua.LTable); ok {
+		hooks.Handlers = make(map[channeldpb.MessageType]MessageHandlerFunc)
+		handlers.ForEach(func(k, v lua.LValue) {
+			msgType, fn, e := luaHook("handlers", k, v)
+			if e != nil {
+				err = e
+				return
+			}
+			hooks.Handlers[msgType] = s.handler(fn)
+		})
+	}
+	if validators, ok := tbl.RawGetString("validators").(*lua.LTable); ok {
+		hooks.Validators = make(map[channeldpb.MessageType]func(ctx MessageContext) error)
+		validators.ForEach(func(k, v lua.LValue) {
+			msgType, fn, e := luaHook("validators", k, v)
+			if e != nil {
+				err = e
+				return
+			}
+			hooks.Validators[msgType] = s.validator(fn)
+		})
+	}
+	if filter, ok := tbl.RawGetString("broadcastFilter").(*lua.LFunction); ok {
+		hooks.BroadcastFilter = s.broadcastFilter(filter)
+	}
+	if err != nil {
+		s.L.Close()
+		return nil, err
+	}
+	return hooks, nil
+}
+
+func luaHook(field string, k lua.LValue, v lua.LValue) (channeldpb.MessageType, *lua.LFunction, error) {
+	msgType, ok := k.(lua.LNumber)
+	if !ok {
+		return 0, nil, fmt.Errorf("the key of %s should be the message type, got %s", field, k.Type())
+	}
+	fn, ok := v.(*lua.LFunction)
+	if !ok {
+		return 0, nil, fmt.Errorf("%s[%d] should be a function, got %s", field, int(msgType), v.Type())
+	}
+	return channeldpb.MessageType(msgType), fn, nil
+}
+
+// Sets the deadline of the calls in the Lua state. The returned function removes it.
+func withScriptDeadline(L *lua.LState) context.CancelFunc {
+	timeoutMs := GlobalSettings.ScriptTimeoutMs
+	if timeoutMs == 0 {
+		timeoutMs = DefaultScriptTimeoutMs
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeoutMs)*time.Millisecond)
+	L.SetContext(ctx)
+	return func() {
+		L.RemoveContext()
+		cancel()
+	}
+}
+
+// Closes the Lua state after the hooks being called return.
+func (s *luaScript) close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.closed = true
+	s.L.Close()
+}
+
+// Calls the function and returns its first result, or nil if the state is closed. Should be called with the lock held.
+func (s *luaScript) call(fn *lua.LFunction, args ...lua.LValue) (lua.LValue, error) {
+	if s.closed {
+		return lua.LNil, nil
+	}
+	cancel := withScriptDeadline(s.L)
+	defer cancel()
+	if err := s.L.CallByParam(lua.P{Fn: fn, NRet: 1, Protect: true}, args...); err != nil {
+		return lua.LNil, err
+	}
+	ret := s.L.Get(-1)
+	s.L.Pop(1)
+	return ret, nil
+}
+
+func (s *luaScript) handler(fn *lua.LFunction) MessageHandlerFunc {
+	return func(ctx MessageContext) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		if _, err := s.call(fn, s.luaContext(ctx)); err != nil {
+			rootLogger.Error("script handler failed", zap.String("script", s.name), zap.Uint32("msgType", uint32(ctx.MsgType)), zap.Error(err))
+		}
+	}
+}
+
+// The message is also rejected if the validator fails.
+func (s *luaScript) validator(fn *lua.LFunction) func(ctx MessageContext) error {
+	return func(ctx MessageContext) error {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		ret, err := s.call(fn, s.luaContext(ctx))
+		if err != nil {
+			return err
+		}
+		if ret == lua.LNil || ret == lua.LFalse {
+			return nil
+		}
+		return errors.New(ret.String())
+	}
+}
+
+// The message is still broadcast if the filter fails.
+func (s *luaScript) broadcastFilter(fn *lua.LFunction) func(ch *Channel, ctx MessageContext, conn ConnectionInChannel) bool {
+	return func(ch *Channel, ctx MessageContext, conn ConnectionInChannel) bool {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		connTbl := s.L.NewTable()
+		connTbl.RawSetString("connId", lua.LNumber(conn.Id()))
+		connTbl.RawSetString("connType", lua.LString(conn.GetConnectionType().String()))
+		ret, err := s.call(fn, s.luaContext(ctx), connTbl)
+		if err != nil {
+			rootLogger.Error("script broadcast filter failed", zap.String("script", s.name), zap.Uint32("msgType", uint32(ctx.MsgType)), zap.Error(err))
+			return true
+		}
+		return ret != lua.LFalse
+	}
+}
+
+// Should be called with the lock held.
+func (s *luaScript) luaContext(ctx MessageContext) *lua.LTable {
+	tbl := s.L.NewTable()
+	tbl.RawSetString("msgType", lua.LNumber(ctx.MsgType))
+	tbl.RawSetString("channelId", lua.LNumber(ctx.ChannelId))
+	if ctx.Connection != nil {
+		tbl.RawSetString("connId", lua.LNumber(ctx.Connection.Id()))
+		tbl.RawSetString("connType", lua.LString(ctx.Connection.GetConnectionType().String()))
+	}
+	if msg, ok := ctx.Msg.(*channeldpb.ServerForwardMessage); ok {
+		tbl.RawSetString("clientConnId", lua.LNumber(msg.ClientConnId))
+		tbl.RawSetString("payload", lua.LString(msg.Payload))
+	}
+	return tbl
+}
+
+// channeld.send(connId, msgType, payload) returns false if the connection doesn't exist.
+func luaSend(L *lua.LState) int {
+	conn := GetConnection(ConnectionId(L.CheckInt(1)))
+	if conn == nil {
+		L.Push(lua.LFalse)
+		return 1
+	}
+	conn.Send(MessageContext{
+		MsgType: channeldpb.MessageType(L.CheckInt(2)),
+		Msg:     &channeldpb.ServerForwardMessage{Payload: []byte(L.CheckString(3))},
+	})
+	L.Push(lua.LTrue)
+	return 1
+}
+
+func (s *luaScript) luaLog(L *lua.LState) int {
+	rootLogger.Info("script log", zap.String("script", s.name), zap.String("text", L.CheckString(1)))
+	return 0
+}
@@ -0,0 +1,192 @@
+package channeld
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns the hooks by the source of the script.
+type testScriptEngine map[string]*ScriptHooks
+
+func (e testScriptEngine) Load(name string, source []byte) (*ScriptHooks, error) {
+	hooks, exists := e[string(source)]
+	if !exists {
+		return nil, errors.New("syntax error")
+	}
+	return hooks, nil
+}
+
+func TestScripts(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	handled := 0
+	engine := testScriptEngine{
+		"handler": {
+			Handlers: map[channeldpb.MessageType]MessageHandlerFunc{
+				channeldpb.MessageType_USER_SPACE_START: func(ctx MessageContext) { handled++ },
+			},
+		},
+		"validator": {
+			Validators: map[channeldpb.MessageType]func(ctx MessageContext) error{
+				channeldpb.MessageType_LIST_CHANNEL: func(ctx MessageContext) error { return errors.New("not allowed") },
+			},
+		},
+		"filter": {
+			BroadcastFilter: func(ch *Channel, ctx MessageContext, conn ConnectionInChannel) bool {
+				return conn.GetConnectionType() != channeldpb.ConnectionType_CLIENT
+			},
+		},
+		"system handler": {
+			Handlers: map[channeldpb.MessageType]MessageHandlerFunc{
+				channeldpb.MessageType_AUTH: func(ctx MessageContext) {},
+			},
+		},
+	}
+	RegisterScriptEngine(".test", engine)
+	defer delete(scriptEngines, ".test")
+	defer scripts.Store((*scriptSet)(nil))
+
+	dir := t.TempDir()
+	write := func(name string, source string) {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(source), 0644))
+	}
+	write("a.test", "handler")
+	write("b.test", "validator")
+	write("c.test", "filter")
+	// Not loaded without the engine
+	write("d.js", "syntax error")
+	assert.NoError(t, LoadScripts(dir))
+
+	scriptHandler(uint32(channeldpb.MessageType_USER_SPACE_START))(MessageContext{})
+	assert.Equal(t, 1, handled)
+	assert.Nil(t, scriptHandler(uint32(channeldpb.MessageType_USER_SPACE_START)+1))
+
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	handledListChannel := false
+	globalChannel.handleMessage(channelMessage{
+		ctx: MessageContext{
+			MsgType:    channeldpb.MessageType_LIST_CHANNEL,
+			Msg:        &channeldpb.ListChannelMessage{},
+			Connection: client,
+			Channel:    globalChannel,
+		},
+		handler: func(ctx MessageContext) { handledListChannel = true },
+	}, "")
+	assert.False(t, handledListChannel)
+
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	ch.removing = 1
+	client.SubscribeToChannel(ch, nil)
+	server.SubscribeToChannel(ch, nil)
+	ch.Broadcast(MessageContext{MsgType: channeldpb.MessageType_USER_SPACE_START, Msg: &channeldpb.ServerForwardMessage{}})
+	assert.Nil(t, client.latestMsg())
+	assert.NotNil(t, server.latestMsg())
+
+	// The previous scripts are kept if any script fails to load
+	set := loadedScripts()
+	assert.False(t, set.changed(dir))
+	write("e.test", "system handler")
+	assert.True(t, set.changed(dir))
+	assert.Error(t, LoadScripts(dir))
+	assert.Same(t, set, loadedScripts())
+
+	// Reloaded when the script is modified
+	assert.NoError(t, os.Remove(filepath.Join(dir, "e.test")))
+	assert.False(t, set.changed(dir))
+	write("c.test", "handler")
+	assert.NoError(t, os.Chtimes(filepath.Join(dir, "c.test"), time.Now(), time.Now().Add(time.Second)))
+	assert.True(t, set.changed(dir))
+	// Both a.test and c.test handle the same message type
+	assert.Error(t, LoadScripts(dir))
+	write("c.test", "filter")
+	assert.NoError(t, LoadScripts(dir))
+	assert.NotSame(t, set, loadedScripts())
+}
+
+func TestLuaScripts(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+	defer scripts.Store((*scriptSet)(nil))
+
+	assert.NoError(t, LoadScripts("testdata/scripts"))
+
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+
+	// Handler
+	handler := scriptHandler(100)
+	if !assert.NotNil(t, handler) {
+		return
+	}
+	handler(MessageContext{MsgType: 100, Msg: &channeldpb.ServerForwardMessage{Payload: []byte("hi")}, Connection: client})
+	if assert.IsType(t, &channeldpb.ServerForwardMessage{}, client.latestMsg()) {
+		assert.Equal(t, "echo:hi", string(client.latestMsg().(*channeldpb.ServerForwardMessage).Payload))
+	}
+
+	// Validator
+	listCtx := MessageContext{MsgType: channeldpb.MessageType_LIST_CHANNEL, Msg: &channeldpb.ListChannelMessage{}, Connection: client}
+	assert.EqualError(t, validateByScripts(listCtx), "clients can't list the channels")
+	listCtx.Connection = server
+	assert.NoError(t, validateByScripts(listCtx))
+
+	// Broadcast filter
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	defer RemoveChannel(ch)
+	client.SubscribeToChannel(ch, nil)
+	server.SubscribeToChannel(ch, nil)
+	clientMsgNum, serverMsgNum := len(client.testQueue()), len(server.testQueue())
+	ch.Broadcast(MessageContext{MsgType: 101, Msg: &channeldpb.ServerForwardMessage{}})
+	assert.Equal(t, clientMsgNum, len(client.testQueue()))
+	assert.Equal(t, serverMsgNum+1, len(server.testQueue()))
+
+	// The scripts that fail to load
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.lua"), []byte("return {"), 0644))
+	assert.Error(t, LoadScripts(dir))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.lua"), []byte("return { handlers = { [100] = 1 } }"), 0644))
+	assert.Error(t, LoadScripts(dir))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.lua"), []byte("return 1"), 0644))
+	assert.Error(t, LoadScripts(dir))
+
+	// Only the sandboxed libraries are available
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.lua"), []byte(`return { validators = { [5] = function(ctx)
+		return type(os) .. type(io) .. type(dofile) .. type(require) .. type(string.format)
+	end } }`), 0644))
+	assert.NoError(t, LoadScripts(dir))
+	assert.EqualError(t, validateByScripts(listCtx), "nilnilnilnilfunction")
+
+	// The calls that don't return are aborted
+	GlobalSettings.ScriptTimeoutMs = 20
+	defer func() { GlobalSettings.ScriptTimeoutMs = 0 }()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.lua"), []byte("while true do end"), 0644))
+	assert.Error(t, LoadScripts(dir))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.lua"), []byte(`return { validators = { [5] = function(ctx)
+		if ctx.connType == "CLIENT" then
+			while true do end
+		end
+	end } }`), 0644))
+	assert.NoError(t, LoadScripts(dir))
+	listCtx.Connection = client
+	start := time.Now()
+	assert.Error(t, validateByScripts(listCtx))
+	assert.Less(t, time.Since(start), time.Second)
+	// The Lua state is still usable
+	listCtx.Connection = server
+	assert.NoError(t, validateByScripts(listCtx))
+
+	// The previous Lua states are closed after the reload
+	validator := loadedScripts().validators[channeldpb.MessageType_LIST_CHANNEL][0]
+	assert.NoError(t, LoadScripts("testdata/scripts"))
+	assert.NoError(t, validator(listCtx))
+	assert.EqualError(t, validateByScripts(MessageContext{MsgType: channeldpb.MessageType_LIST_CHANNEL, Msg: &channeldpb.ListChannelMessage{}, Connection: client}), "clients can't list the channels")
+}
@@ -0,0 +1,65 @@
+package channeld
+
+import (
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// Unpacks the ServerBatchMessage in the receive goroutine, and routes each entry as if it's received in its own MessagePack,
+// so the entries go through the same checks (channel, authentication, FSM) and handlers as the standalone messages.
+func (c *Connection) handleServerBatchMessage(mp *channeldpb.MessagePack) {
+	if c.connectionType != channeldpb.ConnectionType_SERVER {
+		c.Logger().Warn("only the server connection can send the batch message")
+		c.replyError(mp, channeldpb.ErrorMessage_MESSAGE_NOT_ALLOWED, "only the server connection can send the batch message")
+		return
+	}
+	if c.State() != ConnectionState_READY || !c.fsm.IsAllowed(mp.MsgType) {
+		Event_FsmDisallowed.Broadcast(c)
+		c.Logger().Warn("message is not allowed for current state",
+			zap.Uint32("msgType", mp.MsgType),
+			zap.String("connState", c.fsm.CurrentState().Name),
+		)
+		c.replyError(mp, channeldpb.ErrorMessage_MESSAGE_NOT_ALLOWED, "message is not allowed in the current state")
+		return
+	}
+
+	msg := &channeldpb.ServerBatchMessage{}
+	if err := proto.Unmarshal(mp.MsgBody, msg); err != nil {
+		c.Logger().Error("failed to unmarshal the batch message", zap.Error(err))
+		c.replyError(mp, channeldpb.ErrorMessage_INVALID_MESSAGE, err.Error())
+		return
+	}
+	if msg.MsgType == uint32(channeldpb.MessageType_SERVER_BATCH) {
+		c.replyError(mp, channeldpb.ErrorMessage_INVALID_MESSAGE, "the batch message can't be nested")
+		return
+	}
+	if msg.DataTypeUrl != "" && msg.MsgType != uint32(channeldpb.MessageType_CHANNEL_DATA_UPDATE) {
+		c.replyError(mp, channeldpb.ErrorMessage_INVALID_MESSAGE, "dataTypeUrl only works for CHANNEL_DATA_UPDATE")
+		return
+	}
+
+	for _, entry := range msg.Entries {
+		msgBody := entry.MsgBody
+		if msg.DataTypeUrl != "" {
+			var err error
+			msgBody, err = proto.Marshal(&channeldpb.ChannelDataUpdateMessage{
+				Data: &anypb.Any{TypeUrl: msg.DataTypeUrl, Value: entry.MsgBody},
+			})
+			if err != nil {
+				c.Logger().Error("failed to wrap the channel data in the batch", zap.Uint32("channelId", entry.ChannelId), zap.Error(err))
+				continue
+			}
+		}
+		c.receiveMessage(&channeldpb.MessagePack{
+			ChannelId:  entry.ChannelId,
+			ChannelKey: entry.ChannelKey,
+			Broadcast:  entry.Broadcast,
+			StubId:     entry.StubId,
+			MsgType:    msg.MsgType,
+			MsgBody:    msgBody,
+			TraceId:    mp.TraceId,
+		})
+	}
+}
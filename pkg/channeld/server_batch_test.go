@@ -0,0 +1,103 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/fsm"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestServerBatchMessage(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		DefaultFanOutIntervalMs: 50,
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	permissiveFsm, err := fsm.Load([]byte(`{
+		"States": [{"Name": "OPEN", "MsgTypeWhitelist": "1-65535"}],
+		"InitState": "OPEN"
+	}`))
+	assert.NoError(t, err)
+	server.fsm = permissiveFsm
+	server.OnAuthenticated("")
+
+	ch1, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	ch1.InitData(&testpb.TestChannelDataMessage{Num: 1}, nil)
+	ch2, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	ch2.InitData(&testpb.TestChannelDataMessage{Num: 1}, nil)
+	defer RemoveChannel(ch1)
+	defer RemoveChannel(ch2)
+
+	numOf := func(ch *Channel) func() bool {
+		return func() bool {
+			result := make(chan uint32, 1)
+			ch.Execute(func(ch *Channel) {
+				result <- ch.GetDataMessage().(*testpb.TestChannelDataMessage).Num
+			})
+			return <-result == 2
+		}
+	}
+
+	// The channel data in the entries, without the ChannelDataUpdateMessage wrapper
+	data, _ := proto.Marshal(&testpb.TestChannelDataMessage{Num: 2})
+	batchBody, _ := proto.Marshal(&channeldpb.ServerBatchMessage{
+		MsgType:     uint32(channeldpb.MessageType_CHANNEL_DATA_UPDATE),
+		DataTypeUrl: "type.googleapis.com/" + string((&testpb.TestChannelDataMessage{}).ProtoReflect().Descriptor().FullName()),
+		Entries: []*channeldpb.ServerBatchMessage_Entry{
+			{ChannelId: uint32(ch1.id), MsgBody: data},
+		},
+	})
+	server.receiveMessage(&channeldpb.MessagePack{
+		MsgType: uint32(channeldpb.MessageType_SERVER_BATCH),
+		MsgBody: batchBody,
+	})
+	assert.Eventually(t, numOf(ch1), time.Second, 10*time.Millisecond)
+
+	// The marshalled messages in the entries
+	anyData, _ := anypb.New(&testpb.TestChannelDataMessage{Num: 2})
+	updateBody, _ := proto.Marshal(&channeldpb.ChannelDataUpdateMessage{Data: anyData})
+	batchBody, _ = proto.Marshal(&channeldpb.ServerBatchMessage{
+		MsgType: uint32(channeldpb.MessageType_CHANNEL_DATA_UPDATE),
+		Entries: []*channeldpb.ServerBatchMessage_Entry{
+			{ChannelId: uint32(ch2.id), MsgBody: updateBody},
+		},
+	})
+	server.receiveMessage(&channeldpb.MessagePack{
+		MsgType: uint32(channeldpb.MessageType_SERVER_BATCH),
+		MsgBody: batchBody,
+	})
+	assert.Eventually(t, numOf(ch2), time.Second, 10*time.Millisecond)
+
+	// The batch can't be nested
+	nestedBody, _ := proto.Marshal(&channeldpb.ServerBatchMessage{MsgType: uint32(channeldpb.MessageType_SERVER_BATCH)})
+	server.receiveMessage(&channeldpb.MessagePack{
+		MsgType: uint32(channeldpb.MessageType_SERVER_BATCH),
+		MsgBody: nestedBody,
+	})
+	errMsg, ok := server.latestMsg().(*channeldpb.ErrorMessage)
+	if assert.True(t, ok) {
+		assert.Equal(t, channeldpb.ErrorMessage_INVALID_MESSAGE, errMsg.Code)
+	}
+
+	// Only the server connection can send the batch
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	client.OnAuthenticated("")
+	client.receiveMessage(&channeldpb.MessagePack{
+		MsgType: uint32(channeldpb.MessageType_SERVER_BATCH),
+		MsgBody: batchBody,
+	})
+	errMsg, ok = client.latestMsg().(*channeldpb.ErrorMessage)
+	if assert.True(t, ok) {
+		assert.Equal(t, channeldpb.ErrorMessage_MESSAGE_NOT_ALLOWED, errMsg.Code)
+	}
+}
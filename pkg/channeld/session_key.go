@@ -0,0 +1,224 @@
+package channeld
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+	"google.golang.org/protobuf/proto"
+)
+
+// The body of the packet with WireFlag_Encrypted is the id of the session key in varint, the nonce, and the body sealed
+// by AES-256-GCM with the session key. The body is compressed before it's encrypted.
+const sessionKeyNonceSize = 12
+
+// The max bytes that the encryption adds to the packet body: the key id, the nonce and the GCM tag.
+const SessionKeyOverhead = binary.MaxVarintLen32 + sessionKeyNonceSize + 16
+
+const sessionKeyInfo = "channeld session key "
+
+var ErrUnknownSessionKey = errors.New("unknown session key")
+var ErrInvalidEncryptedPacket = errors.New("invalid encrypted packet")
+
+// The key that encrypts the packets between two rotations. See channeldpb.SessionKeyRotationMessage.
+//
+// Each direction has its own AES key, so the nonce counters of both sides never encrypt with the same key and nonce.
+// Sealing and opening can be used in different goroutines.
+type SessionKey struct {
+	Id       uint32
+	sealAead cipher.AEAD
+	openAead cipher.AEAD
+	// The counter of the nonce. Only used for sealing.
+	nonce uint64
+	// The nonce of the last opened packet. The packets are received in order, so a smaller nonce is a replay.
+	openNonce uint64
+}
+
+// Generates the X25519 key pair for deriving the session keys.
+func GenerateSessionKeyPair() (privateKey []byte, publicKey []byte, err error) {
+	privateKey = make([]byte, curve25519.ScalarSize)
+	if _, err = rand.Read(privateKey); err != nil {
+		return nil, nil, err
+	}
+	publicKey, err = curve25519.X25519(privateKey, curve25519.Basepoint)
+	return privateKey, publicKey, err
+}
+
+// Derives the session key from the private key of one side and the public key of the other side. The first 32 bytes
+// of the HKDF output encrypt the packets sent by channeld, and the next 32 bytes the packets sent to channeld.
+func NewSessionKey(id uint32, privateKey []byte, peerPublicKey []byte, isChanneld bool) (*SessionKey, error) {
+	secret, err := curve25519.X25519(privateKey, peerPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	info := make([]byte, len(sessionKeyInfo)+4)
+	copy(info, sessionKeyInfo)
+	binary.BigEndian.PutUint32(info[len(sessionKeyInfo):], id)
+	keys := make([]byte, 64)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, nil, info), keys); err != nil {
+		return nil, err
+	}
+	fromChanneld, err := newGCM(keys[:32])
+	if err != nil {
+		return nil, err
+	}
+	toChanneld, err := newGCM(keys[32:])
+	if err != nil {
+		return nil, err
+	}
+	if isChanneld {
+		return &SessionKey{Id: id, sealAead: fromChanneld, openAead: toChanneld}, nil
+	}
+	return &SessionKey{Id: id, sealAead: toChanneld, openAead: fromChanneld}, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Appends the encrypted packet body to dst and returns the updated slice.
+func (k *SessionKey) Seal(dst []byte, body []byte) []byte {
+	var tmp [binary.MaxVarintLen32 + sessionKeyNonceSize]byte
+	n := binary.PutUvarint(tmp[:], uint64(k.Id))
+	k.nonce++
+	binary.BigEndian.PutUint64(tmp[n+sessionKeyNonceSize-8:], k.nonce)
+	dst = append(dst, tmp[:n+sessionKeyNonceSize]...)
+	return k.sealAead.Seal(dst, dst[len(dst)-sessionKeyNonceSize:], body, nil)
+}
+
+// Decrypts the body of the packet with WireFlag_Encrypted, by the session key of the id in the body. The packets
+// sealed with the same key should be opened in order, in the same goroutine.
+func OpenEncryptedPacket(body []byte, keys map[uint32]*SessionKey) ([]byte, error) {
+	id, n := binary.Uvarint(body)
+	if n <= 0 || len(body) < n+sessionKeyNonceSize {
+		return nil, ErrInvalidEncryptedPacket
+	}
+	key, exists := keys[uint32(id)]
+	if !exists {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownSessionKey, id)
+	}
+	nonce := body[n : n+sessionKeyNonceSize]
+	counter := binary.BigEndian.Uint64(nonce[sessionKeyNonceSize-8:])
+	if counter <= key.openNonce {
+		return nil, fmt.Errorf("%w: replayed nonce %d", ErrInvalidEncryptedPacket, counter)
+	}
+	opened, err := key.openAead.Open(nil, nonce, body[n+sessionKeyNonceSize:], nil)
+	if err != nil {
+		return nil, err
+	}
+	key.openNonce = counter
+	return opened, nil
+}
+
+// The encryption of the packets that channeld sends to the connection.
+type connectionSession struct {
+	peerPublicKey []byte
+	key           *SessionKey
+	rotateTime    time.Time
+}
+
+// Prepares the session key 0 for the connection that requests the encryption, and returns channeld's public key for the
+// AuthResultMessage. The packets after the one that carries the AuthResultMessage are encrypted. Returns nil if the
+// connection can't be encrypted.
+func (c *Connection) startSession(peerPublicKey []byte) []byte {
+	if c.wireVersion != WireVersion_V2 {
+		c.Logger().Warn("the encryption is only supported in the v2 wire format", zap.Uint8("wireVersion", uint8(c.wireVersion)))
+		return nil
+	}
+	privateKey, publicKey, err := GenerateSessionKeyPair()
+	if err != nil {
+		c.Logger().Error("failed to generate the session key pair", zap.Error(err))
+		return nil
+	}
+	key, err := NewSessionKey(0, privateKey, peerPublicKey, true)
+	if err != nil {
+		c.Logger().Warn("failed to derive the session key", zap.Error(err))
+		return nil
+	}
+	c.pendingSession.Store(&connectionSession{peerPublicKey: peerPublicKey, key: key})
+	// The connection encrypts its packets as soon as it receives the AuthResultMessage
+	c.receiveSessionKeys.Store(map[uint32]*SessionKey{0: key})
+	atomic.StoreUint32(&c.encrypted, 1)
+	return publicKey
+}
+
+// Returns the session prepared by startSession(), when the AuthResultMessage is being flushed. Should be called in the
+// flush goroutine.
+func (c *Connection) takePendingSession(now time.Time) *connectionSession {
+	s, _ := c.pendingSession.Load().(*connectionSession)
+	if s == nil {
+		return nil
+	}
+	c.pendingSession.Store((*connectionSession)(nil))
+	s.rotateTime = now.Add(time.Duration(GlobalSettings.SessionKeyRotationIntervalMs) * time.Millisecond)
+	return s
+}
+
+// Adds the SessionKeyRotationMessage to the packet if the session key is due to rotate, and returns the session with
+// the new key, which is used after the packet is sealed with the current key. Should be called in the flush goroutine.
+func (c *Connection) rotateSessionKey(p *channeldpb.Packet, now time.Time) *connectionSession {
+	s := c.session
+	if s == nil || GlobalSettings.SessionKeyRotationIntervalMs <= 0 || now.Before(s.rotateTime) {
+		return nil
+	}
+	privateKey, publicKey, err := GenerateSessionKeyPair()
+	if err != nil {
+		c.Logger().Error("failed to generate the session key pair", zap.Error(err))
+		return nil
+	}
+	key, err := NewSessionKey(s.key.Id+1, privateKey, s.peerPublicKey, true)
+	if err != nil {
+		c.Logger().Error("failed to derive the session key", zap.Error(err))
+		return nil
+	}
+	body, err := proto.Marshal(&channeldpb.SessionKeyRotationMessage{KeyId: key.Id, PublicKey: publicKey})
+	if err != nil {
+		c.Logger().Error("failed to marshal the session key rotation", zap.Error(err))
+		return nil
+	}
+	p.Messages = append(p.Messages, &channeldpb.MessagePack{
+		ChannelId: uint32(GlobalChannelId),
+		MsgType:   uint32(channeldpb.MessageType_SESSION_KEY_ROTATION),
+		MsgBody:   body,
+	})
+	// The connection keeps encrypting with the current key until it receives the rotation
+	c.receiveSessionKeys.Store(map[uint32]*SessionKey{s.key.Id: s.key, key.Id: key})
+	c.Logger().Debug("rotating the session key", zap.Uint32("keyId", key.Id))
+	return &connectionSession{
+		peerPublicKey: s.peerPublicKey,
+		key:           key,
+		rotateTime:    now.Add(time.Duration(GlobalSettings.SessionKeyRotationIntervalMs) * time.Millisecond),
+	}
+}
+
+// The bytes to reserve in the packet body for the encryption
+func (c *Connection) encryptionOverhead() int {
+	if atomic.LoadUint32(&c.encrypted) != 0 {
+		return SessionKeyOverhead
+	}
+	return 0
+}
+
+// Decrypts the body of the packet sent by the connection. Should be called in the receive goroutine.
+func (c *Connection) openPacket(body []byte) ([]byte, error) {
+	keys, _ := c.receiveSessionKeys.Load().(map[uint32]*SessionKey)
+	if keys == nil {
+		return nil, fmt.Errorf("%w: the encryption is not requested", ErrUnsupportedWireFlags)
+	}
+	return OpenEncryptedPacket(body, keys)
+}
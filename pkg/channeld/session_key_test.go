@@ -0,0 +1,153 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSessionKey(t *testing.T) {
+	clientPrivateKey, clientPublicKey, err := GenerateSessionKeyPair()
+	assert.NoError(t, err)
+	serverPrivateKey, serverPublicKey, err := GenerateSessionKeyPair()
+	assert.NoError(t, err)
+
+	serverKey, err := NewSessionKey(3, serverPrivateKey, clientPublicKey, true)
+	assert.NoError(t, err)
+	clientKey, err := NewSessionKey(3, clientPrivateKey, serverPublicKey, false)
+	assert.NoError(t, err)
+
+	sealed := serverKey.Seal(nil, []byte("hello"))
+	// A new nonce for every packet
+	next := serverKey.Seal(nil, []byte("hello"))
+	assert.NotEqual(t, sealed, next)
+	body, err := OpenEncryptedPacket(sealed, map[uint32]*SessionKey{3: clientKey})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hello"), body)
+	// The replayed packet is refused
+	_, err = OpenEncryptedPacket(sealed, map[uint32]*SessionKey{3: clientKey})
+	assert.ErrorIs(t, err, ErrInvalidEncryptedPacket)
+	body, err = OpenEncryptedPacket(next, map[uint32]*SessionKey{3: clientKey})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hello"), body)
+
+	// The other direction has its own key, so the packet can't be reflected to the sender
+	sealed = clientKey.Seal(nil, []byte("world"))
+	_, err = OpenEncryptedPacket(sealed, map[uint32]*SessionKey{3: clientKey})
+	assert.Error(t, err)
+	body, err = OpenEncryptedPacket(sealed, map[uint32]*SessionKey{3: serverKey})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("world"), body)
+
+	sealed = serverKey.Seal(nil, []byte("hello"))
+
+	_, err = OpenEncryptedPacket(sealed, map[uint32]*SessionKey{4: clientKey})
+	assert.ErrorIs(t, err, ErrUnknownSessionKey)
+	// Each key id derives a different key
+	otherKey, _ := NewSessionKey(4, clientPrivateKey, serverPublicKey, false)
+	_, err = OpenEncryptedPacket(sealed, map[uint32]*SessionKey{3: otherKey})
+	assert.Error(t, err)
+	sealed[len(sealed)-1]++
+	_, err = OpenEncryptedPacket(sealed, map[uint32]*SessionKey{3: clientKey})
+	assert.Error(t, err)
+	_, err = OpenEncryptedPacket([]byte{3}, map[uint32]*SessionKey{3: clientKey})
+	assert.ErrorIs(t, err, ErrInvalidEncryptedPacket)
+}
+
+func TestSessionKeyRotation(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	defer func(interval int64) { GlobalSettings.SessionKeyRotationIntervalMs = interval }(GlobalSettings.SessionKeyRotationIntervalMs)
+	GlobalSettings.SessionKeyRotationIntervalMs = 600000
+
+	clientPrivateKey, clientPublicKey, _ := GenerateSessionKeyPair()
+
+	// Only supported in the v2 wire format
+	assert.Nil(t, newLoopbackConnection(channeldpb.CompressionType_NO_COMPRESSION).startSession(clientPublicKey))
+
+	c := newLoopbackConnection(channeldpb.CompressionType_SNAPPY)
+	c.wireVersion = WireVersion_V2
+	serverPublicKey := c.startSession(clientPublicKey)
+	if !assert.NotNil(t, serverPublicKey) {
+		return
+	}
+	key0, _ := NewSessionKey(0, clientPrivateKey, serverPublicKey, false)
+	keys := map[uint32]*SessionKey{0: key0}
+
+	// Flushes the message and returns the messages in the packet, and whether the packet is encrypted.
+	flush := func(msgType channeldpb.MessageType) ([]*channeldpb.MessagePack, bool) {
+		c.sendQueues[0] <- &channeldpb.MessagePack{MsgType: uint32(msgType), MsgBody: []byte{1, 2, 3}}
+		c.flush()
+		packet := c.conn.(*loopbackConn).packet
+		header, _, err := ReadPacketHeader(packet)
+		assert.NoError(t, err)
+		body := packet[header.HeaderSize:]
+		encrypted := header.Flags&WireFlag_Encrypted != 0
+		if encrypted {
+			body, err = OpenEncryptedPacket(body, keys)
+			if !assert.NoError(t, err) {
+				return nil, encrypted
+			}
+		}
+		body, _ = snappy.Decode(nil, body)
+		var p channeldpb.Packet
+		if header.Flags&WireFlag_Batched != 0 {
+			assert.NoError(t, proto.Unmarshal(body, &p))
+		} else {
+			mp := &channeldpb.MessagePack{}
+			assert.NoError(t, proto.Unmarshal(body, mp))
+			p.Messages = append(p.Messages, mp)
+		}
+		return p.Messages, encrypted
+	}
+
+	// The packet that carries the AuthResultMessage is not encrypted
+	mps, encrypted := flush(channeldpb.MessageType_AUTH)
+	assert.False(t, encrypted)
+	assert.Len(t, mps, 1)
+	_, encrypted = flush(channeldpb.MessageType_USER_SPACE_START)
+	assert.True(t, encrypted)
+
+	// The rotation is sent with the current key
+	c.session.rotateTime = time.Now()
+	mps, encrypted = flush(channeldpb.MessageType_USER_SPACE_START)
+	assert.True(t, encrypted)
+	if assert.Len(t, mps, 2) && assert.EqualValues(t, channeldpb.MessageType_SESSION_KEY_ROTATION, mps[0].MsgType) {
+		rotation := &channeldpb.SessionKeyRotationMessage{}
+		assert.NoError(t, proto.Unmarshal(mps[0].MsgBody, rotation))
+		assert.EqualValues(t, 1, rotation.KeyId)
+		keys[1], _ = NewSessionKey(1, clientPrivateKey, rotation.PublicKey, false)
+	}
+
+	// The following packets are encrypted with the new key
+	delete(keys, 0)
+	mps, encrypted = flush(channeldpb.MessageType_USER_SPACE_START)
+	assert.True(t, encrypted)
+	assert.Len(t, mps, 1)
+	assert.True(t, c.session.rotateTime.After(time.Now()))
+
+	// The packets sent to channeld are encrypted with the same keys. Returns the error of reading the packet.
+	receive := func(c *Connection, key *SessionKey) error {
+		body, _ := proto.Marshal(&channeldpb.MessagePack{ChannelId: 100, MsgType: uint32(channeldpb.MessageType_USER_SPACE_START)})
+		buf := key.Seal(make([]byte, MaxPacketHeaderSize), body)
+		c.readPos = copy(c.readBuffer, PutPacketHeader(buf, len(buf)-MaxPacketHeaderSize, WireVersion_V2, WireFlag_Encrypted))
+		bufPos := 0
+		_, err := c.readPacket(&bufPos)
+		return err
+	}
+	// The connection may still use the previous key before it receives the rotation
+	assert.NoError(t, receive(c, key0))
+	assert.NoError(t, receive(c, keys[1]))
+	// The replayed packet is refused
+	key0.nonce--
+	assert.ErrorIs(t, receive(c, key0), ErrInvalidEncryptedPacket)
+
+	// The connection that hasn't requested the encryption
+	other := newLoopbackConnection(channeldpb.CompressionType_NO_COMPRESSION)
+	other.wireVersion = WireVersion_V2
+	assert.ErrorIs(t, receive(other, keys[1]), ErrUnsupportedWireFlags)
+}
@@ -20,18 +20,84 @@ type GlobalSettingsType struct {
 	ProfileOption func(*profile.Profile)
 	ProfilePath   string
 
+	// The address of the admin port, which serves the metrics and the debug endpoints. See NewAdminHandler().
+	AdminAddress string
+	// Expose net/http/pprof on the admin port
+	EnablePprof bool
+	// Expose the network condition emulation on the admin port. See HandleNetworkConditions().
+	EnableNetem bool
+	// Optional. If set, the debug endpoints on the admin port require the token.
+	AdminAuthToken string
+	// Optional. Pushes the metrics to the backend that doesn't scrape the admin port, e.g. MetricsExporter_DogStatsd.
+	// See StartMetricsExporter().
+	MetricsExporter         string
+	MetricsExportIntervalMs uint32
+	StatsdAddress           string
+	// Prepended to the metric names, e.g. "channeld."
+	StatsdPrefix string
+
 	ServerNetwork         string
 	ServerAddress         string
 	ServerReadBufferSize  int
 	ServerWriteBufferSize int
 	ServerFSM             string
 	ServerBypassAuth      bool
+	// The max total size of the chunked messages being reassembled at the same time, per server connection.
+	ServerMaxPendingChunkedBytes int
 
 	ClientNetwork         string
 	ClientAddress         string
 	ClientReadBufferSize  int
 	ClientWriteBufferSize int
 	ClientFSM             string
+	// The max total size of the chunked messages being reassembled at the same time, per client connection.
+	// Much smaller than the server's, as there can be many more client connections, and they are not trusted.
+	ClientMaxPendingChunkedBytes int
+	// If true, the client connections are accepted from the L4 load balancer (e.g. HAProxy or AWS NLB) with the PROXY protocol header.
+	// See ListenOptions.ProxyProtocol.
+	ClientProxyProtocol bool
+
+	// Optional. If set, listens for the OPERATOR connections. See SetOperatorAuthProvider.
+	OperatorNetwork string
+	OperatorAddress string
+	OperatorFSM     string
+
+	// Optional. If set, replaces the listeners above, e.g. to listen for the SERVER connections on the internal interface only,
+	// or for the CLIENT connections on both TCP and WebSocket. See StartListeners().
+	Listeners []ListenerSettings
+
+	// If set, runs as an edge relay: the client connections are forwarded to the central channeld's server address
+	// over a single link. See the relay package.
+	RelayCentralAddress string
+
+	// Optional. The path of the SQLite database that archives the broadcasts. See ChannelSettingsType.ArchiveBroadcasts.
+	MessageArchivePath string
+	// Optional. The path of the SQLite database that persists the ban list. The bans are kept in memory if not set. See Ban().
+	BanStorePath string
+
+	// Optional. The path of the word list file for the content filter. See LoadWordListContentFilter().
+	ContentFilterWordList string
+	// If true, the messages that match the word list are rejected rather than masked.
+	ContentFilterReject bool
+
+	// Optional. Where the events of channeldpb.AnalyticsMessage are written: an HTTP endpoint (http:// or https://), or a file path.
+	// See InitAnalytics().
+	AnalyticsSink            string
+	AnalyticsBatchSize       int
+	AnalyticsFlushIntervalMs uint32
+
+	// Optional. The directory of the scripts that register the message handlers, validators and broadcast filters.
+	// The ".lua" scripts are run by LuaScriptEngine. The other scripts are loaded by the engines registered with
+	// RegisterScriptEngine(). See InitScripts().
+	ScriptDir string
+	// How often the scripts are checked for the hot reload. 0 means no hot reload.
+	ScriptReloadIntervalMs uint32
+	// How long a call of the Lua script (a hook, or running the script when loading) can take before it's aborted, so a
+	// script that doesn't return can't hang the goroutine of the channel. 0 means DefaultScriptTimeoutMs.
+	ScriptTimeoutMs uint32
+
+	// Optional. The comma-separated paths of the plugin binaries that provide the extensions over RPC. See plugin.Load().
+	Plugins string
 
 	CompressionType channeldpb.CompressionType
 
@@ -40,22 +106,132 @@ type GlobalSettingsType struct {
 	ConnectionAuthTimeoutMs int64
 	MaxFailedAuthAttempts   int
 	MaxFsmDisallowed        int
+	// How long an authenticated client connection can stay without any traffic before it's pinged. 0 means no limit.
+	// See channeldpb.PingMessage.
+	ConnectionIdleTimeoutMs int64
+	// How long the idle client connection has to reply to the ping before it's closed.
+	ConnectionIdlePingTimeoutMs int64
+	// How often the session key is rotated for the connections that have requested the encryption. 0 means never.
+	// See channeldpb.SessionKeyRotationMessage.
+	SessionKeyRotationIntervalMs int64
+	// How to handle a client connection logging in with the user id of another client connection. See UserStore.
+	// See SetDuplicateLoginResolver() for deciding per login.
+	DuplicateLoginPolicy DuplicateLoginPolicy
+	// If true, channeld tracks the online status and the current channel of the authenticated clients,
+	// for the GLOBAL channel subscribers with channeldpb.ChannelSubscriptionOptions.Presence enabled.
+	EnablePresence bool
 
 	SpatialControllerConfig NullableString
 	SpatialChannelIdStart   common.ChannelId
 	EntityChannelIdStart    common.ChannelId
+	// How long the id of a removed channel can't be reused. 0 means the id can be reused immediately.
+	ChannelIdQuarantineMs uint32
 
 	ChannelSettings map[channeldpb.ChannelType]ChannelSettingsType
+	// The overrides for the tenants, by the tenant name. See TenantTag.
+	TenantSettings map[string]TenantSettingsType
 
 	EnableRecordPacket bool
 
 	ReplaySessionPersistenceDir string
 }
 
+type TenantSettingsType struct {
+	// Overrides the whole ChannelSettingsType of the channel type, for the channels of the tenant, including the ACLSettings.
+	ChannelSettings map[channeldpb.ChannelType]ChannelSettingsType
+	// The quotas of the tenant. 0 means no limit. See channeldpb.QuotaExceededMessage.
+	// The spatial and entity channels are counted, but not limited, as they are created by the spatial controller.
+	MaxChannels        int
+	MaxChannelsPerType map[channeldpb.ChannelType]int
+	// The authentication of the connection fails with channeldpb.AuthResultMessage_QUOTA_EXCEEDED when exceeded.
+	MaxConnections int
+	// The aggregate bytes per second that the connections of the tenant send to channeld. The packets are dropped when exceeded.
+	MaxBytesPerSecond int64
+}
+
 type ACLSettingsType struct {
 	Sub    ChannelAccessLevel
 	Unsub  ChannelAccessLevel
 	Remove ChannelAccessLevel
+	// Optional. The connection must have all the tags to subscribe to the channel, e.g. {"platform": "ios"}
+	SubRequiredTags map[string]string
+}
+
+// The chat-specific flood protection of the user-space broadcasts. A user that violates the policies is muted in the channels
+// that have the protection enabled, and the mute gets longer as the violations repeat. See channeldpb.ChatPenaltyMessage.
+type ChatFloodSettingsType struct {
+	// How many identical payloads a user can broadcast in RepeatWindowMs. 0 means no detection.
+	MaxRepeats int
+	// 0 means using the default value (DefaultChatRepeatWindowMs).
+	RepeatWindowMs uint32
+	// How many mentions a broadcast can have. 0 means no detection.
+	MaxMentions int
+	// The regular expression that matches a mention in the payload. Empty means using the default value (DefaultChatMentionPattern).
+	MentionPattern string
+	// The mute duration of each penalty level. The last one is used for the higher levels.
+	// Empty means using the default value (DefaultChatMuteDurationsMs).
+	MuteDurationsMs []uint32
+	// The penalty level is reset if the user has no violation in the duration. 0 means using the default value (DefaultChatPenaltyResetMs).
+	PenaltyResetMs uint32
+}
+
+func (s *ChatFloodSettingsType) Enabled() bool {
+	return s.MaxRepeats > 0 || s.MaxMentions > 0
+}
+
+// Maintains a top-level map field of the channel data as a sorted leaderboard. The map field is not fanned out;
+// the subscribers fetch the windows of the leaderboard with channeldpb.FetchLeaderboardMessage instead.
+type LeaderboardSettingsType struct {
+	// The name of the top-level map field in the channel data, e.g. 'scores'. Empty means no leaderboard.
+	MapField string
+	// The name of the numeric field in the map value message that the entries are sorted by.
+	// Empty if the map value itself is numeric.
+	ScoreField string
+	// If true, the lower score ranks higher, e.g. the finish time of a race. The ties are ranked by the key.
+	Ascending bool
+	// The max number of the entries in the leaderboard. The lowest ranked entries are removed from the map when exceeded.
+	// 0 means no limit.
+	TopN int
+}
+
+// Leaves out the position updates of the entities that the subscribers can extrapolate, i.e. the stable movers, from the fan-outs.
+// The entities are the map values of ChannelSettingsType.EntityMapField, or the channel data itself if it's not set.
+// The vector fields should have the float or double x, y and z fields, e.g. unrealpb.FVector.
+type DeadReckoningSettingsType struct {
+	// The names of the vector fields in the entity. The velocity and the acceleration are in the units per second.
+	PositionField string
+	VelocityField string
+	// Optional. The acceleration is regarded as zero if not set.
+	AccelerationField string
+	// The max distance between the position extrapolated by the subscribers and the actual one. 0 means no dead reckoning.
+	MaxError float64
+	// The position is always fanned out if it has been extrapolated for longer. 0 means using the default value (DefaultMaxExtrapolationMs).
+	MaxExtrapolationMs uint32
+}
+
+func (s *DeadReckoningSettingsType) Enabled() bool {
+	return s.PositionField != "" && s.VelocityField != "" && s.MaxError > 0
+}
+
+// Mirrors the updates of the channel data into a shadow copy that is merged in an alternative way, and logs when the two diverge.
+// For validating the changes of the merge logic in production. Not applied to the CRDT channel data.
+type ShadowMergeSettingsType struct {
+	Enabled bool
+	// Optional. The merge options of the shadow copy, instead of the channel's.
+	MergeOptions *channeldpb.ChannelDataMergeOptions
+	// If true, the shadow copy is merged with ReflectMerge(), even if the channel data implements MergeableChannelData.
+	// Ignored if the channel type has a shadow merge function. See RegisterShadowMergeFunc().
+	UseReflectMerge bool
+	// How many updates are merged between the comparisons. 0 means comparing after every update.
+	CompareInterval int
+}
+
+type DataCheckpointSettingsType struct {
+	// How often the channel data is checkpointed. 0 means never. See SetDataCheckpointSink().
+	IntervalMs uint32
+	// The paths of the channel data fields, by the column names, e.g. {"score": "score", "boss_hp": "entities.1.hp"}.
+	// The map entries are addressed by the key, and the list elements by the index. The message, list and map fields are in JSON.
+	Columns map[string]string
 }
 
 type ChannelSettingsType struct {
@@ -63,28 +239,156 @@ type ChannelSettingsType struct {
 	DefaultFanOutIntervalMs        uint32
 	DefaultFanOutDelayMs           int32
 	RemoveChannelAfterOwnerRemoved bool
-	ACLSettings                    ACLSettingsType
+	// What happens to the channel when the connection of its owner drops. Ignored if RemoveChannelAfterOwnerRemoved is true.
+	OwnerDisconnectPolicy OwnerDisconnectPolicy
+	// How long the frozen channel waits for the owner to reclaim it. 0 means using the default value (DefaultOwnerReclaimGracePeriodMs).
+	OwnerReclaimGracePeriodMs uint32
+	// How many client messages to the owner are buffered while the channel is frozen, for the owner to receive after reclaiming
+	// the channel. 0 means no buffering. See channeldpb.AuthMessage.ResumeToken.
+	OwnerReclaimBufferSize int
+	ACLSettings            ACLSettingsType
 	// Optinal. The full name of the Protobuf message type for the channel data (including the package name)
 	DataMsgFullName string
+	// How many channel data update messages are buffered for the fan-out. The older messages are coalesced when exceeded.
+	// 0 means using the default value (MaxUpdateMsgBufferSize).
+	MaxUpdateMsgBufferSize int
+	// How many user-space broadcasts are retained for the reconnected subscribers to resume from. 0 means no retention.
+	BroadcastRetentionSize int
+	// If true, the user-space broadcasts are saved to the message archive (see SetMessageArchive) with the sender and the time,
+	// e.g. for the chat channels. The archive replaces the retained broadcasts for the resumed subscribers,
+	// and the history can be fetched with channeldpb.FetchArchiveMessage.
+	ArchiveBroadcasts bool
+	// If true, the payloads of the user-space broadcasts are checked by the content filter, which can rewrite or reject them.
+	// See SetContentFilter().
+	FilterContent bool
+	// Optional. Mutes the users that flood the channel with the user-space broadcasts.
+	ChatFlood ChatFloodSettingsType
+	// Optional. See LeaderboardSettingsType.
+	Leaderboard LeaderboardSettingsType
+	// The name of the top-level map field in the channel data that holds the entities by the entity id, e.g. 'entities'.
+	// Required by the fan-out budget. See RegisterEntityRelevancyFunc().
+	EntityMapField string
+	// The default of channeldpb.ChannelSubscriptionOptions.FanOutBudgetBytes. 0 means no budget.
+	FanOutBudgetBytes uint32
+	// Optional. See DeadReckoningSettingsType.
+	DeadReckoning DeadReckoningSettingsType
+	// The current schema version of the channel data. The data in an older version, e.g. from the servers that haven't been upgraded,
+	// is migrated on load. See RegisterChannelDataMigration().
+	DataSchemaVersion uint32
+	// Optional. See ShadowMergeSettingsType.
+	ShadowMerge ShadowMergeSettingsType
+	// If true, the data updates handled in a tick are merged at the end of the tick, ordered by the sender's connection id,
+	// then by the order they're sent. So the merged channel data only depends on the updates of each sender in the tick,
+	// not on how they interleave when arriving from the different writers, e.g. for the replay and the shadow channels.
+	// The updates are not visible to the other messages handled in the same tick, e.g. QUERY_CHANNEL_DATA. The base version,
+	// the locks and the transactions are checked against the channel data when merging.
+	DeterministicMerge bool
+	// Optional. See DataCheckpointSettingsType.
+	DataCheckpoint DataCheckpointSettingsType
+	// If true, the channel of this type is created when the first connection subscribes to a non-existing channelId.
+	// The channel data is created from the registered data type. See RegisterChannelDataType().
+	AutoCreateOnSub bool
+	// Decides the owner of the auto-created channel.
+	AutoCreateOwnerPolicy ChannelOwnerPolicy
+	// If true, the goroutine of each channel of this type is locked to its own OS thread, whose id is logged for pinning it to
+	// the CPUs (e.g. with taskset). Meant for the few heavy channels, e.g. the main world or SUBWORLD channels.
+	DedicatedThread bool
+	// The share of the tick interval that the channel can spend handling the messages in a tick, before deferring the rest to
+	// the next tick. 0 means 1, i.e. the whole tick interval. A lower weight makes the busy channels yield the CPU earlier.
+	SchedulingWeight float64
+	// The tags that the SERVER connections must have to be on the hash ring, e.g. {"role": "room"}. All the servers are on
+	// the ring if empty. See ChannelOwnerPolicy_ConsistentHash.
+	OwnerHashServerTags map[string]string
+	// The top-level fields of the channel data that are checked for the conflicts, when the update message has the BaseVersion.
+	// The map fields are checked per entry. See channeldpb.ChannelDataUpdateMessage.BaseVersion.
+	VersionedFields []string
+	// How many entries truncated from the top of each list field are retained for channeldpb.FetchHistoryMessage. 0 means no retention.
+	ListHistorySize int
+	// How often the fan-out carries the checksum of the channel data, for the subscribers to detect the divergence. 0 means never.
+	// See channeldpb.ChannelDataUpdateMessage.Checksum.
+	ChecksumIntervalMs uint32
+	// The deadline of handling a message, or fanning out the channel data, in the channel's tick. When exceeded,
+	// a warning with the stack of the channel goroutine is logged. 0 means no deadline.
+	HandlingDeadlineMs uint32
+	// How often the channel data is snapshotted, for restoring after the channel recovers from a panic. 0 means no snapshot,
+	// and the channel data is kept as it was when the panic happened.
+	DataSnapshotIntervalMs uint32
+	// How many times the channel can recover from a panic before it's removed. 0 means always recovering.
+	// The GLOBAL channel is never removed.
+	MaxPanicRestarts int
+	// How many ticks the channel data lane of a subscriber's send queue can stay full before its fan-out is downgraded:
+	// the fan-out interval is doubled up to SlowSubscriberMaxFanOutIntervalMs, then the keyframe-only mode is switched on.
+	// The channel owner is notified with channeldpb.SlowSubscriberMessage. 0 means no detection.
+	SlowSubscriberTicks int
+	// 0 means using the default value (DefaultSlowSubscriberMaxFanOutIntervalMs).
+	SlowSubscriberMaxFanOutIntervalMs uint32
+	// How many messages can be queued in the channel's inbox before handled. The messages from the connections are dropped
+	// when the inbox is full. 0 means using the default value (DefaultInboxSize).
+	InboxSize int
+	// How many messages of a single sender can be pending in the channel. The exceeded are dropped. 0 means no limit.
+	SenderInboxQuota int
+	// How many messages of a single sender can be handled in a tick. The left are deferred to the next tick,
+	// so the other senders get their fair share. 0 means no limit.
+	SenderMessagesPerTick int
+	// How long the channel can stay without any subscriber or message from the owner before it's removed. 0 means never.
+	// The channel data can be persisted before the removal. See SetIdleChannelPersister() and Event_ChannelIdle.
+	// The GLOBAL channel is never removed.
+	IdleTimeoutMs uint32
+	// How often the channel owner receives channeldpb.ChannelStatsMessage. 0 means never. The statistics are always available
+	// in the admin API. See HandleChannelStats().
+	StatsPushIntervalMs uint32
+	// If true, the channels of the type are reported by the health check. The GLOBAL channel is always reported.
+	// See HandleChannelHealth().
+	HealthCheck bool
+	// The channel is reported degraded when its tick lags behind more than HealthMaxTickLagMs, or it has more than
+	// HealthMaxErrorsPerSec errors (the failed messages and the panics). 0 means using the default values
+	// (DefaultHealthMaxTickLagMs and DefaultHealthMaxErrorsPerSec).
+	HealthMaxTickLagMs    uint32
+	HealthMaxErrorsPerSec float64
+	// The channel is lagging when its actual tick interval exceeds TickIntervalMs by more than TickLagThresholdMs
+	// for TickLagTicks ticks in a row. A warning is logged, and the owner is notified with channeldpb.ChannelTickLagMessage
+	// if NotifyOwnerOfTickLag is true. 0 means no detection.
+	TickLagThresholdMs uint32
+	// 0 means using the default value (DefaultTickLagTicks).
+	TickLagTicks         int
+	NotifyOwnerOfTickLag bool
+	// How many replicas to spawn for each channel of the type, to spread the read-only client subscriptions over.
+	// 0 means no replica. See Channel.SpawnReplica().
+	FanOutReplicas int
+	// The topic of the external broker that the data updates and the user-space messages of each channel of the type
+	// are published to. "{channelId}" in the topic is replaced by the id of the channel. Empty means no bridging.
+	// If BrokerInbound is true, the messages of the topic are injected back into the channel. See SetBroker().
+	BrokerTopic   string
+	BrokerInbound bool
 }
 
 var GlobalSettings = GlobalSettingsType{
-	LogLevel:              &NullableInt{},
-	LogFile:               &NullableString{},
-	ServerReadBufferSize:  0x0001ffff,
-	ServerWriteBufferSize: 256,
-	ServerFSM:             "config/server_authoratative_fsm.json",
-	ClientReadBufferSize:  0x0001ffff,
-	ClientWriteBufferSize: 512,
-	ClientFSM:             "config/client_non_authoratative_fsm.json",
-	CompressionType:       channeldpb.CompressionType_NO_COMPRESSION,
+	LogLevel:                     &NullableInt{},
+	LogFile:                      &NullableString{},
+	AdminAddress:                 ":8080",
+	StatsdAddress:                "127.0.0.1:8125",
+	StatsdPrefix:                 "channeld.",
+	ServerReadBufferSize:         0x0001ffff,
+	ServerWriteBufferSize:        256,
+	ServerFSM:                    "config/server_authoratative_fsm.json",
+	ServerMaxPendingChunkedBytes: DefaultMaxPendingChunkedBytes,
+	ClientReadBufferSize:         0x0001ffff,
+	ClientWriteBufferSize:        512,
+	ClientFSM:                    "config/client_non_authoratative_fsm.json",
+	ClientMaxPendingChunkedBytes: 4 * 1024 * 1024,
+	OperatorFSM:                  "config/operator_fsm.json",
+	CompressionType:              channeldpb.CompressionType_NO_COMPRESSION,
 	// Mirror uses int32 as the connId
-	MaxConnectionIdBits:     31,
-	ConnectionAuthTimeoutMs: 5000,
-	MaxFailedAuthAttempts:   5,
-	MaxFsmDisallowed:        10,
-	SpatialChannelIdStart:   0x00010000,
-	EntityChannelIdStart:    0x00080000,
+	MaxConnectionIdBits:         31,
+	ConnectionAuthTimeoutMs:     5000,
+	ConnectionIdlePingTimeoutMs: 5000,
+	// 10 minutes
+	SessionKeyRotationIntervalMs: 600000,
+	MaxFailedAuthAttempts:        5,
+	MaxFsmDisallowed:             10,
+	SpatialChannelIdStart:        0x00010000,
+	EntityChannelIdStart:         0x00080000,
+	ChannelIdQuarantineMs:        10000,
 	ChannelSettings: map[channeldpb.ChannelType]ChannelSettingsType{
 		channeldpb.ChannelType_GLOBAL: {
 			TickIntervalMs:                 10,
@@ -152,20 +456,48 @@ func (s *GlobalSettingsType) ParseFlag() error {
 	})
 	flag.StringVar(&s.ProfilePath, "profilepath", "profiles", "the path to store the profile output files")
 
-	flag.StringVar(&s.ServerNetwork, "sn", "tcp", "the network type for the server connections")
+	flag.StringVar(&s.AdminAddress, "aa", s.AdminAddress, "the network address for the admin port (metrics and debug endpoints)")
+	flag.BoolVar(&s.EnablePprof, "pprof", false, "expose net/http/pprof on the admin port")
+	flag.BoolVar(&s.EnableNetem, "netem", false, "expose the network condition emulation (latency, jitter, loss, bandwidth) of the connections on the admin port")
+	flag.StringVar(&s.AdminAuthToken, "aat", "", "the token required by the debug endpoints on the admin port, in the 'Authorization: Bearer <token>' header. Empty means no auth.")
+	flag.StringVar(&s.MetricsExporter, "me", "", "the exporter that pushes the metrics besides Prometheus: statsd, dogstatsd. Empty means Prometheus only")
+	flag.StringVar(&s.StatsdAddress, "sda", s.StatsdAddress, "the UDP address of the StatsD/DogStatsD agent")
+	flag.StringVar(&s.StatsdPrefix, "sdp", s.StatsdPrefix, "the prefix of the metric names sent to StatsD/DogStatsD")
+	mei := flag.Uint("mei", uint(s.MetricsExportIntervalMs), "the interval of pushing the metrics to the exporter. Default is 10000.")
+
+	flag.StringVar(&s.ServerNetwork, "sn", "tcp", "the network type for the server connections. The suffix 4 or 6 restricts the address family, e.g. tcp6")
 	flag.StringVar(&s.ServerAddress, "sa", ":11288", "the network address for the server connections")
 	flag.IntVar(&s.ServerReadBufferSize, "srb", s.ServerReadBufferSize, "the read buffer size for the server connections")
 	flag.IntVar(&s.ServerWriteBufferSize, "swb", s.ServerWriteBufferSize, "the write buffer size for the server connections")
 	flag.StringVar(&s.ServerFSM, "sfsm", s.ServerFSM, "the path to the server FSM config")
 	flag.BoolVar(&s.ServerBypassAuth, "sba", true, "should server bypasses the authentication?")
+	flag.IntVar(&s.ServerMaxPendingChunkedBytes, "smpc", s.ServerMaxPendingChunkedBytes, "the max total size of the chunked messages being reassembled, per server connection")
 
 	flag.StringVar(&s.ClientNetwork, "cn", "tcp", "the network type for the client connections")
 	flag.StringVar(&s.ClientAddress, "ca", ":12108", "the network address for the client connections")
 	flag.IntVar(&s.ClientReadBufferSize, "crb", s.ClientReadBufferSize, "the read buffer size for the client connections")
 	flag.IntVar(&s.ClientWriteBufferSize, "cwb", s.ClientWriteBufferSize, "the write buffer size for the client connections")
 	flag.StringVar(&s.ClientFSM, "cfsm", s.ClientFSM, "the path to the client FSM config")
+	flag.IntVar(&s.ClientMaxPendingChunkedBytes, "cmpc", s.ClientMaxPendingChunkedBytes, "the max total size of the chunked messages being reassembled, per client connection")
+	flag.BoolVar(&s.ClientProxyProtocol, "cpp", false, "read the PROXY protocol v1/v2 header on the client connections, for the real client addresses behind the L4 load balancer")
+	flag.StringVar(&s.OperatorNetwork, "on", "tcp", "the network type for the operator connections")
+	flag.StringVar(&s.OperatorAddress, "oa", "", "the network address for the operator connections. Empty means no operator connection is accepted")
+	flag.StringVar(&s.OperatorFSM, "ofsm", s.OperatorFSM, "the path to the operator FSM config")
+	flag.StringVar(&s.RelayCentralAddress, "rca", "", "the server address of the central channeld. If set, runs as an edge relay that forwards the client connections to it")
+	flag.StringVar(&s.MessageArchivePath, "map", "", "the path of the SQLite database to archive the broadcasts. Empty means no archive")
+	flag.StringVar(&s.BanStorePath, "bsp", "", "the path of the SQLite database to persist the ban list and its audit trail. Empty means the bans are lost on restart")
+	flag.StringVar(&s.ContentFilterWordList, "cfwl", "", "the path of the word list file for the content filter. Empty means no content filter")
+	flag.BoolVar(&s.ContentFilterReject, "cfr", false, "reject the messages that match the word list of the content filter, instead of masking the words")
+	flag.StringVar(&s.AnalyticsSink, "as", "", "the HTTP endpoint (http:// or https://) or the file path to write the client analytics events. Empty means the events are dropped")
+	flag.IntVar(&s.AnalyticsBatchSize, "abs", s.AnalyticsBatchSize, "the max number of the analytics events in a batch. Default is 100.")
+	afi := flag.Uint("afi", uint(s.AnalyticsFlushIntervalMs), "the interval of writing the batched analytics events. Default is 5000.")
+	flag.StringVar(&s.ScriptDir, "sd", "", "the directory of the scripts to load at startup. Empty means no script")
+	sri := flag.Uint("sri", uint(s.ScriptReloadIntervalMs), "the interval of checking the scripts for the hot reload. Default is 0. (0 = no hot reload)")
+	sto := flag.Uint("sto", uint(s.ScriptTimeoutMs), "the max duration of a Lua script call before it's aborted. Default is 100.")
+	flag.StringVar(&s.Plugins, "pl", "", "the comma-separated paths of the plugin binaries to launch at startup")
 
 	flag.BoolVar(&s.EnableRecordPacket, "erp", false, "enable record message packets send from clients")
+	flag.BoolVar(&s.EnablePresence, "ep", false, "enable tracking the presence of the authenticated clients")
 	flag.StringVar(&s.ReplaySessionPersistenceDir, "rspd", "", "the path to write packet recording")
 
 	// Use flag.Uint instead of flag.UintVar to avoid the default value being overwritten by the flag value
@@ -175,10 +507,17 @@ func (s *GlobalSettingsType) ParseFlag() error {
 	ecs := flag.Uint("ecs", uint(s.EntityChannelIdStart), "start ChannelId of entity channels. Default is 0x00080000.")
 	mcb := flag.Uint("mcb", uint(s.MaxConnectionIdBits), "max bits of ConnectionId (e.g. 16 means max ConnectionId = 1<<16 - 1). Up to 32.")
 	cat := flag.Uint("cat", uint(s.ConnectionAuthTimeoutMs), "the duration to allow a connection stay unauthenticated before closing it. Default is 5000. (0 = no limit)")
+	cit := flag.Uint("cit", uint(s.ConnectionIdleTimeoutMs), "the duration to allow an authenticated client connection stay without any traffic before pinging it. Default is 0. (0 = no limit)")
+	cipt := flag.Uint("cipt", uint(s.ConnectionIdlePingTimeoutMs), "the duration to wait for the reply of the idle ping before closing the connection. Default is 5000.")
+	skr := flag.Uint("skr", uint(s.SessionKeyRotationIntervalMs), "the interval to rotate the session key of the encrypted connections. Default is 600000. (0 = never)")
 	mfaa := flag.Int("mfaa", s.MaxFailedAuthAttempts, "the max number of failed authentication attempts before closing the connection. Default is 5. (0 = no limit)")
 	mfd := flag.Int("mfd", s.MaxFsmDisallowed, "the max number of disallowed FSM transitions before closing the connection. Default is 10. (0 = no limit)")
+	dlp := flag.Uint("dlp", uint(s.DuplicateLoginPolicy), "the policy when a client logs in with the same user id of another client, 0 = allow both, 1 = kick the old, 2 = reject the new")
+	ciq := flag.Uint("ciq", uint(s.ChannelIdQuarantineMs), "the duration to prevent the id of a removed channel from being reused. Default is 10000. (0 = reuse immediately)")
 
 	chs := flag.String("chs", "config/channel_settings_hifi.json", "the path to the channel settings file")
+	ts := flag.String("ts", "", "the path to the tenant settings file. Empty means no tenant overrides")
+	ls := flag.String("ls", "", "the path to the listener settings file. Empty means the listeners are set by -sn/-sa, -cn/-ca and -on/-oa")
 
 	flag.Parse()
 
@@ -202,6 +541,18 @@ func (s *GlobalSettingsType) ParseFlag() error {
 		s.ConnectionAuthTimeoutMs = int64(*cat)
 	}
 
+	if cit != nil {
+		s.ConnectionIdleTimeoutMs = int64(*cit)
+	}
+
+	if cipt != nil {
+		s.ConnectionIdlePingTimeoutMs = int64(*cipt)
+	}
+
+	if skr != nil {
+		s.SessionKeyRotationIntervalMs = int64(*skr)
+	}
+
 	if mfaa != nil {
 		s.MaxFailedAuthAttempts = int(*mfaa)
 	}
@@ -210,6 +561,30 @@ func (s *GlobalSettingsType) ParseFlag() error {
 		s.MaxFsmDisallowed = int(*mfd)
 	}
 
+	if dlp != nil {
+		s.DuplicateLoginPolicy = DuplicateLoginPolicy(*dlp)
+	}
+
+	if ciq != nil {
+		s.ChannelIdQuarantineMs = uint32(*ciq)
+	}
+
+	if mei != nil {
+		s.MetricsExportIntervalMs = uint32(*mei)
+	}
+
+	if afi != nil {
+		s.AnalyticsFlushIntervalMs = uint32(*afi)
+	}
+
+	if sri != nil {
+		s.ScriptReloadIntervalMs = uint32(*sri)
+	}
+
+	if sto != nil {
+		s.ScriptTimeoutMs = uint32(*sto)
+	}
+
 	chsData, err := os.ReadFile(*chs)
 	if err == nil {
 		if err := json.Unmarshal(chsData, &GlobalSettings.ChannelSettings); err != nil {
@@ -219,6 +594,26 @@ func (s *GlobalSettingsType) ParseFlag() error {
 		return fmt.Errorf("failed to read channel settings: %v", err)
 	}
 
+	if *ts != "" {
+		tsData, err := os.ReadFile(*ts)
+		if err != nil {
+			return fmt.Errorf("failed to read tenant settings: %v", err)
+		}
+		if err := json.Unmarshal(tsData, &GlobalSettings.TenantSettings); err != nil {
+			return fmt.Errorf("failed to unmarshall tenant settings: %v", err)
+		}
+	}
+
+	if *ls != "" {
+		lsData, err := os.ReadFile(*ls)
+		if err != nil {
+			return fmt.Errorf("failed to read listener settings: %v", err)
+		}
+		if err := json.Unmarshal(lsData, &GlobalSettings.Listeners); err != nil {
+			return fmt.Errorf("failed to unmarshall listener settings: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,84 @@
+package channeld
+
+import (
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	DefaultSlowSubscriberMaxFanOutIntervalMs = 1000
+	// The fan-out interval to start doubling from, if the subscription has no interval.
+	minSlowSubscriberFanOutIntervalMs = 20
+)
+
+func (c *Connection) isChannelDataLaneFull() bool {
+	lane := c.sendQueues[MessagePriority_ChannelData]
+	return len(lane) >= cap(lane)
+}
+
+// Downgrades the fan-out of the subscriber if its send queue has stayed full for ChannelSettingsType.SlowSubscriberTicks.
+// Returns true if the fan-out to the subscriber should be skipped in this tick.
+func (ch *Channel) checkSlowSubscriber(foc *fanOutConnection, cs *ChannelSubscription, t ChannelTime) bool {
+	if ch.data.slowSubscriberTicks <= 0 {
+		return false
+	}
+	conn, ok := foc.conn.(*Connection)
+	if !ok || !conn.isChannelDataLaneFull() {
+		foc.slowTicks = 0
+		return false
+	}
+
+	// The subscriber can be visited more than once in a tick, see tickData().
+	if foc.slowCheckTime == t {
+		return foc.keyframeOnly
+	}
+	foc.slowCheckTime = t
+	foc.slowTicks++
+	if foc.slowTicks >= ch.data.slowSubscriberTicks {
+		foc.slowTicks = 0
+		ch.downgradeSlowSubscriber(foc, cs)
+	}
+	// Nothing is lost by skipping, as the next fan-out in the keyframe-only mode carries the whole channel data.
+	return foc.keyframeOnly
+}
+
+func (ch *Channel) downgradeSlowSubscriber(foc *fanOutConnection, cs *ChannelSubscription) {
+	interval := *cs.options.FanOutIntervalMs
+	if interval < ch.data.slowSubscriberMaxFanOutIntervalMs {
+		if interval < minSlowSubscriberFanOutIntervalMs {
+			interval = minSlowSubscriberFanOutIntervalMs
+		} else {
+			interval *= 2
+		}
+		if interval > ch.data.slowSubscriberMaxFanOutIntervalMs {
+			interval = ch.data.slowSubscriberMaxFanOutIntervalMs
+		}
+		cs.options.FanOutIntervalMs = proto.Uint32(interval)
+		if ch.data.maxFanOutIntervalMs < interval {
+			ch.data.maxFanOutIntervalMs = interval
+		}
+		slowSubscriberDowngraded.WithLabelValues(ch.channelType.String(), "fanOutInterval").Inc()
+	} else if !foc.keyframeOnly {
+		foc.keyframeOnly = true
+		slowSubscriberDowngraded.WithLabelValues(ch.channelType.String(), "keyframeOnly").Inc()
+	} else {
+		// Already fully downgraded
+		return
+	}
+
+	ch.Logger().Warn("downgraded the fan-out of the slow subscriber",
+		zap.Uint32("connId", uint32(foc.conn.Id())),
+		zap.Uint32("fanOutIntervalMs", interval),
+		zap.Bool("keyframeOnly", foc.keyframeOnly),
+	)
+
+	if ch.HasOwner() && ch.ownerConnection != foc.conn {
+		ch.ownerConnection.Send(MessageContext{
+			MsgType:   channeldpb.MessageType_SLOW_SUBSCRIBER,
+			Msg:       &channeldpb.SlowSubscriberMessage{ConnId: uint32(foc.conn.Id()), FanOutIntervalMs: interval, KeyframeOnly: foc.keyframeOnly},
+			Channel:   ch,
+			ChannelId: uint32(ch.id),
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSlowSubscriber(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		SlowSubscriberTicks:               2,
+		SlowSubscriberMaxFanOutIntervalMs: 80,
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	owner := addTestConnection(channeldpb.ConnectionType_SERVER)
+	slow := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	fast := addTestConnection(channeldpb.ConnectionType_CLIENT)
+
+	testChannel, _ := CreateChannel(channeldpb.ChannelType_TEST, owner)
+	// Stop the channel.Tick() goroutine
+	testChannel.removing = 1
+	testChannel.InitData(&testpb.TestChannelDataMessage{Text: "a", Num: 1}, nil)
+	testChannel.tickInterval = time.Hour
+
+	slowCs, _ := slow.SubscribeToChannel(testChannel, &channeldpb.ChannelSubscriptionOptions{FanOutIntervalMs: proto.Uint32(20)})
+	fast.SubscribeToChannel(testChannel, &channeldpb.ChannelSubscriptionOptions{FanOutIntervalMs: proto.Uint32(20)})
+
+	lane := slow.sendQueues[MessagePriority_ChannelData]
+	for len(lane) < cap(lane) {
+		lane <- &channeldpb.MessagePack{}
+	}
+
+	// The fan-out interval is doubled every 2 ticks, until the max
+	tick := ChannelTime(0)
+	for i := 0; i < 4; i++ {
+		tick = tick.AddMs(100)
+		testChannel.tickData(tick)
+	}
+	assert.EqualValues(t, 80, *slowCs.options.FanOutIntervalMs)
+	assert.EqualValues(t, &channeldpb.SlowSubscriberMessage{ConnId: uint32(slow.Id()), FanOutIntervalMs: 80}, owner.latestMsg())
+	// The fast subscriber is not affected
+	assert.EqualValues(t, 20, *testChannel.subscribedConnections[fast].options.FanOutIntervalMs)
+
+	// Then switched to the keyframe-only mode, and the fan-out is skipped while the send queue is full
+	for i := 0; i < 2; i++ {
+		tick = tick.AddMs(100)
+		testChannel.tickData(tick)
+	}
+	assert.EqualValues(t, &channeldpb.SlowSubscriberMessage{ConnId: uint32(slow.Id()), FanOutIntervalMs: 80, KeyframeOnly: true}, owner.latestMsg())
+	sentNum := len(slow.testQueue())
+	testChannel.Data().OnUpdate(&testpb.TestChannelDataMessage{Num: 2}, tick, owner.Id(), nil)
+	tick = tick.AddMs(100)
+	testChannel.tickData(tick)
+	assert.Equal(t, sentNum, len(slow.testQueue()))
+	assert.Equal(t, 2, len(fast.testQueue()))
+
+	// The whole channel data is fanned out after the send queue is drained
+	for len(lane) > 0 {
+		<-lane
+	}
+	testChannel.Data().OnUpdate(&testpb.TestChannelDataMessage{Num: 3}, tick, owner.Id(), nil)
+	tick = tick.AddMs(100)
+	testChannel.tickData(tick)
+	updateMsg := slow.latestMsg().(*channeldpb.ChannelDataUpdateMessage)
+	dataMsg, _ := updateMsg.Data.UnmarshalNew()
+	assert.True(t, proto.Equal(&testpb.TestChannelDataMessage{Text: "a", Num: 3}, dataMsg))
+}
@@ -421,7 +421,7 @@ func (ctl *StaticGrid2DSpatialController) CreateChannels(ctx MessageContext) ([]
 
 	channels := make([]*Channel, len(channelIds))
 	for index, channelId := range channelIds {
-		channel := createChannelWithId(channelId, channeldpb.ChannelType_SPATIAL, ctx.Connection)
+		channel := createChannelWithId(channelId, channeldpb.ChannelType_SPATIAL, ctx.Connection, GetTenant(ctx.Connection))
 		if msg.Data != nil {
 			dataMsg, err := msg.Data.UnmarshalNew()
 			if err != nil {
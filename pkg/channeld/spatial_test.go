@@ -726,7 +726,7 @@ func (c *testConnection) Send(ctx MessageContext) {
 func (c *testConnection) SubscribeToChannel(ch *Channel, options *channeldpb.ChannelSubscriptionOptions) (*ChannelSubscription, bool) {
 	c.subscribedChannels[ch.id] = ch
 	return &ChannelSubscription{
-		options: *defaultSubOptions(ch.channelType),
+		options: *defaultSubOptions(ch),
 	}, false
 }
 
@@ -747,6 +747,10 @@ func (c *testConnection) HasInterestIn(spatialChId common.ChannelId) bool {
 	return false
 }
 
+func (c *testConnection) GetTag(key string) (string, bool) {
+	return "", false
+}
+
 func (c *testConnection) Logger() *Logger {
 	return rootLogger
 }
@@ -18,12 +18,12 @@ type ChannelSubscription struct {
 	fanOutElement *list.Element
 }
 
-func defaultSubOptions(t channeldpb.ChannelType) *channeldpb.ChannelSubscriptionOptions {
+func defaultSubOptions(ch *Channel) *channeldpb.ChannelSubscriptionOptions {
 	options := &channeldpb.ChannelSubscriptionOptions{
 		DataAccess:           Pointer(channeldpb.ChannelDataAccess_READ_ACCESS),
 		DataFieldMasks:       make([]string, 0),
-		FanOutDelayMs:        proto.Int32(GlobalSettings.GetChannelSettings(t).DefaultFanOutDelayMs),
-		FanOutIntervalMs:     proto.Uint32(GlobalSettings.GetChannelSettings(t).DefaultFanOutIntervalMs),
+		FanOutDelayMs:        proto.Int32(ch.settings().DefaultFanOutDelayMs),
+		FanOutIntervalMs:     proto.Uint32(ch.settings().DefaultFanOutIntervalMs),
 		SkipSelfUpdateFanOut: proto.Bool(true),
 		SkipFirstFanOut:      proto.Bool(false),
 	}
@@ -47,13 +47,19 @@ func (c *Connection) SubscribeToChannel(ch *Channel, options *channeldpb.Channel
 				zap.String("channelType", ch.channelType.String()),
 				zap.Uint32("channelId", uint32(ch.id)),
 			)
+			wasEventsOnly := cs.isEventsOnly()
 			proto.Merge(&cs.options, options)
+			// The data access was dropped by the events-only subscription
+			if wasEventsOnly && !cs.isEventsOnly() && options.DataAccess == nil {
+				cs.options.DataAccess = Pointer(channeldpb.ChannelDataAccess_READ_ACCESS)
+			}
+			ch.updateFanOutConnection(c, cs)
 		}
 		return cs, exists
 	}
 
 	cs = &ChannelSubscription{
-		options: *defaultSubOptions(ch.channelType),
+		options: *defaultSubOptions(ch),
 		// Send the whole data to the connection when subscribed
 		//fanOutDataMsg: ch.Data().msg,
 		subTime: ch.GetTime(),
@@ -63,12 +69,7 @@ func (c *Connection) SubscribeToChannel(ch *Channel, options *channeldpb.Channel
 		proto.Merge(&cs.options, options)
 	}
 
-	cs.fanOutElement = ch.fanOutQueue.PushFront(&fanOutConnection{
-		conn:           c,
-		hadFirstFanOut: *cs.options.SkipFirstFanOut,
-		// Delay the first fanout, to solve the spawn & update order issue in Mirror & UE.
-		lastFanOutTime: ch.GetTime().OffsetMs(*cs.options.FanOutDelayMs),
-	})
+	ch.updateFanOutConnection(c, cs)
 	// rootLogger.Info("conn sub to channel",
 	// 	zap.Uint32("connId", uint32(cs.fanOutElement.Value.(*fanOutConnection).conn.Id())),
 	// 	zap.Int32("fanOutDelayMs", cs.options.FanOutDelayMs),
@@ -76,11 +77,9 @@ func (c *Connection) SubscribeToChannel(ch *Channel, options *channeldpb.Channel
 	// 	zap.Int64("nextFanOutTime", int64(cs.fanOutElement.Value.(*fanOutConnection).lastFanOutTime)),
 	// )
 
-	// Records the maximum fan-out interval for checking if the oldest update message is removable when the buffer is overflowed.
-	if ch.data != nil && ch.data.maxFanOutIntervalMs < *cs.options.FanOutIntervalMs {
-		ch.data.maxFanOutIntervalMs = *cs.options.FanOutIntervalMs
-	}
 	ch.subscribedConnections[c] = cs
+	ch.countStats(&ch.statsCounters.subscribes)
+	ch.notifyMembershipChange(c, true)
 
 	if ch.channelType == channeldpb.ChannelType_SPATIAL {
 		c.spatialSubscriptions.Store(ch.id, &cs.options)
@@ -94,6 +93,7 @@ func (c *Connection) SubscribeToChannel(ch *Channel, options *channeldpb.Channel
 		zap.Bool("skipSelfUpdateFanOut", *cs.options.SkipSelfUpdateFanOut),
 		zap.Bool("skipFirstFanOut", *cs.options.SkipFirstFanOut),
 	)
+	c.updatePresenceChannel(ch, true)
 	return cs, false
 }
 
@@ -108,15 +108,23 @@ func (c *Connection) UnsubscribeFromChannel(ch *Channel) (*channeldpb.ChannelSub
 		return nil, errors.New("subscription does not exist")
 	}
 
-	ch.fanOutQueue.Remove(cs.fanOutElement)
+	if cs.fanOutElement != nil {
+		ch.fanOutQueue.Remove(cs.fanOutElement)
+	}
 
 	delete(ch.subscribedConnections, c)
+	ch.countStats(&ch.statsCounters.unsubscribes)
+	ch.notifyMembershipChange(c, false)
+	if ch.data != nil {
+		delete(ch.data.inputSeqs, c.Id())
+	}
 
 	if ch.channelType == channeldpb.ChannelType_SPATIAL {
 		c.spatialSubscriptions.Delete(ch.id)
 	}
 
 	ch.Logger().Debug("unsubscribed connection", zap.Uint32("connId", uint32(c.Id())))
+	c.updatePresenceChannel(ch, false)
 	return &cs.options, nil
 }
 
@@ -0,0 +1,67 @@
+package channeld
+
+import (
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+)
+
+// See channeldpb.ChannelSubscriptionOptions.EventsOnly
+func (cs *ChannelSubscription) isEventsOnly() bool {
+	return cs.options.GetEventsOnly()
+}
+
+// Adds the subscriber to the fan-out queue, or removes it if the subscription is events only.
+// Should be called with the connectionsLock held.
+func (ch *Channel) updateFanOutConnection(c ConnectionInChannel, cs *ChannelSubscription) {
+	if cs.isEventsOnly() {
+		cs.options.DataAccess = Pointer(channeldpb.ChannelDataAccess_NO_ACCESS)
+		if cs.fanOutElement != nil {
+			ch.fanOutQueue.Remove(cs.fanOutElement)
+			cs.fanOutElement = nil
+		}
+		return
+	}
+	if cs.fanOutElement != nil {
+		return
+	}
+
+	cs.fanOutElement = ch.fanOutQueue.PushFront(&fanOutConnection{
+		conn:           c,
+		hadFirstFanOut: *cs.options.SkipFirstFanOut,
+		// Delay the first fanout, to solve the spawn & update order issue in Mirror & UE.
+		lastFanOutTime: ch.GetTime().OffsetMs(*cs.options.FanOutDelayMs),
+	})
+
+	// Records the maximum fan-out interval for checking if the oldest update message is removable when the buffer is overflowed.
+	maxFanOutIntervalMs := *cs.options.FanOutIntervalMs
+	if cs.options.GetAdaptiveFanOut() {
+		maxFanOutIntervalMs = cs.maxAdaptiveFanOutIntervalMs()
+	}
+	if ch.data != nil && ch.data.maxFanOutIntervalMs < maxFanOutIntervalMs {
+		ch.data.maxFanOutIntervalMs = maxFanOutIntervalMs
+	}
+}
+
+// Notifies the events-only subscribers that the connection has subscribed to or unsubscribed from the channel.
+// The owner is not notified here, as it's notified by the message handlers, and neither is its own (un)subscription.
+// Should be called with the connectionsLock held.
+func (ch *Channel) notifyMembershipChange(c *Connection, subscribed bool) {
+	if ConnectionInChannel(c) == ch.ownerConnection {
+		return
+	}
+	for conn, cs := range ch.subscribedConnections {
+		if conn == ConnectionInChannel(c) || conn == ch.ownerConnection || !cs.isEventsOnly() || conn.IsClosing() {
+			continue
+		}
+		if subscribed {
+			conn.sendSubscribed(MessageContext{}, ch, c, 0, nil)
+		} else {
+			conn.sendUnsubscribed(MessageContext{}, ch, c, 0)
+		}
+		ch.Logger().VeryVerbose("notified the membership change",
+			zap.Uint32("connId", uint32(c.Id())),
+			zap.Uint32("eventSubscriberConnId", uint32(conn.Id())),
+			zap.Bool("subscribed", subscribed),
+		)
+	}
+}
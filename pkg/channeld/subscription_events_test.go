@@ -0,0 +1,73 @@
+package channeld
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestEventsOnlySubscription(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	owner := addTestConnection(channeldpb.ConnectionType_SERVER)
+	lobby := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	player := addTestConnection(channeldpb.ConnectionType_CLIENT)
+
+	testChannel, _ := CreateChannel(channeldpb.ChannelType_TEST, owner)
+	// Stop the channel.Tick() goroutine
+	testChannel.removing = 1
+	testChannel.InitData(&testpb.TestChannelDataMessage{Text: "a", Num: 1}, nil)
+	testChannel.tickInterval = time.Hour
+
+	lobbyCs, _ := lobby.SubscribeToChannel(testChannel, &channeldpb.ChannelSubscriptionOptions{
+		EventsOnly: proto.Bool(true),
+		DataAccess: Pointer(channeldpb.ChannelDataAccess_WRITE_ACCESS),
+	})
+	assert.Nil(t, lobbyCs.fanOutElement)
+	assert.Equal(t, channeldpb.ChannelDataAccess_NO_ACCESS, *lobbyCs.options.DataAccess)
+	owner.SubscribeToChannel(testChannel, nil)
+	assert.Empty(t, lobby.testQueue(), "the owner is not a member to notify")
+
+	// Notified of the membership change
+	player.SubscribeToChannel(testChannel, nil)
+	if msg, ok := lobby.latestMsg().(*channeldpb.SubscribedToChannelResultMessage); assert.True(t, ok) {
+		assert.EqualValues(t, player.Id(), msg.ConnId)
+		assert.Nil(t, msg.SubOptions)
+	}
+
+	// No fan-out
+	testChannel.tickData(ChannelTime(0).AddMs(100))
+	assert.Len(t, lobby.testQueue(), 1)
+	_, ok := player.latestMsg().(*channeldpb.ChannelDataUpdateMessage)
+	assert.True(t, ok)
+
+	// Receives the user-space broadcasts
+	testChannel.Broadcast(MessageContext{
+		MsgType:   channeldpb.MessageType_USER_SPACE_START,
+		Msg:       &channeldpb.ServerForwardMessage{Payload: []byte("hello")},
+		Broadcast: uint32(channeldpb.BroadcastType_ALL),
+		ChannelId: uint32(testChannel.id),
+	})
+	_, ok = lobby.latestMsg().(*channeldpb.ServerForwardMessage)
+	assert.True(t, ok)
+
+	player.UnsubscribeFromChannel(testChannel)
+	if msg, ok := lobby.latestMsg().(*channeldpb.UnsubscribedFromChannelResultMessage); assert.True(t, ok) {
+		assert.EqualValues(t, player.Id(), msg.ConnId)
+	}
+
+	// Switched to the data subscription
+	lobby.SubscribeToChannel(testChannel, &channeldpb.ChannelSubscriptionOptions{EventsOnly: proto.Bool(false)})
+	assert.NotNil(t, lobbyCs.fanOutElement)
+	testChannel.tickData(ChannelTime(0).AddMs(200))
+	_, ok = lobby.latestMsg().(*channeldpb.ChannelDataUpdateMessage)
+	assert.True(t, ok)
+	_, err := lobby.UnsubscribeFromChannel(testChannel)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,208 @@
+package channeld
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// TagFilter is a predicate on the connection tags, used for the targeted broadcast.
+//
+// The syntax of the filter expression:
+//
+//	team == "red"                       the tag equals the value
+//	platform != "ios"                   the tag doesn't exist or doesn't equal the value
+//	vip                                 the tag exists
+//	!vip                                the tag doesn't exist
+//	team == "red" && (vip || level == "10")
+type TagFilter interface {
+	Match(c ConnectionInChannel) bool
+}
+
+type tagEqualsFilter struct {
+	key   string
+	value string
+}
+
+func (f *tagEqualsFilter) Match(c ConnectionInChannel) bool {
+	v, exists := c.GetTag(f.key)
+	return exists && v == f.value
+}
+
+type tagExistsFilter struct {
+	key string
+}
+
+func (f *tagExistsFilter) Match(c ConnectionInChannel) bool {
+	_, exists := c.GetTag(f.key)
+	return exists
+}
+
+type notFilter struct {
+	f TagFilter
+}
+
+func (f *notFilter) Match(c ConnectionInChannel) bool {
+	return !f.f.Match(c)
+}
+
+type andFilter struct {
+	left, right TagFilter
+}
+
+func (f *andFilter) Match(c ConnectionInChannel) bool {
+	return f.left.Match(c) && f.right.Match(c)
+}
+
+type orFilter struct {
+	left, right TagFilter
+}
+
+func (f *orFilter) Match(c ConnectionInChannel) bool {
+	return f.left.Match(c) || f.right.Match(c)
+}
+
+func ParseTagFilter(expr string) (TagFilter, error) {
+	p := &tagFilterParser{src: expr}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.src) {
+		return nil, fmt.Errorf("unexpected '%s' at %d in tag filter", p.src[p.pos:], p.pos)
+	}
+	return f, nil
+}
+
+type tagFilterParser struct {
+	src string
+	pos int
+}
+
+func (p *tagFilterParser) skipSpaces() {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *tagFilterParser) tryConsume(s string) bool {
+	p.skipSpaces()
+	if strings.HasPrefix(p.src[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *tagFilterParser) parseOr() (TagFilter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.tryConsume("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orFilter{left, right}
+	}
+	return left, nil
+}
+
+func (p *tagFilterParser) parseAnd() (TagFilter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.tryConsume("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andFilter{left, right}
+	}
+	return left, nil
+}
+
+func (p *tagFilterParser) parseUnary() (TagFilter, error) {
+	if p.tryConsume("(") {
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.tryConsume(")") {
+			return nil, fmt.Errorf("missing ')' at %d in tag filter", p.pos)
+		}
+		return f, nil
+	}
+
+	if p.tryConsume("!") {
+		f, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notFilter{f}, nil
+	}
+
+	key, err := p.parseKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.tryConsume("==") {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return &tagEqualsFilter{key, value}, nil
+	} else if p.tryConsume("!=") {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return &notFilter{&tagEqualsFilter{key, value}}, nil
+	}
+
+	return &tagExistsFilter{key}, nil
+}
+
+func (p *tagFilterParser) parseKey() (string, error) {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := rune(p.src[p.pos])
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '_' && c != '.' && c != '-' {
+			break
+		}
+		p.pos++
+	}
+	if start == p.pos {
+		return "", fmt.Errorf("expect tag key at %d in tag filter", start)
+	}
+	return p.src[start:p.pos], nil
+}
+
+func (p *tagFilterParser) parseValue() (string, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.src) || p.src[p.pos] != '"' {
+		return "", fmt.Errorf("expect quoted tag value at %d in tag filter", p.pos)
+	}
+	end := p.pos + 1
+	for end < len(p.src) && p.src[end] != '"' {
+		if p.src[end] == '\\' {
+			end++
+		}
+		end++
+	}
+	if end >= len(p.src) {
+		return "", fmt.Errorf("unterminated tag value at %d in tag filter", p.pos)
+	}
+	value, err := strconv.Unquote(p.src[p.pos : end+1])
+	if err != nil {
+		return "", fmt.Errorf("invalid tag value at %d in tag filter: %w", p.pos, err)
+	}
+	p.pos = end + 1
+	return value, nil
+}
@@ -0,0 +1,82 @@
+package channeld
+
+import (
+	"testing"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTagFilter(t *testing.T) {
+	InitLogs()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	c := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	c.SetTag("team", "red")
+	c.SetTag("vip", "")
+
+	matches := func(expr string) bool {
+		f, err := ParseTagFilter(expr)
+		assert.NoError(t, err, expr)
+		return f.Match(c)
+	}
+
+	assert.True(t, matches(`team == "red"`))
+	assert.False(t, matches(`team == "blue"`))
+	assert.True(t, matches(`team != "blue"`))
+	assert.True(t, matches(`platform != "ios"`))
+	assert.True(t, matches(`vip`))
+	assert.False(t, matches(`!vip`))
+	assert.False(t, matches(`platform`))
+	assert.True(t, matches(`team == "blue" || vip`))
+	assert.False(t, matches(`team == "red" && platform`))
+	assert.True(t, matches(`team == "red" && (platform || vip)`))
+	assert.True(t, matches(`!(team == "blue")`))
+
+	for _, expr := range []string{"", `team ==`, `team == red`, `team == "red`, `(vip`, `vip &&`, `vip )`} {
+		_, err := ParseTagFilter(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestBroadcastWithTagFilter(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	red := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	red.SetTag("team", "red")
+	blue := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	blue.SetTag("team", "blue")
+
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, server)
+	red.SubscribeToChannel(ch, nil)
+	blue.SubscribeToChannel(ch, nil)
+
+	HandleServerToClientUserMessage(MessageContext{
+		MsgType:    channeldpb.MessageType_USER_SPACE_START,
+		Msg:        &channeldpb.ServerForwardMessage{Payload: []byte{1}, TagFilter: `team == "red"`},
+		Broadcast:  uint32(channeldpb.BroadcastType_ALL_BUT_OWNER),
+		Connection: server,
+		Channel:    ch,
+	})
+
+	fwd, ok := red.latestMsg().(*channeldpb.ServerForwardMessage)
+	if assert.True(t, ok) {
+		// The filter is not forwarded to the receivers
+		assert.Empty(t, fwd.TagFilter)
+	}
+	_, ok = blue.latestMsg().(*channeldpb.ServerForwardMessage)
+	assert.False(t, ok)
+
+	// Invalid filter is not broadcast at all
+	HandleServerToClientUserMessage(MessageContext{
+		MsgType:    channeldpb.MessageType_USER_SPACE_START,
+		Msg:        &channeldpb.ServerForwardMessage{Payload: []byte{2}, TagFilter: `team ==`},
+		Broadcast:  uint32(channeldpb.BroadcastType_ALL_BUT_OWNER),
+		Connection: server,
+		Channel:    ch,
+	})
+	assert.Equal(t, []byte{1}, red.latestMsg().(*channeldpb.ServerForwardMessage).Payload)
+}
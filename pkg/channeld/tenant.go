@@ -0,0 +1,90 @@
+package channeld
+
+import (
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+)
+
+// The tag of the connection that tells its tenant, e.g. a game or an environment (dev/staging) sharing the channeld deployment.
+// It's set by the TaggingAuthProvider, and can't be changed after authenticated. Empty means the default tenant.
+const TenantTag = "tenant"
+
+// Returns the tenant of the connection. See TenantTag.
+func GetTenant(c ConnectionInChannel) string {
+	if c == nil {
+		return ""
+	}
+	tenant, _ := c.GetTag(TenantTag)
+	return tenant
+}
+
+// The tenant that the channel belongs to. The channels of the other tenants are invisible to the connection,
+// as if they don't exist. The GLOBAL channel is shared by all the tenants.
+func (ch *Channel) Tenant() string {
+	return ch.tenant
+}
+
+// Returns true if the connection can access the channel. The operators in the default tenant can access all the tenants.
+func (ch *Channel) IsVisibleTo(c ConnectionInChannel) bool {
+	if ch.channelType == channeldpb.ChannelType_GLOBAL {
+		return true
+	}
+	return canAccessTenant(c, ch.tenant)
+}
+
+func canAccessTenant(c ConnectionInChannel, tenant string) bool {
+	connTenant := GetTenant(c)
+	return connTenant == tenant || (connTenant == "" && isOperator(c))
+}
+
+// Returns nil if the channel doesn't exist or is invisible to the connection. See Channel.IsVisibleTo().
+func GetChannelOfConnection(id common.ChannelId, c ConnectionInChannel) *Channel {
+	ch := GetChannel(id)
+	if ch == nil || !ch.IsVisibleTo(c) {
+		return nil
+	}
+	return ch
+}
+
+// Returns nil if the target connection doesn't exist or is in another tenant, so the connections can't sub, unsub,
+// tag or disconnect the connections of the other tenants. The operators in the default tenant can access all the tenants.
+func GetConnectionOfConnection(id ConnectionId, c ConnectionInChannel) *Connection {
+	target := GetConnection(id)
+	if target == nil || !canAccessTenant(c, GetTenant(target)) {
+		return nil
+	}
+	return target
+}
+
+// The settings of the channel type, overridden by TenantSettingsType.ChannelSettings of the tenant.
+func (s GlobalSettingsType) GetTenantChannelSettings(tenant string, t channeldpb.ChannelType) ChannelSettingsType {
+	if tenantSettings, exists := s.TenantSettings[tenant]; exists {
+		if settings, exists := tenantSettings.ChannelSettings[t]; exists {
+			return settings
+		}
+	}
+	return s.GetChannelSettings(t)
+}
+
+func (ch *Channel) settings() ChannelSettingsType {
+	return GlobalSettings.GetTenantChannelSettings(ch.tenant, ch.channelType)
+}
+
+// Unlike settings(), doesn't fall back to the settings of the GLOBAL channel type.
+func (ch *Channel) settingsOfType() (ChannelSettingsType, bool) {
+	if tenantSettings, exists := GlobalSettings.TenantSettings[ch.tenant]; exists {
+		if settings, exists := tenantSettings.ChannelSettings[ch.channelType]; exists {
+			return settings, true
+		}
+	}
+	settings, exists := GlobalSettings.ChannelSettings[ch.channelType]
+	return settings, exists
+}
+
+// The named channels are indexed per tenant, so the tenants can use the same keys.
+func tenantChannelKey(tenant string, key string) string {
+	if tenant == "" {
+		return key
+	}
+	return tenant + "\x00" + key
+}
@@ -0,0 +1,237 @@
+package channeld
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/puzpuzpuz/xsync/v2"
+	"go.uber.org/zap"
+)
+
+// Returned when a channel can't be created as the quota of the tenant is hit.
+type TenantQuotaError struct {
+	*channeldpb.QuotaExceededMessage
+}
+
+func (e *TenantQuotaError) Error() string {
+	return "tenant quota exceeded: " + e.QuotaType.String()
+}
+
+type tenantUsage struct {
+	lock            sync.Mutex
+	channels        int
+	channelsPerType map[channeldpb.ChannelType]int
+	connections     int
+	// How many times each quota is hit
+	exceeded map[channeldpb.QuotaExceededMessage_QuotaType]uint64
+
+	// The bytes received in the current one-second window, and the start time (unix nano) of the window
+	windowBytes int64
+	windowStart int64
+	// The bytes received in the last window
+	lastWindowBytes int64
+}
+
+var tenantUsages = xsync.NewMapOf[*tenantUsage]()
+
+func getTenantUsage(tenant string) *tenantUsage {
+	usage, _ := tenantUsages.LoadOrCompute(tenant, func() *tenantUsage {
+		return &tenantUsage{
+			channelsPerType: make(map[channeldpb.ChannelType]int),
+			exceeded:        make(map[channeldpb.QuotaExceededMessage_QuotaType]uint64),
+		}
+	})
+	return usage
+}
+
+func (u *tenantUsage) addChannel(t channeldpb.ChannelType, delta int) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	u.channels += delta
+	u.channelsPerType[t] += delta
+}
+
+// Should be called with the lock held.
+func (u *tenantUsage) onExceeded(tenant string, quotaType channeldpb.QuotaExceededMessage_QuotaType) {
+	u.exceeded[quotaType]++
+	tenantQuotaExceeded.WithLabelValues(tenant, quotaType.String()).Inc()
+}
+
+// Returns the message to notify the connection if the tenant can't have more channels of the type, otherwise nil.
+func checkTenantChannelQuota(tenant string, t channeldpb.ChannelType) *channeldpb.QuotaExceededMessage {
+	settings, exists := GlobalSettings.TenantSettings[tenant]
+	if !exists {
+		return nil
+	}
+	u := getTenantUsage(tenant)
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	if settings.MaxChannels > 0 && u.channels >= settings.MaxChannels {
+		u.onExceeded(tenant, channeldpb.QuotaExceededMessage_CHANNELS)
+		return &channeldpb.QuotaExceededMessage{
+			QuotaType:   channeldpb.QuotaExceededMessage_CHANNELS,
+			Tenant:      tenant,
+			Limit:       int64(settings.MaxChannels),
+			ChannelType: t,
+		}
+	}
+	if limit := settings.MaxChannelsPerType[t]; limit > 0 && u.channelsPerType[t] >= limit {
+		u.onExceeded(tenant, channeldpb.QuotaExceededMessage_CHANNELS_OF_TYPE)
+		return &channeldpb.QuotaExceededMessage{
+			QuotaType:   channeldpb.QuotaExceededMessage_CHANNELS_OF_TYPE,
+			Tenant:      tenant,
+			Limit:       int64(limit),
+			ChannelType: t,
+		}
+	}
+	return nil
+}
+
+// Counts the connection in its tenant until it's closed, or the returned function is called.
+// Returns nil if the tenant has reached its max connections.
+func (c *Connection) acquireTenantConnection() func() {
+	tenant := GetTenant(c)
+	limit := GlobalSettings.TenantSettings[tenant].MaxConnections
+	u := getTenantUsage(tenant)
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	if limit > 0 && u.connections >= limit {
+		u.onExceeded(tenant, channeldpb.QuotaExceededMessage_CONNECTIONS)
+		return nil
+	}
+	u.connections++
+	tenantConnectionNum.WithLabelValues(tenant).Inc()
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			u.lock.Lock()
+			u.connections--
+			u.lock.Unlock()
+			tenantConnectionNum.WithLabelValues(tenant).Dec()
+		})
+	}
+	c.AddCloseHandler(release)
+	return release
+}
+
+// Counts the received bytes in the tenant of the connection. Returns false if the packet should be dropped,
+// as the tenant has exceeded its max bytes per second. Should be called in the receiving goroutine.
+func (c *Connection) consumeTenantBandwidth(size int) bool {
+	if c.State() != ConnectionState_READY {
+		return true
+	}
+	tenant := GetTenant(c)
+	u := getTenantUsage(tenant)
+	now := time.Now().UnixNano()
+	start := atomic.LoadInt64(&u.windowStart)
+	if now-start >= int64(time.Second) && atomic.CompareAndSwapInt64(&u.windowStart, start, now) {
+		atomic.StoreInt64(&u.lastWindowBytes, atomic.SwapInt64(&u.windowBytes, 0))
+	}
+
+	limit := GlobalSettings.TenantSettings[tenant].MaxBytesPerSecond
+	if bytes := atomic.AddInt64(&u.windowBytes, int64(size)); limit <= 0 || bytes <= limit {
+		return true
+	}
+	// The dropped packet is not counted
+	atomic.AddInt64(&u.windowBytes, -int64(size))
+
+	u.lock.Lock()
+	u.onExceeded(tenant, channeldpb.QuotaExceededMessage_BANDWIDTH)
+	u.lock.Unlock()
+	packetDropped.WithLabelValues(c.connectionType.String()).Inc()
+
+	// Notify the connection at most once per second
+	if now-c.bandwidthExceededTime >= int64(time.Second) {
+		c.bandwidthExceededTime = now
+		c.Logger().Warn("dropped the packet as the tenant has exceeded its bandwidth", zap.String("tenant", tenant), zap.Int64("maxBytesPerSecond", limit))
+		c.sendQuotaExceeded(&channeldpb.QuotaExceededMessage{
+			QuotaType: channeldpb.QuotaExceededMessage_BANDWIDTH,
+			Tenant:    tenant,
+			Limit:     limit,
+		})
+	}
+	return false
+}
+
+func (c *Connection) sendQuotaExceeded(msg *channeldpb.QuotaExceededMessage) {
+	c.Send(MessageContext{
+		MsgType:   channeldpb.MessageType_QUOTA_EXCEEDED,
+		Msg:       msg,
+		ChannelId: uint32(GlobalChannelId),
+	})
+}
+
+type TenantUsage struct {
+	Tenant          string
+	Channels        int
+	ChannelsPerType map[string]int
+	Connections     int
+	// The bytes received from the connections of the tenant in the last second
+	BytesPerSecond int64
+	// How many times each quota is hit
+	Exceeded map[string]uint64
+
+	MaxChannels        int
+	MaxChannelsPerType map[string]int
+	MaxConnections     int
+	MaxBytesPerSecond  int64
+}
+
+// The admin API that reports the usage and the quotas of the tenants, in the order of the tenant name.
+func HandleTenantUsage(w http.ResponseWriter, r *http.Request) {
+	tenants := make(map[string]struct{})
+	tenantUsages.Range(func(tenant string, _ *tenantUsage) bool {
+		tenants[tenant] = struct{}{}
+		return true
+	})
+	for tenant := range GlobalSettings.TenantSettings {
+		tenants[tenant] = struct{}{}
+	}
+
+	report := make([]*TenantUsage, 0, len(tenants))
+	for tenant := range tenants {
+		settings := GlobalSettings.TenantSettings[tenant]
+		report = append(report, getTenantUsage(tenant).report(tenant, settings))
+	}
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].Tenant < report[j].Tenant
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		rootLogger.Error("failed to write tenant usage report", zap.Error(err))
+	}
+}
+
+func (u *tenantUsage) report(tenant string, settings TenantSettingsType) *TenantUsage {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	report := &TenantUsage{
+		Tenant:             tenant,
+		Channels:           u.channels,
+		ChannelsPerType:    make(map[string]int),
+		Connections:        u.connections,
+		BytesPerSecond:     atomic.LoadInt64(&u.lastWindowBytes),
+		Exceeded:           make(map[string]uint64),
+		MaxChannels:        settings.MaxChannels,
+		MaxChannelsPerType: make(map[string]int),
+		MaxConnections:     settings.MaxConnections,
+		MaxBytesPerSecond:  settings.MaxBytesPerSecond,
+	}
+	for t, num := range u.channelsPerType {
+		report.ChannelsPerType[t.String()] = num
+	}
+	for quotaType, num := range u.exceeded {
+		report.Exceeded[quotaType.String()] = num
+	}
+	for t, limit := range settings.MaxChannelsPerType {
+		report.MaxChannelsPerType[t.String()] = limit
+	}
+	return report
+}
@@ -0,0 +1,164 @@
+package channeld
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/puzpuzpuz/xsync/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantChannelQuota(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.TenantSettings = map[string]TenantSettingsType{
+		"channelQuota": {
+			MaxChannels:        2,
+			MaxChannelsPerType: map[channeldpb.ChannelType]int{channeldpb.ChannelType_TEST: 1},
+		},
+	}
+	defer func() { GlobalSettings.TenantSettings = nil }()
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	server.SetTag(TenantTag, "channelQuota")
+
+	ch1, err := CreateChannel(channeldpb.ChannelType_TEST, server)
+	assert.NoError(t, err)
+	defer RemoveChannel(ch1)
+	_, err = CreateChannel(channeldpb.ChannelType_TEST, server)
+	if assert.IsType(t, &TenantQuotaError{}, err) {
+		assert.Equal(t, channeldpb.QuotaExceededMessage_CHANNELS_OF_TYPE, err.(*TenantQuotaError).QuotaType)
+	}
+
+	ch2, err := CreateChannel(channeldpb.ChannelType_SUBWORLD, server)
+	assert.NoError(t, err)
+	// The sender is notified
+	handleCreateChannel(MessageContext{
+		MsgType:    channeldpb.MessageType_CREATE_CHANNEL,
+		Msg:        &channeldpb.CreateChannelMessage{ChannelType: channeldpb.ChannelType_SUBWORLD},
+		Connection: server,
+		Channel:    globalChannel,
+	})
+	assert.EqualValues(t, &channeldpb.QuotaExceededMessage{
+		QuotaType:   channeldpb.QuotaExceededMessage_CHANNELS,
+		Tenant:      "channelQuota",
+		Limit:       2,
+		ChannelType: channeldpb.ChannelType_SUBWORLD,
+	}, server.latestMsg())
+
+	// The removed channel frees the quota
+	RemoveChannel(ch2)
+	ch2, err = CreateChannel(channeldpb.ChannelType_SUBWORLD, server)
+	assert.NoError(t, err)
+	RemoveChannel(ch2)
+}
+
+func TestTenantConnectionQuota(t *testing.T) {
+	InitLogs()
+	// The owner of the GLOBAL channel left by the other tests may not be able to send
+	useNewChannelRegistry(t)
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.TenantSettings = map[string]TenantSettingsType{
+		"connQuota": {MaxConnections: 1},
+	}
+	defer func() { GlobalSettings.TenantSettings = nil }()
+	// Don't count the connections of the previous runs
+	tenantUsages = xsync.NewMapOf[*tenantUsage]()
+
+	c1 := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	c1.SetTag(TenantTag, "connQuota")
+	c2 := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	c2.SetTag(TenantTag, "connQuota")
+
+	release := c1.acquireTenantConnection()
+	if !assert.NotNil(t, release) {
+		return
+	}
+
+	// The authentication fails
+	onAuthComplete(MessageContext{
+		MsgType:    channeldpb.MessageType_AUTH,
+		Msg:        &channeldpb.AuthMessage{ProtocolVersion: ProtocolVersion},
+		Connection: c2,
+		Channel:    globalChannel,
+	}, channeldpb.AuthResultMessage_SUCCESSFUL, "pit2")
+	queue := c2.testQueue()
+	if assert.Len(t, queue, 2) {
+		assert.Equal(t, channeldpb.AuthResultMessage_QUOTA_EXCEEDED, queue[0].(*channeldpb.AuthResultMessage).Result)
+		assert.Equal(t, channeldpb.QuotaExceededMessage_CONNECTIONS, queue[1].(*channeldpb.QuotaExceededMessage).QuotaType)
+	}
+	assert.NotEqual(t, ConnectionState_READY, c2.State())
+
+	// Released once, no matter how many times it's called
+	release()
+	c1.Close()
+	release = c2.acquireTenantConnection()
+	if !assert.NotNil(t, release) {
+		return
+	}
+	defer release()
+	c3 := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	c3.SetTag(TenantTag, "connQuota")
+	assert.Nil(t, c3.acquireTenantConnection())
+}
+
+func TestTenantBandwidthQuota(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.TenantSettings = map[string]TenantSettingsType{
+		"bandwidthQuota": {MaxBytesPerSecond: 100},
+	}
+	defer func() { GlobalSettings.TenantSettings = nil }()
+	// Don't count the bytes of the previous runs
+	tenantUsages = xsync.NewMapOf[*tenantUsage]()
+
+	c := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	c.SetTag(TenantTag, "bandwidthQuota")
+	// Not counted before authenticated
+	assert.True(t, c.consumeTenantBandwidth(1000))
+
+	c.OnAuthenticated("")
+	assert.True(t, c.consumeTenantBandwidth(60))
+	assert.False(t, c.consumeTenantBandwidth(60))
+	assert.EqualValues(t, &channeldpb.QuotaExceededMessage{
+		QuotaType: channeldpb.QuotaExceededMessage_BANDWIDTH,
+		Tenant:    "bandwidthQuota",
+		Limit:     100,
+	}, c.latestMsg())
+	// The dropped packet is not counted
+	assert.True(t, c.consumeTenantBandwidth(40))
+
+	// Notified once per second
+	sentNum := len(c.testQueue())
+	assert.False(t, c.consumeTenantBandwidth(1))
+	assert.Equal(t, sentNum, len(c.testQueue()))
+
+	// The next window
+	u := getTenantUsage("bandwidthQuota")
+	u.windowStart = 0
+	assert.True(t, c.consumeTenantBandwidth(60))
+	assert.EqualValues(t, 100, u.lastWindowBytes)
+
+	rec := httptest.NewRecorder()
+	HandleTenantUsage(rec, httptest.NewRequest("GET", "/debug/tenants", nil))
+	var report []*TenantUsage
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	var usage *TenantUsage
+	for _, tu := range report {
+		if tu.Tenant == "bandwidthQuota" {
+			usage = tu
+		}
+	}
+	if assert.NotNil(t, usage) {
+		assert.EqualValues(t, 100, usage.BytesPerSecond)
+		assert.EqualValues(t, 100, usage.MaxBytesPerSecond)
+		assert.EqualValues(t, 2, usage.Exceeded["BANDWIDTH"])
+	}
+}
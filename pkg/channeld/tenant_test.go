@@ -0,0 +1,221 @@
+package channeld
+
+import (
+	"testing"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantIsolation(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+	InitOperatorConnections("../../config/operator_fsm.json")
+
+	serverA := addTestConnection(channeldpb.ConnectionType_SERVER)
+	serverA.SetTag(TenantTag, "a")
+	clientA := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	clientA.SetTag(TenantTag, "a")
+	clientB := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	clientB.SetTag(TenantTag, "b")
+	operator := addTestConnection(channeldpb.ConnectionType_OPERATOR)
+
+	channelNum := testutil.ToFloat64(tenantChannelNum.WithLabelValues("a"))
+	chA, err := CreateChannel(channeldpb.ChannelType_TEST, serverA)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "a", chA.Tenant())
+	assert.Equal(t, channelNum+1, testutil.ToFloat64(tenantChannelNum.WithLabelValues("a")))
+
+	// The channel of the other tenant doesn't exist to the connection
+	assert.Same(t, chA, GetChannelOfConnection(chA.Id(), clientA))
+	assert.Nil(t, GetChannelOfConnection(chA.Id(), clientB))
+	assert.NoError(t, chA.CheckRequiredTags(clientA))
+	assert.ErrorIs(t, chA.CheckRequiredTags(clientB), ErrTenantMismatch)
+	// The operators in the default tenant can access all the tenants
+	assert.Same(t, chA, GetChannelOfConnection(chA.Id(), operator))
+	// The GLOBAL channel is shared
+	assert.Same(t, globalChannel, GetChannelOfConnection(GlobalChannelId, clientB))
+
+	// The tenants can use the same channel key
+	assert.NoError(t, chA.setKey("lobby"))
+	chB, _ := CreateTenantChannel(channeldpb.ChannelType_TEST, nil, "b")
+	assert.NoError(t, chB.setKey("lobby"))
+	assert.Same(t, chA, GetTenantChannelByKey("a", "lobby"))
+	assert.Same(t, chB, GetTenantChannelByKey("b", "lobby"))
+	assert.Nil(t, GetChannelByKey("lobby"))
+
+	RemoveChannel(chA)
+	RemoveChannel(chB)
+	assert.Nil(t, GetTenantChannelByKey("a", "lobby"))
+	assert.Equal(t, channelNum, testutil.ToFloat64(tenantChannelNum.WithLabelValues("a")))
+}
+
+func TestTenantChannelSettings(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	GlobalSettings.TenantSettings = map[string]TenantSettingsType{
+		"a": {ChannelSettings: map[channeldpb.ChannelType]ChannelSettingsType{
+			channeldpb.ChannelType_TEST: {ACLSettings: ACLSettingsType{Sub: ChannelAccessLevel_Any}},
+		}},
+	}
+	defer func() { GlobalSettings.TenantSettings = nil }()
+
+	clientA := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	clientA.SetTag(TenantTag, "a")
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+
+	chA, _ := CreateTenantChannel(channeldpb.ChannelType_TEST, nil, "a")
+	ch, _ := CreateChannel(channeldpb.ChannelType_TEST, nil)
+	defer RemoveChannel(chA)
+	defer RemoveChannel(ch)
+
+	hasAccess, err := chA.CheckACL(clientA, ChannelAccessType_Sub)
+	assert.True(t, hasAccess)
+	assert.NoError(t, err)
+	// The default tenant doesn't have the override
+	hasAccess, err = ch.CheckACL(client, ChannelAccessType_Sub)
+	assert.False(t, hasAccess)
+	assert.ErrorIs(t, err, ErrNoneAccess)
+}
+
+func TestTenantTagImmutable(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+
+	server := addTestConnection(channeldpb.ConnectionType_SERVER)
+	server.SetTag(TenantTag, "a")
+	client := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	client.SetTag(TenantTag, "a")
+
+	handleSetConnectionTags(MessageContext{
+		MsgType:    channeldpb.MessageType_SET_CONNECTION_TAGS,
+		Msg:        &channeldpb.SetConnectionTagsMessage{ConnId: uint32(client.Id()), Tags: map[string]string{TenantTag: "b", "region": "eu"}},
+		Connection: server,
+		Channel:    globalChannel,
+	})
+	handleSetConnectionTags(MessageContext{
+		MsgType:    channeldpb.MessageType_SET_CONNECTION_TAGS,
+		Msg:        &channeldpb.SetConnectionTagsMessage{ConnId: uint32(client.Id()), TagsToRemove: []string{TenantTag}},
+		Connection: server,
+		Channel:    globalChannel,
+	})
+	assert.Equal(t, map[string]string{TenantTag: "a", "region": "eu"}, client.GetTags())
+}
+
+func TestTenantCrossSubUnsub(t *testing.T) {
+	InitLogs()
+	InitChannels()
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+	InitOperatorConnections("../../config/operator_fsm.json")
+
+	GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = ChannelSettingsType{
+		ACLSettings:     ACLSettingsType{Sub: ChannelAccessLevel_Any, Unsub: ChannelAccessLevel_Any},
+		AutoCreateOnSub: true,
+	}
+	defer delete(GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+
+	serverA := addTestConnection(channeldpb.ConnectionType_SERVER)
+	serverA.SetTag(TenantTag, "a")
+	clientA := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	clientA.SetTag(TenantTag, "a")
+	serverB := addTestConnection(channeldpb.ConnectionType_SERVER)
+	serverB.SetTag(TenantTag, "b")
+	clientB := addTestConnection(channeldpb.ConnectionType_CLIENT)
+	clientB.SetTag(TenantTag, "b")
+	operator := addTestConnection(channeldpb.ConnectionType_OPERATOR)
+	for _, c := range []*Connection{serverA, clientA, serverB, clientB} {
+		c.OnAuthenticated("")
+	}
+
+	chA, _ := CreateChannel(channeldpb.ChannelType_TEST, serverA)
+	chB, _ := CreateChannel(channeldpb.ChannelType_TEST, serverB)
+	// Stop the channel.Tick() goroutines
+	chA.removing = 1
+	chB.removing = 1
+	clientA.SubscribeToChannel(chA, nil)
+	clientB.SubscribeToChannel(chB, nil)
+
+	lastError := func(c *Connection) channeldpb.ErrorMessage_Code {
+		if msg, ok := c.latestMsg().(*channeldpb.ErrorMessage); ok {
+			return msg.Code
+		}
+		return channeldpb.ErrorMessage_UNKNOWN
+	}
+
+	// The client can't sub to the channel of the other tenant, nor auto-create the channel with the same id
+	handleSubToAutoCreatedChannel(MessageContext{
+		MsgType:    channeldpb.MessageType_SUB_TO_CHANNEL,
+		Msg:        &channeldpb.SubscribedToChannelMessage{ChannelType: channeldpb.ChannelType_TEST},
+		Connection: clientB,
+		Channel:    globalChannel,
+		ChannelId:  uint32(chA.id),
+	})
+	assert.Same(t, chA, GetChannel(chA.id))
+	assert.NotContains(t, chA.GetAllConnections(), clientB)
+
+	// The server can't sub the client of the other tenant to its channel
+	handleSubToChannel(MessageContext{
+		MsgType:    channeldpb.MessageType_SUB_TO_CHANNEL,
+		Msg:        &channeldpb.SubscribedToChannelMessage{ConnId: uint32(clientB.Id())},
+		Connection: serverA,
+		Channel:    chA,
+	})
+	assert.Equal(t, channeldpb.ErrorMessage_CONNECTION_NOT_FOUND, lastError(serverA))
+	assert.NotContains(t, chA.GetAllConnections(), clientB)
+
+	handleSubToChannels(MessageContext{
+		MsgType:    channeldpb.MessageType_SUB_TO_CHANNELS,
+		Msg:        &channeldpb.SubscribedToChannelsMessage{ConnId: uint32(clientB.Id()), ChannelIds: []uint32{uint32(chA.id)}},
+		Connection: serverA,
+		Channel:    globalChannel,
+	})
+	assert.Equal(t, channeldpb.ErrorMessage_CONNECTION_NOT_FOUND, lastError(serverA))
+	assert.NotContains(t, chA.GetAllConnections(), clientB)
+
+	// Nor sub its own client to the channel of the other tenant
+	handleSubToChannels(MessageContext{
+		MsgType:    channeldpb.MessageType_SUB_TO_CHANNELS,
+		Msg:        &channeldpb.SubscribedToChannelsMessage{ConnId: uint32(clientA.Id()), ChannelIds: []uint32{uint32(chB.id)}},
+		Connection: serverA,
+		Channel:    globalChannel,
+	})
+	if result, ok := serverA.latestMsg().(*channeldpb.SubscribedToChannelsResultMessage); assert.True(t, ok) {
+		assert.Equal(t, []uint32{uint32(chB.id)}, result.FailedChannelIds)
+	}
+	assert.NotContains(t, chB.GetAllConnections(), clientA)
+
+	// The server can't unsub the client of the other tenant
+	handleUnsubFromChannel(MessageContext{
+		MsgType:    channeldpb.MessageType_UNSUB_FROM_CHANNEL,
+		Msg:        &channeldpb.UnsubscribedFromChannelMessage{ConnId: uint32(clientB.Id())},
+		Connection: serverA,
+		Channel:    chB,
+	})
+	assert.Equal(t, channeldpb.ErrorMessage_CONNECTION_NOT_FOUND, lastError(serverA))
+	assert.Contains(t, chB.GetAllConnections(), clientB)
+
+	handleUnsubFromChannels(MessageContext{
+		MsgType:    channeldpb.MessageType_UNSUB_FROM_CHANNELS,
+		Msg:        &channeldpb.UnsubscribedFromChannelsMessage{ConnId: uint32(clientB.Id()), ChannelIds: []uint32{uint32(chB.id)}},
+		Connection: serverA,
+		Channel:    globalChannel,
+	})
+	assert.Equal(t, channeldpb.ErrorMessage_CONNECTION_NOT_FOUND, lastError(serverA))
+	assert.Contains(t, chB.GetAllConnections(), clientB)
+
+	// The operators in the default tenant can still force-unsub
+	handleUnsubFromChannel(MessageContext{
+		MsgType:    channeldpb.MessageType_UNSUB_FROM_CHANNEL,
+		Msg:        &channeldpb.UnsubscribedFromChannelMessage{ConnId: uint32(clientB.Id())},
+		Connection: operator,
+		Channel:    chB,
+	})
+	assert.NotContains(t, chB.GetAllConnections(), clientB)
+}
@@ -0,0 +1,20 @@
+-- Echoes the user-space message 100 back to the sender, rejects LIST_CHANNEL (5) from the clients, and doesn't broadcast
+-- to the clients.
+return {
+	handlers = {
+		[100] = function(ctx)
+			channeld.log("echo " .. ctx.payload)
+			channeld.send(ctx.connId, ctx.msgType, "echo:" .. ctx.payload)
+		end,
+	},
+	validators = {
+		[5] = function(ctx)
+			if ctx.connType == "CLIENT" then
+				return "clients can't list the channels"
+			end
+		end,
+	},
+	broadcastFilter = function(ctx, conn)
+		return conn.connType ~= "CLIENT"
+	end,
+}
@@ -0,0 +1,72 @@
+package channeld
+
+import (
+	"sync"
+)
+
+// The tag of the connection's user id. See Connection.UserId().
+const UserIdTag = "userId"
+
+// Recognizes the same account across the platforms and the devices, e.g. a Steam PIT and an iOS PIT linked to the same account.
+type UserStore interface {
+	// Returns the id of the account that the PIT is linked to, or an empty string if the PIT is not linked to any account.
+	ResolveUserId(pit string) (string, error)
+}
+
+// Links the PITs to the accounts in memory. Mostly for the development and the tests.
+type MemoryUserStore struct {
+	lock    sync.RWMutex
+	userIds map[string]string
+}
+
+func NewMemoryUserStore() *MemoryUserStore {
+	return &MemoryUserStore{userIds: make(map[string]string)}
+}
+
+func (s *MemoryUserStore) Link(pit string, userId string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.userIds[pit] = userId
+}
+
+func (s *MemoryUserStore) Unlink(pit string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.userIds, pit)
+}
+
+func (s *MemoryUserStore) ResolveUserId(pit string) (string, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.userIds[pit], nil
+}
+
+var userStore UserStore
+
+func SetUserStore(value UserStore) {
+	userStore = value
+}
+
+// Returns the PIT itself if there's no UserStore or the PIT is not linked.
+func resolveUserId(pit string) (string, error) {
+	if userStore == nil {
+		return pit, nil
+	}
+	userId, err := userStore.ResolveUserId(pit)
+	if err != nil {
+		return "", err
+	}
+	if userId == "" {
+		return pit, nil
+	}
+	return userId, nil
+}
+
+// Returns the id of the account resolved by the UserStore when authenticated, or the PIT if not linked to any account.
+// Returns an empty string if not authenticated. Goroutine-safe.
+//
+// The user id is also a tag of the connection (see UserIdTag), so it can be used in ACLSettingsType.SubRequiredTags and the tag filters.
+func (c *Connection) UserId() string {
+	userId, _ := c.GetTag(UserIdTag)
+	return userId
+}
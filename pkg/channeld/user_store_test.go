@@ -0,0 +1,79 @@
+package channeld
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingUserStore struct{}
+
+func (s *failingUserStore) ResolveUserId(pit string) (string, error) {
+	return "", errors.New("user store is down")
+}
+
+func TestUserStore(t *testing.T) {
+	InitLogs()
+	// The owner of the GLOBAL channel left by the other tests may not be able to send
+	useNewChannelRegistry(t)
+	InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_non_authoratative_fsm.json")
+	defer func() {
+		GlobalSettings.DuplicateLoginPolicy = DuplicateLoginPolicy_AllowBoth
+		SetUserStore(nil)
+	}()
+
+	// Log out the users in the end, so they're not duplicate logins in the next run
+	var conns []*Connection
+	defer func() {
+		for _, c := range conns {
+			c.Close()
+		}
+	}()
+	login := func(pit string) *Connection {
+		c := addTestConnection(channeldpb.ConnectionType_CLIENT)
+		conns = append(conns, c)
+		onAuthComplete(MessageContext{
+			MsgType:    channeldpb.MessageType_AUTH,
+			Msg:        &channeldpb.AuthMessage{PlayerIdentifierToken: pit},
+			Connection: c,
+			Channel:    globalChannel,
+		}, channeldpb.AuthResultMessage_SUCCESSFUL, pit)
+		return c
+	}
+
+	// No user store
+	c1 := login("steam:1")
+	assert.Equal(t, "steam:1", c1.UserId())
+	assert.Equal(t, "steam:1", c1.latestMsg().(*channeldpb.AuthResultMessage).UserId)
+
+	store := NewMemoryUserStore()
+	store.Link("steam:2", "user2")
+	store.Link("ios:2", "user2")
+	SetUserStore(store)
+
+	c2 := login("steam:2")
+	assert.Equal(t, "user2", c2.UserId())
+	assert.Equal(t, "user2", c2.latestMsg().(*channeldpb.AuthResultMessage).UserId)
+	assert.True(t, MatchTags(c2, map[string]string{UserIdTag: "user2"}))
+	// Not linked
+	c3 := login("ios:3")
+	assert.Equal(t, "ios:3", c3.UserId())
+
+	// The same account on another device is a duplicate login
+	GlobalSettings.DuplicateLoginPolicy = DuplicateLoginPolicy_RejectNew
+	c4 := login("ios:2")
+	assert.Equal(t, channeldpb.AuthResultMessage_DUPLICATE_LOGIN, c4.latestMsg().(*channeldpb.AuthResultMessage).Result)
+	assert.Empty(t, c4.UserId())
+
+	store.Unlink("ios:2")
+	c5 := login("ios:2")
+	assert.Equal(t, ConnectionState_READY, c5.State())
+	assert.Equal(t, "ios:2", c5.UserId())
+
+	SetUserStore(&failingUserStore{})
+	c6 := login("steam:6")
+	assert.Equal(t, channeldpb.AuthResultMessage_INVALID_PIT, c6.latestMsg().(*channeldpb.AuthResultMessage).Result)
+	assert.NotEqual(t, ConnectionState_READY, c6.State())
+}
@@ -3,7 +3,6 @@ package channeld
 import (
 	"hash/maphash"
 	"net"
-	"strings"
 	"sync"
 
 	"github.com/metaworking/channeld/pkg/common"
@@ -28,7 +27,10 @@ func GetIP(addr net.Addr) string {
 	case *net.TCPAddr:
 		return addr.IP.String()
 	}
-	return strings.Split(addr.String(), ":")[0]
+	if host, _, err := net.SplitHostPort(addr.String()); err == nil {
+		return host
+	}
+	return addr.String()
 }
 
 func GetNextId(m *map[uint32]interface{}, start uint32, min uint32, max uint32) (uint32, bool) {
@@ -0,0 +1,142 @@
+package channeld
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+)
+
+// The version of the wire format. channeld replies in the version of the first packet that the connection sends,
+// so the existing SDKs keep using v1, and the v2 SDKs know that v2 is accepted when they receive a v2 packet.
+type WireVersion uint8
+
+const (
+	// 'CH', the size of the body in 2 bytes (big-endian), and the compression type. See PacketHeaderSize.
+	WireVersion_V1 WireVersion = 1
+	// 'CV', the flags, the size in varint, then the optional header extension. The size includes the extension.
+	WireVersion_V2 WireVersion = 2
+)
+
+// The flags in the v2 header
+const (
+	// The body is compressed with Snappy.
+	WireFlag_Compressed byte = 1 << 0
+	// The body is encrypted with the session key. Only used by the connections that have requested the encryption.
+	// See SessionKey.
+	WireFlag_Encrypted byte = 1 << 1
+	// The body is a channeldpb.Packet. Otherwise it's a single channeldpb.MessagePack.
+	WireFlag_Batched byte = 1 << 2
+	// The size is followed by the header extension: the size of the extension in varint, and the extension bytes.
+	// The readers that don't understand the extension skip it.
+	WireFlag_Extended byte = 1 << 7
+
+	supportedWireFlags = WireFlag_Compressed | WireFlag_Encrypted | WireFlag_Batched | WireFlag_Extended
+)
+
+// The max size of the v2 header without the extension. The read buffer should be no less than MaxPacketSize+MaxPacketHeaderSize.
+const MaxPacketHeaderSize int = 3 + binary.MaxVarintLen32
+
+var ErrInvalidPacketHeader = errors.New("invalid packet header")
+var ErrUnsupportedWireFlags = errors.New("unsupported wire flags")
+
+// The size in the v2 header exceeds MaxPacketSize. The returned header is still complete, so the packet can be skipped.
+var ErrPacketOversized = fmt.Errorf("%w: the packet exceeds MaxPacketSize", ErrInvalidPacketHeader)
+
+type PacketHeader struct {
+	Version WireVersion
+	// For v1, the compression type is converted to WireFlag_Compressed, and WireFlag_Batched is always set.
+	Flags byte
+	// Including the extension
+	HeaderSize int
+	BodySize   int
+}
+
+func (h *PacketHeader) FullSize() int {
+	return h.HeaderSize + h.BodySize
+}
+
+// Reads the header at the beginning of the buffer. Returns false if the buffer doesn't contain the whole header yet.
+func ReadPacketHeader(buf []byte) (PacketHeader, bool, error) {
+	if len(buf) < 2 {
+		return PacketHeader{}, false, nil
+	}
+	if buf[0] != 67 {
+		return PacketHeader{}, false, ErrInvalidPacketHeader
+	}
+
+	switch buf[1] {
+	case 72:
+		if len(buf) < PacketHeaderSize {
+			return PacketHeader{}, false, nil
+		}
+		size := readSize(buf)
+		if size == 0 {
+			return PacketHeader{}, false, ErrInvalidPacketHeader
+		}
+		header := PacketHeader{Version: WireVersion_V1, Flags: WireFlag_Batched, HeaderSize: PacketHeaderSize, BodySize: size}
+		if buf[4] == byte(channeldpb.CompressionType_SNAPPY) {
+			header.Flags |= WireFlag_Compressed
+		}
+		return header, true, nil
+
+	case 86:
+		if len(buf) < 4 {
+			return PacketHeader{}, false, nil
+		}
+		header := PacketHeader{Version: WireVersion_V2, Flags: buf[2]}
+		if header.Flags&^supportedWireFlags != 0 {
+			return header, false, ErrUnsupportedWireFlags
+		}
+		size, n := binary.Uvarint(buf[3:])
+		if n == 0 {
+			return PacketHeader{}, false, nil
+		}
+		if n < 0 || size == 0 || size > math.MaxInt32 {
+			return PacketHeader{}, false, ErrInvalidPacketHeader
+		}
+		if size > uint64(MaxPacketSize) {
+			// The extension, if any, is skipped along with the body
+			return PacketHeader{Version: WireVersion_V2, Flags: header.Flags, HeaderSize: 3 + n, BodySize: int(size)}, true, ErrPacketOversized
+		}
+		header.HeaderSize = 3 + n
+		header.BodySize = int(size)
+		if header.Flags&WireFlag_Extended != 0 {
+			extSize, m := binary.Uvarint(buf[header.HeaderSize:])
+			if m == 0 {
+				return PacketHeader{}, false, nil
+			}
+			if m < 0 || uint64(m)+extSize > size {
+				return PacketHeader{}, false, ErrInvalidPacketHeader
+			}
+			header.HeaderSize += m + int(extSize)
+			header.BodySize -= m + int(extSize)
+		}
+		return header, true, nil
+
+	default:
+		return PacketHeader{}, false, ErrInvalidPacketHeader
+	}
+}
+
+// Writes the header right before the body, which starts at buf[MaxPacketHeaderSize], and returns the whole packet.
+// The header extension is not supported in writing.
+func PutPacketHeader(buf []byte, bodySize int, version WireVersion, flags byte) []byte {
+	var header []byte
+	if version == WireVersion_V2 {
+		var tmp [MaxPacketHeaderSize]byte
+		tmp[0], tmp[1], tmp[2] = 67, 86, flags&^WireFlag_Extended
+		header = tmp[:3+binary.PutUvarint(tmp[3:], uint64(bodySize))]
+	} else {
+		ct := channeldpb.CompressionType_NO_COMPRESSION
+		if flags&WireFlag_Compressed != 0 {
+			ct = channeldpb.CompressionType_SNAPPY
+		}
+		header = []byte{67, 72, byte((bodySize >> 8) & 0xff), byte(bodySize & 0xff), byte(ct)}
+	}
+	start := MaxPacketHeaderSize - len(header)
+	copy(buf[start:], header)
+	return buf[start : MaxPacketHeaderSize+bodySize]
+}
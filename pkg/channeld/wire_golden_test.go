@@ -0,0 +1,155 @@
+package channeld
+
+import (
+	"bytes"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+// Regenerates the golden files of the wire protocol, after an intended change of the wire format:
+//
+//	go test ./pkg/channeld -run TestWireGolden -update
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+// Records every packet written to the connection
+type recordingConn struct {
+	loopbackConn
+	packets [][]byte
+}
+
+func (c *recordingConn) Write(b []byte) (n int, err error) {
+	c.packets = append(c.packets, append([]byte(nil), b...))
+	return len(b), nil
+}
+
+// Dumps the recorded packets in the format of the golden files, validating the header of each packet.
+func (c *recordingConn) dump(t *testing.T) []byte {
+	var buf bytes.Buffer
+	for i, packet := range c.packets {
+		header, complete, err := ReadPacketHeader(packet)
+		if assert.NoError(t, err) && assert.True(t, complete) {
+			assert.Equal(t, len(packet), header.FullSize(), "packet %d", i)
+		}
+		fmt.Fprintf(&buf, "# packet %d: v%d, flags 0x%02x, body %d bytes\n", i, header.Version, header.Flags, header.BodySize)
+		buf.WriteString(hex.Dump(packet))
+	}
+	return buf.Bytes()
+}
+
+func assertGolden(t *testing.T, name string, actual []byte) {
+	path := filepath.Join("testdata", "wire", name+".golden")
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, actual, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read the golden file, run with -update to create it: %v", err)
+	}
+	// Compare the strings so the diff is readable
+	assert.Equal(t, string(expected), string(actual), "the wire format has changed. If it's intended, run with -update and bump the ProtocolVersion if needed.")
+}
+
+// The scripted message exchanges whose byte streams are compared with the golden files, to catch the accidental change
+// of the wire format in the refactors of the packet encoder.
+func TestWireGolden(t *testing.T) {
+	InitLogs()
+	InitChannels()
+
+	userSpaceMsg := func(channelId uint32, stubId uint32, payload string) MessageContext {
+		return MessageContext{
+			MsgType:   channeldpb.MessageType_USER_SPACE_START,
+			Msg:       &channeldpb.ServerForwardMessage{ClientConnId: 7, Payload: []byte(payload)},
+			ChannelId: channelId,
+			StubId:    stubId,
+			Broadcast: uint32(channeldpb.BroadcastType_ALL_BUT_SENDER),
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		wireVersion WireVersion
+		compression channeldpb.CompressionType
+		// Called before sending
+		setup    func(c *Connection)
+		messages []MessageContext
+	}{
+		{
+			name:        "v1_batched",
+			wireVersion: WireVersion_V1,
+			messages: []MessageContext{
+				{MsgType: channeldpb.MessageType_AUTH, Msg: &channeldpb.AuthResultMessage{Result: channeldpb.AuthResultMessage_SUCCESSFUL, ConnId: 1, MaxPacketSize: 1200}},
+				userSpaceMsg(1, 2, "hello"),
+			},
+		},
+		{
+			name:        "v2_single",
+			wireVersion: WireVersion_V2,
+			messages:    []MessageContext{userSpaceMsg(0, 0, "hello")},
+		},
+		{
+			name:        "v2_batched",
+			wireVersion: WireVersion_V2,
+			messages: []MessageContext{
+				userSpaceMsg(1, 1, "hello"),
+				userSpaceMsg(2, 0, "world"),
+			},
+		},
+		{
+			name:        "v2_raw_payload",
+			wireVersion: WireVersion_V2,
+			messages: []MessageContext{func() MessageContext {
+				ctx := userSpaceMsg(1, 0, "raw")
+				ctx.rawPayload = true
+				return ctx
+			}()},
+		},
+		{
+			name:        "v2_snappy",
+			wireVersion: WireVersion_V2,
+			compression: channeldpb.CompressionType_SNAPPY,
+			messages:    []MessageContext{userSpaceMsg(1, 0, strings.Repeat("compress me ", 20))},
+		},
+		{
+			name:        "v2_chunked",
+			wireVersion: WireVersion_V2,
+			setup: func(c *Connection) {
+				c.setProtocolVersion(ProtocolVersion)
+				c.setMaxPacketSize(MinPacketSize)
+			},
+			messages: []MessageContext{userSpaceMsg(1, 3, strings.Repeat("0123456789abcdef", 60))},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newLoopbackConnection(tc.compression)
+			conn := &recordingConn{}
+			c.conn = conn
+			c.wireVersion = tc.wireVersion
+			if tc.setup != nil {
+				tc.setup(c)
+			}
+			for _, ctx := range tc.messages {
+				(&queuedMessagePackSender{}).Send(c, ctx)
+			}
+			for c.sendQueueLen() > 0 {
+				c.flush()
+			}
+			assertGolden(t, tc.name, conn.dump(t))
+		})
+	}
+}
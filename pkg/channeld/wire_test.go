@@ -0,0 +1,125 @@
+package channeld
+
+import (
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestReadPacketHeader(t *testing.T) {
+	// v1
+	header, complete, err := ReadPacketHeader([]byte{67, 72, 1, 2, 1})
+	assert.NoError(t, err)
+	assert.True(t, complete)
+	assert.Equal(t, PacketHeader{Version: WireVersion_V1, Flags: WireFlag_Batched | WireFlag_Compressed, HeaderSize: 5, BodySize: 258}, header)
+
+	_, complete, err = ReadPacketHeader([]byte{67, 72, 1})
+	assert.NoError(t, err)
+	assert.False(t, complete)
+
+	_, _, err = ReadPacketHeader([]byte{67, 72, 0, 0, 0})
+	assert.ErrorIs(t, err, ErrInvalidPacketHeader)
+	_, _, err = ReadPacketHeader([]byte{1, 2, 3, 4, 5})
+	assert.ErrorIs(t, err, ErrInvalidPacketHeader)
+
+	// v2 with the size of 300 in varint
+	header, complete, err = ReadPacketHeader([]byte{67, 86, WireFlag_Batched, 0xac, 0x02})
+	assert.NoError(t, err)
+	assert.True(t, complete)
+	assert.Equal(t, PacketHeader{Version: WireVersion_V2, Flags: WireFlag_Batched, HeaderSize: 5, BodySize: 300}, header)
+
+	// Unfinished varint
+	_, complete, err = ReadPacketHeader([]byte{67, 86, 0, 0xac})
+	assert.NoError(t, err)
+	assert.False(t, complete)
+
+	// The extension of 2 bytes is skipped
+	header, complete, err = ReadPacketHeader([]byte{67, 86, WireFlag_Extended, 10, 2, 0xff, 0xff})
+	assert.NoError(t, err)
+	assert.True(t, complete)
+	assert.Equal(t, 7, header.HeaderSize)
+	assert.Equal(t, 7, header.BodySize)
+	assert.Equal(t, 14, header.FullSize())
+
+	_, _, err = ReadPacketHeader([]byte{67, 86, 1 << 3, 10})
+	assert.ErrorIs(t, err, ErrUnsupportedWireFlags)
+	// Exceeds MaxPacketSize
+	_, _, err = ReadPacketHeader([]byte{67, 86, 0, 0x80, 0x80, 0x04})
+	assert.ErrorIs(t, err, ErrInvalidPacketHeader)
+}
+
+func TestPutPacketHeader(t *testing.T) {
+	for _, version := range []WireVersion{WireVersion_V1, WireVersion_V2} {
+		buf := make([]byte, MaxPacketHeaderSize+300)
+		packet := PutPacketHeader(buf, 300, version, WireFlag_Batched|WireFlag_Compressed)
+		header, complete, err := ReadPacketHeader(packet)
+		assert.NoError(t, err)
+		assert.True(t, complete)
+		assert.Equal(t, version, header.Version)
+		assert.Equal(t, WireFlag_Batched|WireFlag_Compressed, header.Flags)
+		assert.Equal(t, 300, header.BodySize)
+		assert.Equal(t, len(packet), header.FullSize())
+	}
+}
+
+func TestFlushAndReceiveWireV2(t *testing.T) {
+	InitLogs()
+	InitChannels()
+
+	for _, ct := range []channeldpb.CompressionType{channeldpb.CompressionType_NO_COMPRESSION, channeldpb.CompressionType_SNAPPY} {
+		c := newLoopbackConnection(ct)
+		c.wireVersion = WireVersion_V2
+		msgBody, _ := proto.Marshal(&testpb.TestChannelDataMessage{Text: "abc", Num: 123})
+		c.sendQueues[0] <- &channeldpb.MessagePack{ChannelId: 100, MsgType: uint32(channeldpb.MessageType_USER_SPACE_START), MsgBody: msgBody}
+		c.flush()
+
+		packet := c.conn.(*loopbackConn).packet
+		header, complete, err := ReadPacketHeader(packet)
+		assert.NoError(t, err)
+		assert.True(t, complete)
+		assert.Equal(t, WireVersion_V2, header.Version)
+		// The single message is not batched
+		assert.Zero(t, header.Flags&WireFlag_Batched)
+		body := packet[header.HeaderSize:]
+		if ct == channeldpb.CompressionType_SNAPPY {
+			assert.NotZero(t, header.Flags&WireFlag_Compressed)
+			body, _ = snappy.Decode(nil, body)
+		}
+		var mp channeldpb.MessagePack
+		assert.NoError(t, proto.Unmarshal(body, &mp))
+		assert.Equal(t, msgBody, mp.MsgBody)
+
+		// The channel doesn't exist, but the packet should be read
+		c.receive()
+		assert.Equal(t, 0, c.readPos)
+		assert.False(t, c.IsClosing())
+	}
+}
+
+func TestWireVersionNegotiation(t *testing.T) {
+	InitLogs()
+	InitChannels()
+
+	c := newLoopbackConnection(channeldpb.CompressionType_NO_COMPRESSION)
+	body, _ := proto.Marshal(&channeldpb.MessagePack{ChannelId: 100, MsgType: uint32(channeldpb.MessageType_USER_SPACE_START)})
+	buf := make([]byte, MaxPacketHeaderSize+len(body))
+	copy(buf[MaxPacketHeaderSize:], body)
+	n := copy(c.readBuffer, PutPacketHeader(buf, len(body), WireVersion_V2, 0))
+	c.readPos = n
+
+	bufPos := 0
+	_, err := c.readPacket(&bufPos)
+	assert.NoError(t, err)
+	assert.Equal(t, WireVersion_V2, c.wireVersion)
+
+	// The connection can't switch the version
+	v1Packet := PutPacketHeader(make([]byte, MaxPacketHeaderSize+1), 1, WireVersion_V1, WireFlag_Batched)
+	c.readPos = copy(c.readBuffer, v1Packet)
+	bufPos = 0
+	_, err = c.readPacket(&bufPos)
+	assert.Error(t, err)
+}
@@ -102,6 +102,9 @@ const (
 	ConnectionType_NO_CONNECTION ConnectionType = 0
 	ConnectionType_SERVER        ConnectionType = 1
 	ConnectionType_CLIENT        ConnectionType = 2
+	// The live-ops tooling. Authenticated by the operator auth provider, and has the elevated permissions
+	// to inspect any channel, force unsubscribing the connections, and inject the broadcasts.
+	ConnectionType_OPERATOR ConnectionType = 3
 )
 
 // Enum value maps for ConnectionType.
@@ -110,11 +113,13 @@ var (
 		0: "NO_CONNECTION",
 		1: "SERVER",
 		2: "CLIENT",
+		3: "OPERATOR",
 	}
 	ConnectionType_value = map[string]int32{
 		"NO_CONNECTION": 0,
 		"SERVER":        1,
 		"CLIENT":        2,
+		"OPERATOR":      3,
 	}
 )
 
@@ -260,6 +265,68 @@ const (
 	MessageType_ENTITY_GROUP_ADD MessageType = 16
 	// Used by @RemoveEntityGroupMessage
 	MessageType_ENTITY_GROUP_REMOVE MessageType = 17
+	// Used by both @SubscribedToChannelsMessage and @SubscribedToChannelsResultMessage
+	MessageType_SUB_TO_CHANNELS MessageType = 18
+	// Used by both @UnsubscribedFromChannelsMessage and @UnsubscribedFromChannelsResultMessage
+	MessageType_UNSUB_FROM_CHANNELS MessageType = 19
+	// Used by @SetConnectionTagsMessage
+	MessageType_SET_CONNECTION_TAGS MessageType = 20
+	// Used by both @QueryChannelDataMessage and @QueryChannelDataResultMessage
+	MessageType_QUERY_CHANNEL_DATA MessageType = 21
+	// Used by @RelayMessage
+	MessageType_RELAY MessageType = 22
+	// Used by both @ChannelTimerMessage and @TimerFiredMessage
+	MessageType_CHANNEL_TIMER MessageType = 23
+	// Used by @ChannelDataConflictMessage
+	MessageType_CHANNEL_DATA_CONFLICT MessageType = 24
+	// Used by both @ChannelDataLockMessage and @ChannelDataLockResultMessage
+	MessageType_CHANNEL_DATA_LOCK MessageType = 25
+	// Used by both @FetchHistoryMessage and @FetchHistoryResultMessage
+	MessageType_FETCH_HISTORY MessageType = 26
+	// Used by @ChannelDataResyncMessage
+	MessageType_CHANNEL_DATA_RESYNC MessageType = 27
+	// Used by @ChannelRecoveredMessage
+	MessageType_CHANNEL_RECOVERED MessageType = 28
+	// Used by @DuplicateLoginMessage
+	MessageType_DUPLICATE_LOGIN MessageType = 29
+	// Used by @SlowSubscriberMessage
+	MessageType_SLOW_SUBSCRIBER MessageType = 30
+	// Used by @QuotaExceededMessage
+	MessageType_QUOTA_EXCEEDED MessageType = 31
+	// Used by both @FetchArchiveMessage and @FetchArchiveResultMessage
+	MessageType_FETCH_ARCHIVE MessageType = 32
+	// Used by @PresenceUpdateMessage
+	MessageType_PRESENCE_UPDATE MessageType = 33
+	// Used by @UpdateBlockListMessage
+	MessageType_UPDATE_BLOCK_LIST MessageType = 34
+	// Used by @ChatPenaltyMessage
+	MessageType_CHAT_PENALTY MessageType = 35
+	// Used by both @FetchLeaderboardMessage and @FetchLeaderboardResultMessage
+	MessageType_FETCH_LEADERBOARD MessageType = 36
+	// Used by both @ChannelDataTransactionMessage and @ChannelDataTransactionResultMessage
+	MessageType_CHANNEL_DATA_TRANSACTION MessageType = 37
+	// Used by @PingMessage
+	MessageType_PING MessageType = 38
+	// Used by @ChannelOwnerChangedMessage
+	MessageType_CHANNEL_OWNER_CHANGED MessageType = 39
+	// Used by @AnalyticsMessage
+	MessageType_ANALYTICS MessageType = 40
+	// Used by @MessageTooLargeMessage
+	MessageType_MESSAGE_TOO_LARGE MessageType = 41
+	// Used by @ChannelStatsMessage
+	MessageType_CHANNEL_STATS MessageType = 42
+	// Used by @ErrorMessage
+	MessageType_ERROR MessageType = 43
+	// Used by @PauseDataFanOutMessage
+	MessageType_PAUSE_DATA_FAN_OUT MessageType = 44
+	// Used by @ChannelTickLagMessage
+	MessageType_CHANNEL_TICK_LAG MessageType = 45
+	// Used by @ServerBatchMessage
+	MessageType_SERVER_BATCH MessageType = 46
+	// Used by @BandwidthReportMessage
+	MessageType_BANDWIDTH_REPORT MessageType = 47
+	// Used by @SessionKeyRotationMessage
+	MessageType_SESSION_KEY_ROTATION MessageType = 48
 	// Used by @DebugGetSpatialRegionsMessage
 	MessageType_DEBUG_GET_SPATIAL_REGIONS MessageType = 99
 	// Start of any user-space defined message
@@ -286,6 +353,37 @@ var (
 		15:  "CREATE_ENTITY_CHANNEL",
 		16:  "ENTITY_GROUP_ADD",
 		17:  "ENTITY_GROUP_REMOVE",
+		18:  "SUB_TO_CHANNELS",
+		19:  "UNSUB_FROM_CHANNELS",
+		20:  "SET_CONNECTION_TAGS",
+		21:  "QUERY_CHANNEL_DATA",
+		22:  "RELAY",
+		23:  "CHANNEL_TIMER",
+		24:  "CHANNEL_DATA_CONFLICT",
+		25:  "CHANNEL_DATA_LOCK",
+		26:  "FETCH_HISTORY",
+		27:  "CHANNEL_DATA_RESYNC",
+		28:  "CHANNEL_RECOVERED",
+		29:  "DUPLICATE_LOGIN",
+		30:  "SLOW_SUBSCRIBER",
+		31:  "QUOTA_EXCEEDED",
+		32:  "FETCH_ARCHIVE",
+		33:  "PRESENCE_UPDATE",
+		34:  "UPDATE_BLOCK_LIST",
+		35:  "CHAT_PENALTY",
+		36:  "FETCH_LEADERBOARD",
+		37:  "CHANNEL_DATA_TRANSACTION",
+		38:  "PING",
+		39:  "CHANNEL_OWNER_CHANGED",
+		40:  "ANALYTICS",
+		41:  "MESSAGE_TOO_LARGE",
+		42:  "CHANNEL_STATS",
+		43:  "ERROR",
+		44:  "PAUSE_DATA_FAN_OUT",
+		45:  "CHANNEL_TICK_LAG",
+		46:  "SERVER_BATCH",
+		47:  "BANDWIDTH_REPORT",
+		48:  "SESSION_KEY_ROTATION",
 		99:  "DEBUG_GET_SPATIAL_REGIONS",
 		100: "USER_SPACE_START",
 	}
@@ -307,6 +405,37 @@ var (
 		"CREATE_ENTITY_CHANNEL":     15,
 		"ENTITY_GROUP_ADD":          16,
 		"ENTITY_GROUP_REMOVE":       17,
+		"SUB_TO_CHANNELS":           18,
+		"UNSUB_FROM_CHANNELS":       19,
+		"SET_CONNECTION_TAGS":       20,
+		"QUERY_CHANNEL_DATA":        21,
+		"RELAY":                     22,
+		"CHANNEL_TIMER":             23,
+		"CHANNEL_DATA_CONFLICT":     24,
+		"CHANNEL_DATA_LOCK":         25,
+		"FETCH_HISTORY":             26,
+		"CHANNEL_DATA_RESYNC":       27,
+		"CHANNEL_RECOVERED":         28,
+		"DUPLICATE_LOGIN":           29,
+		"SLOW_SUBSCRIBER":           30,
+		"QUOTA_EXCEEDED":            31,
+		"FETCH_ARCHIVE":             32,
+		"PRESENCE_UPDATE":           33,
+		"UPDATE_BLOCK_LIST":         34,
+		"CHAT_PENALTY":              35,
+		"FETCH_LEADERBOARD":         36,
+		"CHANNEL_DATA_TRANSACTION":  37,
+		"PING":                      38,
+		"CHANNEL_OWNER_CHANGED":     39,
+		"ANALYTICS":                 40,
+		"MESSAGE_TOO_LARGE":         41,
+		"CHANNEL_STATS":             42,
+		"ERROR":                     43,
+		"PAUSE_DATA_FAN_OUT":        44,
+		"CHANNEL_TICK_LAG":          45,
+		"SERVER_BATCH":              46,
+		"BANDWIDTH_REPORT":          47,
+		"SESSION_KEY_ROTATION":      48,
 		"DEBUG_GET_SPATIAL_REGIONS": 99,
 		"USER_SPACE_START":          100,
 	}
@@ -339,6 +468,60 @@ func (MessageType) EnumDescriptor() ([]byte, []int) {
 	return file_channeld_proto_rawDescGZIP(), []int{3}
 }
 
+// The format of the channel data (the value of the google.protobuf.Any) in @ChannelDataUpdateMessage and @QueryChannelDataResultMessage.
+// The lightweight clients that can't use the Protobuf descriptors of the channel data (e.g. Lua or JavaScript bots) can use JSON or MessagePack,
+// and channeld transcodes the channel data from/to the canonical Protobuf form. The type URL of the Any stays the same.
+type ChannelDataCodec int32
+
+const (
+	ChannelDataCodec_PROTOBUF ChannelDataCodec = 0
+	// The JSON mapping of Protobuf. See https://protobuf.dev/programming-guides/proto3/#json
+	ChannelDataCodec_JSON ChannelDataCodec = 1
+	// The same structure as the JSON mapping, but in MessagePack. See https://msgpack.org
+	ChannelDataCodec_MSGPACK ChannelDataCodec = 2
+)
+
+// Enum value maps for ChannelDataCodec.
+var (
+	ChannelDataCodec_name = map[int32]string{
+		0: "PROTOBUF",
+		1: "JSON",
+		2: "MSGPACK",
+	}
+	ChannelDataCodec_value = map[string]int32{
+		"PROTOBUF": 0,
+		"JSON":     1,
+		"MSGPACK":  2,
+	}
+)
+
+func (x ChannelDataCodec) Enum() *ChannelDataCodec {
+	p := new(ChannelDataCodec)
+	*p = x
+	return p
+}
+
+func (x ChannelDataCodec) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChannelDataCodec) Descriptor() protoreflect.EnumDescriptor {
+	return file_channeld_proto_enumTypes[4].Descriptor()
+}
+
+func (ChannelDataCodec) Type() protoreflect.EnumType {
+	return &file_channeld_proto_enumTypes[4]
+}
+
+func (x ChannelDataCodec) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChannelDataCodec.Descriptor instead.
+func (ChannelDataCodec) EnumDescriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{4}
+}
+
 type CompressionType int32
 
 const (
@@ -370,11 +553,11 @@ func (x CompressionType) String() string {
 }
 
 func (CompressionType) Descriptor() protoreflect.EnumDescriptor {
-	return file_channeld_proto_enumTypes[4].Descriptor()
+	return file_channeld_proto_enumTypes[5].Descriptor()
 }
 
 func (CompressionType) Type() protoreflect.EnumType {
-	return &file_channeld_proto_enumTypes[4]
+	return &file_channeld_proto_enumTypes[5]
 }
 
 func (x CompressionType) Number() protoreflect.EnumNumber {
@@ -383,7 +566,7 @@ func (x CompressionType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use CompressionType.Descriptor instead.
 func (CompressionType) EnumDescriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{4}
+	return file_channeld_proto_rawDescGZIP(), []int{5}
 }
 
 type ChannelDataAccess int32
@@ -419,11 +602,11 @@ func (x ChannelDataAccess) String() string {
 }
 
 func (ChannelDataAccess) Descriptor() protoreflect.EnumDescriptor {
-	return file_channeld_proto_enumTypes[5].Descriptor()
+	return file_channeld_proto_enumTypes[6].Descriptor()
 }
 
 func (ChannelDataAccess) Type() protoreflect.EnumType {
-	return &file_channeld_proto_enumTypes[5]
+	return &file_channeld_proto_enumTypes[6]
 }
 
 func (x ChannelDataAccess) Number() protoreflect.EnumNumber {
@@ -432,7 +615,7 @@ func (x ChannelDataAccess) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ChannelDataAccess.Descriptor instead.
 func (ChannelDataAccess) EnumDescriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{5}
+	return file_channeld_proto_rawDescGZIP(), []int{6}
 }
 
 type EntityGroupType int32
@@ -465,11 +648,11 @@ func (x EntityGroupType) String() string {
 }
 
 func (EntityGroupType) Descriptor() protoreflect.EnumDescriptor {
-	return file_channeld_proto_enumTypes[6].Descriptor()
+	return file_channeld_proto_enumTypes[7].Descriptor()
 }
 
 func (EntityGroupType) Type() protoreflect.EnumType {
-	return &file_channeld_proto_enumTypes[6]
+	return &file_channeld_proto_enumTypes[7]
 }
 
 func (x EntityGroupType) Number() protoreflect.EnumNumber {
@@ -478,7 +661,7 @@ func (x EntityGroupType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use EntityGroupType.Descriptor instead.
 func (EntityGroupType) EnumDescriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{6}
+	return file_channeld_proto_rawDescGZIP(), []int{7}
 }
 
 type AuthResultMessage_AuthResult int32
@@ -487,6 +670,10 @@ const (
 	AuthResultMessage_SUCCESSFUL  AuthResultMessage_AuthResult = 0
 	AuthResultMessage_INVALID_PIT AuthResultMessage_AuthResult = 1
 	AuthResultMessage_INVALID_LT  AuthResultMessage_AuthResult = 2
+	// Another connection has logged in with the same account (see @AuthResultMessage.userId), and the duplicate login policy rejects the new one.
+	AuthResultMessage_DUPLICATE_LOGIN AuthResultMessage_AuthResult = 3
+	// The tenant of the connection has reached its max connections. See @QuotaExceededMessage.
+	AuthResultMessage_QUOTA_EXCEEDED AuthResultMessage_AuthResult = 4
 )
 
 // Enum value maps for AuthResultMessage_AuthResult.
@@ -495,11 +682,15 @@ var (
 		0: "SUCCESSFUL",
 		1: "INVALID_PIT",
 		2: "INVALID_LT",
+		3: "DUPLICATE_LOGIN",
+		4: "QUOTA_EXCEEDED",
 	}
 	AuthResultMessage_AuthResult_value = map[string]int32{
-		"SUCCESSFUL":  0,
-		"INVALID_PIT": 1,
-		"INVALID_LT":  2,
+		"SUCCESSFUL":      0,
+		"INVALID_PIT":     1,
+		"INVALID_LT":      2,
+		"DUPLICATE_LOGIN": 3,
+		"QUOTA_EXCEEDED":  4,
 	}
 )
 
@@ -514,11 +705,11 @@ func (x AuthResultMessage_AuthResult) String() string {
 }
 
 func (AuthResultMessage_AuthResult) Descriptor() protoreflect.EnumDescriptor {
-	return file_channeld_proto_enumTypes[7].Descriptor()
+	return file_channeld_proto_enumTypes[8].Descriptor()
 }
 
 func (AuthResultMessage_AuthResult) Type() protoreflect.EnumType {
-	return &file_channeld_proto_enumTypes[7]
+	return &file_channeld_proto_enumTypes[8]
 }
 
 func (x AuthResultMessage_AuthResult) Number() protoreflect.EnumNumber {
@@ -527,7 +718,427 @@ func (x AuthResultMessage_AuthResult) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use AuthResultMessage_AuthResult.Descriptor instead.
 func (AuthResultMessage_AuthResult) EnumDescriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{4, 0}
+	return file_channeld_proto_rawDescGZIP(), []int{5, 0}
+}
+
+type ErrorMessage_Code int32
+
+const (
+	ErrorMessage_UNKNOWN ErrorMessage_Code = 0
+	// The channel of the message doesn't exist, or is invisible to the connection
+	ErrorMessage_CHANNEL_NOT_FOUND ErrorMessage_Code = 1
+	// The connection specified in the message doesn't exist
+	ErrorMessage_CONNECTION_NOT_FOUND ErrorMessage_Code = 2
+	// The message type is not defined, or is not allowed in the current state of the connection
+	ErrorMessage_MESSAGE_NOT_ALLOWED ErrorMessage_Code = 3
+	// The message can't be unmarshalled or decoded
+	ErrorMessage_INVALID_MESSAGE ErrorMessage_Code = 4
+	// The message is not allowed to be sent to the channel, e.g. creating a channel outside the GLOBAL channel
+	ErrorMessage_WRONG_CHANNEL ErrorMessage_Code = 5
+	// The connection doesn't have the access (see ChannelSettings.ACLSettings in channeld), or the required tags
+	ErrorMessage_ACCESS_DENIED ErrorMessage_Code = 6
+	// The connection is not subscribed to the channel
+	ErrorMessage_NOT_SUBSCRIBED ErrorMessage_Code = 7
+	// The channel key is used by another channel of the tenant
+	ErrorMessage_CHANNEL_KEY_IN_USE ErrorMessage_Code = 8
+	// The arguments in the message are invalid, e.g. creating the UNKNOWN channel
+	ErrorMessage_INVALID_ARGUMENT ErrorMessage_Code = 9
+	// channeld fails to handle the message for the internal reason
+	ErrorMessage_INTERNAL ErrorMessage_Code = 10
+	// The channel is frozen as its owner has disconnected. See ChannelSettingsType.OwnerDisconnectPolicy in channeld.
+	ErrorMessage_CHANNEL_FROZEN ErrorMessage_Code = 11
+	// The channel data is not initialized yet
+	ErrorMessage_CHANNEL_DATA_NOT_INITIALIZED ErrorMessage_Code = 12
+)
+
+// Enum value maps for ErrorMessage_Code.
+var (
+	ErrorMessage_Code_name = map[int32]string{
+		0:  "UNKNOWN",
+		1:  "CHANNEL_NOT_FOUND",
+		2:  "CONNECTION_NOT_FOUND",
+		3:  "MESSAGE_NOT_ALLOWED",
+		4:  "INVALID_MESSAGE",
+		5:  "WRONG_CHANNEL",
+		6:  "ACCESS_DENIED",
+		7:  "NOT_SUBSCRIBED",
+		8:  "CHANNEL_KEY_IN_USE",
+		9:  "INVALID_ARGUMENT",
+		10: "INTERNAL",
+		11: "CHANNEL_FROZEN",
+		12: "CHANNEL_DATA_NOT_INITIALIZED",
+	}
+	ErrorMessage_Code_value = map[string]int32{
+		"UNKNOWN":                      0,
+		"CHANNEL_NOT_FOUND":            1,
+		"CONNECTION_NOT_FOUND":         2,
+		"MESSAGE_NOT_ALLOWED":          3,
+		"INVALID_MESSAGE":              4,
+		"WRONG_CHANNEL":                5,
+		"ACCESS_DENIED":                6,
+		"NOT_SUBSCRIBED":               7,
+		"CHANNEL_KEY_IN_USE":           8,
+		"INVALID_ARGUMENT":             9,
+		"INTERNAL":                     10,
+		"CHANNEL_FROZEN":               11,
+		"CHANNEL_DATA_NOT_INITIALIZED": 12,
+	}
+)
+
+func (x ErrorMessage_Code) Enum() *ErrorMessage_Code {
+	p := new(ErrorMessage_Code)
+	*p = x
+	return p
+}
+
+func (x ErrorMessage_Code) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorMessage_Code) Descriptor() protoreflect.EnumDescriptor {
+	return file_channeld_proto_enumTypes[9].Descriptor()
+}
+
+func (ErrorMessage_Code) Type() protoreflect.EnumType {
+	return &file_channeld_proto_enumTypes[9]
+}
+
+func (x ErrorMessage_Code) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorMessage_Code.Descriptor instead.
+func (ErrorMessage_Code) EnumDescriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{30, 0}
+}
+
+type ChatPenaltyMessage_Reason int32
+
+const (
+	// Sent the identical message too many times in a short time
+	ChatPenaltyMessage_REPEATED ChatPenaltyMessage_Reason = 0
+	// Mentioned too many users in a message
+	ChatPenaltyMessage_MENTION_SPAM ChatPenaltyMessage_Reason = 1
+)
+
+// Enum value maps for ChatPenaltyMessage_Reason.
+var (
+	ChatPenaltyMessage_Reason_name = map[int32]string{
+		0: "REPEATED",
+		1: "MENTION_SPAM",
+	}
+	ChatPenaltyMessage_Reason_value = map[string]int32{
+		"REPEATED":     0,
+		"MENTION_SPAM": 1,
+	}
+)
+
+func (x ChatPenaltyMessage_Reason) Enum() *ChatPenaltyMessage_Reason {
+	p := new(ChatPenaltyMessage_Reason)
+	*p = x
+	return p
+}
+
+func (x ChatPenaltyMessage_Reason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChatPenaltyMessage_Reason) Descriptor() protoreflect.EnumDescriptor {
+	return file_channeld_proto_enumTypes[10].Descriptor()
+}
+
+func (ChatPenaltyMessage_Reason) Type() protoreflect.EnumType {
+	return &file_channeld_proto_enumTypes[10]
+}
+
+func (x ChatPenaltyMessage_Reason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChatPenaltyMessage_Reason.Descriptor instead.
+func (ChatPenaltyMessage_Reason) EnumDescriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{36, 0}
+}
+
+type QuotaExceededMessage_QuotaType int32
+
+const (
+	// The max channels of the tenant
+	QuotaExceededMessage_CHANNELS QuotaExceededMessage_QuotaType = 0
+	// The max channels of the tenant in the channel type
+	QuotaExceededMessage_CHANNELS_OF_TYPE QuotaExceededMessage_QuotaType = 1
+	// The max authenticated connections of the tenant
+	QuotaExceededMessage_CONNECTIONS QuotaExceededMessage_QuotaType = 2
+	// The max bytes per second that the tenant's connections send to channeld. The packets are dropped when exceeded.
+	QuotaExceededMessage_BANDWIDTH QuotaExceededMessage_QuotaType = 3
+)
+
+// Enum value maps for QuotaExceededMessage_QuotaType.
+var (
+	QuotaExceededMessage_QuotaType_name = map[int32]string{
+		0: "CHANNELS",
+		1: "CHANNELS_OF_TYPE",
+		2: "CONNECTIONS",
+		3: "BANDWIDTH",
+	}
+	QuotaExceededMessage_QuotaType_value = map[string]int32{
+		"CHANNELS":         0,
+		"CHANNELS_OF_TYPE": 1,
+		"CONNECTIONS":      2,
+		"BANDWIDTH":        3,
+	}
+)
+
+func (x QuotaExceededMessage_QuotaType) Enum() *QuotaExceededMessage_QuotaType {
+	p := new(QuotaExceededMessage_QuotaType)
+	*p = x
+	return p
+}
+
+func (x QuotaExceededMessage_QuotaType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (QuotaExceededMessage_QuotaType) Descriptor() protoreflect.EnumDescriptor {
+	return file_channeld_proto_enumTypes[11].Descriptor()
+}
+
+func (QuotaExceededMessage_QuotaType) Type() protoreflect.EnumType {
+	return &file_channeld_proto_enumTypes[11]
+}
+
+func (x QuotaExceededMessage_QuotaType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use QuotaExceededMessage_QuotaType.Descriptor instead.
+func (QuotaExceededMessage_QuotaType) EnumDescriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{37, 0}
+}
+
+type ChannelDataTransactionMessage_Mutation_Op int32
+
+const (
+	// Sets the map entry to the value in the data.
+	ChannelDataTransactionMessage_Mutation_PUT ChannelDataTransactionMessage_Mutation_Op = 0
+	// Marks the map entry as removed (see @ChannelDataMergeOptions.shouldCheckRemovableMapField), so the map value
+	// message must have the 'removed' field. Fails if the entry doesn't exist.
+	ChannelDataTransactionMessage_Mutation_REMOVE ChannelDataTransactionMessage_Mutation_Op = 1
+	// Adds the delta to the numeric value of the map entry. The missing entry is created with the delta.
+	ChannelDataTransactionMessage_Mutation_INCREMENT ChannelDataTransactionMessage_Mutation_Op = 2
+)
+
+// Enum value maps for ChannelDataTransactionMessage_Mutation_Op.
+var (
+	ChannelDataTransactionMessage_Mutation_Op_name = map[int32]string{
+		0: "PUT",
+		1: "REMOVE",
+		2: "INCREMENT",
+	}
+	ChannelDataTransactionMessage_Mutation_Op_value = map[string]int32{
+		"PUT":       0,
+		"REMOVE":    1,
+		"INCREMENT": 2,
+	}
+)
+
+func (x ChannelDataTransactionMessage_Mutation_Op) Enum() *ChannelDataTransactionMessage_Mutation_Op {
+	p := new(ChannelDataTransactionMessage_Mutation_Op)
+	*p = x
+	return p
+}
+
+func (x ChannelDataTransactionMessage_Mutation_Op) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChannelDataTransactionMessage_Mutation_Op) Descriptor() protoreflect.EnumDescriptor {
+	return file_channeld_proto_enumTypes[12].Descriptor()
+}
+
+func (ChannelDataTransactionMessage_Mutation_Op) Type() protoreflect.EnumType {
+	return &file_channeld_proto_enumTypes[12]
+}
+
+func (x ChannelDataTransactionMessage_Mutation_Op) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChannelDataTransactionMessage_Mutation_Op.Descriptor instead.
+func (ChannelDataTransactionMessage_Mutation_Op) EnumDescriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{44, 0, 0}
+}
+
+type ChannelDataTransactionResultMessage_Result int32
+
+const (
+	ChannelDataTransactionResultMessage_APPLIED ChannelDataTransactionResultMessage_Result = 0
+	// The mutation is malformed, e.g. the field is not a map field of the channel data.
+	ChannelDataTransactionResultMessage_INVALID ChannelDataTransactionResultMessage_Result = 1
+	// The entry to REMOVE doesn't exist, or the entry to PUT with ifAbsent already exists.
+	ChannelDataTransactionResultMessage_PRECONDITION_FAILED ChannelDataTransactionResultMessage_Result = 2
+	// The value of the INCREMENT would be out of the range.
+	ChannelDataTransactionResultMessage_OUT_OF_RANGE ChannelDataTransactionResultMessage_Result = 3
+	// The entry is locked by another connection. See @ChannelDataLockMessage.
+	ChannelDataTransactionResultMessage_LOCKED ChannelDataTransactionResultMessage_Result = 4
+)
+
+// Enum value maps for ChannelDataTransactionResultMessage_Result.
+var (
+	ChannelDataTransactionResultMessage_Result_name = map[int32]string{
+		0: "APPLIED",
+		1: "INVALID",
+		2: "PRECONDITION_FAILED",
+		3: "OUT_OF_RANGE",
+		4: "LOCKED",
+	}
+	ChannelDataTransactionResultMessage_Result_value = map[string]int32{
+		"APPLIED":             0,
+		"INVALID":             1,
+		"PRECONDITION_FAILED": 2,
+		"OUT_OF_RANGE":        3,
+		"LOCKED":              4,
+	}
+)
+
+func (x ChannelDataTransactionResultMessage_Result) Enum() *ChannelDataTransactionResultMessage_Result {
+	p := new(ChannelDataTransactionResultMessage_Result)
+	*p = x
+	return p
+}
+
+func (x ChannelDataTransactionResultMessage_Result) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChannelDataTransactionResultMessage_Result) Descriptor() protoreflect.EnumDescriptor {
+	return file_channeld_proto_enumTypes[13].Descriptor()
+}
+
+func (ChannelDataTransactionResultMessage_Result) Type() protoreflect.EnumType {
+	return &file_channeld_proto_enumTypes[13]
+}
+
+func (x ChannelDataTransactionResultMessage_Result) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChannelDataTransactionResultMessage_Result.Descriptor instead.
+func (ChannelDataTransactionResultMessage_Result) EnumDescriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{45, 0}
+}
+
+type RelayMessage_Event int32
+
+const (
+	RelayMessage_DATA         RelayMessage_Event = 0
+	RelayMessage_CONNECTED    RelayMessage_Event = 1
+	RelayMessage_DISCONNECTED RelayMessage_Event = 2
+)
+
+// Enum value maps for RelayMessage_Event.
+var (
+	RelayMessage_Event_name = map[int32]string{
+		0: "DATA",
+		1: "CONNECTED",
+		2: "DISCONNECTED",
+	}
+	RelayMessage_Event_value = map[string]int32{
+		"DATA":         0,
+		"CONNECTED":    1,
+		"DISCONNECTED": 2,
+	}
+)
+
+func (x RelayMessage_Event) Enum() *RelayMessage_Event {
+	p := new(RelayMessage_Event)
+	*p = x
+	return p
+}
+
+func (x RelayMessage_Event) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RelayMessage_Event) Descriptor() protoreflect.EnumDescriptor {
+	return file_channeld_proto_enumTypes[14].Descriptor()
+}
+
+func (RelayMessage_Event) Type() protoreflect.EnumType {
+	return &file_channeld_proto_enumTypes[14]
+}
+
+func (x RelayMessage_Event) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RelayMessage_Event.Descriptor instead.
+func (RelayMessage_Event) EnumDescriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{56, 0}
+}
+
+type DisconnectMessage_Reason int32
+
+const (
+	// The connection is lost, or closed without a reason.
+	DisconnectMessage_UNSPECIFIED DisconnectMessage_Reason = 0
+	// Disconnected by a server or an operator.
+	DisconnectMessage_KICKED DisconnectMessage_Reason = 1
+	// Failed or timed out the auth, or the PIT is banned.
+	DisconnectMessage_AUTH_FAILURE DisconnectMessage_Reason = 2
+	// No traffic from the connection for a while.
+	DisconnectMessage_IDLE_TIMEOUT DisconnectMessage_Reason = 3
+	// channeld is shutting down or draining the connections.
+	DisconnectMessage_SERVER_DRAIN DisconnectMessage_Reason = 4
+	// Another connection has logged in with the same account. See @DuplicateLoginMessage.
+	DisconnectMessage_DUPLICATE_LOGIN DisconnectMessage_Reason = 5
+)
+
+// Enum value maps for DisconnectMessage_Reason.
+var (
+	DisconnectMessage_Reason_name = map[int32]string{
+		0: "UNSPECIFIED",
+		1: "KICKED",
+		2: "AUTH_FAILURE",
+		3: "IDLE_TIMEOUT",
+		4: "SERVER_DRAIN",
+		5: "DUPLICATE_LOGIN",
+	}
+	DisconnectMessage_Reason_value = map[string]int32{
+		"UNSPECIFIED":     0,
+		"KICKED":          1,
+		"AUTH_FAILURE":    2,
+		"IDLE_TIMEOUT":    3,
+		"SERVER_DRAIN":    4,
+		"DUPLICATE_LOGIN": 5,
+	}
+)
+
+func (x DisconnectMessage_Reason) Enum() *DisconnectMessage_Reason {
+	p := new(DisconnectMessage_Reason)
+	*p = x
+	return p
+}
+
+func (x DisconnectMessage_Reason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DisconnectMessage_Reason) Descriptor() protoreflect.EnumDescriptor {
+	return file_channeld_proto_enumTypes[15].Descriptor()
+}
+
+func (DisconnectMessage_Reason) Type() protoreflect.EnumType {
+	return &file_channeld_proto_enumTypes[15]
+}
+
+func (x DisconnectMessage_Reason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DisconnectMessage_Reason.Descriptor instead.
+func (DisconnectMessage_Reason) EnumDescriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{60, 0}
 }
 
 // The data packet that is sent between the endpoints. A packet can have multiple messages in the payload in one trip to improve the efficiency.
@@ -598,6 +1209,25 @@ type MessagePack struct {
 	MsgType uint32 `protobuf:"varint,4,opt,name=msgType,proto3" json:"msgType,omitempty"`
 	// The serialized message. It's Protobuf-marshalled byte array if the message is defined in @MessageType.
 	MsgBody []byte `protobuf:"bytes,5,opt,name=msgBody,proto3" json:"msgBody,omitempty"`
+	// Optional. The key of the named channel that the message is sent to. If set, channelId is ignored.
+	// See @CreateChannelMessage.channelKey.
+	ChannelKey string `protobuf:"bytes,6,opt,name=channelKey,proto3" json:"channelKey,omitempty"`
+	// Optional. Only works for the user-space messages without handlers in channeld. If true, msgBody is the payload itself
+	// (e.g. FlatBuffers or any raw bytes) rather than a marshalled @ServerForwardMessage, so both ends can skip the Protobuf marshalling.
+	// channeld forwards the payload as it is, with rawPayload = true, to the recipients.
+	// The broadcast sequence number and the tag filter in @ServerForwardMessage are not available for the raw payload.
+	RawPayload bool `protobuf:"varint,7,opt,name=rawPayload,proto3" json:"rawPayload,omitempty"`
+	// Only used when rawPayload is true. Same as @ServerForwardMessage.clientConnId.
+	ClientConnId uint32 `protobuf:"varint,8,opt,name=clientConnId,proto3" json:"clientConnId,omitempty"`
+	// Optional. Set if msgBody is only a chunk of the message, as the message doesn't fit in a packet (see MaxPacketSize).
+	// The receiver should append the msgBody to the previous chunks of the same channelId, channelKey and msgType,
+	// until the MessagePack without continued, which completes the message.
+	// Only sent to the connections whose protocol version is 7 or above.
+	Continued bool `protobuf:"varint,9,opt,name=continued,proto3" json:"continued,omitempty"`
+	// Optional. The trace (correlation) id of the request, e.g. a W3C trace-parent or a UUID, for joining the logs across
+	// the client, channeld and the backend servers. channeld logs it and preserves it when forwarding the user-space message,
+	// and sets it in the response to the control message.
+	TraceId string `protobuf:"bytes,10,opt,name=traceId,proto3" json:"traceId,omitempty"`
 }
 
 func (x *MessagePack) Reset() {
@@ -667,22 +1297,56 @@ func (x *MessagePack) GetMsgBody() []byte {
 	return nil
 }
 
-// The message that is used to carries user-space message and communicate between channeld and backend servers.
-// Users don't need to use this message directly if they are using a client library.
-type ServerForwardMessage struct {
+func (x *MessagePack) GetChannelKey() string {
+	if x != nil {
+		return x.ChannelKey
+	}
+	return ""
+}
+
+func (x *MessagePack) GetRawPayload() bool {
+	if x != nil {
+		return x.RawPayload
+	}
+	return false
+}
+
+func (x *MessagePack) GetClientConnId() uint32 {
+	if x != nil {
+		return x.ClientConnId
+	}
+	return 0
+}
+
+func (x *MessagePack) GetContinued() bool {
+	if x != nil {
+		return x.Continued
+	}
+	return false
+}
+
+func (x *MessagePack) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+// The message published to the external broker by channeld, for the channel bridged to a broker topic (see ChannelSettingsType.BrokerTopic in channeld).
+// The pack is either a @ChannelDataUpdateMessage, or a user-space message in @ServerForwardMessage. The same format is expected
+// for the messages injected back into the channel.
+type BrokerMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The client that sends the user-space message to server or server sends the user-space message to.
-	// If a server sends to channeld with clientConnId = 0, the message will be forwarded to the channel owner.
-	ClientConnId uint32 `protobuf:"varint,1,opt,name=clientConnId,proto3" json:"clientConnId,omitempty"`
-	// The user-space message. channeld leaves it as the original binary format.
-	Payload []byte `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	// The channeld instance that publishes the message. The messages published by the instance itself are not injected back.
+	Origin string       `protobuf:"bytes,1,opt,name=origin,proto3" json:"origin,omitempty"`
+	Pack   *MessagePack `protobuf:"bytes,2,opt,name=pack,proto3" json:"pack,omitempty"`
 }
 
-func (x *ServerForwardMessage) Reset() {
-	*x = ServerForwardMessage{}
+func (x *BrokerMessage) Reset() {
+	*x = BrokerMessage{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_channeld_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -690,14 +1354,84 @@ func (x *ServerForwardMessage) Reset() {
 	}
 }
 
-func (x *ServerForwardMessage) String() string {
+func (x *BrokerMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BrokerMessage) ProtoMessage() {}
+
+func (x *BrokerMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BrokerMessage.ProtoReflect.Descriptor instead.
+func (*BrokerMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BrokerMessage) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *BrokerMessage) GetPack() *MessagePack {
+	if x != nil {
+		return x.Pack
+	}
+	return nil
+}
+
+// The message that is used to carries user-space message and communicate between channeld and backend servers.
+// Users don't need to use this message directly if they are using a client library.
+type ServerForwardMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The client that sends the user-space message to server or server sends the user-space message to.
+	// If a server sends to channeld with clientConnId = 0, the message will be forwarded to the channel owner.
+	ClientConnId uint32 `protobuf:"varint,1,opt,name=clientConnId,proto3" json:"clientConnId,omitempty"`
+	// The user-space message. channeld leaves it as the original binary format.
+	Payload []byte `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	// The sequence number of the broadcast in the channel, starting from 1. Only set by channeld when the channel retains the broadcasts.
+	// A subscriber can resume from the last received sequence number after reconnecting. See @SubscribedToChannelMessage.resumeFromSeq.
+	Seq uint64 `protobuf:"varint,3,opt,name=seq,proto3" json:"seq,omitempty"`
+	// Optional. When broadcasting, only the subscribers whose tags match the expression will receive the message, e.g. 'team == "red" && platform != "ios"'.
+	// See @SetConnectionTagsMessage for how to set the tags. Cleared by channeld before forwarding.
+	TagFilter string `protobuf:"bytes,4,opt,name=tagFilter,proto3" json:"tagFilter,omitempty"`
+	// Optional. Only the channel owner can set it. channeld holds the message and forwards it on the first tick of the channel
+	// after the delay (in the channel time), e.g. a "round starts" broadcast that should arrive exactly at the tick.
+	// Cleared by channeld before forwarding.
+	DelayMs uint32 `protobuf:"varint,5,opt,name=delayMs,proto3" json:"delayMs,omitempty"`
+}
+
+func (x *ServerForwardMessage) Reset() {
+	*x = ServerForwardMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServerForwardMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
 func (*ServerForwardMessage) ProtoMessage() {}
 
 func (x *ServerForwardMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_channeld_proto_msgTypes[2]
+	mi := &file_channeld_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -710,7 +1444,7 @@ func (x *ServerForwardMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ServerForwardMessage.ProtoReflect.Descriptor instead.
 func (*ServerForwardMessage) Descriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{2}
+	return file_channeld_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *ServerForwardMessage) GetClientConnId() uint32 {
@@ -727,6 +1461,27 @@ func (x *ServerForwardMessage) GetPayload() []byte {
 	return nil
 }
 
+func (x *ServerForwardMessage) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *ServerForwardMessage) GetTagFilter() string {
+	if x != nil {
+		return x.TagFilter
+	}
+	return ""
+}
+
+func (x *ServerForwardMessage) GetDelayMs() uint32 {
+	if x != nil {
+		return x.DelayMs
+	}
+	return 0
+}
+
 // The message should have channelId = 0 in order to be handled.
 // Response: @AuthResultMessage. The GLOBAL channel owner will also receive this message (to handle the client's subscription if it doesn't have the authority to).
 type AuthMessage struct {
@@ -736,12 +1491,27 @@ type AuthMessage struct {
 
 	PlayerIdentifierToken string `protobuf:"bytes,1,opt,name=playerIdentifierToken,proto3" json:"playerIdentifierToken,omitempty"`
 	LoginToken            string `protobuf:"bytes,2,opt,name=loginToken,proto3" json:"loginToken,omitempty"`
+	// Optional. The codec of the channel data that the connection uses. See @ChannelDataCodec.
+	ChannelDataCodec ChannelDataCodec `protobuf:"varint,3,opt,name=channelDataCodec,proto3,enum=channeldpb.ChannelDataCodec" json:"channelDataCodec,omitempty"`
+	// The version of this protocol that the SDK is built with. See ProtocolVersion in channeld.
+	// 0 means the SDK is built before the version exchange. channeld converts the messages for the older SDKs, see CompatShim in channeld.
+	ProtocolVersion uint32 `protobuf:"varint,4,opt,name=protocolVersion,proto3" json:"protocolVersion,omitempty"`
+	// Optional. Only for the SERVER connection. The @AuthResultMessage.resumeToken of the previous connection of the server,
+	// e.g. before a hot restart. The connection takes over the channels owned by the previous connection, including the frozen ones
+	// (see ChannelSettingsType.OwnerDisconnectPolicy in channeld), and receives the client messages buffered during the gap.
+	ResumeToken string `protobuf:"bytes,5,opt,name=resumeToken,proto3" json:"resumeToken,omitempty"`
+	// Optional. The max size of the packet body that the connection can receive, e.g. for the WebSocket or UDP transports
+	// that limit the frame size. 0 means the default (MaxPacketSize in channeld). See @AuthResultMessage.maxPacketSize.
+	MaxPacketSize uint32 `protobuf:"varint,6,opt,name=maxPacketSize,proto3" json:"maxPacketSize,omitempty"`
+	// Optional. The X25519 public key of the connection, to request the encryption of the packets in both directions.
+	// Only supported in the v2 wire format. See @AuthResultMessage.encryptionPublicKey.
+	EncryptionPublicKey []byte `protobuf:"bytes,7,opt,name=encryptionPublicKey,proto3" json:"encryptionPublicKey,omitempty"`
 }
 
 func (x *AuthMessage) Reset() {
 	*x = AuthMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_channeld_proto_msgTypes[3]
+		mi := &file_channeld_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -754,7 +1524,7 @@ func (x *AuthMessage) String() string {
 func (*AuthMessage) ProtoMessage() {}
 
 func (x *AuthMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_channeld_proto_msgTypes[3]
+	mi := &file_channeld_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -767,7 +1537,7 @@ func (x *AuthMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AuthMessage.ProtoReflect.Descriptor instead.
 func (*AuthMessage) Descriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{3}
+	return file_channeld_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *AuthMessage) GetPlayerIdentifierToken() string {
@@ -784,6 +1554,41 @@ func (x *AuthMessage) GetLoginToken() string {
 	return ""
 }
 
+func (x *AuthMessage) GetChannelDataCodec() ChannelDataCodec {
+	if x != nil {
+		return x.ChannelDataCodec
+	}
+	return ChannelDataCodec_PROTOBUF
+}
+
+func (x *AuthMessage) GetProtocolVersion() uint32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+func (x *AuthMessage) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+func (x *AuthMessage) GetMaxPacketSize() uint32 {
+	if x != nil {
+		return x.MaxPacketSize
+	}
+	return 0
+}
+
+func (x *AuthMessage) GetEncryptionPublicKey() []byte {
+	if x != nil {
+		return x.EncryptionPublicKey
+	}
+	return nil
+}
+
 type AuthResultMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -795,12 +1600,30 @@ type AuthResultMessage struct {
 	// However, because the compression type is specified per packet, the client has its freedom to control which compression type to use.
 	// It's useful when the client has too much CPU load for the compression, or the network debug is needed.
 	CompressionType CompressionType `protobuf:"varint,3,opt,name=compressionType,proto3,enum=channeldpb.CompressionType" json:"compressionType,omitempty"`
+	// The codec of the channel data that channeld uses for the connection. It's the requested @AuthMessage.channelDataCodec if supported, otherwise PROTOBUF.
+	ChannelDataCodec ChannelDataCodec `protobuf:"varint,4,opt,name=channelDataCodec,proto3,enum=channeldpb.ChannelDataCodec" json:"channelDataCodec,omitempty"`
+	// The account that the player identifier token belongs to, resolved by the UserStore in channeld. Same as the player identifier token if not linked to any account.
+	UserId string `protobuf:"bytes,5,opt,name=userId,proto3" json:"userId,omitempty"`
+	// The protocol version that channeld uses for the connection: the lower one of @AuthMessage.protocolVersion and channeld's.
+	ProtocolVersion uint32 `protobuf:"varint,6,opt,name=protocolVersion,proto3" json:"protocolVersion,omitempty"`
+	// Only set for the SERVER connection. See @AuthMessage.resumeToken.
+	ResumeToken string `protobuf:"bytes,7,opt,name=resumeToken,proto3" json:"resumeToken,omitempty"`
+	// The max size of the packet body in both directions: the requested @AuthMessage.maxPacketSize, clamped to the range that channeld supports.
+	// The larger messages are split into chunks (see @MessagePack.continued), or rejected with @MessageTooLargeMessage.
+	MaxPacketSize uint32 `protobuf:"varint,8,opt,name=maxPacketSize,proto3" json:"maxPacketSize,omitempty"`
+	// The X25519 public key of channeld for the session key 0, if the encryption is requested by @AuthMessage.encryptionPublicKey
+	// and supported. The packets after the one that carries this message are encrypted, as well as the packets that the connection
+	// sends after receiving it. See @SessionKeyRotationMessage.
+	// The key is not signed or otherwise authenticated (neither is @SessionKeyRotationMessage.publicKey), so the encryption only
+	// protects against the passive eavesdroppers. It gives no protection against the man-in-the-middle, who can replace the keys
+	// in both directions.
+	EncryptionPublicKey []byte `protobuf:"bytes,9,opt,name=encryptionPublicKey,proto3" json:"encryptionPublicKey,omitempty"`
 }
 
 func (x *AuthResultMessage) Reset() {
 	*x = AuthResultMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_channeld_proto_msgTypes[4]
+		mi := &file_channeld_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -813,7 +1636,7 @@ func (x *AuthResultMessage) String() string {
 func (*AuthResultMessage) ProtoMessage() {}
 
 func (x *AuthResultMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_channeld_proto_msgTypes[4]
+	mi := &file_channeld_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -826,7 +1649,7 @@ func (x *AuthResultMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AuthResultMessage.ProtoReflect.Descriptor instead.
 func (*AuthResultMessage) Descriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{4}
+	return file_channeld_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *AuthResultMessage) GetResult() AuthResultMessage_AuthResult {
@@ -850,6 +1673,48 @@ func (x *AuthResultMessage) GetCompressionType() CompressionType {
 	return CompressionType_NO_COMPRESSION
 }
 
+func (x *AuthResultMessage) GetChannelDataCodec() ChannelDataCodec {
+	if x != nil {
+		return x.ChannelDataCodec
+	}
+	return ChannelDataCodec_PROTOBUF
+}
+
+func (x *AuthResultMessage) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AuthResultMessage) GetProtocolVersion() uint32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+func (x *AuthResultMessage) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+func (x *AuthResultMessage) GetMaxPacketSize() uint32 {
+	if x != nil {
+		return x.MaxPacketSize
+	}
+	return 0
+}
+
+func (x *AuthResultMessage) GetEncryptionPublicKey() []byte {
+	if x != nil {
+		return x.EncryptionPublicKey
+	}
+	return nil
+}
+
 type ChannelSubscriptionOptions struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -873,12 +1738,42 @@ type ChannelSubscriptionOptions struct {
 	SkipSelfUpdateFanOut *bool `protobuf:"varint,5,opt,name=skipSelfUpdateFanOut,proto3,oneof" json:"skipSelfUpdateFanOut,omitempty"`
 	// Whether the subscriber should skip the first fan-out that contains the full states. Default is false.
 	SkipFirstFanOut *bool `protobuf:"varint,6,opt,name=skipFirstFanOut,proto3,oneof" json:"skipFirstFanOut,omitempty"`
+	// How often the fan-out carries the full states (keyframe) instead of the delta since the last fan-out, in millisecond.
+	// Useful for the subscribers over a lossy or unreliable transport, as they can recover from the next keyframe without requesting a resync.
+	// See @ChannelDataUpdateMessage.keyframe. Default is 0, which means no keyframe except the first fan-out.
+	KeyframeIntervalMs *uint32 `protobuf:"varint,7,opt,name=keyframeIntervalMs,proto3,oneof" json:"keyframeIntervalMs,omitempty"`
+	// Only for the GLOBAL channel. If true, the subscriber receives @PresenceUpdateMessage of the clients in its tenant:
+	// the full presence right after subscribing, then the changes in every tick of the GLOBAL channel.
+	Presence *bool `protobuf:"varint,8,opt,name=presence,proto3,oneof" json:"presence,omitempty"`
+	// Applied to every @PresenceInfo in @PresenceUpdateMessage, e.g. ["online"] to only receive the online status.
+	PresenceFieldMasks []string `protobuf:"bytes,9,rep,name=presenceFieldMasks,proto3" json:"presenceFieldMasks,omitempty"`
+	// The max size of each fan-out to the subscriber, in bytes. When exceeded, only the most relevant entities (ranked by the
+	// relevancy function registered in channeld) are included, and the others are deferred to the following fan-outs.
+	// Requires ChannelSettingsType.EntityMapField in channeld. Default is ChannelSettingsType.FanOutBudgetBytes; 0 means no budget.
+	FanOutBudgetBytes *uint32 `protobuf:"varint,10,opt,name=fanOutBudgetBytes,proto3,oneof" json:"fanOutBudgetBytes,omitempty"`
+	// If true, the fan-out interval adapts to how often the channel data changes: it's doubled after each fan-out that has
+	// no change, up to maxFanOutIntervalMs, and goes back to fanOutIntervalMs (as the floor) after a fan-out that merges
+	// more than one update. Saves the bandwidth on the mostly-static channels. Default is false.
+	AdaptiveFanOut *bool `protobuf:"varint,11,opt,name=adaptiveFanOut,proto3,oneof" json:"adaptiveFanOut,omitempty"`
+	// The ceiling of the adaptive fan-out interval, in millisecond. Only used if adaptiveFanOut is true.
+	// Default is 0, which means DefaultAdaptiveMaxFanOutIntervalMs in channeld.
+	MaxFanOutIntervalMs *uint32 `protobuf:"varint,12,opt,name=maxFanOutIntervalMs,proto3,oneof" json:"maxFanOutIntervalMs,omitempty"`
+	// If true, the delta fan-out carries the paths of the updated fields in @ChannelDataUpdateMessage.updatedFields. Default is false.
+	UpdatedFields *bool `protobuf:"varint,13,opt,name=updatedFields,proto3,oneof" json:"updatedFields,omitempty"`
+	// If true, the subscriber receives no channel data fan-out, and has no access to the channel data. Instead, it receives
+	// the user-space broadcasts, and @SubscribedToChannelResultMessage / @UnsubscribedFromChannelResultMessage when any other
+	// connection subscribes to or unsubscribes from the channel. Useful for the lobby list UIs and the moderation tools.
+	// Default is false.
+	EventsOnly *bool `protobuf:"varint,14,opt,name=eventsOnly,proto3,oneof" json:"eventsOnly,omitempty"`
+	// By default, the delta fan-out that is byte-identical to the previous fan-out to the subscriber is not sent, as it carries
+	// no change, e.g. when a server writes the full states periodically. If true, it's still sent. Default is false.
+	AllowDuplicateFanOut *bool `protobuf:"varint,15,opt,name=allowDuplicateFanOut,proto3,oneof" json:"allowDuplicateFanOut,omitempty"`
 }
 
 func (x *ChannelSubscriptionOptions) Reset() {
 	*x = ChannelSubscriptionOptions{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_channeld_proto_msgTypes[5]
+		mi := &file_channeld_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -891,7 +1786,7 @@ func (x *ChannelSubscriptionOptions) String() string {
 func (*ChannelSubscriptionOptions) ProtoMessage() {}
 
 func (x *ChannelSubscriptionOptions) ProtoReflect() protoreflect.Message {
-	mi := &file_channeld_proto_msgTypes[5]
+	mi := &file_channeld_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -904,7 +1799,7 @@ func (x *ChannelSubscriptionOptions) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ChannelSubscriptionOptions.ProtoReflect.Descriptor instead.
 func (*ChannelSubscriptionOptions) Descriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{5}
+	return file_channeld_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *ChannelSubscriptionOptions) GetDataAccess() ChannelDataAccess {
@@ -949,6 +1844,69 @@ func (x *ChannelSubscriptionOptions) GetSkipFirstFanOut() bool {
 	return false
 }
 
+func (x *ChannelSubscriptionOptions) GetKeyframeIntervalMs() uint32 {
+	if x != nil && x.KeyframeIntervalMs != nil {
+		return *x.KeyframeIntervalMs
+	}
+	return 0
+}
+
+func (x *ChannelSubscriptionOptions) GetPresence() bool {
+	if x != nil && x.Presence != nil {
+		return *x.Presence
+	}
+	return false
+}
+
+func (x *ChannelSubscriptionOptions) GetPresenceFieldMasks() []string {
+	if x != nil {
+		return x.PresenceFieldMasks
+	}
+	return nil
+}
+
+func (x *ChannelSubscriptionOptions) GetFanOutBudgetBytes() uint32 {
+	if x != nil && x.FanOutBudgetBytes != nil {
+		return *x.FanOutBudgetBytes
+	}
+	return 0
+}
+
+func (x *ChannelSubscriptionOptions) GetAdaptiveFanOut() bool {
+	if x != nil && x.AdaptiveFanOut != nil {
+		return *x.AdaptiveFanOut
+	}
+	return false
+}
+
+func (x *ChannelSubscriptionOptions) GetMaxFanOutIntervalMs() uint32 {
+	if x != nil && x.MaxFanOutIntervalMs != nil {
+		return *x.MaxFanOutIntervalMs
+	}
+	return 0
+}
+
+func (x *ChannelSubscriptionOptions) GetUpdatedFields() bool {
+	if x != nil && x.UpdatedFields != nil {
+		return *x.UpdatedFields
+	}
+	return false
+}
+
+func (x *ChannelSubscriptionOptions) GetEventsOnly() bool {
+	if x != nil && x.EventsOnly != nil {
+		return *x.EventsOnly
+	}
+	return false
+}
+
+func (x *ChannelSubscriptionOptions) GetAllowDuplicateFanOut() bool {
+	if x != nil && x.AllowDuplicateFanOut != nil {
+		return *x.AllowDuplicateFanOut
+	}
+	return false
+}
+
 // Defines how two @ChannelDataUpdateMessage.data are merged.
 // The custom merge function should always be implemented for the sake of performance. Otherwise,
 // the default merge that based on Protobuf's reflection will be used, and it's >10 times slower.
@@ -965,12 +1923,19 @@ type ChannelDataMergeOptions struct {
 	TruncateTop bool `protobuf:"varint,3,opt,name=truncateTop,proto3" json:"truncateTop,omitempty"`
 	// If true, the merge method will remove any map entry that has removed=true in its value.
 	ShouldCheckRemovableMapField bool `protobuf:"varint,4,opt,name=shouldCheckRemovableMapField,proto3" json:"shouldCheckRemovableMapField,omitempty"`
+	// If true, the channel data is merged as a CRDT, so the concurrent updates from multiple writers converge without an authoritative owner,
+	// regardless of the order they arrive in. The update is timestamped with @ChannelDataUpdateMessage.crdtTimestamp.
+	// - Each top-level field is a last-writer-wins (LWW) register. The older value is dropped. Lists and messages are replaced as a whole.
+	// - Each map field is an observed-remove set (OR-set) of the keys, with the values as the LWW registers.
+	//   A removal (removed=true in the value) doesn't remove the newer add that the remover couldn't have observed, so the concurrent add wins.
+	// The other merge options and the custom Merge() of the channel data are ignored.
+	Crdt bool `protobuf:"varint,5,opt,name=crdt,proto3" json:"crdt,omitempty"`
 }
 
 func (x *ChannelDataMergeOptions) Reset() {
 	*x = ChannelDataMergeOptions{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_channeld_proto_msgTypes[6]
+		mi := &file_channeld_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -983,7 +1948,7 @@ func (x *ChannelDataMergeOptions) String() string {
 func (*ChannelDataMergeOptions) ProtoMessage() {}
 
 func (x *ChannelDataMergeOptions) ProtoReflect() protoreflect.Message {
-	mi := &file_channeld_proto_msgTypes[6]
+	mi := &file_channeld_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -996,7 +1961,7 @@ func (x *ChannelDataMergeOptions) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ChannelDataMergeOptions.ProtoReflect.Descriptor instead.
 func (*ChannelDataMergeOptions) Descriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{6}
+	return file_channeld_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *ChannelDataMergeOptions) GetShouldReplaceList() bool {
@@ -1027,6 +1992,13 @@ func (x *ChannelDataMergeOptions) GetShouldCheckRemovableMapField() bool {
 	return false
 }
 
+func (x *ChannelDataMergeOptions) GetCrdt() bool {
+	if x != nil {
+		return x.Crdt
+	}
+	return false
+}
+
 // The message should have channelId = 0 in order to be handled.
 // Response: @CreateChannelResultMessage, if the MessageType is CREATE_CHANNEL and the channelType is not SPATIAL. The GLOBAL channel owner will also receive this message.
 // Response: @CreateSpatialChannelsResultMessage, if the MessageType is CREATE_SPATIAL_CHANNEL and the channelType is SPATIAL. The GLOBAL channel owner will also receive this message.
@@ -1041,12 +2013,17 @@ type CreateChannelMessage struct {
 	SubOptions   *ChannelSubscriptionOptions `protobuf:"bytes,3,opt,name=subOptions,proto3" json:"subOptions,omitempty"`
 	Data         *anypb.Any                  `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
 	MergeOptions *ChannelDataMergeOptions    `protobuf:"bytes,5,opt,name=mergeOptions,proto3" json:"mergeOptions,omitempty"`
+	// Optional. The unique key of the named channel, e.g. "guild:1234". The messages can be sent to the channel by @MessagePack.channelKey.
+	// The creation fails if the key is already used by another channel.
+	ChannelKey string `protobuf:"bytes,6,opt,name=channelKey,proto3" json:"channelKey,omitempty"`
+	// The schema version of the data. The data in an older version is migrated on load. See ChannelSettingsType.DataSchemaVersion in channeld.
+	DataSchemaVersion uint32 `protobuf:"varint,7,opt,name=dataSchemaVersion,proto3" json:"dataSchemaVersion,omitempty"`
 }
 
 func (x *CreateChannelMessage) Reset() {
 	*x = CreateChannelMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_channeld_proto_msgTypes[7]
+		mi := &file_channeld_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1059,7 +2036,7 @@ func (x *CreateChannelMessage) String() string {
 func (*CreateChannelMessage) ProtoMessage() {}
 
 func (x *CreateChannelMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_channeld_proto_msgTypes[7]
+	mi := &file_channeld_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1072,7 +2049,7 @@ func (x *CreateChannelMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateChannelMessage.ProtoReflect.Descriptor instead.
 func (*CreateChannelMessage) Descriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{7}
+	return file_channeld_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *CreateChannelMessage) GetChannelType() ChannelType {
@@ -1110,6 +2087,20 @@ func (x *CreateChannelMessage) GetMergeOptions() *ChannelDataMergeOptions {
 	return nil
 }
 
+func (x *CreateChannelMessage) GetChannelKey() string {
+	if x != nil {
+		return x.ChannelKey
+	}
+	return ""
+}
+
+func (x *CreateChannelMessage) GetDataSchemaVersion() uint32 {
+	if x != nil {
+		return x.DataSchemaVersion
+	}
+	return 0
+}
+
 type CreateChannelResultMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1119,13 +2110,14 @@ type CreateChannelResultMessage struct {
 	Metadata    string      `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
 	OwnerConnId uint32      `protobuf:"varint,3,opt,name=ownerConnId,proto3" json:"ownerConnId,omitempty"`
 	// The ID of the newly-created channel. Add this field to differentiate it from MessagePack.channelId.
-	ChannelId uint32 `protobuf:"varint,4,opt,name=channelId,proto3" json:"channelId,omitempty"`
+	ChannelId  uint32 `protobuf:"varint,4,opt,name=channelId,proto3" json:"channelId,omitempty"`
+	ChannelKey string `protobuf:"bytes,5,opt,name=channelKey,proto3" json:"channelKey,omitempty"`
 }
 
 func (x *CreateChannelResultMessage) Reset() {
 	*x = CreateChannelResultMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_channeld_proto_msgTypes[8]
+		mi := &file_channeld_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1138,7 +2130,7 @@ func (x *CreateChannelResultMessage) String() string {
 func (*CreateChannelResultMessage) ProtoMessage() {}
 
 func (x *CreateChannelResultMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_channeld_proto_msgTypes[8]
+	mi := &file_channeld_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1151,7 +2143,7 @@ func (x *CreateChannelResultMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateChannelResultMessage.ProtoReflect.Descriptor instead.
 func (*CreateChannelResultMessage) Descriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{8}
+	return file_channeld_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *CreateChannelResultMessage) GetChannelType() ChannelType {
@@ -1182,6 +2174,13 @@ func (x *CreateChannelResultMessage) GetChannelId() uint32 {
 	return 0
 }
 
+func (x *CreateChannelResultMessage) GetChannelKey() string {
+	if x != nil {
+		return x.ChannelKey
+	}
+	return ""
+}
+
 // The message should have channelId = 0 in order to be handled.
 // Response: all connections in the channel will receive @RemoveChannelMessage. The GLOBAL channel owner will also receive this message.
 type RemoveChannelMessage struct {
@@ -1195,7 +2194,7 @@ type RemoveChannelMessage struct {
 func (x *RemoveChannelMessage) Reset() {
 	*x = RemoveChannelMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_channeld_proto_msgTypes[9]
+		mi := &file_channeld_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1208,7 +2207,7 @@ func (x *RemoveChannelMessage) String() string {
 func (*RemoveChannelMessage) ProtoMessage() {}
 
 func (x *RemoveChannelMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_channeld_proto_msgTypes[9]
+	mi := &file_channeld_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1221,7 +2220,7 @@ func (x *RemoveChannelMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveChannelMessage.ProtoReflect.Descriptor instead.
 func (*RemoveChannelMessage) Descriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{9}
+	return file_channeld_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *RemoveChannelMessage) GetChannelId() uint32 {
@@ -1245,7 +2244,7 @@ type ListChannelMessage struct {
 func (x *ListChannelMessage) Reset() {
 	*x = ListChannelMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_channeld_proto_msgTypes[10]
+		mi := &file_channeld_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1258,7 +2257,7 @@ func (x *ListChannelMessage) String() string {
 func (*ListChannelMessage) ProtoMessage() {}
 
 func (x *ListChannelMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_channeld_proto_msgTypes[10]
+	mi := &file_channeld_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1271,7 +2270,7 @@ func (x *ListChannelMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListChannelMessage.ProtoReflect.Descriptor instead.
 func (*ListChannelMessage) Descriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{10}
+	return file_channeld_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *ListChannelMessage) GetTypeFilter() ChannelType {
@@ -1299,7 +2298,7 @@ type ListChannelResultMessage struct {
 func (x *ListChannelResultMessage) Reset() {
 	*x = ListChannelResultMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_channeld_proto_msgTypes[11]
+		mi := &file_channeld_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1312,7 +2311,7 @@ func (x *ListChannelResultMessage) String() string {
 func (*ListChannelResultMessage) ProtoMessage() {}
 
 func (x *ListChannelResultMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_channeld_proto_msgTypes[11]
+	mi := &file_channeld_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1325,7 +2324,7 @@ func (x *ListChannelResultMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListChannelResultMessage.ProtoReflect.Descriptor instead.
 func (*ListChannelResultMessage) Descriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{11}
+	return file_channeld_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *ListChannelResultMessage) GetChannels() []*ListChannelResultMessage_ChannelInfo {
@@ -1346,12 +2345,18 @@ type SubscribedToChannelMessage struct {
 	// Remarks: only the channel owner or the GLOBAL channel owner can sub another connection to the channel.
 	ConnId     uint32                      `protobuf:"varint,1,opt,name=connId,proto3" json:"connId,omitempty"`
 	SubOptions *ChannelSubscriptionOptions `protobuf:"bytes,2,opt,name=subOptions,proto3" json:"subOptions,omitempty"`
+	// If set, the retained user-space broadcasts with seq greater than the value will be sent to the subscribed connection in order.
+	// The replay is bounded by ChannelSettings.BroadcastRetentionSize, or served from the message archive if ChannelSettings.ArchiveBroadcasts is enabled.
+	ResumeFromSeq *uint64 `protobuf:"varint,3,opt,name=resumeFromSeq,proto3,oneof" json:"resumeFromSeq,omitempty"`
+	// Only used when the channel doesn't exist yet. If the channel type has ChannelSettings.AutoCreateOnSub enabled,
+	// the channel will be created with the channelId in the MessagePack on the first subscription.
+	ChannelType ChannelType `protobuf:"varint,4,opt,name=channelType,proto3,enum=channeldpb.ChannelType" json:"channelType,omitempty"`
 }
 
 func (x *SubscribedToChannelMessage) Reset() {
 	*x = SubscribedToChannelMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_channeld_proto_msgTypes[12]
+		mi := &file_channeld_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1364,7 +2369,7 @@ func (x *SubscribedToChannelMessage) String() string {
 func (*SubscribedToChannelMessage) ProtoMessage() {}
 
 func (x *SubscribedToChannelMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_channeld_proto_msgTypes[12]
+	mi := &file_channeld_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1377,7 +2382,7 @@ func (x *SubscribedToChannelMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubscribedToChannelMessage.ProtoReflect.Descriptor instead.
 func (*SubscribedToChannelMessage) Descriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{12}
+	return file_channeld_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *SubscribedToChannelMessage) GetConnId() uint32 {
@@ -1394,6 +2399,20 @@ func (x *SubscribedToChannelMessage) GetSubOptions() *ChannelSubscriptionOptions
 	return nil
 }
 
+func (x *SubscribedToChannelMessage) GetResumeFromSeq() uint64 {
+	if x != nil && x.ResumeFromSeq != nil {
+		return *x.ResumeFromSeq
+	}
+	return 0
+}
+
+func (x *SubscribedToChannelMessage) GetChannelType() ChannelType {
+	if x != nil {
+		return x.ChannelType
+	}
+	return ChannelType_UNKNOWN
+}
+
 type SubscribedToChannelResultMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1409,7 +2428,7 @@ type SubscribedToChannelResultMessage struct {
 func (x *SubscribedToChannelResultMessage) Reset() {
 	*x = SubscribedToChannelResultMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_channeld_proto_msgTypes[13]
+		mi := &file_channeld_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1422,7 +2441,7 @@ func (x *SubscribedToChannelResultMessage) String() string {
 func (*SubscribedToChannelResultMessage) ProtoMessage() {}
 
 func (x *SubscribedToChannelResultMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_channeld_proto_msgTypes[13]
+	mi := &file_channeld_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1435,7 +2454,7 @@ func (x *SubscribedToChannelResultMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubscribedToChannelResultMessage.ProtoReflect.Descriptor instead.
 func (*SubscribedToChannelResultMessage) Descriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{13}
+	return file_channeld_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *SubscribedToChannelResultMessage) GetConnId() uint32 {
@@ -1480,7 +2499,7 @@ type UnsubscribedFromChannelMessage struct {
 func (x *UnsubscribedFromChannelMessage) Reset() {
 	*x = UnsubscribedFromChannelMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_channeld_proto_msgTypes[14]
+		mi := &file_channeld_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1493,7 +2512,7 @@ func (x *UnsubscribedFromChannelMessage) String() string {
 func (*UnsubscribedFromChannelMessage) ProtoMessage() {}
 
 func (x *UnsubscribedFromChannelMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_channeld_proto_msgTypes[14]
+	mi := &file_channeld_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1506,7 +2525,7 @@ func (x *UnsubscribedFromChannelMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UnsubscribedFromChannelMessage.ProtoReflect.Descriptor instead.
 func (*UnsubscribedFromChannelMessage) Descriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{14}
+	return file_channeld_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *UnsubscribedFromChannelMessage) GetConnId() uint32 {
@@ -1530,7 +2549,7 @@ type UnsubscribedFromChannelResultMessage struct {
 func (x *UnsubscribedFromChannelResultMessage) Reset() {
 	*x = UnsubscribedFromChannelResultMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_channeld_proto_msgTypes[15]
+		mi := &file_channeld_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1543,7 +2562,7 @@ func (x *UnsubscribedFromChannelResultMessage) String() string {
 func (*UnsubscribedFromChannelResultMessage) ProtoMessage() {}
 
 func (x *UnsubscribedFromChannelResultMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_channeld_proto_msgTypes[15]
+	mi := &file_channeld_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1556,7 +2575,7 @@ func (x *UnsubscribedFromChannelResultMessage) ProtoReflect() protoreflect.Messa
 
 // Deprecated: Use UnsubscribedFromChannelResultMessage.ProtoReflect.Descriptor instead.
 func (*UnsubscribedFromChannelResultMessage) Descriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{15}
+	return file_channeld_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *UnsubscribedFromChannelResultMessage) GetConnId() uint32 {
@@ -1580,36 +2599,42 @@ func (x *UnsubscribedFromChannelResultMessage) GetChannelType() ChannelType {
 	return ChannelType_UNKNOWN
 }
 
-// Response: no. Each connection in the channel receives the @ChannelDataUpdateMessage in every @ChannelSubscriptionOptions.FanOutIntervalMs
-type ChannelDataUpdateMessage struct {
+// Subscribes a connection to multiple channels at once. The message should have channelId = 0 in order to be handled.
+// The subscriptions are processed atomically: if any of the channels doesn't exist or can't be accessed, none of them will be subscribed.
+// Response: @SubscribedToChannelsResultMessage. The message sender and the subscribed connection (if not the sender) will receive the message.
+// The owner of each channel will receive the @SubscribedToChannelResultMessage respectively.
+type SubscribedToChannelsMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Data *anypb.Any `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
-	// The ID of the connection that causes the update of the channel data.
-	// In a server-authoratative system (which means the @ChannelDataUpdateMessage will only be sent by server), the servers need to send this field to channeld.
-	// If the sender is a client, this field will be ignored.
-	ContextConnId uint32 `protobuf:"varint,2,opt,name=contextConnId,proto3" json:"contextConnId,omitempty"`
+	// The connection to be added to the channels is not necessarily the one sends the message.
+	// Remarks: only the channel owner or the GLOBAL channel owner can sub another connection to the channel.
+	ConnId     uint32   `protobuf:"varint,1,opt,name=connId,proto3" json:"connId,omitempty"`
+	ChannelIds []uint32 `protobuf:"varint,2,rep,packed,name=channelIds,proto3" json:"channelIds,omitempty"`
+	// The options shared by all the channels.
+	SubOptions *ChannelSubscriptionOptions `protobuf:"bytes,3,opt,name=subOptions,proto3" json:"subOptions,omitempty"`
+	// The options for specific channels. Merged on top of the shared options.
+	ChannelSubOptions map[uint32]*ChannelSubscriptionOptions `protobuf:"bytes,4,rep,name=channelSubOptions,proto3" json:"channelSubOptions,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
-func (x *ChannelDataUpdateMessage) Reset() {
-	*x = ChannelDataUpdateMessage{}
+func (x *SubscribedToChannelsMessage) Reset() {
+	*x = SubscribedToChannelsMessage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_channeld_proto_msgTypes[16]
+		mi := &file_channeld_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ChannelDataUpdateMessage) String() string {
+func (x *SubscribedToChannelsMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChannelDataUpdateMessage) ProtoMessage() {}
+func (*SubscribedToChannelsMessage) ProtoMessage() {}
 
-func (x *ChannelDataUpdateMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_channeld_proto_msgTypes[16]
+func (x *SubscribedToChannelsMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1620,89 +2645,55 @@ func (x *ChannelDataUpdateMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChannelDataUpdateMessage.ProtoReflect.Descriptor instead.
-func (*ChannelDataUpdateMessage) Descriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{16}
-}
-
-func (x *ChannelDataUpdateMessage) GetData() *anypb.Any {
-	if x != nil {
-		return x.Data
-	}
-	return nil
+// Deprecated: Use SubscribedToChannelsMessage.ProtoReflect.Descriptor instead.
+func (*SubscribedToChannelsMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *ChannelDataUpdateMessage) GetContextConnId() uint32 {
+func (x *SubscribedToChannelsMessage) GetConnId() uint32 {
 	if x != nil {
-		return x.ContextConnId
+		return x.ConnId
 	}
 	return 0
 }
 
-// Disconnect another connection from channeld.
-// This message should only be sent by the server connection in a server-authoratative environment.
-// The message should have channelId = 0 in order to be handled.
-// Response: no.
-type DisconnectMessage struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	ConnId uint32 `protobuf:"varint,1,opt,name=connId,proto3" json:"connId,omitempty"`
-}
-
-func (x *DisconnectMessage) Reset() {
-	*x = DisconnectMessage{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_channeld_proto_msgTypes[17]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *SubscribedToChannelsMessage) GetChannelIds() []uint32 {
+	if x != nil {
+		return x.ChannelIds
 	}
+	return nil
 }
 
-func (x *DisconnectMessage) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*DisconnectMessage) ProtoMessage() {}
-
-func (x *DisconnectMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_channeld_proto_msgTypes[17]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *SubscribedToChannelsMessage) GetSubOptions() *ChannelSubscriptionOptions {
+	if x != nil {
+		return x.SubOptions
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use DisconnectMessage.ProtoReflect.Descriptor instead.
-func (*DisconnectMessage) Descriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{17}
+	return nil
 }
 
-func (x *DisconnectMessage) GetConnId() uint32 {
+func (x *SubscribedToChannelsMessage) GetChannelSubOptions() map[uint32]*ChannelSubscriptionOptions {
 	if x != nil {
-		return x.ConnId
+		return x.ChannelSubOptions
 	}
-	return 0
+	return nil
 }
 
-// Left-handed coordinate system with Y-up rule.
-type SpatialInfo struct {
+type SubscribedToChannelsResultMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	X float64 `protobuf:"fixed64,1,opt,name=x,proto3" json:"x,omitempty"`
-	Y float64 `protobuf:"fixed64,2,opt,name=y,proto3" json:"y,omitempty"`
-	Z float64 `protobuf:"fixed64,3,opt,name=z,proto3" json:"z,omitempty"`
+	// The connection that subscribed.
+	ConnId   uint32         `protobuf:"varint,1,opt,name=connId,proto3" json:"connId,omitempty"`
+	ConnType ConnectionType `protobuf:"varint,2,opt,name=connType,proto3,enum=channeldpb.ConnectionType" json:"connType,omitempty"`
+	// The subscribed channels and their (merged) subscription options.
+	SubOptions map[uint32]*ChannelSubscriptionOptions `protobuf:"bytes,3,rep,name=subOptions,proto3" json:"subOptions,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The channels that caused the batch to fail. If not empty, none of the channels is subscribed.
+	FailedChannelIds []uint32 `protobuf:"varint,4,rep,packed,name=failedChannelIds,proto3" json:"failedChannelIds,omitempty"`
 }
 
-func (x *SpatialInfo) Reset() {
-	*x = SpatialInfo{}
+func (x *SubscribedToChannelsResultMessage) Reset() {
+	*x = SubscribedToChannelsResultMessage{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_channeld_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1710,13 +2701,13 @@ func (x *SpatialInfo) Reset() {
 	}
 }
 
-func (x *SpatialInfo) String() string {
+func (x *SubscribedToChannelsResultMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SpatialInfo) ProtoMessage() {}
+func (*SubscribedToChannelsResultMessage) ProtoMessage() {}
 
-func (x *SpatialInfo) ProtoReflect() protoreflect.Message {
+func (x *SubscribedToChannelsResultMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_channeld_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1728,44 +2719,54 @@ func (x *SpatialInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SpatialInfo.ProtoReflect.Descriptor instead.
-func (*SpatialInfo) Descriptor() ([]byte, []int) {
+// Deprecated: Use SubscribedToChannelsResultMessage.ProtoReflect.Descriptor instead.
+func (*SubscribedToChannelsResultMessage) Descriptor() ([]byte, []int) {
 	return file_channeld_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *SpatialInfo) GetX() float64 {
+func (x *SubscribedToChannelsResultMessage) GetConnId() uint32 {
 	if x != nil {
-		return x.X
+		return x.ConnId
 	}
 	return 0
 }
 
-func (x *SpatialInfo) GetY() float64 {
+func (x *SubscribedToChannelsResultMessage) GetConnType() ConnectionType {
 	if x != nil {
-		return x.Y
+		return x.ConnType
 	}
-	return 0
+	return ConnectionType_NO_CONNECTION
 }
 
-func (x *SpatialInfo) GetZ() float64 {
+func (x *SubscribedToChannelsResultMessage) GetSubOptions() map[uint32]*ChannelSubscriptionOptions {
 	if x != nil {
-		return x.Z
+		return x.SubOptions
 	}
-	return 0
+	return nil
 }
 
-type CreateSpatialChannelsResultMessage struct {
+func (x *SubscribedToChannelsResultMessage) GetFailedChannelIds() []uint32 {
+	if x != nil {
+		return x.FailedChannelIds
+	}
+	return nil
+}
+
+// Unsubscribes a connection from multiple channels at once. The message should have channelId = 0 in order to be handled.
+// The unsubscriptions are processed atomically, in the same way as @SubscribedToChannelsMessage.
+// Response: @UnsubscribedFromChannelsResultMessage. The message sender and the unsubscribed connection (if not the sender) will receive the message.
+// The owner of each channel will receive the @UnsubscribedFromChannelResultMessage respectively.
+type UnsubscribedFromChannelsMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SpatialChannelId []uint32 `protobuf:"varint,1,rep,packed,name=spatialChannelId,proto3" json:"spatialChannelId,omitempty"`
-	Metadata         string   `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
-	OwnerConnId      uint32   `protobuf:"varint,3,opt,name=ownerConnId,proto3" json:"ownerConnId,omitempty"`
+	ConnId     uint32   `protobuf:"varint,1,opt,name=connId,proto3" json:"connId,omitempty"`
+	ChannelIds []uint32 `protobuf:"varint,2,rep,packed,name=channelIds,proto3" json:"channelIds,omitempty"`
 }
 
-func (x *CreateSpatialChannelsResultMessage) Reset() {
-	*x = CreateSpatialChannelsResultMessage{}
+func (x *UnsubscribedFromChannelsMessage) Reset() {
+	*x = UnsubscribedFromChannelsMessage{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_channeld_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1773,13 +2774,13 @@ func (x *CreateSpatialChannelsResultMessage) Reset() {
 	}
 }
 
-func (x *CreateSpatialChannelsResultMessage) String() string {
+func (x *UnsubscribedFromChannelsMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateSpatialChannelsResultMessage) ProtoMessage() {}
+func (*UnsubscribedFromChannelsMessage) ProtoMessage() {}
 
-func (x *CreateSpatialChannelsResultMessage) ProtoReflect() protoreflect.Message {
+func (x *UnsubscribedFromChannelsMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_channeld_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1791,44 +2792,40 @@ func (x *CreateSpatialChannelsResultMessage) ProtoReflect() protoreflect.Message
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateSpatialChannelsResultMessage.ProtoReflect.Descriptor instead.
-func (*CreateSpatialChannelsResultMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use UnsubscribedFromChannelsMessage.ProtoReflect.Descriptor instead.
+func (*UnsubscribedFromChannelsMessage) Descriptor() ([]byte, []int) {
 	return file_channeld_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *CreateSpatialChannelsResultMessage) GetSpatialChannelId() []uint32 {
-	if x != nil {
-		return x.SpatialChannelId
-	}
-	return nil
-}
-
-func (x *CreateSpatialChannelsResultMessage) GetMetadata() string {
+func (x *UnsubscribedFromChannelsMessage) GetConnId() uint32 {
 	if x != nil {
-		return x.Metadata
+		return x.ConnId
 	}
-	return ""
+	return 0
 }
 
-func (x *CreateSpatialChannelsResultMessage) GetOwnerConnId() uint32 {
+func (x *UnsubscribedFromChannelsMessage) GetChannelIds() []uint32 {
 	if x != nil {
-		return x.OwnerConnId
+		return x.ChannelIds
 	}
-	return 0
+	return nil
 }
 
-// The message should have channelId = 0 in order to be handled.
-// Response: @QuerySpatialChannelResultMessage
-type QuerySpatialChannelMessage struct {
+type UnsubscribedFromChannelsResultMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SpatialInfo []*SpatialInfo `protobuf:"bytes,1,rep,name=spatialInfo,proto3" json:"spatialInfo,omitempty"`
+	// The connection that unsubscribed.
+	ConnId     uint32         `protobuf:"varint,1,opt,name=connId,proto3" json:"connId,omitempty"`
+	ConnType   ConnectionType `protobuf:"varint,2,opt,name=connType,proto3,enum=channeldpb.ConnectionType" json:"connType,omitempty"`
+	ChannelIds []uint32       `protobuf:"varint,3,rep,packed,name=channelIds,proto3" json:"channelIds,omitempty"`
+	// The channels that caused the batch to fail. If not empty, none of the channels is unsubscribed.
+	FailedChannelIds []uint32 `protobuf:"varint,4,rep,packed,name=failedChannelIds,proto3" json:"failedChannelIds,omitempty"`
 }
 
-func (x *QuerySpatialChannelMessage) Reset() {
-	*x = QuerySpatialChannelMessage{}
+func (x *UnsubscribedFromChannelsResultMessage) Reset() {
+	*x = UnsubscribedFromChannelsResultMessage{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_channeld_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1836,13 +2833,13 @@ func (x *QuerySpatialChannelMessage) Reset() {
 	}
 }
 
-func (x *QuerySpatialChannelMessage) String() string {
+func (x *UnsubscribedFromChannelsResultMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QuerySpatialChannelMessage) ProtoMessage() {}
+func (*UnsubscribedFromChannelsResultMessage) ProtoMessage() {}
 
-func (x *QuerySpatialChannelMessage) ProtoReflect() protoreflect.Message {
+func (x *UnsubscribedFromChannelsResultMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_channeld_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1854,28 +2851,56 @@ func (x *QuerySpatialChannelMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QuerySpatialChannelMessage.ProtoReflect.Descriptor instead.
-func (*QuerySpatialChannelMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use UnsubscribedFromChannelsResultMessage.ProtoReflect.Descriptor instead.
+func (*UnsubscribedFromChannelsResultMessage) Descriptor() ([]byte, []int) {
 	return file_channeld_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *QuerySpatialChannelMessage) GetSpatialInfo() []*SpatialInfo {
+func (x *UnsubscribedFromChannelsResultMessage) GetConnId() uint32 {
 	if x != nil {
-		return x.SpatialInfo
+		return x.ConnId
+	}
+	return 0
+}
+
+func (x *UnsubscribedFromChannelsResultMessage) GetConnType() ConnectionType {
+	if x != nil {
+		return x.ConnType
+	}
+	return ConnectionType_NO_CONNECTION
+}
+
+func (x *UnsubscribedFromChannelsResultMessage) GetChannelIds() []uint32 {
+	if x != nil {
+		return x.ChannelIds
 	}
 	return nil
 }
 
-type QuerySpatialChannelResultMessage struct {
+func (x *UnsubscribedFromChannelsResultMessage) GetFailedChannelIds() []uint32 {
+	if x != nil {
+		return x.FailedChannelIds
+	}
+	return nil
+}
+
+// Attaches key/value tags (e.g. platform, region, build version) to a connection. The tags can be used as ACL or broadcast filters.
+// Only the server connections can set the tags. The message should have channelId = 0 in order to be handled.
+// Response: no.
+type SetConnectionTagsMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ChannelId []uint32 `protobuf:"varint,1,rep,packed,name=channelId,proto3" json:"channelId,omitempty"`
+	ConnId uint32 `protobuf:"varint,1,opt,name=connId,proto3" json:"connId,omitempty"`
+	// The tags to add or overwrite.
+	Tags map[string]string `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The keys of the tags to remove.
+	TagsToRemove []string `protobuf:"bytes,3,rep,name=tagsToRemove,proto3" json:"tagsToRemove,omitempty"`
 }
 
-func (x *QuerySpatialChannelResultMessage) Reset() {
-	*x = QuerySpatialChannelResultMessage{}
+func (x *SetConnectionTagsMessage) Reset() {
+	*x = SetConnectionTagsMessage{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_channeld_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1883,13 +2908,13 @@ func (x *QuerySpatialChannelResultMessage) Reset() {
 	}
 }
 
-func (x *QuerySpatialChannelResultMessage) String() string {
+func (x *SetConnectionTagsMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QuerySpatialChannelResultMessage) ProtoMessage() {}
+func (*SetConnectionTagsMessage) ProtoMessage() {}
 
-func (x *QuerySpatialChannelResultMessage) ProtoReflect() protoreflect.Message {
+func (x *SetConnectionTagsMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_channeld_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1901,35 +2926,54 @@ func (x *QuerySpatialChannelResultMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QuerySpatialChannelResultMessage.ProtoReflect.Descriptor instead.
-func (*QuerySpatialChannelResultMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetConnectionTagsMessage.ProtoReflect.Descriptor instead.
+func (*SetConnectionTagsMessage) Descriptor() ([]byte, []int) {
 	return file_channeld_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *QuerySpatialChannelResultMessage) GetChannelId() []uint32 {
+func (x *SetConnectionTagsMessage) GetConnId() uint32 {
 	if x != nil {
-		return x.ChannelId
+		return x.ConnId
+	}
+	return 0
+}
+
+func (x *SetConnectionTagsMessage) GetTags() map[string]string {
+	if x != nil {
+		return x.Tags
 	}
 	return nil
 }
 
-// ALL connections in the source AND destination channels receive this messge when a handover happpned.
-// Handover means an object moves from a spatial channel to another. It doesn't necessarily mean the objece moves from a spatial server to another.
-type ChannelDataHandoverMessage struct {
+func (x *SetConnectionTagsMessage) GetTagsToRemove() []string {
+	if x != nil {
+		return x.TagsToRemove
+	}
+	return nil
+}
+
+// Response: no. Each connection in the channel receives the @ChannelDataUpdateMessage in every @ChannelSubscriptionOptions.FanOutIntervalMs
+// Changes the block list of the connection. The user-space broadcasts from the blocked users are not sent to the connection,
+// e.g. for the ignore list of the chat. The users are identified by the user id (see @AuthResultMessage.userId), so the list
+// keeps working after the blocked user reconnects. The list lives as long as the connection.
+// The message should be sent to the GLOBAL channel. Response: no.
+type UpdateBlockListMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SrcChannelId uint32 `protobuf:"varint,1,opt,name=srcChannelId,proto3" json:"srcChannelId,omitempty"`
-	DstChannelId uint32 `protobuf:"varint,2,opt,name=dstChannelId,proto3" json:"dstChannelId,omitempty"`
-	// The ID of the client connection that triggered the handover. If the handover is triggered by server (e.g. NPC movement), the value will be 0.
-	ContextConnId uint32 `protobuf:"varint,3,opt,name=contextConnId,proto3" json:"contextConnId,omitempty"`
-	// The data that migrate from the source channel to the destination channel. It can be the spatial channel data or anything, as long as the spatial servers can use it to process the handover.
-	Data *anypb.Any `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	// The connection whose block list is changed. 0 means the sender itself. Only the server connections can change the list of the other connections.
+	ConnId uint32 `protobuf:"varint,1,opt,name=connId,proto3" json:"connId,omitempty"`
+	// The user ids to block
+	BlockUserIds []string `protobuf:"bytes,2,rep,name=blockUserIds,proto3" json:"blockUserIds,omitempty"`
+	// The user ids to unblock
+	UnblockUserIds []string `protobuf:"bytes,3,rep,name=unblockUserIds,proto3" json:"unblockUserIds,omitempty"`
+	// If true, the list is cleared before blocking the blockUserIds.
+	Clear bool `protobuf:"varint,4,opt,name=clear,proto3" json:"clear,omitempty"`
 }
 
-func (x *ChannelDataHandoverMessage) Reset() {
-	*x = ChannelDataHandoverMessage{}
+func (x *UpdateBlockListMessage) Reset() {
+	*x = UpdateBlockListMessage{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_channeld_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1937,13 +2981,13 @@ func (x *ChannelDataHandoverMessage) Reset() {
 	}
 }
 
-func (x *ChannelDataHandoverMessage) String() string {
+func (x *UpdateBlockListMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChannelDataHandoverMessage) ProtoMessage() {}
+func (*UpdateBlockListMessage) ProtoMessage() {}
 
-func (x *ChannelDataHandoverMessage) ProtoReflect() protoreflect.Message {
+func (x *UpdateBlockListMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_channeld_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1955,52 +2999,99 @@ func (x *ChannelDataHandoverMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChannelDataHandoverMessage.ProtoReflect.Descriptor instead.
-func (*ChannelDataHandoverMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use UpdateBlockListMessage.ProtoReflect.Descriptor instead.
+func (*UpdateBlockListMessage) Descriptor() ([]byte, []int) {
 	return file_channeld_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *ChannelDataHandoverMessage) GetSrcChannelId() uint32 {
+func (x *UpdateBlockListMessage) GetConnId() uint32 {
 	if x != nil {
-		return x.SrcChannelId
+		return x.ConnId
 	}
 	return 0
 }
 
-func (x *ChannelDataHandoverMessage) GetDstChannelId() uint32 {
+func (x *UpdateBlockListMessage) GetBlockUserIds() []string {
 	if x != nil {
-		return x.DstChannelId
+		return x.BlockUserIds
 	}
-	return 0
+	return nil
 }
 
-func (x *ChannelDataHandoverMessage) GetContextConnId() uint32 {
+func (x *UpdateBlockListMessage) GetUnblockUserIds() []string {
 	if x != nil {
-		return x.ContextConnId
+		return x.UnblockUserIds
 	}
-	return 0
+	return nil
 }
 
-func (x *ChannelDataHandoverMessage) GetData() *anypb.Any {
+func (x *UpdateBlockListMessage) GetClear() bool {
 	if x != nil {
-		return x.Data
+		return x.Clear
 	}
-	return nil
+	return false
 }
 
-type SpatialRegion struct {
+type ChannelDataUpdateMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Min         *SpatialInfo `protobuf:"bytes,1,opt,name=min,proto3" json:"min,omitempty"`
-	Max         *SpatialInfo `protobuf:"bytes,2,opt,name=max,proto3" json:"max,omitempty"`
-	ChannelId   uint32       `protobuf:"varint,3,opt,name=channelId,proto3" json:"channelId,omitempty"`
-	ServerIndex uint32       `protobuf:"varint,4,opt,name=serverIndex,proto3" json:"serverIndex,omitempty"`
+	Data *anypb.Any `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	// The ID of the connection that causes the update of the channel data.
+	// In a server-authoratative system (which means the @ChannelDataUpdateMessage will only be sent by server), the servers need to send this field to channeld.
+	// If the sender is a client, this field will be ignored.
+	ContextConnId uint32 `protobuf:"varint,2,opt,name=contextConnId,proto3" json:"contextConnId,omitempty"`
+	// The version of the channel data that the subscriber has after applying the update, i.e. the number of the updates accepted by channeld.
+	// Only set by channeld when fanning out.
+	Version uint64 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	// Optional. The version of the channel data that the update is based on. If any of the versioned fields in the update
+	// (see ChannelSettingsType.VersionedFields in channeld) has been changed by another connection since the version,
+	// the whole update is rejected, and bounced back in @ChannelDataConflictMessage.
+	BaseVersion *uint64 `protobuf:"varint,4,opt,name=baseVersion,proto3,oneof" json:"baseVersion,omitempty"`
+	// The writer's clock when making the update, e.g. the Unix time in milliseconds or a hybrid logical clock.
+	// Only used when the channel data is merged as a CRDT (see @ChannelDataMergeOptions.crdt). 0 means using the Unix time in milliseconds when channeld receives it.
+	CrdtTimestamp int64 `protobuf:"varint,5,opt,name=crdtTimestamp,proto3" json:"crdtTimestamp,omitempty"`
+	// The checksum of the channel data that the subscriber should have after applying the update. See ChannelDataChecksum() in channeld's common package.
+	// Only set by channeld in the fan-out periodically (see ChannelSettingsType.ChecksumIntervalMs in channeld).
+	// If the subscriber's channel data has a different checksum, it should send @ChannelDataResyncMessage.
+	Checksum *uint32 `protobuf:"varint,6,opt,name=checksum,proto3,oneof" json:"checksum,omitempty"`
+	// The following fields are only set by channeld in the fan-out, if @ChannelSubscriptionOptions.keyframeIntervalMs is set.
+	// If true, the update contains the full states, and the subscriber should replace its channel data with it.
+	Keyframe bool `protobuf:"varint,7,opt,name=keyframe,proto3" json:"keyframe,omitempty"`
+	// The version of the last keyframe that the delta is based on.
+	// The subscriber that has missed the keyframe, or any delta after it (see previousVersion), should drop the deltas until the next keyframe.
+	KeyframeVersion *uint64 `protobuf:"varint,8,opt,name=keyframeVersion,proto3,oneof" json:"keyframeVersion,omitempty"`
+	// The version of the channel data in the previous fan-out to the subscriber. Not set in the keyframe.
+	PreviousVersion *uint64 `protobuf:"varint,9,opt,name=previousVersion,proto3,oneof" json:"previousVersion,omitempty"`
+	// The sequence (or tick) of the client's input that the update incorporates, for the client-side prediction.
+	// Set by the client that updates the channel data with its input, or by the server that has processed the input of the client in contextConnId.
+	// 0 means no input is acknowledged. The sequence should increase for each input of the client.
+	InputSeq uint64 `protobuf:"varint,10,opt,name=inputSeq,proto3" json:"inputSeq,omitempty"`
+	// Only set by channeld in the fan-out to a client: the inputSeq of the last input of the client that has been incorporated in the channel data.
+	// The client can drop the inputs up to the sequence, and re-apply the rest on top of the update to reconcile the prediction.
+	LastInputSeq *uint64 `protobuf:"varint,11,opt,name=lastInputSeq,proto3,oneof" json:"lastInputSeq,omitempty"`
+	// Only set by channeld in the fan-out if the channel has dead reckoning (see ChannelSettingsType.DeadReckoning in channeld).
+	// The entity whose position is not in the update should be extrapolated with its last velocity and acceleration, for up to the time.
+	MaxExtrapolationMs *uint32 `protobuf:"varint,12,opt,name=maxExtrapolationMs,proto3,oneof" json:"maxExtrapolationMs,omitempty"`
+	// The following fields are set by channeld in every fan-out, for the snapshot interpolation on the subscriber.
+	// The Unix time in milliseconds of the fan-out, i.e. when the channel data in the update is sampled.
+	ServerTimeMs int64 `protobuf:"varint,13,opt,name=serverTimeMs,proto3" json:"serverTimeMs,omitempty"`
+	// The number of the channel's tick that fans out the update. The fan-outs in the same tick have the same number.
+	Tick uint64 `protobuf:"varint,14,opt,name=tick,proto3" json:"tick,omitempty"`
+	// The schema version of the data. The update in an older version, e.g. sent by a server that hasn't been upgraded in a rolling deploy,
+	// or replayed from a recording, is migrated before being merged. See ChannelSettingsType.DataSchemaVersion in channeld.
+	// Not set in the fan-out, as the fanned-out data is always in the current version.
+	SchemaVersion uint32 `protobuf:"varint,15,opt,name=schemaVersion,proto3" json:"schemaVersion,omitempty"`
+	// Only set by channeld in the delta fan-out, if @ChannelSubscriptionOptions.updatedFields is true: the paths of the fields
+	// that the update touches, sorted, e.g. ["entities.42.position", "score"], so the subscriber can apply the update
+	// to the affected parts only. The map entries are addressed by the key, and the list fields are not expanded.
+	// The fields set to the default value are not included, as they are not serialized.
+	UpdatedFields []string `protobuf:"bytes,16,rep,name=updatedFields,proto3" json:"updatedFields,omitempty"`
 }
 
-func (x *SpatialRegion) Reset() {
-	*x = SpatialRegion{}
+func (x *ChannelDataUpdateMessage) Reset() {
+	*x = ChannelDataUpdateMessage{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_channeld_proto_msgTypes[23]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2008,13 +3099,13 @@ func (x *SpatialRegion) Reset() {
 	}
 }
 
-func (x *SpatialRegion) String() string {
+func (x *ChannelDataUpdateMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SpatialRegion) ProtoMessage() {}
+func (*ChannelDataUpdateMessage) ProtoMessage() {}
 
-func (x *SpatialRegion) ProtoReflect() protoreflect.Message {
+func (x *ChannelDataUpdateMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_channeld_proto_msgTypes[23]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2026,52 +3117,143 @@ func (x *SpatialRegion) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SpatialRegion.ProtoReflect.Descriptor instead.
-func (*SpatialRegion) Descriptor() ([]byte, []int) {
+// Deprecated: Use ChannelDataUpdateMessage.ProtoReflect.Descriptor instead.
+func (*ChannelDataUpdateMessage) Descriptor() ([]byte, []int) {
 	return file_channeld_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *SpatialRegion) GetMin() *SpatialInfo {
+func (x *ChannelDataUpdateMessage) GetData() *anypb.Any {
 	if x != nil {
-		return x.Min
+		return x.Data
 	}
 	return nil
 }
 
-func (x *SpatialRegion) GetMax() *SpatialInfo {
+func (x *ChannelDataUpdateMessage) GetContextConnId() uint32 {
 	if x != nil {
-		return x.Max
+		return x.ContextConnId
 	}
-	return nil
+	return 0
 }
 
-func (x *SpatialRegion) GetChannelId() uint32 {
+func (x *ChannelDataUpdateMessage) GetVersion() uint64 {
 	if x != nil {
-		return x.ChannelId
+		return x.Version
 	}
 	return 0
 }
 
-func (x *SpatialRegion) GetServerIndex() uint32 {
+func (x *ChannelDataUpdateMessage) GetBaseVersion() uint64 {
+	if x != nil && x.BaseVersion != nil {
+		return *x.BaseVersion
+	}
+	return 0
+}
+
+func (x *ChannelDataUpdateMessage) GetCrdtTimestamp() int64 {
 	if x != nil {
-		return x.ServerIndex
+		return x.CrdtTimestamp
 	}
 	return 0
 }
 
-// channeld updates the information of spatial channels and regions to the spatial servers.
-// Spatial servers use this information mainly for mapping the position of a spawned object to a correct channelId at realtime (rather than querying it before sending the spawn message to the client).
-// Sent upon the creation of spatial channels (after @CreateSpatialChannelsResultMessage being sent), or any regional change (basiclally caused by the loadbalancer).
-type SpatialRegionsUpdateMessage struct {
+func (x *ChannelDataUpdateMessage) GetChecksum() uint32 {
+	if x != nil && x.Checksum != nil {
+		return *x.Checksum
+	}
+	return 0
+}
+
+func (x *ChannelDataUpdateMessage) GetKeyframe() bool {
+	if x != nil {
+		return x.Keyframe
+	}
+	return false
+}
+
+func (x *ChannelDataUpdateMessage) GetKeyframeVersion() uint64 {
+	if x != nil && x.KeyframeVersion != nil {
+		return *x.KeyframeVersion
+	}
+	return 0
+}
+
+func (x *ChannelDataUpdateMessage) GetPreviousVersion() uint64 {
+	if x != nil && x.PreviousVersion != nil {
+		return *x.PreviousVersion
+	}
+	return 0
+}
+
+func (x *ChannelDataUpdateMessage) GetInputSeq() uint64 {
+	if x != nil {
+		return x.InputSeq
+	}
+	return 0
+}
+
+func (x *ChannelDataUpdateMessage) GetLastInputSeq() uint64 {
+	if x != nil && x.LastInputSeq != nil {
+		return *x.LastInputSeq
+	}
+	return 0
+}
+
+func (x *ChannelDataUpdateMessage) GetMaxExtrapolationMs() uint32 {
+	if x != nil && x.MaxExtrapolationMs != nil {
+		return *x.MaxExtrapolationMs
+	}
+	return 0
+}
+
+func (x *ChannelDataUpdateMessage) GetServerTimeMs() int64 {
+	if x != nil {
+		return x.ServerTimeMs
+	}
+	return 0
+}
+
+func (x *ChannelDataUpdateMessage) GetTick() uint64 {
+	if x != nil {
+		return x.Tick
+	}
+	return 0
+}
+
+func (x *ChannelDataUpdateMessage) GetSchemaVersion() uint32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *ChannelDataUpdateMessage) GetUpdatedFields() []string {
+	if x != nil {
+		return x.UpdatedFields
+	}
+	return nil
+}
+
+// Sent to the channel owner after the channel's goroutine recovered from a panic and restarted.
+// The message being handled when the panic happened is dropped.
+type ChannelRecoveredMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Regions []*SpatialRegion `protobuf:"bytes,1,rep,name=regions,proto3" json:"regions,omitempty"`
+	// How many times the channel has panicked
+	PanicCount uint32 `protobuf:"varint,1,opt,name=panicCount,proto3" json:"panicCount,omitempty"`
+	// True if the channel data is restored to the last snapshot (see ChannelSettingsType.DataSnapshotIntervalMs in channeld),
+	// in which case the subscribers will receive the whole channel data in the next fan-out.
+	DataRestored bool `protobuf:"varint,2,opt,name=dataRestored,proto3" json:"dataRestored,omitempty"`
+	// The version of the channel data after the recovery. See @ChannelDataUpdateMessage.version.
+	Version uint64 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	// True if the channel has panicked too many times (see ChannelSettingsType.MaxPanicRestarts in channeld) and is being removed.
+	Removing bool `protobuf:"varint,4,opt,name=removing,proto3" json:"removing,omitempty"`
 }
 
-func (x *SpatialRegionsUpdateMessage) Reset() {
-	*x = SpatialRegionsUpdateMessage{}
+func (x *ChannelRecoveredMessage) Reset() {
+	*x = ChannelRecoveredMessage{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_channeld_proto_msgTypes[24]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2079,13 +3261,13 @@ func (x *SpatialRegionsUpdateMessage) Reset() {
 	}
 }
 
-func (x *SpatialRegionsUpdateMessage) String() string {
+func (x *ChannelRecoveredMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SpatialRegionsUpdateMessage) ProtoMessage() {}
+func (*ChannelRecoveredMessage) ProtoMessage() {}
 
-func (x *SpatialRegionsUpdateMessage) ProtoReflect() protoreflect.Message {
+func (x *ChannelRecoveredMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_channeld_proto_msgTypes[24]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2097,31 +3279,57 @@ func (x *SpatialRegionsUpdateMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SpatialRegionsUpdateMessage.ProtoReflect.Descriptor instead.
-func (*SpatialRegionsUpdateMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use ChannelRecoveredMessage.ProtoReflect.Descriptor instead.
+func (*ChannelRecoveredMessage) Descriptor() ([]byte, []int) {
 	return file_channeld_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *SpatialRegionsUpdateMessage) GetRegions() []*SpatialRegion {
+func (x *ChannelRecoveredMessage) GetPanicCount() uint32 {
 	if x != nil {
-		return x.Regions
+		return x.PanicCount
 	}
-	return nil
+	return 0
 }
 
-type SpatialInterestQuery struct {
+func (x *ChannelRecoveredMessage) GetDataRestored() bool {
+	if x != nil {
+		return x.DataRestored
+	}
+	return false
+}
+
+func (x *ChannelRecoveredMessage) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ChannelRecoveredMessage) GetRemoving() bool {
+	if x != nil {
+		return x.Removing
+	}
+	return false
+}
+
+// Sent to the subscribers of the channel when its owner disconnects, and the channel is frozen or transferred to another
+// SERVER connection, according to ChannelSettingsType.OwnerDisconnectPolicy in channeld. Also sent when the frozen channel is reclaimed.
+type ChannelOwnerChangedMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SpotsAOI  *SpatialInterestQuery_SpotsAOI  `protobuf:"bytes,1,opt,name=spotsAOI,proto3,oneof" json:"spotsAOI,omitempty"`
-	BoxAOI    *SpatialInterestQuery_BoxAOI    `protobuf:"bytes,2,opt,name=boxAOI,proto3,oneof" json:"boxAOI,omitempty"`
-	SphereAOI *SpatialInterestQuery_SphereAOI `protobuf:"bytes,3,opt,name=sphereAOI,proto3,oneof" json:"sphereAOI,omitempty"`
-	ConeAOI   *SpatialInterestQuery_ConeAOI   `protobuf:"bytes,4,opt,name=coneAOI,proto3,oneof" json:"coneAOI,omitempty"`
+	// 0 if the channel is frozen.
+	OwnerConnId         uint32 `protobuf:"varint,1,opt,name=ownerConnId,proto3" json:"ownerConnId,omitempty"`
+	PreviousOwnerConnId uint32 `protobuf:"varint,2,opt,name=previousOwnerConnId,proto3" json:"previousOwnerConnId,omitempty"`
+	// If true, the channel doesn't accept @ChannelDataUpdateMessage until the owner reclaims it by subscribing to the channel again,
+	// with the same PIT. The channel is removed if it's not reclaimed in reclaimGraceMs.
+	Frozen         bool   `protobuf:"varint,3,opt,name=frozen,proto3" json:"frozen,omitempty"`
+	ReclaimGraceMs uint32 `protobuf:"varint,4,opt,name=reclaimGraceMs,proto3" json:"reclaimGraceMs,omitempty"`
 }
 
-func (x *SpatialInterestQuery) Reset() {
-	*x = SpatialInterestQuery{}
+func (x *ChannelOwnerChangedMessage) Reset() {
+	*x = ChannelOwnerChangedMessage{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_channeld_proto_msgTypes[25]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2129,13 +3337,13 @@ func (x *SpatialInterestQuery) Reset() {
 	}
 }
 
-func (x *SpatialInterestQuery) String() string {
+func (x *ChannelOwnerChangedMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SpatialInterestQuery) ProtoMessage() {}
+func (*ChannelOwnerChangedMessage) ProtoMessage() {}
 
-func (x *SpatialInterestQuery) ProtoReflect() protoreflect.Message {
+func (x *ChannelOwnerChangedMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_channeld_proto_msgTypes[25]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2147,50 +3355,57 @@ func (x *SpatialInterestQuery) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SpatialInterestQuery.ProtoReflect.Descriptor instead.
-func (*SpatialInterestQuery) Descriptor() ([]byte, []int) {
+// Deprecated: Use ChannelOwnerChangedMessage.ProtoReflect.Descriptor instead.
+func (*ChannelOwnerChangedMessage) Descriptor() ([]byte, []int) {
 	return file_channeld_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *SpatialInterestQuery) GetSpotsAOI() *SpatialInterestQuery_SpotsAOI {
+func (x *ChannelOwnerChangedMessage) GetOwnerConnId() uint32 {
 	if x != nil {
-		return x.SpotsAOI
+		return x.OwnerConnId
 	}
-	return nil
+	return 0
 }
 
-func (x *SpatialInterestQuery) GetBoxAOI() *SpatialInterestQuery_BoxAOI {
+func (x *ChannelOwnerChangedMessage) GetPreviousOwnerConnId() uint32 {
 	if x != nil {
-		return x.BoxAOI
+		return x.PreviousOwnerConnId
 	}
-	return nil
+	return 0
 }
 
-func (x *SpatialInterestQuery) GetSphereAOI() *SpatialInterestQuery_SphereAOI {
+func (x *ChannelOwnerChangedMessage) GetFrozen() bool {
 	if x != nil {
-		return x.SphereAOI
+		return x.Frozen
 	}
-	return nil
+	return false
 }
 
-func (x *SpatialInterestQuery) GetConeAOI() *SpatialInterestQuery_ConeAOI {
+func (x *ChannelOwnerChangedMessage) GetReclaimGraceMs() uint32 {
 	if x != nil {
-		return x.ConeAOI
+		return x.ReclaimGraceMs
 	}
-	return nil
+	return 0
 }
 
-type UpdateSpatialInterestMessage struct {
+// The telemetry event sent by the client. channeld batches the events and writes them to the analytics sink
+// (see GlobalSettingsType.AnalyticsSink in channeld), without involving any channel or the backend servers.
+// The events are dropped if the sink can't keep up. The message should have channelId = 0.
+// Response: no.
+type AnalyticsMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ConnId uint32                `protobuf:"varint,1,opt,name=connId,proto3" json:"connId,omitempty"`
-	Query  *SpatialInterestQuery `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	// The name of the event, e.g. "level_start"
+	Event      string            `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	Properties map[string]string `protobuf:"bytes,2,rep,name=properties,proto3" json:"properties,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The time when the event happened on the client, in unix milliseconds.
+	ClientTimeMs int64 `protobuf:"varint,3,opt,name=clientTimeMs,proto3" json:"clientTimeMs,omitempty"`
 }
 
-func (x *UpdateSpatialInterestMessage) Reset() {
-	*x = UpdateSpatialInterestMessage{}
+func (x *AnalyticsMessage) Reset() {
+	*x = AnalyticsMessage{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_channeld_proto_msgTypes[26]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2198,13 +3413,13 @@ func (x *UpdateSpatialInterestMessage) Reset() {
 	}
 }
 
-func (x *UpdateSpatialInterestMessage) String() string {
+func (x *AnalyticsMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateSpatialInterestMessage) ProtoMessage() {}
+func (*AnalyticsMessage) ProtoMessage() {}
 
-func (x *UpdateSpatialInterestMessage) ProtoReflect() protoreflect.Message {
+func (x *AnalyticsMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_channeld_proto_msgTypes[26]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2216,40 +3431,50 @@ func (x *UpdateSpatialInterestMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateSpatialInterestMessage.ProtoReflect.Descriptor instead.
-func (*UpdateSpatialInterestMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use AnalyticsMessage.ProtoReflect.Descriptor instead.
+func (*AnalyticsMessage) Descriptor() ([]byte, []int) {
 	return file_channeld_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *UpdateSpatialInterestMessage) GetConnId() uint32 {
+func (x *AnalyticsMessage) GetEvent() string {
 	if x != nil {
-		return x.ConnId
+		return x.Event
 	}
-	return 0
+	return ""
 }
 
-func (x *UpdateSpatialInterestMessage) GetQuery() *SpatialInterestQuery {
+func (x *AnalyticsMessage) GetProperties() map[string]string {
 	if x != nil {
-		return x.Query
+		return x.Properties
 	}
 	return nil
 }
 
-type CreateEntityChannelMessage struct {
+func (x *AnalyticsMessage) GetClientTimeMs() int64 {
+	if x != nil {
+		return x.ClientTimeMs
+	}
+	return 0
+}
+
+// The network condition measured by the client, sent periodically (e.g. every few seconds). channeld adapts the fan-out interval
+// and the fan-out budget of the client's subscriptions to it, by the bandwidth policy (see SetBandwidthPolicy() in channeld).
+// The message should have channelId = 0.
+// Response: no.
+type BandwidthReportMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	EntityId     uint32                      `protobuf:"varint,1,opt,name=entityId,proto3" json:"entityId,omitempty"`
-	Metadata     string                      `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
-	SubOptions   *ChannelSubscriptionOptions `protobuf:"bytes,3,opt,name=subOptions,proto3" json:"subOptions,omitempty"`
-	Data         *anypb.Any                  `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
-	MergeOptions *ChannelDataMergeOptions    `protobuf:"bytes,5,opt,name=mergeOptions,proto3" json:"mergeOptions,omitempty"`
-	IsWellKnown  bool                        `protobuf:"varint,6,opt,name=isWellKnown,proto3" json:"isWellKnown,omitempty"`
+	// The measured downlink bandwidth, in kilobits per second. 0 means unknown.
+	DownlinkKbps uint32 `protobuf:"varint,1,opt,name=downlinkKbps,proto3" json:"downlinkKbps,omitempty"`
+	// The ratio of the lost packets, between 0 and 1.
+	PacketLoss float32 `protobuf:"fixed32,2,opt,name=packetLoss,proto3" json:"packetLoss,omitempty"`
+	RttMs      uint32  `protobuf:"varint,3,opt,name=rttMs,proto3" json:"rttMs,omitempty"`
 }
 
-func (x *CreateEntityChannelMessage) Reset() {
-	*x = CreateEntityChannelMessage{}
+func (x *BandwidthReportMessage) Reset() {
+	*x = BandwidthReportMessage{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_channeld_proto_msgTypes[27]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2257,13 +3482,13 @@ func (x *CreateEntityChannelMessage) Reset() {
 	}
 }
 
-func (x *CreateEntityChannelMessage) String() string {
+func (x *BandwidthReportMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateEntityChannelMessage) ProtoMessage() {}
+func (*BandwidthReportMessage) ProtoMessage() {}
 
-func (x *CreateEntityChannelMessage) ProtoReflect() protoreflect.Message {
+func (x *BandwidthReportMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_channeld_proto_msgTypes[27]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2275,65 +3500,52 @@ func (x *CreateEntityChannelMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateEntityChannelMessage.ProtoReflect.Descriptor instead.
-func (*CreateEntityChannelMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use BandwidthReportMessage.ProtoReflect.Descriptor instead.
+func (*BandwidthReportMessage) Descriptor() ([]byte, []int) {
 	return file_channeld_proto_rawDescGZIP(), []int{27}
 }
 
-func (x *CreateEntityChannelMessage) GetEntityId() uint32 {
+func (x *BandwidthReportMessage) GetDownlinkKbps() uint32 {
 	if x != nil {
-		return x.EntityId
+		return x.DownlinkKbps
 	}
 	return 0
 }
 
-func (x *CreateEntityChannelMessage) GetMetadata() string {
-	if x != nil {
-		return x.Metadata
-	}
-	return ""
-}
-
-func (x *CreateEntityChannelMessage) GetSubOptions() *ChannelSubscriptionOptions {
-	if x != nil {
-		return x.SubOptions
-	}
-	return nil
-}
-
-func (x *CreateEntityChannelMessage) GetData() *anypb.Any {
-	if x != nil {
-		return x.Data
-	}
-	return nil
-}
-
-func (x *CreateEntityChannelMessage) GetMergeOptions() *ChannelDataMergeOptions {
+func (x *BandwidthReportMessage) GetPacketLoss() float32 {
 	if x != nil {
-		return x.MergeOptions
+		return x.PacketLoss
 	}
-	return nil
+	return 0
 }
 
-func (x *CreateEntityChannelMessage) GetIsWellKnown() bool {
+func (x *BandwidthReportMessage) GetRttMs() uint32 {
 	if x != nil {
-		return x.IsWellKnown
+		return x.RttMs
 	}
-	return false
+	return 0
 }
 
-// Add specified entities to the handover/lock group of the entity channel. Should sent by the entity channel owner.
-type AddEntityGroupMessage struct {
+// Sent by channeld to the connection that has requested the encryption (see @AuthMessage.encryptionPublicKey), every
+// GlobalSettings.SessionKeyRotationIntervalMs, so a compromised session key only exposes the traffic of one interval.
+// The packets after the one that carries this message are encrypted with the new key, as well as the packets that the
+// connection sends after receiving it. channeld keeps the previous key for the packets in flight. The session key is derived by
+// HKDF-SHA256 from the X25519 shared secret of the public key and the connection's private key, with the info
+// "channeld session key " followed by the key id in 4 bytes (big-endian). The first 32 bytes of the output encrypt the
+// packets sent by channeld, and the next 32 bytes the packets sent to channeld.
+// The message has channelId = 0. Response: no.
+type SessionKeyRotationMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Type          EntityGroupType `protobuf:"varint,1,opt,name=type,proto3,enum=channeldpb.EntityGroupType" json:"type,omitempty"`
-	EntitiesToAdd []uint32        `protobuf:"varint,2,rep,packed,name=EntitiesToAdd,proto3" json:"EntitiesToAdd,omitempty"`
+	KeyId uint32 `protobuf:"varint,1,opt,name=keyId,proto3" json:"keyId,omitempty"`
+	// The X25519 public key of channeld for the key
+	PublicKey []byte `protobuf:"bytes,2,opt,name=publicKey,proto3" json:"publicKey,omitempty"`
 }
 
-func (x *AddEntityGroupMessage) Reset() {
-	*x = AddEntityGroupMessage{}
+func (x *SessionKeyRotationMessage) Reset() {
+	*x = SessionKeyRotationMessage{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_channeld_proto_msgTypes[28]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2341,13 +3553,13 @@ func (x *AddEntityGroupMessage) Reset() {
 	}
 }
 
-func (x *AddEntityGroupMessage) String() string {
+func (x *SessionKeyRotationMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddEntityGroupMessage) ProtoMessage() {}
+func (*SessionKeyRotationMessage) ProtoMessage() {}
 
-func (x *AddEntityGroupMessage) ProtoReflect() protoreflect.Message {
+func (x *SessionKeyRotationMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_channeld_proto_msgTypes[28]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2359,37 +3571,46 @@ func (x *AddEntityGroupMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddEntityGroupMessage.ProtoReflect.Descriptor instead.
-func (*AddEntityGroupMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use SessionKeyRotationMessage.ProtoReflect.Descriptor instead.
+func (*SessionKeyRotationMessage) Descriptor() ([]byte, []int) {
 	return file_channeld_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *AddEntityGroupMessage) GetType() EntityGroupType {
+func (x *SessionKeyRotationMessage) GetKeyId() uint32 {
 	if x != nil {
-		return x.Type
+		return x.KeyId
 	}
-	return EntityGroupType_HANDOVER
+	return 0
 }
 
-func (x *AddEntityGroupMessage) GetEntitiesToAdd() []uint32 {
+func (x *SessionKeyRotationMessage) GetPublicKey() []byte {
 	if x != nil {
-		return x.EntitiesToAdd
+		return x.PublicKey
 	}
 	return nil
 }
 
-// Remove specified entities from the handover/lock group of the entity channel. Should sent by the entity channel owner.
-type RemoveEntityGroupMessage struct {
+// Sent to the connection whose packet or message is dropped as it exceeds the max packet size (see @AuthResultMessage.maxPacketSize),
+// instead of truncating it or closing the connection. The channelId of the MessagePack is the channel of the dropped message.
+type MessageTooLargeMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Type             EntityGroupType `protobuf:"varint,1,opt,name=type,proto3,enum=channeldpb.EntityGroupType" json:"type,omitempty"`
-	EntitiesToRemove []uint32        `protobuf:"varint,2,rep,packed,name=EntitiesToRemove,proto3" json:"EntitiesToRemove,omitempty"`
+	// The size of the dropped packet body or message body, in bytes.
+	Size uint32 `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	// The max packet size that is exceeded.
+	MaxPacketSize uint32 `protobuf:"varint,2,opt,name=maxPacketSize,proto3" json:"maxPacketSize,omitempty"`
+	// The type of the dropped message. 0 if the whole packet sent to channeld is dropped without being decoded.
+	MsgType uint32 `protobuf:"varint,3,opt,name=msgType,proto3" json:"msgType,omitempty"`
+	// The stub of the dropped message, so the sender can fail the RPC without waiting.
+	StubId uint32 `protobuf:"varint,4,opt,name=stubId,proto3" json:"stubId,omitempty"`
+	// The connection that the message failed to be forwarded to, as it can't receive the chunks. 0 if the packet sent to channeld is dropped.
+	RecipientConnId uint32 `protobuf:"varint,5,opt,name=recipientConnId,proto3" json:"recipientConnId,omitempty"`
 }
 
-func (x *RemoveEntityGroupMessage) Reset() {
-	*x = RemoveEntityGroupMessage{}
+func (x *MessageTooLargeMessage) Reset() {
+	*x = MessageTooLargeMessage{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_channeld_proto_msgTypes[29]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2397,13 +3618,13 @@ func (x *RemoveEntityGroupMessage) Reset() {
 	}
 }
 
-func (x *RemoveEntityGroupMessage) String() string {
+func (x *MessageTooLargeMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemoveEntityGroupMessage) ProtoMessage() {}
+func (*MessageTooLargeMessage) ProtoMessage() {}
 
-func (x *RemoveEntityGroupMessage) ProtoReflect() protoreflect.Message {
+func (x *MessageTooLargeMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_channeld_proto_msgTypes[29]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2415,49 +3636,76 @@ func (x *RemoveEntityGroupMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemoveEntityGroupMessage.ProtoReflect.Descriptor instead.
-func (*RemoveEntityGroupMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use MessageTooLargeMessage.ProtoReflect.Descriptor instead.
+func (*MessageTooLargeMessage) Descriptor() ([]byte, []int) {
 	return file_channeld_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *RemoveEntityGroupMessage) GetType() EntityGroupType {
+func (x *MessageTooLargeMessage) GetSize() uint32 {
 	if x != nil {
-		return x.Type
+		return x.Size
 	}
-	return EntityGroupType_HANDOVER
+	return 0
 }
 
-func (x *RemoveEntityGroupMessage) GetEntitiesToRemove() []uint32 {
+func (x *MessageTooLargeMessage) GetMaxPacketSize() uint32 {
 	if x != nil {
-		return x.EntitiesToRemove
+		return x.MaxPacketSize
 	}
-	return nil
+	return 0
 }
 
-// Client requests the spatail regions information. Only valid in Development mode (with "-dev" launch argument).
-// Response: @SpatialRegionsUpdateMessage
-type DebugGetSpatialRegionsMessage struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *MessageTooLargeMessage) GetMsgType() uint32 {
+	if x != nil {
+		return x.MsgType
+	}
+	return 0
 }
 
-func (x *DebugGetSpatialRegionsMessage) Reset() {
-	*x = DebugGetSpatialRegionsMessage{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_channeld_proto_msgTypes[30]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *MessageTooLargeMessage) GetStubId() uint32 {
+	if x != nil {
+		return x.StubId
 	}
+	return 0
 }
 
-func (x *DebugGetSpatialRegionsMessage) String() string {
+func (x *MessageTooLargeMessage) GetRecipientConnId() uint32 {
+	if x != nil {
+		return x.RecipientConnId
+	}
+	return 0
+}
+
+// Sent back to the connection whose control message (msgType < USER_SPACE_START) fails to be handled, instead of dropping it silently.
+// The channelId and stubId of the MessagePack are the same as the failed message's, so the SDK can fail the RPC without waiting.
+type ErrorMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The type of the failed message
+	MsgType uint32            `protobuf:"varint,1,opt,name=msgType,proto3" json:"msgType,omitempty"`
+	Code    ErrorMessage_Code `protobuf:"varint,2,opt,name=code,proto3,enum=channeldpb.ErrorMessage_Code" json:"code,omitempty"`
+	// The human-readable description of the error, for logging or displaying.
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *ErrorMessage) Reset() {
+	*x = ErrorMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ErrorMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DebugGetSpatialRegionsMessage) ProtoMessage() {}
+func (*ErrorMessage) ProtoMessage() {}
 
-func (x *DebugGetSpatialRegionsMessage) ProtoReflect() protoreflect.Message {
+func (x *ErrorMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_channeld_proto_msgTypes[30]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2469,23 +3717,60 @@ func (x *DebugGetSpatialRegionsMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DebugGetSpatialRegionsMessage.ProtoReflect.Descriptor instead.
-func (*DebugGetSpatialRegionsMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use ErrorMessage.ProtoReflect.Descriptor instead.
+func (*ErrorMessage) Descriptor() ([]byte, []int) {
 	return file_channeld_proto_rawDescGZIP(), []int{30}
 }
 
-type ListChannelResultMessage_ChannelInfo struct {
+func (x *ErrorMessage) GetMsgType() uint32 {
+	if x != nil {
+		return x.MsgType
+	}
+	return 0
+}
+
+func (x *ErrorMessage) GetCode() ErrorMessage_Code {
+	if x != nil {
+		return x.Code
+	}
+	return ErrorMessage_UNKNOWN
+}
+
+func (x *ErrorMessage) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// Sent to the channel owner every ChannelSettings.StatsPushIntervalMs in channeld, for the adaptive game logic,
+// e.g. lowering the update rate of a crowded channel. The rates are averaged over the window.
+type ChannelStatsMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ChannelId   uint32      `protobuf:"varint,1,opt,name=channelId,proto3" json:"channelId,omitempty"`
-	ChannelType ChannelType `protobuf:"varint,2,opt,name=channelType,proto3,enum=channeldpb.ChannelType" json:"channelType,omitempty"`
-	Metadata    string      `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
-}
-
-func (x *ListChannelResultMessage_ChannelInfo) Reset() {
-	*x = ListChannelResultMessage_ChannelInfo{}
+	// The duration that the rates are averaged over
+	WindowMs uint32 `protobuf:"varint,1,opt,name=windowMs,proto3" json:"windowMs,omitempty"`
+	// The channel data updates received per second
+	UpdatesPerSec float64 `protobuf:"fixed64,2,opt,name=updatesPerSec,proto3" json:"updatesPerSec,omitempty"`
+	// The channel data updates fanned out to the subscribers per second
+	FanOutsPerSec float64 `protobuf:"fixed64,3,opt,name=fanOutsPerSec,proto3" json:"fanOutsPerSec,omitempty"`
+	// The subscriptions (join) per second
+	SubscribesPerSec float64 `protobuf:"fixed64,4,opt,name=subscribesPerSec,proto3" json:"subscribesPerSec,omitempty"`
+	// The unsubscriptions (leave) per second
+	UnsubscribesPerSec float64 `protobuf:"fixed64,5,opt,name=unsubscribesPerSec,proto3" json:"unsubscribesPerSec,omitempty"`
+	SubscriberCount    uint32  `protobuf:"varint,6,opt,name=subscriberCount,proto3" json:"subscriberCount,omitempty"`
+	// The marshaled size of the channel data
+	DataBytes uint32 `protobuf:"varint,7,opt,name=dataBytes,proto3" json:"dataBytes,omitempty"`
+	// The change of dataBytes in the window
+	DataBytesDelta int32 `protobuf:"varint,8,opt,name=dataBytesDelta,proto3" json:"dataBytesDelta,omitempty"`
+	// The CPU time spent in the channel's ticks, in percent of the window. See ChannelSettingsType.DedicatedThread.
+	CpuPercent float64 `protobuf:"fixed64,9,opt,name=cpuPercent,proto3" json:"cpuPercent,omitempty"`
+}
+
+func (x *ChannelStatsMessage) Reset() {
+	*x = ChannelStatsMessage{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_channeld_proto_msgTypes[31]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2493,13 +3778,13 @@ func (x *ListChannelResultMessage_ChannelInfo) Reset() {
 	}
 }
 
-func (x *ListChannelResultMessage_ChannelInfo) String() string {
+func (x *ChannelStatsMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListChannelResultMessage_ChannelInfo) ProtoMessage() {}
+func (*ChannelStatsMessage) ProtoMessage() {}
 
-func (x *ListChannelResultMessage_ChannelInfo) ProtoReflect() protoreflect.Message {
+func (x *ChannelStatsMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_channeld_proto_msgTypes[31]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2511,44 +3796,89 @@ func (x *ListChannelResultMessage_ChannelInfo) ProtoReflect() protoreflect.Messa
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListChannelResultMessage_ChannelInfo.ProtoReflect.Descriptor instead.
-func (*ListChannelResultMessage_ChannelInfo) Descriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{11, 0}
+// Deprecated: Use ChannelStatsMessage.ProtoReflect.Descriptor instead.
+func (*ChannelStatsMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *ListChannelResultMessage_ChannelInfo) GetChannelId() uint32 {
+func (x *ChannelStatsMessage) GetWindowMs() uint32 {
 	if x != nil {
-		return x.ChannelId
+		return x.WindowMs
 	}
 	return 0
 }
 
-func (x *ListChannelResultMessage_ChannelInfo) GetChannelType() ChannelType {
+func (x *ChannelStatsMessage) GetUpdatesPerSec() float64 {
 	if x != nil {
-		return x.ChannelType
+		return x.UpdatesPerSec
 	}
-	return ChannelType_UNKNOWN
+	return 0
 }
 
-func (x *ListChannelResultMessage_ChannelInfo) GetMetadata() string {
+func (x *ChannelStatsMessage) GetFanOutsPerSec() float64 {
 	if x != nil {
-		return x.Metadata
+		return x.FanOutsPerSec
 	}
-	return ""
+	return 0
 }
 
-type SpatialInterestQuery_SpotsAOI struct {
+func (x *ChannelStatsMessage) GetSubscribesPerSec() float64 {
+	if x != nil {
+		return x.SubscribesPerSec
+	}
+	return 0
+}
+
+func (x *ChannelStatsMessage) GetUnsubscribesPerSec() float64 {
+	if x != nil {
+		return x.UnsubscribesPerSec
+	}
+	return 0
+}
+
+func (x *ChannelStatsMessage) GetSubscriberCount() uint32 {
+	if x != nil {
+		return x.SubscriberCount
+	}
+	return 0
+}
+
+func (x *ChannelStatsMessage) GetDataBytes() uint32 {
+	if x != nil {
+		return x.DataBytes
+	}
+	return 0
+}
+
+func (x *ChannelStatsMessage) GetDataBytesDelta() int32 {
+	if x != nil {
+		return x.DataBytesDelta
+	}
+	return 0
+}
+
+func (x *ChannelStatsMessage) GetCpuPercent() float64 {
+	if x != nil {
+		return x.CpuPercent
+	}
+	return 0
+}
+
+// Sent to the connection that has logged in, when another connection logs in with the same account (see @AuthResultMessage.userId).
+// See DuplicateLoginPolicy in channeld. If the new connection is rejected, it receives @AuthResultMessage with DUPLICATE_LOGIN instead.
+type DuplicateLoginMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Spots []*SpatialInfo `protobuf:"bytes,1,rep,name=spots,proto3" json:"spots,omitempty"`
-	// The fixed distance between each spot and the player. If not specified, 0 = nearest will be used.
-	Dists []uint32 `protobuf:"varint,2,rep,packed,name=dists,proto3" json:"dists,omitempty"`
+	// The connection that logged in later
+	NewConnId uint32 `protobuf:"varint,1,opt,name=newConnId,proto3" json:"newConnId,omitempty"`
+	// If true, the connection is going to be closed by channeld.
+	Kicked bool `protobuf:"varint,2,opt,name=kicked,proto3" json:"kicked,omitempty"`
 }
 
-func (x *SpatialInterestQuery_SpotsAOI) Reset() {
-	*x = SpatialInterestQuery_SpotsAOI{}
+func (x *DuplicateLoginMessage) Reset() {
+	*x = DuplicateLoginMessage{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_channeld_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2556,13 +3886,13 @@ func (x *SpatialInterestQuery_SpotsAOI) Reset() {
 	}
 }
 
-func (x *SpatialInterestQuery_SpotsAOI) String() string {
+func (x *DuplicateLoginMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SpatialInterestQuery_SpotsAOI) ProtoMessage() {}
+func (*DuplicateLoginMessage) ProtoMessage() {}
 
-func (x *SpatialInterestQuery_SpotsAOI) ProtoReflect() protoreflect.Message {
+func (x *DuplicateLoginMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_channeld_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2574,36 +3904,44 @@ func (x *SpatialInterestQuery_SpotsAOI) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SpatialInterestQuery_SpotsAOI.ProtoReflect.Descriptor instead.
-func (*SpatialInterestQuery_SpotsAOI) Descriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{25, 0}
+// Deprecated: Use DuplicateLoginMessage.ProtoReflect.Descriptor instead.
+func (*DuplicateLoginMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *SpatialInterestQuery_SpotsAOI) GetSpots() []*SpatialInfo {
+func (x *DuplicateLoginMessage) GetNewConnId() uint32 {
 	if x != nil {
-		return x.Spots
+		return x.NewConnId
 	}
-	return nil
+	return 0
 }
 
-func (x *SpatialInterestQuery_SpotsAOI) GetDists() []uint32 {
+func (x *DuplicateLoginMessage) GetKicked() bool {
 	if x != nil {
-		return x.Dists
+		return x.Kicked
 	}
-	return nil
+	return false
 }
 
-type SpatialInterestQuery_BoxAOI struct {
+// Sent to the channel owner when the channel's tick has lagged behind its tick interval for a while, so the owner can shed
+// the load, e.g. lowering the update rate. Sent again with recovered = true when the tick catches up.
+// See ChannelSettingsType.TickLagThresholdMs in channeld.
+type ChannelTickLagMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Center *SpatialInfo `protobuf:"bytes,1,opt,name=center,proto3" json:"center,omitempty"`
-	Extent *SpatialInfo `protobuf:"bytes,2,opt,name=extent,proto3" json:"extent,omitempty"`
+	// The configured tick interval of the channel
+	TickIntervalMs uint32 `protobuf:"varint,1,opt,name=tickIntervalMs,proto3" json:"tickIntervalMs,omitempty"`
+	// The actual interval of the latest tick
+	ActualIntervalMs uint32 `protobuf:"varint,2,opt,name=actualIntervalMs,proto3" json:"actualIntervalMs,omitempty"`
+	// How many ticks in a row have lagged
+	Ticks     uint32 `protobuf:"varint,3,opt,name=ticks,proto3" json:"ticks,omitempty"`
+	Recovered bool   `protobuf:"varint,4,opt,name=recovered,proto3" json:"recovered,omitempty"`
 }
 
-func (x *SpatialInterestQuery_BoxAOI) Reset() {
-	*x = SpatialInterestQuery_BoxAOI{}
+func (x *ChannelTickLagMessage) Reset() {
+	*x = ChannelTickLagMessage{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_channeld_proto_msgTypes[33]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2611,13 +3949,13 @@ func (x *SpatialInterestQuery_BoxAOI) Reset() {
 	}
 }
 
-func (x *SpatialInterestQuery_BoxAOI) String() string {
+func (x *ChannelTickLagMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SpatialInterestQuery_BoxAOI) ProtoMessage() {}
+func (*ChannelTickLagMessage) ProtoMessage() {}
 
-func (x *SpatialInterestQuery_BoxAOI) ProtoReflect() protoreflect.Message {
+func (x *ChannelTickLagMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_channeld_proto_msgTypes[33]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2629,36 +3967,57 @@ func (x *SpatialInterestQuery_BoxAOI) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SpatialInterestQuery_BoxAOI.ProtoReflect.Descriptor instead.
-func (*SpatialInterestQuery_BoxAOI) Descriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{25, 1}
+// Deprecated: Use ChannelTickLagMessage.ProtoReflect.Descriptor instead.
+func (*ChannelTickLagMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *SpatialInterestQuery_BoxAOI) GetCenter() *SpatialInfo {
+func (x *ChannelTickLagMessage) GetTickIntervalMs() uint32 {
 	if x != nil {
-		return x.Center
+		return x.TickIntervalMs
 	}
-	return nil
+	return 0
 }
 
-func (x *SpatialInterestQuery_BoxAOI) GetExtent() *SpatialInfo {
+func (x *ChannelTickLagMessage) GetActualIntervalMs() uint32 {
 	if x != nil {
-		return x.Extent
+		return x.ActualIntervalMs
 	}
-	return nil
+	return 0
 }
 
-type SpatialInterestQuery_SphereAOI struct {
+func (x *ChannelTickLagMessage) GetTicks() uint32 {
+	if x != nil {
+		return x.Ticks
+	}
+	return 0
+}
+
+func (x *ChannelTickLagMessage) GetRecovered() bool {
+	if x != nil {
+		return x.Recovered
+	}
+	return false
+}
+
+// Carries many messages of the same type to different channels in one message, e.g. the updates of hundreds of entity channels
+// in a server tick. channeld unpacks the entries and routes each of them as if it's sent in its own MessagePack.
+// Only valid for the server connections. The channelId of the batch itself is ignored.
+type ServerBatchMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Center *SpatialInfo `protobuf:"bytes,1,opt,name=center,proto3" json:"center,omitempty"`
-	Radius float64      `protobuf:"fixed64,2,opt,name=radius,proto3" json:"radius,omitempty"`
+	// The type of all the messages in the batch. SERVER_BATCH can't be nested.
+	MsgType uint32 `protobuf:"varint,1,opt,name=msgType,proto3" json:"msgType,omitempty"`
+	// Only for CHANNEL_DATA_UPDATE: the type URL of the channel data, e.g. "type.googleapis.com/tpspb.TestChannelData", shared by
+	// all the entries. Saves wrapping each update in the ChannelDataUpdateMessage and the Any.
+	DataTypeUrl string                      `protobuf:"bytes,2,opt,name=dataTypeUrl,proto3" json:"dataTypeUrl,omitempty"`
+	Entries     []*ServerBatchMessage_Entry `protobuf:"bytes,3,rep,name=entries,proto3" json:"entries,omitempty"`
 }
 
-func (x *SpatialInterestQuery_SphereAOI) Reset() {
-	*x = SpatialInterestQuery_SphereAOI{}
+func (x *ServerBatchMessage) Reset() {
+	*x = ServerBatchMessage{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_channeld_proto_msgTypes[34]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2666,13 +4025,13 @@ func (x *SpatialInterestQuery_SphereAOI) Reset() {
 	}
 }
 
-func (x *SpatialInterestQuery_SphereAOI) String() string {
+func (x *ServerBatchMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SpatialInterestQuery_SphereAOI) ProtoMessage() {}
+func (*ServerBatchMessage) ProtoMessage() {}
 
-func (x *SpatialInterestQuery_SphereAOI) ProtoReflect() protoreflect.Message {
+func (x *ServerBatchMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_channeld_proto_msgTypes[34]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2684,39 +4043,49 @@ func (x *SpatialInterestQuery_SphereAOI) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SpatialInterestQuery_SphereAOI.ProtoReflect.Descriptor instead.
-func (*SpatialInterestQuery_SphereAOI) Descriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{25, 2}
+// Deprecated: Use ServerBatchMessage.ProtoReflect.Descriptor instead.
+func (*ServerBatchMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{34}
 }
 
-func (x *SpatialInterestQuery_SphereAOI) GetCenter() *SpatialInfo {
+func (x *ServerBatchMessage) GetMsgType() uint32 {
 	if x != nil {
-		return x.Center
+		return x.MsgType
 	}
-	return nil
+	return 0
 }
 
-func (x *SpatialInterestQuery_SphereAOI) GetRadius() float64 {
+func (x *ServerBatchMessage) GetDataTypeUrl() string {
 	if x != nil {
-		return x.Radius
+		return x.DataTypeUrl
 	}
-	return 0
+	return ""
 }
 
-type SpatialInterestQuery_ConeAOI struct {
+func (x *ServerBatchMessage) GetEntries() []*ServerBatchMessage_Entry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+// Sent to the channel owner when a subscriber's send queue has stayed full for a while (see ChannelSettingsType.SlowSubscriberTicks in channeld),
+// and its fan-out has been downgraded.
+type SlowSubscriberMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Center    *SpatialInfo `protobuf:"bytes,1,opt,name=center,proto3" json:"center,omitempty"`
-	Direction *SpatialInfo `protobuf:"bytes,2,opt,name=direction,proto3" json:"direction,omitempty"`
-	// In radians.
-	Angle  float64 `protobuf:"fixed64,3,opt,name=angle,proto3" json:"angle,omitempty"`
-	Radius float64 `protobuf:"fixed64,4,opt,name=radius,proto3" json:"radius,omitempty"`
+	// The slow subscriber
+	ConnId uint32 `protobuf:"varint,1,opt,name=connId,proto3" json:"connId,omitempty"`
+	// The fan-out interval of the subscription after the downgrade
+	FanOutIntervalMs uint32 `protobuf:"varint,2,opt,name=fanOutIntervalMs,proto3" json:"fanOutIntervalMs,omitempty"`
+	// If true, the subscriber only receives the whole channel data in the fan-out, and the fan-out is skipped while its send queue is full.
+	KeyframeOnly bool `protobuf:"varint,3,opt,name=keyframeOnly,proto3" json:"keyframeOnly,omitempty"`
 }
 
-func (x *SpatialInterestQuery_ConeAOI) Reset() {
-	*x = SpatialInterestQuery_ConeAOI{}
+func (x *SlowSubscriberMessage) Reset() {
+	*x = SlowSubscriberMessage{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_channeld_proto_msgTypes[35]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2724,576 +4093,4943 @@ func (x *SpatialInterestQuery_ConeAOI) Reset() {
 	}
 }
 
-func (x *SpatialInterestQuery_ConeAOI) String() string {
+func (x *SlowSubscriberMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SpatialInterestQuery_ConeAOI) ProtoMessage() {}
+func (*SlowSubscriberMessage) ProtoMessage() {}
 
-func (x *SpatialInterestQuery_ConeAOI) ProtoReflect() protoreflect.Message {
+func (x *SlowSubscriberMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_channeld_proto_msgTypes[35]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
 		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use SpatialInterestQuery_ConeAOI.ProtoReflect.Descriptor instead.
-func (*SpatialInterestQuery_ConeAOI) Descriptor() ([]byte, []int) {
-	return file_channeld_proto_rawDescGZIP(), []int{25, 3}
-}
-
-func (x *SpatialInterestQuery_ConeAOI) GetCenter() *SpatialInfo {
-	if x != nil {
-		return x.Center
-	}
-	return nil
-}
-
-func (x *SpatialInterestQuery_ConeAOI) GetDirection() *SpatialInfo {
-	if x != nil {
-		return x.Direction
-	}
-	return nil
-}
-
-func (x *SpatialInterestQuery_ConeAOI) GetAngle() float64 {
-	if x != nil {
-		return x.Angle
-	}
-	return 0
-}
-
-func (x *SpatialInterestQuery_ConeAOI) GetRadius() float64 {
-	if x != nil {
-		return x.Radius
-	}
-	return 0
-}
-
-var File_channeld_proto protoreflect.FileDescriptor
-
-var file_channeld_proto_rawDesc = []byte{
-	0x0a, 0x0e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x12, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x1a, 0x19, 0x67, 0x6f,
-	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x61, 0x6e,
-	0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x3d, 0x0a, 0x06, 0x50, 0x61, 0x63, 0x6b, 0x65,
-	0x74, 0x12, 0x33, 0x0a, 0x08, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62,
-	0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x50, 0x61, 0x63, 0x6b, 0x52, 0x08, 0x6d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x22, 0x95, 0x01, 0x0a, 0x0b, 0x4d, 0x65, 0x73, 0x73, 0x61,
-	0x67, 0x65, 0x50, 0x61, 0x63, 0x6b, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
-	0x6c, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e,
-	0x65, 0x6c, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x62, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73,
-	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x62, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61,
-	0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x75, 0x62, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x06, 0x73, 0x74, 0x75, 0x62, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x73,
-	0x67, 0x54, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x6d, 0x73, 0x67,
-	0x54, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x73, 0x67, 0x42, 0x6f, 0x64, 0x79, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x6d, 0x73, 0x67, 0x42, 0x6f, 0x64, 0x79, 0x22, 0x54,
-	0x0a, 0x14, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x4d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
-	0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x63, 0x6c,
-	0x69, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61,
-	0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x70, 0x61, 0x79,
-	0x6c, 0x6f, 0x61, 0x64, 0x22, 0x63, 0x0a, 0x0b, 0x41, 0x75, 0x74, 0x68, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x12, 0x34, 0x0a, 0x15, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x64, 0x65,
-	0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x15, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69,
-	0x66, 0x69, 0x65, 0x72, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x6c, 0x6f, 0x67,
-	0x69, 0x6e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6c,
-	0x6f, 0x67, 0x69, 0x6e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0xf3, 0x01, 0x0a, 0x11, 0x41, 0x75,
-	0x74, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
-	0x40, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
-	0x28, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x41, 0x75, 0x74,
-	0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x41,
-	0x75, 0x74, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c,
-	0x74, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0d, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x45, 0x0a, 0x0f, 0x63, 0x6f, 0x6d,
-	0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e,
-	0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52,
-	0x0f, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65,
-	0x22, 0x3d, 0x0a, 0x0a, 0x41, 0x75, 0x74, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x0e,
-	0x0a, 0x0a, 0x53, 0x55, 0x43, 0x43, 0x45, 0x53, 0x53, 0x46, 0x55, 0x4c, 0x10, 0x00, 0x12, 0x0f,
-	0x0a, 0x0b, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x50, 0x49, 0x54, 0x10, 0x01, 0x12,
-	0x0e, 0x0a, 0x0a, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x4c, 0x54, 0x10, 0x02, 0x22,
-	0xaf, 0x03, 0x0a, 0x1a, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x53, 0x75, 0x62, 0x73, 0x63,
-	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x42,
-	0x0a, 0x0a, 0x64, 0x61, 0x74, 0x61, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e,
-	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x41, 0x63, 0x63, 0x65, 0x73,
-	0x73, 0x48, 0x00, 0x52, 0x0a, 0x64, 0x61, 0x74, 0x61, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x88,
-	0x01, 0x01, 0x12, 0x26, 0x0a, 0x0e, 0x64, 0x61, 0x74, 0x61, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4d,
-	0x61, 0x73, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x64, 0x61, 0x74, 0x61,
-	0x46, 0x69, 0x65, 0x6c, 0x64, 0x4d, 0x61, 0x73, 0x6b, 0x73, 0x12, 0x2f, 0x0a, 0x10, 0x66, 0x61,
-	0x6e, 0x4f, 0x75, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0d, 0x48, 0x01, 0x52, 0x10, 0x66, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x49, 0x6e,
-	0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73, 0x88, 0x01, 0x01, 0x12, 0x29, 0x0a, 0x0d, 0x66,
-	0x61, 0x6e, 0x4f, 0x75, 0x74, 0x44, 0x65, 0x6c, 0x61, 0x79, 0x4d, 0x73, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x05, 0x48, 0x02, 0x52, 0x0d, 0x66, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x44, 0x65, 0x6c, 0x61,
-	0x79, 0x4d, 0x73, 0x88, 0x01, 0x01, 0x12, 0x37, 0x0a, 0x14, 0x73, 0x6b, 0x69, 0x70, 0x53, 0x65,
-	0x6c, 0x66, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x18, 0x05,
-	0x20, 0x01, 0x28, 0x08, 0x48, 0x03, 0x52, 0x14, 0x73, 0x6b, 0x69, 0x70, 0x53, 0x65, 0x6c, 0x66,
-	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x88, 0x01, 0x01, 0x12,
-	0x2d, 0x0a, 0x0f, 0x73, 0x6b, 0x69, 0x70, 0x46, 0x69, 0x72, 0x73, 0x74, 0x46, 0x61, 0x6e, 0x4f,
-	0x75, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x48, 0x04, 0x52, 0x0f, 0x73, 0x6b, 0x69, 0x70,
-	0x46, 0x69, 0x72, 0x73, 0x74, 0x46, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x88, 0x01, 0x01, 0x42, 0x0d,
-	0x0a, 0x0b, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x42, 0x13, 0x0a,
-	0x11, 0x5f, 0x66, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c,
-	0x4d, 0x73, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x66, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x44, 0x65, 0x6c,
-	0x61, 0x79, 0x4d, 0x73, 0x42, 0x17, 0x0a, 0x15, 0x5f, 0x73, 0x6b, 0x69, 0x70, 0x53, 0x65, 0x6c,
-	0x66, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x42, 0x12, 0x0a,
-	0x10, 0x5f, 0x73, 0x6b, 0x69, 0x70, 0x46, 0x69, 0x72, 0x73, 0x74, 0x46, 0x61, 0x6e, 0x4f, 0x75,
-	0x74, 0x22, 0xd3, 0x01, 0x0a, 0x17, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74,
-	0x61, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x2c, 0x0a,
-	0x11, 0x73, 0x68, 0x6f, 0x75, 0x6c, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x4c, 0x69,
-	0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x73, 0x68, 0x6f, 0x75, 0x6c, 0x64,
-	0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x6c,
-	0x69, 0x73, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x0d, 0x6c, 0x69, 0x73, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x4c, 0x69, 0x6d, 0x69,
-	0x74, 0x12, 0x20, 0x0a, 0x0b, 0x74, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x70,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x74, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65,
-	0x54, 0x6f, 0x70, 0x12, 0x42, 0x0a, 0x1c, 0x73, 0x68, 0x6f, 0x75, 0x6c, 0x64, 0x43, 0x68, 0x65,
-	0x63, 0x6b, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x62, 0x6c, 0x65, 0x4d, 0x61, 0x70, 0x46, 0x69,
-	0x65, 0x6c, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x1c, 0x73, 0x68, 0x6f, 0x75, 0x6c,
-	0x64, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x62, 0x6c, 0x65, 0x4d,
-	0x61, 0x70, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x22, 0xa8, 0x02, 0x0a, 0x14, 0x43, 0x72, 0x65, 0x61,
-	0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x12, 0x39, 0x0a, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64,
-	0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0b,
-	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6d,
-	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d,
-	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x46, 0x0a, 0x0a, 0x73, 0x75, 0x62, 0x4f, 0x70,
-	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x63, 0x68,
-	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
-	0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x70, 0x74, 0x69,
-	0x6f, 0x6e, 0x73, 0x52, 0x0a, 0x73, 0x75, 0x62, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12,
-	0x28, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e,
-	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
-	0x41, 0x6e, 0x79, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x47, 0x0a, 0x0c, 0x6d, 0x65, 0x72,
-	0x67, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x23, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61,
-	0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x4f, 0x70, 0x74,
-	0x69, 0x6f, 0x6e, 0x73, 0x52, 0x0c, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f,
-	0x6e, 0x73, 0x22, 0xb3, 0x01, 0x0a, 0x1a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61,
-	0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x12, 0x39, 0x0a, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
-	0x64, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x52,
-	0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08,
-	0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
-	0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x20, 0x0a, 0x0b, 0x6f, 0x77, 0x6e, 0x65,
-	0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x6f,
-	0x77, 0x6e, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68,
-	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x63,
-	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x22, 0x34, 0x0a, 0x14, 0x52, 0x65, 0x6d, 0x6f,
-	0x76, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0d, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x22, 0x77,
-	0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x12, 0x37, 0x0a, 0x0a, 0x74, 0x79, 0x70, 0x65, 0x46, 0x69, 0x6c, 0x74,
-	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e,
-	0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70,
-	0x65, 0x52, 0x0a, 0x74, 0x79, 0x70, 0x65, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x28, 0x0a,
-	0x0f, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73,
-	0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
-	0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x22, 0xed, 0x01, 0x0a, 0x18, 0x4c, 0x69, 0x73, 0x74,
-	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x12, 0x4c, 0x0a, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73,
-	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
-	0x64, 0x70, 0x62, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52,
-	0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x43, 0x68, 0x61,
-	0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
-	0x6c, 0x73, 0x1a, 0x82, 0x01, 0x0a, 0x0b, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x6e,
-	0x66, 0x6f, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64,
-	0x12, 0x39, 0x0a, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64,
-	0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0b,
-	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6d,
-	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d,
-	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x22, 0x7c, 0x0a, 0x1a, 0x53, 0x75, 0x62, 0x73, 0x63,
-	0x72, 0x69, 0x62, 0x65, 0x64, 0x54, 0x6f, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x46, 0x0a,
-	0x0a, 0x73, 0x75, 0x62, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x26, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43,
-	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
-	0x6f, 0x6e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x0a, 0x73, 0x75, 0x62, 0x4f, 0x70,
-	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xf5, 0x01, 0x0a, 0x20, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72,
-	0x69, 0x62, 0x65, 0x64, 0x54, 0x6f, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73,
-	0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f,
-	0x6e, 0x6e, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x6e,
-	0x49, 0x64, 0x12, 0x46, 0x0a, 0x0a, 0x73, 0x75, 0x62, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
-	0x64, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x53, 0x75, 0x62, 0x73, 0x63,
-	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x0a,
-	0x73, 0x75, 0x62, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x36, 0x0a, 0x08, 0x63, 0x6f,
-	0x6e, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x63,
-	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x08, 0x63, 0x6f, 0x6e, 0x6e, 0x54, 0x79,
-	0x70, 0x65, 0x12, 0x39, 0x0a, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70,
-	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
-	0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65,
-	0x52, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x22, 0x38, 0x0a,
-	0x1e, 0x55, 0x6e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x64, 0x46, 0x72, 0x6f,
-	0x6d, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
-	0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
-	0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x22, 0xb1, 0x01, 0x0a, 0x24, 0x55, 0x6e, 0x73, 0x75,
-	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x43, 0x68, 0x61, 0x6e,
-	0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
-	0x52, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x36, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x6e,
-	0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x63, 0x68, 0x61,
-	0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x08, 0x63, 0x6f, 0x6e, 0x6e, 0x54, 0x79, 0x70, 0x65,
-	0x12, 0x39, 0x0a, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64,
-	0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0b,
-	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x22, 0x6a, 0x0a, 0x18, 0x43,
-	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
-	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x28, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x04, 0x64, 0x61, 0x74,
-	0x61, 0x12, 0x24, 0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x43, 0x6f, 0x6e, 0x6e,
-	0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78,
-	0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x22, 0x2b, 0x0a, 0x11, 0x44, 0x69, 0x73, 0x63, 0x6f,
-	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06,
-	0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x63, 0x6f,
-	0x6e, 0x6e, 0x49, 0x64, 0x22, 0x37, 0x0a, 0x0b, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49,
-	0x6e, 0x66, 0x6f, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x01,
-	0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x01, 0x79, 0x12,
-	0x0c, 0x0a, 0x01, 0x7a, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x01, 0x7a, 0x22, 0x8e, 0x01,
-	0x0a, 0x22, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x43,
-	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x12, 0x2a, 0x0a, 0x10, 0x73, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x43,
-	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x10,
-	0x73, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64,
-	0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x20, 0x0a, 0x0b,
-	0x6f, 0x77, 0x6e, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x0d, 0x52, 0x0b, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x22, 0x57,
-	0x0a, 0x1a, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x43, 0x68,
-	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x39, 0x0a, 0x0b,
-	0x73, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53,
-	0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0b, 0x73, 0x70, 0x61, 0x74,
-	0x69, 0x61, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0x40, 0x0a, 0x20, 0x51, 0x75, 0x65, 0x72, 0x79,
-	0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65,
-	0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x63,
-	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x09,
-	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x22, 0xb4, 0x01, 0x0a, 0x1a, 0x43, 0x68,
-	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x48, 0x61, 0x6e, 0x64, 0x6f, 0x76, 0x65,
-	0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x72, 0x63, 0x43,
-	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c,
-	0x73, 0x72, 0x63, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c,
-	0x64, 0x73, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x0c, 0x64, 0x73, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64,
-	0x12, 0x24, 0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x49,
-	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74,
-	0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x28, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61,
-	0x22, 0xa5, 0x01, 0x0a, 0x0d, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x52, 0x65, 0x67, 0x69,
-	0x6f, 0x6e, 0x12, 0x29, 0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53, 0x70, 0x61,
-	0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x03, 0x6d, 0x69, 0x6e, 0x12, 0x29, 0x0a,
-	0x03, 0x6d, 0x61, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61,
-	0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49,
-	0x6e, 0x66, 0x6f, 0x52, 0x03, 0x6d, 0x61, 0x78, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x6e,
-	0x6e, 0x65, 0x6c, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x63, 0x68, 0x61,
-	0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
-	0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x73, 0x65, 0x72,
-	0x76, 0x65, 0x72, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x22, 0x52, 0x0a, 0x1b, 0x53, 0x70, 0x61, 0x74,
-	0x69, 0x61, 0x6c, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x73, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
-	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x33, 0x0a, 0x07, 0x72, 0x65, 0x67, 0x69, 0x6f,
-	0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e,
-	0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x52, 0x65, 0x67,
-	0x69, 0x6f, 0x6e, 0x52, 0x07, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xa7, 0x06, 0x0a,
-	0x14, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x65, 0x73, 0x74,
-	0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x4a, 0x0a, 0x08, 0x73, 0x70, 0x6f, 0x74, 0x73, 0x41, 0x4f,
-	0x49, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
-	0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e, 0x74, 0x65,
-	0x72, 0x65, 0x73, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x2e, 0x53, 0x70, 0x6f, 0x74, 0x73, 0x41,
-	0x4f, 0x49, 0x48, 0x00, 0x52, 0x08, 0x73, 0x70, 0x6f, 0x74, 0x73, 0x41, 0x4f, 0x49, 0x88, 0x01,
-	0x01, 0x12, 0x44, 0x0a, 0x06, 0x62, 0x6f, 0x78, 0x41, 0x4f, 0x49, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x27, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53,
-	0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x65, 0x73, 0x74, 0x51, 0x75,
-	0x65, 0x72, 0x79, 0x2e, 0x42, 0x6f, 0x78, 0x41, 0x4f, 0x49, 0x48, 0x01, 0x52, 0x06, 0x62, 0x6f,
-	0x78, 0x41, 0x4f, 0x49, 0x88, 0x01, 0x01, 0x12, 0x4d, 0x0a, 0x09, 0x73, 0x70, 0x68, 0x65, 0x72,
-	0x65, 0x41, 0x4f, 0x49, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x63, 0x68, 0x61,
-	0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49,
-	0x6e, 0x74, 0x65, 0x72, 0x65, 0x73, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x2e, 0x53, 0x70, 0x68,
-	0x65, 0x72, 0x65, 0x41, 0x4f, 0x49, 0x48, 0x02, 0x52, 0x09, 0x73, 0x70, 0x68, 0x65, 0x72, 0x65,
-	0x41, 0x4f, 0x49, 0x88, 0x01, 0x01, 0x12, 0x47, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x65, 0x41, 0x4f,
-	0x49, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
-	0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e, 0x74, 0x65,
-	0x72, 0x65, 0x73, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x2e, 0x43, 0x6f, 0x6e, 0x65, 0x41, 0x4f,
-	0x49, 0x48, 0x03, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x65, 0x41, 0x4f, 0x49, 0x88, 0x01, 0x01, 0x1a,
-	0x4f, 0x0a, 0x08, 0x53, 0x70, 0x6f, 0x74, 0x73, 0x41, 0x4f, 0x49, 0x12, 0x2d, 0x0a, 0x05, 0x73,
-	0x70, 0x6f, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61,
-	0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49,
-	0x6e, 0x66, 0x6f, 0x52, 0x05, 0x73, 0x70, 0x6f, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x64, 0x69,
-	0x73, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x05, 0x64, 0x69, 0x73, 0x74, 0x73,
-	0x1a, 0x6a, 0x0a, 0x06, 0x42, 0x6f, 0x78, 0x41, 0x4f, 0x49, 0x12, 0x2f, 0x0a, 0x06, 0x63, 0x65,
-	0x6e, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61,
-	0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49,
-	0x6e, 0x66, 0x6f, 0x52, 0x06, 0x63, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x12, 0x2f, 0x0a, 0x06, 0x65,
-	0x78, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x68,
-	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c,
-	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x06, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x74, 0x1a, 0x54, 0x0a, 0x09,
-	0x53, 0x70, 0x68, 0x65, 0x72, 0x65, 0x41, 0x4f, 0x49, 0x12, 0x2f, 0x0a, 0x06, 0x63, 0x65, 0x6e,
-	0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e,
-	0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e,
-	0x66, 0x6f, 0x52, 0x06, 0x63, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x61,
-	0x64, 0x69, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x72, 0x61, 0x64, 0x69,
-	0x75, 0x73, 0x1a, 0x9f, 0x01, 0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x65, 0x41, 0x4f, 0x49, 0x12, 0x2f,
-	0x0a, 0x06, 0x63, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
-	0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53, 0x70, 0x61, 0x74,
-	0x69, 0x61, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x06, 0x63, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x12,
-	0x35, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e,
-	0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x09, 0x64, 0x69, 0x72,
-	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6e, 0x67, 0x6c, 0x65, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x61, 0x6e, 0x67, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06,
-	0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x72, 0x61,
-	0x64, 0x69, 0x75, 0x73, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x73, 0x70, 0x6f, 0x74, 0x73, 0x41, 0x4f,
-	0x49, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x62, 0x6f, 0x78, 0x41, 0x4f, 0x49, 0x42, 0x0c, 0x0a, 0x0a,
-	0x5f, 0x73, 0x70, 0x68, 0x65, 0x72, 0x65, 0x41, 0x4f, 0x49, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x63,
-	0x6f, 0x6e, 0x65, 0x41, 0x4f, 0x49, 0x22, 0x6e, 0x0a, 0x1c, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
-	0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x65, 0x73, 0x74, 0x4d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x36,
-	0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e,
-	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53, 0x70, 0x61, 0x74, 0x69,
-	0x61, 0x6c, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x65, 0x73, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52,
-	0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x22, 0xb1, 0x02, 0x0a, 0x1a, 0x43, 0x72, 0x65, 0x61, 0x74,
-	0x65, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x49,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x49,
-	0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x46, 0x0a,
-	0x0a, 0x73, 0x75, 0x62, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x26, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43,
-	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
-	0x6f, 0x6e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x0a, 0x73, 0x75, 0x62, 0x4f, 0x70,
-	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x28, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12,
-	0x47, 0x0a, 0x0c, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64,
-	0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x4d, 0x65,
-	0x72, 0x67, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x0c, 0x6d, 0x65, 0x72, 0x67,
-	0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x69, 0x73, 0x57, 0x65,
-	0x6c, 0x6c, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x69,
-	0x73, 0x57, 0x65, 0x6c, 0x6c, 0x4b, 0x6e, 0x6f, 0x77, 0x6e, 0x22, 0x6e, 0x0a, 0x15, 0x41, 0x64,
-	0x64, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x12, 0x2f, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0e, 0x32, 0x1b, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x45,
-	0x6e, 0x74, 0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04,
-	0x74, 0x79, 0x70, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73,
-	0x54, 0x6f, 0x41, 0x64, 0x64, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x0d, 0x45, 0x6e, 0x74,
-	0x69, 0x74, 0x69, 0x65, 0x73, 0x54, 0x6f, 0x41, 0x64, 0x64, 0x22, 0x77, 0x0a, 0x18, 0x52, 0x65,
-	0x6d, 0x6f, 0x76, 0x65, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x2f, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70,
-	0x62, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70,
-	0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x2a, 0x0a, 0x10, 0x45, 0x6e, 0x74, 0x69, 0x74,
-	0x69, 0x65, 0x73, 0x54, 0x6f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x02, 0x20, 0x03, 0x28,
-	0x0d, 0x52, 0x10, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x54, 0x6f, 0x52, 0x65, 0x6d,
-	0x6f, 0x76, 0x65, 0x22, 0x1f, 0x0a, 0x1d, 0x44, 0x65, 0x62, 0x75, 0x67, 0x47, 0x65, 0x74, 0x53,
-	0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x73, 0x4d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x2a, 0xa7, 0x01, 0x0a, 0x0d, 0x42, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61,
-	0x73, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x10, 0x0a, 0x0c, 0x4e, 0x4f, 0x5f, 0x42, 0x52, 0x4f,
-	0x41, 0x44, 0x43, 0x41, 0x53, 0x54, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x53, 0x49, 0x4e, 0x47,
-	0x4c, 0x45, 0x5f, 0x43, 0x4f, 0x4e, 0x4e, 0x45, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x01, 0x12,
-	0x07, 0x0a, 0x03, 0x41, 0x4c, 0x4c, 0x10, 0x02, 0x12, 0x12, 0x0a, 0x0e, 0x41, 0x4c, 0x4c, 0x5f,
-	0x42, 0x55, 0x54, 0x5f, 0x53, 0x45, 0x4e, 0x44, 0x45, 0x52, 0x10, 0x04, 0x12, 0x11, 0x0a, 0x0d,
-	0x41, 0x4c, 0x4c, 0x5f, 0x42, 0x55, 0x54, 0x5f, 0x4f, 0x57, 0x4e, 0x45, 0x52, 0x10, 0x08, 0x12,
-	0x12, 0x0a, 0x0e, 0x41, 0x4c, 0x4c, 0x5f, 0x42, 0x55, 0x54, 0x5f, 0x43, 0x4c, 0x49, 0x45, 0x4e,
-	0x54, 0x10, 0x10, 0x12, 0x12, 0x0a, 0x0e, 0x41, 0x4c, 0x4c, 0x5f, 0x42, 0x55, 0x54, 0x5f, 0x53,
-	0x45, 0x52, 0x56, 0x45, 0x52, 0x10, 0x20, 0x12, 0x15, 0x0a, 0x11, 0x41, 0x44, 0x4a, 0x41, 0x43,
-	0x45, 0x4e, 0x54, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x53, 0x10, 0x40, 0x2a, 0x3b,
-	0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65,
-	0x12, 0x11, 0x0a, 0x0d, 0x4e, 0x4f, 0x5f, 0x43, 0x4f, 0x4e, 0x4e, 0x45, 0x43, 0x54, 0x49, 0x4f,
-	0x4e, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x45, 0x52, 0x56, 0x45, 0x52, 0x10, 0x01, 0x12,
-	0x0a, 0x0a, 0x06, 0x43, 0x4c, 0x49, 0x45, 0x4e, 0x54, 0x10, 0x02, 0x2a, 0x90, 0x01, 0x0a, 0x0b,
-	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55,
-	0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x47, 0x4c, 0x4f, 0x42,
-	0x41, 0x4c, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x52, 0x49, 0x56, 0x41, 0x54, 0x45, 0x10,
-	0x02, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x55, 0x42, 0x57, 0x4f, 0x52, 0x4c, 0x44, 0x10, 0x03, 0x12,
-	0x0b, 0x0a, 0x07, 0x53, 0x50, 0x41, 0x54, 0x49, 0x41, 0x4c, 0x10, 0x04, 0x12, 0x0a, 0x0a, 0x06,
-	0x45, 0x4e, 0x54, 0x49, 0x54, 0x59, 0x10, 0x05, 0x12, 0x08, 0x0a, 0x04, 0x54, 0x45, 0x53, 0x54,
-	0x10, 0x64, 0x12, 0x09, 0x0a, 0x05, 0x54, 0x45, 0x53, 0x54, 0x31, 0x10, 0x65, 0x12, 0x09, 0x0a,
-	0x05, 0x54, 0x45, 0x53, 0x54, 0x32, 0x10, 0x66, 0x12, 0x09, 0x0a, 0x05, 0x54, 0x45, 0x53, 0x54,
-	0x33, 0x10, 0x67, 0x12, 0x09, 0x0a, 0x05, 0x54, 0x45, 0x53, 0x54, 0x34, 0x10, 0x68, 0x2a, 0xbd,
-	0x03, 0x0a, 0x0b, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b,
-	0x0a, 0x07, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x41,
-	0x55, 0x54, 0x48, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x43, 0x52, 0x45, 0x41, 0x54, 0x45, 0x5f,
-	0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x10, 0x03, 0x12, 0x12, 0x0a, 0x0e, 0x52, 0x45, 0x4d,
-	0x4f, 0x56, 0x45, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x10, 0x04, 0x12, 0x10, 0x0a,
-	0x0c, 0x4c, 0x49, 0x53, 0x54, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x10, 0x05, 0x12,
-	0x12, 0x0a, 0x0e, 0x53, 0x55, 0x42, 0x5f, 0x54, 0x4f, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45,
-	0x4c, 0x10, 0x06, 0x12, 0x16, 0x0a, 0x12, 0x55, 0x4e, 0x53, 0x55, 0x42, 0x5f, 0x46, 0x52, 0x4f,
-	0x4d, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x10, 0x07, 0x12, 0x17, 0x0a, 0x13, 0x43,
-	0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x44, 0x41, 0x54, 0x41, 0x5f, 0x55, 0x50, 0x44, 0x41,
-	0x54, 0x45, 0x10, 0x08, 0x12, 0x0e, 0x0a, 0x0a, 0x44, 0x49, 0x53, 0x43, 0x4f, 0x4e, 0x4e, 0x45,
-	0x43, 0x54, 0x10, 0x09, 0x12, 0x1a, 0x0a, 0x16, 0x43, 0x52, 0x45, 0x41, 0x54, 0x45, 0x5f, 0x53,
-	0x50, 0x41, 0x54, 0x49, 0x41, 0x4c, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x10, 0x0a,
-	0x12, 0x19, 0x0a, 0x15, 0x51, 0x55, 0x45, 0x52, 0x59, 0x5f, 0x53, 0x50, 0x41, 0x54, 0x49, 0x41,
-	0x4c, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x10, 0x0b, 0x12, 0x19, 0x0a, 0x15, 0x43,
-	0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x44, 0x41, 0x54, 0x41, 0x5f, 0x48, 0x41, 0x4e, 0x44,
-	0x4f, 0x56, 0x45, 0x52, 0x10, 0x0c, 0x12, 0x1a, 0x0a, 0x16, 0x53, 0x50, 0x41, 0x54, 0x49, 0x41,
-	0x4c, 0x5f, 0x52, 0x45, 0x47, 0x49, 0x4f, 0x4e, 0x53, 0x5f, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45,
-	0x10, 0x0d, 0x12, 0x1b, 0x0a, 0x17, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x5f, 0x53, 0x50, 0x41,
-	0x54, 0x49, 0x41, 0x4c, 0x5f, 0x49, 0x4e, 0x54, 0x45, 0x52, 0x45, 0x53, 0x54, 0x10, 0x0e, 0x12,
-	0x19, 0x0a, 0x15, 0x43, 0x52, 0x45, 0x41, 0x54, 0x45, 0x5f, 0x45, 0x4e, 0x54, 0x49, 0x54, 0x59,
-	0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x10, 0x0f, 0x12, 0x14, 0x0a, 0x10, 0x45, 0x4e,
-	0x54, 0x49, 0x54, 0x59, 0x5f, 0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f, 0x41, 0x44, 0x44, 0x10, 0x10,
-	0x12, 0x17, 0x0a, 0x13, 0x45, 0x4e, 0x54, 0x49, 0x54, 0x59, 0x5f, 0x47, 0x52, 0x4f, 0x55, 0x50,
-	0x5f, 0x52, 0x45, 0x4d, 0x4f, 0x56, 0x45, 0x10, 0x11, 0x12, 0x1d, 0x0a, 0x19, 0x44, 0x45, 0x42,
-	0x55, 0x47, 0x5f, 0x47, 0x45, 0x54, 0x5f, 0x53, 0x50, 0x41, 0x54, 0x49, 0x41, 0x4c, 0x5f, 0x52,
-	0x45, 0x47, 0x49, 0x4f, 0x4e, 0x53, 0x10, 0x63, 0x12, 0x14, 0x0a, 0x10, 0x55, 0x53, 0x45, 0x52,
-	0x5f, 0x53, 0x50, 0x41, 0x43, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x52, 0x54, 0x10, 0x64, 0x2a, 0x31,
-	0x0a, 0x0f, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70,
-	0x65, 0x12, 0x12, 0x0a, 0x0e, 0x4e, 0x4f, 0x5f, 0x43, 0x4f, 0x4d, 0x50, 0x52, 0x45, 0x53, 0x53,
-	0x49, 0x4f, 0x4e, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x4e, 0x41, 0x50, 0x50, 0x59, 0x10,
-	0x01, 0x2a, 0x45, 0x0a, 0x11, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74, 0x61,
-	0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x0d, 0x0a, 0x09, 0x4e, 0x4f, 0x5f, 0x41, 0x43, 0x43,
-	0x45, 0x53, 0x53, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x52, 0x45, 0x41, 0x44, 0x5f, 0x41, 0x43,
-	0x43, 0x45, 0x53, 0x53, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x57, 0x52, 0x49, 0x54, 0x45, 0x5f,
-	0x41, 0x43, 0x43, 0x45, 0x53, 0x53, 0x10, 0x02, 0x2a, 0x29, 0x0a, 0x0f, 0x45, 0x6e, 0x74, 0x69,
-	0x74, 0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0c, 0x0a, 0x08, 0x48,
-	0x41, 0x4e, 0x44, 0x4f, 0x56, 0x45, 0x52, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x4c, 0x4f, 0x43,
-	0x4b, 0x10, 0x01, 0x42, 0x30, 0x5a, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
-	0x6d, 0x2f, 0x6d, 0x65, 0x74, 0x61, 0x77, 0x6f, 0x72, 0x6b, 0x69, 0x6e, 0x67, 0x2f, 0x63, 0x68,
-	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x63, 0x68, 0x61, 0x6e, 0x6e,
-	0x65, 0x6c, 0x64, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
-}
-
-var (
-	file_channeld_proto_rawDescOnce sync.Once
-	file_channeld_proto_rawDescData = file_channeld_proto_rawDesc
-)
-
-func file_channeld_proto_rawDescGZIP() []byte {
-	file_channeld_proto_rawDescOnce.Do(func() {
-		file_channeld_proto_rawDescData = protoimpl.X.CompressGZIP(file_channeld_proto_rawDescData)
-	})
-	return file_channeld_proto_rawDescData
-}
-
-var file_channeld_proto_enumTypes = make([]protoimpl.EnumInfo, 8)
-var file_channeld_proto_msgTypes = make([]protoimpl.MessageInfo, 36)
-var file_channeld_proto_goTypes = []interface{}{
-	(BroadcastType)(0),                           // 0: channeldpb.BroadcastType
-	(ConnectionType)(0),                          // 1: channeldpb.ConnectionType
-	(ChannelType)(0),                             // 2: channeldpb.ChannelType
-	(MessageType)(0),                             // 3: channeldpb.MessageType
-	(CompressionType)(0),                         // 4: channeldpb.CompressionType
-	(ChannelDataAccess)(0),                       // 5: channeldpb.ChannelDataAccess
-	(EntityGroupType)(0),                         // 6: channeldpb.EntityGroupType
-	(AuthResultMessage_AuthResult)(0),            // 7: channeldpb.AuthResultMessage.AuthResult
-	(*Packet)(nil),                               // 8: channeldpb.Packet
-	(*MessagePack)(nil),                          // 9: channeldpb.MessagePack
-	(*ServerForwardMessage)(nil),                 // 10: channeldpb.ServerForwardMessage
-	(*AuthMessage)(nil),                          // 11: channeldpb.AuthMessage
-	(*AuthResultMessage)(nil),                    // 12: channeldpb.AuthResultMessage
-	(*ChannelSubscriptionOptions)(nil),           // 13: channeldpb.ChannelSubscriptionOptions
-	(*ChannelDataMergeOptions)(nil),              // 14: channeldpb.ChannelDataMergeOptions
-	(*CreateChannelMessage)(nil),                 // 15: channeldpb.CreateChannelMessage
-	(*CreateChannelResultMessage)(nil),           // 16: channeldpb.CreateChannelResultMessage
-	(*RemoveChannelMessage)(nil),                 // 17: channeldpb.RemoveChannelMessage
-	(*ListChannelMessage)(nil),                   // 18: channeldpb.ListChannelMessage
-	(*ListChannelResultMessage)(nil),             // 19: channeldpb.ListChannelResultMessage
-	(*SubscribedToChannelMessage)(nil),           // 20: channeldpb.SubscribedToChannelMessage
-	(*SubscribedToChannelResultMessage)(nil),     // 21: channeldpb.SubscribedToChannelResultMessage
-	(*UnsubscribedFromChannelMessage)(nil),       // 22: channeldpb.UnsubscribedFromChannelMessage
-	(*UnsubscribedFromChannelResultMessage)(nil), // 23: channeldpb.UnsubscribedFromChannelResultMessage
-	(*ChannelDataUpdateMessage)(nil),             // 24: channeldpb.ChannelDataUpdateMessage
-	(*DisconnectMessage)(nil),                    // 25: channeldpb.DisconnectMessage
-	(*SpatialInfo)(nil),                          // 26: channeldpb.SpatialInfo
-	(*CreateSpatialChannelsResultMessage)(nil),   // 27: channeldpb.CreateSpatialChannelsResultMessage
-	(*QuerySpatialChannelMessage)(nil),           // 28: channeldpb.QuerySpatialChannelMessage
-	(*QuerySpatialChannelResultMessage)(nil),     // 29: channeldpb.QuerySpatialChannelResultMessage
-	(*ChannelDataHandoverMessage)(nil),           // 30: channeldpb.ChannelDataHandoverMessage
-	(*SpatialRegion)(nil),                        // 31: channeldpb.SpatialRegion
-	(*SpatialRegionsUpdateMessage)(nil),          // 32: channeldpb.SpatialRegionsUpdateMessage
-	(*SpatialInterestQuery)(nil),                 // 33: channeldpb.SpatialInterestQuery
-	(*UpdateSpatialInterestMessage)(nil),         // 34: channeldpb.UpdateSpatialInterestMessage
-	(*CreateEntityChannelMessage)(nil),           // 35: channeldpb.CreateEntityChannelMessage
-	(*AddEntityGroupMessage)(nil),                // 36: channeldpb.AddEntityGroupMessage
-	(*RemoveEntityGroupMessage)(nil),             // 37: channeldpb.RemoveEntityGroupMessage
-	(*DebugGetSpatialRegionsMessage)(nil),        // 38: channeldpb.DebugGetSpatialRegionsMessage
-	(*ListChannelResultMessage_ChannelInfo)(nil), // 39: channeldpb.ListChannelResultMessage.ChannelInfo
-	(*SpatialInterestQuery_SpotsAOI)(nil),        // 40: channeldpb.SpatialInterestQuery.SpotsAOI
-	(*SpatialInterestQuery_BoxAOI)(nil),          // 41: channeldpb.SpatialInterestQuery.BoxAOI
-	(*SpatialInterestQuery_SphereAOI)(nil),       // 42: channeldpb.SpatialInterestQuery.SphereAOI
-	(*SpatialInterestQuery_ConeAOI)(nil),         // 43: channeldpb.SpatialInterestQuery.ConeAOI
-	(*anypb.Any)(nil),                            // 44: google.protobuf.Any
-}
-var file_channeld_proto_depIdxs = []int32{
-	9,  // 0: channeldpb.Packet.messages:type_name -> channeldpb.MessagePack
-	7,  // 1: channeldpb.AuthResultMessage.result:type_name -> channeldpb.AuthResultMessage.AuthResult
-	4,  // 2: channeldpb.AuthResultMessage.compressionType:type_name -> channeldpb.CompressionType
-	5,  // 3: channeldpb.ChannelSubscriptionOptions.dataAccess:type_name -> channeldpb.ChannelDataAccess
-	2,  // 4: channeldpb.CreateChannelMessage.channelType:type_name -> channeldpb.ChannelType
-	13, // 5: channeldpb.CreateChannelMessage.subOptions:type_name -> channeldpb.ChannelSubscriptionOptions
-	44, // 6: channeldpb.CreateChannelMessage.data:type_name -> google.protobuf.Any
-	14, // 7: channeldpb.CreateChannelMessage.mergeOptions:type_name -> channeldpb.ChannelDataMergeOptions
-	2,  // 8: channeldpb.CreateChannelResultMessage.channelType:type_name -> channeldpb.ChannelType
-	2,  // 9: channeldpb.ListChannelMessage.typeFilter:type_name -> channeldpb.ChannelType
-	39, // 10: channeldpb.ListChannelResultMessage.channels:type_name -> channeldpb.ListChannelResultMessage.ChannelInfo
-	13, // 11: channeldpb.SubscribedToChannelMessage.subOptions:type_name -> channeldpb.ChannelSubscriptionOptions
-	13, // 12: channeldpb.SubscribedToChannelResultMessage.subOptions:type_name -> channeldpb.ChannelSubscriptionOptions
-	1,  // 13: channeldpb.SubscribedToChannelResultMessage.connType:type_name -> channeldpb.ConnectionType
-	2,  // 14: channeldpb.SubscribedToChannelResultMessage.channelType:type_name -> channeldpb.ChannelType
-	1,  // 15: channeldpb.UnsubscribedFromChannelResultMessage.connType:type_name -> channeldpb.ConnectionType
-	2,  // 16: channeldpb.UnsubscribedFromChannelResultMessage.channelType:type_name -> channeldpb.ChannelType
-	44, // 17: channeldpb.ChannelDataUpdateMessage.data:type_name -> google.protobuf.Any
-	26, // 18: channeldpb.QuerySpatialChannelMessage.spatialInfo:type_name -> channeldpb.SpatialInfo
-	44, // 19: channeldpb.ChannelDataHandoverMessage.data:type_name -> google.protobuf.Any
-	26, // 20: channeldpb.SpatialRegion.min:type_name -> channeldpb.SpatialInfo
-	26, // 21: channeldpb.SpatialRegion.max:type_name -> channeldpb.SpatialInfo
-	31, // 22: channeldpb.SpatialRegionsUpdateMessage.regions:type_name -> channeldpb.SpatialRegion
-	40, // 23: channeldpb.SpatialInterestQuery.spotsAOI:type_name -> channeldpb.SpatialInterestQuery.SpotsAOI
-	41, // 24: channeldpb.SpatialInterestQuery.boxAOI:type_name -> channeldpb.SpatialInterestQuery.BoxAOI
-	42, // 25: channeldpb.SpatialInterestQuery.sphereAOI:type_name -> channeldpb.SpatialInterestQuery.SphereAOI
-	43, // 26: channeldpb.SpatialInterestQuery.coneAOI:type_name -> channeldpb.SpatialInterestQuery.ConeAOI
-	33, // 27: channeldpb.UpdateSpatialInterestMessage.query:type_name -> channeldpb.SpatialInterestQuery
-	13, // 28: channeldpb.CreateEntityChannelMessage.subOptions:type_name -> channeldpb.ChannelSubscriptionOptions
-	44, // 29: channeldpb.CreateEntityChannelMessage.data:type_name -> google.protobuf.Any
-	14, // 30: channeldpb.CreateEntityChannelMessage.mergeOptions:type_name -> channeldpb.ChannelDataMergeOptions
-	6,  // 31: channeldpb.AddEntityGroupMessage.type:type_name -> channeldpb.EntityGroupType
-	6,  // 32: channeldpb.RemoveEntityGroupMessage.type:type_name -> channeldpb.EntityGroupType
-	2,  // 33: channeldpb.ListChannelResultMessage.ChannelInfo.channelType:type_name -> channeldpb.ChannelType
-	26, // 34: channeldpb.SpatialInterestQuery.SpotsAOI.spots:type_name -> channeldpb.SpatialInfo
-	26, // 35: channeldpb.SpatialInterestQuery.BoxAOI.center:type_name -> channeldpb.SpatialInfo
-	26, // 36: channeldpb.SpatialInterestQuery.BoxAOI.extent:type_name -> channeldpb.SpatialInfo
-	26, // 37: channeldpb.SpatialInterestQuery.SphereAOI.center:type_name -> channeldpb.SpatialInfo
-	26, // 38: channeldpb.SpatialInterestQuery.ConeAOI.center:type_name -> channeldpb.SpatialInfo
-	26, // 39: channeldpb.SpatialInterestQuery.ConeAOI.direction:type_name -> channeldpb.SpatialInfo
-	40, // [40:40] is the sub-list for method output_type
-	40, // [40:40] is the sub-list for method input_type
-	40, // [40:40] is the sub-list for extension type_name
-	40, // [40:40] is the sub-list for extension extendee
-	0,  // [0:40] is the sub-list for field type_name
-}
-
-func init() { file_channeld_proto_init() }
-func file_channeld_proto_init() {
-	if File_channeld_proto != nil {
-		return
-	}
-	if !protoimpl.UnsafeEnabled {
-		file_channeld_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Packet); i {
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlowSubscriberMessage.ProtoReflect.Descriptor instead.
+func (*SlowSubscriberMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *SlowSubscriberMessage) GetConnId() uint32 {
+	if x != nil {
+		return x.ConnId
+	}
+	return 0
+}
+
+func (x *SlowSubscriberMessage) GetFanOutIntervalMs() uint32 {
+	if x != nil {
+		return x.FanOutIntervalMs
+	}
+	return 0
+}
+
+func (x *SlowSubscriberMessage) GetKeyframeOnly() bool {
+	if x != nil {
+		return x.KeyframeOnly
+	}
+	return false
+}
+
+// Sent to the channel owner and the penalized connection when a user is muted for flooding a channel that has
+// ChannelSettings.ChatFlood enabled. The user-space broadcasts of the user are dropped until the mute expires.
+type ChatPenaltyMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ConnId uint32                    `protobuf:"varint,1,opt,name=connId,proto3" json:"connId,omitempty"`
+	UserId string                    `protobuf:"bytes,2,opt,name=userId,proto3" json:"userId,omitempty"`
+	Reason ChatPenaltyMessage_Reason `protobuf:"varint,3,opt,name=reason,proto3,enum=channeldpb.ChatPenaltyMessage_Reason" json:"reason,omitempty"`
+	// How many penalties the user has received in a row, starting from 1. The mute is longer with the higher level.
+	Level  uint32 `protobuf:"varint,4,opt,name=level,proto3" json:"level,omitempty"`
+	MuteMs uint32 `protobuf:"varint,5,opt,name=muteMs,proto3" json:"muteMs,omitempty"`
+}
+
+func (x *ChatPenaltyMessage) Reset() {
+	*x = ChatPenaltyMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChatPenaltyMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatPenaltyMessage) ProtoMessage() {}
+
+func (x *ChatPenaltyMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatPenaltyMessage.ProtoReflect.Descriptor instead.
+func (*ChatPenaltyMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ChatPenaltyMessage) GetConnId() uint32 {
+	if x != nil {
+		return x.ConnId
+	}
+	return 0
+}
+
+func (x *ChatPenaltyMessage) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ChatPenaltyMessage) GetReason() ChatPenaltyMessage_Reason {
+	if x != nil {
+		return x.Reason
+	}
+	return ChatPenaltyMessage_REPEATED
+}
+
+func (x *ChatPenaltyMessage) GetLevel() uint32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *ChatPenaltyMessage) GetMuteMs() uint32 {
+	if x != nil {
+		return x.MuteMs
+	}
+	return 0
+}
+
+// Sent to the connection when its request is rejected, or its packets are dropped, as a quota of its tenant is hit.
+// See TenantSettingsType in channeld.
+type QuotaExceededMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	QuotaType QuotaExceededMessage_QuotaType `protobuf:"varint,1,opt,name=quotaType,proto3,enum=channeldpb.QuotaExceededMessage_QuotaType" json:"quotaType,omitempty"`
+	Tenant    string                         `protobuf:"bytes,2,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	// The limit of the quota
+	Limit int64 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// The type of the channel that failed to be created. Only for CHANNELS and CHANNELS_OF_TYPE.
+	ChannelType ChannelType `protobuf:"varint,4,opt,name=channelType,proto3,enum=channeldpb.ChannelType" json:"channelType,omitempty"`
+}
+
+func (x *QuotaExceededMessage) Reset() {
+	*x = QuotaExceededMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QuotaExceededMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuotaExceededMessage) ProtoMessage() {}
+
+func (x *QuotaExceededMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuotaExceededMessage.ProtoReflect.Descriptor instead.
+func (*QuotaExceededMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *QuotaExceededMessage) GetQuotaType() QuotaExceededMessage_QuotaType {
+	if x != nil {
+		return x.QuotaType
+	}
+	return QuotaExceededMessage_CHANNELS
+}
+
+func (x *QuotaExceededMessage) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *QuotaExceededMessage) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *QuotaExceededMessage) GetChannelType() ChannelType {
+	if x != nil {
+		return x.ChannelType
+	}
+	return ChannelType_UNKNOWN
+}
+
+// Sent by the channel owner to pause or resume the channel data fan-out to a subscriber or the whole channel, e.g. while the
+// clients are on the loading screen and can't process the updates. The updates are buffered and coalesced during the pause,
+// and the next fan-out after the resume carries the whole channel data as a single consolidated update.
+// Response: @ErrorMessage if the sender is not the owner, or the connection is not subscribed to the channel.
+type PauseDataFanOutMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The subscriber to pause or resume. 0 means the whole channel.
+	ConnId uint32 `protobuf:"varint,1,opt,name=connId,proto3" json:"connId,omitempty"`
+	// False to pause, true to resume.
+	Resume bool `protobuf:"varint,2,opt,name=resume,proto3" json:"resume,omitempty"`
+}
+
+func (x *PauseDataFanOutMessage) Reset() {
+	*x = PauseDataFanOutMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PauseDataFanOutMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseDataFanOutMessage) ProtoMessage() {}
+
+func (x *PauseDataFanOutMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseDataFanOutMessage.ProtoReflect.Descriptor instead.
+func (*PauseDataFanOutMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *PauseDataFanOutMessage) GetConnId() uint32 {
+	if x != nil {
+		return x.ConnId
+	}
+	return 0
+}
+
+func (x *PauseDataFanOutMessage) GetResume() bool {
+	if x != nil {
+		return x.Resume
+	}
+	return false
+}
+
+// Sent by the subscriber that has detected its channel data diverged from channeld's (see @ChannelDataUpdateMessage.checksum).
+// The next fan-out to the subscriber will contain the whole channel data.
+type ChannelDataResyncMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The checksum of the subscriber's channel data, for diagnosis.
+	Checksum uint32 `protobuf:"varint,1,opt,name=checksum,proto3" json:"checksum,omitempty"`
+}
+
+func (x *ChannelDataResyncMessage) Reset() {
+	*x = ChannelDataResyncMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChannelDataResyncMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelDataResyncMessage) ProtoMessage() {}
+
+func (x *ChannelDataResyncMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelDataResyncMessage.ProtoReflect.Descriptor instead.
+func (*ChannelDataResyncMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ChannelDataResyncMessage) GetChecksum() uint32 {
+	if x != nil {
+		return x.Checksum
+	}
+	return 0
+}
+
+// Sent back to the connection whose @ChannelDataUpdateMessage is rejected for being based on a stale version of the channel data.
+// The sender should apply the latest data (from the fan-out or @QueryChannelDataMessage) and retry.
+type ChannelDataConflictMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The rejected update
+	Data        *anypb.Any `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	BaseVersion uint64     `protobuf:"varint,2,opt,name=baseVersion,proto3" json:"baseVersion,omitempty"`
+	// The current version of the channel data
+	CurrentVersion uint64 `protobuf:"varint,3,opt,name=currentVersion,proto3" json:"currentVersion,omitempty"`
+	// The versioned fields (or map entries, e.g. 'players[1001]') changed by other connections since the base version.
+	ConflictFields []string `protobuf:"bytes,4,rep,name=conflictFields,proto3" json:"conflictFields,omitempty"`
+}
+
+func (x *ChannelDataConflictMessage) Reset() {
+	*x = ChannelDataConflictMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChannelDataConflictMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelDataConflictMessage) ProtoMessage() {}
+
+func (x *ChannelDataConflictMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelDataConflictMessage.ProtoReflect.Descriptor instead.
+func (*ChannelDataConflictMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ChannelDataConflictMessage) GetData() *anypb.Any {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ChannelDataConflictMessage) GetBaseVersion() uint64 {
+	if x != nil {
+		return x.BaseVersion
+	}
+	return 0
+}
+
+func (x *ChannelDataConflictMessage) GetCurrentVersion() uint64 {
+	if x != nil {
+		return x.CurrentVersion
+	}
+	return 0
+}
+
+func (x *ChannelDataConflictMessage) GetConflictFields() []string {
+	if x != nil {
+		return x.ConflictFields
+	}
+	return nil
+}
+
+// Acquires or releases the exclusive write access to an entry of a map field in the channel data, e.g. an auction item.
+// While the entry is locked, the updates of it from other connections are dropped when merging. The other entries are not affected.
+// The lock expires automatically, or when the holder is disconnected.
+// Only the channel owner and the subscribers with the write access can send it.
+// Response: @ChannelDataLockResultMessage
+type ChannelDataLockMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the top-level map field in the channel data, e.g. 'items'
+	Field string `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	// The map key in the text form, e.g. '1001'
+	Key string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// True to release the lock. Only the holder can release it.
+	Unlock bool `protobuf:"varint,3,opt,name=unlock,proto3" json:"unlock,omitempty"`
+	// How long the lock lasts if not released. Locking again by the holder renews it. 0 means the default (5s). Up to 60s.
+	ExpireMs uint32 `protobuf:"varint,4,opt,name=expireMs,proto3" json:"expireMs,omitempty"`
+}
+
+func (x *ChannelDataLockMessage) Reset() {
+	*x = ChannelDataLockMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChannelDataLockMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelDataLockMessage) ProtoMessage() {}
+
+func (x *ChannelDataLockMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelDataLockMessage.ProtoReflect.Descriptor instead.
+func (*ChannelDataLockMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ChannelDataLockMessage) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *ChannelDataLockMessage) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ChannelDataLockMessage) GetUnlock() bool {
+	if x != nil {
+		return x.Unlock
+	}
+	return false
+}
+
+func (x *ChannelDataLockMessage) GetExpireMs() uint32 {
+	if x != nil {
+		return x.ExpireMs
+	}
+	return 0
+}
+
+type ChannelDataLockResultMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Field   string `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Key     string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Unlock  bool   `protobuf:"varint,3,opt,name=unlock,proto3" json:"unlock,omitempty"`
+	Success bool   `protobuf:"varint,4,opt,name=success,proto3" json:"success,omitempty"`
+	// The connection that holds the lock after the request. 0 means not locked.
+	HolderConnId uint32 `protobuf:"varint,5,opt,name=holderConnId,proto3" json:"holderConnId,omitempty"`
+	// The channel time when the lock expires, in milliseconds.
+	ExpireTimeMs int64 `protobuf:"varint,6,opt,name=expireTimeMs,proto3" json:"expireTimeMs,omitempty"`
+}
+
+func (x *ChannelDataLockResultMessage) Reset() {
+	*x = ChannelDataLockResultMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChannelDataLockResultMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelDataLockResultMessage) ProtoMessage() {}
+
+func (x *ChannelDataLockResultMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelDataLockResultMessage.ProtoReflect.Descriptor instead.
+func (*ChannelDataLockResultMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ChannelDataLockResultMessage) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *ChannelDataLockResultMessage) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ChannelDataLockResultMessage) GetUnlock() bool {
+	if x != nil {
+		return x.Unlock
+	}
+	return false
+}
+
+func (x *ChannelDataLockResultMessage) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ChannelDataLockResultMessage) GetHolderConnId() uint32 {
+	if x != nil {
+		return x.HolderConnId
+	}
+	return 0
+}
+
+func (x *ChannelDataLockResultMessage) GetExpireTimeMs() int64 {
+	if x != nil {
+		return x.ExpireTimeMs
+	}
+	return 0
+}
+
+// Reads the current channel data once, without subscribing to the channel.
+// The channel owner, the subscribers, and the connections that have the sub access of the channel can query the data.
+// Response: @QueryChannelDataResultMessage
+type QueryChannelDataMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Same as @ChannelSubscriptionOptions.DataFieldMasks. If empty, the full channel data is returned.
+	DataFieldMasks []string `protobuf:"bytes,1,rep,name=dataFieldMasks,proto3" json:"dataFieldMasks,omitempty"`
+}
+
+func (x *QueryChannelDataMessage) Reset() {
+	*x = QueryChannelDataMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryChannelDataMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryChannelDataMessage) ProtoMessage() {}
+
+func (x *QueryChannelDataMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryChannelDataMessage.ProtoReflect.Descriptor instead.
+func (*QueryChannelDataMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *QueryChannelDataMessage) GetDataFieldMasks() []string {
+	if x != nil {
+		return x.DataFieldMasks
+	}
+	return nil
+}
+
+// Applies the mutations of the map entries in the channel data atomically: either all the mutations are applied, or the
+// transaction is rejected as a whole, e.g. debiting the currency and adding the item when buying an item.
+// Requires the same access as @ChannelDataUpdateMessage. The applied mutations are fanned out as a normal update.
+// Response: @ChannelDataTransactionResultMessage
+type ChannelDataTransactionMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Applied in order. The later mutations see the results of the earlier ones.
+	Mutations []*ChannelDataTransactionMessage_Mutation `protobuf:"bytes,1,rep,name=mutations,proto3" json:"mutations,omitempty"`
+}
+
+func (x *ChannelDataTransactionMessage) Reset() {
+	*x = ChannelDataTransactionMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChannelDataTransactionMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelDataTransactionMessage) ProtoMessage() {}
+
+func (x *ChannelDataTransactionMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelDataTransactionMessage.ProtoReflect.Descriptor instead.
+func (*ChannelDataTransactionMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ChannelDataTransactionMessage) GetMutations() []*ChannelDataTransactionMessage_Mutation {
+	if x != nil {
+		return x.Mutations
+	}
+	return nil
+}
+
+type ChannelDataTransactionResultMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result ChannelDataTransactionResultMessage_Result `protobuf:"varint,1,opt,name=result,proto3,enum=channeldpb.ChannelDataTransactionResultMessage_Result" json:"result,omitempty"`
+	// The index of the mutation that failed the transaction.
+	FailedIndex uint32 `protobuf:"varint,2,opt,name=failedIndex,proto3" json:"failedIndex,omitempty"`
+	// The version of the channel data after the transaction. See @ChannelDataUpdateMessage.version.
+	Version uint64 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (x *ChannelDataTransactionResultMessage) Reset() {
+	*x = ChannelDataTransactionResultMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChannelDataTransactionResultMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelDataTransactionResultMessage) ProtoMessage() {}
+
+func (x *ChannelDataTransactionResultMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelDataTransactionResultMessage.ProtoReflect.Descriptor instead.
+func (*ChannelDataTransactionResultMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ChannelDataTransactionResultMessage) GetResult() ChannelDataTransactionResultMessage_Result {
+	if x != nil {
+		return x.Result
+	}
+	return ChannelDataTransactionResultMessage_APPLIED
+}
+
+func (x *ChannelDataTransactionResultMessage) GetFailedIndex() uint32 {
+	if x != nil {
+		return x.FailedIndex
+	}
+	return 0
+}
+
+func (x *ChannelDataTransactionResultMessage) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// Pages the older entries of a list field in the channel data (e.g. the chat history) that have been truncated by
+// @ChannelDataMergeOptions.listSizeLimit. Only the entries truncated from the top (see @ChannelDataMergeOptions.truncateTop) are retained,
+// up to ChannelSettingsType.ListHistorySize in channeld. The channel owner and the subscribers can fetch the history.
+// Response: @FetchHistoryResultMessage
+type FetchHistoryMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the top-level list field in the channel data, e.g. 'chatMessages'
+	Field string `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	// Fetches the entries before the index. Every entry ever added to the list has an index, in the order of being added, starting from 0.
+	// 0 means fetching from the latest truncated entry.
+	BeforeIndex uint64 `protobuf:"varint,2,opt,name=beforeIndex,proto3" json:"beforeIndex,omitempty"`
+	// The max number of the entries to fetch. 0 means the default (20). Up to 100.
+	Limit uint32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *FetchHistoryMessage) Reset() {
+	*x = FetchHistoryMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetchHistoryMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchHistoryMessage) ProtoMessage() {}
+
+func (x *FetchHistoryMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchHistoryMessage.ProtoReflect.Descriptor instead.
+func (*FetchHistoryMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *FetchHistoryMessage) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FetchHistoryMessage) GetBeforeIndex() uint64 {
+	if x != nil {
+		return x.BeforeIndex
+	}
+	return 0
+}
+
+func (x *FetchHistoryMessage) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type FetchHistoryResultMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Field string `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	// The channel data message that only has the list field set, with the fetched entries in the order of being added.
+	Data *anypb.Any `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	// The index of the first fetched entry. Use it as the beforeIndex to fetch the next page.
+	StartIndex uint64 `protobuf:"varint,3,opt,name=startIndex,proto3" json:"startIndex,omitempty"`
+	// True if there are older entries retained.
+	HasMore bool `protobuf:"varint,4,opt,name=hasMore,proto3" json:"hasMore,omitempty"`
+}
+
+func (x *FetchHistoryResultMessage) Reset() {
+	*x = FetchHistoryResultMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetchHistoryResultMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchHistoryResultMessage) ProtoMessage() {}
+
+func (x *FetchHistoryResultMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchHistoryResultMessage.ProtoReflect.Descriptor instead.
+func (*FetchHistoryResultMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *FetchHistoryResultMessage) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *FetchHistoryResultMessage) GetData() *anypb.Any {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *FetchHistoryResultMessage) GetStartIndex() uint64 {
+	if x != nil {
+		return x.StartIndex
+	}
+	return 0
+}
+
+func (x *FetchHistoryResultMessage) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+// Fetches a window of the sorted leaderboard in the channel data. See ChannelSettings.Leaderboard.
+// Response: @FetchLeaderboardResultMessage
+type FetchLeaderboardMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Fetches the entries around the key of the leaderboard map field, e.g. the user id of the player.
+	// The integer keys are in the decimal format. Takes precedence over startRank.
+	AroundKey string `protobuf:"bytes,1,opt,name=aroundKey,proto3" json:"aroundKey,omitempty"`
+	// If true, the aroundKey is the user id of the connection.
+	AroundSelf bool `protobuf:"varint,2,opt,name=aroundSelf,proto3" json:"aroundSelf,omitempty"`
+	// The number of the entries before and after the key in the window.
+	Before uint32 `protobuf:"varint,3,opt,name=before,proto3" json:"before,omitempty"`
+	After  uint32 `protobuf:"varint,4,opt,name=after,proto3" json:"after,omitempty"`
+	// Fetches the entries from the rank, starting from 0, if no key is specified.
+	StartRank uint32 `protobuf:"varint,5,opt,name=startRank,proto3" json:"startRank,omitempty"`
+	// The max number of the entries to fetch from the startRank. 0 means the default (20). Up to 100.
+	Limit uint32 `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *FetchLeaderboardMessage) Reset() {
+	*x = FetchLeaderboardMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[48]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetchLeaderboardMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchLeaderboardMessage) ProtoMessage() {}
+
+func (x *FetchLeaderboardMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[48]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchLeaderboardMessage.ProtoReflect.Descriptor instead.
+func (*FetchLeaderboardMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *FetchLeaderboardMessage) GetAroundKey() string {
+	if x != nil {
+		return x.AroundKey
+	}
+	return ""
+}
+
+func (x *FetchLeaderboardMessage) GetAroundSelf() bool {
+	if x != nil {
+		return x.AroundSelf
+	}
+	return false
+}
+
+func (x *FetchLeaderboardMessage) GetBefore() uint32 {
+	if x != nil {
+		return x.Before
+	}
+	return 0
+}
+
+func (x *FetchLeaderboardMessage) GetAfter() uint32 {
+	if x != nil {
+		return x.After
+	}
+	return 0
+}
+
+func (x *FetchLeaderboardMessage) GetStartRank() uint32 {
+	if x != nil {
+		return x.StartRank
+	}
+	return 0
+}
+
+func (x *FetchLeaderboardMessage) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type FetchLeaderboardResultMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The channel data message that only has the leaderboard map field set, with the fetched entries.
+	Data *anypb.Any `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	// The keys of the fetched entries in the order of the rank, in the same format as the aroundKey.
+	Keys []string `protobuf:"bytes,2,rep,name=keys,proto3" json:"keys,omitempty"`
+	// The rank of the first fetched entry, starting from 0.
+	StartRank uint32 `protobuf:"varint,3,opt,name=startRank,proto3" json:"startRank,omitempty"`
+	// The number of the entries in the leaderboard.
+	Total uint32 `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	// The rank of the aroundKey. -1 if the key is not in the leaderboard, or no key is specified.
+	KeyRank int32 `protobuf:"varint,5,opt,name=keyRank,proto3" json:"keyRank,omitempty"`
+}
+
+func (x *FetchLeaderboardResultMessage) Reset() {
+	*x = FetchLeaderboardResultMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[49]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetchLeaderboardResultMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchLeaderboardResultMessage) ProtoMessage() {}
+
+func (x *FetchLeaderboardResultMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[49]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchLeaderboardResultMessage.ProtoReflect.Descriptor instead.
+func (*FetchLeaderboardResultMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *FetchLeaderboardResultMessage) GetData() *anypb.Any {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *FetchLeaderboardResultMessage) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *FetchLeaderboardResultMessage) GetStartRank() uint32 {
+	if x != nil {
+		return x.StartRank
+	}
+	return 0
+}
+
+func (x *FetchLeaderboardResultMessage) GetTotal() uint32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *FetchLeaderboardResultMessage) GetKeyRank() int32 {
+	if x != nil {
+		return x.KeyRank
+	}
+	return 0
+}
+
+// A user-space broadcast saved by the message archive of channeld. See ChannelSettings.ArchiveBroadcasts.
+type ArchivedMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The unique id in the archive, in the order of being archived.
+	Id        uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ChannelId uint32 `protobuf:"varint,2,opt,name=channelId,proto3" json:"channelId,omitempty"`
+	// The key of the channel, if it has one. The channelId can be reused after the channel is removed, but the key can't.
+	ChannelKey string `protobuf:"bytes,3,opt,name=channelKey,proto3" json:"channelKey,omitempty"`
+	// See @ServerForwardMessage.seq
+	Seq uint64 `protobuf:"varint,4,opt,name=seq,proto3" json:"seq,omitempty"`
+	// The connection that sends the broadcast.
+	SenderConnId uint32 `protobuf:"varint,5,opt,name=senderConnId,proto3" json:"senderConnId,omitempty"`
+	// The 'userId' tag of the sender, if set. See @SetConnectionTagsMessage.
+	// If the sender is a server connection, the user id of the client of the clientConnId.
+	SenderUserId string `protobuf:"bytes,6,opt,name=senderUserId,proto3" json:"senderUserId,omitempty"`
+	// When the message is broadcasted, in unix milliseconds.
+	Timestamp int64  `protobuf:"varint,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	MsgType   uint32 `protobuf:"varint,8,opt,name=msgType,proto3" json:"msgType,omitempty"`
+	// The user-space message, in the original binary format.
+	Payload []byte `protobuf:"bytes,9,opt,name=payload,proto3" json:"payload,omitempty"`
+	// The tenant of the channel. See TenantTag in channeld.
+	Tenant string `protobuf:"bytes,10,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	// The BroadcastType of the message
+	Broadcast uint32 `protobuf:"varint,11,opt,name=broadcast,proto3" json:"broadcast,omitempty"`
+	// See @ServerForwardMessage.tagFilter. Only the connections that match the filter can fetch the message.
+	TagFilter string `protobuf:"bytes,12,opt,name=tagFilter,proto3" json:"tagFilter,omitempty"`
+	// See @ServerForwardMessage.clientConnId
+	ClientConnId uint32 `protobuf:"varint,13,opt,name=clientConnId,proto3" json:"clientConnId,omitempty"`
+	// See @MessagePack.rawPayload
+	RawPayload bool `protobuf:"varint,14,opt,name=rawPayload,proto3" json:"rawPayload,omitempty"`
+}
+
+func (x *ArchivedMessage) Reset() {
+	*x = ArchivedMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[50]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ArchivedMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchivedMessage) ProtoMessage() {}
+
+func (x *ArchivedMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[50]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchivedMessage.ProtoReflect.Descriptor instead.
+func (*ArchivedMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ArchivedMessage) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ArchivedMessage) GetChannelId() uint32 {
+	if x != nil {
+		return x.ChannelId
+	}
+	return 0
+}
+
+func (x *ArchivedMessage) GetChannelKey() string {
+	if x != nil {
+		return x.ChannelKey
+	}
+	return ""
+}
+
+func (x *ArchivedMessage) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *ArchivedMessage) GetSenderConnId() uint32 {
+	if x != nil {
+		return x.SenderConnId
+	}
+	return 0
+}
+
+func (x *ArchivedMessage) GetSenderUserId() string {
+	if x != nil {
+		return x.SenderUserId
+	}
+	return ""
+}
+
+func (x *ArchivedMessage) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *ArchivedMessage) GetMsgType() uint32 {
+	if x != nil {
+		return x.MsgType
+	}
+	return 0
+}
+
+func (x *ArchivedMessage) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *ArchivedMessage) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *ArchivedMessage) GetBroadcast() uint32 {
+	if x != nil {
+		return x.Broadcast
+	}
+	return 0
+}
+
+func (x *ArchivedMessage) GetTagFilter() string {
+	if x != nil {
+		return x.TagFilter
+	}
+	return ""
+}
+
+func (x *ArchivedMessage) GetClientConnId() uint32 {
+	if x != nil {
+		return x.ClientConnId
+	}
+	return 0
+}
+
+func (x *ArchivedMessage) GetRawPayload() bool {
+	if x != nil {
+		return x.RawPayload
+	}
+	return false
+}
+
+// Fetches the archived broadcasts of the channel. Only the channel owner and the subscribers can fetch.
+// If the channel has a key, the messages archived under the key in the previous instances of the channel are also fetched.
+// Response: @FetchArchiveResultMessage
+type FetchArchiveMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Optional. The messages archived at or after the time (in unix milliseconds).
+	FromTime int64 `protobuf:"varint,1,opt,name=fromTime,proto3" json:"fromTime,omitempty"`
+	// Optional. The messages archived before the time (in unix milliseconds). 0 means no upper bound.
+	ToTime int64 `protobuf:"varint,2,opt,name=toTime,proto3" json:"toTime,omitempty"`
+	// Optional. The messages with id greater than the value. Use the id of the last fetched message to fetch the next page.
+	AfterId uint64 `protobuf:"varint,3,opt,name=afterId,proto3" json:"afterId,omitempty"`
+	// The max number of the messages to fetch. 0 means the default (20). Up to 100.
+	Limit uint32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *FetchArchiveMessage) Reset() {
+	*x = FetchArchiveMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[51]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetchArchiveMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchArchiveMessage) ProtoMessage() {}
+
+func (x *FetchArchiveMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[51]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchArchiveMessage.ProtoReflect.Descriptor instead.
+func (*FetchArchiveMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *FetchArchiveMessage) GetFromTime() int64 {
+	if x != nil {
+		return x.FromTime
+	}
+	return 0
+}
+
+func (x *FetchArchiveMessage) GetToTime() int64 {
+	if x != nil {
+		return x.ToTime
+	}
+	return 0
+}
+
+func (x *FetchArchiveMessage) GetAfterId() uint64 {
+	if x != nil {
+		return x.AfterId
+	}
+	return 0
+}
+
+func (x *FetchArchiveMessage) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type FetchArchiveResultMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The fetched messages, in the order of being archived.
+	Messages []*ArchivedMessage `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	// True if there are more messages in the range.
+	HasMore bool `protobuf:"varint,2,opt,name=hasMore,proto3" json:"hasMore,omitempty"`
+}
+
+func (x *FetchArchiveResultMessage) Reset() {
+	*x = FetchArchiveResultMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[52]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetchArchiveResultMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchArchiveResultMessage) ProtoMessage() {}
+
+func (x *FetchArchiveResultMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[52]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchArchiveResultMessage.ProtoReflect.Descriptor instead.
+func (*FetchArchiveResultMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *FetchArchiveResultMessage) GetMessages() []*ArchivedMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *FetchArchiveResultMessage) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+// The presence of a user, maintained by channeld when the clients authenticate, subscribe to the channels, and disconnect.
+type PresenceInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserId string `protobuf:"bytes,1,opt,name=userId,proto3" json:"userId,omitempty"`
+	// False if all the client connections of the user have disconnected. The offline user is removed from the presence after the update.
+	Online bool `protobuf:"varint,2,opt,name=online,proto3" json:"online,omitempty"`
+	// The latest authenticated client connection of the user
+	ConnId uint32 `protobuf:"varint,3,opt,name=connId,proto3" json:"connId,omitempty"`
+	// The channel (other than GLOBAL) that the user subscribed to most recently. 0 if the user is not in any channel.
+	ChannelId   uint32      `protobuf:"varint,4,opt,name=channelId,proto3" json:"channelId,omitempty"`
+	ChannelType ChannelType `protobuf:"varint,5,opt,name=channelType,proto3,enum=channeldpb.ChannelType" json:"channelType,omitempty"`
+	// When the presence changed, in unix milliseconds.
+	UpdateTime int64 `protobuf:"varint,6,opt,name=updateTime,proto3" json:"updateTime,omitempty"`
+}
+
+func (x *PresenceInfo) Reset() {
+	*x = PresenceInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[53]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PresenceInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PresenceInfo) ProtoMessage() {}
+
+func (x *PresenceInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[53]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PresenceInfo.ProtoReflect.Descriptor instead.
+func (*PresenceInfo) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *PresenceInfo) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PresenceInfo) GetOnline() bool {
+	if x != nil {
+		return x.Online
+	}
+	return false
+}
+
+func (x *PresenceInfo) GetConnId() uint32 {
+	if x != nil {
+		return x.ConnId
+	}
+	return 0
+}
+
+func (x *PresenceInfo) GetChannelId() uint32 {
+	if x != nil {
+		return x.ChannelId
+	}
+	return 0
+}
+
+func (x *PresenceInfo) GetChannelType() ChannelType {
+	if x != nil {
+		return x.ChannelType
+	}
+	return ChannelType_UNKNOWN
+}
+
+func (x *PresenceInfo) GetUpdateTime() int64 {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return 0
+}
+
+// Sent by channeld to the GLOBAL channel subscribers that have @ChannelSubscriptionOptions.presence enabled.
+type PresenceUpdateMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The changed presence, by the userId.
+	Users map[string]*PresenceInfo `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// True if the message carries the full presence of the online users, rather than the changes.
+	Full bool `protobuf:"varint,2,opt,name=full,proto3" json:"full,omitempty"`
+}
+
+func (x *PresenceUpdateMessage) Reset() {
+	*x = PresenceUpdateMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[54]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PresenceUpdateMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PresenceUpdateMessage) ProtoMessage() {}
+
+func (x *PresenceUpdateMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[54]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PresenceUpdateMessage.ProtoReflect.Descriptor instead.
+func (*PresenceUpdateMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *PresenceUpdateMessage) GetUsers() map[string]*PresenceInfo {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *PresenceUpdateMessage) GetFull() bool {
+	if x != nil {
+		return x.Full
+	}
+	return false
+}
+
+type QueryChannelDataResultMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data *anypb.Any `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	// See @ChannelDataUpdateMessage.version
+	Version uint64 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (x *QueryChannelDataResultMessage) Reset() {
+	*x = QueryChannelDataResultMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[55]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryChannelDataResultMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryChannelDataResultMessage) ProtoMessage() {}
+
+func (x *QueryChannelDataResultMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[55]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryChannelDataResultMessage.ProtoReflect.Descriptor instead.
+func (*QueryChannelDataResultMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *QueryChannelDataResultMessage) GetData() *anypb.Any {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *QueryChannelDataResultMessage) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// Carries the traffic of a client connection between the edge relay (see pkg/relay) and the central channeld,
+// over the single server connection from the edge to the central. The central handles the relayed client as if it's connected directly.
+// Only valid for the server connections.
+type RelayMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The id of the client connection assigned by the edge. Unique in the edge.
+	EdgeConnId uint32             `protobuf:"varint,1,opt,name=edgeConnId,proto3" json:"edgeConnId,omitempty"`
+	Event      RelayMessage_Event `protobuf:"varint,2,opt,name=event,proto3,enum=channeldpb.RelayMessage_Event" json:"event,omitempty"`
+	// DATA: the bytes read from, or to be written to the client connection, in the wire format of channeld.
+	Data []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	// CONNECTED: the remote address of the client, for the IP-based security checks in the central.
+	RemoteAddr string `protobuf:"bytes,4,opt,name=remoteAddr,proto3" json:"remoteAddr,omitempty"`
+}
+
+func (x *RelayMessage) Reset() {
+	*x = RelayMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[56]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RelayMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RelayMessage) ProtoMessage() {}
+
+func (x *RelayMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[56]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RelayMessage.ProtoReflect.Descriptor instead.
+func (*RelayMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *RelayMessage) GetEdgeConnId() uint32 {
+	if x != nil {
+		return x.EdgeConnId
+	}
+	return 0
+}
+
+func (x *RelayMessage) GetEvent() RelayMessage_Event {
+	if x != nil {
+		return x.Event
+	}
+	return RelayMessage_DATA
+}
+
+func (x *RelayMessage) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *RelayMessage) GetRemoteAddr() string {
+	if x != nil {
+		return x.RemoteAddr
+	}
+	return ""
+}
+
+// Registers or cancels a recurring timer on the channel. The timer fires every intervalMs of the channel time,
+// so it stays in sync with the channel's ticks. Only the channel owner can send it.
+// Response: @TimerFiredMessage each time the timer fires, sent to the channel owner. No response while the channel has no owner.
+type ChannelTimerMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Chosen by the owner to identify the timer in the channel. Registering an existing timerId replaces the timer.
+	TimerId uint32 `protobuf:"varint,1,opt,name=timerId,proto3" json:"timerId,omitempty"`
+	// 0 means canceling the timer.
+	IntervalMs uint32 `protobuf:"varint,2,opt,name=intervalMs,proto3" json:"intervalMs,omitempty"`
+}
+
+func (x *ChannelTimerMessage) Reset() {
+	*x = ChannelTimerMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[57]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChannelTimerMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelTimerMessage) ProtoMessage() {}
+
+func (x *ChannelTimerMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[57]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelTimerMessage.ProtoReflect.Descriptor instead.
+func (*ChannelTimerMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ChannelTimerMessage) GetTimerId() uint32 {
+	if x != nil {
+		return x.TimerId
+	}
+	return 0
+}
+
+func (x *ChannelTimerMessage) GetIntervalMs() uint32 {
+	if x != nil {
+		return x.IntervalMs
+	}
+	return 0
+}
+
+type TimerFiredMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TimerId uint32 `protobuf:"varint,1,opt,name=timerId,proto3" json:"timerId,omitempty"`
+	// How many times the timer has fired, starting from 1.
+	Count uint64 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	// The channel time when the timer fires, in milliseconds.
+	ChannelTimeMs int64 `protobuf:"varint,3,opt,name=channelTimeMs,proto3" json:"channelTimeMs,omitempty"`
+}
+
+func (x *TimerFiredMessage) Reset() {
+	*x = TimerFiredMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[58]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TimerFiredMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimerFiredMessage) ProtoMessage() {}
+
+func (x *TimerFiredMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[58]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimerFiredMessage.ProtoReflect.Descriptor instead.
+func (*TimerFiredMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *TimerFiredMessage) GetTimerId() uint32 {
+	if x != nil {
+		return x.TimerId
+	}
+	return 0
+}
+
+func (x *TimerFiredMessage) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *TimerFiredMessage) GetChannelTimeMs() int64 {
+	if x != nil {
+		return x.ChannelTimeMs
+	}
+	return 0
+}
+
+// Sent by channeld to the client connection that has been idle for GlobalSettings.ConnectionIdleTimeoutMs.
+// The client should reply with the same message, or any other message, in GlobalSettings.ConnectionIdlePingTimeoutMs,
+// otherwise it's disconnected with @DisconnectMessage.Reason.IDLE_TIMEOUT.
+// The message should have channelId = 0.
+type PingMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The time when channeld sends the message, in unix milliseconds.
+	ServerTimeMs int64 `protobuf:"varint,1,opt,name=serverTimeMs,proto3" json:"serverTimeMs,omitempty"`
+}
+
+func (x *PingMessage) Reset() {
+	*x = PingMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[59]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PingMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingMessage) ProtoMessage() {}
+
+func (x *PingMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[59]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingMessage.ProtoReflect.Descriptor instead.
+func (*PingMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *PingMessage) GetServerTimeMs() int64 {
+	if x != nil {
+		return x.ServerTimeMs
+	}
+	return 0
+}
+
+// Disconnect another connection from channeld.
+// This message should only be sent by the server connection in a server-authoratative environment.
+// The message should have channelId = 0 in order to be handled.
+// Response: no.
+// channeld also sends the message (best-effort) to the connection before closing it, and to the owner of each channel
+// that the closed connection subscribed to, with channelId = the channel's id.
+type DisconnectMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ConnId uint32 `protobuf:"varint,1,opt,name=connId,proto3" json:"connId,omitempty"`
+	// Set by the server to tell the client why it's disconnected. KICKED if not set.
+	Reason DisconnectMessage_Reason `protobuf:"varint,2,opt,name=reason,proto3,enum=channeldpb.DisconnectMessage_Reason" json:"reason,omitempty"`
+	// The human-readable detail of the reason. Optional.
+	Detail string `protobuf:"bytes,3,opt,name=detail,proto3" json:"detail,omitempty"`
+}
+
+func (x *DisconnectMessage) Reset() {
+	*x = DisconnectMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[60]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DisconnectMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisconnectMessage) ProtoMessage() {}
+
+func (x *DisconnectMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[60]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisconnectMessage.ProtoReflect.Descriptor instead.
+func (*DisconnectMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *DisconnectMessage) GetConnId() uint32 {
+	if x != nil {
+		return x.ConnId
+	}
+	return 0
+}
+
+func (x *DisconnectMessage) GetReason() DisconnectMessage_Reason {
+	if x != nil {
+		return x.Reason
+	}
+	return DisconnectMessage_UNSPECIFIED
+}
+
+func (x *DisconnectMessage) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+// Left-handed coordinate system with Y-up rule.
+type SpatialInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	X float64 `protobuf:"fixed64,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y float64 `protobuf:"fixed64,2,opt,name=y,proto3" json:"y,omitempty"`
+	Z float64 `protobuf:"fixed64,3,opt,name=z,proto3" json:"z,omitempty"`
+}
+
+func (x *SpatialInfo) Reset() {
+	*x = SpatialInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[61]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SpatialInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpatialInfo) ProtoMessage() {}
+
+func (x *SpatialInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[61]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpatialInfo.ProtoReflect.Descriptor instead.
+func (*SpatialInfo) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *SpatialInfo) GetX() float64 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *SpatialInfo) GetY() float64 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *SpatialInfo) GetZ() float64 {
+	if x != nil {
+		return x.Z
+	}
+	return 0
+}
+
+type CreateSpatialChannelsResultMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SpatialChannelId []uint32 `protobuf:"varint,1,rep,packed,name=spatialChannelId,proto3" json:"spatialChannelId,omitempty"`
+	Metadata         string   `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	OwnerConnId      uint32   `protobuf:"varint,3,opt,name=ownerConnId,proto3" json:"ownerConnId,omitempty"`
+}
+
+func (x *CreateSpatialChannelsResultMessage) Reset() {
+	*x = CreateSpatialChannelsResultMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[62]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateSpatialChannelsResultMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSpatialChannelsResultMessage) ProtoMessage() {}
+
+func (x *CreateSpatialChannelsResultMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[62]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSpatialChannelsResultMessage.ProtoReflect.Descriptor instead.
+func (*CreateSpatialChannelsResultMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *CreateSpatialChannelsResultMessage) GetSpatialChannelId() []uint32 {
+	if x != nil {
+		return x.SpatialChannelId
+	}
+	return nil
+}
+
+func (x *CreateSpatialChannelsResultMessage) GetMetadata() string {
+	if x != nil {
+		return x.Metadata
+	}
+	return ""
+}
+
+func (x *CreateSpatialChannelsResultMessage) GetOwnerConnId() uint32 {
+	if x != nil {
+		return x.OwnerConnId
+	}
+	return 0
+}
+
+// The message should have channelId = 0 in order to be handled.
+// Response: @QuerySpatialChannelResultMessage
+type QuerySpatialChannelMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SpatialInfo []*SpatialInfo `protobuf:"bytes,1,rep,name=spatialInfo,proto3" json:"spatialInfo,omitempty"`
+}
+
+func (x *QuerySpatialChannelMessage) Reset() {
+	*x = QuerySpatialChannelMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[63]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QuerySpatialChannelMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuerySpatialChannelMessage) ProtoMessage() {}
+
+func (x *QuerySpatialChannelMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[63]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuerySpatialChannelMessage.ProtoReflect.Descriptor instead.
+func (*QuerySpatialChannelMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *QuerySpatialChannelMessage) GetSpatialInfo() []*SpatialInfo {
+	if x != nil {
+		return x.SpatialInfo
+	}
+	return nil
+}
+
+type QuerySpatialChannelResultMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChannelId []uint32 `protobuf:"varint,1,rep,packed,name=channelId,proto3" json:"channelId,omitempty"`
+}
+
+func (x *QuerySpatialChannelResultMessage) Reset() {
+	*x = QuerySpatialChannelResultMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[64]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QuerySpatialChannelResultMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuerySpatialChannelResultMessage) ProtoMessage() {}
+
+func (x *QuerySpatialChannelResultMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[64]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuerySpatialChannelResultMessage.ProtoReflect.Descriptor instead.
+func (*QuerySpatialChannelResultMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *QuerySpatialChannelResultMessage) GetChannelId() []uint32 {
+	if x != nil {
+		return x.ChannelId
+	}
+	return nil
+}
+
+// ALL connections in the source AND destination channels receive this messge when a handover happpned.
+// Handover means an object moves from a spatial channel to another. It doesn't necessarily mean the objece moves from a spatial server to another.
+type ChannelDataHandoverMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SrcChannelId uint32 `protobuf:"varint,1,opt,name=srcChannelId,proto3" json:"srcChannelId,omitempty"`
+	DstChannelId uint32 `protobuf:"varint,2,opt,name=dstChannelId,proto3" json:"dstChannelId,omitempty"`
+	// The ID of the client connection that triggered the handover. If the handover is triggered by server (e.g. NPC movement), the value will be 0.
+	ContextConnId uint32 `protobuf:"varint,3,opt,name=contextConnId,proto3" json:"contextConnId,omitempty"`
+	// The data that migrate from the source channel to the destination channel. It can be the spatial channel data or anything, as long as the spatial servers can use it to process the handover.
+	Data *anypb.Any `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *ChannelDataHandoverMessage) Reset() {
+	*x = ChannelDataHandoverMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[65]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChannelDataHandoverMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelDataHandoverMessage) ProtoMessage() {}
+
+func (x *ChannelDataHandoverMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[65]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelDataHandoverMessage.ProtoReflect.Descriptor instead.
+func (*ChannelDataHandoverMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *ChannelDataHandoverMessage) GetSrcChannelId() uint32 {
+	if x != nil {
+		return x.SrcChannelId
+	}
+	return 0
+}
+
+func (x *ChannelDataHandoverMessage) GetDstChannelId() uint32 {
+	if x != nil {
+		return x.DstChannelId
+	}
+	return 0
+}
+
+func (x *ChannelDataHandoverMessage) GetContextConnId() uint32 {
+	if x != nil {
+		return x.ContextConnId
+	}
+	return 0
+}
+
+func (x *ChannelDataHandoverMessage) GetData() *anypb.Any {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type SpatialRegion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Min         *SpatialInfo `protobuf:"bytes,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max         *SpatialInfo `protobuf:"bytes,2,opt,name=max,proto3" json:"max,omitempty"`
+	ChannelId   uint32       `protobuf:"varint,3,opt,name=channelId,proto3" json:"channelId,omitempty"`
+	ServerIndex uint32       `protobuf:"varint,4,opt,name=serverIndex,proto3" json:"serverIndex,omitempty"`
+}
+
+func (x *SpatialRegion) Reset() {
+	*x = SpatialRegion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[66]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SpatialRegion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpatialRegion) ProtoMessage() {}
+
+func (x *SpatialRegion) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[66]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpatialRegion.ProtoReflect.Descriptor instead.
+func (*SpatialRegion) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *SpatialRegion) GetMin() *SpatialInfo {
+	if x != nil {
+		return x.Min
+	}
+	return nil
+}
+
+func (x *SpatialRegion) GetMax() *SpatialInfo {
+	if x != nil {
+		return x.Max
+	}
+	return nil
+}
+
+func (x *SpatialRegion) GetChannelId() uint32 {
+	if x != nil {
+		return x.ChannelId
+	}
+	return 0
+}
+
+func (x *SpatialRegion) GetServerIndex() uint32 {
+	if x != nil {
+		return x.ServerIndex
+	}
+	return 0
+}
+
+// channeld updates the information of spatial channels and regions to the spatial servers.
+// Spatial servers use this information mainly for mapping the position of a spawned object to a correct channelId at realtime (rather than querying it before sending the spawn message to the client).
+// Sent upon the creation of spatial channels (after @CreateSpatialChannelsResultMessage being sent), or any regional change (basiclally caused by the loadbalancer).
+type SpatialRegionsUpdateMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Regions []*SpatialRegion `protobuf:"bytes,1,rep,name=regions,proto3" json:"regions,omitempty"`
+}
+
+func (x *SpatialRegionsUpdateMessage) Reset() {
+	*x = SpatialRegionsUpdateMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[67]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SpatialRegionsUpdateMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpatialRegionsUpdateMessage) ProtoMessage() {}
+
+func (x *SpatialRegionsUpdateMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[67]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpatialRegionsUpdateMessage.ProtoReflect.Descriptor instead.
+func (*SpatialRegionsUpdateMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *SpatialRegionsUpdateMessage) GetRegions() []*SpatialRegion {
+	if x != nil {
+		return x.Regions
+	}
+	return nil
+}
+
+type SpatialInterestQuery struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SpotsAOI  *SpatialInterestQuery_SpotsAOI  `protobuf:"bytes,1,opt,name=spotsAOI,proto3,oneof" json:"spotsAOI,omitempty"`
+	BoxAOI    *SpatialInterestQuery_BoxAOI    `protobuf:"bytes,2,opt,name=boxAOI,proto3,oneof" json:"boxAOI,omitempty"`
+	SphereAOI *SpatialInterestQuery_SphereAOI `protobuf:"bytes,3,opt,name=sphereAOI,proto3,oneof" json:"sphereAOI,omitempty"`
+	ConeAOI   *SpatialInterestQuery_ConeAOI   `protobuf:"bytes,4,opt,name=coneAOI,proto3,oneof" json:"coneAOI,omitempty"`
+}
+
+func (x *SpatialInterestQuery) Reset() {
+	*x = SpatialInterestQuery{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[68]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SpatialInterestQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpatialInterestQuery) ProtoMessage() {}
+
+func (x *SpatialInterestQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[68]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpatialInterestQuery.ProtoReflect.Descriptor instead.
+func (*SpatialInterestQuery) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *SpatialInterestQuery) GetSpotsAOI() *SpatialInterestQuery_SpotsAOI {
+	if x != nil {
+		return x.SpotsAOI
+	}
+	return nil
+}
+
+func (x *SpatialInterestQuery) GetBoxAOI() *SpatialInterestQuery_BoxAOI {
+	if x != nil {
+		return x.BoxAOI
+	}
+	return nil
+}
+
+func (x *SpatialInterestQuery) GetSphereAOI() *SpatialInterestQuery_SphereAOI {
+	if x != nil {
+		return x.SphereAOI
+	}
+	return nil
+}
+
+func (x *SpatialInterestQuery) GetConeAOI() *SpatialInterestQuery_ConeAOI {
+	if x != nil {
+		return x.ConeAOI
+	}
+	return nil
+}
+
+type UpdateSpatialInterestMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ConnId uint32                `protobuf:"varint,1,opt,name=connId,proto3" json:"connId,omitempty"`
+	Query  *SpatialInterestQuery `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+}
+
+func (x *UpdateSpatialInterestMessage) Reset() {
+	*x = UpdateSpatialInterestMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[69]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateSpatialInterestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSpatialInterestMessage) ProtoMessage() {}
+
+func (x *UpdateSpatialInterestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[69]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSpatialInterestMessage.ProtoReflect.Descriptor instead.
+func (*UpdateSpatialInterestMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *UpdateSpatialInterestMessage) GetConnId() uint32 {
+	if x != nil {
+		return x.ConnId
+	}
+	return 0
+}
+
+func (x *UpdateSpatialInterestMessage) GetQuery() *SpatialInterestQuery {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+type CreateEntityChannelMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	EntityId     uint32                      `protobuf:"varint,1,opt,name=entityId,proto3" json:"entityId,omitempty"`
+	Metadata     string                      `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	SubOptions   *ChannelSubscriptionOptions `protobuf:"bytes,3,opt,name=subOptions,proto3" json:"subOptions,omitempty"`
+	Data         *anypb.Any                  `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	MergeOptions *ChannelDataMergeOptions    `protobuf:"bytes,5,opt,name=mergeOptions,proto3" json:"mergeOptions,omitempty"`
+	IsWellKnown  bool                        `protobuf:"varint,6,opt,name=isWellKnown,proto3" json:"isWellKnown,omitempty"`
+	// See @CreateChannelMessage.dataSchemaVersion.
+	DataSchemaVersion uint32 `protobuf:"varint,7,opt,name=dataSchemaVersion,proto3" json:"dataSchemaVersion,omitempty"`
+}
+
+func (x *CreateEntityChannelMessage) Reset() {
+	*x = CreateEntityChannelMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[70]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateEntityChannelMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEntityChannelMessage) ProtoMessage() {}
+
+func (x *CreateEntityChannelMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[70]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEntityChannelMessage.ProtoReflect.Descriptor instead.
+func (*CreateEntityChannelMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *CreateEntityChannelMessage) GetEntityId() uint32 {
+	if x != nil {
+		return x.EntityId
+	}
+	return 0
+}
+
+func (x *CreateEntityChannelMessage) GetMetadata() string {
+	if x != nil {
+		return x.Metadata
+	}
+	return ""
+}
+
+func (x *CreateEntityChannelMessage) GetSubOptions() *ChannelSubscriptionOptions {
+	if x != nil {
+		return x.SubOptions
+	}
+	return nil
+}
+
+func (x *CreateEntityChannelMessage) GetData() *anypb.Any {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *CreateEntityChannelMessage) GetMergeOptions() *ChannelDataMergeOptions {
+	if x != nil {
+		return x.MergeOptions
+	}
+	return nil
+}
+
+func (x *CreateEntityChannelMessage) GetIsWellKnown() bool {
+	if x != nil {
+		return x.IsWellKnown
+	}
+	return false
+}
+
+func (x *CreateEntityChannelMessage) GetDataSchemaVersion() uint32 {
+	if x != nil {
+		return x.DataSchemaVersion
+	}
+	return 0
+}
+
+// Add specified entities to the handover/lock group of the entity channel. Should sent by the entity channel owner.
+type AddEntityGroupMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type          EntityGroupType `protobuf:"varint,1,opt,name=type,proto3,enum=channeldpb.EntityGroupType" json:"type,omitempty"`
+	EntitiesToAdd []uint32        `protobuf:"varint,2,rep,packed,name=EntitiesToAdd,proto3" json:"EntitiesToAdd,omitempty"`
+}
+
+func (x *AddEntityGroupMessage) Reset() {
+	*x = AddEntityGroupMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[71]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddEntityGroupMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddEntityGroupMessage) ProtoMessage() {}
+
+func (x *AddEntityGroupMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[71]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddEntityGroupMessage.ProtoReflect.Descriptor instead.
+func (*AddEntityGroupMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *AddEntityGroupMessage) GetType() EntityGroupType {
+	if x != nil {
+		return x.Type
+	}
+	return EntityGroupType_HANDOVER
+}
+
+func (x *AddEntityGroupMessage) GetEntitiesToAdd() []uint32 {
+	if x != nil {
+		return x.EntitiesToAdd
+	}
+	return nil
+}
+
+// Remove specified entities from the handover/lock group of the entity channel. Should sent by the entity channel owner.
+type RemoveEntityGroupMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type             EntityGroupType `protobuf:"varint,1,opt,name=type,proto3,enum=channeldpb.EntityGroupType" json:"type,omitempty"`
+	EntitiesToRemove []uint32        `protobuf:"varint,2,rep,packed,name=EntitiesToRemove,proto3" json:"EntitiesToRemove,omitempty"`
+}
+
+func (x *RemoveEntityGroupMessage) Reset() {
+	*x = RemoveEntityGroupMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[72]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RemoveEntityGroupMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveEntityGroupMessage) ProtoMessage() {}
+
+func (x *RemoveEntityGroupMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[72]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveEntityGroupMessage.ProtoReflect.Descriptor instead.
+func (*RemoveEntityGroupMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *RemoveEntityGroupMessage) GetType() EntityGroupType {
+	if x != nil {
+		return x.Type
+	}
+	return EntityGroupType_HANDOVER
+}
+
+func (x *RemoveEntityGroupMessage) GetEntitiesToRemove() []uint32 {
+	if x != nil {
+		return x.EntitiesToRemove
+	}
+	return nil
+}
+
+// Client requests the spatail regions information. Only valid in Development mode (with "-dev" launch argument).
+// Response: @SpatialRegionsUpdateMessage
+type DebugGetSpatialRegionsMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DebugGetSpatialRegionsMessage) Reset() {
+	*x = DebugGetSpatialRegionsMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[73]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DebugGetSpatialRegionsMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebugGetSpatialRegionsMessage) ProtoMessage() {}
+
+func (x *DebugGetSpatialRegionsMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[73]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebugGetSpatialRegionsMessage.ProtoReflect.Descriptor instead.
+func (*DebugGetSpatialRegionsMessage) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{73}
+}
+
+type ListChannelResultMessage_ChannelInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChannelId   uint32      `protobuf:"varint,1,opt,name=channelId,proto3" json:"channelId,omitempty"`
+	ChannelType ChannelType `protobuf:"varint,2,opt,name=channelType,proto3,enum=channeldpb.ChannelType" json:"channelType,omitempty"`
+	Metadata    string      `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	ChannelKey  string      `protobuf:"bytes,4,opt,name=channelKey,proto3" json:"channelKey,omitempty"`
+}
+
+func (x *ListChannelResultMessage_ChannelInfo) Reset() {
+	*x = ListChannelResultMessage_ChannelInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[74]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListChannelResultMessage_ChannelInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChannelResultMessage_ChannelInfo) ProtoMessage() {}
+
+func (x *ListChannelResultMessage_ChannelInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[74]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChannelResultMessage_ChannelInfo.ProtoReflect.Descriptor instead.
+func (*ListChannelResultMessage_ChannelInfo) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{12, 0}
+}
+
+func (x *ListChannelResultMessage_ChannelInfo) GetChannelId() uint32 {
+	if x != nil {
+		return x.ChannelId
+	}
+	return 0
+}
+
+func (x *ListChannelResultMessage_ChannelInfo) GetChannelType() ChannelType {
+	if x != nil {
+		return x.ChannelType
+	}
+	return ChannelType_UNKNOWN
+}
+
+func (x *ListChannelResultMessage_ChannelInfo) GetMetadata() string {
+	if x != nil {
+		return x.Metadata
+	}
+	return ""
+}
+
+func (x *ListChannelResultMessage_ChannelInfo) GetChannelKey() string {
+	if x != nil {
+		return x.ChannelKey
+	}
+	return ""
+}
+
+type ServerBatchMessage_Entry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChannelId uint32 `protobuf:"varint,1,opt,name=channelId,proto3" json:"channelId,omitempty"`
+	// Used instead of channelId if not empty. See MessagePack.channelKey.
+	ChannelKey string `protobuf:"bytes,2,opt,name=channelKey,proto3" json:"channelKey,omitempty"`
+	Broadcast  uint32 `protobuf:"varint,3,opt,name=broadcast,proto3" json:"broadcast,omitempty"`
+	// For the response of the entry. 0 means no response.
+	StubId uint32 `protobuf:"varint,4,opt,name=stubId,proto3" json:"stubId,omitempty"`
+	// The marshalled message. If dataTypeUrl is set, the marshalled channel data message instead of the ChannelDataUpdateMessage.
+	MsgBody []byte `protobuf:"bytes,5,opt,name=msgBody,proto3" json:"msgBody,omitempty"`
+}
+
+func (x *ServerBatchMessage_Entry) Reset() {
+	*x = ServerBatchMessage_Entry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[79]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServerBatchMessage_Entry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerBatchMessage_Entry) ProtoMessage() {}
+
+func (x *ServerBatchMessage_Entry) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[79]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerBatchMessage_Entry.ProtoReflect.Descriptor instead.
+func (*ServerBatchMessage_Entry) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{34, 0}
+}
+
+func (x *ServerBatchMessage_Entry) GetChannelId() uint32 {
+	if x != nil {
+		return x.ChannelId
+	}
+	return 0
+}
+
+func (x *ServerBatchMessage_Entry) GetChannelKey() string {
+	if x != nil {
+		return x.ChannelKey
+	}
+	return ""
+}
+
+func (x *ServerBatchMessage_Entry) GetBroadcast() uint32 {
+	if x != nil {
+		return x.Broadcast
+	}
+	return 0
+}
+
+func (x *ServerBatchMessage_Entry) GetStubId() uint32 {
+	if x != nil {
+		return x.StubId
+	}
+	return 0
+}
+
+func (x *ServerBatchMessage_Entry) GetMsgBody() []byte {
+	if x != nil {
+		return x.MsgBody
+	}
+	return nil
+}
+
+type ChannelDataTransactionMessage_Mutation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Op ChannelDataTransactionMessage_Mutation_Op `protobuf:"varint,1,opt,name=op,proto3,enum=channeldpb.ChannelDataTransactionMessage_Mutation_Op" json:"op,omitempty"`
+	// The name of the top-level map field in the channel data, e.g. 'inventory'
+	Field string `protobuf:"bytes,2,opt,name=field,proto3" json:"field,omitempty"`
+	// The key of the map entry. The integer keys are in the decimal format.
+	Key string `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	// PUT: the channel data message that has the map entry set.
+	Data *anypb.Any `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	// PUT: if true, fails if the entry already exists.
+	IfAbsent bool `protobuf:"varint,5,opt,name=ifAbsent,proto3" json:"ifAbsent,omitempty"`
+	// INCREMENT: the name of the numeric field in the map value message. Empty if the map value itself is numeric.
+	ValueField string `protobuf:"bytes,6,opt,name=valueField,proto3" json:"valueField,omitempty"`
+	Delta      int64  `protobuf:"varint,7,opt,name=delta,proto3" json:"delta,omitempty"`
+	// INCREMENT: fails if the value would be out of the range, e.g. min = 0 for the currency that can't be overdrawn.
+	Min *int64 `protobuf:"varint,8,opt,name=min,proto3,oneof" json:"min,omitempty"`
+	Max *int64 `protobuf:"varint,9,opt,name=max,proto3,oneof" json:"max,omitempty"`
+}
+
+func (x *ChannelDataTransactionMessage_Mutation) Reset() {
+	*x = ChannelDataTransactionMessage_Mutation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[80]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChannelDataTransactionMessage_Mutation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelDataTransactionMessage_Mutation) ProtoMessage() {}
+
+func (x *ChannelDataTransactionMessage_Mutation) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[80]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelDataTransactionMessage_Mutation.ProtoReflect.Descriptor instead.
+func (*ChannelDataTransactionMessage_Mutation) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{44, 0}
+}
+
+func (x *ChannelDataTransactionMessage_Mutation) GetOp() ChannelDataTransactionMessage_Mutation_Op {
+	if x != nil {
+		return x.Op
+	}
+	return ChannelDataTransactionMessage_Mutation_PUT
+}
+
+func (x *ChannelDataTransactionMessage_Mutation) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *ChannelDataTransactionMessage_Mutation) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ChannelDataTransactionMessage_Mutation) GetData() *anypb.Any {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ChannelDataTransactionMessage_Mutation) GetIfAbsent() bool {
+	if x != nil {
+		return x.IfAbsent
+	}
+	return false
+}
+
+func (x *ChannelDataTransactionMessage_Mutation) GetValueField() string {
+	if x != nil {
+		return x.ValueField
+	}
+	return ""
+}
+
+func (x *ChannelDataTransactionMessage_Mutation) GetDelta() int64 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+func (x *ChannelDataTransactionMessage_Mutation) GetMin() int64 {
+	if x != nil && x.Min != nil {
+		return *x.Min
+	}
+	return 0
+}
+
+func (x *ChannelDataTransactionMessage_Mutation) GetMax() int64 {
+	if x != nil && x.Max != nil {
+		return *x.Max
+	}
+	return 0
+}
+
+type SpatialInterestQuery_SpotsAOI struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Spots []*SpatialInfo `protobuf:"bytes,1,rep,name=spots,proto3" json:"spots,omitempty"`
+	// The fixed distance between each spot and the player. If not specified, 0 = nearest will be used.
+	Dists []uint32 `protobuf:"varint,2,rep,packed,name=dists,proto3" json:"dists,omitempty"`
+}
+
+func (x *SpatialInterestQuery_SpotsAOI) Reset() {
+	*x = SpatialInterestQuery_SpotsAOI{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[82]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SpatialInterestQuery_SpotsAOI) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpatialInterestQuery_SpotsAOI) ProtoMessage() {}
+
+func (x *SpatialInterestQuery_SpotsAOI) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[82]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpatialInterestQuery_SpotsAOI.ProtoReflect.Descriptor instead.
+func (*SpatialInterestQuery_SpotsAOI) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{68, 0}
+}
+
+func (x *SpatialInterestQuery_SpotsAOI) GetSpots() []*SpatialInfo {
+	if x != nil {
+		return x.Spots
+	}
+	return nil
+}
+
+func (x *SpatialInterestQuery_SpotsAOI) GetDists() []uint32 {
+	if x != nil {
+		return x.Dists
+	}
+	return nil
+}
+
+type SpatialInterestQuery_BoxAOI struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Center *SpatialInfo `protobuf:"bytes,1,opt,name=center,proto3" json:"center,omitempty"`
+	Extent *SpatialInfo `protobuf:"bytes,2,opt,name=extent,proto3" json:"extent,omitempty"`
+}
+
+func (x *SpatialInterestQuery_BoxAOI) Reset() {
+	*x = SpatialInterestQuery_BoxAOI{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[83]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SpatialInterestQuery_BoxAOI) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpatialInterestQuery_BoxAOI) ProtoMessage() {}
+
+func (x *SpatialInterestQuery_BoxAOI) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[83]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpatialInterestQuery_BoxAOI.ProtoReflect.Descriptor instead.
+func (*SpatialInterestQuery_BoxAOI) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{68, 1}
+}
+
+func (x *SpatialInterestQuery_BoxAOI) GetCenter() *SpatialInfo {
+	if x != nil {
+		return x.Center
+	}
+	return nil
+}
+
+func (x *SpatialInterestQuery_BoxAOI) GetExtent() *SpatialInfo {
+	if x != nil {
+		return x.Extent
+	}
+	return nil
+}
+
+type SpatialInterestQuery_SphereAOI struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Center *SpatialInfo `protobuf:"bytes,1,opt,name=center,proto3" json:"center,omitempty"`
+	Radius float64      `protobuf:"fixed64,2,opt,name=radius,proto3" json:"radius,omitempty"`
+}
+
+func (x *SpatialInterestQuery_SphereAOI) Reset() {
+	*x = SpatialInterestQuery_SphereAOI{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[84]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SpatialInterestQuery_SphereAOI) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpatialInterestQuery_SphereAOI) ProtoMessage() {}
+
+func (x *SpatialInterestQuery_SphereAOI) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[84]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpatialInterestQuery_SphereAOI.ProtoReflect.Descriptor instead.
+func (*SpatialInterestQuery_SphereAOI) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{68, 2}
+}
+
+func (x *SpatialInterestQuery_SphereAOI) GetCenter() *SpatialInfo {
+	if x != nil {
+		return x.Center
+	}
+	return nil
+}
+
+func (x *SpatialInterestQuery_SphereAOI) GetRadius() float64 {
+	if x != nil {
+		return x.Radius
+	}
+	return 0
+}
+
+type SpatialInterestQuery_ConeAOI struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Center    *SpatialInfo `protobuf:"bytes,1,opt,name=center,proto3" json:"center,omitempty"`
+	Direction *SpatialInfo `protobuf:"bytes,2,opt,name=direction,proto3" json:"direction,omitempty"`
+	// In radians.
+	Angle  float64 `protobuf:"fixed64,3,opt,name=angle,proto3" json:"angle,omitempty"`
+	Radius float64 `protobuf:"fixed64,4,opt,name=radius,proto3" json:"radius,omitempty"`
+}
+
+func (x *SpatialInterestQuery_ConeAOI) Reset() {
+	*x = SpatialInterestQuery_ConeAOI{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_channeld_proto_msgTypes[85]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SpatialInterestQuery_ConeAOI) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpatialInterestQuery_ConeAOI) ProtoMessage() {}
+
+func (x *SpatialInterestQuery_ConeAOI) ProtoReflect() protoreflect.Message {
+	mi := &file_channeld_proto_msgTypes[85]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpatialInterestQuery_ConeAOI.ProtoReflect.Descriptor instead.
+func (*SpatialInterestQuery_ConeAOI) Descriptor() ([]byte, []int) {
+	return file_channeld_proto_rawDescGZIP(), []int{68, 3}
+}
+
+func (x *SpatialInterestQuery_ConeAOI) GetCenter() *SpatialInfo {
+	if x != nil {
+		return x.Center
+	}
+	return nil
+}
+
+func (x *SpatialInterestQuery_ConeAOI) GetDirection() *SpatialInfo {
+	if x != nil {
+		return x.Direction
+	}
+	return nil
+}
+
+func (x *SpatialInterestQuery_ConeAOI) GetAngle() float64 {
+	if x != nil {
+		return x.Angle
+	}
+	return 0
+}
+
+func (x *SpatialInterestQuery_ConeAOI) GetRadius() float64 {
+	if x != nil {
+		return x.Radius
+	}
+	return 0
+}
+
+var File_channeld_proto protoreflect.FileDescriptor
+
+var file_channeld_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x1a, 0x19, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x61, 0x6e,
+	0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x3d, 0x0a, 0x06, 0x50, 0x61, 0x63, 0x6b, 0x65,
+	0x74, 0x12, 0x33, 0x0a, 0x08, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62,
+	0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x50, 0x61, 0x63, 0x6b, 0x52, 0x08, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x22, 0xb1, 0x02, 0x0a, 0x0b, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x50, 0x61, 0x63, 0x6b, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x62, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x62, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61,
+	0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x75, 0x62, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x06, 0x73, 0x74, 0x75, 0x62, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x73,
+	0x67, 0x54, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x6d, 0x73, 0x67,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x73, 0x67, 0x42, 0x6f, 0x64, 0x79, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x6d, 0x73, 0x67, 0x42, 0x6f, 0x64, 0x79, 0x12, 0x1e,
+	0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4b, 0x65, 0x79, 0x12, 0x1e,
+	0x0a, 0x0a, 0x72, 0x61, 0x77, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0a, 0x72, 0x61, 0x77, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x22,
+	0x0a, 0x0c, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x6e,
+	0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x74, 0x69, 0x6e, 0x75, 0x65, 0x64, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x74, 0x69, 0x6e, 0x75, 0x65, 0x64,
+	0x12, 0x18, 0x0a, 0x07, 0x74, 0x72, 0x61, 0x63, 0x65, 0x49, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x74, 0x72, 0x61, 0x63, 0x65, 0x49, 0x64, 0x22, 0x54, 0x0a, 0x0d, 0x42, 0x72,
+	0x6f, 0x6b, 0x65, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f,
+	0x72, 0x69, 0x67, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x72, 0x69,
+	0x67, 0x69, 0x6e, 0x12, 0x2b, 0x0a, 0x04, 0x70, 0x61, 0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x50, 0x61, 0x63, 0x6b, 0x52, 0x04, 0x70, 0x61, 0x63, 0x6b,
+	0x22, 0x9e, 0x01, 0x0a, 0x14, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x46, 0x6f, 0x72, 0x77, 0x61,
+	0x72, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0c, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x18, 0x0a,
+	0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07,
+	0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x73, 0x65, 0x71, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x61, 0x67,
+	0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x61,
+	0x67, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x6c, 0x61, 0x79,
+	0x4d, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x64, 0x65, 0x6c, 0x61, 0x79, 0x4d,
+	0x73, 0x22, 0xd1, 0x02, 0x0a, 0x0b, 0x41, 0x75, 0x74, 0x68, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x34, 0x0a, 0x15, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x66, 0x69, 0x65, 0x72, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x15, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69,
+	0x65, 0x72, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x6c, 0x6f, 0x67, 0x69, 0x6e,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6c, 0x6f, 0x67,
+	0x69, 0x6e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x48, 0x0a, 0x10, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x43, 0x6f, 0x64, 0x65, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x1c, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x43, 0x6f, 0x64, 0x65, 0x63, 0x52,
+	0x10, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x43, 0x6f, 0x64, 0x65,
+	0x63, 0x12, 0x28, 0x0a, 0x0f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x63, 0x6f, 0x6c, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x72,
+	0x65, 0x73, 0x75, 0x6d, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x24, 0x0a,
+	0x0d, 0x6d, 0x61, 0x78, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x6d, 0x61, 0x78, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x53,
+	0x69, 0x7a, 0x65, 0x12, 0x30, 0x0a, 0x13, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x13, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x75, 0x62, 0x6c,
+	0x69, 0x63, 0x4b, 0x65, 0x79, 0x22, 0xa2, 0x04, 0x0a, 0x11, 0x41, 0x75, 0x74, 0x68, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x40, 0x0a, 0x06, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x28, 0x2e, 0x63, 0x68,
+	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x63,
+	0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x45, 0x0a, 0x0f, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b,
+	0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x6f, 0x6d, 0x70,
+	0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0f, 0x63, 0x6f, 0x6d,
+	0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x48, 0x0a, 0x10,
+	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x43, 0x6f, 0x64, 0x65, 0x63,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1c, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x64, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x43,
+	0x6f, 0x64, 0x65, 0x63, 0x52, 0x10, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74,
+	0x61, 0x43, 0x6f, 0x64, 0x65, 0x63, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x28,
+	0x0a, 0x0f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
+	0x6c, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x72, 0x65, 0x73, 0x75,
+	0x6d, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72,
+	0x65, 0x73, 0x75, 0x6d, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x24, 0x0a, 0x0d, 0x6d, 0x61,
+	0x78, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0d, 0x6d, 0x61, 0x78, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x53, 0x69, 0x7a, 0x65,
+	0x12, 0x30, 0x0a, 0x13, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x75,
+	0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x13, 0x65,
+	0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b,
+	0x65, 0x79, 0x22, 0x66, 0x0a, 0x0a, 0x41, 0x75, 0x74, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x12, 0x0e, 0x0a, 0x0a, 0x53, 0x55, 0x43, 0x43, 0x45, 0x53, 0x53, 0x46, 0x55, 0x4c, 0x10, 0x00,
+	0x12, 0x0f, 0x0a, 0x0b, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x50, 0x49, 0x54, 0x10,
+	0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x4c, 0x54, 0x10,
+	0x02, 0x12, 0x13, 0x0a, 0x0f, 0x44, 0x55, 0x50, 0x4c, 0x49, 0x43, 0x41, 0x54, 0x45, 0x5f, 0x4c,
+	0x4f, 0x47, 0x49, 0x4e, 0x10, 0x03, 0x12, 0x12, 0x0a, 0x0e, 0x51, 0x55, 0x4f, 0x54, 0x41, 0x5f,
+	0x45, 0x58, 0x43, 0x45, 0x45, 0x44, 0x45, 0x44, 0x10, 0x04, 0x22, 0xf4, 0x07, 0x0a, 0x1a, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x42, 0x0a, 0x0a, 0x64, 0x61, 0x74,
+	0x61, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e,
+	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x48, 0x00, 0x52, 0x0a,
+	0x64, 0x61, 0x74, 0x61, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a,
+	0x0e, 0x64, 0x61, 0x74, 0x61, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4d, 0x61, 0x73, 0x6b, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x64, 0x61, 0x74, 0x61, 0x46, 0x69, 0x65, 0x6c, 0x64,
+	0x4d, 0x61, 0x73, 0x6b, 0x73, 0x12, 0x2f, 0x0a, 0x10, 0x66, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x49,
+	0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x48,
+	0x01, 0x52, 0x10, 0x66, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61,
+	0x6c, 0x4d, 0x73, 0x88, 0x01, 0x01, 0x12, 0x29, 0x0a, 0x0d, 0x66, 0x61, 0x6e, 0x4f, 0x75, 0x74,
+	0x44, 0x65, 0x6c, 0x61, 0x79, 0x4d, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x02, 0x52,
+	0x0d, 0x66, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x44, 0x65, 0x6c, 0x61, 0x79, 0x4d, 0x73, 0x88, 0x01,
+	0x01, 0x12, 0x37, 0x0a, 0x14, 0x73, 0x6b, 0x69, 0x70, 0x53, 0x65, 0x6c, 0x66, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x46, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x48,
+	0x03, 0x52, 0x14, 0x73, 0x6b, 0x69, 0x70, 0x53, 0x65, 0x6c, 0x66, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x46, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x88, 0x01, 0x01, 0x12, 0x2d, 0x0a, 0x0f, 0x73, 0x6b,
+	0x69, 0x70, 0x46, 0x69, 0x72, 0x73, 0x74, 0x46, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x08, 0x48, 0x04, 0x52, 0x0f, 0x73, 0x6b, 0x69, 0x70, 0x46, 0x69, 0x72, 0x73, 0x74,
+	0x46, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x88, 0x01, 0x01, 0x12, 0x33, 0x0a, 0x12, 0x6b, 0x65, 0x79,
+	0x66, 0x72, 0x61, 0x6d, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x05, 0x52, 0x12, 0x6b, 0x65, 0x79, 0x66, 0x72, 0x61, 0x6d,
+	0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73, 0x88, 0x01, 0x01, 0x12, 0x1f,
+	0x0a, 0x08, 0x70, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08,
+	0x48, 0x06, 0x52, 0x08, 0x70, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x88, 0x01, 0x01, 0x12,
+	0x2e, 0x0a, 0x12, 0x70, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x46, 0x69, 0x65, 0x6c, 0x64,
+	0x4d, 0x61, 0x73, 0x6b, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x09, 0x52, 0x12, 0x70, 0x72, 0x65,
+	0x73, 0x65, 0x6e, 0x63, 0x65, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4d, 0x61, 0x73, 0x6b, 0x73, 0x12,
+	0x31, 0x0a, 0x11, 0x66, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x42, 0x75, 0x64, 0x67, 0x65, 0x74, 0x42,
+	0x79, 0x74, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x07, 0x52, 0x11, 0x66, 0x61,
+	0x6e, 0x4f, 0x75, 0x74, 0x42, 0x75, 0x64, 0x67, 0x65, 0x74, 0x42, 0x79, 0x74, 0x65, 0x73, 0x88,
+	0x01, 0x01, 0x12, 0x2b, 0x0a, 0x0e, 0x61, 0x64, 0x61, 0x70, 0x74, 0x69, 0x76, 0x65, 0x46, 0x61,
+	0x6e, 0x4f, 0x75, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x48, 0x08, 0x52, 0x0e, 0x61, 0x64,
+	0x61, 0x70, 0x74, 0x69, 0x76, 0x65, 0x46, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x88, 0x01, 0x01, 0x12,
+	0x35, 0x0a, 0x13, 0x6d, 0x61, 0x78, 0x46, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x49, 0x6e, 0x74, 0x65,
+	0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x09, 0x52, 0x13,
+	0x6d, 0x61, 0x78, 0x46, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61,
+	0x6c, 0x4d, 0x73, 0x88, 0x01, 0x01, 0x12, 0x29, 0x0a, 0x0d, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x64, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x08, 0x48, 0x0a, 0x52,
+	0x0d, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x88, 0x01,
+	0x01, 0x12, 0x23, 0x0a, 0x0a, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x4f, 0x6e, 0x6c, 0x79, 0x18,
+	0x0e, 0x20, 0x01, 0x28, 0x08, 0x48, 0x0b, 0x52, 0x0a, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x4f,
+	0x6e, 0x6c, 0x79, 0x88, 0x01, 0x01, 0x12, 0x37, 0x0a, 0x14, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x44,
+	0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x46, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x18, 0x0f,
+	0x20, 0x01, 0x28, 0x08, 0x48, 0x0c, 0x52, 0x14, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x44, 0x75, 0x70,
+	0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x46, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x88, 0x01, 0x01, 0x42,
+	0x0d, 0x0a, 0x0b, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x42, 0x13,
+	0x0a, 0x11, 0x5f, 0x66, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61,
+	0x6c, 0x4d, 0x73, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x66, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x44, 0x65,
+	0x6c, 0x61, 0x79, 0x4d, 0x73, 0x42, 0x17, 0x0a, 0x15, 0x5f, 0x73, 0x6b, 0x69, 0x70, 0x53, 0x65,
+	0x6c, 0x66, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x46, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x42, 0x12,
+	0x0a, 0x10, 0x5f, 0x73, 0x6b, 0x69, 0x70, 0x46, 0x69, 0x72, 0x73, 0x74, 0x46, 0x61, 0x6e, 0x4f,
+	0x75, 0x74, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x6b, 0x65, 0x79, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x49,
+	0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x70, 0x72,
+	0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x42, 0x14, 0x0a, 0x12, 0x5f, 0x66, 0x61, 0x6e, 0x4f, 0x75,
+	0x74, 0x42, 0x75, 0x64, 0x67, 0x65, 0x74, 0x42, 0x79, 0x74, 0x65, 0x73, 0x42, 0x11, 0x0a, 0x0f,
+	0x5f, 0x61, 0x64, 0x61, 0x70, 0x74, 0x69, 0x76, 0x65, 0x46, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x42,
+	0x16, 0x0a, 0x14, 0x5f, 0x6d, 0x61, 0x78, 0x46, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x49, 0x6e, 0x74,
+	0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x75, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x64, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x73, 0x4f, 0x6e, 0x6c, 0x79, 0x42, 0x17, 0x0a, 0x15, 0x5f, 0x61, 0x6c, 0x6c,
+	0x6f, 0x77, 0x44, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x46, 0x61, 0x6e, 0x4f, 0x75,
+	0x74, 0x22, 0xe7, 0x01, 0x0a, 0x17, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74,
+	0x61, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x2c, 0x0a,
+	0x11, 0x73, 0x68, 0x6f, 0x75, 0x6c, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x4c, 0x69,
+	0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x73, 0x68, 0x6f, 0x75, 0x6c, 0x64,
+	0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x6c,
+	0x69, 0x73, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0d, 0x6c, 0x69, 0x73, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x12, 0x20, 0x0a, 0x0b, 0x74, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x70,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x74, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65,
+	0x54, 0x6f, 0x70, 0x12, 0x42, 0x0a, 0x1c, 0x73, 0x68, 0x6f, 0x75, 0x6c, 0x64, 0x43, 0x68, 0x65,
+	0x63, 0x6b, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x62, 0x6c, 0x65, 0x4d, 0x61, 0x70, 0x46, 0x69,
+	0x65, 0x6c, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x1c, 0x73, 0x68, 0x6f, 0x75, 0x6c,
+	0x64, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x62, 0x6c, 0x65, 0x4d,
+	0x61, 0x70, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x72, 0x64, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x63, 0x72, 0x64, 0x74, 0x22, 0xf6, 0x02, 0x0a, 0x14,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x39, 0x0a, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54,
+	0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79,
+	0x70, 0x65, 0x52, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x1a, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x46, 0x0a, 0x0a, 0x73,
+	0x75, 0x62, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x26, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x0a, 0x73, 0x75, 0x62, 0x4f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x12, 0x28, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x47, 0x0a,
+	0x0c, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62,
+	0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x4d, 0x65, 0x72, 0x67,
+	0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x0c, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x4f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x4b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x4b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x11, 0x64, 0x61, 0x74, 0x61, 0x53, 0x63,
+	0x68, 0x65, 0x6d, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x11, 0x64, 0x61, 0x74, 0x61, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x22, 0xd3, 0x01, 0x0a, 0x1a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x39, 0x0a, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79,
+	0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1a,
+	0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x20, 0x0a, 0x0b, 0x6f, 0x77,
+	0x6e, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0b, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09,
+	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x68,
+	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4b, 0x65, 0x79, 0x22, 0x34, 0x0a, 0x14, 0x52, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64,
+	0x22, 0x77, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x37, 0x0a, 0x0a, 0x74, 0x79, 0x70, 0x65, 0x46, 0x69,
+	0x6c, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54,
+	0x79, 0x70, 0x65, 0x52, 0x0a, 0x74, 0x79, 0x70, 0x65, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12,
+	0x28, 0x0a, 0x0f, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x46, 0x69, 0x6c, 0x74, 0x65,
+	0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x22, 0x8d, 0x02, 0x0a, 0x18, 0x4c, 0x69,
+	0x73, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x4c, 0x0a, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x08, 0x63, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x73, 0x1a, 0xa2, 0x01, 0x0a, 0x0b, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x49, 0x64, 0x12, 0x39, 0x0a, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65,
+	0x52, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a,
+	0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x4b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4b, 0x65, 0x79, 0x22, 0xf4, 0x01, 0x0a, 0x1a, 0x53, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x64, 0x54, 0x6f, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x6e,
+	0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64,
+	0x12, 0x46, 0x0a, 0x0a, 0x73, 0x75, 0x62, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70,
+	0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x0a, 0x73, 0x75,
+	0x62, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x29, 0x0a, 0x0d, 0x72, 0x65, 0x73, 0x75,
+	0x6d, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x53, 0x65, 0x71, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x48,
+	0x00, 0x52, 0x0d, 0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x53, 0x65, 0x71,
+	0x88, 0x01, 0x01, 0x12, 0x39, 0x0a, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79,
+	0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x42, 0x10,
+	0x0a, 0x0e, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x53, 0x65, 0x71,
+	0x22, 0xf5, 0x01, 0x0a, 0x20, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x64, 0x54,
+	0x6f, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x46, 0x0a,
+	0x0a, 0x73, 0x75, 0x62, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x26, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x0a, 0x73, 0x75, 0x62, 0x4f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x36, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x6e, 0x54, 0x79, 0x70,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54,
+	0x79, 0x70, 0x65, 0x52, 0x08, 0x63, 0x6f, 0x6e, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x39, 0x0a,
+	0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e,
+	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0b, 0x63, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x22, 0x38, 0x0a, 0x1e, 0x55, 0x6e, 0x73, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f,
+	0x6e, 0x6e, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x6e,
+	0x49, 0x64, 0x22, 0xb1, 0x01, 0x0a, 0x24, 0x55, 0x6e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63,
+	0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x63, 0x6f, 0x6e,
+	0x6e, 0x49, 0x64, 0x12, 0x36, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64,
+	0x70, 0x62, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x08, 0x63, 0x6f, 0x6e, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x39, 0x0a, 0x0b, 0x63,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x68,
+	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x22, 0xf9, 0x02, 0x0a, 0x1b, 0x53, 0x75, 0x62, 0x73, 0x63,
+	0x72, 0x69, 0x62, 0x65, 0x64, 0x54, 0x6f, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x1e,
+	0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0d, 0x52, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x73, 0x12, 0x46,
+	0x0a, 0x0a, 0x73, 0x75, 0x62, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x26, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e,
+	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x0a, 0x73, 0x75, 0x62, 0x4f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x6c, 0x0a, 0x11, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x53, 0x75, 0x62, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x3e, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53,
+	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x64, 0x54, 0x6f, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x73, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x53, 0x75, 0x62, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x11, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x53, 0x75, 0x62, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x1a, 0x6c, 0x0a, 0x16, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x53,
+	0x75, 0x62, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x3c, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x26, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x22, 0xe5, 0x02, 0x0a, 0x21, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65,
+	0x64, 0x54, 0x6f, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x6e,
+	0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64,
+	0x12, 0x36, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e,
+	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x08,
+	0x63, 0x6f, 0x6e, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x5d, 0x0a, 0x0a, 0x73, 0x75, 0x62, 0x4f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x3d, 0x2e, 0x63,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72,
+	0x69, 0x62, 0x65, 0x64, 0x54, 0x6f, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x53, 0x75, 0x62, 0x4f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x73, 0x75, 0x62,
+	0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x2a, 0x0a, 0x10, 0x66, 0x61, 0x69, 0x6c, 0x65,
+	0x64, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x0d, 0x52, 0x10, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x49, 0x64, 0x73, 0x1a, 0x65, 0x0a, 0x0f, 0x53, 0x75, 0x62, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x3c, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x53, 0x75, 0x62, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x59, 0x0a, 0x1f, 0x55, 0x6e,
+	0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x43, 0x68,
+	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x63,
+	0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x49, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x49, 0x64, 0x73, 0x22, 0xc3, 0x01, 0x0a, 0x25, 0x55, 0x6e, 0x73, 0x75, 0x62, 0x73,
+	0x63, 0x72, 0x69, 0x62, 0x65, 0x64, 0x46, 0x72, 0x6f, 0x6d, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x73, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x36, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x6e, 0x54,
+	0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x63, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x08, 0x63, 0x6f, 0x6e, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x1e, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0d, 0x52, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x73, 0x12,
+	0x2a, 0x0a, 0x10, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x49, 0x64, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x10, 0x66, 0x61, 0x69, 0x6c, 0x65,
+	0x64, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x73, 0x22, 0xd3, 0x01, 0x0a, 0x18,
+	0x53, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x61, 0x67,
+	0x73, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x6e,
+	0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64,
+	0x12, 0x42, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2e,
+	0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53, 0x65, 0x74, 0x43,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x61, 0x67, 0x73, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x2e, 0x54, 0x61, 0x67, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x04,
+	0x74, 0x61, 0x67, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x74, 0x61, 0x67, 0x73, 0x54, 0x6f, 0x52, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x74, 0x61, 0x67, 0x73,
+	0x54, 0x6f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x1a, 0x37, 0x0a, 0x09, 0x54, 0x61, 0x67, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x22, 0x92, 0x01, 0x0a, 0x16, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06,
+	0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x63, 0x6f,
+	0x6e, 0x6e, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x55, 0x73, 0x65,
+	0x72, 0x49, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x75, 0x6e, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0e, 0x75, 0x6e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x73,
+	0x12, 0x14, 0x0a, 0x05, 0x63, 0x6c, 0x65, 0x61, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x05, 0x63, 0x6c, 0x65, 0x61, 0x72, 0x22, 0xd7, 0x05, 0x0a, 0x18, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x28, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x24, 0x0a,
+	0x0d, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x43, 0x6f, 0x6e,
+	0x6e, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a,
+	0x0b, 0x62, 0x61, 0x73, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x04, 0x48, 0x00, 0x52, 0x0b, 0x62, 0x61, 0x73, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0d, 0x63, 0x72, 0x64, 0x74, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x63, 0x72, 0x64,
+	0x74, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1f, 0x0a, 0x08, 0x63, 0x68,
+	0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x01, 0x52, 0x08,
+	0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x88, 0x01, 0x01, 0x12, 0x1a, 0x0a, 0x08, 0x6b,
+	0x65, 0x79, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6b,
+	0x65, 0x79, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x12, 0x2d, 0x0a, 0x0f, 0x6b, 0x65, 0x79, 0x66, 0x72,
+	0x61, 0x6d, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04,
+	0x48, 0x02, 0x52, 0x0f, 0x6b, 0x65, 0x79, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x2d, 0x0a, 0x0f, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f,
+	0x75, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x48,
+	0x03, 0x52, 0x0f, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x53, 0x65,
+	0x71, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x53, 0x65,
+	0x71, 0x12, 0x27, 0x0a, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x53, 0x65,
+	0x71, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x04, 0x48, 0x04, 0x52, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x49,
+	0x6e, 0x70, 0x75, 0x74, 0x53, 0x65, 0x71, 0x88, 0x01, 0x01, 0x12, 0x33, 0x0a, 0x12, 0x6d, 0x61,
+	0x78, 0x45, 0x78, 0x74, 0x72, 0x61, 0x70, 0x6f, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x73,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x05, 0x52, 0x12, 0x6d, 0x61, 0x78, 0x45, 0x78, 0x74,
+	0x72, 0x61, 0x70, 0x6f, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x73, 0x88, 0x01, 0x01, 0x12,
+	0x22, 0x0a, 0x0c, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x4d, 0x73, 0x18,
+	0x0d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x54, 0x69, 0x6d,
+	0x65, 0x4d, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x63, 0x6b, 0x18, 0x0e, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x04, 0x74, 0x69, 0x63, 0x6b, 0x12, 0x24, 0x0a, 0x0d, 0x73, 0x63, 0x68, 0x65, 0x6d,
+	0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d,
+	0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a,
+	0x0d, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x10,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x46, 0x69, 0x65,
+	0x6c, 0x64, 0x73, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d,
+	0x42, 0x12, 0x0a, 0x10, 0x5f, 0x6b, 0x65, 0x79, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75,
+	0x73, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6c, 0x61, 0x73,
+	0x74, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x53, 0x65, 0x71, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x6d, 0x61,
+	0x78, 0x45, 0x78, 0x74, 0x72, 0x61, 0x70, 0x6f, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x73,
+	0x22, 0x93, 0x01, 0x0a, 0x17, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x63, 0x6f,
+	0x76, 0x65, 0x72, 0x65, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1e, 0x0a, 0x0a,
+	0x70, 0x61, 0x6e, 0x69, 0x63, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x0a, 0x70, 0x61, 0x6e, 0x69, 0x63, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x22, 0x0a, 0x0c,
+	0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0c, 0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x64,
+	0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65,
+	0x6d, 0x6f, 0x76, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65,
+	0x6d, 0x6f, 0x76, 0x69, 0x6e, 0x67, 0x22, 0xb0, 0x01, 0x0a, 0x1a, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x43, 0x6f,
+	0x6e, 0x6e, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x6f, 0x77, 0x6e, 0x65,
+	0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x30, 0x0a, 0x13, 0x70, 0x72, 0x65, 0x76, 0x69,
+	0x6f, 0x75, 0x73, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x13, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x4f, 0x77,
+	0x6e, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x72, 0x6f,
+	0x7a, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x66, 0x72, 0x6f, 0x7a, 0x65,
+	0x6e, 0x12, 0x26, 0x0a, 0x0e, 0x72, 0x65, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x47, 0x72, 0x61, 0x63,
+	0x65, 0x4d, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x72, 0x65, 0x63, 0x6c, 0x61,
+	0x69, 0x6d, 0x47, 0x72, 0x61, 0x63, 0x65, 0x4d, 0x73, 0x22, 0xd9, 0x01, 0x0a, 0x10, 0x41, 0x6e,
+	0x61, 0x6c, 0x79, 0x74, 0x69, 0x63, 0x73, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x12, 0x4c, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69,
+	0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x74, 0x69, 0x63, 0x73, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69,
+	0x65, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x54, 0x69, 0x6d, 0x65,
+	0x4d, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
+	0x54, 0x69, 0x6d, 0x65, 0x4d, 0x73, 0x1a, 0x3d, 0x0a, 0x0f, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72,
+	0x74, 0x69, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x72, 0x0a, 0x16, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64,
+	0x74, 0x68, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
+	0x22, 0x0a, 0x0c, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x69, 0x6e, 0x6b, 0x4b, 0x62, 0x70, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x69, 0x6e, 0x6b, 0x4b,
+	0x62, 0x70, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x4c, 0x6f, 0x73,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0a, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x4c,
+	0x6f, 0x73, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x74, 0x74, 0x4d, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x05, 0x72, 0x74, 0x74, 0x4d, 0x73, 0x22, 0x4f, 0x0a, 0x19, 0x53, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x4b, 0x65, 0x79, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6b, 0x65, 0x79, 0x49, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6b, 0x65, 0x79, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09,
+	0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x22, 0xae, 0x01, 0x0a, 0x16, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6f, 0x4c, 0x61, 0x72, 0x67, 0x65, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x6d, 0x61, 0x78,
+	0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x0d, 0x6d, 0x61, 0x78, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x6d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x07, 0x6d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x75,
+	0x62, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x73, 0x74, 0x75, 0x62, 0x49,
+	0x64, 0x12, 0x28, 0x0a, 0x0f, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x6f,
+	0x6e, 0x6e, 0x49, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x72, 0x65, 0x63, 0x69,
+	0x70, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x22, 0x96, 0x03, 0x0a, 0x0c,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x6d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x6d,
+	0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x31, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70,
+	0x62, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x43,
+	0x6f, 0x64, 0x65, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x22, 0x9e, 0x02, 0x0a, 0x04, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x0b, 0x0a, 0x07,
+	0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x43, 0x48, 0x41,
+	0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0x01,
+	0x12, 0x18, 0x0a, 0x14, 0x43, 0x4f, 0x4e, 0x4e, 0x45, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4e,
+	0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0x02, 0x12, 0x17, 0x0a, 0x13, 0x4d, 0x45,
+	0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x41, 0x4c, 0x4c, 0x4f, 0x57, 0x45,
+	0x44, 0x10, 0x03, 0x12, 0x13, 0x0a, 0x0f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x4d,
+	0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x10, 0x04, 0x12, 0x11, 0x0a, 0x0d, 0x57, 0x52, 0x4f, 0x4e,
+	0x47, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x10, 0x05, 0x12, 0x11, 0x0a, 0x0d, 0x41,
+	0x43, 0x43, 0x45, 0x53, 0x53, 0x5f, 0x44, 0x45, 0x4e, 0x49, 0x45, 0x44, 0x10, 0x06, 0x12, 0x12,
+	0x0a, 0x0e, 0x4e, 0x4f, 0x54, 0x5f, 0x53, 0x55, 0x42, 0x53, 0x43, 0x52, 0x49, 0x42, 0x45, 0x44,
+	0x10, 0x07, 0x12, 0x16, 0x0a, 0x12, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x4b, 0x45,
+	0x59, 0x5f, 0x49, 0x4e, 0x5f, 0x55, 0x53, 0x45, 0x10, 0x08, 0x12, 0x14, 0x0a, 0x10, 0x49, 0x4e,
+	0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x41, 0x52, 0x47, 0x55, 0x4d, 0x45, 0x4e, 0x54, 0x10, 0x09,
+	0x12, 0x0c, 0x0a, 0x08, 0x49, 0x4e, 0x54, 0x45, 0x52, 0x4e, 0x41, 0x4c, 0x10, 0x0a, 0x12, 0x12,
+	0x0a, 0x0e, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x46, 0x52, 0x4f, 0x5a, 0x45, 0x4e,
+	0x10, 0x0b, 0x12, 0x20, 0x0a, 0x1c, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x44, 0x41,
+	0x54, 0x41, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x49, 0x4e, 0x49, 0x54, 0x49, 0x41, 0x4c, 0x49, 0x5a,
+	0x45, 0x44, 0x10, 0x0c, 0x22, 0xe9, 0x02, 0x0a, 0x13, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x53, 0x74, 0x61, 0x74, 0x73, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1a, 0x0a, 0x08,
+	0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x4d, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08,
+	0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x4d, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x75, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x0d, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63, 0x12, 0x24,
+	0x0a, 0x0d, 0x66, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x66, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x73, 0x50, 0x65,
+	0x72, 0x53, 0x65, 0x63, 0x12, 0x2a, 0x0a, 0x10, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62,
+	0x65, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x10,
+	0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63,
+	0x12, 0x2e, 0x0a, 0x12, 0x75, 0x6e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x73,
+	0x50, 0x65, 0x72, 0x53, 0x65, 0x63, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x12, 0x75, 0x6e,
+	0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63,
+	0x12, 0x28, 0x0a, 0x0f, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x73, 0x75, 0x62, 0x73, 0x63,
+	0x72, 0x69, 0x62, 0x65, 0x72, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x61,
+	0x74, 0x61, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x64,
+	0x61, 0x74, 0x61, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x64, 0x61, 0x74, 0x61,
+	0x42, 0x79, 0x74, 0x65, 0x73, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0e, 0x64, 0x61, 0x74, 0x61, 0x42, 0x79, 0x74, 0x65, 0x73, 0x44, 0x65, 0x6c, 0x74, 0x61,
+	0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x70, 0x75, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x63, 0x70, 0x75, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74,
+	0x22, 0x4d, 0x0a, 0x15, 0x44, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x4c, 0x6f, 0x67,
+	0x69, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x65, 0x77,
+	0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x6e, 0x65,
+	0x77, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6b, 0x69, 0x63, 0x6b, 0x65,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x6b, 0x69, 0x63, 0x6b, 0x65, 0x64, 0x22,
+	0x9f, 0x01, 0x0a, 0x15, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x69, 0x63, 0x6b, 0x4c,
+	0x61, 0x67, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x26, 0x0a, 0x0e, 0x74, 0x69, 0x63,
+	0x6b, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0e, 0x74, 0x69, 0x63, 0x6b, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d,
+	0x73, 0x12, 0x2a, 0x0a, 0x10, 0x61, 0x63, 0x74, 0x75, 0x61, 0x6c, 0x49, 0x6e, 0x74, 0x65, 0x72,
+	0x76, 0x61, 0x6c, 0x4d, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x61, 0x63, 0x74,
+	0x75, 0x61, 0x6c, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73, 0x12, 0x14, 0x0a,
+	0x05, 0x74, 0x69, 0x63, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x74, 0x69,
+	0x63, 0x6b, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x65, 0x64,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x72, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x65,
+	0x64, 0x22, 0xa8, 0x02, 0x0a, 0x12, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x73, 0x67, 0x54,
+	0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x6d, 0x73, 0x67, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x61, 0x74, 0x61, 0x54, 0x79, 0x70, 0x65, 0x55, 0x72,
+	0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x61, 0x74, 0x61, 0x54, 0x79, 0x70,
+	0x65, 0x55, 0x72, 0x6c, 0x12, 0x3e, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64,
+	0x70, 0x62, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x42, 0x61, 0x74, 0x63, 0x68, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74,
+	0x72, 0x69, 0x65, 0x73, 0x1a, 0x95, 0x01, 0x0a, 0x05, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x1c,
+	0x0a, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a,
+	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4b, 0x65, 0x79, 0x12, 0x1c, 0x0a, 0x09,
+	0x62, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x09, 0x62, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74,
+	0x75, 0x62, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x73, 0x74, 0x75, 0x62,
+	0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x73, 0x67, 0x42, 0x6f, 0x64, 0x79, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x07, 0x6d, 0x73, 0x67, 0x42, 0x6f, 0x64, 0x79, 0x22, 0x7f, 0x0a, 0x15,
+	0x53, 0x6c, 0x6f, 0x77, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x72, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x2a, 0x0a,
+	0x10, 0x66, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x66, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x49,
+	0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x6b, 0x65, 0x79,
+	0x66, 0x72, 0x61, 0x6d, 0x65, 0x4f, 0x6e, 0x6c, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0c, 0x6b, 0x65, 0x79, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x4f, 0x6e, 0x6c, 0x79, 0x22, 0xdb, 0x01,
+	0x0a, 0x12, 0x43, 0x68, 0x61, 0x74, 0x50, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06,
+	0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73,
+	0x65, 0x72, 0x49, 0x64, 0x12, 0x3d, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x25, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70,
+	0x62, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x50, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x2e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52, 0x06, 0x72, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x75, 0x74,
+	0x65, 0x4d, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x6d, 0x75, 0x74, 0x65, 0x4d,
+	0x73, 0x22, 0x28, 0x0a, 0x06, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x0c, 0x0a, 0x08, 0x52,
+	0x45, 0x50, 0x45, 0x41, 0x54, 0x45, 0x44, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x4d, 0x45, 0x4e,
+	0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x50, 0x41, 0x4d, 0x10, 0x01, 0x22, 0x9a, 0x02, 0x0a, 0x14,
+	0x51, 0x75, 0x6f, 0x74, 0x61, 0x45, 0x78, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x48, 0x0a, 0x09, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x54, 0x79, 0x70,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x2a, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x64, 0x70, 0x62, 0x2e, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x45, 0x78, 0x63, 0x65, 0x65, 0x64,
+	0x65, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x54,
+	0x79, 0x70, 0x65, 0x52, 0x09, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x39, 0x0a, 0x0b,
+	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0b, 0x63, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x22, 0x4f, 0x0a, 0x09, 0x51, 0x75, 0x6f, 0x74, 0x61,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x0c, 0x0a, 0x08, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x53,
+	0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x53, 0x5f, 0x4f,
+	0x46, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x4f, 0x4e, 0x4e,
+	0x45, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x53, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x42, 0x41, 0x4e,
+	0x44, 0x57, 0x49, 0x44, 0x54, 0x48, 0x10, 0x03, 0x22, 0x48, 0x0a, 0x16, 0x50, 0x61, 0x75, 0x73,
+	0x65, 0x44, 0x61, 0x74, 0x61, 0x46, 0x61, 0x6e, 0x4f, 0x75, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65,
+	0x73, 0x75, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75,
+	0x6d, 0x65, 0x22, 0x36, 0x0a, 0x18, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74,
+	0x61, 0x52, 0x65, 0x73, 0x79, 0x6e, 0x63, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1a,
+	0x0a, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x22, 0xb8, 0x01, 0x0a, 0x1a, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x43, 0x6f, 0x6e, 0x66, 0x6c, 0x69,
+	0x63, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x28, 0x0a, 0x04, 0x64, 0x61, 0x74,
+	0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x12, 0x20, 0x0a, 0x0b, 0x62, 0x61, 0x73, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x62, 0x61, 0x73, 0x65, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x0e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x63,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a,
+	0x0e, 0x63, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x63, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x46,
+	0x69, 0x65, 0x6c, 0x64, 0x73, 0x22, 0x74, 0x0a, 0x16, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x44, 0x61, 0x74, 0x61, 0x4c, 0x6f, 0x63, 0x6b, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x66, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x6e, 0x6c, 0x6f, 0x63,
+	0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x75, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x12,
+	0x1a, 0x0a, 0x08, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x4d, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x08, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x4d, 0x73, 0x22, 0xc0, 0x01, 0x0a, 0x1c,
+	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x4c, 0x6f, 0x63, 0x6b, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x66, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x65,
+	0x6c, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x75, 0x6e, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x18, 0x0a, 0x07,
+	0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73,
+	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x68, 0x6f, 0x6c, 0x64, 0x65, 0x72,
+	0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x68, 0x6f,
+	0x6c, 0x64, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x65, 0x78,
+	0x70, 0x69, 0x72, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x4d, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0c, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x4d, 0x73, 0x22, 0x41,
+	0x0a, 0x17, 0x51, 0x75, 0x65, 0x72, 0x79, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61,
+	0x74, 0x61, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x26, 0x0a, 0x0e, 0x64, 0x61, 0x74,
+	0x61, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4d, 0x61, 0x73, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0e, 0x64, 0x61, 0x74, 0x61, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4d, 0x61, 0x73, 0x6b,
+	0x73, 0x22, 0xd1, 0x03, 0x0a, 0x1d, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74,
+	0x61, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x50, 0x0a, 0x09, 0x6d, 0x75, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x64, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x2e, 0x4d, 0x75, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x6d, 0x75, 0x74, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x1a, 0xdd, 0x02, 0x0a, 0x08, 0x4d, 0x75, 0x74, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x45, 0x0a, 0x02, 0x6f, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x35,
+	0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x4d, 0x75, 0x74, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x2e, 0x4f, 0x70, 0x52, 0x02, 0x6f, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x65,
+	0x6c, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x28, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x1a, 0x0a, 0x08, 0x69,
+	0x66, 0x41, 0x62, 0x73, 0x65, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69,
+	0x66, 0x41, 0x62, 0x73, 0x65, 0x6e, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x46, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x64, 0x65, 0x6c, 0x74, 0x61,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x15, 0x0a,
+	0x03, 0x6d, 0x69, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x03, 0x6d, 0x69,
+	0x6e, 0x88, 0x01, 0x01, 0x12, 0x15, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x03, 0x48, 0x01, 0x52, 0x03, 0x6d, 0x61, 0x78, 0x88, 0x01, 0x01, 0x22, 0x28, 0x0a, 0x02, 0x4f,
+	0x70, 0x12, 0x07, 0x0a, 0x03, 0x50, 0x55, 0x54, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x52, 0x45,
+	0x4d, 0x4f, 0x56, 0x45, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x49, 0x4e, 0x43, 0x52, 0x45, 0x4d,
+	0x45, 0x4e, 0x54, 0x10, 0x02, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x6d, 0x69, 0x6e, 0x42, 0x06, 0x0a,
+	0x04, 0x5f, 0x6d, 0x61, 0x78, 0x22, 0x8c, 0x02, 0x0a, 0x23, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x44, 0x61, 0x74, 0x61, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x4e, 0x0a,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x36, 0x2e,
+	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x20, 0x0a,
+	0x0b, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0b, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12,
+	0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x59, 0x0a, 0x06, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x12, 0x0b, 0x0a, 0x07, 0x41, 0x50, 0x50, 0x4c, 0x49, 0x45, 0x44, 0x10, 0x00,
+	0x12, 0x0b, 0x0a, 0x07, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x10, 0x01, 0x12, 0x17, 0x0a,
+	0x13, 0x50, 0x52, 0x45, 0x43, 0x4f, 0x4e, 0x44, 0x49, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x46, 0x41,
+	0x49, 0x4c, 0x45, 0x44, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0c, 0x4f, 0x55, 0x54, 0x5f, 0x4f, 0x46,
+	0x5f, 0x52, 0x41, 0x4e, 0x47, 0x45, 0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x4c, 0x4f, 0x43, 0x4b,
+	0x45, 0x44, 0x10, 0x04, 0x22, 0x63, 0x0a, 0x13, 0x46, 0x65, 0x74, 0x63, 0x68, 0x48, 0x69, 0x73,
+	0x74, 0x6f, 0x72, 0x79, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x66,
+	0x69, 0x65, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c,
+	0x64, 0x12, 0x20, 0x0a, 0x0b, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x49, 0x6e, 0x64, 0x65, 0x78,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x95, 0x01, 0x0a, 0x19, 0x46, 0x65,
+	0x74, 0x63, 0x68, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x28, 0x0a,
+	0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e,
+	0x79, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x18, 0x0a, 0x07, 0x68, 0x61, 0x73, 0x4d, 0x6f,
+	0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x68, 0x61, 0x73, 0x4d, 0x6f, 0x72,
+	0x65, 0x22, 0xb9, 0x01, 0x0a, 0x17, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4c, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1c, 0x0a,
+	0x09, 0x61, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x4b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x61, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x4b, 0x65, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x61,
+	0x72, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x65, 0x6c, 0x66, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0a, 0x61, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x53, 0x65, 0x6c, 0x66, 0x12, 0x16, 0x0a, 0x06, 0x62,
+	0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x62, 0x65, 0x66,
+	0x6f, 0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x05, 0x61, 0x66, 0x74, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x52, 0x61, 0x6e, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x52, 0x61, 0x6e, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0xab, 0x01,
+	0x0a, 0x1d, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x62, 0x6f, 0x61,
+	0x72, 0x64, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
+	0x28, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x41, 0x6e, 0x79, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x65, 0x79,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x12, 0x1c, 0x0a,
+	0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x52, 0x61, 0x6e, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x52, 0x61, 0x6e, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x12, 0x18, 0x0a, 0x07, 0x6b, 0x65, 0x79, 0x52, 0x61, 0x6e, 0x6b, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x07, 0x6b, 0x65, 0x79, 0x52, 0x61, 0x6e, 0x6b, 0x22, 0xa3, 0x03, 0x0a, 0x0f,
+	0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x1e, 0x0a,
+	0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4b, 0x65, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x73, 0x65, 0x71, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x73, 0x65, 0x71, 0x12,
+	0x22, 0x0a, 0x0c, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x43, 0x6f, 0x6e,
+	0x6e, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x55, 0x73, 0x65,
+	0x72, 0x49, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x65, 0x6e, 0x64, 0x65,
+	0x72, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x6d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x65, 0x6e,
+	0x61, 0x6e, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e,
+	0x74, 0x12, 0x1c, 0x0a, 0x09, 0x62, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x62, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x12,
+	0x1c, 0x0a, 0x09, 0x74, 0x61, 0x67, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x74, 0x61, 0x67, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x22, 0x0a,
+	0x0c, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x0d, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x0c, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x49,
+	0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x61, 0x77, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18,
+	0x0e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x72, 0x61, 0x77, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61,
+	0x64, 0x22, 0x79, 0x0a, 0x13, 0x46, 0x65, 0x74, 0x63, 0x68, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76,
+	0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x72, 0x6f, 0x6d,
+	0x54, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x66, 0x72, 0x6f, 0x6d,
+	0x54, 0x69, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x6f, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x74, 0x6f, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x61, 0x66, 0x74, 0x65, 0x72, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x61,
+	0x66, 0x74, 0x65, 0x72, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x6e, 0x0a, 0x19,
+	0x46, 0x65, 0x74, 0x63, 0x68, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x37, 0x0a, 0x08, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x63, 0x68,
+	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65,
+	0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x08, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x68, 0x61, 0x73, 0x4d, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x68, 0x61, 0x73, 0x4d, 0x6f, 0x72, 0x65, 0x22, 0xcf, 0x01, 0x0a,
+	0x0c, 0x50, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x16, 0x0a,
+	0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75,
+	0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x6e, 0x6c, 0x69, 0x6e, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x6f, 0x6e, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x63,
+	0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x49, 0x64, 0x12, 0x39, 0x0a, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79,
+	0x70, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x0b, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1e,
+	0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x22, 0xc3,
+	0x01, 0x0a, 0x15, 0x50, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x42, 0x0a, 0x05, 0x75, 0x73, 0x65, 0x72,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x64, 0x70, 0x62, 0x2e, 0x50, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x12, 0x12, 0x0a, 0x04,
+	0x66, 0x75, 0x6c, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x66, 0x75, 0x6c, 0x6c,
+	0x1a, 0x52, 0x0a, 0x0a, 0x55, 0x73, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x2e, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x50, 0x72, 0x65,
+	0x73, 0x65, 0x6e, 0x63, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x22, 0x63, 0x0a, 0x1d, 0x51, 0x75, 0x65, 0x72, 0x79, 0x43, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x28, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12,
+	0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0xcc, 0x01, 0x0a, 0x0c, 0x52, 0x65,
+	0x6c, 0x61, 0x79, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x64,
+	0x67, 0x65, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a,
+	0x65, 0x64, 0x67, 0x65, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x34, 0x0a, 0x05, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x63, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04,
+	0x64, 0x61, 0x74, 0x61, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x41, 0x64,
+	0x64, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65,
+	0x41, 0x64, 0x64, 0x72, 0x22, 0x32, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x08, 0x0a,
+	0x04, 0x44, 0x41, 0x54, 0x41, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x43, 0x4f, 0x4e, 0x4e, 0x45,
+	0x43, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x44, 0x49, 0x53, 0x43, 0x4f, 0x4e,
+	0x4e, 0x45, 0x43, 0x54, 0x45, 0x44, 0x10, 0x02, 0x22, 0x4f, 0x0a, 0x13, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x4d, 0x73, 0x22, 0x69, 0x0a, 0x11, 0x54, 0x69, 0x6d,
+	0x65, 0x72, 0x46, 0x69, 0x72, 0x65, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x07, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x24,
+	0x0a, 0x0d, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x69, 0x6d, 0x65, 0x4d, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x69,
+	0x6d, 0x65, 0x4d, 0x73, 0x22, 0x31, 0x0a, 0x0b, 0x50, 0x69, 0x6e, 0x67, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x54, 0x69, 0x6d,
+	0x65, 0x4d, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x54, 0x69, 0x6d, 0x65, 0x4d, 0x73, 0x22, 0xf3, 0x01, 0x0a, 0x11, 0x44, 0x69, 0x73, 0x63,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x63,
+	0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x3c, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x24, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64,
+	0x70, 0x62, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x2e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52, 0x06, 0x72, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x22, 0x70, 0x0a, 0x06, 0x52,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x0f, 0x0a, 0x0b, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49,
+	0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x4b, 0x49, 0x43, 0x4b, 0x45, 0x44,
+	0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x41, 0x55, 0x54, 0x48, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x55,
+	0x52, 0x45, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0c, 0x49, 0x44, 0x4c, 0x45, 0x5f, 0x54, 0x49, 0x4d,
+	0x45, 0x4f, 0x55, 0x54, 0x10, 0x03, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x45, 0x52, 0x56, 0x45, 0x52,
+	0x5f, 0x44, 0x52, 0x41, 0x49, 0x4e, 0x10, 0x04, 0x12, 0x13, 0x0a, 0x0f, 0x44, 0x55, 0x50, 0x4c,
+	0x49, 0x43, 0x41, 0x54, 0x45, 0x5f, 0x4c, 0x4f, 0x47, 0x49, 0x4e, 0x10, 0x05, 0x22, 0x37, 0x0a,
+	0x0b, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x0c, 0x0a, 0x01,
+	0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x01, 0x79, 0x12, 0x0c, 0x0a, 0x01, 0x7a, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x01, 0x7a, 0x22, 0x8e, 0x01, 0x0a, 0x22, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x2a, 0x0a,
+	0x10, 0x73, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49,
+	0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x10, 0x73, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c,
+	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x20, 0x0a, 0x0b, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x43, 0x6f,
+	0x6e, 0x6e, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x6f, 0x77, 0x6e, 0x65,
+	0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x22, 0x57, 0x0a, 0x1a, 0x51, 0x75, 0x65, 0x72, 0x79,
+	0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x39, 0x0a, 0x0b, 0x73, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c,
+	0x49, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49,
+	0x6e, 0x66, 0x6f, 0x52, 0x0b, 0x73, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e, 0x66, 0x6f,
+	0x22, 0x40, 0x0a, 0x20, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c,
+	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49,
+	0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x49, 0x64, 0x22, 0xb4, 0x01, 0x0a, 0x1a, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61,
+	0x74, 0x61, 0x48, 0x61, 0x6e, 0x64, 0x6f, 0x76, 0x65, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x72, 0x63, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x73, 0x72, 0x63, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x64, 0x73, 0x74, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x64, 0x73, 0x74,
+	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x24, 0x0a, 0x0d, 0x63, 0x6f, 0x6e,
+	0x74, 0x65, 0x78, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x0d, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x43, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12,
+	0x28, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x41, 0x6e, 0x79, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0xa5, 0x01, 0x0a, 0x0d, 0x53, 0x70,
+	0x61, 0x74, 0x69, 0x61, 0x6c, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x12, 0x29, 0x0a, 0x03, 0x6d,
+	0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e, 0x66,
+	0x6f, 0x52, 0x03, 0x6d, 0x69, 0x6e, 0x12, 0x29, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62,
+	0x2e, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x03, 0x6d, 0x61,
+	0x78, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12,
+	0x20, 0x0a, 0x0b, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x49, 0x6e, 0x64, 0x65,
+	0x78, 0x22, 0x52, 0x0a, 0x1b, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x52, 0x65, 0x67, 0x69,
+	0x6f, 0x6e, 0x73, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x33, 0x0a, 0x07, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53,
+	0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x52, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x72, 0x65,
+	0x67, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xa7, 0x06, 0x0a, 0x14, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61,
+	0x6c, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x65, 0x73, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x4a,
+	0x0a, 0x08, 0x73, 0x70, 0x6f, 0x74, 0x73, 0x41, 0x4f, 0x49, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x29, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53, 0x70,
+	0x61, 0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x65, 0x73, 0x74, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x2e, 0x53, 0x70, 0x6f, 0x74, 0x73, 0x41, 0x4f, 0x49, 0x48, 0x00, 0x52, 0x08, 0x73,
+	0x70, 0x6f, 0x74, 0x73, 0x41, 0x4f, 0x49, 0x88, 0x01, 0x01, 0x12, 0x44, 0x0a, 0x06, 0x62, 0x6f,
+	0x78, 0x41, 0x4f, 0x49, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x63, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49,
+	0x6e, 0x74, 0x65, 0x72, 0x65, 0x73, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x2e, 0x42, 0x6f, 0x78,
+	0x41, 0x4f, 0x49, 0x48, 0x01, 0x52, 0x06, 0x62, 0x6f, 0x78, 0x41, 0x4f, 0x49, 0x88, 0x01, 0x01,
+	0x12, 0x4d, 0x0a, 0x09, 0x73, 0x70, 0x68, 0x65, 0x72, 0x65, 0x41, 0x4f, 0x49, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62,
+	0x2e, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x65, 0x73, 0x74,
+	0x51, 0x75, 0x65, 0x72, 0x79, 0x2e, 0x53, 0x70, 0x68, 0x65, 0x72, 0x65, 0x41, 0x4f, 0x49, 0x48,
+	0x02, 0x52, 0x09, 0x73, 0x70, 0x68, 0x65, 0x72, 0x65, 0x41, 0x4f, 0x49, 0x88, 0x01, 0x01, 0x12,
+	0x47, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x65, 0x41, 0x4f, 0x49, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x28, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53, 0x70,
+	0x61, 0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x65, 0x73, 0x74, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x2e, 0x43, 0x6f, 0x6e, 0x65, 0x41, 0x4f, 0x49, 0x48, 0x03, 0x52, 0x07, 0x63, 0x6f,
+	0x6e, 0x65, 0x41, 0x4f, 0x49, 0x88, 0x01, 0x01, 0x1a, 0x4f, 0x0a, 0x08, 0x53, 0x70, 0x6f, 0x74,
+	0x73, 0x41, 0x4f, 0x49, 0x12, 0x2d, 0x0a, 0x05, 0x73, 0x70, 0x6f, 0x74, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62,
+	0x2e, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x05, 0x73, 0x70,
+	0x6f, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x64, 0x69, 0x73, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0d, 0x52, 0x05, 0x64, 0x69, 0x73, 0x74, 0x73, 0x1a, 0x6a, 0x0a, 0x06, 0x42, 0x6f, 0x78,
+	0x41, 0x4f, 0x49, 0x12, 0x2f, 0x0a, 0x06, 0x63, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62,
+	0x2e, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x06, 0x63, 0x65,
+	0x6e, 0x74, 0x65, 0x72, 0x12, 0x2f, 0x0a, 0x06, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70,
+	0x62, 0x2e, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x06, 0x65,
+	0x78, 0x74, 0x65, 0x6e, 0x74, 0x1a, 0x54, 0x0a, 0x09, 0x53, 0x70, 0x68, 0x65, 0x72, 0x65, 0x41,
+	0x4f, 0x49, 0x12, 0x2f, 0x0a, 0x06, 0x63, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e,
+	0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x06, 0x63, 0x65, 0x6e,
+	0x74, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x06, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x1a, 0x9f, 0x01, 0x0a, 0x07,
+	0x43, 0x6f, 0x6e, 0x65, 0x41, 0x4f, 0x49, 0x12, 0x2f, 0x0a, 0x06, 0x63, 0x65, 0x6e, 0x74, 0x65,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e, 0x66, 0x6f,
+	0x52, 0x06, 0x63, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x12, 0x35, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x68,
+	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c,
+	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x14, 0x0a, 0x05, 0x61, 0x6e, 0x67, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05,
+	0x61, 0x6e, 0x67, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x42, 0x0b, 0x0a,
+	0x09, 0x5f, 0x73, 0x70, 0x6f, 0x74, 0x73, 0x41, 0x4f, 0x49, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x62,
+	0x6f, 0x78, 0x41, 0x4f, 0x49, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x73, 0x70, 0x68, 0x65, 0x72, 0x65,
+	0x41, 0x4f, 0x49, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x63, 0x6f, 0x6e, 0x65, 0x41, 0x4f, 0x49, 0x22,
+	0x6e, 0x0a, 0x1c, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x06, 0x63, 0x6f, 0x6e, 0x6e, 0x49, 0x64, 0x12, 0x36, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x64, 0x70, 0x62, 0x2e, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x49, 0x6e, 0x74, 0x65, 0x72,
+	0x65, 0x73, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x22,
+	0xdf, 0x02, 0x0a, 0x1a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79,
+	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1a,
+	0x0a, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x65,
+	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x65,
+	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x46, 0x0a, 0x0a, 0x73, 0x75, 0x62, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x63, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x53,
+	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x52, 0x0a, 0x73, 0x75, 0x62, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x28,
+	0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41,
+	0x6e, 0x79, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x47, 0x0a, 0x0c, 0x6d, 0x65, 0x72, 0x67,
+	0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23,
+	0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x4f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x52, 0x0c, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x12, 0x20, 0x0a, 0x0b, 0x69, 0x73, 0x57, 0x65, 0x6c, 0x6c, 0x4b, 0x6e, 0x6f, 0x77, 0x6e,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x69, 0x73, 0x57, 0x65, 0x6c, 0x6c, 0x4b, 0x6e,
+	0x6f, 0x77, 0x6e, 0x12, 0x2c, 0x0a, 0x11, 0x64, 0x61, 0x74, 0x61, 0x53, 0x63, 0x68, 0x65, 0x6d,
+	0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x11,
+	0x64, 0x61, 0x74, 0x61, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x22, 0x6e, 0x0a, 0x15, 0x41, 0x64, 0x64, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x2f, 0x0a, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x47, 0x72, 0x6f, 0x75,
+	0x70, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x45,
+	0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x54, 0x6f, 0x41, 0x64, 0x64, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0d, 0x52, 0x0d, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x54, 0x6f, 0x41, 0x64,
+	0x64, 0x22, 0x77, 0x0a, 0x18, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x45, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x2f, 0x0a,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x63, 0x68,
+	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64, 0x70, 0x62, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x2a,
+	0x0a, 0x10, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x54, 0x6f, 0x52, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x10, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x69,
+	0x65, 0x73, 0x54, 0x6f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x22, 0x1f, 0x0a, 0x1d, 0x44, 0x65,
+	0x62, 0x75, 0x67, 0x47, 0x65, 0x74, 0x53, 0x70, 0x61, 0x74, 0x69, 0x61, 0x6c, 0x52, 0x65, 0x67,
+	0x69, 0x6f, 0x6e, 0x73, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2a, 0xa7, 0x01, 0x0a, 0x0d,
+	0x42, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x10, 0x0a,
+	0x0c, 0x4e, 0x4f, 0x5f, 0x42, 0x52, 0x4f, 0x41, 0x44, 0x43, 0x41, 0x53, 0x54, 0x10, 0x00, 0x12,
+	0x15, 0x0a, 0x11, 0x53, 0x49, 0x4e, 0x47, 0x4c, 0x45, 0x5f, 0x43, 0x4f, 0x4e, 0x4e, 0x45, 0x43,
+	0x54, 0x49, 0x4f, 0x4e, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x4c, 0x4c, 0x10, 0x02, 0x12,
+	0x12, 0x0a, 0x0e, 0x41, 0x4c, 0x4c, 0x5f, 0x42, 0x55, 0x54, 0x5f, 0x53, 0x45, 0x4e, 0x44, 0x45,
+	0x52, 0x10, 0x04, 0x12, 0x11, 0x0a, 0x0d, 0x41, 0x4c, 0x4c, 0x5f, 0x42, 0x55, 0x54, 0x5f, 0x4f,
+	0x57, 0x4e, 0x45, 0x52, 0x10, 0x08, 0x12, 0x12, 0x0a, 0x0e, 0x41, 0x4c, 0x4c, 0x5f, 0x42, 0x55,
+	0x54, 0x5f, 0x43, 0x4c, 0x49, 0x45, 0x4e, 0x54, 0x10, 0x10, 0x12, 0x12, 0x0a, 0x0e, 0x41, 0x4c,
+	0x4c, 0x5f, 0x42, 0x55, 0x54, 0x5f, 0x53, 0x45, 0x52, 0x56, 0x45, 0x52, 0x10, 0x20, 0x12, 0x15,
+	0x0a, 0x11, 0x41, 0x44, 0x4a, 0x41, 0x43, 0x45, 0x4e, 0x54, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e,
+	0x45, 0x4c, 0x53, 0x10, 0x40, 0x2a, 0x49, 0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x11, 0x0a, 0x0d, 0x4e, 0x4f, 0x5f, 0x43, 0x4f,
+	0x4e, 0x4e, 0x45, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x45,
+	0x52, 0x56, 0x45, 0x52, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x4c, 0x49, 0x45, 0x4e, 0x54,
+	0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x4f, 0x52, 0x10, 0x03,
+	0x2a, 0x90, 0x01, 0x0a, 0x0b, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0a, 0x0a,
+	0x06, 0x47, 0x4c, 0x4f, 0x42, 0x41, 0x4c, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x52, 0x49,
+	0x56, 0x41, 0x54, 0x45, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x55, 0x42, 0x57, 0x4f, 0x52,
+	0x4c, 0x44, 0x10, 0x03, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x50, 0x41, 0x54, 0x49, 0x41, 0x4c, 0x10,
+	0x04, 0x12, 0x0a, 0x0a, 0x06, 0x45, 0x4e, 0x54, 0x49, 0x54, 0x59, 0x10, 0x05, 0x12, 0x08, 0x0a,
+	0x04, 0x54, 0x45, 0x53, 0x54, 0x10, 0x64, 0x12, 0x09, 0x0a, 0x05, 0x54, 0x45, 0x53, 0x54, 0x31,
+	0x10, 0x65, 0x12, 0x09, 0x0a, 0x05, 0x54, 0x45, 0x53, 0x54, 0x32, 0x10, 0x66, 0x12, 0x09, 0x0a,
+	0x05, 0x54, 0x45, 0x53, 0x54, 0x33, 0x10, 0x67, 0x12, 0x09, 0x0a, 0x05, 0x54, 0x45, 0x53, 0x54,
+	0x34, 0x10, 0x68, 0x2a, 0xcc, 0x08, 0x0a, 0x0b, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x10, 0x00,
+	0x12, 0x08, 0x0a, 0x04, 0x41, 0x55, 0x54, 0x48, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x43, 0x52,
+	0x45, 0x41, 0x54, 0x45, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x10, 0x03, 0x12, 0x12,
+	0x0a, 0x0e, 0x52, 0x45, 0x4d, 0x4f, 0x56, 0x45, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c,
+	0x10, 0x04, 0x12, 0x10, 0x0a, 0x0c, 0x4c, 0x49, 0x53, 0x54, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e,
+	0x45, 0x4c, 0x10, 0x05, 0x12, 0x12, 0x0a, 0x0e, 0x53, 0x55, 0x42, 0x5f, 0x54, 0x4f, 0x5f, 0x43,
+	0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x10, 0x06, 0x12, 0x16, 0x0a, 0x12, 0x55, 0x4e, 0x53, 0x55,
+	0x42, 0x5f, 0x46, 0x52, 0x4f, 0x4d, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x10, 0x07,
+	0x12, 0x17, 0x0a, 0x13, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x44, 0x41, 0x54, 0x41,
+	0x5f, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x10, 0x08, 0x12, 0x0e, 0x0a, 0x0a, 0x44, 0x49, 0x53,
+	0x43, 0x4f, 0x4e, 0x4e, 0x45, 0x43, 0x54, 0x10, 0x09, 0x12, 0x1a, 0x0a, 0x16, 0x43, 0x52, 0x45,
+	0x41, 0x54, 0x45, 0x5f, 0x53, 0x50, 0x41, 0x54, 0x49, 0x41, 0x4c, 0x5f, 0x43, 0x48, 0x41, 0x4e,
+	0x4e, 0x45, 0x4c, 0x10, 0x0a, 0x12, 0x19, 0x0a, 0x15, 0x51, 0x55, 0x45, 0x52, 0x59, 0x5f, 0x53,
+	0x50, 0x41, 0x54, 0x49, 0x41, 0x4c, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x10, 0x0b,
+	0x12, 0x19, 0x0a, 0x15, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x44, 0x41, 0x54, 0x41,
+	0x5f, 0x48, 0x41, 0x4e, 0x44, 0x4f, 0x56, 0x45, 0x52, 0x10, 0x0c, 0x12, 0x1a, 0x0a, 0x16, 0x53,
+	0x50, 0x41, 0x54, 0x49, 0x41, 0x4c, 0x5f, 0x52, 0x45, 0x47, 0x49, 0x4f, 0x4e, 0x53, 0x5f, 0x55,
+	0x50, 0x44, 0x41, 0x54, 0x45, 0x10, 0x0d, 0x12, 0x1b, 0x0a, 0x17, 0x55, 0x50, 0x44, 0x41, 0x54,
+	0x45, 0x5f, 0x53, 0x50, 0x41, 0x54, 0x49, 0x41, 0x4c, 0x5f, 0x49, 0x4e, 0x54, 0x45, 0x52, 0x45,
+	0x53, 0x54, 0x10, 0x0e, 0x12, 0x19, 0x0a, 0x15, 0x43, 0x52, 0x45, 0x41, 0x54, 0x45, 0x5f, 0x45,
+	0x4e, 0x54, 0x49, 0x54, 0x59, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x10, 0x0f, 0x12,
+	0x14, 0x0a, 0x10, 0x45, 0x4e, 0x54, 0x49, 0x54, 0x59, 0x5f, 0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f,
+	0x41, 0x44, 0x44, 0x10, 0x10, 0x12, 0x17, 0x0a, 0x13, 0x45, 0x4e, 0x54, 0x49, 0x54, 0x59, 0x5f,
+	0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f, 0x52, 0x45, 0x4d, 0x4f, 0x56, 0x45, 0x10, 0x11, 0x12, 0x13,
+	0x0a, 0x0f, 0x53, 0x55, 0x42, 0x5f, 0x54, 0x4f, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c,
+	0x53, 0x10, 0x12, 0x12, 0x17, 0x0a, 0x13, 0x55, 0x4e, 0x53, 0x55, 0x42, 0x5f, 0x46, 0x52, 0x4f,
+	0x4d, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x53, 0x10, 0x13, 0x12, 0x17, 0x0a, 0x13,
+	0x53, 0x45, 0x54, 0x5f, 0x43, 0x4f, 0x4e, 0x4e, 0x45, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x54,
+	0x41, 0x47, 0x53, 0x10, 0x14, 0x12, 0x16, 0x0a, 0x12, 0x51, 0x55, 0x45, 0x52, 0x59, 0x5f, 0x43,
+	0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x44, 0x41, 0x54, 0x41, 0x10, 0x15, 0x12, 0x09, 0x0a,
+	0x05, 0x52, 0x45, 0x4c, 0x41, 0x59, 0x10, 0x16, 0x12, 0x11, 0x0a, 0x0d, 0x43, 0x48, 0x41, 0x4e,
+	0x4e, 0x45, 0x4c, 0x5f, 0x54, 0x49, 0x4d, 0x45, 0x52, 0x10, 0x17, 0x12, 0x19, 0x0a, 0x15, 0x43,
+	0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x44, 0x41, 0x54, 0x41, 0x5f, 0x43, 0x4f, 0x4e, 0x46,
+	0x4c, 0x49, 0x43, 0x54, 0x10, 0x18, 0x12, 0x15, 0x0a, 0x11, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45,
+	0x4c, 0x5f, 0x44, 0x41, 0x54, 0x41, 0x5f, 0x4c, 0x4f, 0x43, 0x4b, 0x10, 0x19, 0x12, 0x11, 0x0a,
+	0x0d, 0x46, 0x45, 0x54, 0x43, 0x48, 0x5f, 0x48, 0x49, 0x53, 0x54, 0x4f, 0x52, 0x59, 0x10, 0x1a,
+	0x12, 0x17, 0x0a, 0x13, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x44, 0x41, 0x54, 0x41,
+	0x5f, 0x52, 0x45, 0x53, 0x59, 0x4e, 0x43, 0x10, 0x1b, 0x12, 0x15, 0x0a, 0x11, 0x43, 0x48, 0x41,
+	0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x52, 0x45, 0x43, 0x4f, 0x56, 0x45, 0x52, 0x45, 0x44, 0x10, 0x1c,
+	0x12, 0x13, 0x0a, 0x0f, 0x44, 0x55, 0x50, 0x4c, 0x49, 0x43, 0x41, 0x54, 0x45, 0x5f, 0x4c, 0x4f,
+	0x47, 0x49, 0x4e, 0x10, 0x1d, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x4c, 0x4f, 0x57, 0x5f, 0x53, 0x55,
+	0x42, 0x53, 0x43, 0x52, 0x49, 0x42, 0x45, 0x52, 0x10, 0x1e, 0x12, 0x12, 0x0a, 0x0e, 0x51, 0x55,
+	0x4f, 0x54, 0x41, 0x5f, 0x45, 0x58, 0x43, 0x45, 0x45, 0x44, 0x45, 0x44, 0x10, 0x1f, 0x12, 0x11,
+	0x0a, 0x0d, 0x46, 0x45, 0x54, 0x43, 0x48, 0x5f, 0x41, 0x52, 0x43, 0x48, 0x49, 0x56, 0x45, 0x10,
+	0x20, 0x12, 0x13, 0x0a, 0x0f, 0x50, 0x52, 0x45, 0x53, 0x45, 0x4e, 0x43, 0x45, 0x5f, 0x55, 0x50,
+	0x44, 0x41, 0x54, 0x45, 0x10, 0x21, 0x12, 0x15, 0x0a, 0x11, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45,
+	0x5f, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x10, 0x22, 0x12, 0x10, 0x0a,
+	0x0c, 0x43, 0x48, 0x41, 0x54, 0x5f, 0x50, 0x45, 0x4e, 0x41, 0x4c, 0x54, 0x59, 0x10, 0x23, 0x12,
+	0x15, 0x0a, 0x11, 0x46, 0x45, 0x54, 0x43, 0x48, 0x5f, 0x4c, 0x45, 0x41, 0x44, 0x45, 0x52, 0x42,
+	0x4f, 0x41, 0x52, 0x44, 0x10, 0x24, 0x12, 0x1c, 0x0a, 0x18, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45,
+	0x4c, 0x5f, 0x44, 0x41, 0x54, 0x41, 0x5f, 0x54, 0x52, 0x41, 0x4e, 0x53, 0x41, 0x43, 0x54, 0x49,
+	0x4f, 0x4e, 0x10, 0x25, 0x12, 0x08, 0x0a, 0x04, 0x50, 0x49, 0x4e, 0x47, 0x10, 0x26, 0x12, 0x19,
+	0x0a, 0x15, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x4f, 0x57, 0x4e, 0x45, 0x52, 0x5f,
+	0x43, 0x48, 0x41, 0x4e, 0x47, 0x45, 0x44, 0x10, 0x27, 0x12, 0x0d, 0x0a, 0x09, 0x41, 0x4e, 0x41,
+	0x4c, 0x59, 0x54, 0x49, 0x43, 0x53, 0x10, 0x28, 0x12, 0x15, 0x0a, 0x11, 0x4d, 0x45, 0x53, 0x53,
+	0x41, 0x47, 0x45, 0x5f, 0x54, 0x4f, 0x4f, 0x5f, 0x4c, 0x41, 0x52, 0x47, 0x45, 0x10, 0x29, 0x12,
+	0x11, 0x0a, 0x0d, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x53,
+	0x10, 0x2a, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x2b, 0x12, 0x16, 0x0a,
+	0x12, 0x50, 0x41, 0x55, 0x53, 0x45, 0x5f, 0x44, 0x41, 0x54, 0x41, 0x5f, 0x46, 0x41, 0x4e, 0x5f,
+	0x4f, 0x55, 0x54, 0x10, 0x2c, 0x12, 0x14, 0x0a, 0x10, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c,
+	0x5f, 0x54, 0x49, 0x43, 0x4b, 0x5f, 0x4c, 0x41, 0x47, 0x10, 0x2d, 0x12, 0x10, 0x0a, 0x0c, 0x53,
+	0x45, 0x52, 0x56, 0x45, 0x52, 0x5f, 0x42, 0x41, 0x54, 0x43, 0x48, 0x10, 0x2e, 0x12, 0x14, 0x0a,
+	0x10, 0x42, 0x41, 0x4e, 0x44, 0x57, 0x49, 0x44, 0x54, 0x48, 0x5f, 0x52, 0x45, 0x50, 0x4f, 0x52,
+	0x54, 0x10, 0x2f, 0x12, 0x18, 0x0a, 0x14, 0x53, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x4b,
+	0x45, 0x59, 0x5f, 0x52, 0x4f, 0x54, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x30, 0x12, 0x1d, 0x0a,
+	0x19, 0x44, 0x45, 0x42, 0x55, 0x47, 0x5f, 0x47, 0x45, 0x54, 0x5f, 0x53, 0x50, 0x41, 0x54, 0x49,
+	0x41, 0x4c, 0x5f, 0x52, 0x45, 0x47, 0x49, 0x4f, 0x4e, 0x53, 0x10, 0x63, 0x12, 0x14, 0x0a, 0x10,
+	0x55, 0x53, 0x45, 0x52, 0x5f, 0x53, 0x50, 0x41, 0x43, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x52, 0x54,
+	0x10, 0x64, 0x2a, 0x37, 0x0a, 0x10, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74,
+	0x61, 0x43, 0x6f, 0x64, 0x65, 0x63, 0x12, 0x0c, 0x0a, 0x08, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x42,
+	0x55, 0x46, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x4a, 0x53, 0x4f, 0x4e, 0x10, 0x01, 0x12, 0x0b,
+	0x0a, 0x07, 0x4d, 0x53, 0x47, 0x50, 0x41, 0x43, 0x4b, 0x10, 0x02, 0x2a, 0x31, 0x0a, 0x0f, 0x43,
+	0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12,
+	0x0a, 0x0e, 0x4e, 0x4f, 0x5f, 0x43, 0x4f, 0x4d, 0x50, 0x52, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e,
+	0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x4e, 0x41, 0x50, 0x50, 0x59, 0x10, 0x01, 0x2a, 0x45,
+	0x0a, 0x11, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x41, 0x63, 0x63,
+	0x65, 0x73, 0x73, 0x12, 0x0d, 0x0a, 0x09, 0x4e, 0x4f, 0x5f, 0x41, 0x43, 0x43, 0x45, 0x53, 0x53,
+	0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x52, 0x45, 0x41, 0x44, 0x5f, 0x41, 0x43, 0x43, 0x45, 0x53,
+	0x53, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x57, 0x52, 0x49, 0x54, 0x45, 0x5f, 0x41, 0x43, 0x43,
+	0x45, 0x53, 0x53, 0x10, 0x02, 0x2a, 0x29, 0x0a, 0x0f, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0c, 0x0a, 0x08, 0x48, 0x41, 0x4e, 0x44,
+	0x4f, 0x56, 0x45, 0x52, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x4c, 0x4f, 0x43, 0x4b, 0x10, 0x01,
+	0x42, 0x30, 0x5a, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d,
+	0x65, 0x74, 0x61, 0x77, 0x6f, 0x72, 0x6b, 0x69, 0x6e, 0x67, 0x2f, 0x63, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x64, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x64,
+	0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_channeld_proto_rawDescOnce sync.Once
+	file_channeld_proto_rawDescData = file_channeld_proto_rawDesc
+)
+
+func file_channeld_proto_rawDescGZIP() []byte {
+	file_channeld_proto_rawDescOnce.Do(func() {
+		file_channeld_proto_rawDescData = protoimpl.X.CompressGZIP(file_channeld_proto_rawDescData)
+	})
+	return file_channeld_proto_rawDescData
+}
+
+var file_channeld_proto_enumTypes = make([]protoimpl.EnumInfo, 16)
+var file_channeld_proto_msgTypes = make([]protoimpl.MessageInfo, 86)
+var file_channeld_proto_goTypes = []interface{}{
+	(BroadcastType)(0),                              // 0: channeldpb.BroadcastType
+	(ConnectionType)(0),                             // 1: channeldpb.ConnectionType
+	(ChannelType)(0),                                // 2: channeldpb.ChannelType
+	(MessageType)(0),                                // 3: channeldpb.MessageType
+	(ChannelDataCodec)(0),                           // 4: channeldpb.ChannelDataCodec
+	(CompressionType)(0),                            // 5: channeldpb.CompressionType
+	(ChannelDataAccess)(0),                          // 6: channeldpb.ChannelDataAccess
+	(EntityGroupType)(0),                            // 7: channeldpb.EntityGroupType
+	(AuthResultMessage_AuthResult)(0),               // 8: channeldpb.AuthResultMessage.AuthResult
+	(ErrorMessage_Code)(0),                          // 9: channeldpb.ErrorMessage.Code
+	(ChatPenaltyMessage_Reason)(0),                  // 10: channeldpb.ChatPenaltyMessage.Reason
+	(QuotaExceededMessage_QuotaType)(0),             // 11: channeldpb.QuotaExceededMessage.QuotaType
+	(ChannelDataTransactionMessage_Mutation_Op)(0),  // 12: channeldpb.ChannelDataTransactionMessage.Mutation.Op
+	(ChannelDataTransactionResultMessage_Result)(0), // 13: channeldpb.ChannelDataTransactionResultMessage.Result
+	(RelayMessage_Event)(0),                         // 14: channeldpb.RelayMessage.Event
+	(DisconnectMessage_Reason)(0),                   // 15: channeldpb.DisconnectMessage.Reason
+	(*Packet)(nil),                                  // 16: channeldpb.Packet
+	(*MessagePack)(nil),                             // 17: channeldpb.MessagePack
+	(*BrokerMessage)(nil),                           // 18: channeldpb.BrokerMessage
+	(*ServerForwardMessage)(nil),                    // 19: channeldpb.ServerForwardMessage
+	(*AuthMessage)(nil),                             // 20: channeldpb.AuthMessage
+	(*AuthResultMessage)(nil),                       // 21: channeldpb.AuthResultMessage
+	(*ChannelSubscriptionOptions)(nil),              // 22: channeldpb.ChannelSubscriptionOptions
+	(*ChannelDataMergeOptions)(nil),                 // 23: channeldpb.ChannelDataMergeOptions
+	(*CreateChannelMessage)(nil),                    // 24: channeldpb.CreateChannelMessage
+	(*CreateChannelResultMessage)(nil),              // 25: channeldpb.CreateChannelResultMessage
+	(*RemoveChannelMessage)(nil),                    // 26: channeldpb.RemoveChannelMessage
+	(*ListChannelMessage)(nil),                      // 27: channeldpb.ListChannelMessage
+	(*ListChannelResultMessage)(nil),                // 28: channeldpb.ListChannelResultMessage
+	(*SubscribedToChannelMessage)(nil),              // 29: channeldpb.SubscribedToChannelMessage
+	(*SubscribedToChannelResultMessage)(nil),        // 30: channeldpb.SubscribedToChannelResultMessage
+	(*UnsubscribedFromChannelMessage)(nil),          // 31: channeldpb.UnsubscribedFromChannelMessage
+	(*UnsubscribedFromChannelResultMessage)(nil),    // 32: channeldpb.UnsubscribedFromChannelResultMessage
+	(*SubscribedToChannelsMessage)(nil),             // 33: channeldpb.SubscribedToChannelsMessage
+	(*SubscribedToChannelsResultMessage)(nil),       // 34: channeldpb.SubscribedToChannelsResultMessage
+	(*UnsubscribedFromChannelsMessage)(nil),         // 35: channeldpb.UnsubscribedFromChannelsMessage
+	(*UnsubscribedFromChannelsResultMessage)(nil),   // 36: channeldpb.UnsubscribedFromChannelsResultMessage
+	(*SetConnectionTagsMessage)(nil),                // 37: channeldpb.SetConnectionTagsMessage
+	(*UpdateBlockListMessage)(nil),                  // 38: channeldpb.UpdateBlockListMessage
+	(*ChannelDataUpdateMessage)(nil),                // 39: channeldpb.ChannelDataUpdateMessage
+	(*ChannelRecoveredMessage)(nil),                 // 40: channeldpb.ChannelRecoveredMessage
+	(*ChannelOwnerChangedMessage)(nil),              // 41: channeldpb.ChannelOwnerChangedMessage
+	(*AnalyticsMessage)(nil),                        // 42: channeldpb.AnalyticsMessage
+	(*BandwidthReportMessage)(nil),                  // 43: channeldpb.BandwidthReportMessage
+	(*SessionKeyRotationMessage)(nil),               // 44: channeldpb.SessionKeyRotationMessage
+	(*MessageTooLargeMessage)(nil),                  // 45: channeldpb.MessageTooLargeMessage
+	(*ErrorMessage)(nil),                            // 46: channeldpb.ErrorMessage
+	(*ChannelStatsMessage)(nil),                     // 47: channeldpb.ChannelStatsMessage
+	(*DuplicateLoginMessage)(nil),                   // 48: channeldpb.DuplicateLoginMessage
+	(*ChannelTickLagMessage)(nil),                   // 49: channeldpb.ChannelTickLagMessage
+	(*ServerBatchMessage)(nil),                      // 50: channeldpb.ServerBatchMessage
+	(*SlowSubscriberMessage)(nil),                   // 51: channeldpb.SlowSubscriberMessage
+	(*ChatPenaltyMessage)(nil),                      // 52: channeldpb.ChatPenaltyMessage
+	(*QuotaExceededMessage)(nil),                    // 53: channeldpb.QuotaExceededMessage
+	(*PauseDataFanOutMessage)(nil),                  // 54: channeldpb.PauseDataFanOutMessage
+	(*ChannelDataResyncMessage)(nil),                // 55: channeldpb.ChannelDataResyncMessage
+	(*ChannelDataConflictMessage)(nil),              // 56: channeldpb.ChannelDataConflictMessage
+	(*ChannelDataLockMessage)(nil),                  // 57: channeldpb.ChannelDataLockMessage
+	(*ChannelDataLockResultMessage)(nil),            // 58: channeldpb.ChannelDataLockResultMessage
+	(*QueryChannelDataMessage)(nil),                 // 59: channeldpb.QueryChannelDataMessage
+	(*ChannelDataTransactionMessage)(nil),           // 60: channeldpb.ChannelDataTransactionMessage
+	(*ChannelDataTransactionResultMessage)(nil),     // 61: channeldpb.ChannelDataTransactionResultMessage
+	(*FetchHistoryMessage)(nil),                     // 62: channeldpb.FetchHistoryMessage
+	(*FetchHistoryResultMessage)(nil),               // 63: channeldpb.FetchHistoryResultMessage
+	(*FetchLeaderboardMessage)(nil),                 // 64: channeldpb.FetchLeaderboardMessage
+	(*FetchLeaderboardResultMessage)(nil),           // 65: channeldpb.FetchLeaderboardResultMessage
+	(*ArchivedMessage)(nil),                         // 66: channeldpb.ArchivedMessage
+	(*FetchArchiveMessage)(nil),                     // 67: channeldpb.FetchArchiveMessage
+	(*FetchArchiveResultMessage)(nil),               // 68: channeldpb.FetchArchiveResultMessage
+	(*PresenceInfo)(nil),                            // 69: channeldpb.PresenceInfo
+	(*PresenceUpdateMessage)(nil),                   // 70: channeldpb.PresenceUpdateMessage
+	(*QueryChannelDataResultMessage)(nil),           // 71: channeldpb.QueryChannelDataResultMessage
+	(*RelayMessage)(nil),                            // 72: channeldpb.RelayMessage
+	(*ChannelTimerMessage)(nil),                     // 73: channeldpb.ChannelTimerMessage
+	(*TimerFiredMessage)(nil),                       // 74: channeldpb.TimerFiredMessage
+	(*PingMessage)(nil),                             // 75: channeldpb.PingMessage
+	(*DisconnectMessage)(nil),                       // 76: channeldpb.DisconnectMessage
+	(*SpatialInfo)(nil),                             // 77: channeldpb.SpatialInfo
+	(*CreateSpatialChannelsResultMessage)(nil),      // 78: channeldpb.CreateSpatialChannelsResultMessage
+	(*QuerySpatialChannelMessage)(nil),              // 79: channeldpb.QuerySpatialChannelMessage
+	(*QuerySpatialChannelResultMessage)(nil),        // 80: channeldpb.QuerySpatialChannelResultMessage
+	(*ChannelDataHandoverMessage)(nil),              // 81: channeldpb.ChannelDataHandoverMessage
+	(*SpatialRegion)(nil),                           // 82: channeldpb.SpatialRegion
+	(*SpatialRegionsUpdateMessage)(nil),             // 83: channeldpb.SpatialRegionsUpdateMessage
+	(*SpatialInterestQuery)(nil),                    // 84: channeldpb.SpatialInterestQuery
+	(*UpdateSpatialInterestMessage)(nil),            // 85: channeldpb.UpdateSpatialInterestMessage
+	(*CreateEntityChannelMessage)(nil),              // 86: channeldpb.CreateEntityChannelMessage
+	(*AddEntityGroupMessage)(nil),                   // 87: channeldpb.AddEntityGroupMessage
+	(*RemoveEntityGroupMessage)(nil),                // 88: channeldpb.RemoveEntityGroupMessage
+	(*DebugGetSpatialRegionsMessage)(nil),           // 89: channeldpb.DebugGetSpatialRegionsMessage
+	(*ListChannelResultMessage_ChannelInfo)(nil),    // 90: channeldpb.ListChannelResultMessage.ChannelInfo
+	nil,                              // 91: channeldpb.SubscribedToChannelsMessage.ChannelSubOptionsEntry
+	nil,                              // 92: channeldpb.SubscribedToChannelsResultMessage.SubOptionsEntry
+	nil,                              // 93: channeldpb.SetConnectionTagsMessage.TagsEntry
+	nil,                              // 94: channeldpb.AnalyticsMessage.PropertiesEntry
+	(*ServerBatchMessage_Entry)(nil), // 95: channeldpb.ServerBatchMessage.Entry
+	(*ChannelDataTransactionMessage_Mutation)(nil), // 96: channeldpb.ChannelDataTransactionMessage.Mutation
+	nil,                                    // 97: channeldpb.PresenceUpdateMessage.UsersEntry
+	(*SpatialInterestQuery_SpotsAOI)(nil),  // 98: channeldpb.SpatialInterestQuery.SpotsAOI
+	(*SpatialInterestQuery_BoxAOI)(nil),    // 99: channeldpb.SpatialInterestQuery.BoxAOI
+	(*SpatialInterestQuery_SphereAOI)(nil), // 100: channeldpb.SpatialInterestQuery.SphereAOI
+	(*SpatialInterestQuery_ConeAOI)(nil),   // 101: channeldpb.SpatialInterestQuery.ConeAOI
+	(*anypb.Any)(nil),                      // 102: google.protobuf.Any
+}
+var file_channeld_proto_depIdxs = []int32{
+	17,  // 0: channeldpb.Packet.messages:type_name -> channeldpb.MessagePack
+	17,  // 1: channeldpb.BrokerMessage.pack:type_name -> channeldpb.MessagePack
+	4,   // 2: channeldpb.AuthMessage.channelDataCodec:type_name -> channeldpb.ChannelDataCodec
+	8,   // 3: channeldpb.AuthResultMessage.result:type_name -> channeldpb.AuthResultMessage.AuthResult
+	5,   // 4: channeldpb.AuthResultMessage.compressionType:type_name -> channeldpb.CompressionType
+	4,   // 5: channeldpb.AuthResultMessage.channelDataCodec:type_name -> channeldpb.ChannelDataCodec
+	6,   // 6: channeldpb.ChannelSubscriptionOptions.dataAccess:type_name -> channeldpb.ChannelDataAccess
+	2,   // 7: channeldpb.CreateChannelMessage.channelType:type_name -> channeldpb.ChannelType
+	22,  // 8: channeldpb.CreateChannelMessage.subOptions:type_name -> channeldpb.ChannelSubscriptionOptions
+	102, // 9: channeldpb.CreateChannelMessage.data:type_name -> google.protobuf.Any
+	23,  // 10: channeldpb.CreateChannelMessage.mergeOptions:type_name -> channeldpb.ChannelDataMergeOptions
+	2,   // 11: channeldpb.CreateChannelResultMessage.channelType:type_name -> channeldpb.ChannelType
+	2,   // 12: channeldpb.ListChannelMessage.typeFilter:type_name -> channeldpb.ChannelType
+	90,  // 13: channeldpb.ListChannelResultMessage.channels:type_name -> channeldpb.ListChannelResultMessage.ChannelInfo
+	22,  // 14: channeldpb.SubscribedToChannelMessage.subOptions:type_name -> channeldpb.ChannelSubscriptionOptions
+	2,   // 15: channeldpb.SubscribedToChannelMessage.channelType:type_name -> channeldpb.ChannelType
+	22,  // 16: channeldpb.SubscribedToChannelResultMessage.subOptions:type_name -> channeldpb.ChannelSubscriptionOptions
+	1,   // 17: channeldpb.SubscribedToChannelResultMessage.connType:type_name -> channeldpb.ConnectionType
+	2,   // 18: channeldpb.SubscribedToChannelResultMessage.channelType:type_name -> channeldpb.ChannelType
+	1,   // 19: channeldpb.UnsubscribedFromChannelResultMessage.connType:type_name -> channeldpb.ConnectionType
+	2,   // 20: channeldpb.UnsubscribedFromChannelResultMessage.channelType:type_name -> channeldpb.ChannelType
+	22,  // 21: channeldpb.SubscribedToChannelsMessage.subOptions:type_name -> channeldpb.ChannelSubscriptionOptions
+	91,  // 22: channeldpb.SubscribedToChannelsMessage.channelSubOptions:type_name -> channeldpb.SubscribedToChannelsMessage.ChannelSubOptionsEntry
+	1,   // 23: channeldpb.SubscribedToChannelsResultMessage.connType:type_name -> channeldpb.ConnectionType
+	92,  // 24: channeldpb.SubscribedToChannelsResultMessage.subOptions:type_name -> channeldpb.SubscribedToChannelsResultMessage.SubOptionsEntry
+	1,   // 25: channeldpb.UnsubscribedFromChannelsResultMessage.connType:type_name -> channeldpb.ConnectionType
+	93,  // 26: channeldpb.SetConnectionTagsMessage.tags:type_name -> channeldpb.SetConnectionTagsMessage.TagsEntry
+	102, // 27: channeldpb.ChannelDataUpdateMessage.data:type_name -> google.protobuf.Any
+	94,  // 28: channeldpb.AnalyticsMessage.properties:type_name -> channeldpb.AnalyticsMessage.PropertiesEntry
+	9,   // 29: channeldpb.ErrorMessage.code:type_name -> channeldpb.ErrorMessage.Code
+	95,  // 30: channeldpb.ServerBatchMessage.entries:type_name -> channeldpb.ServerBatchMessage.Entry
+	10,  // 31: channeldpb.ChatPenaltyMessage.reason:type_name -> channeldpb.ChatPenaltyMessage.Reason
+	11,  // 32: channeldpb.QuotaExceededMessage.quotaType:type_name -> channeldpb.QuotaExceededMessage.QuotaType
+	2,   // 33: channeldpb.QuotaExceededMessage.channelType:type_name -> channeldpb.ChannelType
+	102, // 34: channeldpb.ChannelDataConflictMessage.data:type_name -> google.protobuf.Any
+	96,  // 35: channeldpb.ChannelDataTransactionMessage.mutations:type_name -> channeldpb.ChannelDataTransactionMessage.Mutation
+	13,  // 36: channeldpb.ChannelDataTransactionResultMessage.result:type_name -> channeldpb.ChannelDataTransactionResultMessage.Result
+	102, // 37: channeldpb.FetchHistoryResultMessage.data:type_name -> google.protobuf.Any
+	102, // 38: channeldpb.FetchLeaderboardResultMessage.data:type_name -> google.protobuf.Any
+	66,  // 39: channeldpb.FetchArchiveResultMessage.messages:type_name -> channeldpb.ArchivedMessage
+	2,   // 40: channeldpb.PresenceInfo.channelType:type_name -> channeldpb.ChannelType
+	97,  // 41: channeldpb.PresenceUpdateMessage.users:type_name -> channeldpb.PresenceUpdateMessage.UsersEntry
+	102, // 42: channeldpb.QueryChannelDataResultMessage.data:type_name -> google.protobuf.Any
+	14,  // 43: channeldpb.RelayMessage.event:type_name -> channeldpb.RelayMessage.Event
+	15,  // 44: channeldpb.DisconnectMessage.reason:type_name -> channeldpb.DisconnectMessage.Reason
+	77,  // 45: channeldpb.QuerySpatialChannelMessage.spatialInfo:type_name -> channeldpb.SpatialInfo
+	102, // 46: channeldpb.ChannelDataHandoverMessage.data:type_name -> google.protobuf.Any
+	77,  // 47: channeldpb.SpatialRegion.min:type_name -> channeldpb.SpatialInfo
+	77,  // 48: channeldpb.SpatialRegion.max:type_name -> channeldpb.SpatialInfo
+	82,  // 49: channeldpb.SpatialRegionsUpdateMessage.regions:type_name -> channeldpb.SpatialRegion
+	98,  // 50: channeldpb.SpatialInterestQuery.spotsAOI:type_name -> channeldpb.SpatialInterestQuery.SpotsAOI
+	99,  // 51: channeldpb.SpatialInterestQuery.boxAOI:type_name -> channeldpb.SpatialInterestQuery.BoxAOI
+	100, // 52: channeldpb.SpatialInterestQuery.sphereAOI:type_name -> channeldpb.SpatialInterestQuery.SphereAOI
+	101, // 53: channeldpb.SpatialInterestQuery.coneAOI:type_name -> channeldpb.SpatialInterestQuery.ConeAOI
+	84,  // 54: channeldpb.UpdateSpatialInterestMessage.query:type_name -> channeldpb.SpatialInterestQuery
+	22,  // 55: channeldpb.CreateEntityChannelMessage.subOptions:type_name -> channeldpb.ChannelSubscriptionOptions
+	102, // 56: channeldpb.CreateEntityChannelMessage.data:type_name -> google.protobuf.Any
+	23,  // 57: channeldpb.CreateEntityChannelMessage.mergeOptions:type_name -> channeldpb.ChannelDataMergeOptions
+	7,   // 58: channeldpb.AddEntityGroupMessage.type:type_name -> channeldpb.EntityGroupType
+	7,   // 59: channeldpb.RemoveEntityGroupMessage.type:type_name -> channeldpb.EntityGroupType
+	2,   // 60: channeldpb.ListChannelResultMessage.ChannelInfo.channelType:type_name -> channeldpb.ChannelType
+	22,  // 61: channeldpb.SubscribedToChannelsMessage.ChannelSubOptionsEntry.value:type_name -> channeldpb.ChannelSubscriptionOptions
+	22,  // 62: channeldpb.SubscribedToChannelsResultMessage.SubOptionsEntry.value:type_name -> channeldpb.ChannelSubscriptionOptions
+	12,  // 63: channeldpb.ChannelDataTransactionMessage.Mutation.op:type_name -> channeldpb.ChannelDataTransactionMessage.Mutation.Op
+	102, // 64: channeldpb.ChannelDataTransactionMessage.Mutation.data:type_name -> google.protobuf.Any
+	69,  // 65: channeldpb.PresenceUpdateMessage.UsersEntry.value:type_name -> channeldpb.PresenceInfo
+	77,  // 66: channeldpb.SpatialInterestQuery.SpotsAOI.spots:type_name -> channeldpb.SpatialInfo
+	77,  // 67: channeldpb.SpatialInterestQuery.BoxAOI.center:type_name -> channeldpb.SpatialInfo
+	77,  // 68: channeldpb.SpatialInterestQuery.BoxAOI.extent:type_name -> channeldpb.SpatialInfo
+	77,  // 69: channeldpb.SpatialInterestQuery.SphereAOI.center:type_name -> channeldpb.SpatialInfo
+	77,  // 70: channeldpb.SpatialInterestQuery.ConeAOI.center:type_name -> channeldpb.SpatialInfo
+	77,  // 71: channeldpb.SpatialInterestQuery.ConeAOI.direction:type_name -> channeldpb.SpatialInfo
+	72,  // [72:72] is the sub-list for method output_type
+	72,  // [72:72] is the sub-list for method input_type
+	72,  // [72:72] is the sub-list for extension type_name
+	72,  // [72:72] is the sub-list for extension extendee
+	0,   // [0:72] is the sub-list for field type_name
+}
+
+func init() { file_channeld_proto_init() }
+func file_channeld_proto_init() {
+	if File_channeld_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_channeld_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Packet); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessagePack); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BrokerMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ServerForwardMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuthMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuthResultMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelSubscriptionOptions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelDataMergeOptions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateChannelMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateChannelResultMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveChannelMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListChannelMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListChannelResultMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribedToChannelMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribedToChannelResultMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnsubscribedFromChannelMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnsubscribedFromChannelResultMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribedToChannelsMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribedToChannelsResultMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnsubscribedFromChannelsMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnsubscribedFromChannelsResultMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetConnectionTagsMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateBlockListMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelDataUpdateMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelRecoveredMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelOwnerChangedMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AnalyticsMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BandwidthReportMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SessionKeyRotationMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessageTooLargeMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ErrorMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelStatsMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DuplicateLoginMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelTickLagMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ServerBatchMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SlowSubscriberMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChatPenaltyMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QuotaExceededMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3304,8 +9040,8 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MessagePack); i {
+		file_channeld_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PauseDataFanOutMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3316,8 +9052,8 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ServerForwardMessage); i {
+		file_channeld_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelDataResyncMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3328,8 +9064,8 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AuthMessage); i {
+		file_channeld_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelDataConflictMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3340,8 +9076,8 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AuthResultMessage); i {
+		file_channeld_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelDataLockMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3352,8 +9088,8 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ChannelSubscriptionOptions); i {
+		file_channeld_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelDataLockResultMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3364,8 +9100,8 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ChannelDataMergeOptions); i {
+		file_channeld_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryChannelDataMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3376,8 +9112,8 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateChannelMessage); i {
+		file_channeld_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelDataTransactionMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3388,8 +9124,8 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateChannelResultMessage); i {
+		file_channeld_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelDataTransactionResultMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3400,8 +9136,8 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RemoveChannelMessage); i {
+		file_channeld_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchHistoryMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3412,8 +9148,8 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListChannelMessage); i {
+		file_channeld_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchHistoryResultMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3424,8 +9160,8 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListChannelResultMessage); i {
+		file_channeld_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchLeaderboardMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3436,8 +9172,8 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SubscribedToChannelMessage); i {
+		file_channeld_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchLeaderboardResultMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3448,8 +9184,8 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SubscribedToChannelResultMessage); i {
+		file_channeld_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ArchivedMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3460,8 +9196,8 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UnsubscribedFromChannelMessage); i {
+		file_channeld_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchArchiveMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3472,8 +9208,8 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UnsubscribedFromChannelResultMessage); i {
+		file_channeld_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchArchiveResultMessage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3484,8 +9220,8 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ChannelDataUpdateMessage); i {
+		file_channeld_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PresenceInfo); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3496,7 +9232,79 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+		file_channeld_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PresenceUpdateMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[55].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryChannelDataResultMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[56].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RelayMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[57].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelTimerMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[58].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TimerFiredMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[59].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PingMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[60].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DisconnectMessage); i {
 			case 0:
 				return &v.state
@@ -3508,7 +9316,7 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+		file_channeld_proto_msgTypes[61].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SpatialInfo); i {
 			case 0:
 				return &v.state
@@ -3520,7 +9328,7 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+		file_channeld_proto_msgTypes[62].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*CreateSpatialChannelsResultMessage); i {
 			case 0:
 				return &v.state
@@ -3532,7 +9340,7 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+		file_channeld_proto_msgTypes[63].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*QuerySpatialChannelMessage); i {
 			case 0:
 				return &v.state
@@ -3544,7 +9352,7 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+		file_channeld_proto_msgTypes[64].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*QuerySpatialChannelResultMessage); i {
 			case 0:
 				return &v.state
@@ -3556,7 +9364,7 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+		file_channeld_proto_msgTypes[65].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ChannelDataHandoverMessage); i {
 			case 0:
 				return &v.state
@@ -3568,7 +9376,7 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+		file_channeld_proto_msgTypes[66].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SpatialRegion); i {
 			case 0:
 				return &v.state
@@ -3580,7 +9388,7 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+		file_channeld_proto_msgTypes[67].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SpatialRegionsUpdateMessage); i {
 			case 0:
 				return &v.state
@@ -3592,7 +9400,7 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+		file_channeld_proto_msgTypes[68].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SpatialInterestQuery); i {
 			case 0:
 				return &v.state
@@ -3604,7 +9412,7 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+		file_channeld_proto_msgTypes[69].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*UpdateSpatialInterestMessage); i {
 			case 0:
 				return &v.state
@@ -3616,7 +9424,7 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+		file_channeld_proto_msgTypes[70].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*CreateEntityChannelMessage); i {
 			case 0:
 				return &v.state
@@ -3628,7 +9436,7 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+		file_channeld_proto_msgTypes[71].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*AddEntityGroupMessage); i {
 			case 0:
 				return &v.state
@@ -3640,7 +9448,7 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+		file_channeld_proto_msgTypes[72].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RemoveEntityGroupMessage); i {
 			case 0:
 				return &v.state
@@ -3652,7 +9460,7 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+		file_channeld_proto_msgTypes[73].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DebugGetSpatialRegionsMessage); i {
 			case 0:
 				return &v.state
@@ -3664,7 +9472,7 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+		file_channeld_proto_msgTypes[74].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ListChannelResultMessage_ChannelInfo); i {
 			case 0:
 				return &v.state
@@ -3676,7 +9484,31 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+		file_channeld_proto_msgTypes[79].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ServerBatchMessage_Entry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[80].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelDataTransactionMessage_Mutation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_channeld_proto_msgTypes[82].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SpatialInterestQuery_SpotsAOI); i {
 			case 0:
 				return &v.state
@@ -3688,7 +9520,7 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+		file_channeld_proto_msgTypes[83].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SpatialInterestQuery_BoxAOI); i {
 			case 0:
 				return &v.state
@@ -3700,7 +9532,7 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+		file_channeld_proto_msgTypes[84].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SpatialInterestQuery_SphereAOI); i {
 			case 0:
 				return &v.state
@@ -3712,7 +9544,7 @@ func file_channeld_proto_init() {
 				return nil
 			}
 		}
-		file_channeld_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+		file_channeld_proto_msgTypes[85].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SpatialInterestQuery_ConeAOI); i {
 			case 0:
 				return &v.state
@@ -3725,15 +9557,18 @@ func file_channeld_proto_init() {
 			}
 		}
 	}
-	file_channeld_proto_msgTypes[5].OneofWrappers = []interface{}{}
-	file_channeld_proto_msgTypes[25].OneofWrappers = []interface{}{}
+	file_channeld_proto_msgTypes[6].OneofWrappers = []interface{}{}
+	file_channeld_proto_msgTypes[13].OneofWrappers = []interface{}{}
+	file_channeld_proto_msgTypes[23].OneofWrappers = []interface{}{}
+	file_channeld_proto_msgTypes[68].OneofWrappers = []interface{}{}
+	file_channeld_proto_msgTypes[80].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_channeld_proto_rawDesc,
-			NumEnums:      8,
-			NumMessages:   36,
+			NumEnums:      16,
+			NumMessages:   86,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
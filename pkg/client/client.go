@@ -26,12 +26,22 @@ type messageQueueEntry struct {
 	channelId uint32
 	stubId    uint32
 	handlers  []MessageHandlerFunc
+	// Only set when the raw message handler is set.
+	pack *channeldpb.MessagePack
 }
 
+// Handles the message in the wire format. See ChanneldClient.SetRawMessageHandler().
+type RawMessageHandlerFunc func(client *ChanneldClient, mp *channeldpb.MessagePack)
+
 // Go library for writing game client/server that interations with channeld.
 type ChanneldClient struct {
 	Id                 uint32
 	CompressionType    channeldpb.CompressionType
+	WireVersion        channeld.WireVersion // The wire format to send in. channeld replies in v2 if it accepts v2.
+	ProtocolVersion    uint32               // The protocol version that channeld uses for the client. Set when authenticated.
+	ResumeToken        string               // Only for the server connection. Set when authenticated. See Resume().
+	MaxPacketSize      uint32               // The max packet size to request when authenticating. Set to the negotiated one when authenticated. 0 means channeld.MaxPacketSize.
+	EnableEncryption   bool                 // Encrypts the packets in both directions with the session keys. Should be set before Auth(). Requires WireVersion_V2. Doesn't authenticate channeld, see channeldpb.AuthResultMessage.EncryptionPublicKey.
 	SubscribedChannels map[uint32]struct{}
 	CreatedChannels    map[uint32]struct{}
 	ListedChannels     map[uint32]struct{}
@@ -43,32 +53,42 @@ type ChanneldClient struct {
 	outgoingQueue      chan *channeldpb.MessagePack
 	messageMap         map[uint32]*messageMapEntry
 	stubCallbacks      map[uint32]MessageHandlerFunc
+	rawMessageHandler  RawMessageHandlerFunc
+	chunkAssembler     channeld.MessagePackAssembler
+	sessionPrivateKey  []byte
+	sessionKeys        map[uint32]*channeld.SessionKey // The current and the previous session keys, by the key id. Only used in Receive().
+	sendSessionKey     *channeld.SessionKey            // The latest session key to encrypt the packets sent. Guarded by writeMutex.
 	writeMutex         sync.Mutex
 }
 
 func NewClient(addr string) (*ChanneldClient, error) {
+	return NewClientWithOptions(addr, DialOptions{})
+}
+
+// Connects to channeld with the options, e.g. through the proxy of the corporate network.
+func NewClientWithOptions(addr string, options DialOptions) (*ChanneldClient, error) {
 	var conn net.Conn
+	var err error
 	if strings.HasPrefix(addr, "ws") {
-		c, _, err := websocket.DefaultDialer.Dial(addr, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		conn = &wsConn{conn: c}
+		conn, err = dialWebSocket(addr, options)
 	} else {
-		var err error
-		conn, err = net.Dial("tcp", addr)
-		if err != nil {
-			return nil, err
-		}
+		conn, err = dialTCP(addr, options)
+	}
+	if err != nil {
+		return nil, err
 	}
+	return newClient(conn), nil
+}
+
+func newClient(conn net.Conn) *ChanneldClient {
 	c := &ChanneldClient{
 		CompressionType:    channeldpb.CompressionType_NO_COMPRESSION,
+		WireVersion:        channeld.WireVersion_V1,
 		SubscribedChannels: make(map[uint32]struct{}),
 		CreatedChannels:    make(map[uint32]struct{}),
 		ListedChannels:     make(map[uint32]struct{}),
 		Conn:               conn,
-		readBuffer:         make([]byte, channeld.MaxPacketSize),
+		readBuffer:         make([]byte, channeld.MaxPacketSize+channeld.MaxPacketHeaderSize),
 		readPos:            0,
 		connected:          true,
 		incomingQueue:      make(chan messageQueueEntry, 128),
@@ -86,9 +106,33 @@ func NewClient(addr string) (*ChanneldClient, error) {
 	c.SetMessageEntry(uint32(channeldpb.MessageType_SUB_TO_CHANNEL), &channeldpb.SubscribedToChannelResultMessage{}, handleSubToChannel)
 	c.SetMessageEntry(uint32(channeldpb.MessageType_UNSUB_FROM_CHANNEL), &channeldpb.UnsubscribedFromChannelResultMessage{}, handleUnsubToChannel)
 	c.SetMessageEntry(uint32(channeldpb.MessageType_LIST_CHANNEL), &channeldpb.ListChannelResultMessage{}, handleListChannel)
+	c.SetMessageEntry(uint32(channeldpb.MessageType_SUB_TO_CHANNELS), &channeldpb.SubscribedToChannelsResultMessage{}, handleSubToChannels)
+	c.SetMessageEntry(uint32(channeldpb.MessageType_UNSUB_FROM_CHANNELS), &channeldpb.UnsubscribedFromChannelsResultMessage{}, handleUnsubFromChannels)
 	c.SetMessageEntry(uint32(channeldpb.MessageType_CHANNEL_DATA_UPDATE), &channeldpb.ChannelDataUpdateMessage{}, defaultMessageHandler)
-
-	return c, nil
+	c.SetMessageEntry(uint32(channeldpb.MessageType_QUERY_CHANNEL_DATA), &channeldpb.QueryChannelDataResultMessage{}, defaultMessageHandler)
+	c.SetMessageEntry(uint32(channeldpb.MessageType_CHANNEL_TIMER), &channeldpb.TimerFiredMessage{}, defaultMessageHandler)
+	c.SetMessageEntry(uint32(channeldpb.MessageType_CHANNEL_DATA_CONFLICT), &channeldpb.ChannelDataConflictMessage{}, defaultMessageHandler)
+	c.SetMessageEntry(uint32(channeldpb.MessageType_CHANNEL_DATA_LOCK), &channeldpb.ChannelDataLockResultMessage{}, defaultMessageHandler)
+	c.SetMessageEntry(uint32(channeldpb.MessageType_FETCH_HISTORY), &channeldpb.FetchHistoryResultMessage{}, defaultMessageHandler)
+	c.SetMessageEntry(uint32(channeldpb.MessageType_FETCH_ARCHIVE), &channeldpb.FetchArchiveResultMessage{}, defaultMessageHandler)
+	c.SetMessageEntry(uint32(channeldpb.MessageType_FETCH_LEADERBOARD), &channeldpb.FetchLeaderboardResultMessage{}, defaultMessageHandler)
+	c.SetMessageEntry(uint32(channeldpb.MessageType_CHANNEL_DATA_TRANSACTION), &channeldpb.ChannelDataTransactionResultMessage{}, defaultMessageHandler)
+	c.SetMessageEntry(uint32(channeldpb.MessageType_PRESENCE_UPDATE), &channeldpb.PresenceUpdateMessage{}, defaultMessageHandler)
+	c.SetMessageEntry(uint32(channeldpb.MessageType_CHANNEL_RECOVERED), &channeldpb.ChannelRecoveredMessage{}, defaultMessageHandler)
+	c.SetMessageEntry(uint32(channeldpb.MessageType_DUPLICATE_LOGIN), &channeldpb.DuplicateLoginMessage{}, defaultMessageHandler)
+	c.SetMessageEntry(uint32(channeldpb.MessageType_SLOW_SUBSCRIBER), &channeldpb.SlowSubscriberMessage{}, defaultMessageHandler)
+	c.SetMessageEntry(uint32(channeldpb.MessageType_CHANNEL_STATS), &channeldpb.ChannelStatsMessage{}, defaultMessageHandler)
+	c.SetMessageEntry(uint32(channeldpb.MessageType_QUOTA_EXCEEDED), &channeldpb.QuotaExceededMessage{}, defaultMessageHandler)
+	c.SetMessageEntry(uint32(channeldpb.MessageType_CHAT_PENALTY), &channeldpb.ChatPenaltyMessage{}, defaultMessageHandler)
+	c.SetMessageEntry(uint32(channeldpb.MessageType_DISCONNECT), &channeldpb.DisconnectMessage{}, defaultMessageHandler)
+	c.SetMessageEntry(uint32(channeldpb.MessageType_PING), &channeldpb.PingMessage{}, handlePing)
+	c.SetMessageEntry(uint32(channeldpb.MessageType_CHANNEL_OWNER_CHANGED), &channeldpb.ChannelOwnerChangedMessage{}, defaultMessageHandler)
+	c.SetMessageEntry(uint32(channeldpb.MessageType_MESSAGE_TOO_LARGE), &channeldpb.MessageTooLargeMessage{}, defaultMessageHandler)
+	c.SetMessageEntry(uint32(channeldpb.MessageType_ERROR), &channeldpb.ErrorMessage{}, defaultMessageHandler)
+	c.SetMessageEntry(uint32(channeldpb.MessageType_CHANNEL_TICK_LAG), &channeldpb.ChannelTickLagMessage{}, defaultMessageHandler)
+	c.SetMessageEntry(uint32(channeldpb.MessageType_SESSION_KEY_ROTATION), &channeldpb.SessionKeyRotationMessage{}, defaultMessageHandler)
+
+	return c
 }
 
 func (client *ChanneldClient) Disconnect() error {
@@ -102,6 +146,13 @@ func (client *ChanneldClient) SetMessageEntry(msgType uint32, msgTemplate Messag
 	}
 }
 
+// Sets the handler that receives every message in the wire format, after the handlers of the message type are called.
+// The messages of the unregistered types are also passed to the handler, instead of failing Receive().
+// Useful for forwarding the messages to the non-Go code. Should be set before calling Receive().
+func (client *ChanneldClient) SetRawMessageHandler(handler RawMessageHandlerFunc) {
+	client.rawMessageHandler = handler
+}
+
 func (client *ChanneldClient) AddMessageHandler(msgType uint32, handlers ...MessageHandlerFunc) error {
 	entry := client.messageMap[msgType]
 	if entry != nil {
@@ -117,10 +168,85 @@ func (client *ChanneldClient) Auth(lt string, pit string) {
 	client.Send(0, channeldpb.BroadcastType_NO_BROADCAST, uint32(channeldpb.MessageType_AUTH), &channeldpb.AuthMessage{
 		LoginToken:            lt,
 		PlayerIdentifierToken: pit,
+		ProtocolVersion:       channeld.ProtocolVersion,
+		MaxPacketSize:         client.MaxPacketSize,
+		EncryptionPublicKey:   client.encryptionPublicKey(),
 	}, nil)
 	//return result
 }
 
+// Authenticates the server connection with the resume token of its previous connection, e.g. after a hot restart,
+// to take over the channels owned by the previous connection.
+func (client *ChanneldClient) Resume(lt string, pit string, resumeToken string) {
+	client.Send(0, channeldpb.BroadcastType_NO_BROADCAST, uint32(channeldpb.MessageType_AUTH), &channeldpb.AuthMessage{
+		LoginToken:            lt,
+		PlayerIdentifierToken: pit,
+		ProtocolVersion:       channeld.ProtocolVersion,
+		ResumeToken:           resumeToken,
+		MaxPacketSize:         client.MaxPacketSize,
+		EncryptionPublicKey:   client.encryptionPublicKey(),
+	}, nil)
+}
+
+// Generates the key pair for the session keys if the encryption is enabled, and returns the public key.
+func (client *ChanneldClient) encryptionPublicKey() []byte {
+	if !client.EnableEncryption {
+		return nil
+	}
+	privateKey, publicKey, err := channeld.GenerateSessionKeyPair()
+	if err != nil {
+		return nil
+	}
+	client.sessionPrivateKey = privateKey
+	client.sessionKeys = make(map[uint32]*channeld.SessionKey)
+	return publicKey
+}
+
+// Derives the session key as soon as channeld's public key is received, as the packets after it are encrypted with the key.
+func (client *ChanneldClient) updateSessionKey(msg Message) error {
+	var keyId uint32
+	var publicKey []byte
+	switch m := msg.(type) {
+	case *channeldpb.AuthResultMessage:
+		publicKey = m.EncryptionPublicKey
+	case *channeldpb.SessionKeyRotationMessage:
+		keyId, publicKey = m.KeyId, m.PublicKey
+	}
+	if len(publicKey) == 0 || client.sessionPrivateKey == nil {
+		return nil
+	}
+	key, err := channeld.NewSessionKey(keyId, client.sessionPrivateKey, publicKey, false)
+	if err != nil {
+		return fmt.Errorf("failed to derive the session key %d: %w", keyId, err)
+	}
+	client.sessionKeys[keyId] = key
+	// The packets sealed with the previous key may still be in flight
+	delete(client.sessionKeys, keyId-2)
+	// channeld accepts the new key as soon as it sends the key
+	client.writeMutex.Lock()
+	client.sendSessionKey = key
+	client.writeMutex.Unlock()
+	return nil
+}
+
+// Sends the telemetry event to channeld, which forwards it to the analytics sink instead of the backend servers.
+func (client *ChanneldClient) SendAnalytics(event string, properties map[string]string) {
+	client.Send(0, channeldpb.BroadcastType_NO_BROADCAST, uint32(channeldpb.MessageType_ANALYTICS), &channeldpb.AnalyticsMessage{
+		Event:        event,
+		Properties:   properties,
+		ClientTimeMs: time.Now().UnixMilli(),
+	}, nil)
+}
+
+// Reports the measured network condition to channeld, which adapts the fan-outs to the client. Should be called periodically.
+func (client *ChanneldClient) ReportBandwidth(downlinkKbps uint32, packetLoss float32, rttMs uint32) {
+	client.Send(0, channeldpb.BroadcastType_NO_BROADCAST, uint32(channeldpb.MessageType_BANDWIDTH_REPORT), &channeldpb.BandwidthReportMessage{
+		DownlinkKbps: downlinkKbps,
+		PacketLoss:   packetLoss,
+		RttMs:        rttMs,
+	}, nil)
+}
+
 func handleAuth(client *ChanneldClient, channelId uint32, m Message) {
 	msg := m.(*channeldpb.AuthResultMessage)
 
@@ -128,6 +254,10 @@ func handleAuth(client *ChanneldClient, channelId uint32, m Message) {
 		if client.Id == 0 {
 			client.Id = msg.ConnId
 			client.CompressionType = msg.CompressionType
+			client.ProtocolVersion = msg.ProtocolVersion
+			client.ResumeToken = msg.ResumeToken
+			// 0 if channeld is built before the negotiation
+			client.MaxPacketSize = msg.MaxPacketSize
 		}
 
 		// client.Send(0, channeldpb.BroadcastType_NO_BROADCAST, uint32(channeldpb.MessageType_SUB_TO_CHANNEL), &channeldpb.SubscribedToChannelMessage{
@@ -155,6 +285,26 @@ func handleUnsubToChannel(c *ChanneldClient, channelId uint32, m Message) {
 	delete(c.SubscribedChannels, channelId)
 }
 
+func handleSubToChannels(client *ChanneldClient, channelId uint32, m Message) {
+	msg := m.(*channeldpb.SubscribedToChannelsResultMessage)
+	if msg.ConnId != client.Id {
+		return
+	}
+	for chId := range msg.SubOptions {
+		client.SubscribedChannels[chId] = struct{}{}
+	}
+}
+
+func handleUnsubFromChannels(client *ChanneldClient, channelId uint32, m Message) {
+	msg := m.(*channeldpb.UnsubscribedFromChannelsResultMessage)
+	if msg.ConnId != client.Id {
+		return
+	}
+	for _, chId := range msg.ChannelIds {
+		delete(client.SubscribedChannels, chId)
+	}
+}
+
 func handleListChannel(c *ChanneldClient, channelId uint32, m Message) {
 	c.ListedChannels = map[uint32]struct{}{}
 	for _, info := range m.(*channeldpb.ListChannelResultMessage).Channels {
@@ -162,6 +312,11 @@ func handleListChannel(c *ChanneldClient, channelId uint32, m Message) {
 	}
 }
 
+// Replies to the idle ping, so the connection is not closed.
+func handlePing(client *ChanneldClient, channelId uint32, m Message) {
+	client.Send(channelId, channeldpb.BroadcastType_NO_BROADCAST, uint32(channeldpb.MessageType_PING), m, nil)
+}
+
 func defaultMessageHandler(client *ChanneldClient, channelId uint32, m Message) {
 	//log.Printf("Client(%d) received message from channel %d: %s", client.Id, channelId, m)
 }
@@ -178,34 +333,49 @@ func (client *ChanneldClient) Receive() error {
 	}
 
 	client.readPos += bytesRead
-	if client.readPos < 5 {
-		// Unfinished header
-		return nil
-	}
+	// A read may contain multiple packets
+	for client.readPos > 0 {
+		header, complete, err := channeld.ReadPacketHeader(client.readBuffer[:client.readPos])
+		if err != nil {
+			client.readPos = 0
+			return fmt.Errorf("invalid tag: %w, the packet will be dropped", err)
+		}
+		if !complete {
+			// Unfinished header
+			return nil
+		}
 
-	tag := client.readBuffer[:5]
-	if tag[0] != 67 {
-		return fmt.Errorf("invalid tag: %s, the packet will be dropped: %w", tag, err)
-	}
+		fullSize := header.FullSize()
+		if client.readPos < fullSize {
+			// Unfinished packet
+			return nil
+		}
 
-	packetSize := int(tag[3])
-	if tag[1] != 72 {
-		packetSize = packetSize | int(tag[1])<<16 | int(tag[2])<<8
-	} else if tag[2] != 78 {
-		packetSize = packetSize | int(tag[2])<<8
-	}
+		err = client.handlePacket(client.readBuffer[header.HeaderSize:fullSize], header.Flags)
 
-	fullSize := 5 + packetSize
-	if client.readPos < fullSize {
-		// Unfinished packet
-		return nil
+		// Move the unhandled content to the front
+		copy(client.readBuffer, client.readBuffer[fullSize:client.readPos])
+		client.readPos -= fullSize
+
+		if err != nil {
+			return err
+		}
 	}
 
-	bytes := client.readBuffer[5:fullSize]
+	return nil
+}
+
+func (client *ChanneldClient) handlePacket(bytes []byte, flags byte) error {
+	if flags&channeld.WireFlag_Encrypted != 0 {
+		var err error
+		bytes, err = channeld.OpenEncryptedPacket(bytes, client.sessionKeys)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt the packet: %w", err)
+		}
+	}
 
-	// Apply the decompression from the 5th byte in the header
-	// Apply the decompression from the 5th byte in the header
-	if tag[4] == byte(channeldpb.CompressionType_SNAPPY) {
+	// Apply the decompression from the 5th byte in the v1 header, or the flags in the v2 header
+	if flags&channeld.WireFlag_Compressed != 0 {
 		len, err := snappy.DecodedLen(bytes)
 		if err != nil {
 			return fmt.Errorf("snappy.DecodedLen: %w", err)
@@ -218,28 +388,61 @@ func (client *ChanneldClient) Receive() error {
 	}
 
 	var p channeldpb.Packet
-	if err := proto.Unmarshal(bytes, &p); err != nil {
-		return fmt.Errorf("error unmarshalling packet: %w", err)
+	if flags&channeld.WireFlag_Batched != 0 {
+		if err := proto.Unmarshal(bytes, &p); err != nil {
+			return fmt.Errorf("error unmarshalling packet: %w", err)
+		}
+	} else {
+		mp := &channeldpb.MessagePack{}
+		if err := proto.Unmarshal(bytes, mp); err != nil {
+			return fmt.Errorf("error unmarshalling message: %w", err)
+		}
+		p.Messages = []*channeldpb.MessagePack{mp}
 	}
 
-	for _, mp := range p.Messages {
+	for _, chunk := range p.Messages {
+		// The large channel data is split into chunks by channeld. See MessagePack.continued.
+		mp, err := client.chunkAssembler.Add(chunk)
+		if err != nil {
+			return fmt.Errorf("failed to reassemble the chunked message: %w", err)
+		}
+		if mp == nil {
+			continue
+		}
+
+		var rawPack *channeldpb.MessagePack
+		if client.rawMessageHandler != nil {
+			rawPack = mp
+		}
+
 		entry := client.messageMap[mp.MsgType]
 		if entry == nil {
-			return fmt.Errorf("no message type registered: %d", mp.MsgType)
+			if rawPack == nil {
+				return fmt.Errorf("no message type registered: %d", mp.MsgType)
+			}
+			client.incomingQueue <- messageQueueEntry{nil, mp.ChannelId, mp.StubId, nil, rawPack}
+			continue
 		}
 
-		// Always make a clone!
-		msg := proto.Clone(entry.msg)
-		err = proto.Unmarshal(mp.MsgBody, msg)
-		if err != nil {
-			return fmt.Errorf("failed to unmarshal message: %w", err)
+		var msg Message
+		if mp.RawPayload {
+			// The payload is not marshalled by Protobuf. Leave it to the handlers.
+			msg = &channeldpb.ServerForwardMessage{ClientConnId: mp.ClientConnId, Payload: mp.MsgBody}
+		} else {
+			// Always make a clone!
+			msg = proto.Clone(entry.msg)
+			err := proto.Unmarshal(mp.MsgBody, msg)
+			if err != nil {
+				return fmt.Errorf("failed to unmarshal message: %w", err)
+			}
+			if err := client.updateSessionKey(msg); err != nil {
+				return err
+			}
 		}
 
-		client.incomingQueue <- messageQueueEntry{msg, mp.ChannelId, mp.StubId, entry.handlers}
+		client.incomingQueue <- messageQueueEntry{msg, mp.ChannelId, mp.StubId, entry.handlers, rawPack}
 	}
 
-	client.readPos = 0
-
 	return nil
 }
 
@@ -251,31 +454,51 @@ func (client *ChanneldClient) Tick() error {
 			handler(client, entry.channelId, entry.msg)
 		}
 
-		if entry.stubId > 0 {
+		if entry.stubId > 0 && entry.msg != nil {
 			callback := client.stubCallbacks[entry.stubId]
 			if callback != nil {
 				callback(client, entry.channelId, entry.msg)
 			}
 		}
+
+		if entry.pack != nil {
+			client.rawMessageHandler(client, entry.pack)
+		}
 	}
 
 	if len(client.outgoingQueue) == 0 {
 		return nil
 	}
 
+	// Split the queued messages into multiple packets if they don't fit in one.
 	p := channeldpb.Packet{Messages: make([]*channeldpb.MessagePack, 0, len(client.outgoingQueue))}
 	size := 0
 	for len(client.outgoingQueue) > 0 {
 		mp := <-client.outgoingQueue
-		if size+proto.Size(mp) >= 0xfffff0 {
-			break
+		// The tag and the length of the repeated field
+		mpSize := proto.Size(mp) + 4
+		if size+mpSize > client.maxPacketSize()-channeld.SessionKeyOverhead && len(p.Messages) > 0 {
+			if err := client.writePacket(&p); err != nil {
+				return err
+			}
+			p.Messages = p.Messages[:0]
+			size = 0
 		}
 		p.Messages = append(p.Messages, mp)
+		size += mpSize
 	}
 	return client.writePacket(&p)
 }
 
-func (client *ChanneldClient) Send(channelId uint32, broadcast channeldpb.BroadcastType, msgType uint32, msg Message, callback MessageHandlerFunc) error {
+func (client *ChanneldClient) maxPacketSize() int {
+	if client.MaxPacketSize > 0 {
+		return int(client.MaxPacketSize)
+	}
+	return channeld.MaxPacketSize
+}
+
+// Returns the stubId associated with the callback, or 0 if the callback is nil.
+func (client *ChanneldClient) addStubCallback(callback MessageHandlerFunc) uint32 {
 	var stubId uint32 = 0
 	if callback != nil {
 		for client.stubCallbacks[stubId] != nil {
@@ -283,6 +506,11 @@ func (client *ChanneldClient) Send(channelId uint32, broadcast channeldpb.Broadc
 		}
 		client.stubCallbacks[stubId] = callback
 	}
+	return stubId
+}
+
+func (client *ChanneldClient) Send(channelId uint32, broadcast channeldpb.BroadcastType, msgType uint32, msg Message, callback MessageHandlerFunc) error {
+	stubId := client.addStubCallback(callback)
 
 	msgBody, err := proto.Marshal(msg)
 	if err != nil {
@@ -299,14 +527,19 @@ func (client *ChanneldClient) Send(channelId uint32, broadcast channeldpb.Broadc
 	return nil
 }
 
+// Sends the messages of the same type to many channels in one ServerBatchMessage. Only for the server connections.
+// If dataTypeUrl is set, msgType must be CHANNEL_DATA_UPDATE, and the msgBody of each entry is the marshalled channel data.
+// The response of each entry is passed to the handlers of the message type, by the stubId of the entry.
+func (client *ChanneldClient) SendBatch(msgType uint32, dataTypeUrl string, entries []*channeldpb.ServerBatchMessage_Entry) error {
+	return client.Send(0, channeldpb.BroadcastType_NO_BROADCAST, uint32(channeldpb.MessageType_SERVER_BATCH), &channeldpb.ServerBatchMessage{
+		MsgType:     msgType,
+		DataTypeUrl: dataTypeUrl,
+		Entries:     entries,
+	}, nil)
+}
+
 func (client *ChanneldClient) SendRaw(channelId uint32, broadcast channeldpb.BroadcastType, msgType uint32, msgBody *[]byte, callback MessageHandlerFunc) error {
-	var stubId uint32 = 0
-	if callback != nil {
-		for client.stubCallbacks[stubId] != nil {
-			stubId++
-		}
-		client.stubCallbacks[stubId] = callback
-	}
+	stubId := client.addStubCallback(callback)
 
 	client.outgoingQueue <- &channeldpb.MessagePack{
 		ChannelId: channelId,
@@ -318,36 +551,74 @@ func (client *ChanneldClient) SendRaw(channelId uint32, broadcast channeldpb.Bro
 	return nil
 }
 
+// Queues the message pack as it is, e.g. the one forwarded from the non-Go code.
+// The stubId is not associated with any callback, so the response is only passed to the handlers of the message type.
+func (client *ChanneldClient) SendPack(mp *channeldpb.MessagePack) error {
+	client.outgoingQueue <- mp
+	return nil
+}
+
+// Sends the user-space message whose payload is not marshalled by Protobuf, e.g. FlatBuffers. See channeldpb.MessagePack.RawPayload.
+// The handlers of the message type receive the payload in a ServerForwardMessage.
+// clientConnId is only used when sending from a server. See channeldpb.ServerForwardMessage.ClientConnId.
+func (client *ChanneldClient) SendRawPayload(channelId uint32, broadcast channeldpb.BroadcastType, msgType uint32, clientConnId uint32, payload []byte) error {
+	if msgType < uint32(channeldpb.MessageType_USER_SPACE_START) {
+		return fmt.Errorf("raw payload only works for the user-space message, msgType: %d", msgType)
+	}
+
+	client.outgoingQueue <- &channeldpb.MessagePack{
+		ChannelId:    channelId,
+		Broadcast:    uint32(broadcast),
+		MsgType:      msgType,
+		MsgBody:      payload,
+		RawPayload:   true,
+		ClientConnId: clientConnId,
+	}
+	return nil
+}
+
 func (client *ChanneldClient) writePacket(p *channeldpb.Packet) error {
-	bytes, err := proto.Marshal(p)
+	var body proto.Message = p
+	flags := channeld.WireFlag_Batched
+	if client.WireVersion == channeld.WireVersion_V2 && len(p.Messages) == 1 {
+		body = p.Messages[0]
+		flags = 0
+	}
+
+	// Leave the room for the header. See channeld.PutPacketHeader().
+	bytes, err := proto.MarshalOptions{}.MarshalAppend(make([]byte, channeld.MaxPacketHeaderSize), body)
 	if err != nil {
 		return fmt.Errorf("error marshalling packet: %w", err)
 	}
 
 	// Apply the compression
 	if client.CompressionType == channeldpb.CompressionType_SNAPPY {
-		dst := make([]byte, snappy.MaxEncodedLen(len(bytes)))
-		bytes = snappy.Encode(dst, bytes)
+		dst := make([]byte, channeld.MaxPacketHeaderSize+snappy.MaxEncodedLen(len(bytes)))
+		bytes = dst[:channeld.MaxPacketHeaderSize+len(snappy.Encode(dst[channeld.MaxPacketHeaderSize:], bytes[channeld.MaxPacketHeaderSize:]))]
+		flags |= channeld.WireFlag_Compressed
 	}
 
-	// 'CHNL' in ASCII
-	tag := []byte{67, 72, 78, 76, byte(client.CompressionType)}
-	len := len(bytes)
-	tag[3] = byte(len & 0xff)
-	if len > 0xff {
-		tag[2] = byte((len >> 8) & 0xff)
+	client.writeMutex.Lock()
+	defer client.writeMutex.Unlock()
+
+	// Apply the encryption after the compression, same as channeld.Connection.flush()
+	if client.sendSessionKey != nil {
+		bytes = client.sendSessionKey.Seal(make([]byte, channeld.MaxPacketHeaderSize, len(bytes)+channeld.SessionKeyOverhead), bytes[channeld.MaxPacketHeaderSize:])
+		flags |= channeld.WireFlag_Encrypted
 	}
-	if len > 0xffff {
-		tag[1] = byte((len >> 16) & 0xff)
+
+	len := len(bytes) - channeld.MaxPacketHeaderSize
+	if len > client.maxPacketSize() {
+		return fmt.Errorf("packet size exceeds the limit: %d", len)
 	}
+	// Same as channeld.Connection.flush()
+	bytes = channeld.PutPacketHeader(bytes, len, client.WireVersion, flags)
 
-	client.writeMutex.Lock()
-	defer client.writeMutex.Unlock()
 	/* With WebSocket, every Write() sends a message.
 	client.conn.Write(tag)
 	client.conn.Write(bytes)
 	*/
-	client.Conn.Write(append(tag, bytes...))
+	client.Conn.Write(bytes)
 	return nil
 }
 
@@ -0,0 +1,140 @@
+package client
+
+import (
+	"net"
+	"testing"
+
+	"github.com/metaworking/channeld/pkg/channeld"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func writeTestPacket(conn net.Conn, mps ...*channeldpb.MessagePack) {
+	body, _ := proto.Marshal(&channeldpb.Packet{Messages: mps})
+	conn.Write(append([]byte{67, 72, byte(len(body) >> 8), byte(len(body)), 0}, body...))
+}
+
+func TestRawMessageHandler(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	client := newClient(clientConn)
+
+	var rawPacks []*channeldpb.MessagePack
+	client.SetRawMessageHandler(func(_ *ChanneldClient, mp *channeldpb.MessagePack) {
+		rawPacks = append(rawPacks, mp)
+	})
+
+	authBody, _ := proto.Marshal(&channeldpb.AuthResultMessage{Result: channeldpb.AuthResultMessage_SUCCESSFUL, ConnId: 1})
+	go writeTestPacket(serverConn,
+		&channeldpb.MessagePack{MsgType: uint32(channeldpb.MessageType_AUTH), MsgBody: authBody},
+		// Unregistered message type
+		&channeldpb.MessagePack{ChannelId: 1, MsgType: 200, StubId: 2, MsgBody: []byte{1, 2, 3}},
+	)
+	assert.NoError(t, client.Receive())
+	assert.NoError(t, client.Tick())
+
+	// The registered handlers are still called
+	assert.EqualValues(t, 1, client.Id)
+	if assert.Equal(t, 2, len(rawPacks)) {
+		assert.Equal(t, authBody, rawPacks[0].MsgBody)
+		assert.EqualValues(t, 200, rawPacks[1].MsgType)
+		assert.EqualValues(t, 1, rawPacks[1].ChannelId)
+		assert.EqualValues(t, 2, rawPacks[1].StubId)
+		assert.Equal(t, []byte{1, 2, 3}, rawPacks[1].MsgBody)
+	}
+}
+
+func TestReceiveWireV2(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	client := newClient(clientConn)
+
+	authBody, _ := proto.Marshal(&channeldpb.AuthResultMessage{Result: channeldpb.AuthResultMessage_SUCCESSFUL, ConnId: 1})
+	body, _ := proto.Marshal(&channeldpb.MessagePack{MsgType: uint32(channeldpb.MessageType_AUTH), MsgBody: authBody})
+	buf := make([]byte, channeld.MaxPacketHeaderSize+len(body))
+	copy(buf[channeld.MaxPacketHeaderSize:], body)
+	// The single message without the Packet wrapper
+	go serverConn.Write(channeld.PutPacketHeader(buf, len(body), channeld.WireVersion_V2, 0))
+
+	assert.NoError(t, client.Receive())
+	assert.NoError(t, client.Tick())
+	assert.EqualValues(t, 1, client.Id)
+}
+
+func TestReceiveChunkedMessage(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	client := newClient(clientConn)
+
+	authBody, _ := proto.Marshal(&channeldpb.AuthResultMessage{Result: channeldpb.AuthResultMessage_SUCCESSFUL, ConnId: 1})
+	chunks := channeld.SplitMessagePack(&channeldpb.MessagePack{MsgType: uint32(channeldpb.MessageType_AUTH), MsgBody: authBody}, 2)
+	go func() {
+		// One chunk per packet
+		for _, chunk := range chunks {
+			writeTestPacket(serverConn, chunk)
+		}
+	}()
+
+	for i := 0; i < len(chunks)-1; i++ {
+		assert.NoError(t, client.Receive())
+		assert.Equal(t, 0, len(client.incomingQueue))
+	}
+	assert.NoError(t, client.Receive())
+	assert.NoError(t, client.Tick())
+	assert.EqualValues(t, 1, client.Id)
+}
+
+func TestReceiveEncryptedPackets(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	client := newClient(clientConn)
+	client.EnableEncryption = true
+	clientPublicKey := client.encryptionPublicKey()
+
+	// Writes the message in the v2 wire format, encrypted with the key if it's not nil
+	write := func(msgType channeldpb.MessageType, msg Message, key *channeld.SessionKey) {
+		msgBody, _ := proto.Marshal(msg)
+		body, _ := proto.Marshal(&channeldpb.MessagePack{MsgType: uint32(msgType), MsgBody: msgBody})
+		buf := make([]byte, channeld.MaxPacketHeaderSize, channeld.MaxPacketHeaderSize+len(body)+64)
+		flags := byte(0)
+		if key != nil {
+			buf = key.Seal(buf, body)
+			flags = channeld.WireFlag_Encrypted
+		} else {
+			buf = append(buf, body...)
+		}
+		go serverConn.Write(channeld.PutPacketHeader(buf, len(buf)-channeld.MaxPacketHeaderSize, channeld.WireVersion_V2, flags))
+		assert.NoError(t, client.Receive())
+	}
+
+	privateKey0, publicKey0, _ := channeld.GenerateSessionKeyPair()
+	key0, _ := channeld.NewSessionKey(0, privateKey0, clientPublicKey, true)
+	write(channeldpb.MessageType_AUTH, &channeldpb.AuthResultMessage{Result: channeldpb.AuthResultMessage_SUCCESSFUL, ConnId: 1, EncryptionPublicKey: publicKey0}, nil)
+	write(channeldpb.MessageType_ERROR, &channeldpb.ErrorMessage{}, key0)
+
+	privateKey1, publicKey1, _ := channeld.GenerateSessionKeyPair()
+	key1, _ := channeld.NewSessionKey(1, privateKey1, clientPublicKey, true)
+	write(channeldpb.MessageType_SESSION_KEY_ROTATION, &channeldpb.SessionKeyRotationMessage{KeyId: 1, PublicKey: publicKey1}, key0)
+	write(channeldpb.MessageType_CHANNEL_TICK_LAG, &channeldpb.ChannelTickLagMessage{}, key1)
+
+	assert.NoError(t, client.Tick())
+	assert.EqualValues(t, 1, client.Id)
+	assert.Len(t, client.sessionKeys, 2)
+
+	// The packets sent after the rotation are encrypted with the new key
+	packet := make(chan []byte, 1)
+	go func() {
+		buf := make([]byte, 1024)
+		n, _ := serverConn.Read(buf)
+		packet <- buf[:n]
+	}()
+	client.WireVersion = channeld.WireVersion_V2
+	assert.NoError(t, client.Send(0, channeldpb.BroadcastType_NO_BROADCAST, uint32(channeldpb.MessageType_USER_SPACE_START), &channeldpb.ServerForwardMessage{Payload: []byte("hello")}, nil))
+	assert.NoError(t, client.Tick())
+	bytes := <-packet
+	header, _, err := channeld.ReadPacketHeader(bytes)
+	if assert.NoError(t, err) && assert.NotZero(t, header.Flags&channeld.WireFlag_Encrypted) {
+		body, err := channeld.OpenEncryptedPacket(bytes[header.HeaderSize:header.FullSize()], map[uint32]*channeld.SessionKey{1: key1})
+		assert.NoError(t, err)
+		mp := &channeldpb.MessagePack{}
+		assert.NoError(t, proto.Unmarshal(body, mp))
+		assert.EqualValues(t, channeldpb.MessageType_USER_SPACE_START, mp.MsgType)
+	}
+}
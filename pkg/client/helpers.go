@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const (
+	// Used by Request() if the context has no deadline.
+	DefaultRequestTimeout = 5 * time.Second
+	// How often Request() ticks the client while waiting for the reply.
+	requestTickInterval = time.Millisecond
+)
+
+// Connects to channeld and sends the AuthMessage. The handler is called when the AuthResultMessage is received.
+// Like NewClient, the caller is responsible for calling Receive() and Tick() of the returned client.
+func Connect(addr string, lt string, pit string, onAuth func(client *ChanneldClient, result *channeldpb.AuthResultMessage)) (*ChanneldClient, error) {
+	client, err := NewClient(addr)
+	if err != nil {
+		return nil, err
+	}
+	if onAuth != nil {
+		client.OnAuth(onAuth)
+	}
+	client.Auth(lt, pit)
+	return client, nil
+}
+
+// Adds the handler of the AuthResultMessage.
+func (client *ChanneldClient) OnAuth(handler func(client *ChanneldClient, result *channeldpb.AuthResultMessage)) {
+	client.AddMessageHandler(uint32(channeldpb.MessageType_AUTH), func(c *ChanneldClient, _ uint32, m Message) {
+		handler(c, m.(*channeldpb.AuthResultMessage))
+	})
+}
+
+// Subscribes the client to the channel. If subOptions is nil, the channel's default subscription options are used.
+// The callback is called when the SubscribedToChannelResultMessage of this subscription is received.
+func (client *ChanneldClient) Subscribe(channelId uint32, subOptions *channeldpb.ChannelSubscriptionOptions, callback func(client *ChanneldClient, channelId uint32, result *channeldpb.SubscribedToChannelResultMessage)) error {
+	var stubCallback MessageHandlerFunc
+	if callback != nil {
+		stubCallback = func(c *ChanneldClient, chId uint32, m Message) {
+			callback(c, chId, m.(*channeldpb.SubscribedToChannelResultMessage))
+		}
+	}
+	return client.Send(channelId, channeldpb.BroadcastType_NO_BROADCAST, uint32(channeldpb.MessageType_SUB_TO_CHANNEL), &channeldpb.SubscribedToChannelMessage{
+		ConnId:     client.Id,
+		SubOptions: subOptions,
+	}, stubCallback)
+}
+
+// Unsubscribes the client from the channel.
+// The callback is called when the UnsubscribedFromChannelResultMessage of this unsubscription is received.
+func (client *ChanneldClient) Unsubscribe(channelId uint32, callback func(client *ChanneldClient, channelId uint32, result *channeldpb.UnsubscribedFromChannelResultMessage)) error {
+	var stubCallback MessageHandlerFunc
+	if callback != nil {
+		stubCallback = func(c *ChanneldClient, chId uint32, m Message) {
+			callback(c, chId, m.(*channeldpb.UnsubscribedFromChannelResultMessage))
+		}
+	}
+	return client.Send(channelId, channeldpb.BroadcastType_NO_BROADCAST, uint32(channeldpb.MessageType_UNSUB_FROM_CHANNEL), &channeldpb.UnsubscribedFromChannelMessage{
+		ConnId: client.Id,
+	}, stubCallback)
+}
+
+// Adds the handler of the ChannelDataUpdateMessage. The channel data is left in the Any form.
+// Use OnChannelDataUpdate() to handle the unmarshalled channel data of a specific type.
+func (client *ChanneldClient) OnUpdate(handler func(client *ChanneldClient, channelId uint32, msg *channeldpb.ChannelDataUpdateMessage)) {
+	client.AddMessageHandler(uint32(channeldpb.MessageType_CHANNEL_DATA_UPDATE), func(c *ChanneldClient, channelId uint32, m Message) {
+		handler(c, channelId, m.(*channeldpb.ChannelDataUpdateMessage))
+	})
+}
+
+// Sends the ChannelDataUpdateMessage that contains the channel data. The client should have the write access to the channel.
+func (client *ChanneldClient) UpdateChannelData(channelId uint32, data Message) error {
+	any, err := anypb.New(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal channel data: %w", err)
+	}
+	return client.Send(channelId, channeldpb.BroadcastType_NO_BROADCAST, uint32(channeldpb.MessageType_CHANNEL_DATA_UPDATE), &channeldpb.ChannelDataUpdateMessage{
+		Data: any,
+	}, nil)
+}
+
+// Sends the ChannelDataResyncMessage, so the next fan-out contains the whole channel data.
+// Should be called when the checksum of the received ChannelDataUpdateMessage doesn't match common.ChannelDataChecksum() of the client's channel data.
+func (client *ChanneldClient) RequestResync(channelId uint32, checksum uint32) error {
+	return client.Send(channelId, channeldpb.BroadcastType_NO_BROADCAST, uint32(channeldpb.MessageType_CHANNEL_DATA_RESYNC), &channeldpb.ChannelDataResyncMessage{
+		Checksum: checksum,
+	}, nil)
+}
+
+// Adds the handler of the ChannelDataUpdateMessage whose channel data is of type T, e.g. *chatpb.ChatChannelData.
+// The handler receives a new instance of T for each update, so it's safe to keep the reference.
+// The updates of other channel data types are ignored.
+func OnChannelDataUpdate[T Message](client *ChanneldClient, handler func(client *ChanneldClient, channelId uint32, data T, contextConnId uint32)) {
+	var zero T
+	msgType := zero.ProtoReflect().Type()
+	client.OnUpdate(func(c *ChanneldClient, channelId uint32, msg *channeldpb.ChannelDataUpdateMessage) {
+		if msg.Data == nil || !msg.Data.MessageIs(zero) {
+			return
+		}
+		data := msgType.New().Interface().(T)
+		if err := proto.Unmarshal(msg.Data.Value, data); err != nil {
+			return
+		}
+		handler(c, channelId, data, msg.ContextConnId)
+	})
+}
+
+// Sends the message and waits for the reply with the same stubId, e.g. the SubscribedToChannelResultMessage of the SubscribedToChannelMessage.
+// Returns the error of the context if no reply is received before it's done, or in DefaultRequestTimeout if it has no deadline.
+//
+// The client is ticked while waiting, so Request() should be called in the goroutine that calls Tick(), with Receive() running in another goroutine.
+// The handlers of the message type are also called with the reply, as in Tick().
+func (client *ChanneldClient) Request(ctx context.Context, channelId uint32, msgType uint32, msg Message) (Message, error) {
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultRequestTimeout)
+		defer cancel()
+	}
+
+	var reply Message
+	stubId := client.addStubCallback(func(_ *ChanneldClient, _ uint32, m Message) {
+		reply = m
+	})
+	defer delete(client.stubCallbacks, stubId)
+
+	msgBody, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal message %d: %s. Error: %w", msgType, msg, err)
+	}
+	client.outgoingQueue <- &channeldpb.MessagePack{
+		ChannelId: channelId,
+		Broadcast: uint32(channeldpb.BroadcastType_NO_BROADCAST),
+		StubId:    stubId,
+		MsgType:   msgType,
+		MsgBody:   msgBody,
+	}
+
+	ticker := time.NewTicker(requestTickInterval)
+	defer ticker.Stop()
+	for {
+		if err := client.Tick(); err != nil {
+			return nil, err
+		}
+		if reply != nil {
+			return reply, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
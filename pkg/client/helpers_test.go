@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestSubscribeCallback(t *testing.T) {
+	conn, _ := net.Pipe()
+	client := newClient(conn)
+	client.Id = 1
+
+	var result *channeldpb.SubscribedToChannelResultMessage
+	assert.NoError(t, client.Subscribe(100, nil, func(_ *ChanneldClient, channelId uint32, msg *channeldpb.SubscribedToChannelResultMessage) {
+		assert.EqualValues(t, 100, channelId)
+		result = msg
+	}))
+
+	mp := <-client.outgoingQueue
+	assert.EqualValues(t, channeldpb.MessageType_SUB_TO_CHANNEL, mp.MsgType)
+	assert.NotZero(t, mp.StubId)
+	subMsg := &channeldpb.SubscribedToChannelMessage{}
+	assert.NoError(t, proto.Unmarshal(mp.MsgBody, subMsg))
+	assert.EqualValues(t, 1, subMsg.ConnId)
+
+	entry := client.messageMap[mp.MsgType]
+	client.incomingQueue <- messageQueueEntry{&channeldpb.SubscribedToChannelResultMessage{ConnId: 1}, 100, mp.StubId, entry.handlers, nil}
+	assert.NoError(t, client.Tick())
+	if assert.NotNil(t, result) {
+		assert.EqualValues(t, 1, result.ConnId)
+	}
+	assert.Contains(t, client.SubscribedChannels, uint32(100))
+}
+
+func TestOnChannelDataUpdate(t *testing.T) {
+	conn, _ := net.Pipe()
+	client := newClient(conn)
+
+	var received []*testpb.TestChannelDataMessage
+	OnChannelDataUpdate(client, func(_ *ChanneldClient, channelId uint32, data *testpb.TestChannelDataMessage, contextConnId uint32) {
+		assert.EqualValues(t, 100, channelId)
+		assert.EqualValues(t, 2, contextConnId)
+		received = append(received, data)
+	})
+
+	entry := client.messageMap[uint32(channeldpb.MessageType_CHANNEL_DATA_UPDATE)]
+	data, _ := anypb.New(&testpb.TestChannelDataMessage{Text: "a", Num: 1})
+	client.incomingQueue <- messageQueueEntry{&channeldpb.ChannelDataUpdateMessage{Data: data, ContextConnId: 2}, 100, 0, entry.handlers, nil}
+	// Other channel data type should be ignored
+	otherData, _ := anypb.New(&testpb.TestMapMessage{})
+	client.incomingQueue <- messageQueueEntry{&channeldpb.ChannelDataUpdateMessage{Data: otherData, ContextConnId: 2}, 100, 0, entry.handlers, nil}
+	assert.NoError(t, client.Tick())
+
+	if assert.Equal(t, 1, len(received)) {
+		assert.Equal(t, "a", received[0].Text)
+		assert.EqualValues(t, 1, received[0].Num)
+	}
+}
+
+func TestRequest(t *testing.T) {
+	conn, peer := net.Pipe()
+	go io.Copy(io.Discard, peer)
+	client := newClient(conn)
+
+	// Replies with the first stubId, as channeld does
+	entry := client.messageMap[uint32(channeldpb.MessageType_LIST_CHANNEL)]
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		client.incomingQueue <- messageQueueEntry{&channeldpb.ListChannelResultMessage{
+			Channels: []*channeldpb.ListChannelResultMessage_ChannelInfo{{ChannelId: 100}},
+		}, 0, 1, entry.handlers, nil}
+	}()
+	reply, err := client.Request(context.Background(), 0, uint32(channeldpb.MessageType_LIST_CHANNEL), &channeldpb.ListChannelMessage{})
+	if assert.NoError(t, err) {
+		assert.Len(t, reply.(*channeldpb.ListChannelResultMessage).Channels, 1)
+	}
+	// The handlers of the message type are also called
+	assert.Contains(t, client.ListedChannels, uint32(100))
+	assert.NotContains(t, client.stubCallbacks, uint32(1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.Request(ctx, 0, uint32(channeldpb.MessageType_LIST_CHANNEL), &channeldpb.ListChannelMessage{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NotContains(t, client.stubCallbacks, uint32(1))
+}
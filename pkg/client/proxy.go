@@ -0,0 +1,221 @@
+package client
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// The options of NewClientWithOptions(), for the clients on the restrictive networks.
+type DialOptions struct {
+	// Optional. The proxy to connect through, for both the TCP and the WebSocket addresses:
+	// "http://[user:password@]host:port" tunnels with HTTP CONNECT, and "socks5://[user:password@]host:port" with SOCKS5.
+	Proxy string
+	// If true and Proxy is empty, uses the proxy in the environment variables (HTTPS_PROXY and NO_PROXY, same as the browsers),
+	// which is how the corporate networks usually configure the proxy.
+	ProxyFromEnvironment bool
+	// The timeout of connecting, including the proxy handshake. 0 means no timeout.
+	Timeout time.Duration
+}
+
+func (options *DialOptions) proxyURL(addr string) (*url.URL, error) {
+	if options.Proxy != "" {
+		return url.Parse(options.Proxy)
+	}
+	if options.ProxyFromEnvironment {
+		target := &url.URL{Scheme: "https", Host: addr}
+		if u, err := url.Parse(addr); err == nil && u.Host != "" {
+			target.Host = u.Host
+		}
+		return http.ProxyFromEnvironment(&http.Request{URL: target})
+	}
+	return nil, nil
+}
+
+func dialWebSocket(addr string, options DialOptions) (net.Conn, error) {
+	dialer := *websocket.DefaultDialer
+	dialer.HandshakeTimeout = options.Timeout
+	proxyURL, err := options.proxyURL(addr)
+	if err != nil {
+		return nil, err
+	}
+	// Supports both the HTTP and the SOCKS5 proxies
+	dialer.Proxy = http.ProxyURL(proxyURL)
+	c, _, err := dialer.Dial(addr, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &wsConn{conn: c}, nil
+}
+
+func dialTCP(addr string, options DialOptions) (net.Conn, error) {
+	proxyURL, err := options.proxyURL(addr)
+	if err != nil {
+		return nil, err
+	}
+	if proxyURL == nil {
+		return net.DialTimeout("tcp", addr, options.Timeout)
+	}
+
+	conn, err := net.DialTimeout("tcp", proxyURL.Host, options.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	if options.Timeout > 0 {
+		conn.SetDeadline(time.Now().Add(options.Timeout))
+	}
+	tunnel := conn
+	switch proxyURL.Scheme {
+	case "http":
+		tunnel, err = httpConnect(conn, proxyURL, addr)
+	case "socks5", "socks5h":
+		err = socks5Connect(conn, proxyURL, addr)
+	default:
+		err = fmt.Errorf("unsupported proxy scheme: %s", proxyURL.Scheme)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return tunnel, nil
+}
+
+// The connection tunneled by HTTP CONNECT, which may have read the bytes after the response.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+func httpConnect(conn net.Conn, proxyURL *url.URL, addr string) (net.Conn, error) {
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := req.Write(conn); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("proxy refused to connect: %s", resp.Status)
+	}
+	if reader.Buffered() > 0 {
+		return &bufferedConn{conn, reader}, nil
+	}
+	return conn, nil
+}
+
+// See RFC 1928 and RFC 1929
+func socks5Connect(conn net.Conn, proxyURL *url.URL, addr string) error {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return err
+	}
+
+	// The auth methods: no auth, and username/password if set
+	greeting := []byte{5, 1, 0}
+	if proxyURL.User != nil {
+		greeting = []byte{5, 2, 0, 2}
+	}
+	if _, err := conn.Write(greeting); err != nil {
+		return err
+	}
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return err
+	}
+	if reply[0] != 5 {
+		return errors.New("invalid SOCKS5 reply")
+	}
+	switch reply[1] {
+	case 0:
+	case 2:
+		if proxyURL.User == nil {
+			return errors.New("SOCKS5 proxy requires the username and password")
+		}
+		username := proxyURL.User.Username()
+		password, _ := proxyURL.User.Password()
+		auth := append([]byte{1, byte(len(username))}, username...)
+		auth = append(append(auth, byte(len(password))), password...)
+		if _, err := conn.Write(auth); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(conn, reply); err != nil {
+			return err
+		}
+		if reply[1] != 0 {
+			return errors.New("SOCKS5 authentication failed")
+		}
+	default:
+		return errors.New("no acceptable SOCKS5 auth method")
+	}
+
+	req := []byte{5, 1, 0}
+	if ip := net.ParseIP(host); ip == nil {
+		// Let the proxy resolve the domain name, as the client may not have the DNS access on the restrictive network
+		req = append(append(req, 3, byte(len(host))), host...)
+	} else if ip4 := ip.To4(); ip4 != nil {
+		req = append(append(req, 1), ip4...)
+	} else {
+		req = append(append(req, 4), ip.To16()...)
+	}
+	req = append(req, byte(port>>8), byte(port))
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	// VER, REP, RSV, ATYP, then the bound address and port
+	resp := make([]byte, 4)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return err
+	}
+	if resp[1] != 0 {
+		return fmt.Errorf("SOCKS5 proxy failed to connect, reply code: %d", resp[1])
+	}
+	var addrLen int
+	switch resp[3] {
+	case 1:
+		addrLen = net.IPv4len
+	case 4:
+		addrLen = net.IPv6len
+	case 3:
+		if _, err := io.ReadFull(conn, resp[:1]); err != nil {
+			return err
+		}
+		addrLen = int(resp[0])
+	default:
+		return errors.New("invalid SOCKS5 address type")
+	}
+	_, err = io.ReadFull(conn, make([]byte, addrLen+2))
+	return err
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Accepts one connection and echoes the bytes
+func startEchoServer(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		conn, err := l.Accept()
+		if err == nil {
+			io.Copy(conn, conn)
+		}
+	}()
+	return l.Addr().String()
+}
+
+// Accepts one connection, runs the handshake of the proxy, then connects it to the target
+func startTestProxy(t *testing.T, handshake func(conn net.Conn, reader *bufio.Reader) string) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		reader := bufio.NewReader(conn)
+		target, err := net.Dial("tcp", handshake(conn, reader))
+		if err != nil {
+			conn.Close()
+			return
+		}
+		go io.Copy(target, reader)
+		io.Copy(conn, target)
+	}()
+	return l.Addr().String()
+}
+
+func assertEcho(t *testing.T, conn net.Conn) {
+	conn.SetDeadline(time.Now().Add(time.Second))
+	_, err := conn.Write([]byte("ping"))
+	assert.NoError(t, err)
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(conn, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+}
+
+func TestDialThroughHttpProxy(t *testing.T) {
+	target := startEchoServer(t)
+	var auth string
+	proxy := startTestProxy(t, func(conn net.Conn, reader *bufio.Reader) string {
+		req, err := http.ReadRequest(reader)
+		if err != nil {
+			return ""
+		}
+		auth = req.Header.Get("Proxy-Authorization")
+		conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		return req.Host
+	})
+
+	conn, err := dialTCP(target, DialOptions{Proxy: "http://user:pass@" + proxy, Timeout: time.Second})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+	assertEcho(t, conn)
+	assert.Equal(t, "Basic dXNlcjpwYXNz", auth)
+}
+
+func TestDialThroughSocks5Proxy(t *testing.T) {
+	target := startEchoServer(t)
+	proxy := startTestProxy(t, func(conn net.Conn, reader *bufio.Reader) string {
+		buf := make([]byte, 4)
+		// The greeting with the username/password method
+		io.ReadFull(reader, buf)
+		conn.Write([]byte{5, 2})
+		// The username and password
+		io.ReadFull(reader, buf[:2])
+		io.ReadFull(reader, make([]byte, buf[1]))
+		io.ReadFull(reader, buf[:1])
+		io.ReadFull(reader, make([]byte, buf[0]))
+		conn.Write([]byte{1, 0})
+		// The CONNECT request with the IPv4 address
+		io.ReadFull(reader, buf)
+		ip := make([]byte, 4)
+		io.ReadFull(reader, ip)
+		port := make([]byte, 2)
+		io.ReadFull(reader, port)
+		conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+		return (&net.TCPAddr{IP: ip, Port: int(port[0])<<8 | int(port[1])}).String()
+	})
+
+	conn, err := dialTCP(target, DialOptions{Proxy: "socks5://user:pass@" + proxy, Timeout: time.Second})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+	assertEcho(t, conn)
+}
+
+func TestDialThroughRefusingProxy(t *testing.T) {
+	proxy := startTestProxy(t, func(conn net.Conn, reader *bufio.Reader) string {
+		http.ReadRequest(reader)
+		conn.Write([]byte("HTTP/1.1 407 Proxy Authentication Required\r\n\r\n"))
+		return ""
+	})
+	_, err := dialTCP("127.0.0.1:12108", DialOptions{Proxy: "http://" + proxy, Timeout: time.Second})
+	assert.Error(t, err)
+
+	_, err = dialTCP("127.0.0.1:12108", DialOptions{Proxy: "ftp://" + proxy})
+	assert.Error(t, err)
+}
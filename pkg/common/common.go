@@ -2,6 +2,7 @@ package common
 
 import (
 	"fmt"
+	"hash/crc32"
 	"math"
 
 	"google.golang.org/protobuf/proto"
@@ -37,6 +38,15 @@ type SpatialInfoChangedNotifier interface {
 	Notify(oldInfo SpatialInfo, newInfo SpatialInfo, handoverDataProvider func(ChannelId, ChannelId, interface{}))
 }
 
+// Returns the CRC-32 (IEEE) of the deterministically marshaled channel data. Used for the consistency check between channeld and the subscribers.
+func ChannelDataChecksum(msg ChannelDataMessage) uint32 {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return 0
+	}
+	return crc32.ChecksumIEEE(b)
+}
+
 func (s *SpatialInfo) String() string {
 	return fmt.Sprintf("(%.4f, %.4f, %.4f)", s.X, s.Y, s.Z)
 }
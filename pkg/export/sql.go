@@ -0,0 +1,105 @@
+// Package export implements channeld.DataCheckpointSink with database/sql, so the selected fields of the live channel data
+// can be queried by the analysts in Postgres, ClickHouse or any other SQL database, without hitting the game servers.
+//
+// The driver is imported by the application, and the tables are created by the analysts. Each row has the columns
+// tenant, channel_id, channel_type, version and time_ms, followed by the columns in ChannelSettingsType.DataCheckpoint.Columns.
+package export
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/metaworking/channeld/pkg/channeld"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"go.uber.org/zap"
+)
+
+type PlaceholderStyle int
+
+const (
+	// ?, ?, ... e.g. MySQL, ClickHouse and SQLite
+	PlaceholderQuestion PlaceholderStyle = iota
+	// $1, $2, ... e.g. Postgres
+	PlaceholderDollar
+)
+
+type SQLSinkConfig struct {
+	// The table of each channel type. The checkpoints of the other channel types are dropped.
+	Tables      map[channeldpb.ChannelType]string
+	Placeholder PlaceholderStyle
+}
+
+// The table and the column names are put in the statements as they are, so they must be plain identifiers.
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var fixedColumns = []string{"tenant", "channel_id", "channel_type", "version", "time_ms"}
+
+type SQLSink struct {
+	config SQLSinkConfig
+	db     *sql.DB
+}
+
+func NewSQLSink(db *sql.DB, config SQLSinkConfig) (*SQLSink, error) {
+	for channelType, table := range config.Tables {
+		if !identifierPattern.MatchString(table) {
+			return nil, fmt.Errorf("invalid table name %q of channel type %s", table, channelType)
+		}
+	}
+	return &SQLSink{config: config, db: db}, nil
+}
+
+// Writes the checkpoints in one transaction.
+func (s *SQLSink) Write(checkpoints []*channeld.DataCheckpoint) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	for _, checkpoint := range checkpoints {
+		table, exists := s.config.Tables[checkpoint.ChannelType]
+		if !exists {
+			continue
+		}
+		stmt, args, err := s.insertStatement(table, checkpoint)
+		if err != nil {
+			channeld.RootLogger().Warn("failed to write the checkpoint", zap.Uint32("channelId", checkpoint.ChannelId), zap.Error(err))
+			continue
+		}
+		if _, err := tx.Exec(stmt, args...); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *SQLSink) insertStatement(table string, checkpoint *channeld.DataCheckpoint) (string, []interface{}, error) {
+	dataColumns := make([]string, 0, len(checkpoint.Columns))
+	for column := range checkpoint.Columns {
+		if !identifierPattern.MatchString(column) {
+			return "", nil, fmt.Errorf("invalid column name %q", column)
+		}
+		dataColumns = append(dataColumns, column)
+	}
+	sort.Strings(dataColumns)
+
+	columns := append(append([]string{}, fixedColumns...), dataColumns...)
+	args := []interface{}{checkpoint.Tenant, int64(checkpoint.ChannelId), checkpoint.ChannelType.String(), int64(checkpoint.Version), checkpoint.TimeMs}
+	for _, column := range dataColumns {
+		args = append(args, checkpoint.Columns[column])
+	}
+
+	placeholders := make([]string, len(columns))
+	for i := range placeholders {
+		if s.config.Placeholder == PlaceholderDollar {
+			placeholders[i] = "$" + strconv.Itoa(i+1)
+		} else {
+			placeholders[i] = "?"
+		}
+	}
+	stmt := "INSERT INTO " + table + " (" + strings.Join(columns, ", ") + ") VALUES (" + strings.Join(placeholders, ", ") + ")"
+	return stmt, args, nil
+}
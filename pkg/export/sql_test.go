@@ -0,0 +1,64 @@
+package export
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/metaworking/channeld/pkg/channeld"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLSink(t *testing.T) {
+	channeld.InitLogs()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "checkpoints.db"))
+	assert.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec(`CREATE TABLE test_checkpoints (tenant TEXT, channel_id INTEGER, channel_type TEXT, version INTEGER,
+		time_ms INTEGER, text TEXT, num INTEGER)`)
+	assert.NoError(t, err)
+
+	_, err = NewSQLSink(db, SQLSinkConfig{Tables: map[channeldpb.ChannelType]string{channeldpb.ChannelType_TEST: "x; DROP TABLE y"}})
+	assert.Error(t, err)
+
+	sink, err := NewSQLSink(db, SQLSinkConfig{Tables: map[channeldpb.ChannelType]string{channeldpb.ChannelType_TEST: "test_checkpoints"}})
+	assert.NoError(t, err)
+	err = sink.Write([]*channeld.DataCheckpoint{
+		{ChannelId: 1, ChannelType: channeldpb.ChannelType_TEST, Version: 3, TimeMs: 1000, Columns: map[string]interface{}{"text": "a", "num": uint32(1)}},
+		// The channel type without the table is dropped
+		{ChannelId: 2, ChannelType: channeldpb.ChannelType_SUBWORLD, Columns: map[string]interface{}{"text": "b"}},
+		// The field that doesn't exist is written as NULL
+		{ChannelId: 3, ChannelType: channeldpb.ChannelType_TEST, Version: 1, TimeMs: 2000, Columns: map[string]interface{}{"text": "c", "num": nil}},
+		// The invalid column name is dropped
+		{ChannelId: 4, ChannelType: channeldpb.ChannelType_TEST, Columns: map[string]interface{}{"text;": "d"}},
+	})
+	assert.NoError(t, err)
+
+	rows, err := db.Query("SELECT channel_id, channel_type, version, time_ms, text, num FROM test_checkpoints ORDER BY channel_id")
+	assert.NoError(t, err)
+	defer rows.Close()
+	type row struct {
+		channelId   uint32
+		channelType string
+		version     uint64
+		timeMs      int64
+		text        string
+		num         sql.NullInt64
+	}
+	var result []row
+	for rows.Next() {
+		var r row
+		assert.NoError(t, rows.Scan(&r.channelId, &r.channelType, &r.version, &r.timeMs, &r.text, &r.num))
+		result = append(result, r)
+	}
+	assert.Equal(t, []row{
+		{1, "TEST", 3, 1000, "a", sql.NullInt64{Int64: 1, Valid: true}},
+		{3, "TEST", 1, 2000, "c", sql.NullInt64{}},
+	}, result)
+
+	stmt, _, _ := (&SQLSink{config: SQLSinkConfig{Placeholder: PlaceholderDollar}}).insertStatement("t", &channeld.DataCheckpoint{Columns: map[string]interface{}{"b": 1, "a": 2}})
+	assert.Equal(t, "INSERT INTO t (tenant, channel_id, channel_type, version, time_ms, a, b) VALUES ($1, $2, $3, $4, $5, $6, $7)", stmt)
+}
@@ -4,6 +4,7 @@ import (
 	"io/ioutil"
 	"testing"
 
+	"github.com/metaworking/channeld/pkg/channeldpb"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -64,3 +65,29 @@ func TestTransitionAndMsgAllowence(t *testing.T) {
 	serverFSM.OnReceived(22)
 	assert.Equal(t, "OPEN", serverFSM.CurrentState().Name)
 }
+
+func TestClientNonAuthoritativeFSM(t *testing.T) {
+	bytes, err := ioutil.ReadFile("../../config/client_non_authoratative_fsm.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	clientFSM, err := Load(bytes)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, clientFSM.ChangeState("OPEN"))
+
+	assert.True(t, clientFSM.IsAllowed(uint32(channeldpb.MessageType_UNSUB_FROM_CHANNEL)))
+	assert.True(t, clientFSM.IsAllowed(uint32(channeldpb.MessageType_UNSUB_FROM_CHANNELS)))
+	assert.True(t, clientFSM.IsAllowed(uint32(channeldpb.MessageType_QUERY_CHANNEL_DATA)))
+	assert.True(t, clientFSM.IsAllowed(uint32(channeldpb.MessageType_FETCH_HISTORY)))
+	assert.True(t, clientFSM.IsAllowed(uint32(channeldpb.MessageType_CHANNEL_DATA_RESYNC)))
+	assert.True(t, clientFSM.IsAllowed(uint32(channeldpb.MessageType_FETCH_ARCHIVE)))
+	assert.True(t, clientFSM.IsAllowed(uint32(channeldpb.MessageType_UPDATE_BLOCK_LIST)))
+	assert.True(t, clientFSM.IsAllowed(uint32(channeldpb.MessageType_FETCH_LEADERBOARD)))
+	assert.True(t, clientFSM.IsAllowed(uint32(channeldpb.MessageType_USER_SPACE_START)))
+	// The non-authoritative client can't create channels or update the channel data
+	assert.False(t, clientFSM.IsAllowed(uint32(channeldpb.MessageType_CREATE_CHANNEL)))
+	assert.False(t, clientFSM.IsAllowed(uint32(channeldpb.MessageType_CHANNEL_DATA_UPDATE)))
+	assert.False(t, clientFSM.IsAllowed(uint32(channeldpb.MessageType_CHANNEL_DATA_HANDOVER)))
+}
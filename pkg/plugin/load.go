@@ -0,0 +1,252 @@
+package plugin
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/rpc"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeld"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// A launched plugin process, and the RPC client to it.
+type Plugin struct {
+	Name string
+	// How long a call to the plugin can take. See DefaultCallTimeout.
+	CallTimeout  time.Duration
+	cmd          *exec.Cmd
+	stdin        io.WriteCloser
+	client       *rpc.Client
+	capabilities Capabilities
+}
+
+// Launches the plugin binary, and connects to it once it has done the handshake.
+func Load(path string, args ...string) (*Plugin, error) {
+	cmd := exec.Command(path, args...)
+	cmd.Env = append(os.Environ(), MagicCookieKey+"="+MagicCookieValue)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	p := &Plugin{
+		Name:        filepath.Base(path),
+		CallTimeout: DefaultCallTimeout,
+		cmd:         cmd,
+		stdin:       stdin,
+	}
+	if err := p.handshake(stdout); err != nil {
+		p.Close()
+		return nil, fmt.Errorf("failed to load plugin %s: %w", path, err)
+	}
+	return p, nil
+}
+
+func (p *Plugin) handshake(stdout io.Reader) error {
+	reader := bufio.NewReader(stdout)
+	lineCh := make(chan string, 1)
+	errCh := make(chan error, 1)
+	go func() {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			errCh <- err
+			return
+		}
+		lineCh <- line
+		// Any further output of the plugin goes to the log
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			channeld.RootLogger().Info(scanner.Text(), zap.String("plugin", p.Name))
+		}
+	}()
+
+	var line string
+	select {
+	case line = <-lineCh:
+	case err := <-errCh:
+		return fmt.Errorf("plugin exited before the handshake: %w", err)
+	case <-time.After(DefaultHandshakeTimeout):
+		return fmt.Errorf("timed out waiting for the handshake")
+	}
+
+	network, addr, err := parseHandshake(line)
+	if err != nil {
+		return err
+	}
+	p.client, err = rpc.Dial(network, addr)
+	if err != nil {
+		return err
+	}
+	return p.call("Capabilities", Empty{}, &p.capabilities)
+}
+
+func (p *Plugin) call(method string, args interface{}, reply interface{}) error {
+	call := p.client.Go(serviceName+"."+method, args, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-call.Done:
+		return call.Error
+	case <-time.After(p.CallTimeout):
+		return ErrCallTimeout
+	}
+}
+
+// Closes the connection to the plugin, and kills the process if it doesn't exit in time.
+func (p *Plugin) Close() error {
+	if p.client != nil {
+		p.client.Close()
+	}
+	p.stdin.Close()
+	exited := make(chan error, 1)
+	go func() {
+		exited <- p.cmd.Wait()
+	}()
+	select {
+	case err := <-exited:
+		return err
+	case <-time.After(DefaultHandshakeTimeout):
+		return p.cmd.Process.Kill()
+	}
+}
+
+// Returns nil if the plugin doesn't provide the auth provider.
+func (p *Plugin) AuthProvider() channeld.AuthProvider {
+	if !p.capabilities.AuthProvider {
+		return nil
+	}
+	return &authProvider{p}
+}
+
+// Returns nil if the plugin doesn't provide the content filter.
+func (p *Plugin) ContentFilter() channeld.ContentFilter {
+	if !p.capabilities.ContentFilter {
+		return nil
+	}
+	return &contentFilter{p}
+}
+
+// Returns the merge functions by the full names of the channel data message types.
+func (p *Plugin) MergeFuncs() map[protoreflect.FullName]channeld.ChannelDataMergeFunc {
+	funcs := make(map[protoreflect.FullName]channeld.ChannelDataMergeFunc, len(p.capabilities.MergeTypes))
+	for _, typeName := range p.capabilities.MergeTypes {
+		typeName := typeName
+		funcs[protoreflect.FullName(typeName)] = func(dst common.ChannelDataMessage, src common.ChannelDataMessage, options *channeldpb.ChannelDataMergeOptions) error {
+			return p.merge(typeName, dst, src, options)
+		}
+	}
+	return funcs
+}
+
+// Sets all the extensions that the plugin provides to channeld.
+func (p *Plugin) Install() {
+	if auth := p.AuthProvider(); auth != nil {
+		channeld.SetAuthProvider(auth)
+	}
+	if filter := p.ContentFilter(); filter != nil {
+		channeld.SetContentFilter(filter)
+	}
+	for typeName, merge := range p.MergeFuncs() {
+		channeld.RegisterChannelDataMergeFunc(typeName, merge)
+	}
+	channeld.RootLogger().Info("installed plugin", zap.String("plugin", p.Name),
+		zap.Bool("authProvider", p.capabilities.AuthProvider),
+		zap.Bool("contentFilter", p.capabilities.ContentFilter),
+		zap.Strings("mergeTypes", p.capabilities.MergeTypes),
+	)
+}
+
+// Loads and installs the plugins in GlobalSettingsType.Plugins. The extensions of the latter plugins take precedence.
+// The returned plugins should be closed when channeld exits.
+func InitPlugins() ([]*Plugin, error) {
+	var plugins []*Plugin
+	for _, path := range strings.Split(channeld.GlobalSettings.Plugins, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		p, err := Load(path)
+		if err != nil {
+			for _, loaded := range plugins {
+				loaded.Close()
+			}
+			return nil, err
+		}
+		p.Install()
+		plugins = append(plugins, p)
+	}
+	return plugins, nil
+}
+
+type authProvider struct {
+	p *Plugin
+}
+
+func (a *authProvider) DoAuth(connId channeld.ConnectionId, pit string, lt string) (channeldpb.AuthResultMessage_AuthResult, error) {
+	reply := &AuthReply{}
+	if err := a.p.call("DoAuth", AuthArgs{ConnId: uint32(connId), Pit: pit, Lt: lt}, reply); err != nil {
+		return channeldpb.AuthResultMessage_INVALID_LT, err
+	}
+	return reply.Result, nil
+}
+
+type contentFilter struct {
+	p *Plugin
+}
+
+// Rejects the message if the plugin fails to respond, so the unchecked content is never broadcast.
+func (f *contentFilter) FilterContent(ch *channeld.Channel, sender channeld.ConnectionInChannel, msgType channeldpb.MessageType, payload []byte) ([]byte, bool) {
+	args := ContentFilterArgs{
+		ChannelId:   uint32(ch.Id()),
+		ChannelType: ch.Type(),
+		MsgType:     msgType,
+		Payload:     payload,
+	}
+	if sender != nil {
+		args.SenderConnId = uint32(sender.Id())
+	}
+	reply := &ContentFilterReply{}
+	if err := f.p.call("FilterContent", args, reply); err != nil {
+		channeld.RootLogger().Error("content filter plugin error", zap.String("plugin", f.p.Name), zap.Error(err))
+		return nil, false
+	}
+	return reply.Payload, reply.Ok
+}
+
+func (p *Plugin) merge(typeName string, dst common.ChannelDataMessage, src common.ChannelDataMessage, options *channeldpb.ChannelDataMergeOptions) error {
+	args := MergeArgs{TypeName: typeName}
+	var err error
+	if args.Dst, err = proto.Marshal(dst); err != nil {
+		return err
+	}
+	if args.Src, err = proto.Marshal(src); err != nil {
+		return err
+	}
+	if options != nil {
+		if args.Options, err = proto.Marshal(options); err != nil {
+			return err
+		}
+	}
+	reply := &MergeReply{}
+	if err := p.call("Merge", args, reply); err != nil {
+		return err
+	}
+	proto.Reset(dst)
+	return proto.Unmarshal(reply.Merged, dst)
+}
@@ -0,0 +1,121 @@
+// Package plugin loads the extensions of channeld from the external plugin binaries, so they can be written,
+// built and deployed separately from channeld.
+//
+// A plugin binary calls Serve() with the extensions it provides. channeld launches the binary with Load(),
+// which reads the address the plugin listens on from its stdout (the handshake), and calls the extensions
+// over net/rpc. The plugin exits when its stdin is closed, i.e. when channeld closes the plugin or exits.
+//
+// The stable extension interfaces are channeld.AuthProvider, ContentFilter (the RPC counterpart of channeld.ContentFilter)
+// and channeld.ChannelDataMergeFunc. The spatial controllers are not supported, as they create and manage the channels.
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeld"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// Increased when the RPC methods or their arguments change incompatibly.
+	ProtocolVersion = 1
+
+	// Set in the environment of the plugin process, so the binary can tell whether it's launched by channeld.
+	MagicCookieKey   = "CHANNELD_PLUGIN_MAGIC_COOKIE"
+	MagicCookieValue = "5c1f2b0e8d7a4e3f9b6c"
+
+	// The name of the RPC service served by the plugin.
+	serviceName = "Plugin"
+
+	DefaultHandshakeTimeout = 10 * time.Second
+	// The calls are made in the channel's goroutine, so a hanging plugin must not block the channel forever.
+	DefaultCallTimeout = time.Second
+)
+
+var ErrNotLaunchedByChannelD = errors.New("the plugin should be launched by channeld")
+var ErrCallTimeout = errors.New("the plugin call timed out")
+
+// The RPC counterpart of channeld.ContentFilter, as the channel and the connection can't be passed to the plugin.
+type ContentFilter interface {
+	// Returns the payload to broadcast, which can be rewritten, or false to reject the message.
+	FilterContent(args *ContentFilterArgs) ([]byte, bool)
+}
+
+// The extensions that a plugin provides. Any of them can be empty.
+type Extensions struct {
+	AuthProvider  channeld.AuthProvider
+	ContentFilter ContentFilter
+	// The merge functions by the full names of the channel data message types, e.g. "tps.TestChannelDataMessage".
+	// The message types should be linked into the plugin binary, as the messages are unmarshalled in the plugin.
+	MergeFuncs map[protoreflect.FullName]channeld.ChannelDataMergeFunc
+}
+
+// The arguments and replies of the RPC methods. Should only be changed along with ProtocolVersion.
+
+type Empty struct{}
+
+type Capabilities struct {
+	AuthProvider  bool
+	ContentFilter bool
+	MergeTypes    []string
+}
+
+type AuthArgs struct {
+	ConnId uint32
+	Pit    string
+	Lt     string
+}
+
+type AuthReply struct {
+	Result channeldpb.AuthResultMessage_AuthResult
+}
+
+type ContentFilterArgs struct {
+	ChannelId    uint32
+	ChannelType  channeldpb.ChannelType
+	SenderConnId uint32
+	MsgType      channeldpb.MessageType
+	Payload      []byte
+}
+
+type ContentFilterReply struct {
+	Payload []byte
+	Ok      bool
+}
+
+type MergeArgs struct {
+	TypeName string
+	Dst      []byte
+	Src      []byte
+	// The marshalled channeldpb.ChannelDataMergeOptions
+	Options []byte
+}
+
+type MergeReply struct {
+	Merged []byte
+}
+
+// The first line that the plugin writes to stdout: "<ProtocolVersion>|<network>|<address>"
+func formatHandshake(network string, addr string) string {
+	return fmt.Sprintf("%d|%s|%s\n", ProtocolVersion, network, addr)
+}
+
+func parseHandshake(line string) (network string, addr string, err error) {
+	parts := strings.Split(strings.TrimSpace(line), "|")
+	if len(parts) != 3 {
+		return "", "", fmt.Errorf("invalid handshake: %q", line)
+	}
+	version, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return "", "", fmt.Errorf("invalid handshake: %q", line)
+	}
+	if version != ProtocolVersion {
+		return "", "", fmt.Errorf("incompatible plugin protocol version %d, expected %d", version, ProtocolVersion)
+	}
+	return parts[1], parts[2], nil
+}
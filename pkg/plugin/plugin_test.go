@@ -0,0 +1,123 @@
+package plugin
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeld"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type testAuthProvider struct{}
+
+func (a *testAuthProvider) DoAuth(connId channeld.ConnectionId, pit string, lt string) (channeldpb.AuthResultMessage_AuthResult, error) {
+	if lt == "secret" {
+		return channeldpb.AuthResultMessage_SUCCESSFUL, nil
+	}
+	return channeldpb.AuthResultMessage_INVALID_LT, nil
+}
+
+type testContentFilter struct{}
+
+func (f *testContentFilter) FilterContent(args *ContentFilterArgs) ([]byte, bool) {
+	if args.MsgType != channeldpb.MessageType_USER_SPACE_START {
+		return nil, false
+	}
+	if args.Payload == nil {
+		// Hangs to test the timeout
+		time.Sleep(time.Second)
+	}
+	return bytes.ReplaceAll(args.Payload, []byte("bad"), []byte("***")), true
+}
+
+var testExtensions = &Extensions{
+	AuthProvider:  &testAuthProvider{},
+	ContentFilter: &testContentFilter{},
+	MergeFuncs: map[protoreflect.FullName]channeld.ChannelDataMergeFunc{
+		"testpb.TestChannelDataMessage": func(dst common.ChannelDataMessage, src common.ChannelDataMessage, options *channeldpb.ChannelDataMergeOptions) error {
+			dst.(*testpb.TestChannelDataMessage).Num += src.(*testpb.TestChannelDataMessage).Num
+			return nil
+		},
+	},
+}
+
+// The test binary serves as the plugin when it's launched by Load().
+func TestMain(m *testing.M) {
+	if os.Getenv(MagicCookieKey) == MagicCookieValue {
+		if err := Serve(testExtensions); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestPlugin(t *testing.T) {
+	channeld.InitLogs()
+	channeld.InitChannels()
+
+	assert.ErrorIs(t, Serve(testExtensions), ErrNotLaunchedByChannelD)
+
+	p, err := Load(os.Args[0])
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer p.Close()
+
+	auth := p.AuthProvider()
+	if assert.NotNil(t, auth) {
+		result, err := auth.DoAuth(1, "p1", "secret")
+		assert.NoError(t, err)
+		assert.Equal(t, channeldpb.AuthResultMessage_SUCCESSFUL, result)
+		result, err = auth.DoAuth(1, "p1", "wrong")
+		assert.NoError(t, err)
+		assert.Equal(t, channeldpb.AuthResultMessage_INVALID_LT, result)
+	}
+
+	filter := p.ContentFilter()
+	if assert.NotNil(t, filter) {
+		ch := channeld.GetChannel(channeld.GlobalChannelId)
+		payload, ok := filter.FilterContent(ch, nil, channeldpb.MessageType_USER_SPACE_START, []byte("a bad word"))
+		assert.True(t, ok)
+		assert.Equal(t, "a *** word", string(payload))
+		_, ok = filter.FilterContent(ch, nil, channeldpb.MessageType_USER_SPACE_START+1, []byte("a"))
+		assert.False(t, ok)
+		// Rejected if the plugin doesn't respond in time
+		p.CallTimeout = 100 * time.Millisecond
+		_, ok = filter.FilterContent(ch, nil, channeldpb.MessageType_USER_SPACE_START, nil)
+		assert.False(t, ok)
+		p.CallTimeout = DefaultCallTimeout
+	}
+
+	merges := p.MergeFuncs()
+	if assert.Contains(t, merges, protoreflect.FullName("testpb.TestChannelDataMessage")) {
+		dst := &testpb.TestChannelDataMessage{Text: "a", Num: 1}
+		src := &testpb.TestChannelDataMessage{Num: 2}
+		assert.NoError(t, merges["testpb.TestChannelDataMessage"](dst, src, nil))
+		assert.Equal(t, "a", dst.Text)
+		assert.EqualValues(t, 3, dst.Num)
+	}
+
+	// The plugin exits when it's closed
+	assert.NoError(t, p.Close())
+	_, err = p.AuthProvider().DoAuth(1, "p1", "secret")
+	assert.Error(t, err)
+}
+
+func TestParseHandshake(t *testing.T) {
+	network, addr, err := parseHandshake(formatHandshake("tcp", "127.0.0.1:1234"))
+	assert.NoError(t, err)
+	assert.Equal(t, "tcp", network)
+	assert.Equal(t, "127.0.0.1:1234", addr)
+
+	_, _, err = parseHandshake("0|tcp|127.0.0.1:1234\n")
+	assert.Error(t, err)
+	_, _, err = parseHandshake("hello\n")
+	assert.Error(t, err)
+}
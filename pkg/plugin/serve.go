@@ -0,0 +1,109 @@
+package plugin
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/rpc"
+	"os"
+
+	"github.com/metaworking/channeld/pkg/channeld"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Serves the extensions to channeld. Should be called in the main function of the plugin binary.
+// Blocks until channeld closes the plugin.
+func Serve(ext *Extensions) error {
+	if os.Getenv(MagicCookieKey) != MagicCookieValue {
+		return ErrNotLaunchedByChannelD
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	server := rpc.NewServer()
+	if err := server.RegisterName(serviceName, &rpcServer{ext: ext}); err != nil {
+		listener.Close()
+		return err
+	}
+
+	// The pipe is closed when channeld closes the plugin or exits
+	go func() {
+		io.Copy(io.Discard, os.Stdin)
+		listener.Close()
+	}()
+
+	fmt.Fprint(os.Stdout, formatHandshake("tcp", listener.Addr().String()))
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return nil
+		}
+		go server.ServeConn(conn)
+	}
+}
+
+type rpcServer struct {
+	ext *Extensions
+}
+
+func (s *rpcServer) Capabilities(args Empty, reply *Capabilities) error {
+	reply.AuthProvider = s.ext.AuthProvider != nil
+	reply.ContentFilter = s.ext.ContentFilter != nil
+	for typeName := range s.ext.MergeFuncs {
+		reply.MergeTypes = append(reply.MergeTypes, string(typeName))
+	}
+	return nil
+}
+
+func (s *rpcServer) DoAuth(args AuthArgs, reply *AuthReply) error {
+	if s.ext.AuthProvider == nil {
+		return fmt.Errorf("the plugin doesn't provide the auth provider")
+	}
+	result, err := s.ext.AuthProvider.DoAuth(channeld.ConnectionId(args.ConnId), args.Pit, args.Lt)
+	reply.Result = result
+	return err
+}
+
+func (s *rpcServer) FilterContent(args ContentFilterArgs, reply *ContentFilterReply) error {
+	if s.ext.ContentFilter == nil {
+		return fmt.Errorf("the plugin doesn't provide the content filter")
+	}
+	reply.Payload, reply.Ok = s.ext.ContentFilter.FilterContent(&args)
+	return nil
+}
+
+func (s *rpcServer) Merge(args MergeArgs, reply *MergeReply) error {
+	merge, exists := s.ext.MergeFuncs[protoreflect.FullName(args.TypeName)]
+	if !exists {
+		return fmt.Errorf("the plugin doesn't provide the merge function of %s", args.TypeName)
+	}
+	msgType, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(args.TypeName))
+	if err != nil {
+		return err
+	}
+	dst := msgType.New().Interface()
+	if err := proto.Unmarshal(args.Dst, dst); err != nil {
+		return err
+	}
+	src := msgType.New().Interface()
+	if err := proto.Unmarshal(args.Src, src); err != nil {
+		return err
+	}
+	var options *channeldpb.ChannelDataMergeOptions
+	if args.Options != nil {
+		options = &channeldpb.ChannelDataMergeOptions{}
+		if err := proto.Unmarshal(args.Options, options); err != nil {
+			return err
+		}
+	}
+	if err := merge(dst, src, options); err != nil {
+		return err
+	}
+	reply.Merged, err = proto.Marshal(dst)
+	return err
+}
@@ -0,0 +1,268 @@
+// Package relay implements the edge relay mode: a lightweight channeld instance deployed near the players terminates
+// the client connections, and forwards the traffic to the central channeld over a single multiplexed link.
+//
+// The link is a server connection to the central channeld. Each client connection is identified by an edgeConnId,
+// and its bytes are forwarded as they are in the RelayMessages (the edge doesn't decode the packets). The central
+// channeld creates a client Connection for each relayed connection, so the auth, the FSM and the subscriptions
+// work in the same way as the direct client connections.
+package relay
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeld"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/client"
+	"go.uber.org/zap"
+)
+
+type EdgeConfig struct {
+	// The server address of the central channeld
+	CentralAddr string
+	// The network and the address to listen for the client connections. Only the stream-oriented networks are supported.
+	Network    string
+	ListenAddr string
+	// The tokens to authenticate the link
+	LoginToken            string
+	PlayerIdentifierToken string
+	TickInterval          time.Duration
+	// Max time to wait for the auth result of the link.
+	Timeout time.Duration
+	// How many data chunks from the central are buffered for each client. The client is dropped when exceeded.
+	ClientSendQueueSize int
+}
+
+var DefaultEdgeConfig = EdgeConfig{
+	Network:             "tcp",
+	ListenAddr:          ":12108",
+	TickInterval:        time.Millisecond,
+	Timeout:             5 * time.Second,
+	ClientSendQueueSize: 256,
+}
+
+type Edge struct {
+	config     EdgeConfig
+	link       *client.ChanneldClient
+	linkErr    chan error
+	listener   net.Listener
+	clients    sync.Map // map[uint32]*edgeClient
+	clientNum  int32
+	nextConnId uint32
+	stopped    int32
+}
+
+type edgeClient struct {
+	id        uint32
+	conn      net.Conn
+	sendQueue chan []byte
+	closing   chan struct{}
+	closeOnce sync.Once
+}
+
+func NewEdge(config EdgeConfig) *Edge {
+	return &Edge{config: config, linkErr: make(chan error, 1)}
+}
+
+// Returns the number of the client connections currently relayed.
+func (e *Edge) ClientNum() int {
+	return int(atomic.LoadInt32(&e.clientNum))
+}
+
+// Stops the edge. Run() returns after the current tick.
+func (e *Edge) Stop() {
+	atomic.StoreInt32(&e.stopped, 1)
+}
+
+func (e *Edge) isStopped() bool {
+	return atomic.LoadInt32(&e.stopped) == 1
+}
+
+// Connects to the central channeld, then accepts and relays the client connections until Stop() is called or the link is closed.
+// All the client connections are closed when it returns.
+func (e *Edge) Run() error {
+	if err := e.connect(); err != nil {
+		return fmt.Errorf("failed to connect to the central %s: %w", e.config.CentralAddr, err)
+	}
+	defer e.link.Disconnect()
+
+	listener, err := net.Listen(e.config.Network, e.config.ListenAddr)
+	if err != nil {
+		return err
+	}
+	e.listener = listener
+	defer listener.Close()
+	go e.accept()
+
+	defer e.clients.Range(func(_, value any) bool {
+		e.closeClient(value.(*edgeClient), false)
+		return true
+	})
+
+	for !e.isStopped() {
+		select {
+		case err := <-e.linkErr:
+			return fmt.Errorf("disconnected from the central: %w", err)
+		default:
+		}
+		if err := e.link.Tick(); err != nil {
+			return err
+		}
+		time.Sleep(e.config.TickInterval)
+	}
+	return nil
+}
+
+func (e *Edge) connect() error {
+	link, err := client.NewClient(e.config.CentralAddr)
+	if err != nil {
+		return err
+	}
+	e.link = link
+	go func() {
+		for {
+			if err := link.Receive(); err != nil {
+				e.linkErr <- err
+				return
+			}
+		}
+	}()
+
+	link.SetMessageEntry(uint32(channeldpb.MessageType_RELAY), &channeldpb.RelayMessage{}, e.handleRelayMessage)
+
+	var authResult *channeldpb.AuthResultMessage
+	link.OnAuth(func(_ *client.ChanneldClient, result *channeldpb.AuthResultMessage) {
+		authResult = result
+	})
+	link.Auth(e.config.LoginToken, e.config.PlayerIdentifierToken)
+	deadline := time.Now().Add(e.config.Timeout)
+	for authResult == nil {
+		if time.Now().After(deadline) {
+			return errors.New("timed out")
+		}
+		select {
+		case err := <-e.linkErr:
+			return err
+		default:
+		}
+		if err := link.Tick(); err != nil {
+			return err
+		}
+		time.Sleep(e.config.TickInterval)
+	}
+	if authResult.Result != channeldpb.AuthResultMessage_SUCCESSFUL {
+		return fmt.Errorf("auth result: %s", authResult.Result)
+	}
+	return nil
+}
+
+func (e *Edge) accept() {
+	for {
+		conn, err := e.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				channeld.RootLogger().Error("failed to accept the client connection", zap.Error(err))
+			}
+			return
+		}
+
+		c := &edgeClient{
+			id:        atomic.AddUint32(&e.nextConnId, 1),
+			conn:      conn,
+			sendQueue: make(chan []byte, e.config.ClientSendQueueSize),
+			closing:   make(chan struct{}),
+		}
+		e.clients.Store(c.id, c)
+		atomic.AddInt32(&e.clientNum, 1)
+		e.sendRelay(&channeldpb.RelayMessage{
+			EdgeConnId: c.id,
+			Event:      channeldpb.RelayMessage_CONNECTED,
+			RemoteAddr: conn.RemoteAddr().String(),
+		})
+		go e.readClient(c)
+		go e.writeClient(c)
+	}
+}
+
+// Forwards the bytes read from the client to the central.
+func (e *Edge) readClient(c *edgeClient) {
+	buf := make([]byte, channeld.MaxRelayDataSize)
+	for {
+		n, err := c.conn.Read(buf)
+		if err != nil {
+			e.closeClient(c, true)
+			return
+		}
+		// The data is marshalled in Send(), so the buffer can be reused.
+		e.sendRelay(&channeldpb.RelayMessage{
+			EdgeConnId: c.id,
+			Event:      channeldpb.RelayMessage_DATA,
+			Data:       buf[:n],
+		})
+	}
+}
+
+// Writes the bytes from the central to the client.
+func (e *Edge) writeClient(c *edgeClient) {
+	for {
+		select {
+		case data := <-c.sendQueue:
+			if _, err := c.conn.Write(data); err != nil {
+				e.closeClient(c, true)
+				return
+			}
+		case <-c.closing:
+			return
+		}
+	}
+}
+
+// Called in the tick goroutine.
+func (e *Edge) handleRelayMessage(_ *client.ChanneldClient, _ uint32, m client.Message) {
+	msg, ok := m.(*channeldpb.RelayMessage)
+	if !ok {
+		return
+	}
+	value, exists := e.clients.Load(msg.EdgeConnId)
+	if !exists {
+		return
+	}
+	c := value.(*edgeClient)
+
+	switch msg.Event {
+	case channeldpb.RelayMessage_DATA:
+		select {
+		case c.sendQueue <- msg.Data:
+		default:
+			channeld.RootLogger().Warn("dropped the relayed client as the send queue is full", zap.Uint32("edgeConnId", c.id))
+			// Don't block the tick goroutine, as it also drains the outgoing queue of the link.
+			go e.closeClient(c, true)
+		}
+	case channeldpb.RelayMessage_DISCONNECTED:
+		e.closeClient(c, false)
+	}
+}
+
+// Closes the client connection. If notifyCentral is true, the central closes the relayed connection as well.
+func (e *Edge) closeClient(c *edgeClient, notifyCentral bool) {
+	c.closeOnce.Do(func() {
+		e.clients.Delete(c.id)
+		atomic.AddInt32(&e.clientNum, -1)
+		close(c.closing)
+		c.conn.Close()
+		if notifyCentral {
+			e.sendRelay(&channeldpb.RelayMessage{
+				EdgeConnId: c.id,
+				Event:      channeldpb.RelayMessage_DISCONNECTED,
+			})
+		}
+	})
+}
+
+func (e *Edge) sendRelay(msg *channeldpb.RelayMessage) {
+	e.link.Send(0, channeldpb.BroadcastType_NO_BROADCAST, uint32(channeldpb.MessageType_RELAY), msg, nil)
+}
@@ -0,0 +1,92 @@
+package relay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeld"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func startCentral() {
+	channeld.InitLogs()
+	channeld.InitChannels()
+	channeld.InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_authoratative_fsm.json")
+	channeld.GlobalSettings.Development = true
+	channeld.GetChannel(channeld.GlobalChannelId).Execute(func(ch *channeld.Channel) {
+		ch.InitData(&testpb.TestChannelDataMessage{Text: "a"}, nil)
+	})
+
+	go channeld.StartListening(channeldpb.ConnectionType_SERVER, "tcp", ":32131")
+	time.Sleep(100 * time.Millisecond)
+}
+
+func TestEdgeRelay(t *testing.T) {
+	startCentral()
+
+	config := DefaultEdgeConfig
+	config.CentralAddr = "127.0.0.1:32131"
+	config.ListenAddr = ":32132"
+	edge := NewEdge(config)
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- edge.Run()
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	// The client connects to the edge in the same way as to the central
+	c, err := client.NewClient("127.0.0.1:32132")
+	assert.NoError(t, err)
+	go func() {
+		for c.Receive() == nil {
+		}
+	}()
+
+	var authResult *channeldpb.AuthResultMessage
+	var data *testpb.TestChannelDataMessage
+	c.OnAuth(func(c *client.ChanneldClient, result *channeldpb.AuthResultMessage) {
+		authResult = result
+		c.Subscribe(uint32(channeld.GlobalChannelId), &channeldpb.ChannelSubscriptionOptions{
+			DataAccess: channeldpb.ChannelDataAccess_READ_ACCESS.Enum(),
+		}, nil)
+	})
+	client.OnChannelDataUpdate(c, func(_ *client.ChanneldClient, _ uint32, msg *testpb.TestChannelDataMessage, _ uint32) {
+		data = msg
+	})
+	c.Auth("test", "relayed")
+
+	assert.Eventually(t, func() bool {
+		c.Tick()
+		return data != nil
+	}, time.Second, 10*time.Millisecond)
+	if assert.NotNil(t, authResult) {
+		assert.Equal(t, channeldpb.AuthResultMessage_SUCCESSFUL, authResult.Result)
+	}
+	if assert.NotNil(t, data) {
+		assert.Equal(t, "a", data.Text)
+	}
+	assert.Equal(t, 1, edge.ClientNum())
+
+	// The central handles the relayed connection as a client connection
+	conn := channeld.GetConnection(channeld.ConnectionId(c.Id))
+	if assert.NotNil(t, conn) {
+		assert.Equal(t, channeldpb.ConnectionType_CLIENT, conn.GetConnectionType())
+	}
+
+	// Closing the client closes the relayed connection in the central
+	c.Disconnect()
+	assert.Eventually(t, func() bool {
+		return edge.ClientNum() == 0 && (conn == nil || conn.IsClosing())
+	}, time.Second, 10*time.Millisecond)
+
+	edge.Stop()
+	select {
+	case err := <-runErr:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Error("edge didn't stop")
+	}
+}
@@ -0,0 +1,109 @@
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"github.com/metaworking/channeld/pkg/channeld"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/replaypb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Which messages of the recording are exported. The zero value exports all of them.
+type ExportOptions struct {
+	// Set as the session field of every line, e.g. the name of the recording file, so the exports of many recordings
+	// can be loaded into the same table.
+	Session string
+	// Only the messages sent to the channels are exported. Empty means all the channels.
+	ChannelIds []uint32
+	// The time range of the messages since the start of the recording, in milliseconds. ToMs = 0 means no upper bound.
+	FromMs int64
+	ToMs   int64
+}
+
+// A line of the export. The message is resolved by its type and written as JSON, or written as the base64 of the raw
+// body if it can't be resolved, e.g. a user-space message or a channel data message of an unlinked type.
+type ExportedMessage struct {
+	Session string `json:"session,omitempty"`
+	// Since the start of the recording
+	TimeMs      int64  `json:"timeMs"`
+	PacketIndex int    `json:"packetIndex"`
+	ChannelId   uint32 `json:"channelId"`
+	ChannelKey  string `json:"channelKey,omitempty"`
+	Broadcast   uint32 `json:"broadcast,omitempty"`
+	StubId      uint32 `json:"stubId,omitempty"`
+	MsgType     uint32 `json:"msgType"`
+	// The name of the MessageType, e.g. "CHANNEL_DATA_UPDATE", or "USER_SPACE" for the user-space messages.
+	MsgTypeName string          `json:"msgTypeName"`
+	Msg         json.RawMessage `json:"msg,omitempty"`
+	MsgBody     []byte          `json:"msgBody,omitempty"`
+}
+
+// Writes the messages of the recording to w as newline-delimited JSON, one ExportedMessage per line, which can be loaded
+// by pandas.read_json(lines=True) or BigQuery directly. Returns the number of the messages written.
+func ExportJSON(w io.Writer, session *replaypb.ReplaySession, options ExportOptions) (int, error) {
+	channelIds := make(map[uint32]bool, len(options.ChannelIds))
+	for _, channelId := range options.ChannelIds {
+		channelIds[channelId] = true
+	}
+
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+	count := 0
+	// The offset time of each packet is the nanoseconds since the previous one
+	var timeNs int64
+	for i, packet := range session.Packets {
+		timeNs += packet.OffsetTime
+		timeMs := timeNs / 1e6
+		if timeMs < options.FromMs {
+			continue
+		}
+		if options.ToMs > 0 && timeMs >= options.ToMs {
+			break
+		}
+		for _, mp := range packet.Packet.GetMessages() {
+			if len(channelIds) > 0 && !channelIds[mp.ChannelId] {
+				continue
+			}
+			line := exportMessagePack(mp)
+			line.Session = options.Session
+			line.TimeMs = timeMs
+			line.PacketIndex = i
+			if err := encoder.Encode(line); err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+	return count, bw.Flush()
+}
+
+func exportMessagePack(mp *channeldpb.MessagePack) *ExportedMessage {
+	line := &ExportedMessage{
+		ChannelId:  mp.ChannelId,
+		ChannelKey: mp.ChannelKey,
+		Broadcast:  mp.Broadcast,
+		StubId:     mp.StubId,
+		MsgType:    mp.MsgType,
+	}
+	if mp.MsgType >= uint32(channeldpb.MessageType_USER_SPACE_START) {
+		line.MsgTypeName = "USER_SPACE"
+	} else {
+		line.MsgTypeName = channeldpb.MessageType(mp.MsgType).String()
+	}
+
+	if msg := channeld.NewMessageOfType(channeldpb.MessageType(mp.MsgType)); msg != nil {
+		if err := proto.Unmarshal(mp.MsgBody, msg); err == nil {
+			// Fails if the message has an Any of the type that's not linked
+			if data, err := protojson.Marshal(msg); err == nil {
+				line.Msg = data
+				return line
+			}
+		}
+	}
+	line.MsgBody = mp.MsgBody
+	return line
+}
@@ -0,0 +1,85 @@
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/replaypb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExportJSON(t *testing.T) {
+	subBody, _ := proto.Marshal(&channeldpb.SubscribedToChannelMessage{ConnId: 1})
+	authBody, _ := proto.Marshal(&channeldpb.AuthMessage{PlayerIdentifierToken: "p1"})
+	session := &replaypb.ReplaySession{
+		Packets: []*replaypb.ReplayPacket{
+			{OffsetTime: 0, Packet: &channeldpb.Packet{Messages: []*channeldpb.MessagePack{
+				{ChannelId: 0, MsgType: uint32(channeldpb.MessageType_AUTH), MsgBody: authBody},
+			}}},
+			{OffsetTime: int64(time.Second), Packet: &channeldpb.Packet{Messages: []*channeldpb.MessagePack{
+				{ChannelId: 1, MsgType: uint32(channeldpb.MessageType_SUB_TO_CHANNEL), MsgBody: subBody, StubId: 7},
+				{ChannelId: 2, MsgType: uint32(channeldpb.MessageType_USER_SPACE_START) + 1, MsgBody: []byte{1, 2, 3}},
+			}}},
+			{OffsetTime: int64(time.Second), Packet: &channeldpb.Packet{Messages: []*channeldpb.MessagePack{
+				{ChannelId: 1, MsgType: uint32(channeldpb.MessageType_UNSUB_FROM_CHANNEL)},
+			}}},
+		},
+	}
+
+	export := func(options ExportOptions) []*ExportedMessage {
+		var buf bytes.Buffer
+		count, err := ExportJSON(&buf, session, options)
+		assert.NoError(t, err)
+		var result []*ExportedMessage
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			msg := &ExportedMessage{}
+			assert.NoError(t, json.Unmarshal([]byte(line), msg))
+			result = append(result, msg)
+		}
+		assert.Equal(t, count, len(result))
+		return result
+	}
+
+	all := export(ExportOptions{Session: "s1"})
+	if assert.Len(t, all, 4) {
+		assert.Equal(t, "s1", all[0].Session)
+		assert.Equal(t, "AUTH", all[0].MsgTypeName)
+		assert.Contains(t, string(all[0].Msg), `"playerIdentifierToken":"p1"`)
+
+		assert.EqualValues(t, 1000, all[1].TimeMs)
+		assert.Equal(t, 1, all[1].PacketIndex)
+		assert.EqualValues(t, 7, all[1].StubId)
+		assert.Equal(t, "SUB_TO_CHANNEL", all[1].MsgTypeName)
+		assert.Contains(t, string(all[1].Msg), `"connId":1`)
+
+		// The user-space message is exported as the raw body
+		assert.Equal(t, "USER_SPACE", all[2].MsgTypeName)
+		assert.Nil(t, all[2].Msg)
+		assert.Equal(t, []byte{1, 2, 3}, all[2].MsgBody)
+
+		assert.EqualValues(t, 2000, all[3].TimeMs)
+	}
+
+	// Filtered by the channel
+	filtered := export(ExportOptions{ChannelIds: []uint32{1}})
+	if assert.Len(t, filtered, 2) {
+		assert.Equal(t, "SUB_TO_CHANNEL", filtered[0].MsgTypeName)
+		assert.Equal(t, "UNSUB_FROM_CHANNEL", filtered[1].MsgTypeName)
+	}
+
+	// Filtered by the time range
+	filtered = export(ExportOptions{FromMs: 500, ToMs: 2000})
+	assert.Len(t, filtered, 2)
+	filtered = export(ExportOptions{FromMs: 1500})
+	assert.Len(t, filtered, 1)
+	filtered = export(ExportOptions{ChannelIds: []uint32{2}, FromMs: 1500})
+	assert.Len(t, filtered, 0)
+}
@@ -0,0 +1,218 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/metaworking/channeld/internal/testpb"
+	"github.com/metaworking/channeld/pkg/channeld"
+	"github.com/metaworking/channeld/pkg/channeldpb"
+	"github.com/metaworking/channeld/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+const mergeOrderTestAddr = "127.0.0.1:32138"
+
+// Records the updates of the writers, then replays the recordings with the updates of the writers arriving in the
+// different orders in the same tick. See ChannelSettingsType.DeterministicMerge.
+func TestReplayDeterministicMerge(t *testing.T) {
+	channeld.InitLogs()
+	channeld.InitChannels()
+	channeld.InitConnections("../../config/server_conn_fsm_test.json", "../../config/client_authoratative_fsm.json")
+	channeld.GlobalSettings.Development = true
+	channeld.GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST] = channeld.ChannelSettingsType{TickIntervalMs: 200, DefaultFanOutIntervalMs: 20, DeterministicMerge: true}
+	channeld.GlobalSettings.ChannelSettings[channeldpb.ChannelType_TEST1] = channeld.ChannelSettingsType{TickIntervalMs: 200, DefaultFanOutIntervalMs: 20}
+	defer delete(channeld.GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST)
+	defer delete(channeld.GlobalSettings.ChannelSettings, channeldpb.ChannelType_TEST1)
+
+	recordDir := t.TempDir()
+	channeld.GlobalSettings.EnableRecordPacket = true
+	channeld.GlobalSettings.ReplaySessionPersistenceDir = recordDir
+	go channeld.StartListening(channeldpb.ConnectionType_CLIENT, "tcp", mergeOrderTestAddr)
+	time.Sleep(100 * time.Millisecond)
+
+	const writers = 3
+	recordChannel := newTestChannel(t, channeldpb.ChannelType_TEST)
+	recordings := make([]string, writers)
+	for i := range recordings {
+		recordings[i] = recordWriter(t, recordDir, uint32(recordChannel.Id()), i)
+	}
+	channeld.GlobalSettings.EnableRecordPacket = false
+
+	// The replay clients keep running until the running time is up
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	// Returns the text of the merged channel data, and the writer of the connection that has the largest id
+	replay := func(channelType channeldpb.ChannelType, arrivalOrder []int) (text string, lastWriter int) {
+		return replayWriters(t, t.TempDir(), newTestChannel(t, channelType), recordings, arrivalOrder, &wg)
+	}
+
+	// The result depends on the arrival order by default
+	text, _ := replay(channeldpb.ChannelType_TEST1, []int{0, 1, 2})
+	assert.Equal(t, "w2-1", text)
+	text, _ = replay(channeldpb.ChannelType_TEST1, []int{2, 1, 0})
+	assert.Equal(t, "w0-1", text)
+
+	// Ordered by the connection id, then the order of each writer's updates
+	for _, arrivalOrder := range [][]int{{0, 1, 2}, {2, 1, 0}, {1, 2, 0}} {
+		text, lastWriter := replay(channeldpb.ChannelType_TEST, arrivalOrder)
+		assert.Equal(t, fmt.Sprintf("w%d-1", lastWriter), text, "arrival order: %v", arrivalOrder)
+	}
+}
+
+func newTestChannel(t *testing.T, channelType channeldpb.ChannelType) *channeld.Channel {
+	ch, err := channeld.CreateChannel(channelType, nil)
+	assert.NoError(t, err)
+	waitForTick(ch, func(ch *channeld.Channel) {
+		ch.InitData(&testpb.TestChannelDataMessage{}, nil)
+	})
+	return ch
+}
+
+// Blocks until the callback is executed in the channel's tick.
+func waitForTick(ch *channeld.Channel, callback func(ch *channeld.Channel)) {
+	done := make(chan struct{})
+	ch.Execute(func(ch *channeld.Channel) {
+		callback(ch)
+		close(done)
+	})
+	<-done
+}
+
+// Subscribes the writer to the channel and sends two updates, then returns the path of the recording.
+func recordWriter(t *testing.T, recordDir string, channelId uint32, writer int) string {
+	c, err := client.NewClient(mergeOrderTestAddr)
+	assert.NoError(t, err)
+	go func() {
+		for c.Receive() == nil {
+		}
+	}()
+	authed := make(chan struct{})
+	c.OnAuth(func(c *client.ChanneldClient, result *channeldpb.AuthResultMessage) {
+		close(authed)
+	})
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				c.Disconnect()
+				return
+			default:
+				c.Tick()
+				time.Sleep(10 * time.Millisecond)
+			}
+		}
+	}()
+
+	c.Auth("test", fmt.Sprintf("writer%d", writer))
+	<-authed
+	c.Subscribe(channelId, &channeldpb.ChannelSubscriptionOptions{DataAccess: channeldpb.ChannelDataAccess_WRITE_ACCESS.Enum()}, nil)
+	for n := 0; n < 2; n++ {
+		time.Sleep(20 * time.Millisecond)
+		c.UpdateChannelData(channelId, &testpb.TestChannelDataMessage{Text: fmt.Sprintf("w%d-%d", writer, n), Num: uint32(n)})
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(done)
+
+	// The recording is written when the connection is closed
+	pattern := filepath.Join(recordDir, fmt.Sprintf("session_%d_*.cpr", c.Id))
+	for i := 0; i < 50; i++ {
+		if paths, _ := filepath.Glob(pattern); len(paths) > 0 {
+			return paths[0]
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("recording of writer %d is not found", writer)
+	return ""
+}
+
+// Replays the recordings of the writers into the channel. The updates of the writers are held until all the writers have
+// subscribed, then sent in the arrival order in the same tick. Returns the text of the merged channel data, and the
+// writer of the connection that has the largest id.
+func replayWriters(t *testing.T, configDir string, ch *channeld.Channel, recordings []string, arrivalOrder []int, wg *sync.WaitGroup) (string, int) {
+	type replayedWriter struct {
+		writer int
+		client *client.ChanneldClient
+	}
+	subscribed := make(chan replayedWriter, len(recordings))
+	gates := make([]chan struct{}, len(recordings))
+
+	for i, recording := range recordings {
+		config, _ := json.Marshal(map[string]interface{}{
+			"channeldAddr": mergeOrderTestAddr,
+			"connectionGroups": []map[string]interface{}{{
+				"cprFilePath":              recording,
+				"connectionNumber":         1,
+				"runningTime":              "2s",
+				"sleepEndOfSession":        "1h",
+				"maxTickInterval":          "10ms",
+				"actionIntervalMultiplier": 0,
+				"waitAuthSuccess":          true,
+				"authOnlyOnce":             true,
+			}},
+		})
+		configPath := filepath.Join(configDir, fmt.Sprintf("case%d.json", i))
+		assert.NoError(t, os.WriteFile(configPath, config, 0666))
+		rc, err := CreateReplayClientByConfigFile(configPath)
+		if !assert.NoError(t, err) {
+			return "", -1
+		}
+
+		writer := i
+		gates[writer] = make(chan struct{})
+		rc.SetAlterChannelIdBeforeSendHandler(func(channelId uint32, msgType channeldpb.MessageType, msgPack *channeldpb.MessagePack, c *client.ChanneldClient) (uint32, bool) {
+			if channelId == 0 {
+				return channelId, true
+			}
+			return uint32(ch.Id()), true
+		})
+		rc.SetBeforeSendMessageEntry(channeldpb.MessageType_SUB_TO_CHANNEL, &channeldpb.SubscribedToChannelMessage{}, func(msg proto.Message, msgPack *channeldpb.MessagePack, c *client.ChanneldClient) bool {
+			subscribed <- replayedWriter{writer: writer, client: c}
+			return true
+		})
+		rc.SetBeforeSendMessageEntry(channeldpb.MessageType_CHANNEL_DATA_UPDATE, &channeldpb.ChannelDataUpdateMessage{}, func(msg proto.Message, msgPack *channeldpb.MessagePack, c *client.ChanneldClient) bool {
+			<-gates[writer]
+			return true
+		})
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rc.Run()
+		}()
+	}
+
+	replayed := make([]replayedWriter, 0, len(recordings))
+	for range recordings {
+		replayed = append(replayed, <-subscribed)
+	}
+	sort.Slice(replayed, func(i, j int) bool {
+		return replayed[i].client.Id < replayed[j].client.Id
+	})
+
+	// The subscriptions are handled in this tick, and the updates are sent right after it
+	waitForTick(ch, func(ch *channeld.Channel) {})
+	for _, writer := range arrivalOrder {
+		close(gates[writer])
+		time.Sleep(30 * time.Millisecond)
+	}
+	// The updates are merged at the end of the next tick
+	waitForTick(ch, func(ch *channeld.Channel) {})
+	var text string
+	waitForTick(ch, func(ch *channeld.Channel) {
+		text = ch.GetDataMessage().(*testpb.TestChannelDataMessage).Text
+	})
+
+	for _, w := range replayed {
+		w.client.Disconnect()
+	}
+	return text, replayed[len(replayed)-1].writer
+}
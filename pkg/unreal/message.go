@@ -61,7 +61,7 @@ func handleUnrealSpawnObject(ctx channeld.MessageContext) {
 			if err == nil {
 				msg.Payload = newPayload
 				// Update the channel and let the new channel handle the message. Otherwise race conditions may happen.
-				ctx.Channel = channeld.GetChannel(spatialChId)
+				ctx.Channel = channeld.GetChannelOfConnection(spatialChId, ctx.Connection)
 				if ctx.Channel != nil {
 					ctx.Channel.Execute(func(ch *channeld.Channel) {
 						addSpatialEntity(ch, spawnMsg.Obj)
@@ -95,7 +95,7 @@ func handleUnrealSpawnObject(ctx channeld.MessageContext) {
 	*/
 
 	// Entity channel should already be created by the spatial server.
-	entityChannel := channeld.GetChannel(common.ChannelId(*spawnMsg.Obj.NetGUID))
+	entityChannel := channeld.GetChannelOfConnection(common.ChannelId(*spawnMsg.Obj.NetGUID), ctx.Connection)
 	if entityChannel == nil {
 		return
 	}
@@ -175,7 +175,7 @@ func handleUnrealDestroyObject(ctx channeld.MessageContext) {
 	// Send/broadcast the message
 	channeld.HandleServerToClientUserMessage(ctx)
 
-	entityCh := channeld.GetChannel(common.ChannelId(destroyMsg.NetId))
+	entityCh := channeld.GetChannelOfConnection(common.ChannelId(destroyMsg.NetId), ctx.Connection)
 	if entityCh != nil {
 		entityCh.Logger().Info("removing entity channel from unrealpb.DestroyObjectMessage")
 		channeld.RemoveChannel(entityCh)